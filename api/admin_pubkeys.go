@@ -0,0 +1,41 @@
+package api
+
+import (
+	"github.com/flokiorg/lokihub/db"
+)
+
+func (api *api) ListAdminPubkeys() ([]AdminPubkey, error) {
+	dbAdminPubkeys, err := api.nostrAuthSvc.ListAdminPubkeys()
+	if err != nil {
+		return nil, err
+	}
+
+	adminPubkeys := []AdminPubkey{}
+	for i := range dbAdminPubkeys {
+		adminPubkeys = append(adminPubkeys, toApiAdminPubkey(&dbAdminPubkeys[i]))
+	}
+	return adminPubkeys, nil
+}
+
+func (api *api) AddAdminPubkey(req *AddAdminPubkeyRequest) (*AdminPubkey, error) {
+	dbAdminPubkey, err := api.nostrAuthSvc.AddAdminPubkey(req.Pubkey, req.Name, req.Permission)
+	if err != nil {
+		return nil, err
+	}
+	adminPubkey := toApiAdminPubkey(dbAdminPubkey)
+	return &adminPubkey, nil
+}
+
+func (api *api) RemoveAdminPubkey(id uint) error {
+	return api.nostrAuthSvc.RemoveAdminPubkey(id)
+}
+
+func toApiAdminPubkey(adminPubkey *db.AdminPubkey) AdminPubkey {
+	return AdminPubkey{
+		Id:         adminPubkey.ID,
+		Pubkey:     adminPubkey.Pubkey,
+		Name:       adminPubkey.Name,
+		Permission: adminPubkey.Permission,
+		CreatedAt:  adminPubkey.CreatedAt,
+	}
+}
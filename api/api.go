@@ -23,12 +23,16 @@ import (
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
+	"github.com/flokiorg/lokihub/apitokens"
+	"github.com/flokiorg/lokihub/approvals"
 	"github.com/flokiorg/lokihub/apps"
+	"github.com/flokiorg/lokihub/audit"
 	"github.com/flokiorg/lokihub/config"
 	"github.com/flokiorg/lokihub/constants"
 	"github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/db/queries"
 	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/jitwallet"
 	"github.com/flokiorg/lokihub/lnclient"
 	"github.com/flokiorg/lokihub/lnclient/flnd/wrapper"
@@ -39,10 +43,13 @@ import (
 
 	"github.com/flokiorg/lokihub/keys"
 	permissions "github.com/flokiorg/lokihub/nip47/permissions"
+	"github.com/flokiorg/lokihub/nostrauth"
 	"github.com/flokiorg/lokihub/service"
 	"github.com/flokiorg/lokihub/swaps"
+	"github.com/flokiorg/lokihub/totp"
 	"github.com/flokiorg/lokihub/utils"
 	"github.com/flokiorg/lokihub/version"
+	"github.com/flokiorg/lokihub/webhooks"
 )
 
 const (
@@ -62,6 +69,13 @@ type api struct {
 	eventPublisher   events.EventPublisher
 	lspManager       *manager.LSPManager
 	iaManager        *apps.IdentityAuthorityManager
+	approvalsSvc     approvals.ApprovalsService
+	webhooksSvc      webhooks.WebhooksService
+	apiTokensSvc     apitokens.ApiTokensService
+	auditSvc         audit.AuditService
+	nostrAuthSvc     nostrauth.NostrAuthService
+	totpSvc          totp.TotpService
+	freezeSvc        freeze.FreezeService
 }
 
 func NewAPI(svc service.Service, gormDB *gorm.DB, config config.Config, keys keys.Keys, lokiSvc loki.LokiService, eventPublisher events.EventPublisher) *api {
@@ -76,6 +90,13 @@ func NewAPI(svc service.Service, gormDB *gorm.DB, config config.Config, keys key
 		eventPublisher: eventPublisher,
 		lspManager:     manager.NewLSPManager(gormDB),
 		iaManager:      apps.NewIdentityAuthorityManager(gormDB),
+		approvalsSvc:   approvals.NewApprovalsService(gormDB, eventPublisher),
+		webhooksSvc:    webhooks.NewWebhooksService(gormDB, config),
+		apiTokensSvc:   apitokens.NewApiTokensService(gormDB),
+		auditSvc:       audit.NewAuditService(gormDB),
+		nostrAuthSvc:   nostrauth.NewNostrAuthService(gormDB),
+		totpSvc:        totp.NewTotpService(gormDB, config),
+		freezeSvc:      freeze.NewFreezeService(gormDB, eventPublisher),
 	}
 }
 
@@ -162,6 +183,16 @@ func (api *api) CreateApp(createAppRequest *CreateAppRequest) (*CreateAppRespons
 		return nil, err
 	}
 
+	if createAppRequest.SpendingPolicy != nil {
+		if err := api.appsSvc.SetAppSpendingPolicy(app.ID, toDBSpendingPolicy(createAppRequest.SpendingPolicy)); err != nil {
+			// don't leave behind a connection without the policy it was requested with
+			if deleteErr := api.appsSvc.DeleteApp(app); deleteErr != nil {
+				logger.Logger.Error().Err(deleteErr).Uint("app_id", app.ID).Msg("Failed to delete app after invalid spending policy")
+			}
+			return nil, err
+		}
+	}
+
 	if kind == db.AppKindCircleHub {
 		// Resolve the actual identity attached (whether newly created or reused)
 		// to find its real policy/pubkey — createAppRequest's circlePolicy/
@@ -430,9 +461,26 @@ func (api *api) UpdateApp(userApp *db.App, updateAppRequest *UpdateAppRequest) e
 		}
 	}
 
+	if updateAppRequest.SpendingPolicy != nil {
+		if err := api.appsSvc.SetAppSpendingPolicy(userApp.ID, toDBSpendingPolicy(updateAppRequest.SpendingPolicy)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func toDBSpendingPolicy(policy *SpendingPolicy) db.AppSpendingPolicy {
+	return db.AppSpendingPolicy{
+		MaxAmountPerPaymentLoki: policy.MaxAmountPerPaymentLoki,
+		MaxPaymentsPerHour:      policy.MaxPaymentsPerHour,
+		MaxFeePpm:               policy.MaxFeePpm,
+		AllowedPubkeys:          policy.AllowedPubkeys,
+		DeniedPubkeys:           policy.DeniedPubkeys,
+		ApprovalThresholdLoki:   policy.ApprovalThresholdLoki,
+	}
+}
+
 func (api *api) DeleteApp(userApp *db.App) error {
 	// jit_wallet/circle_wallet hold a shared balance that must be reclaimed
 	// back to their hub before the row disappears — apps.DeleteApp has no
@@ -533,6 +581,7 @@ func (api *api) GetApp(ctx context.Context, dbApp *db.App) *App {
 		WalletPubkey:       walletPubkey,
 		UniqueWalletPubkey: uniqueWalletPubkey,
 		LastUsedAt:         dbApp.LastUsedAt,
+		Frozen:             dbApp.Frozen,
 	}
 
 	if dbApp.IsIsolated() {
@@ -556,6 +605,17 @@ func (api *api) GetApp(ctx context.Context, dbApp *db.App) *App {
 		}
 	}
 
+	if policy, policyErr := api.appsSvc.GetAppSpendingPolicy(dbApp.ID); policyErr == nil && policy != nil {
+		response.SpendingPolicy = &SpendingPolicy{
+			MaxAmountPerPaymentLoki: policy.MaxAmountPerPaymentLoki,
+			MaxPaymentsPerHour:      policy.MaxPaymentsPerHour,
+			MaxFeePpm:               policy.MaxFeePpm,
+			AllowedPubkeys:          policy.AllowedPubkeys,
+			DeniedPubkeys:           policy.DeniedPubkeys,
+			ApprovalThresholdLoki:   policy.ApprovalThresholdLoki,
+		}
+	}
+
 	return &response
 }
 
@@ -809,6 +869,7 @@ func (api *api) ListApps(limit uint64, offset uint64, filters ListAppsFilters, o
 			WalletPubkey:       walletPubkey,
 			UniqueWalletPubkey: uniqueWalletPubkey,
 			LastUsedAt:         dbApp.LastUsedAt,
+			Frozen:             dbApp.Frozen,
 		}
 
 		if dbApp.IsIsolated() {
@@ -1035,15 +1096,48 @@ func (api *api) GetAutoSwapConfig() (*GetAutoSwapConfigResponse, error) {
 		}
 	}
 
+	swapIn, err := api.getAutoSwapInConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &GetAutoSwapConfigResponse{
 		Type:             constants.SWAP_TYPE_OUT,
 		Enabled:          swapOutEnabled,
 		BalanceThreshold: swapOutBalanceThreshold,
 		SwapAmount:       swapOutAmount,
 		Destination:      swapOutDestination,
+		SwapIn:           *swapIn,
 	}, nil
 }
 
+func (api *api) getAutoSwapInConfig() (*AutoSwapInConfigResponse, error) {
+	response := &AutoSwapInConfigResponse{UnrefundedSwapIds: []string{}}
+	swapInConfig, err := swaps.LoadAutoSwapInConfig(api.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if swapInConfig != nil {
+		response.Enabled = true
+		response.SpendableThreshold = swapInConfig.SpendableThreshold
+		response.OnchainThreshold = swapInConfig.OnchainThreshold
+		response.SwapAmount = swapInConfig.SwapAmount
+		response.DailyLimit = swapInConfig.DailyLimit
+	}
+
+	// failed swaps may still need a refund after auto swap in was disabled
+	if api.svc.GetSwapsService() != nil {
+		status, err := api.svc.GetSwapsService().GetAutoSwapInStatus()
+		if err != nil {
+			return nil, err
+		}
+		response.SwappedToday = status.SwappedToday
+		response.PendingSwapId = status.PendingSwapId
+		response.UnrefundedSwapIds = status.UnrefundedSwapIds
+	}
+	return response, nil
+}
+
 func (api *api) LookupSwap(swapId string) (*LookupSwapResponse, error) {
 	if api.svc.GetSwapsService() == nil {
 		return nil, errors.New("SwapsService not started")
@@ -1145,6 +1239,9 @@ func (api *api) InitiateSwapOut(ctx context.Context, initiateSwapOutRequest *Ini
 	if api.svc.GetSwapsService() == nil {
 		return nil, errors.New("SwapsService not started")
 	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
 
 	amount := initiateSwapOutRequest.SwapAmount
 	destination := initiateSwapOutRequest.Destination
@@ -1152,6 +1249,9 @@ func (api *api) InitiateSwapOut(ctx context.Context, initiateSwapOutRequest *Ini
 	if amount == 0 {
 		return nil, errors.New("invalid swap amount")
 	}
+	if err := api.verifyPaymentTotp(ctx, lokiToMloki(amount)); err != nil {
+		return nil, err
+	}
 
 	swapOutResponse, err := api.svc.GetSwapsService().SwapOut(amount, destination, false, false)
 	if err != nil {
@@ -1217,6 +1317,49 @@ func (api *api) EnableAutoSwapOut(ctx context.Context, enableAutoSwapsRequest *E
 	return api.svc.GetSwapsService().EnableAutoSwapOut()
 }
 
+func (api *api) EnableAutoSwapIn(req *EnableAutoSwapInRequest) error {
+	if req.SwapAmount == 0 {
+		return fmt.Errorf("%w: swap amount must be set", constants.ErrInvalidParams)
+	}
+	if req.DailyLimit < req.SwapAmount {
+		return fmt.Errorf("%w: the daily limit must be at least the swap amount", constants.ErrInvalidParams)
+	}
+
+	values := map[string]uint64{
+		config.AutoSwapInSpendableThresholdKey: req.SpendableThreshold,
+		config.AutoSwapInOnchainThresholdKey:   req.OnchainThreshold,
+		config.AutoSwapInAmountKey:             req.SwapAmount,
+		config.AutoSwapInDailyLimitKey:         req.DailyLimit,
+	}
+	for key, value := range values {
+		if err := api.cfg.SetUpdate(key, strconv.FormatUint(value, 10), ""); err != nil {
+			logger.Logger.Error().Err(err).Str("key", key).Msg("Failed to save auto swap in config")
+			return err
+		}
+	}
+
+	if api.svc.GetSwapsService() == nil {
+		return errors.New("SwapsService not started")
+	}
+	return api.svc.GetSwapsService().EnableAutoSwapIn()
+}
+
+func (api *api) DisableAutoSwapIn() error {
+	keys := []string{config.AutoSwapInSpendableThresholdKey, config.AutoSwapInOnchainThresholdKey, config.AutoSwapInAmountKey, config.AutoSwapInDailyLimitKey}
+
+	for _, key := range keys {
+		if err := api.cfg.SetUpdate(key, "", ""); err != nil {
+			logger.Logger.Error().Err(err).Str("key", key).Msg("Failed to remove auto swap in config")
+			return err
+		}
+	}
+
+	if api.svc.GetSwapsService() != nil {
+		api.svc.GetSwapsService().StopAutoSwapIn()
+	}
+	return nil
+}
+
 func (api *api) DisableAutoSwap() error {
 	keys := []string{config.AutoSwapBalanceThresholdKey, config.AutoSwapAmountKey, config.AutoSwapDestinationKey}
 
@@ -1262,9 +1405,67 @@ func (api *api) OpenChannel(ctx context.Context, openChannelRequest *OpenChannel
 	if api.svc.GetLNClient() == nil {
 		return nil, errors.New("LNClient not started")
 	}
+	if err := validateOpenChannelRequest(openChannelRequest); err != nil {
+		return nil, err
+	}
+	if openChannelRequest.FeeRate != nil && openChannelRequest.ConfTarget != nil {
+		return nil, fmt.Errorf("%w: set either a fee rate or a confirmation target", constants.ErrInvalidParams)
+	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	if err := api.verifyPaymentTotp(ctx, lokiToMloki(uint64(openChannelRequest.AmountLoki))); err != nil {
+		return nil, err
+	}
 	return api.svc.GetLNClient().OpenChannel(ctx, openChannelRequest)
 }
 
+func (api *api) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	if len(batchOpenChannelRequest.Channels) < 2 {
+		return nil, fmt.Errorf("%w: a batch needs at least two channels", constants.ErrInvalidParams)
+	}
+	if batchOpenChannelRequest.FeeRate != nil && batchOpenChannelRequest.ConfTarget != nil {
+		return nil, fmt.Errorf("%w: set either a fee rate or a confirmation target", constants.ErrInvalidParams)
+	}
+	var amountLoki uint64
+	for i := range batchOpenChannelRequest.Channels {
+		channel := &batchOpenChannelRequest.Channels[i]
+		if err := validateOpenChannelRequest(channel); err != nil {
+			return nil, err
+		}
+		if channel.FeeRate != nil || channel.ConfTarget != nil || len(channel.Outpoints) > 0 {
+			return nil, fmt.Errorf("%w: fee options and outpoints apply to the whole batch, not to single channels", constants.ErrInvalidParams)
+		}
+		amountLoki += uint64(channel.AmountLoki)
+	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	if err := api.verifyPaymentTotp(ctx, lokiToMloki(amountLoki)); err != nil {
+		return nil, err
+	}
+	return api.svc.GetLNClient().BatchOpenChannel(ctx, batchOpenChannelRequest)
+}
+
+func validateOpenChannelRequest(openChannelRequest *OpenChannelRequest) error {
+	if openChannelRequest.Pubkey == "" {
+		return fmt.Errorf("%w: pubkey must be set", constants.ErrInvalidParams)
+	}
+	if openChannelRequest.AmountLoki <= 0 {
+		return fmt.Errorf("%w: amount must be greater than zero", constants.ErrInvalidParams)
+	}
+	if openChannelRequest.PushAmountLoki >= uint64(openChannelRequest.AmountLoki) {
+		return fmt.Errorf("%w: push amount must be below the channel amount", constants.ErrInvalidParams)
+	}
+	if openChannelRequest.ScidAlias && openChannelRequest.Public {
+		return fmt.Errorf("%w: SCID alias channels must be private", constants.ErrInvalidParams)
+	}
+	return validateOutpoints(openChannelRequest.Outpoints)
+}
+
 func (api *api) DisconnectPeer(ctx context.Context, peerId string) error {
 	if api.svc.GetLNClient() == nil {
 		return errors.New("LNClient not started")
@@ -1367,11 +1568,30 @@ func (api *api) SignMessage(ctx context.Context, message string) (*SignMessageRe
 	}, nil
 }
 
-func (api *api) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (*RedeemOnchainFundsResponse, error) {
+func (api *api) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (*RedeemOnchainFundsResponse, error) {
 	if api.svc.GetLNClient() == nil {
 		return nil, errors.New("LNClient not started")
 	}
-	txId, err := api.svc.GetLNClient().RedeemOnchainFunds(ctx, toAddress, amount, feeRate, sendAll)
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	amountMloki := lokiToMloki(amount)
+	if sendAll {
+		amountMloki = unknownAmountMloki
+	}
+	if err := api.verifyPaymentTotp(ctx, amountMloki); err != nil {
+		return nil, err
+	}
+	var txId string
+	var err error
+	if len(outpoints) > 0 {
+		if err := validateOutpoints(outpoints); err != nil {
+			return nil, err
+		}
+		txId, err = api.svc.GetLNClient().SendOnchainFundsFromUtxos(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+	} else {
+		txId, err = api.svc.GetLNClient().RedeemOnchainFunds(ctx, toAddress, amount, feeRate, sendAll)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1496,6 +1716,7 @@ func (api *api) GetInfo(ctx context.Context) (*InfoResponse, error) {
 	info.MempoolUrl = api.cfg.GetMempoolApi()
 	info.EnableSwap = api.cfg.EnableSwap()
 	info.EnableMessageboardNwc = api.cfg.EnableMessageboardNwc()
+	info.EnableHttpWebhooks = api.cfg.EnableHttpWebhooks()
 	info.WorkDir = api.cfg.GetDefaultWorkDir()
 	info.EnablePolling = constants.DEFAULT_ENABLE_POLLING
 
@@ -1573,6 +1794,13 @@ func (api *api) UpdateSettings(updateSettingsRequest *UpdateSettingsRequest) err
 		}
 	}
 
+	if updateSettingsRequest.EnableHttpWebhooks != nil {
+		err := api.cfg.SetEnableHttpWebhooks(*updateSettingsRequest.EnableHttpWebhooks)
+		if err != nil {
+			return fmt.Errorf("failed to set EnableHttpWebhooks: %w", err)
+		}
+	}
+
 	if updateSettingsRequest.SwapServiceUrl != "" {
 		if err := utils.ValidateHTTPURL(updateSettingsRequest.SwapServiceUrl); err != nil {
 			return fmt.Errorf("invalid Swap Service URL: %w", err)
@@ -1681,10 +1909,14 @@ func (api *api) SetNodeAlias(ctx context.Context, nodeAlias string) error {
 	return nil
 }
 
-func (api *api) GetMnemonic(unlockPassword string) (*MnemonicResponse, error) {
+func (api *api) GetMnemonic(unlockPassword string, totpCode string) (*MnemonicResponse, error) {
 	if !api.cfg.CheckUnlockPassword(unlockPassword) {
 		return nil, fmt.Errorf("wrong password")
 	}
+	// checked after the password so a wrong password does not use up the code
+	if err := api.totpSvc.Verify(totpCode, unlockPassword); err != nil {
+		return nil, err
+	}
 
 	mnemonic, err := api.cfg.Get("Mnemonic", unlockPassword)
 	if err != nil {
@@ -2018,12 +2250,14 @@ func (api *api) SendPaymentProbes(ctx context.Context, sendPaymentProbesRequest
 	}
 
 	var errMessage string
-	err := api.svc.GetLNClient().SendPaymentProbes(ctx, sendPaymentProbesRequest.Invoice)
+	route, err := api.svc.GetLNClient().SendPaymentProbes(ctx, sendPaymentProbesRequest.Invoice)
 	if err != nil {
 		errMessage = err.Error()
+	} else if !route.Success {
+		errMessage = route.FailureReason
 	}
 
-	return &SendPaymentProbesResponse{Error: errMessage}, nil
+	return &SendPaymentProbesResponse{Error: errMessage, Route: route}, nil
 }
 
 func (api *api) MigrateNodeStorage(ctx context.Context, to string) error {
@@ -2044,12 +2278,14 @@ func (api *api) SendSpontaneousPaymentProbes(ctx context.Context, sendSpontaneou
 	}
 
 	var errMessage string
-	err := api.svc.GetLNClient().SendSpontaneousPaymentProbes(ctx, sendSpontaneousPaymentProbesRequest.Amount, sendSpontaneousPaymentProbesRequest.NodeId)
+	route, err := api.svc.GetLNClient().SendSpontaneousPaymentProbes(ctx, sendSpontaneousPaymentProbesRequest.Amount, sendSpontaneousPaymentProbesRequest.NodeId)
 	if err != nil {
 		errMessage = err.Error()
+	} else if !route.Success {
+		errMessage = route.FailureReason
 	}
 
-	return &SendSpontaneousPaymentProbesResponse{Error: errMessage}, nil
+	return &SendSpontaneousPaymentProbesResponse{Error: errMessage, Route: route}, nil
 }
 
 func (api *api) GetNetworkGraph(ctx context.Context, nodeIds []string) (NetworkGraphResponse, error) {
@@ -2164,9 +2400,15 @@ func (api *api) GetCustomNodeCommands() (*CustomNodeCommandsResponse, error) {
 	for _, commandDef := range allCommandDefs {
 		argDefs := make([]CustomNodeCommandArgDef, 0, len(commandDef.Args))
 		for _, argDef := range commandDef.Args {
+			argType := argDef.Type
+			if argType == "" {
+				argType = lnclient.CustomNodeCommandArgTypeString
+			}
 			argDefs = append(argDefs, CustomNodeCommandArgDef{
 				Name:        argDef.Name,
 				Description: argDef.Description,
+				Type:        argType,
+				Required:    argDef.Required,
 			})
 		}
 		commandDefs = append(commandDefs, CustomNodeCommandDef{
@@ -2221,12 +2463,20 @@ func (api *api) ExecuteCustomNodeCommand(ctx context.Context, command string) (i
 
 	reqArgs := make([]lnclient.CustomNodeCommandArg, 0, len(argValues))
 	for _, argDef := range commandDef.Args {
-		if argValue, ok := argValues[argDef.Name]; ok {
-			reqArgs = append(reqArgs, lnclient.CustomNodeCommandArg{
-				Name:  argDef.Name,
-				Value: argValue,
-			})
+		argValue, ok := argValues[argDef.Name]
+		if !ok {
+			if argDef.Required {
+				return nil, fmt.Errorf("missing required argument --%s", argDef.Name)
+			}
+			continue
+		}
+		if err := validateNodeCommandArg(argDef, argValue); err != nil {
+			return nil, err
 		}
+		reqArgs = append(reqArgs, lnclient.CustomNodeCommandArg{
+			Name:  argDef.Name,
+			Value: argValue,
+		})
 	}
 
 	nodeResp, err := lnClient.ExecuteCustomNodeCommand(ctx, &lnclient.CustomNodeCommandRequest{
@@ -2240,6 +2490,28 @@ func (api *api) ExecuteCustomNodeCommand(ctx context.Context, command string) (i
 	return nodeResp.Response, nil
 }
 
+// validateNodeCommandArg checks that value can be parsed as the type of the
+// argument. The node still checks the range of integers.
+func validateNodeCommandArg(argDef lnclient.CustomNodeCommandArgDef, value string) error {
+	var err error
+	switch argDef.Type {
+	case lnclient.CustomNodeCommandArgTypeInteger:
+		if strings.HasPrefix(value, "-") {
+			_, err = strconv.ParseInt(value, 10, 64)
+		} else {
+			_, err = strconv.ParseUint(value, 10, 64)
+		}
+	case lnclient.CustomNodeCommandArgTypeBoolean:
+		_, err = strconv.ParseBool(value)
+	case lnclient.CustomNodeCommandArgTypeOutpoint:
+		err = validateOutpoints([]string{value})
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for --%s: expected %s", argDef.Name, argDef.Type)
+	}
+	return nil
+}
+
 func (api *api) SendEvent(event string, properties interface{}) {
 	api.svc.GetEventPublisher().Publish(&events.Event{
 		Event:      event,
@@ -2261,30 +2533,6 @@ func (api *api) parseExpiresAt(expiresAtString string) (*time.Time, error) {
 	return expiresAt, nil
 }
 
-func (api *api) GetForwards() (*GetForwardsResponse, error) {
-	var forwards []db.Forward
-	err := api.db.Find(&forwards).Error
-	if err != nil {
-		return nil, err
-	}
-
-	var totalOutboundAmount uint64
-	var totalFeeEarned uint64
-
-	for _, forward := range forwards {
-		totalOutboundAmount += forward.OutboundAmountForwardedMloki
-		totalFeeEarned += forward.TotalFeeEarnedMloki
-	}
-
-	numForwards := len(forwards)
-
-	return &GetForwardsResponse{
-		OutboundAmountForwardedMloki: totalOutboundAmount,
-		TotalFeeEarnedMloki:          totalFeeEarned,
-		NumForwards:                  uint64(numForwards),
-	}, nil
-}
-
 func (api *api) SetupLocal(ctx context.Context, req *SetupLocalRequest) error {
 	if !startMutex.TryLock() {
 		return errors.New("app is busy")
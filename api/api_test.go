@@ -28,7 +28,7 @@ func TestGetCustomNodeCommandDefinitions(t *testing.T) {
 			Description: "command with args",
 			Args: []lnclient.CustomNodeCommandArgDef{
 				{Name: "arg1", Description: "first argument"},
-				{Name: "arg2", Description: "second argument"},
+				{Name: "arg2", Description: "second argument", Type: lnclient.CustomNodeCommandArgTypeInteger, Required: true},
 			},
 		},
 	}
@@ -43,8 +43,8 @@ func TestGetCustomNodeCommandDefinitions(t *testing.T) {
 			Name:        "with_args",
 			Description: "command with args",
 			Args: []CustomNodeCommandArgDef{
-				{Name: "arg1", Description: "first argument"},
-				{Name: "arg2", Description: "second argument"},
+				{Name: "arg1", Description: "first argument", Type: "string"},
+				{Name: "arg2", Description: "second argument", Type: "integer", Required: true},
 			},
 		},
 	}
@@ -170,6 +170,45 @@ func TestExecuteCustomNodeCommand(t *testing.T) {
 		apiExpectedErr:       "flag provided but not defined: -unknown",
 	}
 
+	typedCommands := []lnclient.CustomNodeCommandDef{
+		{
+			Name: "test_command",
+			Args: []lnclient.CustomNodeCommandArgDef{
+				{Name: "amount", Type: lnclient.CustomNodeCommandArgTypeInteger, Required: true},
+				{Name: "flag", Type: lnclient.CustomNodeCommandArgTypeBoolean},
+				{Name: "outpoint", Type: lnclient.CustomNodeCommandArgTypeOutpoint},
+			},
+		},
+	}
+
+	// Error: a required argument is missing.
+	testCaseErrMissingRequiredArg := testCase{
+		name:                "missing required argument",
+		apiCommandLine:      "test_command --flag=true",
+		lnSupportedCommands: typedCommands,
+		apiExpectedErr:      "missing required argument --amount",
+	}
+
+	// Error: argument values must match their type.
+	testCaseErrInvalidInteger := testCase{
+		name:                "invalid integer argument",
+		apiCommandLine:      "test_command --amount=ten",
+		lnSupportedCommands: typedCommands,
+		apiExpectedErr:      "invalid value for --amount: expected integer",
+	}
+	testCaseErrInvalidBoolean := testCase{
+		name:                "invalid boolean argument",
+		apiCommandLine:      "test_command --amount=10 --flag=maybe",
+		lnSupportedCommands: typedCommands,
+		apiExpectedErr:      "invalid value for --flag: expected boolean",
+	}
+	testCaseErrInvalidOutpoint := testCase{
+		name:                "invalid outpoint argument",
+		apiCommandLine:      "test_command --amount=10 --outpoint=abc",
+		lnSupportedCommands: typedCommands,
+		apiExpectedErr:      "invalid value for --outpoint: expected outpoint",
+	}
+
 	// Error: the command is valid but the node fails to execute it.
 	testCaseErrNodeFailed := testCase{
 		name:                 "node failed to execute command",
@@ -190,6 +229,10 @@ func TestExecuteCustomNodeCommand(t *testing.T) {
 		testCaseErrMalformedCommand,
 		testCaseErrUnknownCommand,
 		testCaseErrUnknownArg,
+		testCaseErrMissingRequiredArg,
+		testCaseErrInvalidInteger,
+		testCaseErrInvalidBoolean,
+		testCaseErrInvalidOutpoint,
 		testCaseErrNodeFailed,
 	}
 
@@ -224,5 +267,5 @@ func TestExecuteCustomNodeCommand(t *testing.T) {
 // instantiateAPIWithService is a helper function that returns a partially
 // constructed API instance. It is only suitable for the simplest of test cases.
 func instantiateAPIWithService(s service.Service) *api {
-	return &api{svc: s}
+	return &api{svc: s, freezeSvc: &stubFreezeService{}, totpSvc: &stubTotpService{}}
 }
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"github.com/flokiorg/lokihub/db"
+)
+
+func (api *api) ListApiTokens() ([]ApiToken, error) {
+	dbApiTokens, err := api.apiTokensSvc.ListTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	apiTokens := []ApiToken{}
+	for i := range dbApiTokens {
+		apiTokens = append(apiTokens, toApiApiToken(&dbApiTokens[i]))
+	}
+	return apiTokens, nil
+}
+
+func (api *api) CreateApiToken(req *CreateApiTokenRequest) (*CreateApiTokenResponse, error) {
+	dbApiToken, token, err := api.apiTokensSvc.CreateToken(req.Name, req.Scopes, req.AppId, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateApiTokenResponse{
+		ApiToken: toApiApiToken(dbApiToken),
+		Token:    token,
+	}, nil
+}
+
+func (api *api) RevokeApiToken(id uint) error {
+	return api.apiTokensSvc.RevokeToken(id)
+}
+
+func toApiApiToken(apiToken *db.ApiToken) ApiToken {
+	scopes := []string{}
+	scopes = append(scopes, apiToken.Scopes...)
+	var appName string
+	if apiToken.App != nil {
+		appName = apiToken.App.Name
+	}
+	return ApiToken{
+		Id:         apiToken.ID,
+		Name:       apiToken.Name,
+		Scopes:     scopes,
+		AppId:      apiToken.AppId,
+		AppName:    appName,
+		ExpiresAt:  apiToken.ExpiresAt,
+		LastUsedAt: apiToken.LastUsedAt,
+		CreatedAt:  apiToken.CreatedAt,
+	}
+}
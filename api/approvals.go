@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+)
+
+func (api *api) ListPaymentApprovals(state string) ([]PaymentApproval, error) {
+	if state != "" && !slices.Contains([]string{
+		db.PaymentApprovalStatePending,
+		db.PaymentApprovalStateApproved,
+		db.PaymentApprovalStateRejected,
+		db.PaymentApprovalStateExpired,
+	}, state) {
+		return nil, fmt.Errorf("%w: unknown approval state %q", constants.ErrInvalidParams, state)
+	}
+
+	dbApprovals, err := api.approvalsSvc.ListApprovals(state)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := []PaymentApproval{}
+	for i := range dbApprovals {
+		approvals = append(approvals, toApiPaymentApproval(&dbApprovals[i]))
+	}
+	return approvals, nil
+}
+
+func (api *api) DecidePaymentApproval(ctx context.Context, id uint, approve bool) (*PaymentApproval, error) {
+	if approve {
+		if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+			return nil, err
+		}
+		var amountMloki uint64
+		err := api.db.Model(&db.PaymentApproval{}).
+			Select("amount_mloki").
+			Where("id = ?", id).
+			Scan(&amountMloki).Error
+		if err != nil {
+			return nil, err
+		}
+		if err := api.verifyPaymentTotp(ctx, amountMloki); err != nil {
+			return nil, err
+		}
+	}
+
+	dbApproval, err := api.approvalsSvc.DecideApproval(id, approve)
+	if err != nil {
+		return nil, err
+	}
+	approval := toApiPaymentApproval(dbApproval)
+	return &approval, nil
+}
+
+func toApiPaymentApproval(approval *db.PaymentApproval) PaymentApproval {
+	return PaymentApproval{
+		Id:          approval.ID,
+		AppId:       approval.AppId,
+		AppName:     approval.App.Name,
+		Method:      approval.Method,
+		Amount:      approval.AmountMloki,
+		Destination: approval.Destination,
+		Invoice:     approval.PaymentRequest,
+		Description: approval.Description,
+		State:       approval.State,
+		CreatedAt:   approval.CreatedAt,
+		ExpiresAt:   approval.ExpiresAt,
+		DecidedAt:   approval.DecidedAt,
+	}
+}
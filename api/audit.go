@@ -0,0 +1,62 @@
+package api
+
+import (
+	"io"
+	"time"
+
+	"github.com/flokiorg/lokihub/audit"
+	"github.com/flokiorg/lokihub/db"
+)
+
+func (api *api) ListAuditLog(req *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	dbEntries, totalCount, err := api.auditSvc.List(toAuditFilter(&req.AuditLogFilter), req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []AuditLogEntry{}
+	for i := range dbEntries {
+		entries = append(entries, toApiAuditLogEntry(&dbEntries[i]))
+	}
+	return &ListAuditLogResponse{
+		Entries:    entries,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (api *api) ExportAuditLog(req *AuditLogFilter, w io.Writer) error {
+	return api.auditSvc.ExportCSV(toAuditFilter(req), w)
+}
+
+func toAuditFilter(req *AuditLogFilter) *audit.Filter {
+	filter := &audit.Filter{
+		Action:     req.Action,
+		Actor:      req.Actor,
+		Outcome:    req.Outcome,
+		ApiTokenId: req.ApiTokenId,
+	}
+	if req.From != 0 {
+		filter.From = time.Unix(int64(req.From), 0) //nolint:gosec // unix timestamps are far below int64 range
+	}
+	if req.Until != 0 {
+		filter.Until = time.Unix(int64(req.Until), 0) //nolint:gosec // unix timestamps are far below int64 range
+	}
+	return filter
+}
+
+func toApiAuditLogEntry(entry *db.AuditLogEntry) AuditLogEntry {
+	return AuditLogEntry{
+		Id:         entry.ID,
+		Action:     entry.Action,
+		Actor:      entry.Actor,
+		ApiTokenId: entry.ApiTokenId,
+		RemoteIp:   entry.RemoteIp,
+		RequestId:  entry.RequestId,
+		Method:     entry.Method,
+		Path:       entry.Path,
+		Payload:    entry.Payload,
+		Outcome:    entry.Outcome,
+		StatusCode: entry.StatusCode,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/flokiorg/lokihub/autoliquidity"
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+var autoLiquidityKeys = []string{
+	config.AutoLiquidityReceiveThresholdKey,
+	config.AutoLiquidityChannelSizeKey,
+	config.AutoLiquidityMaxFeeKey,
+	config.AutoLiquidityDailyLimitKey,
+	config.AutoLiquidityMonthlyLimitKey,
+}
+
+func (api *api) GetAutoLiquidityConfig() (*GetAutoLiquidityConfigResponse, error) {
+	autoLiquidityConfig, err := autoliquidity.LoadConfig(api.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if autoLiquidityConfig == nil {
+		return &GetAutoLiquidityConfigResponse{}, nil
+	}
+
+	response := &GetAutoLiquidityConfigResponse{
+		Enabled:          true,
+		ReceiveThreshold: autoLiquidityConfig.ReceiveThreshold,
+		ChannelSize:      autoLiquidityConfig.ChannelSize,
+		MaxFee:           autoLiquidityConfig.MaxFee,
+		DailyLimit:       autoLiquidityConfig.DailyLimit,
+		MonthlyLimit:     autoLiquidityConfig.MonthlyLimit,
+	}
+	if api.svc.GetAutoLiquidityService() != nil {
+		spending, err := api.svc.GetAutoLiquidityService().GetSpending()
+		if err != nil {
+			return nil, err
+		}
+		response.SpentToday = spending.Today
+		response.SpentThisMonth = spending.ThisMonth
+	}
+	return response, nil
+}
+
+func (api *api) EnableAutoLiquidity(req *EnableAutoLiquidityRequest) error {
+	if req.ChannelSize == 0 {
+		return fmt.Errorf("%w: channel size must be set", constants.ErrInvalidParams)
+	}
+	if req.DailyLimit == 0 || req.MonthlyLimit < req.DailyLimit {
+		return fmt.Errorf("%w: the monthly limit must be at least the daily limit", constants.ErrInvalidParams)
+	}
+
+	values := []uint64{req.ReceiveThreshold, req.ChannelSize, req.MaxFee, req.DailyLimit, req.MonthlyLimit}
+	for i, key := range autoLiquidityKeys {
+		if err := api.cfg.SetUpdate(key, strconv.FormatUint(values[i], 10), ""); err != nil {
+			logger.Logger.Error().Err(err).Str("key", key).Msg("Failed to save auto liquidity config")
+			return err
+		}
+	}
+
+	if api.svc.GetAutoLiquidityService() == nil {
+		return errors.New("AutoLiquidityService not started")
+	}
+	return api.svc.GetAutoLiquidityService().EnableAutoLiquidity()
+}
+
+func (api *api) DisableAutoLiquidity() error {
+	for _, key := range autoLiquidityKeys {
+		if err := api.cfg.SetUpdate(key, "", ""); err != nil {
+			logger.Logger.Error().Err(err).Str("key", key).Msg("Failed to remove auto liquidity config")
+			return err
+		}
+	}
+
+	if api.svc.GetAutoLiquidityService() != nil {
+		api.svc.GetAutoLiquidityService().StopAutoLiquidity()
+	}
+	return nil
+}
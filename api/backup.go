@@ -46,13 +46,16 @@ const backupFormatCTR = 0x02
 // disk. Generous enough to cover a Lightning node's db/channel state.
 const maxBackupEntrySize = 20 * 1024 * 1024 * 1024 // 20 GiB
 
-func (api *api) CreateBackup(unlockPassword string, w io.Writer) error {
+func (api *api) CreateBackup(unlockPassword string, totpCode string, w io.Writer) error {
 	logger.Logger.Info().Msg("Creating backup to migrate Lokihub to another device")
 	var err error
 
 	if !api.cfg.CheckUnlockPassword(unlockPassword) {
 		return errors.New("invalid unlock password")
 	}
+	if err := api.totpSvc.Verify(totpCode, unlockPassword); err != nil {
+		return err
+	}
 
 	autoUnlockPassword, err := api.cfg.Get("AutoUnlockPassword", "")
 	if err != nil {
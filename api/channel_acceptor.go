@@ -0,0 +1,24 @@
+package api
+
+import (
+	"github.com/flokiorg/lokihub/lsps/manager"
+)
+
+func (api *api) GetChannelAcceptorPolicy() (*manager.ChannelAcceptorPolicy, error) {
+	return manager.LoadChannelAcceptorPolicy(api.cfg)
+}
+
+func (api *api) UpdateChannelAcceptorPolicy(policy *manager.ChannelAcceptorPolicy) error {
+	return manager.SaveChannelAcceptorPolicy(api.cfg, policy)
+}
+
+func (api *api) ListChannelAcceptDecisions(req *ListChannelAcceptDecisionsRequest) (*ListChannelAcceptDecisionsResponse, error) {
+	decisions, totalCount, err := api.lspManager.ListChannelAcceptDecisions(req.Accepted, int(req.Limit), int(req.Offset)) //nolint:gosec // limit and offset are bounded by the caller
+	if err != nil {
+		return nil, err
+	}
+	return &ListChannelAcceptDecisionsResponse{
+		Decisions:  decisions,
+		TotalCount: totalCount,
+	}, nil
+}
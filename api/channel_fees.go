@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/flokiorg/lokihub/feemanager"
+)
+
+func (api *api) GetChannelFeeManagerConfig() (*feemanager.Config, error) {
+	return feemanager.LoadConfig(api.cfg)
+}
+
+func (api *api) UpdateChannelFeeManagerConfig(feeManagerConfig *feemanager.Config) error {
+	if err := feemanager.SaveConfig(api.cfg, feeManagerConfig); err != nil {
+		return err
+	}
+
+	if api.svc.GetFeeManagerService() == nil {
+		return errors.New("FeeManagerService not started")
+	}
+	return api.svc.GetFeeManagerService().EnableFeeManager()
+}
+
+func (api *api) ListChannelFeeUpdates(req *ListChannelFeeUpdatesRequest) (*ListChannelFeeUpdatesResponse, error) {
+	if api.svc.GetFeeManagerService() == nil {
+		return nil, errors.New("FeeManagerService not started")
+	}
+	dbFeeUpdates, totalCount, err := api.svc.GetFeeManagerService().ListFeeUpdates(req.ChannelId, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	feeUpdates := make([]ChannelFeeUpdate, 0, len(dbFeeUpdates))
+	for _, feeUpdate := range dbFeeUpdates {
+		feeUpdates = append(feeUpdates, ChannelFeeUpdate{
+			Id:                  feeUpdate.ID,
+			ChannelId:           feeUpdate.ChannelId,
+			PeerPubkey:          feeUpdate.PeerPubkey,
+			OldBaseFeeMloki:     feeUpdate.OldBaseFeeMloki,
+			NewBaseFeeMloki:     feeUpdate.NewBaseFeeMloki,
+			OldFeePpm:           feeUpdate.OldFeePpm,
+			NewFeePpm:           feeUpdate.NewFeePpm,
+			LocalBalancePercent: feeUpdate.LocalBalancePercent,
+			OutboundFlowMloki:   feeUpdate.OutboundFlowMloki,
+			CreatedAt:           feeUpdate.CreatedAt,
+		})
+	}
+	return &ListChannelFeeUpdatesResponse{
+		FeeUpdates: feeUpdates,
+		TotalCount: totalCount,
+	}, nil
+}
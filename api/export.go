@@ -0,0 +1,25 @@
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/flokiorg/lokihub/export"
+)
+
+func (api *api) ExportTransactions(ctx context.Context, req *ExportTransactionsRequest, w io.Writer) error {
+	exportRequest := &export.Request{
+		Format:       req.Format,
+		AppId:        req.AppId,
+		FiatCurrency: api.cfg.GetCurrency(),
+	}
+	if req.From != 0 {
+		exportRequest.From = time.Unix(int64(req.From), 0) //nolint:gosec // unix timestamps are far below int64 range
+	}
+	if req.Until != 0 {
+		exportRequest.Until = time.Unix(int64(req.Until), 0) //nolint:gosec // unix timestamps are far below int64 range
+	}
+
+	return export.Export(ctx, api.db, api.svc.GetLNClient(), exportRequest, w)
+}
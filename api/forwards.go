@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/db/queries"
+)
+
+func (api *api) GetForwards(req *GetForwardsRequest) (*GetForwardsResponse, error) {
+	var from, until time.Time
+	if req.From != 0 {
+		from = time.Unix(int64(req.From), 0) //nolint:gosec // unix timestamps fit in int64
+	}
+	if req.Until != 0 {
+		until = time.Unix(int64(req.Until), 0) //nolint:gosec // unix timestamps fit in int64
+	}
+	if !from.IsZero() && !until.IsZero() && until.Before(from) {
+		return nil, fmt.Errorf("%w: until must not be before from", constants.ErrInvalidParams)
+	}
+	if req.GroupBy != "" && req.GroupBy != queries.ForwardsGroupByChannel && req.GroupBy != queries.ForwardsGroupByPeer {
+		return nil, fmt.Errorf("%w: groupBy must be %q or %q", constants.ErrInvalidParams, queries.ForwardsGroupByChannel, queries.ForwardsGroupByPeer)
+	}
+
+	inRange := func(query *gorm.DB) *gorm.DB {
+		if !from.IsZero() {
+			query = query.Where("forwarded_at >= ?", from)
+		}
+		if !until.IsZero() {
+			query = query.Where("forwarded_at <= ?", until)
+		}
+		return query
+	}
+
+	var totals struct {
+		NumForwards                  uint64
+		OutboundAmountForwardedMloki uint64
+		TotalFeeEarnedMloki          uint64
+	}
+	err := inRange(api.db.Model(&db.Forward{})).
+		Select("COUNT(*) AS num_forwards, COALESCE(SUM(outbound_amount_forwarded_mloki), 0) AS outbound_amount_forwarded_mloki, COALESCE(SUM(total_fee_earned_mloki), 0) AS total_fee_earned_mloki").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var dbForwards []db.Forward
+	query := inRange(api.db).Order("forwarded_at DESC, id DESC").Offset(int(req.Offset)) //nolint:gosec // offsets are far below MaxInt
+	if req.Limit > 0 {
+		query = query.Limit(int(req.Limit)) //nolint:gosec // limits are far below MaxInt
+	}
+	if err := query.Find(&dbForwards).Error; err != nil {
+		return nil, err
+	}
+
+	forwards := make([]Forward, 0, len(dbForwards))
+	for _, dbForward := range dbForwards {
+		forwards = append(forwards, Forward{
+			Id:                  dbForward.ID,
+			IncomingChannelId:   dbForward.IncomingChannelId,
+			OutgoingChannelId:   dbForward.OutgoingChannelId,
+			IncomingPeerPubkey:  dbForward.IncomingPeerPubkey,
+			OutgoingPeerPubkey:  dbForward.OutgoingPeerPubkey,
+			InboundAmountMloki:  dbForward.InboundAmountMloki,
+			OutboundAmountMloki: dbForward.OutboundAmountForwardedMloki,
+			FeeEarnedMloki:      dbForward.TotalFeeEarnedMloki,
+			ForwardedAt:         dbForward.ForwardedAt,
+		})
+	}
+
+	var groups []ForwardsGroup
+	if req.GroupBy != "" {
+		earnings, err := queries.GetForwardEarnings(api.db, req.GroupBy, from, until)
+		if err != nil {
+			return nil, err
+		}
+		groups = make([]ForwardsGroup, 0, len(earnings))
+		for _, groupEarnings := range earnings {
+			group := ForwardsGroup{
+				NumForwardsIn:       groupEarnings.NumForwardsIn,
+				NumForwardsOut:      groupEarnings.NumForwardsOut,
+				InboundAmountMloki:  groupEarnings.InboundAmountMloki,
+				OutboundAmountMloki: groupEarnings.OutboundAmountMloki,
+				FeeEarnedMloki:      groupEarnings.FeeEarnedMloki,
+			}
+			if req.GroupBy == queries.ForwardsGroupByChannel {
+				group.ChannelId = groupEarnings.Key
+			} else {
+				group.PeerPubkey = groupEarnings.Key
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	return &GetForwardsResponse{
+		OutboundAmountForwardedMloki: totals.OutboundAmountForwardedMloki,
+		TotalFeeEarnedMloki:          totals.TotalFeeEarnedMloki,
+		NumForwards:                  totals.NumForwards,
+		TotalCount:                   totals.NumForwards,
+		Forwards:                     forwards,
+		Groups:                       groups,
+	}, nil
+}
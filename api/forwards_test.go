@@ -0,0 +1,92 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func createTestForwards(t *testing.T, svc *tests.TestService, forwardedAt ...time.Time) {
+	t.Helper()
+	for i, at := range forwardedAt {
+		nodeIndex := uint64(i + 1) //nolint:gosec // i is a non-negative slice index
+		require.NoError(t, svc.DB.Create(&db.Forward{
+			NodeIndex:                    &nodeIndex,
+			IncomingChannelId:            "1",
+			OutgoingChannelId:            "2",
+			IncomingPeerPubkey:           "02aaaa",
+			OutgoingPeerPubkey:           "02bbbb",
+			InboundAmountMloki:           101_000,
+			OutboundAmountForwardedMloki: 100_000,
+			TotalFeeEarnedMloki:          1_000,
+			ForwardedAt:                  at,
+		}).Error)
+	}
+}
+
+func TestGetForwards_PaginatesNewestFirst(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	now := time.Now().Truncate(time.Second)
+	createTestForwards(t, svc, now.Add(-3*time.Hour), now.Add(-2*time.Hour), now.Add(-time.Hour))
+	theAPI := newTestAPI(svc)
+
+	resp, err := theAPI.GetForwards(&GetForwardsRequest{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), resp.TotalCount)
+	assert.Equal(t, uint64(3), resp.NumForwards)
+	assert.Equal(t, uint64(3_000), resp.TotalFeeEarnedMloki)
+	assert.Equal(t, uint64(300_000), resp.OutboundAmountForwardedMloki)
+	require.Len(t, resp.Forwards, 2)
+	assert.True(t, now.Add(-2*time.Hour).Equal(resp.Forwards[0].ForwardedAt))
+	assert.True(t, now.Add(-3*time.Hour).Equal(resp.Forwards[1].ForwardedAt))
+	assert.Equal(t, "1", resp.Forwards[0].IncomingChannelId)
+	assert.Equal(t, uint64(101_000), resp.Forwards[0].InboundAmountMloki)
+	assert.Nil(t, resp.Groups)
+}
+
+func TestGetForwards_TimeRangeAndGrouping(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	now := time.Now().Truncate(time.Second)
+	createTestForwards(t, svc, now.Add(-3*time.Hour), now.Add(-2*time.Hour), now.Add(-time.Hour))
+	theAPI := newTestAPI(svc)
+
+	resp, err := theAPI.GetForwards(&GetForwardsRequest{
+		From:    uint64(now.Add(-150 * time.Minute).Unix()), //nolint:gosec // test timestamps are positive
+		GroupBy: "peer",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), resp.TotalCount)
+	assert.Equal(t, uint64(2_000), resp.TotalFeeEarnedMloki)
+	require.Len(t, resp.Groups, 2)
+	assert.Equal(t, ForwardsGroup{
+		PeerPubkey: "02bbbb", NumForwardsOut: 2, OutboundAmountMloki: 200_000, FeeEarnedMloki: 2_000,
+	}, resp.Groups[0])
+	assert.Equal(t, ForwardsGroup{
+		PeerPubkey: "02aaaa", NumForwardsIn: 2, InboundAmountMloki: 202_000,
+	}, resp.Groups[1])
+}
+
+func TestGetForwards_InvalidParams(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	theAPI := newTestAPI(svc)
+
+	_, err = theAPI.GetForwards(&GetForwardsRequest{GroupBy: "app"})
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+
+	_, err = theAPI.GetForwards(&GetForwardsRequest{From: 200, Until: 100})
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+}
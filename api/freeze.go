@@ -0,0 +1,42 @@
+package api
+
+import (
+	"github.com/flokiorg/lokihub/db"
+)
+
+func (api *api) GetFreezeState() (*FreezeState, error) {
+	state, err := api.freezeSvc.GetState()
+	if err != nil {
+		return nil, err
+	}
+	return toApiFreezeState(state), nil
+}
+
+func (api *api) Freeze(req *FreezeRequest, source string) (*FreezeState, error) {
+	state, err := api.freezeSvc.Freeze(req.PauseOutgoingPayments, source)
+	if err != nil {
+		return nil, err
+	}
+	return toApiFreezeState(state), nil
+}
+
+func (api *api) Unfreeze(source string) (*FreezeState, error) {
+	state, err := api.freezeSvc.Unfreeze(source)
+	if err != nil {
+		return nil, err
+	}
+	return toApiFreezeState(state), nil
+}
+
+func (api *api) SetAppFrozen(appId uint, req *FreezeAppRequest) error {
+	return api.freezeSvc.SetAppFrozen(appId, req.Frozen)
+}
+
+func toApiFreezeState(state *db.HubFreeze) *FreezeState {
+	return &FreezeState{
+		Frozen:                state.Frozen,
+		PauseOutgoingPayments: state.PauseOutgoingPayments,
+		Source:                state.Source,
+		FrozenAt:              state.FrozenAt,
+	}
+}
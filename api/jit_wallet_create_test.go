@@ -11,9 +11,11 @@ import (
 	"github.com/flokiorg/lokihub/apps"
 	"github.com/flokiorg/lokihub/constants"
 	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/lnclient"
 	"github.com/flokiorg/lokihub/tests"
 	"github.com/flokiorg/lokihub/tests/mocks"
+	"github.com/flokiorg/lokihub/totp"
 	"github.com/flokiorg/lokihub/transactions"
 )
 
@@ -39,6 +41,8 @@ func newTestAPIWithService(t *testing.T, svc *tests.TestService) *api {
 		cfg:       svc.Cfg,
 		svc:       mockSvc,
 		iaManager: apps.NewIdentityAuthorityManager(svc.DB),
+		totpSvc:   totp.NewTotpService(svc.DB, svc.Cfg),
+		freezeSvc: freeze.NewFreezeService(svc.DB, svc.EventPublisher),
 	}
 }
 
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnurl"
+)
+
+func (api *api) ListLightningAddresses() ([]LightningAddress, error) {
+	dbLightningAddresses, err := api.svc.GetLNURLService().ListLightningAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	lightningAddresses := []LightningAddress{}
+	for i := range dbLightningAddresses {
+		lightningAddresses = append(lightningAddresses, api.toApiLightningAddress(&dbLightningAddresses[i]))
+	}
+	return lightningAddresses, nil
+}
+
+func (api *api) CreateLightningAddress(req *CreateLightningAddressRequest) (*LightningAddress, error) {
+	// accept either a bare username or a full address
+	username, _, _ := strings.Cut(req.Address, "@")
+	dbLightningAddress, err := api.svc.GetLNURLService().CreateLightningAddress(req.AppId, username)
+	if err != nil {
+		return nil, err
+	}
+	lightningAddress := api.toApiLightningAddress(dbLightningAddress)
+	return &lightningAddress, nil
+}
+
+func (api *api) DeleteLightningAddress(appId uint) error {
+	return api.svc.GetLNURLService().DeleteLightningAddress(appId)
+}
+
+func (api *api) toApiLightningAddress(lightningAddress *db.LightningAddress) LightningAddress {
+	var address string
+	if baseUrl := api.cfg.GetEnv().BaseUrl; baseUrl != "" {
+		address = lnurl.Address(lightningAddress.Username, baseUrl)
+	}
+	return LightningAddress{
+		AppId:     lightningAddress.AppId,
+		Username:  lightningAddress.Username,
+		Address:   address,
+		CreatedAt: lightningAddress.CreatedAt,
+	}
+}
+
+func (api *api) GetLNURLPayParams(username string, baseUrl string) (*lnurl.PayParams, error) {
+	return api.svc.GetLNURLService().GetPayParams(username, baseUrl)
+}
+
+func (api *api) MakeLNURLPayInvoice(ctx context.Context, username string, baseUrl string, req *lnurl.InvoiceRequest) (*lnurl.InvoiceResponse, error) {
+	return api.svc.GetLNURLService().MakeInvoice(ctx, username, baseUrl, req)
+}
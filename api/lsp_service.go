@@ -0,0 +1,30 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lsps/manager"
+	"github.com/flokiorg/lokihub/lsps/persist"
+)
+
+func (api *api) GetLSPServiceConfig() (*manager.LSPServiceConfig, error) {
+	return manager.LoadLSPServiceConfig(api.cfg)
+}
+
+func (api *api) UpdateLSPServiceConfig(serviceConfig *manager.LSPServiceConfig) error {
+	return manager.SaveLSPServiceConfig(api.cfg, serviceConfig)
+}
+
+func (api *api) ListLSPServiceRefunds() ([]persist.LSPS1ServiceOrder, error) {
+	return persist.NewGormServiceStore(api.db).ListLSPS1OrdersToRefund()
+}
+
+func (api *api) MarkLSPServiceOrderRefunded(orderID string) (*persist.LSPS1ServiceOrder, error) {
+	order, err := persist.NewGormServiceStore(api.db).MarkLSPS1OrderRefunded(orderID)
+	if errors.Is(err, persist.ErrServiceRecordNotFound) {
+		return nil, fmt.Errorf("%w: order %s does not need a refund", constants.ErrInvalidParams, orderID)
+	}
+	return order, err
+}
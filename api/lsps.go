@@ -74,6 +74,13 @@ func (api *api) LSPS1GetInfo(ctx context.Context, req *LSPS1GetInfoRequest) (int
 
 // LSPS1CreateOrder creates a channel order
 func (api *api) LSPS1CreateOrder(ctx context.Context, req *LSPS1CreateOrderRequest) (interface{}, error) {
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	// the order fee is only known once the LSP quotes it
+	if err := api.verifyPaymentTotp(ctx, unknownAmountMloki); err != nil {
+		return nil, err
+	}
 	ctx, cancel := context.WithTimeout(ctx, lspsRequestTimeout)
 	defer cancel()
 	orderParams := lsps1.OrderParams{
@@ -211,6 +218,12 @@ func (api *api) LSPS2Buy(ctx context.Context, req *LSPS2BuyRequest) (*LSPS2BuyRe
 	if req.PaymentSizeMloki == nil {
 		return nil, fmt.Errorf("payment_size_mloki is required")
 	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	if err := api.verifyPaymentTotp(ctx, *req.PaymentSizeMloki); err != nil {
+		return nil, err
+	}
 
 	// Use the new robust BuyLiquidity method which handles retries
 	hints, err := api.svc.GetLiquidityManager().BuyLiquidity(ctx, req.LSPPubkey, *req.PaymentSizeMloki, &req.OpeningFeeParams)
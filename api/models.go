@@ -6,10 +6,13 @@ import (
 	"time"
 
 	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/feemanager"
 	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/lnurl"
 	"github.com/flokiorg/lokihub/lsps/lsps1"
 	"github.com/flokiorg/lokihub/lsps/lsps2"
 	"github.com/flokiorg/lokihub/lsps/manager"
+	"github.com/flokiorg/lokihub/lsps/persist"
 	"github.com/flokiorg/lokihub/swaps"
 )
 
@@ -64,6 +67,7 @@ type API interface {
 	ConnectPeer(ctx context.Context, connectPeerRequest *ConnectPeerRequest) error
 	DisconnectPeer(ctx context.Context, peerId string) error
 	OpenChannel(ctx context.Context, openChannelRequest *OpenChannelRequest) (*OpenChannelResponse, error)
+	BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error)
 
 	CloseChannel(ctx context.Context, peerId, channelId string, force bool) (*CloseChannelResponse, error)
 	UpdateChannel(ctx context.Context, updateChannelRequest *UpdateChannelRequest) error
@@ -71,17 +75,32 @@ type API interface {
 	GetNewOnchainAddress(ctx context.Context) (string, error)
 	GetUnusedOnchainAddress(ctx context.Context) (string, error)
 	SignMessage(ctx context.Context, message string) (*SignMessageResponse, error)
-	RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (*RedeemOnchainFundsResponse, error)
+	RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (*RedeemOnchainFundsResponse, error)
+	ListUtxos(ctx context.Context) ([]Utxo, error)
+	UpdateUtxo(ctx context.Context, updateUtxoRequest *UpdateUtxoRequest) error
+	CreatePsbt(ctx context.Context, createPsbtRequest *CreatePsbtRequest) (*CreatePsbtResponse, error)
+	FinalizePsbt(ctx context.Context, finalizePsbtRequest *FinalizePsbtRequest) (*FinalizePsbtResponse, error)
+	PublishTransaction(ctx context.Context, publishTransactionRequest *PublishTransactionRequest) (*PublishTransactionResponse, error)
+	BumpFee(ctx context.Context, bumpFeeRequest *BumpFeeRequest) (*BumpFeeResponse, error)
 	GetBalances(ctx context.Context) (*BalancesResponse, error)
 	ListTransactions(ctx context.Context, appId *uint, limit uint64, offset uint64) (*ListTransactionsResponse, error)
 	ListOnchainTransactions(ctx context.Context, limit, offset uint64) ([]lnclient.OnchainTransaction, error)
+	ExportTransactions(ctx context.Context, req *ExportTransactionsRequest, w io.Writer) error
 	SendPayment(ctx context.Context, invoice string, amountMloki *uint64, appID *uint, metadata map[string]interface{}) (*SendPaymentResponse, error)
 	CreateInvoice(ctx context.Context, req *MakeInvoiceRequest) (*MakeInvoiceResponse, error)
 	LookupInvoice(ctx context.Context, paymentHash string) (*LookupInvoiceResponse, error)
+	CreateOffer(ctx context.Context, req *MakeOfferRequest) (*Offer, error)
+	LookupOffer(ctx context.Context, offer string) (*Offer, error)
+	PayOffer(ctx context.Context, req *PayOfferRequest) (*SendPaymentResponse, error)
+	ListLightningAddresses() ([]LightningAddress, error)
+	CreateLightningAddress(req *CreateLightningAddressRequest) (*LightningAddress, error)
+	DeleteLightningAddress(appId uint) error
+	GetLNURLPayParams(username string, baseUrl string) (*lnurl.PayParams, error)
+	MakeLNURLPayInvoice(ctx context.Context, username string, baseUrl string, req *lnurl.InvoiceRequest) (*lnurl.InvoiceResponse, error)
 	RequestMempoolApi(ctx context.Context, endpoint string) (interface{}, error)
 	GetServices(ctx context.Context) (interface{}, error)
 	GetInfo(ctx context.Context) (*InfoResponse, error)
-	GetMnemonic(unlockPassword string) (*MnemonicResponse, error)
+	GetMnemonic(unlockPassword string, totpCode string) (*MnemonicResponse, error)
 	SetNextBackupReminder(backupReminderRequest *BackupReminderRequest) error
 	Start(startRequest *StartRequest) error
 	Setup(ctx context.Context, setupRequest *SetupRequest) error
@@ -94,7 +113,7 @@ type API interface {
 	SyncWallet() error
 	GetLogOutput(ctx context.Context, logType string, getLogRequest *GetLogOutputRequest) (*GetLogOutputResponse, error)
 
-	CreateBackup(unlockPassword string, w io.Writer) error
+	CreateBackup(unlockPassword string, totpCode string, w io.Writer) error
 	RestoreBackup(unlockPassword string, r io.Reader) error
 	MigrateNodeStorage(ctx context.Context, to string) error
 	GetWalletCapabilities(ctx context.Context) (*WalletCapabilitiesResponse, error)
@@ -113,11 +132,65 @@ type API interface {
 	GetAutoSwapConfig() (*GetAutoSwapConfigResponse, error)
 	EnableAutoSwapOut(ctx context.Context, autoSwapRequest *EnableAutoSwapRequest) error
 	DisableAutoSwap() error
+	EnableAutoSwapIn(req *EnableAutoSwapInRequest) error
+	DisableAutoSwapIn() error
+	GetAutoLiquidityConfig() (*GetAutoLiquidityConfigResponse, error)
+	EnableAutoLiquidity(req *EnableAutoLiquidityRequest) error
+	DisableAutoLiquidity() error
+	Rebalance(ctx context.Context, req *RebalanceRequest) (*Rebalance, error)
+	ListRebalances(req *ListRebalancesRequest) (*ListRebalancesResponse, error)
+	GetAutoRebalanceConfig() (*GetAutoRebalanceConfigResponse, error)
+	EnableAutoRebalance(req *EnableAutoRebalanceRequest) error
+	DisableAutoRebalance() error
 	SetNodeAlias(ctx context.Context, nodeAlias string) error
 	GetCustomNodeCommands() (*CustomNodeCommandsResponse, error)
 	ExecuteCustomNodeCommand(ctx context.Context, command string) (interface{}, error)
 	SendEvent(event string, properties interface{})
-	GetForwards() (*GetForwardsResponse, error)
+	GetForwards(req *GetForwardsRequest) (*GetForwardsResponse, error)
+	ListPaymentApprovals(state string) ([]PaymentApproval, error)
+	DecidePaymentApproval(ctx context.Context, id uint, approve bool) (*PaymentApproval, error)
+	ListWebhookEndpoints() ([]WebhookEndpoint, error)
+	CreateWebhookEndpoint(req *CreateWebhookEndpointRequest) (*CreateWebhookEndpointResponse, error)
+	UpdateWebhookEndpoint(id uint, req *UpdateWebhookEndpointRequest) (*WebhookEndpoint, error)
+	DeleteWebhookEndpoint(id uint) error
+	ListWebhookDeliveries(req *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	RedeliverWebhookDelivery(id uint) (*WebhookDelivery, error)
+	ListApiTokens() ([]ApiToken, error)
+	CreateApiToken(req *CreateApiTokenRequest) (*CreateApiTokenResponse, error)
+	RevokeApiToken(id uint) error
+	ListAuditLog(req *ListAuditLogRequest) (*ListAuditLogResponse, error)
+	ListAdminPubkeys() ([]AdminPubkey, error)
+	AddAdminPubkey(req *AddAdminPubkeyRequest) (*AdminPubkey, error)
+	RemoveAdminPubkey(id uint) error
+	GetFreezeState() (*FreezeState, error)
+	// Freeze freezes the hub; source says what triggered it.
+	Freeze(req *FreezeRequest, source string) (*FreezeState, error)
+	Unfreeze(source string) (*FreezeState, error)
+	SetAppFrozen(appId uint, req *FreezeAppRequest) error
+	GetTotpStatus() (*TotpStatus, error)
+	BeginTotpEnrollment(req *BeginTotpEnrollmentRequest) (*BeginTotpEnrollmentResponse, error)
+	ConfirmTotpEnrollment(req *TotpCodeRequest) (*TotpRecoveryCodesResponse, error)
+	DisableTotp(req *DisableTotpRequest) error
+	RegenerateTotpRecoveryCodes(req *TotpCodeRequest) (*TotpRecoveryCodesResponse, error)
+	UpdateTotp(req *UpdateTotpRequest) error
+	VerifyTotp(code string, unlockPassword string) error
+	ExportAuditLog(req *AuditLogFilter, w io.Writer) error
+
+	// Channel acceptor
+	GetChannelAcceptorPolicy() (*manager.ChannelAcceptorPolicy, error)
+	UpdateChannelAcceptorPolicy(policy *manager.ChannelAcceptorPolicy) error
+	ListChannelAcceptDecisions(req *ListChannelAcceptDecisionsRequest) (*ListChannelAcceptDecisionsResponse, error)
+	GetChannelFeeManagerConfig() (*feemanager.Config, error)
+	UpdateChannelFeeManagerConfig(feeManagerConfig *feemanager.Config) error
+	ListChannelFeeUpdates(req *ListChannelFeeUpdatesRequest) (*ListChannelFeeUpdatesResponse, error)
+	GetLSPServiceConfig() (*manager.LSPServiceConfig, error)
+	UpdateLSPServiceConfig(serviceConfig *manager.LSPServiceConfig) error
+	// ListLSPServiceRefunds returns the paid LSPS1 orders sold by this node
+	// whose channel failed to open, or may not have been opened
+	ListLSPServiceRefunds() ([]persist.LSPS1ServiceOrder, error)
+	// MarkLSPServiceOrderRefunded records that the payment of an order listed
+	// by ListLSPServiceRefunds was returned to the client
+	MarkLSPServiceOrderRefunded(orderID string) (*persist.LSPS1ServiceOrder, error)
 
 	// LSPS
 	LSPS0ListProtocols(ctx context.Context, req *LSPS0ListProtocolsRequest) (*LSPS0ListProtocolsResponse, error)
@@ -224,10 +297,26 @@ type App struct {
 	// CircleMaxExpSecs/CircleFeesPpm/CirclePerWalletMaxMloki/CircleMinBudgetRenewal
 	// are set only for circle_hub apps — the hub-wide defaults set at
 	// creation time, for the same reason as above.
-	CircleMaxExpSecs        *int    `json:"circleMaxExpSecs,omitempty"`
-	CircleFeesPpm           *int    `json:"circleFeesPpm,omitempty"`
-	CirclePerWalletMaxMloki *int    `json:"circlePerWalletMaxMloki,omitempty"`
-	CircleMinBudgetRenewal  *string `json:"circleMinBudgetRenewal,omitempty"`
+	CircleMaxExpSecs        *int            `json:"circleMaxExpSecs,omitempty"`
+	CircleFeesPpm           *int            `json:"circleFeesPpm,omitempty"`
+	CirclePerWalletMaxMloki *int            `json:"circlePerWalletMaxMloki,omitempty"`
+	CircleMinBudgetRenewal  *string         `json:"circleMinBudgetRenewal,omitempty"`
+	SpendingPolicy          *SpendingPolicy `json:"spendingPolicy,omitempty"`
+	// Frozen apps only answer get_info
+	Frozen bool `json:"frozen"`
+}
+
+// SpendingPolicy holds the per-payment rules an app's outgoing payments are
+// checked against on top of its budget. Zero values and empty lists disable
+// the corresponding rule. NIP-47 payments above ApprovalThresholdLoki wait
+// for the owner's approval (see /api/approvals).
+type SpendingPolicy struct {
+	MaxAmountPerPaymentLoki int      `json:"maxAmountPerPayment"`
+	MaxPaymentsPerHour      int      `json:"maxPaymentsPerHour"`
+	MaxFeePpm               int      `json:"maxFeePpm"`
+	AllowedPubkeys          []string `json:"allowedPubkeys"`
+	DeniedPubkeys           []string `json:"deniedPubkeys"`
+	ApprovalThresholdLoki   int      `json:"approvalThreshold"`
 }
 
 // CircleIdentitySummary is the bare identity, used for the circle-creation-time picker.
@@ -299,6 +388,9 @@ type UpdateAppRequest struct {
 	CircleFeesPpm           *int    `json:"circleFeesPpm"`
 	CirclePerWalletMaxMloki *int    `json:"circlePerWalletMaxMloki"`
 	CircleMinBudgetRenewal  *string `json:"circleMinBudgetRenewal"`
+	// SpendingPolicy replaces the app's spending policy; nil leaves it
+	// unchanged and a policy without any rule removes it.
+	SpendingPolicy *SpendingPolicy `json:"spendingPolicy"`
 }
 
 type TransferRequest struct {
@@ -329,9 +421,10 @@ type CreateAppRequest struct {
 	CircleIdentityId *uint `json:"circleIdentityId"`
 	// CircleIdentityName/CirclePolicy/ProviderPubkey create a brand-new
 	// CircleIdentity — used only when CircleIdentityId is nil.
-	CircleIdentityName string `json:"circleIdentityName"`
-	CirclePolicy       string `json:"circlePolicy"`
-	ProviderPubkey     string `json:"providerPubkey"`
+	CircleIdentityName string          `json:"circleIdentityName"`
+	CirclePolicy       string          `json:"circlePolicy"`
+	ProviderPubkey     string          `json:"providerPubkey"`
+	SpendingPolicy     *SpendingPolicy `json:"spendingPolicy"`
 }
 
 type CreateLightningAddressRequest struct {
@@ -339,6 +432,15 @@ type CreateLightningAddressRequest struct {
 	AppId   uint   `json:"appId"`
 }
 
+type LightningAddress struct {
+	AppId    uint   `json:"appId"`
+	Username string `json:"username"`
+	// Address is empty until BASE_URL is configured, as the domain part of
+	// the address is the host the hub is publicly reachable on.
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 // JIT wallet / claim types.
 
 // JITWalletRecipient describes one recipient's requested slice when creating
@@ -444,11 +546,105 @@ type EnableAutoSwapRequest struct {
 }
 
 type GetAutoSwapConfigResponse struct {
-	Type             string `json:"type"`
+	Type             string                   `json:"type"`
+	Enabled          bool                     `json:"enabled"`
+	BalanceThreshold uint64                   `json:"balanceThreshold"`
+	SwapAmount       uint64                   `json:"swapAmount"`
+	Destination      string                   `json:"destination"`
+	SwapIn           AutoSwapInConfigResponse `json:"swapIn"`
+}
+
+// EnableAutoSwapInRequest configures refilling the Lightning balance from the
+// on-chain wallet. All amounts are in loki.
+type EnableAutoSwapInRequest struct {
+	SpendableThreshold uint64 `json:"spendableThreshold"`
+	OnchainThreshold   uint64 `json:"onchainThreshold"`
+	SwapAmount         uint64 `json:"swapAmount"`
+	DailyLimit         uint64 `json:"dailyLimit"`
+}
+
+type AutoSwapInConfigResponse struct {
+	Enabled            bool     `json:"enabled"`
+	SpendableThreshold uint64   `json:"spendableThreshold"`
+	OnchainThreshold   uint64   `json:"onchainThreshold"`
+	SwapAmount         uint64   `json:"swapAmount"`
+	DailyLimit         uint64   `json:"dailyLimit"`
+	SwappedToday       uint64   `json:"swappedToday"`
+	PendingSwapId      string   `json:"pendingSwapId,omitempty"`
+	UnrefundedSwapIds  []string `json:"unrefundedSwapIds"`
+}
+
+// EnableAutoLiquidityRequest configures buying inbound liquidity over LSPS1.
+// All amounts are in loki.
+type EnableAutoLiquidityRequest struct {
+	ReceiveThreshold uint64 `json:"receiveThreshold"`
+	ChannelSize      uint64 `json:"channelSize"`
+	MaxFee           uint64 `json:"maxFee"`
+	DailyLimit       uint64 `json:"dailyLimit"`
+	MonthlyLimit     uint64 `json:"monthlyLimit"`
+}
+
+type GetAutoLiquidityConfigResponse struct {
 	Enabled          bool   `json:"enabled"`
-	BalanceThreshold uint64 `json:"balanceThreshold"`
-	SwapAmount       uint64 `json:"swapAmount"`
-	Destination      string `json:"destination"`
+	ReceiveThreshold uint64 `json:"receiveThreshold"`
+	ChannelSize      uint64 `json:"channelSize"`
+	MaxFee           uint64 `json:"maxFee"`
+	DailyLimit       uint64 `json:"dailyLimit"`
+	MonthlyLimit     uint64 `json:"monthlyLimit"`
+	SpentToday       uint64 `json:"spentToday"`
+	SpentThisMonth   uint64 `json:"spentThisMonth"`
+}
+
+// RebalanceRequest moves AmountLoki from the outgoing channel to the
+// incoming channel, paying at most MaxFeePpm of the amount in routing fees.
+type RebalanceRequest struct {
+	OutgoingChannelId string `json:"outgoingChannelId"`
+	IncomingChannelId string `json:"incomingChannelId"`
+	AmountLoki        uint64 `json:"amountLoki"`
+	MaxFeePpm         uint32 `json:"maxFeePpm"`
+}
+
+type Rebalance struct {
+	Id                uint      `json:"id"`
+	OutgoingChannelId string    `json:"outgoingChannelId"`
+	IncomingChannelId string    `json:"incomingChannelId"`
+	LastHopPubkey     string    `json:"lastHopPubkey"`
+	AmountMloki       uint64    `json:"amountMloki"`
+	FeeMloki          uint64    `json:"feeMloki"`
+	MaxFeePpm         uint32    `json:"maxFeePpm"`
+	PaymentHash       string    `json:"paymentHash"`
+	State             string    `json:"state"`
+	FailureReason     string    `json:"failureReason,omitempty"`
+	Auto              bool      `json:"auto"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type ListRebalancesRequest struct {
+	Limit  uint64
+	Offset uint64
+}
+
+type ListRebalancesResponse struct {
+	Rebalances []Rebalance `json:"rebalances"`
+	TotalCount uint64      `json:"totalCount"`
+}
+
+// EnableAutoRebalanceRequest configures the rebalance scheduler. Channels
+// whose local balance is at least SourceMinLocalPercent of their capacity are
+// rebalanced into channels at or below TargetMaxLocalPercent.
+type EnableAutoRebalanceRequest struct {
+	SourceMinLocalPercent uint64 `json:"sourceMinLocalPercent"`
+	TargetMaxLocalPercent uint64 `json:"targetMaxLocalPercent"`
+	AmountLoki            uint64 `json:"amountLoki"`
+	MaxFeePpm             uint64 `json:"maxFeePpm"`
+}
+
+type GetAutoRebalanceConfigResponse struct {
+	Enabled               bool   `json:"enabled"`
+	SourceMinLocalPercent uint64 `json:"sourceMinLocalPercent"`
+	TargetMaxLocalPercent uint64 `json:"targetMaxLocalPercent"`
+	AmountLoki            uint64 `json:"amountLoki"`
+	MaxFeePpm             uint64 `json:"maxFeePpm"`
 }
 
 type SwapInfoResponse struct {
@@ -493,6 +689,8 @@ type UnlockRequest struct {
 	UnlockPassword  string  `json:"unlockPassword"`
 	TokenExpiryDays *uint64 `json:"tokenExpiryDays"`
 	Permission      string  `json:"permission,omitempty"` // "full" or "readonly"
+	// TotpCode is required for full access once TOTP is enabled
+	TotpCode string `json:"totpCode,omitempty"`
 }
 
 type BackupReminderRequest struct {
@@ -665,6 +863,7 @@ type InfoResponse struct {
 	MessageboardNwcUrl          string              `json:"messageboardNwcUrl"`
 	EnableSwap                  bool                `json:"enableSwap"`
 	EnableMessageboardNwc       bool                `json:"enableMessageboardNwc"`
+	EnableHttpWebhooks          bool                `json:"enableHttpWebhooks"`
 	WorkDir                     string              `json:"workDir"`
 	EnablePolling               bool                `json:"enablePolling"`
 }
@@ -698,6 +897,7 @@ func (r *InfoResponse) RedactForUnauthenticated() {
 	r.MessageboardNwcUrl = ""
 	r.EnableSwap = false
 	r.EnableMessageboardNwc = false
+	r.EnableHttpWebhooks = false
 	r.EnablePolling = false
 }
 
@@ -714,6 +914,7 @@ type UpdateSettingsRequest struct {
 	LSPs                   []LSPSettingInput `json:"lsps,omitempty"`
 	EnableSwap             *bool             `json:"enableSwap"`
 	EnableMessageboardNwc  *bool             `json:"enableMessageboardNwc"`
+	EnableHttpWebhooks     *bool             `json:"enableHttpWebhooks"`
 }
 
 type LSPSettingInput struct {
@@ -730,6 +931,7 @@ type SetNodeAliasRequest struct {
 
 type MnemonicRequest struct {
 	UnlockPassword string `json:"unlockPassword"`
+	TotpCode       string `json:"totpCode,omitempty"`
 }
 
 type MnemonicResponse struct {
@@ -739,6 +941,7 @@ type MnemonicResponse struct {
 type ChangeUnlockPasswordRequest struct {
 	CurrentUnlockPassword string `json:"currentUnlockPassword"`
 	NewUnlockPassword     string `json:"newUnlockPassword"`
+	TotpCode              string `json:"totpCode,omitempty"`
 }
 type AutoUnlockRequest struct {
 	UnlockPassword string `json:"unlockPassword"`
@@ -747,6 +950,8 @@ type AutoUnlockRequest struct {
 type ConnectPeerRequest = lnclient.ConnectPeerRequest
 type OpenChannelRequest = lnclient.OpenChannelRequest
 type OpenChannelResponse = lnclient.OpenChannelResponse
+type BatchOpenChannelRequest = lnclient.BatchOpenChannelRequest
+type BatchOpenChannelResponse = lnclient.BatchOpenChannelResponse
 type CloseChannelResponse = lnclient.CloseChannelResponse
 type UpdateChannelRequest = lnclient.UpdateChannelRequest
 
@@ -755,12 +960,51 @@ type RedeemOnchainFundsRequest struct {
 	Amount    uint64  `json:"amount"`
 	FeeRate   *uint64 `json:"feeRate"`
 	SendAll   bool    `json:"sendAll"`
+	// Outpoints restricts the inputs to the given "txid:vout" UTXOs
+	Outpoints []string `json:"outpoints,omitempty"`
+	TotpCode  string   `json:"totpCode,omitempty"`
 }
 
 type RedeemOnchainFundsResponse struct {
 	TxId string `json:"txId"`
 }
 
+type Utxo struct {
+	Outpoint      string `json:"outpoint"`
+	Address       string `json:"address"`
+	AmountLoki    int64  `json:"amountLoki"`
+	Confirmations int64  `json:"confirmations"`
+	Frozen        bool   `json:"frozen"`
+	Label         string `json:"label"`
+}
+
+// UpdateUtxoRequest changes the label and/or frozen state of an output.
+// An empty label removes it.
+type UpdateUtxoRequest struct {
+	Outpoint string  `json:"outpoint"`
+	Label    *string `json:"label"`
+	Frozen   *bool   `json:"frozen"`
+}
+
+type CreatePsbtRequest = lnclient.CreatePsbtRequest
+type CreatePsbtResponse = lnclient.CreatePsbtResponse
+type FinalizePsbtResponse = lnclient.FinalizePsbtResponse
+type BumpFeeRequest = lnclient.BumpFeeRequest
+type BumpFeeResponse = lnclient.BumpFeeResponse
+
+type FinalizePsbtRequest struct {
+	Psbt string `json:"psbt"`
+}
+
+type PublishTransactionRequest struct {
+	RawTx string `json:"rawTx"`
+	Label string `json:"label"`
+}
+
+type PublishTransactionResponse struct {
+	TxId string `json:"txId"`
+}
+
 type OnchainBalanceResponse = lnclient.OnchainBalanceResponse
 type BalancesResponse = lnclient.BalancesResponse
 
@@ -818,8 +1062,11 @@ type SendPaymentProbesRequest struct {
 	Invoice string `json:"invoice"`
 }
 
+type ProbeResult = lnclient.ProbeResult
+
 type SendPaymentProbesResponse struct {
-	Error string `json:"error"`
+	Error string       `json:"error"`
+	Route *ProbeResult `json:"route,omitempty"`
 }
 
 type SendSpontaneousPaymentProbesRequest struct {
@@ -828,7 +1075,8 @@ type SendSpontaneousPaymentProbesRequest struct {
 }
 
 type SendSpontaneousPaymentProbesResponse struct {
-	Error string `json:"error"`
+	Error string       `json:"error"`
+	Route *ProbeResult `json:"route,omitempty"`
 }
 
 const (
@@ -952,10 +1200,28 @@ type PayInvoiceRequest struct {
 	Amount   *uint64  `json:"amount"`
 	AppId    *uint    `json:"appId"`
 	Metadata Metadata `json:"metadata"`
+	TotpCode string   `json:"totpCode,omitempty"`
 }
 
 type MakeOfferRequest struct {
 	Description string `json:"description"`
+	AppId       *uint  `json:"appId,omitempty"`
+}
+
+type PayOfferRequest struct {
+	Offer     string   `json:"offer"`
+	Amount    uint64   `json:"amount"`
+	PayerNote string   `json:"payerNote"`
+	AppId     *uint    `json:"appId"`
+	Metadata  Metadata `json:"metadata"`
+	TotpCode  string   `json:"totpCode,omitempty"`
+}
+
+type Offer struct {
+	Offer       string `json:"offer"`
+	Description string `json:"description"`
+	AppId       *uint  `json:"appId"`
+	CreatedAt   string `json:"createdAt"`
 }
 
 type MakeInvoiceRequest struct {
@@ -976,6 +1242,7 @@ type ResetRouterRequest struct {
 
 type BasicBackupRequest struct {
 	UnlockPassword string `json:"unlockPassword"`
+	TotpCode       string `json:"totpCode,omitempty"`
 }
 
 type BasicRestoreWailsRequest struct {
@@ -1043,6 +1310,8 @@ type HealthResponse struct {
 type CustomNodeCommandArgDef struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
 }
 
 type CustomNodeCommandDef struct {
@@ -1059,8 +1328,320 @@ type ExecuteCustomNodeCommandRequest struct {
 	Command string `json:"command"`
 }
 
+// ExportTransactionsRequest selects the settled Lightning payments, on-chain
+// transactions, swaps and forwards to export. Format is "csv", "beancount"
+// or "hledger". From and Until are unix timestamps (0 leaves that end of the
+// range open). With AppId set only that app's Lightning payments are
+// exported.
+type ExportTransactionsRequest struct {
+	Format string
+	From   uint64
+	Until  uint64
+	AppId  *uint
+}
+
+// GetForwardsRequest selects a page of the forwarding history. From and Until
+// are unix timestamps (0 leaves that end of the range open) and also bound the
+// totals and groups. GroupBy is "channel", "peer" or empty for no grouping.
+type GetForwardsRequest struct {
+	Limit   uint64
+	Offset  uint64
+	From    uint64
+	Until   uint64
+	GroupBy string
+}
+
 type GetForwardsResponse struct {
-	OutboundAmountForwardedMloki uint64 `json:"outboundAmountForwardedMloki"`
-	TotalFeeEarnedMloki          uint64 `json:"totalFeeEarnedMloki"`
-	NumForwards                  uint64 `json:"numForwards"`
+	OutboundAmountForwardedMloki uint64          `json:"outboundAmountForwardedMloki"`
+	TotalFeeEarnedMloki          uint64          `json:"totalFeeEarnedMloki"`
+	NumForwards                  uint64          `json:"numForwards"`
+	TotalCount                   uint64          `json:"totalCount"`
+	Forwards                     []Forward       `json:"forwards"`
+	Groups                       []ForwardsGroup `json:"groups,omitempty"`
+}
+
+type Forward struct {
+	Id                  uint      `json:"id"`
+	IncomingChannelId   string    `json:"incomingChannelId"`
+	OutgoingChannelId   string    `json:"outgoingChannelId"`
+	IncomingPeerPubkey  string    `json:"incomingPeerPubkey"`
+	OutgoingPeerPubkey  string    `json:"outgoingPeerPubkey"`
+	InboundAmountMloki  uint64    `json:"inboundAmountMloki"`
+	OutboundAmountMloki uint64    `json:"outboundAmountMloki"`
+	FeeEarnedMloki      uint64    `json:"feeEarnedMloki"`
+	ForwardedAt         time.Time `json:"forwardedAt"`
+}
+
+// ForwardsGroup holds the forwarding totals of one channel or peer. Fees are
+// credited to the channel or peer the forward left through.
+type ForwardsGroup struct {
+	ChannelId           string `json:"channelId,omitempty"`
+	PeerPubkey          string `json:"peerPubkey,omitempty"`
+	NumForwardsIn       uint64 `json:"numForwardsIn"`
+	NumForwardsOut      uint64 `json:"numForwardsOut"`
+	InboundAmountMloki  uint64 `json:"inboundAmountMloki"`
+	OutboundAmountMloki uint64 `json:"outboundAmountMloki"`
+	FeeEarnedMloki      uint64 `json:"feeEarnedMloki"`
+}
+
+// PaymentApproval is a NIP-47 payment held for the owner's decision because
+// it is above its app's approval threshold. State is "pending", "approved",
+// "rejected" or "expired"; the NIP-47 request fails once ExpiresAt passes.
+type PaymentApproval struct {
+	Id          uint       `json:"id"`
+	AppId       uint       `json:"appId"`
+	AppName     string     `json:"appName"`
+	Method      string     `json:"method"`
+	Amount      uint64     `json:"amount"`
+	Destination string     `json:"destination"`
+	Invoice     string     `json:"invoice"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	DecidedAt   *time.Time `json:"decidedAt"`
+}
+
+type DecidePaymentApprovalRequest struct {
+	Approve bool `json:"approve"`
+}
+
+// WebhookEndpoint receives hub events as signed JSON POSTs. Events lists the
+// forwarded event names; empty means every event.
+type WebhookEndpoint struct {
+	Id        uint      `json:"id"`
+	Url       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CreateWebhookEndpointRequest struct {
+	Url     string   `json:"url"`
+	Events  []string `json:"events"`
+	Enabled bool     `json:"enabled"`
+}
+
+// CreateWebhookEndpointResponse is the only response that includes the
+// endpoint's signing secret.
+type CreateWebhookEndpointResponse struct {
+	WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+type UpdateWebhookEndpointRequest struct {
+	Url     *string   `json:"url"`
+	Events  *[]string `json:"events"`
+	Enabled *bool     `json:"enabled"`
+}
+
+type ListWebhookDeliveriesRequest struct {
+	EndpointId uint
+	State      string
+	Limit      uint64
+	Offset     uint64
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	TotalCount int64             `json:"totalCount"`
+}
+
+// WebhookDelivery is one event sent, or still to be sent, to an endpoint.
+// State is "pending", "delivered" or "failed".
+type WebhookDelivery struct {
+	Id             uint       `json:"id"`
+	EndpointId     uint       `json:"endpointId"`
+	Event          string     `json:"event"`
+	Payload        string     `json:"payload"`
+	State          string     `json:"state"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt"`
+	LastStatusCode int        `json:"lastStatusCode"`
+	LastError      string     `json:"lastError"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt"`
+}
+
+// ApiToken is a named admin API token. The token itself is only returned
+// once, on creation.
+type ApiToken struct {
+	Id         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	AppId      *uint      `json:"appId"`
+	AppName    string     `json:"appName,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreateApiTokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	AppId     *uint      `json:"appId"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type CreateApiTokenResponse struct {
+	ApiToken
+	Token string `json:"token"`
+}
+
+// AdminPubkey is a Nostr pubkey that can log in to the admin API with a
+// NIP-98 signed event. Permission is "full" or "readonly".
+type AdminPubkey struct {
+	Id         uint      `json:"id"`
+	Pubkey     string    `json:"pubkey"`
+	Name       string    `json:"name"`
+	Permission string    `json:"permission"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type AddAdminPubkeyRequest struct {
+	Pubkey     string `json:"pubkey"`
+	Name       string `json:"name"`
+	Permission string `json:"permission"`
+}
+
+// NostrUnlockRequest is the optional body of a NIP-98 login. The body is
+// covered by the signed event, so the TOTP code cannot be swapped in transit.
+type NostrUnlockRequest struct {
+	TokenExpiryDays *uint64 `json:"tokenExpiryDays"`
+	// TotpCode is required for full access once TOTP is enabled
+	TotpCode string `json:"totpCode,omitempty"`
+}
+
+// FreezeState is the emergency freeze state of the hub. Source is what last
+// changed it: "api", "tray" or "nostr:<admin pubkey>".
+type FreezeState struct {
+	Frozen                bool       `json:"frozen"`
+	PauseOutgoingPayments bool       `json:"pauseOutgoingPayments"`
+	Source                string     `json:"source"`
+	FrozenAt              *time.Time `json:"frozenAt"`
+}
+
+type FreezeRequest struct {
+	PauseOutgoingPayments bool `json:"pauseOutgoingPayments"`
+}
+
+type FreezeAppRequest struct {
+	Frozen bool `json:"frozen"`
+}
+
+// TotpStatus describes the TOTP second factor. PaymentThresholdMloki is the
+// amount above which payments require a code; nil means every payment does.
+type TotpStatus struct {
+	Enabled               bool    `json:"enabled"`
+	Pending               bool    `json:"pending"`
+	PaymentThresholdMloki *uint64 `json:"paymentThresholdMloki"`
+	RecoveryCodesLeft     int     `json:"recoveryCodesLeft"`
+}
+
+type BeginTotpEnrollmentRequest struct {
+	UnlockPassword string `json:"unlockPassword"`
+}
+
+type BeginTotpEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthUrl string `json:"otpauthUrl"`
+}
+
+type TotpCodeRequest struct {
+	TotpCode string `json:"totpCode"`
+}
+
+type DisableTotpRequest struct {
+	UnlockPassword string `json:"unlockPassword"`
+	TotpCode       string `json:"totpCode"`
+}
+
+type UpdateTotpRequest struct {
+	PaymentThresholdMloki *uint64 `json:"paymentThresholdMloki"`
+	TotpCode              string  `json:"totpCode"`
+}
+
+// TotpRecoveryCodesResponse holds newly generated recovery codes, which are
+// only shown once.
+type TotpRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// AuditLogFilter selects audit log entries. Outcome is "success" or
+// "failure". From and Until are unix timestamps (0 leaves that end of the
+// range open). Empty fields match every entry.
+type AuditLogFilter struct {
+	Action     string
+	Actor      string
+	Outcome    string
+	ApiTokenId *uint
+	From       uint64
+	Until      uint64
+}
+
+type ListAuditLogRequest struct {
+	AuditLogFilter
+	Limit  uint64
+	Offset uint64
+}
+
+type ListAuditLogResponse struct {
+	Entries    []AuditLogEntry `json:"entries"`
+	TotalCount int64           `json:"totalCount"`
+}
+
+// AuditLogEntry is one admin API request that changed the hub or revealed a
+// secret. Payload is the request body with sensitive fields redacted.
+type AuditLogEntry struct {
+	Id         uint      `json:"id"`
+	Action     string    `json:"action"`
+	Actor      string    `json:"actor"`
+	ApiTokenId *uint     `json:"apiTokenId"`
+	RemoteIp   string    `json:"remoteIp"`
+	RequestId  string    `json:"requestId"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Payload    string    `json:"payload"`
+	Outcome    string    `json:"outcome"`
+	StatusCode int       `json:"statusCode"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ListChannelAcceptDecisionsRequest optionally filters on whether channels
+// were accepted.
+type ListChannelAcceptDecisionsRequest struct {
+	Accepted *bool
+	Limit    uint64
+	Offset   uint64
+}
+
+type ListChannelAcceptDecisionsResponse struct {
+	Decisions  []persist.ChannelAcceptDecision `json:"decisions"`
+	TotalCount int64                           `json:"totalCount"`
+}
+
+// ListChannelFeeUpdatesRequest optionally filters on a single channel.
+type ListChannelFeeUpdatesRequest struct {
+	ChannelId string
+	Limit     uint64
+	Offset    uint64
+}
+
+type ChannelFeeUpdate struct {
+	Id                  uint      `json:"id"`
+	ChannelId           string    `json:"channelId"`
+	PeerPubkey          string    `json:"peerPubkey"`
+	OldBaseFeeMloki     uint32    `json:"oldBaseFeeMloki"`
+	NewBaseFeeMloki     uint32    `json:"newBaseFeeMloki"`
+	OldFeePpm           uint32    `json:"oldFeePpm"`
+	NewFeePpm           uint32    `json:"newFeePpm"`
+	LocalBalancePercent uint32    `json:"localBalancePercent"`
+	OutboundFlowMloki   uint64    `json:"outboundFlowMloki"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+type ListChannelFeeUpdatesResponse struct {
+	FeeUpdates []ChannelFeeUpdate `json:"feeUpdates"`
+	TotalCount uint64             `json:"totalCount"`
 }
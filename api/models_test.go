@@ -32,6 +32,7 @@ func fullInfoResponse() InfoResponse {
 		MessageboardNwcUrl:          "https://example.com/board",
 		EnableSwap:                  true,
 		EnableMessageboardNwc:       true,
+		EnableHttpWebhooks:          true,
 		WorkDir:                     "/home/user/.lokihub",
 		EnablePolling:               true,
 	}
@@ -75,6 +76,7 @@ func TestInfoResponse_RedactForUnauthenticated_SetupCompleted(t *testing.T) {
 	assert.Empty(t, info.MessageboardNwcUrl)
 	assert.False(t, info.EnableSwap)
 	assert.False(t, info.EnableMessageboardNwc)
+	assert.False(t, info.EnableHttpWebhooks)
 	assert.False(t, info.EnablePolling)
 }
 
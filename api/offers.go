@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+func (api *api) CreateOffer(ctx context.Context, req *MakeOfferRequest) (*Offer, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	offer, err := api.svc.GetTransactionsService().MakeOffer(ctx, req.Description, api.svc.GetLNClient(), req.AppId)
+	if err != nil {
+		return nil, err
+	}
+	return toApiOffer(offer), nil
+}
+
+func (api *api) LookupOffer(ctx context.Context, offer string) (*Offer, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	dbOffer, err := api.svc.GetTransactionsService().LookupOffer(ctx, offer, nil)
+	if err != nil {
+		return nil, err
+	}
+	return toApiOffer(dbOffer), nil
+}
+
+func (api *api) PayOffer(ctx context.Context, req *PayOfferRequest) (*SendPaymentResponse, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	if err := api.verifyPaymentTotp(ctx, offerAmountMloki(req.Offer, req.Amount)); err != nil {
+		return nil, err
+	}
+	if req.Metadata != nil {
+		delete(req.Metadata, "internal_transfer")
+		delete(req.Metadata, "jit_claim_slice")
+	}
+	transaction, err := api.svc.GetTransactionsService().PayOffer(ctx, req.Offer, req.Amount, req.PayerNote, req.Metadata, api.svc.GetLNClient(), req.AppId, nil)
+	if err != nil {
+		return nil, err
+	}
+	return toApiTransaction(transaction), nil
+}
+
+func toApiOffer(offer *transactions.Offer) *Offer {
+	return &Offer{
+		Offer:       offer.Offer,
+		Description: offer.Description,
+		AppId:       offer.AppId,
+		CreatedAt:   offer.CreatedAt.Format(time.RFC3339),
+	}
+}
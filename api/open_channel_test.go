@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/freeze"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func TestOpenChannel_InvalidParams(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	theAPI := newTestAPIWithService(t, svc)
+
+	feeRate := uint64(10)
+	confTarget := uint32(6)
+	invalid := []*OpenChannelRequest{
+		{AmountLoki: 1_000_000},
+		{Pubkey: "02aaaa"},
+		{Pubkey: "02aaaa", AmountLoki: 1_000_000, PushAmountLoki: 1_000_000},
+		{Pubkey: "02aaaa", AmountLoki: 1_000_000, Public: true, ScidAlias: true},
+		{Pubkey: "02aaaa", AmountLoki: 1_000_000, FeeRate: &feeRate, ConfTarget: &confTarget},
+	}
+	for _, req := range invalid {
+		_, err := theAPI.OpenChannel(context.Background(), req)
+		assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	}
+
+	_, err = theAPI.OpenChannel(context.Background(), &OpenChannelRequest{Pubkey: "02aaaa", AmountLoki: 1_000_000, FeeRate: &feeRate, PushAmountLoki: 1_000})
+	assert.NoError(t, err)
+
+	_, err = theAPI.freezeSvc.Freeze(true, freeze.SourceApi)
+	require.NoError(t, err)
+	_, err = theAPI.OpenChannel(context.Background(), &OpenChannelRequest{Pubkey: "02aaaa", AmountLoki: 1_000_000})
+	assert.ErrorIs(t, err, freeze.ErrOutgoingPaymentsPaused)
+}
+
+func TestBatchOpenChannel_InvalidParams(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	theAPI := newTestAPIWithService(t, svc)
+
+	feeRate := uint64(10)
+	confTarget := uint32(6)
+	channel := OpenChannelRequest{Pubkey: "02aaaa", AmountLoki: 1_000_000}
+	withFeeRate := channel
+	withFeeRate.FeeRate = &feeRate
+	withOutpoints := channel
+	withOutpoints.Outpoints = []string{"txid:0"}
+
+	invalid := []*BatchOpenChannelRequest{
+		{Channels: []OpenChannelRequest{channel}},
+		{Channels: []OpenChannelRequest{channel, {Pubkey: "02bbbb"}}},
+		{Channels: []OpenChannelRequest{channel, channel}, FeeRate: &feeRate, ConfTarget: &confTarget},
+		{Channels: []OpenChannelRequest{channel, withFeeRate}},
+		{Channels: []OpenChannelRequest{channel, withOutpoints}},
+	}
+	for _, req := range invalid {
+		_, err := theAPI.BatchOpenChannel(context.Background(), req)
+		assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	}
+
+	_, err = theAPI.BatchOpenChannel(context.Background(), &BatchOpenChannelRequest{Channels: []OpenChannelRequest{channel, channel}, ConfTarget: &confTarget})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/rebalance"
+)
+
+var autoRebalanceKeys = []string{
+	config.AutoRebalanceSourceMinLocalPercentKey,
+	config.AutoRebalanceTargetMaxLocalPercentKey,
+	config.AutoRebalanceAmountKey,
+	config.AutoRebalanceMaxFeePpmKey,
+}
+
+func (api *api) Rebalance(ctx context.Context, req *RebalanceRequest) (*Rebalance, error) {
+	if req.OutgoingChannelId == "" || req.IncomingChannelId == "" {
+		return nil, fmt.Errorf("%w: outgoing and incoming channels must be set", constants.ErrInvalidParams)
+	}
+	if req.AmountLoki == 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", constants.ErrInvalidParams)
+	}
+	if req.MaxFeePpm == 0 || req.MaxFeePpm > 1_000_000 {
+		return nil, fmt.Errorf("%w: max fee must be between 1 and 1000000 ppm", constants.ErrInvalidParams)
+	}
+	if api.svc.GetRebalanceService() == nil {
+		return nil, errors.New("RebalanceService not started")
+	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	if err := api.verifyPaymentTotp(ctx, lokiToMloki(req.AmountLoki)); err != nil {
+		return nil, err
+	}
+
+	dbRebalance, err := api.svc.GetRebalanceService().Rebalance(ctx, req.OutgoingChannelId, req.IncomingChannelId, req.AmountLoki*1000, req.MaxFeePpm)
+	if err != nil {
+		return nil, err
+	}
+	apiRebalance := toApiRebalance(dbRebalance)
+	return &apiRebalance, nil
+}
+
+func (api *api) ListRebalances(req *ListRebalancesRequest) (*ListRebalancesResponse, error) {
+	if api.svc.GetRebalanceService() == nil {
+		return nil, errors.New("RebalanceService not started")
+	}
+	dbRebalances, totalCount, err := api.svc.GetRebalanceService().ListRebalances(req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	rebalances := make([]Rebalance, 0, len(dbRebalances))
+	for i := range dbRebalances {
+		rebalances = append(rebalances, toApiRebalance(&dbRebalances[i]))
+	}
+	return &ListRebalancesResponse{
+		Rebalances: rebalances,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (api *api) GetAutoRebalanceConfig() (*GetAutoRebalanceConfigResponse, error) {
+	autoRebalanceConfig, err := rebalance.LoadAutoRebalanceConfig(api.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if autoRebalanceConfig == nil {
+		return &GetAutoRebalanceConfigResponse{}, nil
+	}
+	return &GetAutoRebalanceConfigResponse{
+		Enabled:               true,
+		SourceMinLocalPercent: autoRebalanceConfig.SourceMinLocalPercent,
+		TargetMaxLocalPercent: autoRebalanceConfig.TargetMaxLocalPercent,
+		AmountLoki:            autoRebalanceConfig.Amount,
+		MaxFeePpm:             autoRebalanceConfig.MaxFeePpm,
+	}, nil
+}
+
+func (api *api) EnableAutoRebalance(req *EnableAutoRebalanceRequest) error {
+	if req.SourceMinLocalPercent > 100 || req.TargetMaxLocalPercent >= req.SourceMinLocalPercent {
+		return fmt.Errorf("%w: the source threshold must be above the target threshold and at most 100%%", constants.ErrInvalidParams)
+	}
+	if req.AmountLoki == 0 {
+		return fmt.Errorf("%w: amount must be greater than zero", constants.ErrInvalidParams)
+	}
+	if req.MaxFeePpm == 0 || req.MaxFeePpm > 1_000_000 {
+		return fmt.Errorf("%w: max fee must be between 1 and 1000000 ppm", constants.ErrInvalidParams)
+	}
+
+	values := []uint64{req.SourceMinLocalPercent, req.TargetMaxLocalPercent, req.AmountLoki, req.MaxFeePpm}
+	for i, key := range autoRebalanceKeys {
+		if err := api.cfg.SetUpdate(key, strconv.FormatUint(values[i], 10), ""); err != nil {
+			logger.Logger.Error().Err(err).Str("key", key).Msg("Failed to save auto rebalance config")
+			return err
+		}
+	}
+
+	if api.svc.GetRebalanceService() == nil {
+		return errors.New("RebalanceService not started")
+	}
+	return api.svc.GetRebalanceService().EnableAutoRebalance()
+}
+
+func (api *api) DisableAutoRebalance() error {
+	for _, key := range autoRebalanceKeys {
+		if err := api.cfg.SetUpdate(key, "", ""); err != nil {
+			logger.Logger.Error().Err(err).Str("key", key).Msg("Failed to remove auto rebalance config")
+			return err
+		}
+	}
+
+	if api.svc.GetRebalanceService() != nil {
+		api.svc.GetRebalanceService().StopAutoRebalance()
+	}
+	return nil
+}
+
+func toApiRebalance(dbRebalance *db.Rebalance) Rebalance {
+	return Rebalance{
+		Id:                dbRebalance.ID,
+		OutgoingChannelId: dbRebalance.OutgoingChannelId,
+		IncomingChannelId: dbRebalance.IncomingChannelId,
+		LastHopPubkey:     dbRebalance.LastHopPubkey,
+		AmountMloki:       dbRebalance.AmountMloki,
+		FeeMloki:          dbRebalance.FeeMloki,
+		MaxFeePpm:         dbRebalance.MaxFeePpm,
+		PaymentHash:       dbRebalance.PaymentHash,
+		State:             dbRebalance.State,
+		FailureReason:     dbRebalance.FailureReason,
+		Auto:              dbRebalance.Auto,
+		CreatedAt:         dbRebalance.CreatedAt,
+	}
+}
@@ -8,15 +8,36 @@ import (
 
 	"github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/lnclient"
 	"github.com/flokiorg/lokihub/lsps/manager"
 	"github.com/flokiorg/lokihub/tests/mocks"
+	"github.com/flokiorg/lokihub/totp"
 	"github.com/flokiorg/lokihub/transactions"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+// stubFreezeService implements freeze.FreezeService for a hub that is not
+// frozen. Methods other than CheckOutgoingPayment panic if called.
+type stubFreezeService struct {
+	freeze.FreezeService
+}
+
+func (s *stubFreezeService) CheckOutgoingPayment() error {
+	return nil
+}
+
+// stubTotpService implements totp.TotpService for a hub without TOTP.
+type stubTotpService struct {
+	totp.TotpService
+}
+
+func (s *stubTotpService) RequiresCodeForPayment(_ uint64) (bool, error) {
+	return false, nil
+}
+
 // stubTransactionsService implements transactions.TransactionsService.
 // Only SendPaymentSync is testify-mocked; other methods panic if called.
 type stubTransactionsService struct {
@@ -53,6 +74,10 @@ func (s *stubTransactionsService) SendKeysend(_ uint64, _ string, _ []lnclient.T
 	panic("SendKeysend: unexpected call in test")
 }
 
+func (s *stubTransactionsService) CheckCanPay(_ uint64, _ string, _ string, _ lnclient.LNClient, _ *uint) error {
+	panic("CheckCanPay: unexpected call in test")
+}
+
 func (s *stubTransactionsService) MakeHoldInvoice(_ context.Context, _ uint64, _, _ string, _ uint64, _ string, _ map[string]interface{}, _ lnclient.LNClient, _ *uint, _ *uint) (*transactions.Transaction, error) {
 	panic("MakeHoldInvoice: unexpected call in test")
 }
@@ -78,6 +103,18 @@ func (s *stubTransactionsService) EstimateFee(_ string) (uint64, error) {
 	panic("EstimateFee: unexpected call in test")
 }
 
+func (s *stubTransactionsService) MakeOffer(_ context.Context, _ string, _ lnclient.LNClient, _ *uint) (*transactions.Offer, error) {
+	panic("MakeOffer: unexpected call in test")
+}
+
+func (s *stubTransactionsService) LookupOffer(_ context.Context, _ string, _ *uint) (*transactions.Offer, error) {
+	panic("LookupOffer: unexpected call in test")
+}
+
+func (s *stubTransactionsService) PayOffer(_ context.Context, _ string, _ uint64, _ string, _ map[string]interface{}, _ lnclient.LNClient, _ *uint, _ *uint) (*transactions.Transaction, error) {
+	panic("PayOffer: unexpected call in test")
+}
+
 func makeSettledTransaction(preimage string) *db.Transaction {
 	now := time.Now()
 	return &db.Transaction{
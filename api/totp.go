@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"math"
+
+	decodepay "github.com/flokiorg/lokihub/decodepay"
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+// unknownAmountMloki is used for payments whose amount cannot be known up
+// front, so they always require a TOTP code once TOTP is enabled
+const unknownAmountMloki = math.MaxUint64
+
+type totpCodeContextKey struct{}
+
+// WithTotpCode attaches the TOTP code sent with a request, which is checked
+// by every API method that moves funds out of the hub.
+func WithTotpCode(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, totpCodeContextKey{}, code)
+}
+
+func totpCodeFromContext(ctx context.Context) string {
+	code, _ := ctx.Value(totpCodeContextKey{}).(string)
+	return code
+}
+
+// verifyPaymentTotp checks the TOTP code attached to ctx if the amount is
+// above the configured threshold
+func (api *api) verifyPaymentTotp(ctx context.Context, amountMloki uint64) error {
+	required, err := api.totpSvc.RequiresCodeForPayment(amountMloki)
+	if err != nil || !required {
+		return err
+	}
+	return api.totpSvc.Verify(totpCodeFromContext(ctx), "")
+}
+
+// lokiToMloki converts an amount in loki, saturating instead of wrapping so
+// a huge amount cannot slip below the TOTP threshold
+func lokiToMloki(amountLoki uint64) uint64 {
+	if amountLoki > math.MaxUint64/1000 {
+		return unknownAmountMloki
+	}
+	return amountLoki * 1000
+}
+
+// invoiceAmountMloki returns the amount a bolt11 invoice will be paid for. An
+// invoice that cannot be decoded is treated as the largest possible amount;
+// paying it fails anyway.
+func invoiceAmountMloki(invoice string, amountMloki *uint64) uint64 {
+	if amountMloki != nil {
+		return *amountMloki
+	}
+	paymentRequest, err := decodepay.Decode(invoice)
+	if err != nil || paymentRequest.MSat < 0 {
+		return unknownAmountMloki
+	}
+	return uint64(paymentRequest.MSat)
+}
+
+// offerAmountMloki returns the amount a BOLT12 offer will be paid for, the
+// amount asked for by the offer unless the payer chose one
+func offerAmountMloki(offer string, amountMloki uint64) uint64 {
+	if amountMloki != 0 {
+		return amountMloki
+	}
+	offerAmount, err := transactions.OfferAmountMloki(offer)
+	if err != nil || offerAmount == 0 {
+		return unknownAmountMloki
+	}
+	return offerAmount
+}
+
+func (api *api) GetTotpStatus() (*TotpStatus, error) {
+	status, err := api.totpSvc.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	return &TotpStatus{
+		Enabled:               status.Enabled,
+		Pending:               status.Pending,
+		PaymentThresholdMloki: status.PaymentThresholdMloki,
+		RecoveryCodesLeft:     status.RecoveryCodesLeft,
+	}, nil
+}
+
+func (api *api) BeginTotpEnrollment(req *BeginTotpEnrollmentRequest) (*BeginTotpEnrollmentResponse, error) {
+	secret, otpauthUrl, err := api.totpSvc.BeginEnrollment(req.UnlockPassword)
+	if err != nil {
+		return nil, err
+	}
+	return &BeginTotpEnrollmentResponse{
+		Secret:     secret,
+		OtpauthUrl: otpauthUrl,
+	}, nil
+}
+
+func (api *api) ConfirmTotpEnrollment(req *TotpCodeRequest) (*TotpRecoveryCodesResponse, error) {
+	recoveryCodes, err := api.totpSvc.ConfirmEnrollment(req.TotpCode)
+	if err != nil {
+		return nil, err
+	}
+	return &TotpRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (api *api) DisableTotp(req *DisableTotpRequest) error {
+	return api.totpSvc.Disable(req.UnlockPassword, req.TotpCode)
+}
+
+func (api *api) RegenerateTotpRecoveryCodes(req *TotpCodeRequest) (*TotpRecoveryCodesResponse, error) {
+	recoveryCodes, err := api.totpSvc.RegenerateRecoveryCodes(req.TotpCode)
+	if err != nil {
+		return nil, err
+	}
+	return &TotpRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (api *api) UpdateTotp(req *UpdateTotpRequest) error {
+	return api.totpSvc.SetPaymentThreshold(req.PaymentThresholdMloki, req.TotpCode)
+}
+
+func (api *api) VerifyTotp(code string, unlockPassword string) error {
+	return api.totpSvc.Verify(code, unlockPassword)
+}
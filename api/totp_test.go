@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/tests"
+	"github.com/flokiorg/lokihub/totp"
+)
+
+func TestOpenChannel_RequiresTotp(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	require.NoError(t, svc.Cfg.SaveUnlockPasswordCheck("123"))
+	theAPI := newTestAPIWithService(t, svc)
+
+	secret, _, err := theAPI.totpSvc.BeginEnrollment("123")
+	require.NoError(t, err)
+	// codes are only accepted once, so each step below uses a later period
+	code, err := totp.GenerateCode(secret, time.Now().Add(-30*time.Second))
+	require.NoError(t, err)
+	_, err = theAPI.totpSvc.ConfirmEnrollment(code)
+	require.NoError(t, err)
+
+	threshold := uint64(500_000_000)
+	code, err = totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, theAPI.totpSvc.SetPaymentThreshold(&threshold, code))
+
+	// channels up to the threshold do not need a code
+	_, err = theAPI.OpenChannel(context.Background(), &OpenChannelRequest{Pubkey: "02aaaa", AmountLoki: 500_000})
+	assert.NoError(t, err)
+
+	request := &OpenChannelRequest{Pubkey: "02aaaa", AmountLoki: 1_000_000}
+	_, err = theAPI.OpenChannel(context.Background(), request)
+	assert.ErrorIs(t, err, totp.ErrTotpRequired)
+	_, err = theAPI.OpenChannel(WithTotpCode(context.Background(), "000000"), request)
+	assert.ErrorIs(t, err, totp.ErrInvalidTotpCode)
+
+	code, err = totp.GenerateCode(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+	_, err = theAPI.OpenChannel(WithTotpCode(context.Background(), code), request)
+	assert.NoError(t, err)
+}
@@ -85,6 +85,12 @@ func (api *api) SendPayment(ctx context.Context, invoice string, amountMloki *ui
 	if api.svc.GetLNClient() == nil {
 		return nil, errors.New("LNClient not started")
 	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	if err := api.verifyPaymentTotp(ctx, invoiceAmountMloki(invoice, amountMloki)); err != nil {
+		return nil, err
+	}
 	if metadata != nil {
 		delete(metadata, "internal_transfer")
 		delete(metadata, "jit_claim_slice")
@@ -179,6 +185,13 @@ func (api *api) Transfer(ctx context.Context, fromAppId *uint, toAppId *uint, am
 		}
 	}
 
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return err
+	}
+	if err := api.verifyPaymentTotp(ctx, amountMloki); err != nil {
+		return err
+	}
+
 	transaction, err := api.svc.GetTransactionsService().MakeInvoice(ctx, amountMloki, "transfer", "", 0, nil, api.svc.GetLNClient(), toAppId, nil, nil, nil, nil, nil, nil, &transactions.InternalMakeInvoiceMeta{InternalTransfer: true})
 
 	if err != nil {
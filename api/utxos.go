@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+func (api *api) ListUtxos(ctx context.Context) ([]Utxo, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	lnUtxos, err := api.svc.GetLNClient().ListUtxos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var utxoLabels []db.UtxoLabel
+	if err := api.db.Find(&utxoLabels).Error; err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(utxoLabels))
+	for _, utxoLabel := range utxoLabels {
+		labels[utxoLabel.Outpoint] = utxoLabel.Label
+	}
+
+	utxos := make([]Utxo, 0, len(lnUtxos))
+	for _, utxo := range lnUtxos {
+		utxos = append(utxos, Utxo{
+			Outpoint:      utxo.Outpoint,
+			Address:       utxo.Address,
+			AmountLoki:    utxo.AmountLoki,
+			Confirmations: utxo.Confirmations,
+			Frozen:        utxo.Frozen,
+			Label:         labels[utxo.Outpoint],
+		})
+	}
+	return utxos, nil
+}
+
+func (api *api) UpdateUtxo(ctx context.Context, updateUtxoRequest *UpdateUtxoRequest) error {
+	if err := validateOutpoints([]string{updateUtxoRequest.Outpoint}); err != nil {
+		return err
+	}
+	if updateUtxoRequest.Label == nil && updateUtxoRequest.Frozen == nil {
+		return fmt.Errorf("%w: nothing to update", constants.ErrInvalidParams)
+	}
+
+	if updateUtxoRequest.Frozen != nil {
+		if api.svc.GetLNClient() == nil {
+			return errors.New("LNClient not started")
+		}
+		var err error
+		if *updateUtxoRequest.Frozen {
+			err = api.svc.GetLNClient().FreezeUtxo(ctx, updateUtxoRequest.Outpoint)
+		} else {
+			err = api.svc.GetLNClient().UnfreezeUtxo(ctx, updateUtxoRequest.Outpoint)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if updateUtxoRequest.Label != nil {
+		label := strings.TrimSpace(*updateUtxoRequest.Label)
+		var err error
+		if label == "" {
+			err = api.db.Where("outpoint = ?", updateUtxoRequest.Outpoint).Delete(&db.UtxoLabel{}).Error
+		} else {
+			err = api.db.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "outpoint"}},
+				DoUpdates: clause.AssignmentColumns([]string{"label", "updated_at"}),
+			}).Create(&db.UtxoLabel{Outpoint: updateUtxoRequest.Outpoint, Label: label}).Error
+		}
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("outpoint", updateUtxoRequest.Outpoint).Msg("Failed to save UTXO label")
+			return err
+		}
+	}
+	return nil
+}
+
+func (api *api) CreatePsbt(ctx context.Context, createPsbtRequest *CreatePsbtRequest) (*CreatePsbtResponse, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	if len(createPsbtRequest.Outputs) == 0 {
+		return nil, fmt.Errorf("%w: at least one output is required", constants.ErrInvalidParams)
+	}
+	for _, output := range createPsbtRequest.Outputs {
+		if output.Address == "" || output.AmountLoki == 0 {
+			return nil, fmt.Errorf("%w: outputs need an address and an amount", constants.ErrInvalidParams)
+		}
+	}
+	if createPsbtRequest.FeeRate != nil && createPsbtRequest.ConfTarget != nil {
+		return nil, fmt.Errorf("%w: set either a fee rate or a confirmation target", constants.ErrInvalidParams)
+	}
+	if err := validateOutpoints(createPsbtRequest.Outpoints); err != nil {
+		return nil, err
+	}
+	var amountLoki uint64
+	for _, output := range createPsbtRequest.Outputs {
+		amountLoki += output.AmountLoki
+	}
+	if err := api.verifyPaymentTotp(ctx, lokiToMloki(amountLoki)); err != nil {
+		return nil, err
+	}
+	return api.svc.GetLNClient().CreatePsbt(ctx, createPsbtRequest)
+}
+
+func (api *api) FinalizePsbt(ctx context.Context, finalizePsbtRequest *FinalizePsbtRequest) (*FinalizePsbtResponse, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	if finalizePsbtRequest.Psbt == "" {
+		return nil, fmt.Errorf("%w: psbt must be set", constants.ErrInvalidParams)
+	}
+	// signing can spend any input of the PSBT, not only the outputs it was
+	// created for
+	if err := api.verifyPaymentTotp(ctx, unknownAmountMloki); err != nil {
+		return nil, err
+	}
+	return api.svc.GetLNClient().FinalizePsbt(ctx, finalizePsbtRequest.Psbt)
+}
+
+func (api *api) PublishTransaction(ctx context.Context, publishTransactionRequest *PublishTransactionRequest) (*PublishTransactionResponse, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	if _, err := hex.DecodeString(publishTransactionRequest.RawTx); err != nil || publishTransactionRequest.RawTx == "" {
+		return nil, fmt.Errorf("%w: rawTx must be a hex encoded transaction", constants.ErrInvalidParams)
+	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	if err := api.verifyPaymentTotp(ctx, unknownAmountMloki); err != nil {
+		return nil, err
+	}
+	txId, err := api.svc.GetLNClient().PublishTransaction(ctx, publishTransactionRequest.RawTx, publishTransactionRequest.Label)
+	if err != nil {
+		return nil, err
+	}
+	return &PublishTransactionResponse{TxId: txId}, nil
+}
+
+func (api *api) BumpFee(ctx context.Context, bumpFeeRequest *BumpFeeRequest) (*BumpFeeResponse, error) {
+	if api.svc.GetLNClient() == nil {
+		return nil, errors.New("LNClient not started")
+	}
+	if (bumpFeeRequest.TxId == "") == (bumpFeeRequest.Outpoint == "") {
+		return nil, fmt.Errorf("%w: set either a txid or an outpoint", constants.ErrInvalidParams)
+	}
+	if bumpFeeRequest.TxId != "" {
+		if txIdBytes, err := hex.DecodeString(bumpFeeRequest.TxId); err != nil || len(txIdBytes) != 32 {
+			return nil, fmt.Errorf("%w: invalid txid %q", constants.ErrInvalidParams, bumpFeeRequest.TxId)
+		}
+	}
+	if bumpFeeRequest.Outpoint != "" {
+		if err := validateOutpoints([]string{bumpFeeRequest.Outpoint}); err != nil {
+			return nil, err
+		}
+	}
+	if bumpFeeRequest.FeeRate == 0 {
+		return nil, fmt.Errorf("%w: fee rate must be greater than zero", constants.ErrInvalidParams)
+	}
+	if err := api.freezeSvc.CheckOutgoingPayment(); err != nil {
+		return nil, err
+	}
+	return api.svc.GetLNClient().BumpFee(ctx, bumpFeeRequest)
+}
+
+// validateOutpoints checks that every outpoint is a "txid:vout" reference
+func validateOutpoints(outpoints []string) error {
+	for _, outpoint := range outpoints {
+		txid, vout, found := strings.Cut(outpoint, ":")
+		if txidBytes, err := hex.DecodeString(txid); !found || err != nil || len(txidBytes) != 32 {
+			return fmt.Errorf("%w: invalid outpoint %q", constants.ErrInvalidParams, outpoint)
+		}
+		if _, err := strconv.ParseUint(vout, 10, 32); err != nil {
+			return fmt.Errorf("%w: invalid outpoint %q", constants.ErrInvalidParams, outpoint)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+const (
+	testUtxo       = "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16:0"
+	testFrozenUtxo = "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16:1"
+)
+
+// utxosLn serves a fixed UTXO list and records freezes
+type utxosLn struct {
+	*tests.MockLn
+	frozen map[string]bool
+}
+
+func (ln *utxosLn) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	return []lnclient.Utxo{
+		{Outpoint: testUtxo, Address: "fc1qfirst", AmountLoki: 10_000, Confirmations: 3},
+		{Outpoint: testFrozenUtxo, AmountLoki: 20_000, Frozen: true},
+	}, nil
+}
+
+func (ln *utxosLn) FreezeUtxo(ctx context.Context, outpoint string) error {
+	ln.frozen[outpoint] = true
+	return nil
+}
+
+func (ln *utxosLn) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	ln.frozen[outpoint] = false
+	return nil
+}
+
+func newTestUtxosAPI(t *testing.T) (*api, *utxosLn) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	t.Cleanup(svc.Remove)
+	ln := &utxosLn{MockLn: svc.LNClient.(*tests.MockLn), frozen: map[string]bool{}}
+	svc.LNClient = ln
+	return newTestAPIWithService(t, svc), ln
+}
+
+func TestUpdateUtxo_Label(t *testing.T) {
+	theAPI, _ := newTestUtxosAPI(t)
+	ctx := context.Background()
+
+	label := " cold storage "
+	require.NoError(t, theAPI.UpdateUtxo(ctx, &UpdateUtxoRequest{Outpoint: testUtxo, Label: &label}))
+	label = "exchange withdrawal"
+	require.NoError(t, theAPI.UpdateUtxo(ctx, &UpdateUtxoRequest{Outpoint: testUtxo, Label: &label}))
+
+	utxos, err := theAPI.ListUtxos(ctx)
+	require.NoError(t, err)
+	require.Len(t, utxos, 2)
+	assert.Equal(t, "exchange withdrawal", utxos[0].Label)
+	assert.Equal(t, int64(3), utxos[0].Confirmations)
+	assert.Empty(t, utxos[1].Label)
+	assert.True(t, utxos[1].Frozen)
+
+	label = ""
+	require.NoError(t, theAPI.UpdateUtxo(ctx, &UpdateUtxoRequest{Outpoint: testUtxo, Label: &label}))
+	utxos, err = theAPI.ListUtxos(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, utxos[0].Label)
+}
+
+func TestUpdateUtxo_Freeze(t *testing.T) {
+	theAPI, ln := newTestUtxosAPI(t)
+	ctx := context.Background()
+
+	frozen := true
+	require.NoError(t, theAPI.UpdateUtxo(ctx, &UpdateUtxoRequest{Outpoint: testUtxo, Frozen: &frozen}))
+	assert.True(t, ln.frozen[testUtxo])
+
+	frozen = false
+	require.NoError(t, theAPI.UpdateUtxo(ctx, &UpdateUtxoRequest{Outpoint: testFrozenUtxo, Frozen: &frozen}))
+	assert.False(t, ln.frozen[testFrozenUtxo])
+}
+
+func TestUpdateUtxo_InvalidParams(t *testing.T) {
+	theAPI, _ := newTestUtxosAPI(t)
+	label := "label"
+
+	invalid := []*UpdateUtxoRequest{
+		{Outpoint: testUtxo},
+		{Outpoint: "f4184fc5:0", Label: &label},
+		{Outpoint: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", Label: &label},
+		{Outpoint: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16:x", Label: &label},
+	}
+	for _, req := range invalid {
+		assert.ErrorIs(t, theAPI.UpdateUtxo(context.Background(), req), constants.ErrInvalidParams)
+	}
+}
+
+func TestCreatePsbt_InvalidParams(t *testing.T) {
+	theAPI, _ := newTestUtxosAPI(t)
+	feeRate := uint64(5)
+	confTarget := uint32(6)
+	outputs := []lnclient.PsbtOutput{{Address: "fc1qfirst", AmountLoki: 10_000}}
+
+	invalid := []*CreatePsbtRequest{
+		{},
+		{Outputs: []lnclient.PsbtOutput{{Address: "fc1qfirst"}}},
+		{Outputs: outputs, FeeRate: &feeRate, ConfTarget: &confTarget},
+		{Outputs: outputs, Outpoints: []string{"not an outpoint"}},
+	}
+	for _, req := range invalid {
+		_, err := theAPI.CreatePsbt(context.Background(), req)
+		assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	}
+
+	_, err := theAPI.PublishTransaction(context.Background(), &PublishTransactionRequest{RawTx: "not hex"})
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+}
+
+func TestBumpFee_InvalidParams(t *testing.T) {
+	theAPI, _ := newTestUtxosAPI(t)
+	txId := "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"
+
+	invalid := []*BumpFeeRequest{
+		{FeeRate: 10},
+		{TxId: txId, Outpoint: testUtxo, FeeRate: 10},
+		{TxId: "abcd", FeeRate: 10},
+		{Outpoint: txId, FeeRate: 10},
+		{TxId: txId},
+	}
+	for _, req := range invalid {
+		_, err := theAPI.BumpFee(context.Background(), req)
+		assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	}
+
+	_, err := theAPI.BumpFee(context.Background(), &BumpFeeRequest{Outpoint: testUtxo, FeeRate: 10})
+	assert.NoError(t, err)
+}
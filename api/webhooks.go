@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+)
+
+func (api *api) ListWebhookEndpoints() ([]WebhookEndpoint, error) {
+	dbEndpoints, err := api.webhooksSvc.ListEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []WebhookEndpoint{}
+	for i := range dbEndpoints {
+		endpoints = append(endpoints, toApiWebhookEndpoint(&dbEndpoints[i]))
+	}
+	return endpoints, nil
+}
+
+func (api *api) CreateWebhookEndpoint(req *CreateWebhookEndpointRequest) (*CreateWebhookEndpointResponse, error) {
+	dbEndpoint, err := api.webhooksSvc.CreateEndpoint(req.Url, req.Events, req.Enabled)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateWebhookEndpointResponse{
+		WebhookEndpoint: toApiWebhookEndpoint(dbEndpoint),
+		Secret:          dbEndpoint.Secret,
+	}, nil
+}
+
+func (api *api) UpdateWebhookEndpoint(id uint, req *UpdateWebhookEndpointRequest) (*WebhookEndpoint, error) {
+	dbEndpoint, err := api.webhooksSvc.UpdateEndpoint(id, req.Url, req.Events, req.Enabled)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := toApiWebhookEndpoint(dbEndpoint)
+	return &endpoint, nil
+}
+
+func (api *api) DeleteWebhookEndpoint(id uint) error {
+	return api.webhooksSvc.DeleteEndpoint(id)
+}
+
+func (api *api) ListWebhookDeliveries(req *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	if req.State != "" && !slices.Contains([]string{
+		db.WebhookDeliveryStatePending,
+		db.WebhookDeliveryStateDelivered,
+		db.WebhookDeliveryStateFailed,
+	}, req.State) {
+		return nil, fmt.Errorf("%w: unknown delivery state %q", constants.ErrInvalidParams, req.State)
+	}
+
+	dbDeliveries, totalCount, err := api.webhooksSvc.ListDeliveries(req.EndpointId, req.State, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []WebhookDelivery{}
+	for i := range dbDeliveries {
+		deliveries = append(deliveries, toApiWebhookDelivery(&dbDeliveries[i]))
+	}
+	return &ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (api *api) RedeliverWebhookDelivery(id uint) (*WebhookDelivery, error) {
+	dbDelivery, err := api.webhooksSvc.RedeliverDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	delivery := toApiWebhookDelivery(dbDelivery)
+	return &delivery, nil
+}
+
+func toApiWebhookEndpoint(endpoint *db.WebhookEndpoint) WebhookEndpoint {
+	events := []string{}
+	events = append(events, endpoint.Events...)
+	return WebhookEndpoint{
+		Id:        endpoint.ID,
+		Url:       endpoint.Url,
+		Events:    events,
+		Enabled:   endpoint.Enabled,
+		CreatedAt: endpoint.CreatedAt,
+		UpdatedAt: endpoint.UpdatedAt,
+	}
+}
+
+func toApiWebhookDelivery(delivery *db.WebhookDelivery) WebhookDelivery {
+	var nextAttemptAt *time.Time
+	if delivery.State == db.WebhookDeliveryStatePending {
+		nextAttemptAt = &delivery.NextAttemptAt
+	}
+	return WebhookDelivery{
+		Id:             delivery.ID,
+		EndpointId:     delivery.EndpointId,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		State:          delivery.State,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
@@ -0,0 +1,213 @@
+// Package apitokens manages named, scoped admin API tokens. Unlike the JWTs
+// issued on unlock, which are all signed with the same secret, every token is
+// stored (as a hash) and can be revoked on its own.
+package apitokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+// TokenPrefix starts every API token, which tells them apart from JWTs.
+const TokenPrefix = "lkh_"
+
+// Scopes group the admin API routes. A token may call a route if it holds the
+// route's scope.
+const (
+	ScopeRead           = "read"
+	ScopeAppsWrite      = "apps:write"
+	ScopePaymentsSend   = "payments:send"
+	ScopeInvoicesWrite  = "invoices:write"
+	ScopeChannelsManage = "channels:manage"
+	ScopeSwaps          = "swaps"
+	ScopeSettings       = "settings"
+	// ScopeAdmin covers secrets and credentials (the mnemonic, the unlock
+	// password, API tokens themselves). It is only held by full access
+	// sessions and cannot be granted to a token.
+	ScopeAdmin = "admin"
+)
+
+// lastUsedResolution limits how often using a token writes to the database
+const lastUsedResolution = time.Minute
+
+var (
+	ErrInvalidToken  = errors.New("invalid or expired API token")
+	ErrTokenNotFound = errors.New("API token not found")
+)
+
+// GrantableScopes returns the scopes a token can be created with.
+func GrantableScopes() []string {
+	return []string{
+		ScopeRead,
+		ScopeAppsWrite,
+		ScopePaymentsSend,
+		ScopeInvoicesWrite,
+		ScopeChannelsManage,
+		ScopeSwaps,
+		ScopeSettings,
+	}
+}
+
+// AppScopes returns the scopes a token restricted to a single app can be
+// created with. The others act on the node or hub as a whole.
+func AppScopes() []string {
+	return []string{
+		ScopeRead,
+		ScopeAppsWrite,
+		ScopePaymentsSend,
+		ScopeInvoicesWrite,
+	}
+}
+
+type ApiTokensService interface {
+	// CreateToken stores a new token and returns it together with the token
+	// itself, which is not retrievable afterwards.
+	CreateToken(name string, scopes []string, appId *uint, expiresAt *time.Time) (*db.ApiToken, string, error)
+	// ListTokens returns every token, newest first, with its App loaded.
+	ListTokens() ([]db.ApiToken, error)
+	RevokeToken(id uint) error
+	// Authenticate returns the stored token, with its App loaded, and records
+	// its use. ErrInvalidToken is returned for unknown and expired tokens.
+	Authenticate(token string) (*db.ApiToken, error)
+}
+
+type apiTokensService struct {
+	db *gorm.DB
+}
+
+func NewApiTokensService(db *gorm.DB) *apiTokensService {
+	return &apiTokensService{
+		db: db,
+	}
+}
+
+func (svc *apiTokensService) CreateToken(name string, scopes []string, appId *uint, expiresAt *time.Time) (*db.ApiToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name must be set", constants.ErrInvalidParams)
+	}
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", constants.ErrInvalidParams)
+	}
+	allowedScopes := GrantableScopes()
+	if appId != nil {
+		allowedScopes = AppScopes()
+	}
+	uniqueScopes := []string{}
+	for _, scope := range scopes {
+		if !slices.Contains(allowedScopes, scope) {
+			return nil, "", fmt.Errorf("%w: scope %q cannot be granted to this token", constants.ErrInvalidParams, scope)
+		}
+		if !slices.Contains(uniqueScopes, scope) {
+			uniqueScopes = append(uniqueScopes, scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiry must be in the future", constants.ErrInvalidParams)
+	}
+	if appId != nil {
+		var count int64
+		if err := svc.db.Model(&db.App{}).Where("id = ?", *appId).Count(&count).Error; err != nil {
+			return nil, "", err
+		}
+		if count == 0 {
+			return nil, "", fmt.Errorf("%w: app %d does not exist", constants.ErrInvalidParams, *appId)
+		}
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	apiToken := db.ApiToken{
+		Name:      name,
+		TokenHash: hashToken(token),
+		Scopes:    uniqueScopes,
+		AppId:     appId,
+		ExpiresAt: expiresAt,
+	}
+	if err := svc.db.Create(&apiToken).Error; err != nil {
+		return nil, "", err
+	}
+	logger.Logger.Info().
+		Uint("api_token_id", apiToken.ID).
+		Str("name", apiToken.Name).
+		Strs("scopes", uniqueScopes).
+		Msg("Created API token")
+	return &apiToken, token, nil
+}
+
+func (svc *apiTokensService) ListTokens() ([]db.ApiToken, error) {
+	apiTokens := []db.ApiToken{}
+	if err := svc.db.Preload("App").Order("id DESC").Find(&apiTokens).Error; err != nil {
+		return nil, err
+	}
+	return apiTokens, nil
+}
+
+func (svc *apiTokensService) RevokeToken(id uint) error {
+	result := svc.db.Delete(&db.ApiToken{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+	logger.Logger.Info().Uint("api_token_id", id).Msg("Revoked API token")
+	return nil
+}
+
+func (svc *apiTokensService) Authenticate(token string) (*db.ApiToken, error) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+	var apiToken db.ApiToken
+	err := svc.db.Preload("App").Where("token_hash = ?", hashToken(token)).First(&apiToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if apiToken.ExpiresAt != nil && !apiToken.ExpiresAt.After(now) {
+		return nil, ErrInvalidToken
+	}
+
+	if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) >= lastUsedResolution {
+		if err := svc.db.Model(&apiToken).Update("last_used_at", now).Error; err != nil {
+			logger.Logger.Error().Err(err).Uint("api_token_id", apiToken.ID).Msg("Failed to update API token last used time")
+		}
+	}
+	return &apiToken, nil
+}
+
+// HasScope returns whether the token may call routes of the given scope.
+func HasScope(apiToken *db.ApiToken, scope string) bool {
+	return slices.Contains(apiToken.Scopes, scope)
+}
+
+func generateToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return TokenPrefix + hex.EncodeToString(bytes), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,127 @@
+package apitokens
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func TestCreateToken_Authenticate(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	apiTokensSvc := NewApiTokensService(svc.DB)
+	apiToken, token, err := apiTokensSvc.CreateToken(" ci ", []string{ScopeRead, ScopeSwaps, ScopeRead}, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, TokenPrefix))
+	assert.Equal(t, "ci", apiToken.Name)
+	assert.Equal(t, []string{ScopeRead, ScopeSwaps}, []string(apiToken.Scopes))
+	assert.NotContains(t, apiToken.TokenHash, token)
+
+	authenticated, err := apiTokensSvc.Authenticate(token)
+	require.NoError(t, err)
+	assert.Equal(t, apiToken.ID, authenticated.ID)
+	assert.True(t, HasScope(authenticated, ScopeSwaps))
+	assert.False(t, HasScope(authenticated, ScopePaymentsSend))
+
+	var stored db.ApiToken
+	require.NoError(t, svc.DB.First(&stored, apiToken.ID).Error)
+	assert.NotNil(t, stored.LastUsedAt)
+
+	_, err = apiTokensSvc.Authenticate(token + "0")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = apiTokensSvc.Authenticate("not a token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthenticate_Expired(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	apiTokensSvc := NewApiTokensService(svc.DB)
+	expiresAt := time.Now().Add(time.Hour)
+	apiToken, token, err := apiTokensSvc.CreateToken("expiring", []string{ScopeRead}, nil, &expiresAt)
+	require.NoError(t, err)
+	_, err = apiTokensSvc.Authenticate(token)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DB.Model(apiToken).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	_, err = apiTokensSvc.Authenticate(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestRevokeToken(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	apiTokensSvc := NewApiTokensService(svc.DB)
+	apiToken, token, err := apiTokensSvc.CreateToken("leaked", []string{ScopePaymentsSend}, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, apiTokensSvc.RevokeToken(apiToken.ID))
+	_, err = apiTokensSvc.Authenticate(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.ErrorIs(t, apiTokensSvc.RevokeToken(apiToken.ID), ErrTokenNotFound)
+
+	apiTokens, err := apiTokensSvc.ListTokens()
+	require.NoError(t, err)
+	assert.Empty(t, apiTokens)
+}
+
+func TestCreateToken_AppRestriction(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	apiTokensSvc := NewApiTokensService(svc.DB)
+	_, token, err := apiTokensSvc.CreateToken("app", []string{ScopePaymentsSend}, &app.ID, nil)
+	require.NoError(t, err)
+	authenticated, err := apiTokensSvc.Authenticate(token)
+	require.NoError(t, err)
+	require.NotNil(t, authenticated.App)
+	assert.Equal(t, app.AppPubkey, authenticated.App.AppPubkey)
+
+	_, _, err = apiTokensSvc.CreateToken("app", []string{ScopeChannelsManage}, &app.ID, nil)
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+
+	unknownAppId := app.ID + 100
+	_, _, err = apiTokensSvc.CreateToken("app", []string{ScopeRead}, &unknownAppId, nil)
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+}
+
+func TestCreateToken_InvalidParams(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	apiTokensSvc := NewApiTokensService(svc.DB)
+	expired := time.Now().Add(-time.Hour)
+	invalid := []struct {
+		name      string
+		scopes    []string
+		expiresAt *time.Time
+	}{
+		{"", []string{ScopeRead}, nil},
+		{"no scopes", nil, nil},
+		{"unknown scope", []string{"everything"}, nil},
+		{"admin", []string{ScopeAdmin}, nil},
+		{"expired", []string{ScopeRead}, &expired},
+	}
+	for _, req := range invalid {
+		_, _, err := apiTokensSvc.CreateToken(req.name, req.scopes, nil, req.expiresAt)
+		assert.ErrorIs(t, err, constants.ErrInvalidParams, req.name)
+	}
+}
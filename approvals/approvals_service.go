@@ -0,0 +1,317 @@
+// Package approvals holds NIP-47 payments above their app's approval
+// threshold until the wallet owner approves or rejects them. The NIP-47
+// request waits for the decision, which reaches it over the events bus, so
+// the deciding side (HTTP API or Wails) needs no handle on the waiting one.
+package approvals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+// DefaultTimeout is how long a payment waits for a decision before it is
+// expired and the NIP-47 request fails.
+const DefaultTimeout = 5 * time.Minute
+
+const (
+	// EventApprovalRequested is published when a payment is held for approval.
+	EventApprovalRequested = "nwc_payment_approval_requested"
+	// EventApprovalDecided is published when a held payment is approved,
+	// rejected or expired.
+	EventApprovalDecided = "nwc_payment_approval_decided"
+)
+
+var (
+	ErrPaymentRejected    = errors.New("the payment was rejected by the wallet owner")
+	ErrApprovalTimeout    = errors.New("the payment was not approved by the wallet owner in time")
+	ErrApprovalNotFound   = errors.New("payment approval not found")
+	ErrApprovalNotPending = fmt.Errorf("%w: payment approval is no longer pending", constants.ErrInvalidParams)
+)
+
+// Payment describes an outgoing NIP-47 payment that may need approval.
+type Payment struct {
+	AppId          uint
+	RequestEventId *uint
+	Method         string
+	AmountMloki    uint64
+	Destination    string
+	PaymentRequest string
+	Description    string
+}
+
+type ApprovalsService interface {
+	// AwaitApproval returns nil straight away for payments within their app's
+	// approval threshold. Larger payments are held as a db.PaymentApproval
+	// and AwaitApproval blocks until the owner decides: nil if approved,
+	// ErrPaymentRejected or ErrApprovalTimeout otherwise.
+	AwaitApproval(ctx context.Context, payment Payment) error
+	// ListApprovals returns approvals in the given state (all if empty),
+	// newest first, with their App loaded.
+	ListApprovals(state string) ([]db.PaymentApproval, error)
+	// DecideApproval approves or rejects a pending approval.
+	DecideApproval(id uint, approve bool) (*db.PaymentApproval, error)
+	// ExpirePendingApprovals expires every pending approval. Nothing waits
+	// for them after a restart, so it is called on startup.
+	ExpirePendingApprovals() error
+	// RejectPendingApprovals rejects every pending approval, failing the
+	// payments waiting for them. It is called when the hub is frozen.
+	RejectPendingApprovals() error
+}
+
+type approvalsService struct {
+	db             *gorm.DB
+	eventPublisher events.EventPublisher
+	timeout        time.Duration
+}
+
+func NewApprovalsService(db *gorm.DB, eventPublisher events.EventPublisher) *approvalsService {
+	return &approvalsService{
+		db:             db,
+		eventPublisher: eventPublisher,
+		timeout:        DefaultTimeout,
+	}
+}
+
+func (svc *approvalsService) AwaitApproval(ctx context.Context, payment Payment) error {
+	var thresholdLoki int
+	err := svc.db.Model(&db.AppSpendingPolicy{}).
+		Select("approval_threshold_loki").
+		Where("app_id = ?", payment.AppId).
+		Scan(&thresholdLoki).Error
+	if err != nil {
+		return err
+	}
+	if thresholdLoki <= 0 || payment.AmountMloki <= uint64(thresholdLoki)*1000 { //nolint:gosec // thresholdLoki > 0 is checked above
+		return nil
+	}
+
+	approval := db.PaymentApproval{
+		AppId:          payment.AppId,
+		RequestEventId: payment.RequestEventId,
+		Method:         payment.Method,
+		AmountMloki:    payment.AmountMloki,
+		Destination:    payment.Destination,
+		PaymentRequest: payment.PaymentRequest,
+		Description:    payment.Description,
+		State:          db.PaymentApprovalStatePending,
+		ExpiresAt:      time.Now().Add(svc.timeout),
+	}
+	if err := svc.db.Create(&approval).Error; err != nil {
+		return err
+	}
+
+	// subscribe before announcing the approval so no decision can be missed
+	subscriber := newDecisionSubscriber(approval.ID)
+	svc.eventPublisher.RegisterSubscriber(subscriber)
+	defer svc.eventPublisher.RemoveSubscriber(subscriber)
+
+	svc.eventPublisher.Publish(&events.Event{
+		Event: EventApprovalRequested,
+		Properties: map[string]interface{}{
+			"id":          approval.ID,
+			"app_id":      approval.AppId,
+			"method":      approval.Method,
+			"amount":      approval.AmountMloki,
+			"description": approval.Description,
+			"expires_at":  approval.ExpiresAt,
+		},
+	})
+
+	logger.Logger.Info().
+		Uint("approval_id", approval.ID).
+		Uint("app_id", approval.AppId).
+		Uint64("amount_mloki", approval.AmountMloki).
+		Msg("Payment is waiting for approval")
+
+	timer := time.NewTimer(time.Until(approval.ExpiresAt))
+	defer timer.Stop()
+
+	var state string
+	select {
+	case <-subscriber.decided:
+		state, err = svc.getState(approval.ID)
+	case <-timer.C:
+		state, err = svc.expire(approval.ID)
+	case <-ctx.Done():
+		state, err = svc.expire(approval.ID)
+		if err == nil && state == db.PaymentApprovalStateExpired {
+			return ctx.Err()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch state {
+	case db.PaymentApprovalStateApproved:
+		return nil
+	case db.PaymentApprovalStateRejected:
+		return ErrPaymentRejected
+	default:
+		return ErrApprovalTimeout
+	}
+}
+
+func (svc *approvalsService) ListApprovals(state string) ([]db.PaymentApproval, error) {
+	query := svc.db.Preload("App").Order("id DESC")
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+	approvals := []db.PaymentApproval{}
+	if err := query.Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+func (svc *approvalsService) DecideApproval(id uint, approve bool) (*db.PaymentApproval, error) {
+	state := db.PaymentApprovalStateRejected
+	if approve {
+		state = db.PaymentApprovalStateApproved
+	}
+
+	now := time.Now()
+	result := svc.db.Model(&db.PaymentApproval{}).
+		Where("id = ? AND state = ? AND expires_at > ?", id, db.PaymentApprovalStatePending, now).
+		Updates(map[string]interface{}{
+			"state":      state,
+			"decided_at": now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	approval := db.PaymentApproval{}
+	if err := svc.db.Preload("App").First(&approval, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrApprovalNotPending
+	}
+
+	svc.publishDecided(&approval)
+	return &approval, nil
+}
+
+func (svc *approvalsService) ExpirePendingApprovals() error {
+	return svc.db.Model(&db.PaymentApproval{}).
+		Where("state = ?", db.PaymentApprovalStatePending).
+		Updates(map[string]interface{}{
+			"state":      db.PaymentApprovalStateExpired,
+			"decided_at": time.Now(),
+		}).Error
+}
+
+func (svc *approvalsService) RejectPendingApprovals() error {
+	pending := []db.PaymentApproval{}
+	if err := svc.db.Where("state = ?", db.PaymentApprovalStatePending).Find(&pending).Error; err != nil {
+		return err
+	}
+	for i := range pending {
+		approval := &pending[i]
+		now := time.Now()
+		result := svc.db.Model(&db.PaymentApproval{}).
+			Where("id = ? AND state = ?", approval.ID, db.PaymentApprovalStatePending).
+			Updates(map[string]interface{}{
+				"state":      db.PaymentApprovalStateRejected,
+				"decided_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		// decided in the meantime
+		if result.RowsAffected == 0 {
+			continue
+		}
+		approval.State = db.PaymentApprovalStateRejected
+		approval.DecidedAt = &now
+		svc.publishDecided(approval)
+	}
+	if len(pending) > 0 {
+		logger.Logger.Info().Int("count", len(pending)).Msg("Rejected pending payment approvals")
+	}
+	return nil
+}
+
+// expire marks a still-pending approval as expired and returns its final
+// state, which differs if it was decided in the meantime.
+func (svc *approvalsService) expire(id uint) (string, error) {
+	result := svc.db.Model(&db.PaymentApproval{}).
+		Where("id = ? AND state = ?", id, db.PaymentApprovalStatePending).
+		Updates(map[string]interface{}{
+			"state":      db.PaymentApprovalStateExpired,
+			"decided_at": time.Now(),
+		})
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return svc.getState(id)
+	}
+
+	approval := db.PaymentApproval{}
+	if err := svc.db.First(&approval, id).Error; err == nil {
+		svc.publishDecided(&approval)
+	}
+	return db.PaymentApprovalStateExpired, nil
+}
+
+func (svc *approvalsService) getState(id uint) (string, error) {
+	approval := db.PaymentApproval{}
+	if err := svc.db.Select("state").First(&approval, id).Error; err != nil {
+		return "", err
+	}
+	return approval.State, nil
+}
+
+func (svc *approvalsService) publishDecided(approval *db.PaymentApproval) {
+	svc.eventPublisher.Publish(&events.Event{
+		Event: EventApprovalDecided,
+		Properties: map[string]interface{}{
+			"id":     approval.ID,
+			"app_id": approval.AppId,
+			"state":  approval.State,
+		},
+	})
+}
+
+// decisionSubscriber closes decided once the approval it waits for is
+// decided.
+type decisionSubscriber struct {
+	approvalId uint
+	decided    chan struct{}
+	once       sync.Once
+}
+
+func newDecisionSubscriber(approvalId uint) *decisionSubscriber {
+	return &decisionSubscriber{
+		approvalId: approvalId,
+		decided:    make(chan struct{}),
+	}
+}
+
+func (s *decisionSubscriber) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
+	if event.Event != EventApprovalDecided {
+		return
+	}
+	properties, ok := event.Properties.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if id, ok := properties["id"].(uint); ok && id == s.approvalId {
+		s.once.Do(func() { close(s.decided) })
+	}
+}
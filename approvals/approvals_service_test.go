@@ -0,0 +1,243 @@
+package approvals
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func setApprovalThreshold(t *testing.T, svc *tests.TestService, appId uint, thresholdLoki int) {
+	err := svc.DB.Create(&db.AppSpendingPolicy{AppID: appId, ApprovalThresholdLoki: thresholdLoki}).Error
+	require.NoError(t, err)
+}
+
+// awaitInBackground starts AwaitApproval and returns its result channel
+// together with the approval it created.
+func awaitInBackground(t *testing.T, svc *tests.TestService, approvalsSvc *approvalsService, ctx context.Context, payment Payment) (chan error, db.PaymentApproval) {
+	result := make(chan error, 1)
+	go func() {
+		result <- approvalsSvc.AwaitApproval(ctx, payment)
+	}()
+
+	var approval db.PaymentApproval
+	require.Eventually(t, func() bool {
+		return svc.DB.Where("app_id = ?", payment.AppId).Last(&approval).Error == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return result, approval
+}
+
+func TestAwaitApproval_BelowThreshold(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	setApprovalThreshold(t, svc, app.ID, 1000)
+
+	approvalsSvc := NewApprovalsService(svc.DB, svc.EventPublisher)
+	err = approvalsSvc.AwaitApproval(context.Background(), Payment{AppId: app.ID, AmountMloki: 1_000_000})
+	assert.NoError(t, err)
+
+	// no policy at all
+	other, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	err = approvalsSvc.AwaitApproval(context.Background(), Payment{AppId: other.ID, AmountMloki: 1_000_000_000})
+	assert.NoError(t, err)
+
+	var count int64
+	svc.DB.Model(&db.PaymentApproval{}).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestAwaitApproval_Approved(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	setApprovalThreshold(t, svc, app.ID, 1000)
+
+	mockEventConsumer := tests.NewMockEventConsumer()
+	svc.EventPublisher.RegisterSubscriber(mockEventConsumer)
+
+	approvalsSvc := NewApprovalsService(svc.DB, svc.EventPublisher)
+	result, approval := awaitInBackground(t, svc, approvalsSvc, context.Background(), Payment{
+		AppId:       app.ID,
+		Method:      "pay_invoice",
+		AmountMloki: 1_000_001,
+		Description: "coffee",
+	})
+	assert.Equal(t, db.PaymentApprovalStatePending, approval.State)
+	assert.Equal(t, uint64(1_000_001), approval.AmountMloki)
+	assert.Equal(t, "coffee", approval.Description)
+
+	require.Eventually(t, func() bool {
+		events := mockEventConsumer.GetConsumedEvents()
+		return len(events) > 0 && events[0].Event == EventApprovalRequested
+	}, 5*time.Second, 10*time.Millisecond)
+
+	decided, err := approvalsSvc.DecideApproval(approval.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, db.PaymentApprovalStateApproved, decided.State)
+	assert.NotNil(t, decided.DecidedAt)
+	assert.Equal(t, app.Name, decided.App.Name)
+
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("AwaitApproval did not return after the approval was decided")
+	}
+
+	_, err = approvalsSvc.DecideApproval(approval.ID, false)
+	assert.ErrorIs(t, err, ErrApprovalNotPending)
+}
+
+func TestAwaitApproval_Rejected(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	setApprovalThreshold(t, svc, app.ID, 1)
+
+	approvalsSvc := NewApprovalsService(svc.DB, svc.EventPublisher)
+	result, approval := awaitInBackground(t, svc, approvalsSvc, context.Background(), Payment{AppId: app.ID, AmountMloki: 2000})
+
+	_, err = approvalsSvc.DecideApproval(approval.ID, false)
+	require.NoError(t, err)
+
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, ErrPaymentRejected)
+	case <-time.After(5 * time.Second):
+		t.Fatal("AwaitApproval did not return after the approval was decided")
+	}
+}
+
+func TestAwaitApproval_Timeout(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	setApprovalThreshold(t, svc, app.ID, 1)
+
+	approvalsSvc := NewApprovalsService(svc.DB, svc.EventPublisher)
+	approvalsSvc.timeout = 100 * time.Millisecond
+
+	err = approvalsSvc.AwaitApproval(context.Background(), Payment{AppId: app.ID, AmountMloki: 2000})
+	assert.ErrorIs(t, err, ErrApprovalTimeout)
+
+	approvals, err := approvalsSvc.ListApprovals(db.PaymentApprovalStateExpired)
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+
+	_, err = approvalsSvc.DecideApproval(approvals[0].ID, true)
+	assert.ErrorIs(t, err, ErrApprovalNotPending)
+}
+
+func TestAwaitApproval_ContextCancelled(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	setApprovalThreshold(t, svc, app.ID, 1)
+
+	approvalsSvc := NewApprovalsService(svc.DB, svc.EventPublisher)
+	ctx, cancel := context.WithCancel(context.Background())
+	result, approval := awaitInBackground(t, svc, approvalsSvc, ctx, Payment{AppId: app.ID, AmountMloki: 2000})
+	cancel()
+
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("AwaitApproval did not return after its context was cancelled")
+	}
+
+	require.NoError(t, svc.DB.First(&approval, approval.ID).Error)
+	assert.Equal(t, db.PaymentApprovalStateExpired, approval.State)
+}
+
+func TestDecideApproval_NotFound(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	_, err = NewApprovalsService(svc.DB, svc.EventPublisher).DecideApproval(42, true)
+	assert.ErrorIs(t, err, ErrApprovalNotFound)
+}
+
+func TestExpirePendingApprovals(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	for _, state := range []string{db.PaymentApprovalStatePending, db.PaymentApprovalStateApproved} {
+		err = svc.DB.Create(&db.PaymentApproval{
+			AppId:     app.ID,
+			State:     state,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}).Error
+		require.NoError(t, err)
+	}
+
+	approvalsSvc := NewApprovalsService(svc.DB, svc.EventPublisher)
+	require.NoError(t, approvalsSvc.ExpirePendingApprovals())
+
+	pending, err := approvalsSvc.ListApprovals(db.PaymentApprovalStatePending)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	all, err := approvalsSvc.ListApprovals("")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, db.PaymentApprovalStateApproved, all[0].State)
+	assert.Equal(t, db.PaymentApprovalStateExpired, all[1].State)
+}
+
+func TestRejectPendingApprovals(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	setApprovalThreshold(t, svc, app.ID, 1000)
+
+	approvalsSvc := NewApprovalsService(svc.DB, svc.EventPublisher)
+	result, approval := awaitInBackground(t, svc, approvalsSvc, context.Background(), Payment{
+		AppId:       app.ID,
+		Method:      "pay_invoice",
+		AmountMloki: 1_000_001,
+	})
+	assert.Equal(t, db.PaymentApprovalStatePending, approval.State)
+
+	require.NoError(t, approvalsSvc.RejectPendingApprovals())
+
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, ErrPaymentRejected)
+	case <-time.After(5 * time.Second):
+		t.Fatal("AwaitApproval did not return after the approval was rejected")
+	}
+
+	_, err = approvalsSvc.DecideApproval(approval.ID, true)
+	assert.ErrorIs(t, err, ErrApprovalNotPending)
+}
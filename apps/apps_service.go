@@ -76,6 +76,11 @@ type AppsService interface {
 	// PerWalletMaxMloki, and/or MinBudgetRenewal. A nil pointer leaves that
 	// field unchanged.
 	UpdateCircleHubConfig(appID uint, maxExpSecs *int, feesPpm *int, perWalletMaxMloki *int, minBudgetRenewal *string) error
+	// GetAppSpendingPolicy returns an app's spending policy, or nil if it has none.
+	GetAppSpendingPolicy(appID uint) (*db.AppSpendingPolicy, error)
+	// SetAppSpendingPolicy creates or replaces an app's spending policy from
+	// policy's limits and pubkey lists. A policy without any rule removes it.
+	SetAppSpendingPolicy(appID uint, policy db.AppSpendingPolicy) error
 	// CreateCircleIdentity creates a standalone, reusable CircleIdentity.
 	CreateCircleIdentity(name, policy, providerPubkey string) (*db.CircleIdentity, error)
 	// GetCircleIdentity returns a CircleIdentity by ID.
@@ -0,0 +1,83 @@
+package apps
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+)
+
+func (svc *appsService) GetAppSpendingPolicy(appID uint) (*db.AppSpendingPolicy, error) {
+	var policy db.AppSpendingPolicy
+	err := svc.db.Where("app_id = ?", appID).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (svc *appsService) SetAppSpendingPolicy(appID uint, policy db.AppSpendingPolicy) error {
+	if policy.MaxAmountPerPaymentLoki < 0 || policy.MaxPaymentsPerHour < 0 || policy.MaxFeePpm < 0 || policy.ApprovalThresholdLoki < 0 {
+		return fmt.Errorf("%w: spending policy limits must not be negative", constants.ErrInvalidParams)
+	}
+	if policy.MaxFeePpm > constants.PPM_DIVISOR {
+		return fmt.Errorf("%w: max_fee_ppm must be at most %d", constants.ErrInvalidParams, constants.PPM_DIVISOR)
+	}
+	allowedPubkeys, err := normalizeNodePubkeys(policy.AllowedPubkeys)
+	if err != nil {
+		return err
+	}
+	deniedPubkeys, err := normalizeNodePubkeys(policy.DeniedPubkeys)
+	if err != nil {
+		return err
+	}
+
+	if policy.MaxAmountPerPaymentLoki == 0 && policy.MaxPaymentsPerHour == 0 && policy.MaxFeePpm == 0 &&
+		policy.ApprovalThresholdLoki == 0 && len(allowedPubkeys) == 0 && len(deniedPubkeys) == 0 {
+		return svc.db.Where("app_id = ?", appID).Delete(&db.AppSpendingPolicy{}).Error
+	}
+
+	row := db.AppSpendingPolicy{
+		AppID:                   appID,
+		MaxAmountPerPaymentLoki: policy.MaxAmountPerPaymentLoki,
+		MaxPaymentsPerHour:      policy.MaxPaymentsPerHour,
+		MaxFeePpm:               policy.MaxFeePpm,
+		AllowedPubkeys:          allowedPubkeys,
+		DeniedPubkeys:           deniedPubkeys,
+		ApprovalThresholdLoki:   policy.ApprovalThresholdLoki,
+	}
+	return svc.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "app_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"max_amount_per_payment_loki", "max_payments_per_hour", "max_fee_ppm",
+			"allowed_pubkeys", "denied_pubkeys", "approval_threshold_loki", "updated_at",
+		}),
+	}).Create(&row).Error
+}
+
+// normalizeNodePubkeys lowercases and dedupes a list of node pubkeys,
+// rejecting anything that is not a 33-byte compressed public key.
+func normalizeNodePubkeys(pubkeys []string) ([]string, error) {
+	normalized := make([]string, 0, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+		decoded, err := hex.DecodeString(pubkey)
+		if err != nil || len(decoded) != 33 {
+			return nil, fmt.Errorf("%w: invalid node pubkey %q", constants.ErrInvalidParams, pubkey)
+		}
+		if !slices.Contains(normalized, pubkey) {
+			normalized = append(normalized, pubkey)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,87 @@
+package apps_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+const spendingPolicyPubkey = "02a3b1fbd2a6a3e0f0c45fdd8b0b8b5e0d5b2dd4a2b1c1ab1ad3c6f1e3e2d1c0b9"
+
+func TestSetAppSpendingPolicy_CreateUpdateRemove(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	policy, err := svc.AppsService.GetAppSpendingPolicy(app.ID)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	err = svc.AppsService.SetAppSpendingPolicy(app.ID, db.AppSpendingPolicy{
+		MaxAmountPerPaymentLoki: 1000,
+		AllowedPubkeys:          []string{strings.ToUpper(spendingPolicyPubkey), spendingPolicyPubkey},
+	})
+	require.NoError(t, err)
+
+	policy, err = svc.AppsService.GetAppSpendingPolicy(app.ID)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, 1000, policy.MaxAmountPerPaymentLoki)
+	assert.Equal(t, []string{spendingPolicyPubkey}, []string(policy.AllowedPubkeys))
+
+	err = svc.AppsService.SetAppSpendingPolicy(app.ID, db.AppSpendingPolicy{MaxPaymentsPerHour: 10, ApprovalThresholdLoki: 500})
+	require.NoError(t, err)
+
+	policy, err = svc.AppsService.GetAppSpendingPolicy(app.ID)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Zero(t, policy.MaxAmountPerPaymentLoki)
+	assert.Equal(t, 10, policy.MaxPaymentsPerHour)
+	assert.Equal(t, 500, policy.ApprovalThresholdLoki)
+	assert.Empty(t, policy.AllowedPubkeys)
+
+	var count int64
+	svc.DB.Model(&db.AppSpendingPolicy{}).Where("app_id = ?", app.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	err = svc.AppsService.SetAppSpendingPolicy(app.ID, db.AppSpendingPolicy{})
+	require.NoError(t, err)
+
+	policy, err = svc.AppsService.GetAppSpendingPolicy(app.ID)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
+
+func TestSetAppSpendingPolicy_Invalid(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	for _, policy := range []db.AppSpendingPolicy{
+		{MaxAmountPerPaymentLoki: -1},
+		{MaxPaymentsPerHour: -1},
+		{ApprovalThresholdLoki: -1},
+		{MaxFeePpm: constants.PPM_DIVISOR + 1},
+		{AllowedPubkeys: []string{"not a pubkey"}},
+		{DeniedPubkeys: []string{spendingPolicyPubkey[:64]}},
+	} {
+		err = svc.AppsService.SetAppSpendingPolicy(app.ID, policy)
+		assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	}
+
+	policy, err := svc.AppsService.GetAppSpendingPolicy(app.ID)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
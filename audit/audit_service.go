@@ -0,0 +1,237 @@
+// Package audit keeps an append-only log of admin API requests that change
+// the hub or reveal a secret: who made them, from where, with what (redacted)
+// payload and whether they succeeded.
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// maxPayloadLength bounds the stored payload of a single entry
+const maxPayloadLength = 16 * 1024
+
+// redactedValue replaces the values of sensitive payload fields
+const redactedValue = "[redacted]"
+
+// sensitiveFields are matched case-insensitively against payload field names
+// containing them
+var sensitiveFields = []string{
+	"password",
+	"mnemonic",
+	"secret",
+	"token",
+	"privatekey",
+	"seed",
+	"passphrase",
+	"totp",
+}
+
+// exportBatchSize is the number of entries loaded at a time when exporting
+const exportBatchSize = 500
+
+// Filter selects audit log entries. Empty fields match every entry.
+type Filter struct {
+	Action     string
+	Actor      string
+	Outcome    string
+	ApiTokenId *uint
+	From       time.Time
+	Until      time.Time
+}
+
+type AuditService interface {
+	// Record appends an entry to the log. Entries cannot be changed or
+	// removed afterwards.
+	Record(entry *db.AuditLogEntry) error
+	// List returns entries newest first together with the total number of
+	// matches.
+	List(filter *Filter, limit uint64, offset uint64) ([]db.AuditLogEntry, int64, error)
+	// ExportCSV writes every matching entry, oldest first, as CSV.
+	ExportCSV(filter *Filter, w io.Writer) error
+}
+
+type auditService struct {
+	db *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *auditService {
+	return &auditService{
+		db: db,
+	}
+}
+
+func (svc *auditService) Record(entry *db.AuditLogEntry) error {
+	if entry.Action == "" {
+		return fmt.Errorf("%w: action must be set", constants.ErrInvalidParams)
+	}
+	if entry.Outcome != OutcomeSuccess && entry.Outcome != OutcomeFailure {
+		return fmt.Errorf("%w: unknown outcome %q", constants.ErrInvalidParams, entry.Outcome)
+	}
+	return svc.db.Create(entry).Error
+}
+
+func (svc *auditService) List(filter *Filter, limit uint64, offset uint64) ([]db.AuditLogEntry, int64, error) {
+	query, err := svc.filterQuery(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entries := []db.AuditLogEntry{}
+	query = query.Order("id DESC").Offset(int(offset)) //nolint:gosec // offset is bounded by the number of rows
+	if limit > 0 {
+		query = query.Limit(int(limit)) //nolint:gosec // limit is bounded by the number of rows
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (svc *auditService) ExportCSV(filter *Filter, w io.Writer) error {
+	query, err := svc.filterQuery(filter)
+	if err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{
+		"id", "created_at", "action", "actor", "api_token_id", "remote_ip",
+		"request_id", "method", "path", "outcome", "status_code", "payload",
+	}); err != nil {
+		return err
+	}
+
+	entries := []db.AuditLogEntry{}
+	result := query.Order("id ASC").FindInBatches(&entries, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, entry := range entries {
+			apiTokenId := ""
+			if entry.ApiTokenId != nil {
+				apiTokenId = strconv.FormatUint(uint64(*entry.ApiTokenId), 10)
+			}
+			if err := csvWriter.Write([]string{
+				strconv.FormatUint(uint64(entry.ID), 10),
+				entry.CreatedAt.UTC().Format(time.RFC3339),
+				entry.Action,
+				entry.Actor,
+				apiTokenId,
+				entry.RemoteIp,
+				entry.RequestId,
+				entry.Method,
+				entry.Path,
+				entry.Outcome,
+				strconv.Itoa(entry.StatusCode),
+				entry.Payload,
+			}); err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func (svc *auditService) filterQuery(filter *Filter) (*gorm.DB, error) {
+	query := svc.db.Model(&db.AuditLogEntry{})
+	if filter == nil {
+		return query, nil
+	}
+	if filter.Outcome != "" && filter.Outcome != OutcomeSuccess && filter.Outcome != OutcomeFailure {
+		return nil, fmt.Errorf("%w: unknown outcome %q", constants.ErrInvalidParams, filter.Outcome)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.ApiTokenId != nil {
+		query = query.Where("api_token_id = ?", *filter.ApiTokenId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+	return query, nil
+}
+
+// RedactPayload returns a JSON request body with the values of sensitive
+// fields (passwords, mnemonics, secrets, tokens, keys) replaced, ready to be
+// stored in an entry. Bodies that are not JSON are not stored at all and long
+// payloads are truncated.
+func RedactPayload(body []byte) string {
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return ""
+	}
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return redactedValue
+	}
+	redacted, err := json.Marshal(redact(payload))
+	if err != nil {
+		return redactedValue
+	}
+	if len(redacted) > maxPayloadLength {
+		return string(redacted[:maxPayloadLength])
+	}
+	return string(redacted)
+}
+
+func redact(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range value {
+			if isSensitiveField(key) {
+				value[key] = redactedValue
+				continue
+			}
+			value[key] = redact(fieldValue)
+		}
+		return value
+	case []interface{}:
+		for i := range value {
+			value[i] = redact(value[i])
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	return slices.ContainsFunc(sensitiveFields, func(field string) bool {
+		return strings.Contains(key, field)
+	})
+}
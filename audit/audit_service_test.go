@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func TestRecord_List(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	auditSvc := NewAuditService(svc.DB)
+	apiTokenId := uint(7)
+	require.NoError(t, auditSvc.Record(&db.AuditLogEntry{Action: "app.create", Actor: "session:full", Outcome: OutcomeSuccess, StatusCode: 200}))
+	require.NoError(t, auditSvc.Record(&db.AuditLogEntry{Action: "app.delete", Actor: "token:ci", ApiTokenId: &apiTokenId, Outcome: OutcomeFailure, StatusCode: 403}))
+	require.NoError(t, auditSvc.Record(&db.AuditLogEntry{Action: "app.create", Actor: "token:ci", ApiTokenId: &apiTokenId, Outcome: OutcomeSuccess, StatusCode: 200}))
+
+	entries, total, err := auditSvc.List(nil, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "app.create", entries[0].Action)
+	assert.Equal(t, "app.delete", entries[1].Action)
+
+	entries, total, err = auditSvc.List(&Filter{Action: "app.create"}, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, entries, 2)
+
+	entries, total, err = auditSvc.List(&Filter{ApiTokenId: &apiTokenId, Outcome: OutcomeFailure}, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 403, entries[0].StatusCode)
+
+	_, total, err = auditSvc.List(&Filter{Until: time.Now().Add(-time.Hour)}, 20, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	_, _, err = auditSvc.List(&Filter{Outcome: "maybe"}, 20, 0)
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+
+	assert.ErrorIs(t, auditSvc.Record(&db.AuditLogEntry{Outcome: OutcomeSuccess}), constants.ErrInvalidParams)
+	assert.ErrorIs(t, auditSvc.Record(&db.AuditLogEntry{Action: "app.create"}), constants.ErrInvalidParams)
+}
+
+func TestExportCSV(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	auditSvc := NewAuditService(svc.DB)
+	require.NoError(t, auditSvc.Record(&db.AuditLogEntry{
+		Action:     "transfer",
+		Actor:      "session:full",
+		RemoteIp:   "127.0.0.1",
+		RequestId:  "req-1",
+		Method:     "POST",
+		Path:       "/api/transfers",
+		Payload:    `{"amountMloki":1000}`,
+		Outcome:    OutcomeSuccess,
+		StatusCode: 200,
+	}))
+	require.NoError(t, auditSvc.Record(&db.AuditLogEntry{Action: "swap.out", Outcome: OutcomeFailure, StatusCode: 500}))
+
+	var out strings.Builder
+	require.NoError(t, auditSvc.ExportCSV(&Filter{Action: "transfer"}, &out))
+
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "action", records[0][2])
+	assert.Equal(t, []string{"transfer", "session:full", "", "127.0.0.1", "req-1", "POST", "/api/transfers", "success", "200", `{"amountMloki":1000}`}, records[1][2:])
+}
+
+func TestRedactPayload(t *testing.T) {
+	assert.Equal(t, "", RedactPayload(nil))
+	assert.Equal(t, redactedValue, RedactPayload([]byte("not json")))
+	assert.JSONEq(t,
+		`{"name":"app","unlockPassword":"[redacted]","nested":[{"Mnemonic":"[redacted]","amount":1}],"webhookSecret":"[redacted]"}`,
+		RedactPayload([]byte(`{"name":"app","unlockPassword":"hunter2","nested":[{"Mnemonic":"abandon abandon","amount":1}],"webhookSecret":"s3cret"}`)),
+	)
+}
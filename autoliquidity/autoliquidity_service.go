@@ -0,0 +1,377 @@
+// Package autoliquidity buys inbound liquidity over LSPS1 when the node's
+// receivable balance drops below a configured threshold. Every selected LSP
+// is asked for a quote and the cheapest one within the fee budget is paid,
+// as long as the daily and monthly spend caps allow it.
+package autoliquidity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/decodepay"
+	"github.com/flokiorg/lokihub/freeze"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/lsps/lsps1"
+	"github.com/flokiorg/lokihub/lsps/manager"
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+const (
+	checkInterval = 15 * time.Minute
+	quoteTimeout  = 30 * time.Second
+	// channelExpiryBlocks is how long the LSP has to keep the channel open,
+	// about 90 days of 1-minute blocks
+	channelExpiryBlocks = 129_600
+	// fundingConfirmsWithinBlocks matches the orders created from the API
+	fundingConfirmsWithinBlocks = 6
+)
+
+// Config is the auto-liquidity configuration. Amounts are in loki.
+type Config struct {
+	// ReceiveThreshold triggers a purchase when the receivable balance drops below it
+	ReceiveThreshold uint64
+	// ChannelSize is the inbound capacity bought per order
+	ChannelSize uint64
+	// MaxFee is the most a single order may cost
+	MaxFee uint64
+	// DailyLimit and MonthlyLimit cap the order totals per calendar day and
+	// month (UTC)
+	DailyLimit   uint64
+	MonthlyLimit uint64
+}
+
+// LoadConfig returns the saved auto-liquidity config, or nil if auto-liquidity
+// is not enabled
+func LoadConfig(cfg config.Config) (*Config, error) {
+	keys := []string{
+		config.AutoLiquidityReceiveThresholdKey,
+		config.AutoLiquidityChannelSizeKey,
+		config.AutoLiquidityMaxFeeKey,
+		config.AutoLiquidityDailyLimitKey,
+		config.AutoLiquidityMonthlyLimitKey,
+	}
+	values := make([]uint64, len(keys))
+	for i, key := range keys {
+		valueStr, _ := cfg.Get(key, "")
+		if valueStr == "" {
+			return nil, nil
+		}
+		value, err := strconv.ParseUint(valueStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto liquidity configuration %s: %w", key, err)
+		}
+		values[i] = value
+	}
+	return &Config{
+		ReceiveThreshold: values[0],
+		ChannelSize:      values[1],
+		MaxFee:           values[2],
+		DailyLimit:       values[3],
+		MonthlyLimit:     values[4],
+	}, nil
+}
+
+// Spending is what auto-liquidity orders cost in the current calendar day
+// and month (UTC), in loki
+type Spending struct {
+	Today     uint64
+	ThisMonth uint64
+}
+
+type AutoLiquidityService interface {
+	// EnableAutoLiquidity (re)starts checking the receivable balance with the
+	// saved config. It does nothing if auto-liquidity is not configured.
+	EnableAutoLiquidity() error
+	StopAutoLiquidity()
+	GetSpending() (*Spending, error)
+}
+
+// LiquidityManager is the part of manager.LiquidityManager used to buy channels
+type LiquidityManager interface {
+	GetSelectedLSPs() ([]manager.SettingsLSP, error)
+	GetLSPS1Info(ctx context.Context, pubkey string) (lsps1.Options, error)
+	QuoteLSPS1Order(ctx context.Context, pubkey string, orderParams lsps1.OrderParams, refundAddr *string) (*lsps1.OrderCreatedEvent, error)
+	MonitorOrder(lspPubkey, orderID string, invoice string, feeTotal, orderTotal, lspBalance, clientBalance uint64)
+	MarkOrderAutoLiquidity(orderID string) error
+	HandleOrderStateUpdate(orderID, state, lspPubkey string)
+	HasPendingAutoLiquidityOrder() (bool, error)
+	AutoLiquidityOrderTotalSince(since time.Time) (uint64, error)
+}
+
+type autoLiquidityService struct {
+	ctx                 context.Context
+	cfg                 config.Config
+	lnClient            lnclient.LNClient
+	liquidityManager    LiquidityManager
+	transactionsService transactions.TransactionsService
+	freezeSvc           freeze.FreezeService
+
+	mu       sync.Mutex
+	cancelFn context.CancelFunc
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// quote is an order created by an LSP that has not been paid yet
+type quote struct {
+	lspPubkey  string
+	orderID    string
+	invoice    string
+	feeTotal   uint64
+	orderTotal uint64
+}
+
+func NewAutoLiquidityService(ctx context.Context, cfg config.Config, lnClient lnclient.LNClient,
+	liquidityManager LiquidityManager, transactionsService transactions.TransactionsService, freezeSvc freeze.FreezeService) AutoLiquidityService {
+	svc := &autoLiquidityService{
+		ctx:                 ctx,
+		cfg:                 cfg,
+		lnClient:            lnClient,
+		liquidityManager:    liquidityManager,
+		transactionsService: transactionsService,
+		freezeSvc:           freezeSvc,
+		now:                 time.Now,
+	}
+	if err := svc.EnableAutoLiquidity(); err != nil {
+		logger.Logger.Error().Err(err).Msg("Couldn't enable auto liquidity")
+	}
+	return svc
+}
+
+func (svc *autoLiquidityService) StopAutoLiquidity() {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.stop()
+}
+
+func (svc *autoLiquidityService) stop() {
+	if svc.cancelFn != nil {
+		svc.cancelFn()
+		svc.cancelFn = nil
+		logger.Logger.Info().Msg("Auto liquidity service stopped")
+	}
+}
+
+func (svc *autoLiquidityService) EnableAutoLiquidity() error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.stop()
+
+	autoLiquidityConfig, err := LoadConfig(svc.cfg)
+	if err != nil {
+		return err
+	}
+	if autoLiquidityConfig == nil {
+		logger.Logger.Info().Msg("Auto liquidity not configured")
+		return nil
+	}
+
+	ctx, cancelFn := context.WithCancel(svc.ctx)
+	svc.cancelFn = cancelFn
+
+	logger.Logger.Info().Msg("Starting auto liquidity workflow")
+
+	go func() {
+		for {
+			select {
+			case <-time.After(checkInterval):
+				if err := svc.checkLiquidity(ctx, autoLiquidityConfig); err != nil {
+					logger.Logger.Error().Err(err).Msg("Auto liquidity check failed")
+				}
+			case <-ctx.Done():
+				logger.Logger.Info().Msg("Stopping auto liquidity workflow")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (svc *autoLiquidityService) GetSpending() (*Spending, error) {
+	dayStart, monthStart := svc.periodStarts()
+	today, err := svc.liquidityManager.AutoLiquidityOrderTotalSince(dayStart)
+	if err != nil {
+		return nil, err
+	}
+	thisMonth, err := svc.liquidityManager.AutoLiquidityOrderTotalSince(monthStart)
+	if err != nil {
+		return nil, err
+	}
+	return &Spending{Today: today, ThisMonth: thisMonth}, nil
+}
+
+func (svc *autoLiquidityService) periodStarts() (time.Time, time.Time) {
+	now := svc.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return dayStart, monthStart
+}
+
+// checkLiquidity buys a channel if the receivable balance is below the
+// threshold and the spend caps allow it
+func (svc *autoLiquidityService) checkLiquidity(ctx context.Context, autoLiquidityConfig *Config) error {
+	if err := svc.freezeSvc.CheckOutgoingPayment(); err != nil {
+		logger.Logger.Info().Err(err).Msg("Not buying liquidity")
+		return nil
+	}
+
+	balances, err := svc.lnClient.GetBalances(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get balances: %w", err)
+	}
+	receivable := uint64(max(balances.Lightning.TotalReceivable, 0))
+	if receivable >= autoLiquidityConfig.ReceiveThreshold*1000 {
+		logger.Logger.Debug().Msg("Enough receivable balance, not buying liquidity")
+		return nil
+	}
+
+	pending, err := svc.liquidityManager.HasPendingAutoLiquidityOrder()
+	if err != nil {
+		return err
+	}
+	if pending {
+		logger.Logger.Debug().Msg("Waiting for the previous auto liquidity order")
+		return nil
+	}
+
+	spending, err := svc.GetSpending()
+	if err != nil {
+		return err
+	}
+	if spending.Today >= autoLiquidityConfig.DailyLimit || spending.ThisMonth >= autoLiquidityConfig.MonthlyLimit {
+		logger.Logger.Info().
+			Uint64("spent_today", spending.Today).
+			Uint64("spent_this_month", spending.ThisMonth).
+			Msg("Auto liquidity spend cap reached")
+		return nil
+	}
+	budget := min(autoLiquidityConfig.DailyLimit-spending.Today, autoLiquidityConfig.MonthlyLimit-spending.ThisMonth)
+
+	best, err := svc.cheapestQuote(ctx, autoLiquidityConfig, budget)
+	if err != nil {
+		return err
+	}
+
+	return svc.payQuote(best, autoLiquidityConfig.ChannelSize)
+}
+
+// cheapestQuote asks every selected LSP for a quote and returns the
+// cheapest one within the max fee and the remaining budget
+func (svc *autoLiquidityService) cheapestQuote(ctx context.Context, autoLiquidityConfig *Config, budget uint64) (*quote, error) {
+	lsps, err := svc.liquidityManager.GetSelectedLSPs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get selected LSPs: %w", err)
+	}
+	if len(lsps) == 0 {
+		return nil, errors.New("no LSP selected")
+	}
+
+	var best *quote
+	for _, lsp := range lsps {
+		q, err := svc.requestQuote(ctx, lsp.Pubkey, autoLiquidityConfig.ChannelSize)
+		if err != nil {
+			logger.Logger.Warn().Err(err).Str("lsp", lsp.Pubkey).Msg("Failed to get auto liquidity quote")
+			continue
+		}
+		logger.Logger.Info().
+			Str("lsp", lsp.Pubkey).
+			Uint64("fee_total", q.feeTotal).
+			Uint64("order_total", q.orderTotal).
+			Msg("Received auto liquidity quote")
+		if q.feeTotal > autoLiquidityConfig.MaxFee || q.orderTotal > budget {
+			continue
+		}
+		if best == nil || q.feeTotal < best.feeTotal {
+			best = q
+		}
+	}
+	if best == nil {
+		return nil, errors.New("no LSP quoted within the max fee and spend caps")
+	}
+	return best, nil
+}
+
+func (svc *autoLiquidityService) requestQuote(ctx context.Context, lspPubkey string, channelSize uint64) (*quote, error) {
+	ctx, cancel := context.WithTimeout(ctx, quoteTimeout)
+	defer cancel()
+
+	options, err := svc.liquidityManager.GetLSPS1Info(ctx, lspPubkey)
+	if err != nil {
+		return nil, err
+	}
+	if channelSize < options.MinInitialLspBalanceLoki ||
+		(options.MaxInitialLspBalanceLoki > 0 && channelSize > options.MaxInitialLspBalanceLoki) {
+		return nil, fmt.Errorf("channel size %d is outside of the LSP's range", channelSize)
+	}
+	expiryBlocks := uint32(channelExpiryBlocks)
+	if options.MaxChannelExpiryBlocks > 0 {
+		expiryBlocks = min(expiryBlocks, options.MaxChannelExpiryBlocks)
+	}
+
+	event, err := svc.liquidityManager.QuoteLSPS1Order(ctx, lspPubkey, lsps1.OrderParams{
+		LspBalanceLoki:               channelSize,
+		RequiredChannelConfirmations: options.MinRequiredChannelConfirmations,
+		FundingConfirmsWithinBlocks:  max(options.MinFundingConfirmsWithinBlocks, fundingConfirmsWithinBlocks),
+		ChannelExpiryBlocks:          expiryBlocks,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if event.Payment.Bolt11 == nil {
+		return nil, errors.New("LSP offered no lightning payment")
+	}
+
+	q := &quote{
+		lspPubkey:  lspPubkey,
+		orderID:    event.OrderID,
+		invoice:    event.Payment.Bolt11.Invoice,
+		feeTotal:   event.Payment.Bolt11.FeeTotalLoki,
+		orderTotal: event.Payment.Bolt11.OrderTotalLoki,
+	}
+	// no client balance is pushed, so the whole order is the fee
+	if q.feeTotal == 0 {
+		q.feeTotal = q.orderTotal
+	}
+
+	paymentRequest, err := decodepay.Decode(q.invoice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode order invoice: %w", err)
+	}
+	if paymentRequest.MSat > int64(q.orderTotal*1000) { //nolint:gosec // order totals are far below int64 range
+		return nil, fmt.Errorf("order invoice of %d mloki exceeds the order total", paymentRequest.MSat)
+	}
+	return q, nil
+}
+
+func (svc *autoLiquidityService) payQuote(q *quote, channelSize uint64) error {
+	svc.liquidityManager.MonitorOrder(q.lspPubkey, q.orderID, q.invoice, q.feeTotal, q.orderTotal, channelSize, 0)
+	if err := svc.liquidityManager.MarkOrderAutoLiquidity(q.orderID); err != nil {
+		return fmt.Errorf("failed to mark auto liquidity order: %w", err)
+	}
+
+	logger.Logger.Info().
+		Str("lsp", q.lspPubkey).
+		Str("order_id", q.orderID).
+		Uint64("channel_size", channelSize).
+		Uint64("fee_total", q.feeTotal).
+		Msg("Paying auto liquidity order")
+
+	metadata := map[string]interface{}{
+		"auto_liquidity": true,
+		"lsps1_order_id": q.orderID,
+	}
+	if _, err := svc.transactionsService.SendPaymentSync(q.invoice, nil, metadata, svc.lnClient, nil, nil); err != nil {
+		// the LSP will not open the channel, and the failed order no longer
+		// counts towards the spend caps
+		svc.liquidityManager.HandleOrderStateUpdate(q.orderID, "FAILED", q.lspPubkey)
+		return fmt.Errorf("failed to pay auto liquidity order: %w", err)
+	}
+	return nil
+}
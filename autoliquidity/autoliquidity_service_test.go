@@ -0,0 +1,216 @@
+package autoliquidity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/freeze"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/lsps/lsps1"
+	"github.com/flokiorg/lokihub/lsps/manager"
+	"github.com/flokiorg/lokihub/tests"
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+type fakeQuote struct {
+	options lsps1.Options
+	fee     uint64
+	total   uint64
+	err     error
+}
+
+type fakeLiquidityManager struct {
+	quotes        map[string]fakeQuote
+	pending       bool
+	spentSince    func(since time.Time) uint64
+	monitored     []string
+	autoOrders    []string
+	failedOrders  []string
+	quotedBalance uint64
+}
+
+func (m *fakeLiquidityManager) GetSelectedLSPs() ([]manager.SettingsLSP, error) {
+	lsps := []manager.SettingsLSP{}
+	for _, pubkey := range []string{"lsp_a", "lsp_b", "lsp_c"} {
+		if _, ok := m.quotes[pubkey]; ok {
+			lsps = append(lsps, manager.SettingsLSP{Pubkey: pubkey, Active: true})
+		}
+	}
+	return lsps, nil
+}
+
+func (m *fakeLiquidityManager) GetLSPS1Info(ctx context.Context, pubkey string) (lsps1.Options, error) {
+	return m.quotes[pubkey].options, nil
+}
+
+func (m *fakeLiquidityManager) QuoteLSPS1Order(ctx context.Context, pubkey string, orderParams lsps1.OrderParams, refundAddr *string) (*lsps1.OrderCreatedEvent, error) {
+	q := m.quotes[pubkey]
+	if q.err != nil {
+		return nil, q.err
+	}
+	m.quotedBalance = orderParams.LspBalanceLoki
+	return &lsps1.OrderCreatedEvent{
+		OrderID: "order_" + pubkey,
+		Payment: lsps1.PaymentInfo{Bolt11: &lsps1.Bolt11PaymentInfo{
+			FeeTotalLoki:   q.fee,
+			OrderTotalLoki: q.total,
+			Invoice:        tests.MockInvoice,
+		}},
+	}, nil
+}
+
+func (m *fakeLiquidityManager) MonitorOrder(lspPubkey, orderID string, invoice string, feeTotal, orderTotal, lspBalance, clientBalance uint64) {
+	m.monitored = append(m.monitored, orderID)
+}
+
+func (m *fakeLiquidityManager) MarkOrderAutoLiquidity(orderID string) error {
+	m.autoOrders = append(m.autoOrders, orderID)
+	return nil
+}
+
+func (m *fakeLiquidityManager) HandleOrderStateUpdate(orderID, state, lspPubkey string) {
+	if state == "FAILED" {
+		m.failedOrders = append(m.failedOrders, orderID)
+	}
+}
+
+func (m *fakeLiquidityManager) HasPendingAutoLiquidityOrder() (bool, error) {
+	return m.pending, nil
+}
+
+func (m *fakeLiquidityManager) AutoLiquidityOrderTotalSince(since time.Time) (uint64, error) {
+	if m.spentSince == nil {
+		return 0, nil
+	}
+	return m.spentSince(since), nil
+}
+
+func newTestAutoLiquidityService(t *testing.T, liquidityManager *fakeLiquidityManager) (*autoLiquidityService, *tests.TestService) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	t.Cleanup(svc.Remove)
+	return &autoLiquidityService{
+		ctx:                 context.Background(),
+		cfg:                 svc.Cfg,
+		lnClient:            svc.LNClient,
+		liquidityManager:    liquidityManager,
+		transactionsService: transactions.NewTransactionsService(svc.DB, svc.EventPublisher),
+		freezeSvc:           freeze.NewFreezeService(svc.DB, svc.EventPublisher),
+		now:                 time.Now,
+	}, svc
+}
+
+var testConfig = &Config{
+	ReceiveThreshold: 100_000,
+	ChannelSize:      1_000_000,
+	MaxFee:           1_000,
+	DailyLimit:       2_000,
+	MonthlyLimit:     10_000,
+}
+
+// the mock invoice is for 123 loki, so quotes must total at least that
+func TestCheckLiquidity_BuysCheapestQuote(t *testing.T) {
+	liquidityManager := &fakeLiquidityManager{quotes: map[string]fakeQuote{
+		"lsp_a": {fee: 800, total: 800},
+		"lsp_b": {fee: 500, total: 500},
+		"lsp_c": {err: errors.New("offline")},
+	}}
+	svc, _ := newTestAutoLiquidityService(t, liquidityManager)
+
+	require.NoError(t, svc.checkLiquidity(context.Background(), testConfig))
+	assert.Equal(t, []string{"order_lsp_b"}, liquidityManager.monitored)
+	assert.Equal(t, []string{"order_lsp_b"}, liquidityManager.autoOrders)
+	assert.Empty(t, liquidityManager.failedOrders)
+	assert.Equal(t, testConfig.ChannelSize, liquidityManager.quotedBalance)
+}
+
+func TestCheckLiquidity_SkipsQuotes(t *testing.T) {
+	testCases := []struct {
+		name   string
+		quotes map[string]fakeQuote
+	}{
+		{"fee above max", map[string]fakeQuote{"lsp_a": {fee: 1_001, total: 1_001}}},
+		{"total above daily budget", map[string]fakeQuote{"lsp_a": {fee: 500, total: 2_001}}},
+		{"channel size above LSP max", map[string]fakeQuote{"lsp_a": {
+			options: lsps1.Options{MaxInitialLspBalanceLoki: 500_000}, fee: 500, total: 500,
+		}}},
+		{"invoice above order total", map[string]fakeQuote{"lsp_a": {fee: 100, total: 100}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			liquidityManager := &fakeLiquidityManager{quotes: tc.quotes}
+			svc, _ := newTestAutoLiquidityService(t, liquidityManager)
+
+			assert.Error(t, svc.checkLiquidity(context.Background(), testConfig))
+			assert.Empty(t, liquidityManager.monitored)
+		})
+	}
+}
+
+func TestCheckLiquidity_DoesNotBuy(t *testing.T) {
+	quotes := map[string]fakeQuote{"lsp_a": {fee: 500, total: 500}}
+
+	t.Run("enough receivable balance", func(t *testing.T) {
+		liquidityManager := &fakeLiquidityManager{quotes: quotes}
+		svc, _ := newTestAutoLiquidityService(t, liquidityManager)
+		config := *testConfig
+		config.ReceiveThreshold = 0
+
+		require.NoError(t, svc.checkLiquidity(context.Background(), &config))
+		assert.Empty(t, liquidityManager.monitored)
+	})
+
+	t.Run("outgoing payments paused", func(t *testing.T) {
+		liquidityManager := &fakeLiquidityManager{quotes: quotes}
+		svc, _ := newTestAutoLiquidityService(t, liquidityManager)
+		_, err := svc.freezeSvc.Freeze(true, freeze.SourceApi)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.checkLiquidity(context.Background(), testConfig))
+		assert.Empty(t, liquidityManager.monitored)
+	})
+
+	t.Run("previous order pending", func(t *testing.T) {
+		liquidityManager := &fakeLiquidityManager{quotes: quotes, pending: true}
+		svc, _ := newTestAutoLiquidityService(t, liquidityManager)
+
+		require.NoError(t, svc.checkLiquidity(context.Background(), testConfig))
+		assert.Empty(t, liquidityManager.monitored)
+	})
+
+	t.Run("monthly cap reached", func(t *testing.T) {
+		now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+		liquidityManager := &fakeLiquidityManager{quotes: quotes, spentSince: func(since time.Time) uint64 {
+			if since.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+				return 10_000
+			}
+			return 0
+		}}
+		svc, _ := newTestAutoLiquidityService(t, liquidityManager)
+		svc.now = func() time.Time { return now }
+
+		require.NoError(t, svc.checkLiquidity(context.Background(), testConfig))
+		assert.Empty(t, liquidityManager.monitored)
+
+		spending, err := svc.GetSpending()
+		require.NoError(t, err)
+		assert.Equal(t, &Spending{Today: 0, ThisMonth: 10_000}, spending)
+	})
+}
+
+func TestCheckLiquidity_PaymentFails(t *testing.T) {
+	liquidityManager := &fakeLiquidityManager{quotes: map[string]fakeQuote{"lsp_a": {fee: 500, total: 500}}}
+	svc, testSvc := newTestAutoLiquidityService(t, liquidityManager)
+	mockLn := testSvc.LNClient.(*tests.MockLn)
+	mockLn.PayInvoiceResponses = []*lnclient.PayInvoiceResponse{nil}
+	mockLn.PayInvoiceErrors = []error{errors.New("no route")}
+
+	assert.Error(t, svc.checkLiquidity(context.Background(), testConfig))
+	assert.Equal(t, []string{"order_lsp_a"}, liquidityManager.monitored)
+	assert.Equal(t, []string{"order_lsp_a"}, liquidityManager.failedOrders)
+}
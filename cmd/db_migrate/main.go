@@ -16,6 +16,7 @@ import (
 var expectedTables = []string{
 	"apps",
 	"app_permissions",
+	"app_spending_policies",
 	"circle_identities",
 	"circle_identity_allowed_pubkeys",
 	"circle_hub_configs",
@@ -29,6 +30,23 @@ var expectedTables = []string{
 	"swaps",
 	"user_configs",
 	"forwards",
+	"offers",
+	"lightning_addresses",
+	"zap_requests",
+	"payment_approvals",
+	"webhook_endpoints",
+	"webhook_deliveries",
+	"flokicoin_rates",
+	"rebalances",
+	"channel_fee_updates",
+	"utxo_labels",
+	"api_tokens",
+	"audit_log_entries",
+	"admin_pubkeys",
+	"nostr_auth_events",
+	"totp_factors",
+	"totp_recovery_codes",
+	"hub_freezes",
 	"circle_identities",
 	"circle_identity_allowed_pubkeys",
 	"jit_hub_configs",
@@ -153,6 +171,11 @@ func migrateDB(from, to *gorm.DB) error {
 		return fmt.Errorf("failed to migrate app_permissions: %w", err)
 	}
 
+	logger.Logger.Info().Msg("migrating app_spending_policies...")
+	if err := migrateTable[db.AppSpendingPolicy](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate app_spending_policies: %w", err)
+	}
+
 	logger.Logger.Info().Msg("migrating request_events...")
 	if err := migrateTable[db.RequestEvent](from, tx); err != nil {
 		return fmt.Errorf("failed to migrate request_events: %w", err)
@@ -168,6 +191,71 @@ func migrateDB(from, to *gorm.DB) error {
 		return fmt.Errorf("failed to migrate transactions: %w", err)
 	}
 
+	logger.Logger.Info().Msg("migrating payment_approvals...")
+	if err := migrateTable[db.PaymentApproval](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate payment_approvals: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating webhook_endpoints...")
+	if err := migrateTable[db.WebhookEndpoint](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate webhook_endpoints: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating webhook_deliveries...")
+	if err := migrateTable[db.WebhookDelivery](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate webhook_deliveries: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating flokicoin_rates...")
+	if err := migrateTable[db.FlokicoinRate](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate flokicoin_rates: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating rebalances...")
+	if err := migrateTable[db.Rebalance](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate rebalances: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating channel_fee_updates...")
+	if err := migrateTable[db.ChannelFeeUpdate](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate channel_fee_updates: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating utxo_labels...")
+	if err := migrateTable[db.UtxoLabel](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate utxo_labels: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating api_tokens...")
+	if err := migrateTable[db.ApiToken](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate api_tokens: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating audit_log_entries...")
+	if err := migrateTable[db.AuditLogEntry](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate audit_log_entries: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating admin_pubkeys...")
+	if err := migrateTable[db.AdminPubkey](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate admin_pubkeys: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating totp_factors...")
+	if err := migrateTable[db.TotpFactor](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate totp_factors: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating totp_recovery_codes...")
+	if err := migrateTable[db.TotpRecoveryCode](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate totp_recovery_codes: %w", err)
+	}
+
+	logger.Logger.Info().Msg("migrating hub_freezes...")
+	if err := migrateTable[db.HubFreeze](from, tx); err != nil {
+		return fmt.Errorf("failed to migrate hub_freezes: %w", err)
+	}
+
 	logger.Logger.Info().Msg("migrating user_configs...")
 	if err := migrateTable[db.UserConfig](from, tx); err != nil {
 		return fmt.Errorf("failed to migrate user_configs: %w", err)
@@ -280,9 +368,23 @@ func resetSequences(db *gorm.DB) error {
 	resetReqs := []resetReq{
 		{"apps", "apps_2_id_seq"},
 		{"app_permissions", "app_permissions_2_id_seq"},
+		{"app_spending_policies", "app_spending_policies_id_seq"},
 		{"request_events", "request_events_id_seq"},
 		{"response_events", "response_events_id_seq"},
 		{"transactions", "transactions_id_seq"},
+		{"payment_approvals", "payment_approvals_id_seq"},
+		{"webhook_endpoints", "webhook_endpoints_id_seq"},
+		{"webhook_deliveries", "webhook_deliveries_id_seq"},
+		{"flokicoin_rates", "flokicoin_rates_id_seq"},
+		{"rebalances", "rebalances_id_seq"},
+		{"channel_fee_updates", "channel_fee_updates_id_seq"},
+		{"utxo_labels", "utxo_labels_id_seq"},
+		{"api_tokens", "api_tokens_id_seq"},
+		{"audit_log_entries", "audit_log_entries_id_seq"},
+		{"admin_pubkeys", "admin_pubkeys_id_seq"},
+		{"totp_factors", "totp_factors_id_seq"},
+		{"totp_recovery_codes", "totp_recovery_codes_id_seq"},
+		{"hub_freezes", "hub_freezes_id_seq"},
 		{"user_configs", "user_configs_id_seq"},
 	}
 
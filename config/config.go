@@ -26,12 +26,17 @@ type config struct {
 	cache      map[string]map[string]string // key -> encryptionKeyHash -> value
 	cacheMutex sync.Mutex
 	jwtSecret  string
+	totpSecret string
 }
 
 const (
 	unlockPasswordCheck = "THIS STRING SHOULD MATCH IF PASSWORD IS CORRECT"
 )
 
+// TOTPSecretKey is the config key of the TOTP secret, which is encrypted with
+// the unlock password.
+const TOTPSecretKey = "TOTPSecret"
+
 func NewConfig(env *AppConfig, db *gorm.DB) (*config, error) {
 	cfg := &config{
 		db:    db,
@@ -129,6 +134,26 @@ func (cfg *config) GetJWTSecret() (string, error) {
 	return cfg.jwtSecret, nil
 }
 
+// GetTOTPSecret returns the TOTP secret loaded when the config was unlocked,
+// or an empty string if there is none.
+func (cfg *config) GetTOTPSecret() string {
+	cfg.cacheMutex.Lock()
+	defer cfg.cacheMutex.Unlock()
+	return cfg.totpSecret
+}
+
+// SetTOTPSecret stores the TOTP secret encrypted with the unlock password. An
+// empty secret removes it.
+func (cfg *config) SetTOTPSecret(secret string, encryptionKey string) error {
+	if err := cfg.SetUpdate(TOTPSecretKey, secret, encryptionKey); err != nil {
+		return err
+	}
+	cfg.cacheMutex.Lock()
+	defer cfg.cacheMutex.Unlock()
+	cfg.totpSecret = secret
+	return nil
+}
+
 func (cfg *config) Unlock(encryptionKey string) error {
 	if !cfg.CheckUnlockPassword(encryptionKey) {
 		return errors.New("incorrect password")
@@ -162,6 +187,14 @@ func (cfg *config) Unlock(encryptionKey string) error {
 	}
 	cfg.jwtSecret = jwtSecret
 
+	// kept in memory like the JWT secret so TOTP codes can be checked on
+	// requests that do not carry the unlock password
+	totpSecret, err := cfg.Get(TOTPSecretKey, encryptionKey)
+	if err != nil {
+		return err
+	}
+	cfg.totpSecret = totpSecret
+
 	// Seed the default General relay list on first run only. SetIgnore is a
 	// no-op if "GeneralRelay" already exists, even if the user has since
 	// cleared it to empty, so this never overwrites an explicit user choice.
@@ -703,6 +736,35 @@ func (cfg *config) SetEnableMessageboardNwc(enable bool) error {
 	return nil
 }
 
+// EnableHttpWebhooks reports whether hub events are forwarded to the
+// configured webhook endpoints.
+func (cfg *config) EnableHttpWebhooks() bool {
+	value, err := cfg.Get("EnableHttpWebhooks", "")
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to fetch EnableHttpWebhooks")
+		return constants.DEFAULT_ENABLE_HTTP_WEBHOOKS
+	}
+	if value == "" {
+		return constants.DEFAULT_ENABLE_HTTP_WEBHOOKS
+	}
+	return value == "true"
+}
+
+func (cfg *config) SetEnableHttpWebhooks(enable bool) error {
+	var value string
+	if enable {
+		value = "true"
+	} else {
+		value = "false"
+	}
+	err := cfg.SetUpdate("EnableHttpWebhooks", value, "")
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to update EnableHttpWebhooks")
+		return err
+	}
+	return nil
+}
+
 func (cfg *config) GetDefaultWorkDir() string {
 	if cfg.Env.Workdir != "" {
 		return cfg.Env.Workdir
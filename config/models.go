@@ -5,11 +5,29 @@ const (
 )
 
 const (
-	OnchainAddressKey           = "OnchainAddress"
-	AutoSwapBalanceThresholdKey = "AutoSwapBalanceThreshold"
-	AutoSwapAmountKey           = "AutoSwapAmount"
-	AutoSwapDestinationKey      = "AutoSwapDestination"
-	AutoSwapXpubIndexStart      = "AutoSwapXpubIndexStart"
+	OnchainAddressKey               = "OnchainAddress"
+	AutoSwapBalanceThresholdKey     = "AutoSwapBalanceThreshold"
+	AutoSwapAmountKey               = "AutoSwapAmount"
+	AutoSwapDestinationKey          = "AutoSwapDestination"
+	AutoSwapXpubIndexStart          = "AutoSwapXpubIndexStart"
+	AutoSwapInSpendableThresholdKey = "AutoSwapInSpendableThreshold"
+	AutoSwapInOnchainThresholdKey   = "AutoSwapInOnchainThreshold"
+	AutoSwapInAmountKey             = "AutoSwapInAmount"
+	AutoSwapInDailyLimitKey         = "AutoSwapInDailyLimit"
+	ChannelAcceptorPolicyKey        = "ChannelAcceptorPolicy"
+	ChannelFeeManagerConfigKey      = "ChannelFeeManagerConfig"
+	LSPServiceConfigKey             = "LSPServiceConfig"
+
+	AutoLiquidityReceiveThresholdKey = "AutoLiquidityReceiveThreshold"
+	AutoLiquidityChannelSizeKey      = "AutoLiquidityChannelSize"
+	AutoLiquidityMaxFeeKey           = "AutoLiquidityMaxFee"
+	AutoLiquidityDailyLimitKey       = "AutoLiquidityDailyLimit"
+	AutoLiquidityMonthlyLimitKey     = "AutoLiquidityMonthlyLimit"
+
+	AutoRebalanceSourceMinLocalPercentKey = "AutoRebalanceSourceMinLocalPercent"
+	AutoRebalanceTargetMaxLocalPercentKey = "AutoRebalanceTargetMaxLocalPercent"
+	AutoRebalanceAmountKey                = "AutoRebalanceAmount"
+	AutoRebalanceMaxFeePpmKey             = "AutoRebalanceMaxFeePpm"
 )
 
 type AppConfig struct {
@@ -55,6 +73,9 @@ type AppConfig struct {
 	// CircleWalletRateLimitPerHour caps create_circle_wallet calls per calling
 	// app pubkey. 0 disables the limit entirely.
 	CircleWalletRateLimitPerHour int `envconfig:"CIRCLE_WALLET_RATE_LIMIT_PER_HOUR" default:"3"`
+	// MetricsToken is the bearer token Prometheus scrapes /metrics with. It is
+	// separate from the admin JWT; /metrics is not served when it is empty.
+	MetricsToken string `envconfig:"METRICS_TOKEN"`
 }
 
 func (c *AppConfig) GetBaseFrontendUrl() string {
@@ -71,6 +92,8 @@ type Config interface {
 	SetIgnore(key string, value string, encryptionKey string) error
 	SetUpdate(key string, value string, encryptionKey string) error
 	GetJWTSecret() (string, error)
+	GetTOTPSecret() string
+	SetTOTPSecret(secret string, encryptionKey string) error
 	GetRelayUrls() []string
 	GetNetwork() string
 	GetMempoolApi() string
@@ -106,6 +129,8 @@ type Config interface {
 	SetEnableSwap(value bool) error
 	EnableMessageboardNwc() bool
 	SetEnableMessageboardNwc(value bool) error
+	EnableHttpWebhooks() bool
+	SetEnableHttpWebhooks(value bool) error
 	GetDefaultWorkDir() string
 	GetLSP() string
 	SetLSP(value string) error
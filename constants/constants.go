@@ -40,6 +40,10 @@ const (
 	SWAP_STATE_SUCCESS  = "SUCCESS"
 	SWAP_STATE_FAILED   = "FAILED"
 	SWAP_STATE_REFUNDED = "REFUNDED"
+
+	REBALANCE_STATE_PENDING   = "PENDING"
+	REBALANCE_STATE_SUCCEEDED = "SUCCEEDED"
+	REBALANCE_STATE_FAILED    = "FAILED"
 )
 
 const (
@@ -89,7 +93,7 @@ func BudgetRenewalRank(renewal string) int {
 }
 
 const (
-	PAY_INVOICE_SCOPE       = "pay_invoice" // also covers pay_keysend and multi_* payment methods
+	PAY_INVOICE_SCOPE       = "pay_invoice" // also covers pay_keysend, pay_offer and multi_* payment methods
 	GET_BALANCE_SCOPE       = "get_balance"
 	GET_INFO_SCOPE          = "get_info"
 	MAKE_INVOICE_SCOPE      = "make_invoice"
@@ -170,6 +174,16 @@ const (
 	ERROR_RATE_LIMITED  = "RATE_LIMITED"
 	ERROR_NOT_SUPPORTED = "NOT_SUPPORTED"
 	ERROR_NOT_READY     = "NOT_READY"
+	// ERROR_POLICY_VIOLATION rejects a payment that breaks the paying app's
+	// spending policy (per-payment cap, payment rate, destination or fee rules).
+	ERROR_POLICY_VIOLATION = "POLICY_VIOLATION"
+	// ERROR_PAYMENT_REJECTED and ERROR_APPROVAL_TIMEOUT end a payment that
+	// was held for the wallet owner's approval without being approved.
+	ERROR_PAYMENT_REJECTED = "PAYMENT_REJECTED"
+	ERROR_APPROVAL_TIMEOUT = "APPROVAL_TIMEOUT"
+	// ERROR_FROZEN rejects every request but get_info while the hub or the
+	// app is frozen.
+	ERROR_FROZEN = "FROZEN"
 )
 
 // limit encoded metadata length, otherwise relays may have trouble listing multiple transactions
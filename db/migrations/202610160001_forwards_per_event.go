@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// MigrateForwardsPerEvent clears the forwards recorded before forwards held
+// one row per forwarding event. Those rows only carried per-poll totals that
+// cannot be split into events, and the forwards sync re-imports the node's
+// whole forwarding history from flnd into the emptied table, so the totals
+// would otherwise be counted twice.
+func MigrateForwardsPerEvent(db *gorm.DB) error {
+	if !db.Migrator().HasTable("forwards") {
+		return nil // fresh DB; AutoMigrate will create the table
+	}
+
+	if db.Migrator().HasColumn("forwards", "node_index") {
+		return nil // already migrated
+	}
+
+	return db.Exec(`DELETE FROM forwards`).Error
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/db"
+)
+
+// legacyForward is the forwards table as it was before per-event history,
+// when each row held the totals of one poll.
+type legacyForward struct {
+	ID                           uint
+	OutboundAmountForwardedMloki uint64
+	TotalFeeEarnedMloki          uint64
+	CreatedAt                    time.Time
+	UpdatedAt                    time.Time
+}
+
+func (legacyForward) TableName() string {
+	return "forwards"
+}
+
+func TestMigrateForwardsPerEvent_ClearsLegacyTotals(t *testing.T) {
+	uri := filepath.Join(t.TempDir(), "forwards_per_event_test.db")
+	gormDB, err := db.NewDBWithConfig(&db.Config{URI: uri})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Stop(gormDB) })
+
+	require.NoError(t, gormDB.AutoMigrate(&legacyForward{}))
+	require.NoError(t, gormDB.Create(&legacyForward{
+		OutboundAmountForwardedMloki: 100_000,
+		TotalFeeEarnedMloki:          1_000,
+		CreatedAt:                    time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC),
+	}).Error)
+
+	require.NoError(t, MigrateForwardsPerEvent(gormDB))
+	require.NoError(t, gormDB.AutoMigrate(&db.Forward{}))
+
+	var count int64
+	require.NoError(t, gormDB.Model(&db.Forward{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	// forwards imported afterwards are kept when the migration runs again
+	nodeIndex := uint64(1)
+	require.NoError(t, gormDB.Create(&db.Forward{NodeIndex: &nodeIndex, ForwardedAt: time.Now()}).Error)
+	require.NoError(t, MigrateForwardsPerEvent(gormDB), "running twice must be a no-op, not an error")
+	require.NoError(t, gormDB.Model(&db.Forward{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -52,12 +52,18 @@ func Migrate(gormDB *gorm.DB) error {
 		return err
 	}
 
+	// Drop forwards recorded as poll totals; the node's history is re-imported.
+	if err := MigrateForwardsPerEvent(gormDB); err != nil {
+		return err
+	}
+
 	// AutoMigrate all core models (adds new columns declared in structs)
 	// Note: LSP model is migrated separately in LSPManager (via manager_db.go)
 	if err := gormDB.AutoMigrate(
 		&db.UserConfig{},
 		&db.App{},
 		&db.AppPermission{},
+		&db.AppSpendingPolicy{},
 		&db.RequestEvent{},
 		&db.ResponseEvent{},
 		&db.Transaction{},
@@ -70,6 +76,23 @@ func Migrate(gormDB *gorm.DB) error {
 		&db.JITWalletClaim{},
 		&db.CircleWalletIdentityProof{},
 		&db.CircleWalletMembership{},
+		&db.Offer{},
+		&db.LightningAddress{},
+		&db.ZapRequest{},
+		&db.PaymentApproval{},
+		&db.WebhookEndpoint{},
+		&db.WebhookDelivery{},
+		&db.FlokicoinRate{},
+		&db.Rebalance{},
+		&db.ChannelFeeUpdate{},
+		&db.UtxoLabel{},
+		&db.ApiToken{},
+		&db.AuditLogEntry{},
+		&db.AdminPubkey{},
+		&db.NostrAuthEvent{},
+		&db.TotpFactor{},
+		&db.TotpRecoveryCode{},
+		&db.HubFreeze{},
 	); err != nil {
 		return err
 	}
@@ -75,6 +75,9 @@ type App struct {
 
 	// Cleanup state — set atomically before expiry sweep to prevent double-cleanup.
 	CleanupInProgress bool
+
+	// Frozen apps only answer get_info, like every app while the hub is frozen.
+	Frozen bool
 }
 
 // JITHubConfig holds the per-JIT-Hub parameters that constrain what wallets may be issued.
@@ -260,6 +263,93 @@ type AppPermission struct {
 	UpdatedAt     time.Time
 }
 
+// AppSpendingPolicy holds per-payment rules an app's outgoing payments must
+// satisfy on top of its budget (AppPermission.MaxAmountLoki/BudgetRenewal).
+// At most one row per app; a zero limit or an empty pubkey list disables
+// that rule. DeniedPubkeys wins over AllowedPubkeys.
+//
+// ApprovalThresholdLoki is not a limit but a hold: NIP-47 payments above it
+// wait in a PaymentApproval until the owner decides on them.
+type AppSpendingPolicy struct {
+	ID                      uint `gorm:"primaryKey"`
+	AppID                   uint `gorm:"uniqueIndex;not null"`
+	App                     App  `gorm:"constraint:OnDelete:CASCADE;"`
+	MaxAmountPerPaymentLoki int
+	MaxPaymentsPerHour      int
+	MaxFeePpm               int
+	AllowedPubkeys          datatypes.JSONSlice[string]
+	DeniedPubkeys           datatypes.JSONSlice[string]
+	ApprovalThresholdLoki   int
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+const (
+	PaymentApprovalStatePending  = "pending"
+	PaymentApprovalStateApproved = "approved"
+	PaymentApprovalStateRejected = "rejected"
+	PaymentApprovalStateExpired  = "expired"
+)
+
+// PaymentApproval is a NIP-47 payment held back because it is above its
+// app's AppSpendingPolicy.ApprovalThresholdLoki. The NIP-47 request stays
+// open until the owner approves or rejects it, or ExpiresAt passes.
+type PaymentApproval struct {
+	ID             uint
+	AppId          uint          `gorm:"index;not null"`
+	App            App           `gorm:"constraint:OnDelete:CASCADE;"`
+	RequestEventId *uint         `gorm:"index"`
+	RequestEvent   *RequestEvent `gorm:"constraint:OnDelete:SET NULL;foreignKey:RequestEventId"`
+	Method         string
+	AmountMloki    uint64
+	// Destination is the paid node's pubkey, if known.
+	Destination    string
+	PaymentRequest string
+	Description    string
+	State          string `gorm:"index"`
+	ExpiresAt      time.Time
+	DecidedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+const (
+	WebhookDeliveryStatePending   = "pending"
+	WebhookDeliveryStateDelivered = "delivered"
+	WebhookDeliveryStateFailed    = "failed"
+)
+
+// WebhookEndpoint is an HTTP endpoint that hub events are forwarded to.
+// Events restricts which events are sent; empty means all of them.
+type WebhookEndpoint struct {
+	ID        uint
+	Url       string `gorm:"not null"`
+	Secret    string `gorm:"not null"`
+	Events    datatypes.JSONSlice[string]
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookDelivery is one event queued for, or sent to, a WebhookEndpoint.
+// Failed attempts are retried at NextAttemptAt until the delivery is either
+// delivered or has failed for good.
+type WebhookDelivery struct {
+	ID             uint
+	EndpointId     uint            `gorm:"index;not null"`
+	Endpoint       WebhookEndpoint `gorm:"constraint:OnDelete:CASCADE;"`
+	Event          string
+	Payload        string
+	State          string `gorm:"index"`
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index"`
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
 type RequestEvent struct {
 	ID          uint
 	AppId       *uint
@@ -342,14 +432,209 @@ type Swap struct {
 	UpdatedAt          time.Time
 }
 
+// Forward is a single HTLC forwarded by the node. NodeIndex is the event's
+// position in the node's forwarding log and doubles as the sync cursor.
 type Forward struct {
 	ID                           uint
+	NodeIndex                    *uint64 `gorm:"uniqueIndex"`
+	IncomingChannelId            string  `gorm:"index"`
+	OutgoingChannelId            string  `gorm:"index"`
+	IncomingPeerPubkey           string
+	OutgoingPeerPubkey           string
+	InboundAmountMloki           uint64
 	OutboundAmountForwardedMloki uint64
 	TotalFeeEarnedMloki          uint64
+	ForwardedAt                  time.Time `gorm:"index"`
 	CreatedAt                    time.Time
 	UpdatedAt                    time.Time
 }
 
+// Rebalance is a circular payment from one of our channels to another,
+// paying our own invoice to move AmountMloki of local balance from the
+// outgoing channel to the incoming one. Auto is set for rebalances started
+// by the scheduler.
+type Rebalance struct {
+	ID                uint
+	OutgoingChannelId string `gorm:"index"`
+	IncomingChannelId string `gorm:"index"`
+	LastHopPubkey     string
+	AmountMloki       uint64
+	MaxFeePpm         uint32
+	FeeMloki          uint64
+	PaymentHash       string `gorm:"index"`
+	State             string
+	FailureReason     string
+	Auto              bool
+	CreatedAt         time.Time `gorm:"index"`
+	UpdatedAt         time.Time
+}
+
+// ChannelFeeUpdate is a forwarding fee change made by the channel fee
+// manager, with the local balance share and recent outbound flow it was
+// based on.
+type ChannelFeeUpdate struct {
+	ID                  uint
+	ChannelId           string `gorm:"index"`
+	PeerPubkey          string
+	OldBaseFeeMloki     uint32
+	NewBaseFeeMloki     uint32
+	OldFeePpm           uint32
+	NewFeePpm           uint32
+	LocalBalancePercent uint32
+	OutboundFlowMloki   uint64
+	CreatedAt           time.Time `gorm:"index"`
+}
+
+// UtxoLabel is a user note on an on-chain output of the node wallet, keyed
+// by its "txid:vout" outpoint.
+type UtxoLabel struct {
+	ID        uint
+	Outpoint  string `gorm:"uniqueIndex"`
+	Label     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ApiToken is a named credential for the admin API. Only a SHA-256 hash of
+// the token is stored. Scopes lists the route groups the token may call and
+// AppId optionally restricts it to a single app. Revoking a token deletes it.
+type ApiToken struct {
+	ID         uint
+	Name       string `gorm:"not null"`
+	TokenHash  string `gorm:"uniqueIndex;not null"`
+	Scopes     datatypes.JSONSlice[string]
+	AppId      *uint `gorm:"index"`
+	App        *App  `gorm:"constraint:OnDelete:CASCADE;"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// AuditLogEntry records one admin API request that changed the hub or
+// revealed a secret. Entries are only ever appended. ApiTokenId is not a
+// foreign key, so entries outlive the token that made them.
+type AuditLogEntry struct {
+	ID         uint
+	Action     string `gorm:"index;not null"`
+	Actor      string `gorm:"index"`
+	ApiTokenId *uint  `gorm:"index"`
+	RemoteIp   string
+	RequestId  string `gorm:"index"`
+	Method     string
+	Path       string
+	Payload    string
+	Outcome    string `gorm:"index"`
+	StatusCode int
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+// AdminPubkey is a Nostr pubkey allowed to log in to the admin API with a
+// NIP-98 signed HTTP auth event. Logging in issues a session with the
+// pubkey's Permission ("full" or "readonly").
+type AdminPubkey struct {
+	ID         uint
+	Pubkey     string `gorm:"uniqueIndex;not null"`
+	Name       string
+	Permission string `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NostrAuthEvent is a NIP-98 event that has already been used to log in. It
+// is kept until the event is too old to be accepted anyway.
+type NostrAuthEvent struct {
+	EventId   string    `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// TotpFactor is the TOTP second factor of the hub. There is at most one row.
+// The secret itself is stored encrypted in the user config. ConfirmedAt is
+// nil until a first code has been verified, and LastUsedStep prevents a code
+// from being used twice. FailedAttempts counts the wrong codes since the last
+// valid one; too many lock the factor until LockedUntil.
+type TotpFactor struct {
+	ID                    uint
+	ConfirmedAt           *time.Time
+	PaymentThresholdMloki *uint64
+	LastUsedStep          uint64
+	FailedAttempts        uint `gorm:"not null;default:0"`
+	LockedUntil           *time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// TotpRecoveryCode is a single-use code that can stand in for a TOTP code
+// when the authenticator is lost. Only its hash is stored.
+type TotpRecoveryCode struct {
+	ID        uint
+	CodeHash  string `gorm:"uniqueIndex;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// HubFreeze is the emergency freeze state of the hub. There is at most one
+// row. While Frozen, NIP-47 connections can only call get_info; with
+// PauseOutgoingPayments the admin API cannot send payments either. Receiving
+// is never paused.
+type HubFreeze struct {
+	ID                    uint
+	Frozen                bool
+	PauseOutgoingPayments bool
+	// Source is what last froze or unfroze the hub: "api", "tray" or
+	// "nostr:<pubkey>"
+	Source    string
+	FrozenAt  *time.Time
+	UpdatedAt time.Time
+}
+
+// FlokicoinRate is a snapshot of the fiat price of one FLC, recorded
+// periodically so past transactions can be valued at the rate of their day.
+type FlokicoinRate struct {
+	ID        uint
+	Currency  string `gorm:"index:idx_flokicoin_rates_currency_created_at,priority:1"`
+	Rate      float64
+	CreatedAt time.Time `gorm:"index:idx_flokicoin_rates_currency_created_at,priority:2"`
+}
+
+// Offer is a reusable BOLT12 offer created through make_offer. Each offer
+// belongs to the app that created it (nil for offers made from the admin
+// API) so isolated apps can only look up their own offers.
+type Offer struct {
+	ID          uint
+	AppId       *uint  `gorm:"index"`
+	App         *App   `gorm:"constraint:OnDelete:CASCADE;"`
+	Offer       string `gorm:"uniqueIndex;not null"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// LightningAddress maps an LNURL-pay username (the part before the @ of a
+// lightning address) to the app whose balance receives payments sent to it.
+// Each app has at most one username.
+type LightningAddress struct {
+	ID        uint
+	AppId     uint   `gorm:"uniqueIndex;not null"`
+	App       App    `gorm:"constraint:OnDelete:CASCADE;"`
+	Username  string `gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ZapRequest keeps the exact NIP-57 zap request JSON an LNURL-pay invoice was
+// created for. The invoice's description hash commits to these bytes, so the
+// zap receipt must quote them verbatim rather than a re-serialized event.
+type ZapRequest struct {
+	ID             uint
+	TransactionId  uint        `gorm:"uniqueIndex;not null"`
+	Transaction    Transaction `gorm:"constraint:OnDelete:CASCADE;"`
+	Request        string      `gorm:"not null"`
+	ReceiptEventId string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
 const (
 	REQUEST_EVENT_STATE_HANDLER_EXECUTING = "executing"
 	REQUEST_EVENT_STATE_HANDLER_EXECUTED  = "executed"
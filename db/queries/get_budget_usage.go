@@ -20,7 +20,12 @@ func GetBudgetUsageSat(tx *gorm.DB, appPermission *db.AppPermission) uint64 {
 }
 
 func getStartOfBudget(budget_type string) time.Time {
-	now := time.Now()
+	return GetStartOfBudgetAt(budget_type, time.Now())
+}
+
+// GetStartOfBudgetAt returns when the budget period containing now started,
+// or the zero time for budgets that never renew.
+func GetStartOfBudgetAt(budget_type string, now time.Time) time.Time {
 	switch budget_type {
 	case constants.BUDGET_RENEWAL_DAILY:
 		// TODO: Use the location of the user, instead of the server
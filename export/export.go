@@ -0,0 +1,294 @@
+// Package export writes the hub's settled Lightning payments, on-chain
+// transactions, swaps and forwards for bookkeeping, as CSV or as a Beancount
+// or hledger journal. Rows are read in batches and written in settlement
+// order as they are produced, so exports of any size are streamed.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+const (
+	FormatCSV       = "csv"
+	FormatBeancount = "beancount"
+	FormatHledger   = "hledger"
+)
+
+const (
+	KindLightning = "lightning"
+	KindOnchain   = "onchain"
+	KindSwap      = "swap"
+	KindForward   = "forward"
+)
+
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+)
+
+const lokiPerFlokicoin = 100_000_000
+
+// maxRateAge is the oldest a recorded rate may be when it values a row.
+const maxRateAge = 24 * time.Hour
+
+const batchSize = 500
+
+// Request selects what is exported. From is inclusive and Until exclusive;
+// zero values leave that end of the range open. With AppId set, only that
+// app's Lightning payments are exported, since on-chain transactions, swaps
+// and forwards belong to the node rather than to an app.
+type Request struct {
+	Format       string
+	From         time.Time
+	Until        time.Time
+	AppId        *uint
+	FiatCurrency string
+}
+
+// Row is one exported entry. Amounts are in mloki and AmountMloki excludes
+// fees: a swap's AmountMloki is what came out of it and its FeeMloki the
+// difference to what was sent into it.
+type Row struct {
+	Time         time.Time
+	Kind         string
+	Direction    string
+	AmountMloki  uint64
+	FeeMloki     uint64
+	FeeSkimMloki uint64
+	AppId        *uint
+	AppName      string
+	Reference    string
+	Description  string
+	// FiatRate is the price of one FLC in FiatCurrency when the row settled,
+	// or 0 if no rate was recorded around that time.
+	FiatRate     float64
+	FiatCurrency string
+}
+
+// FiatValue returns the value of mloki at the row's fiat rate.
+func (row *Row) FiatValue(mloki uint64) float64 {
+	return float64(mloki) / 1000 / lokiPerFlokicoin * row.FiatRate
+}
+
+// ValidateFormat returns an error wrapping constants.ErrInvalidParams for
+// unknown formats.
+func ValidateFormat(format string) error {
+	if !slices.Contains([]string{FormatCSV, FormatBeancount, FormatHledger}, format) {
+		return fmt.Errorf("%w: unknown export format %q", constants.ErrInvalidParams, format)
+	}
+	return nil
+}
+
+// ContentType returns the MIME type of an export in format.
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// FileExtension returns the usual file extension of an export in format.
+func FileExtension(format string) string {
+	switch format {
+	case FormatBeancount:
+		return "beancount"
+	case FormatHledger:
+		return "journal"
+	default:
+		return "csv"
+	}
+}
+
+// Export writes the rows selected by req to w. lnClient may be nil, in
+// which case on-chain transactions are left out.
+func Export(ctx context.Context, gormDB *gorm.DB, lnClient lnclient.LNClient, req *Request, w io.Writer) error {
+	if err := ValidateFormat(req.Format); err != nil {
+		return err
+	}
+	if !req.Until.IsZero() && !req.From.Before(req.Until) {
+		return fmt.Errorf("%w: from must be before until", constants.ErrInvalidParams)
+	}
+
+	rates, err := loadRates(gormDB, req)
+	if err != nil {
+		return err
+	}
+
+	sources := []rowSource{}
+	var swapHashes, swapTxIds map[string]bool
+	if req.AppId == nil {
+		var swapRows []Row
+		swapRows, swapHashes, swapTxIds, err = loadSwaps(gormDB, req)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, &sliceSource{rows: swapRows})
+
+		if lnClient != nil {
+			onchainRows, err := loadOnchainTransactions(ctx, lnClient, req, swapTxIds)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, &sliceSource{rows: onchainRows})
+		}
+
+		sources = append(sources, newForwardsSource(gormDB, req))
+	}
+	sources = append(sources, newTransactionsSource(gormDB, req, swapHashes))
+
+	writer := newWriter(req.Format, w)
+	if err := writer.writeHeader(req); err != nil {
+		return err
+	}
+
+	merged, err := newMergedSource(sources)
+	if err != nil {
+		return err
+	}
+	for ctx.Err() == nil {
+		row, err := merged.next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			return writer.flush()
+		}
+		row.FiatCurrency = req.FiatCurrency
+		row.FiatRate = rates.at(row.Time)
+		if err := writer.writeRow(row); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// rateHistory holds recorded rates in ascending order.
+type rateHistory []db.FlokicoinRate
+
+func loadRates(gormDB *gorm.DB, req *Request) (rateHistory, error) {
+	query := gormDB.Where("currency = ?", req.FiatCurrency).Order("created_at ASC")
+	if !req.From.IsZero() {
+		query = query.Where("created_at >= ?", req.From.Add(-maxRateAge))
+	}
+	if !req.Until.IsZero() {
+		query = query.Where("created_at < ?", req.Until)
+	}
+	rates := rateHistory{}
+	if err := query.Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// at returns the latest rate recorded at or before t, or 0 if there is
+// none within maxRateAge.
+func (rates rateHistory) at(t time.Time) float64 {
+	i := sort.Search(len(rates), func(i int) bool {
+		return rates[i].CreatedAt.After(t)
+	})
+	if i == 0 || t.Sub(rates[i-1].CreatedAt) > maxRateAge {
+		return 0
+	}
+	return rates[i-1].Rate
+}
+
+// loadSwaps returns the rows of completed swaps together with the payment
+// hashes and transaction IDs of the Lightning and on-chain legs of every
+// completed swap. The legs are part of their swap's row and are not
+// exported again, even when the swap completed outside the range.
+func loadSwaps(gormDB *gorm.DB, req *Request) ([]Row, map[string]bool, map[string]bool, error) {
+	swaps := []db.Swap{}
+	err := gormDB.Where("state = ?", constants.SWAP_STATE_SUCCESS).
+		Order("updated_at ASC, id ASC").
+		Find(&swaps).Error
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows := make([]Row, 0, len(swaps))
+	paymentHashes := map[string]bool{}
+	txIds := map[string]bool{}
+	for _, swap := range swaps {
+		paymentHashes[swap.PaymentHash] = true
+		txIds[swap.LockupTxId] = true
+		txIds[swap.ClaimTxId] = true
+		if !inTimeRange(swap.UpdatedAt, req) {
+			continue
+		}
+
+		row := Row{
+			Time:        swap.UpdatedAt,
+			Kind:        KindSwap,
+			AmountMloki: swap.ReceiveAmount * 1000,
+			Reference:   swap.SwapId,
+		}
+		if swap.SendAmount > swap.ReceiveAmount {
+			row.FeeMloki = (swap.SendAmount - swap.ReceiveAmount) * 1000
+		}
+		if swap.Type == constants.SWAP_TYPE_OUT {
+			row.Direction = DirectionOutgoing
+			row.Description = fmt.Sprintf("Swap out to %s", swap.DestinationAddress)
+		} else {
+			row.Direction = DirectionIncoming
+			row.Description = "Swap in"
+		}
+		rows = append(rows, row)
+	}
+	delete(paymentHashes, "")
+	delete(txIds, "")
+	return rows, paymentHashes, txIds, nil
+}
+
+func loadOnchainTransactions(ctx context.Context, lnClient lnclient.LNClient, req *Request, skipTxIds map[string]bool) ([]Row, error) {
+	onchainTransactions, err := lnClient.ListOnchainTransactions(ctx, 0, 0, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []Row{}
+	for _, transaction := range onchainTransactions {
+		if transaction.State != "confirmed" || skipTxIds[transaction.TxId] {
+			continue
+		}
+		createdAt := time.Unix(int64(transaction.CreatedAt), 0) //nolint:gosec // LN-node-reported timestamps are far below int64 range
+		if !inTimeRange(createdAt, req) {
+			continue
+		}
+		rows = append(rows, Row{
+			Time:        createdAt,
+			Kind:        KindOnchain,
+			Direction:   transaction.Type,
+			AmountMloki: transaction.AmountLoki * 1000,
+			Reference:   transaction.TxId,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Time.Before(rows[j].Time)
+	})
+	return rows, nil
+}
+
+func whereTimeRange(query *gorm.DB, column string, req *Request) *gorm.DB {
+	if !req.From.IsZero() {
+		query = query.Where(column+" >= ?", req.From)
+	}
+	if !req.Until.IsZero() {
+		query = query.Where(column+" < ?", req.Until)
+	}
+	return query
+}
+
+func inTimeRange(t time.Time, req *Request) bool {
+	return (req.From.IsZero() || !t.Before(req.From)) && (req.Until.IsZero() || t.Before(req.Until))
+}
@@ -0,0 +1,271 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+var exportStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func createSettledTransaction(t *testing.T, svc *tests.TestService, transaction db.Transaction) {
+	settledAt := transaction.CreatedAt
+	if transaction.State == "" {
+		transaction.State = constants.TRANSACTION_STATE_SETTLED
+	}
+	if transaction.State == constants.TRANSACTION_STATE_SETTLED {
+		transaction.SettledAt = &settledAt
+	}
+	require.NoError(t, svc.DB.Create(&transaction).Error)
+}
+
+// createHistory creates one entry of each kind, an hour apart, plus entries
+// that must not be exported.
+func createHistory(t *testing.T, svc *tests.TestService) *db.App {
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DB.Create(&db.FlokicoinRate{Currency: "USD", Rate: 2, CreatedAt: exportStart.Add(-time.Hour)}).Error)
+
+	svc.LNClient.(*tests.MockLn).OnchainTransactions = []lnclient.OnchainTransaction{
+		{AmountLoki: 50_000, CreatedAt: uint64(exportStart.Unix()), State: "confirmed", Type: "incoming", TxId: "deposit"},
+		{AmountLoki: 9_000, CreatedAt: uint64(exportStart.Add(4 * time.Hour).Unix()), State: "confirmed", Type: "incoming", TxId: "claim"},
+		{AmountLoki: 1_000, CreatedAt: uint64(exportStart.Add(5 * time.Hour).Unix()), State: "unconfirmed", Type: "incoming", TxId: "unconfirmed"},
+	}
+
+	createSettledTransaction(t, svc, db.Transaction{
+		AppId:        &app.ID,
+		Type:         constants.TRANSACTION_TYPE_OUTGOING,
+		AmountMloki:  100_000,
+		FeeMloki:     2_000,
+		FeeSkimMloki: 500,
+		PaymentHash:  "outgoing",
+		Description:  "=coffee",
+		CreatedAt:    exportStart.Add(time.Hour),
+	})
+	createSettledTransaction(t, svc, db.Transaction{
+		Type:        constants.TRANSACTION_TYPE_INCOMING,
+		AmountMloki: 30_000,
+		PaymentHash: "incoming",
+		Description: "salary",
+		CreatedAt:   exportStart.Add(2 * time.Hour),
+	})
+	createSettledTransaction(t, svc, db.Transaction{
+		AppId:       &app.ID,
+		Type:        constants.TRANSACTION_TYPE_OUTGOING,
+		State:       constants.TRANSACTION_STATE_FAILED,
+		AmountMloki: 1_000,
+		PaymentHash: "failed",
+		CreatedAt:   exportStart.Add(2 * time.Hour),
+	})
+	// the swap's Lightning payment
+	createSettledTransaction(t, svc, db.Transaction{
+		Type:        constants.TRANSACTION_TYPE_OUTGOING,
+		AmountMloki: 10_000_000,
+		PaymentHash: "swap",
+		CreatedAt:   exportStart.Add(4 * time.Hour),
+	})
+
+	nodeIndex := uint64(1)
+	require.NoError(t, svc.DB.Create(&db.Forward{
+		NodeIndex:                    &nodeIndex,
+		IncomingChannelId:            "1x1x1",
+		OutgoingChannelId:            "2x2x2",
+		OutboundAmountForwardedMloki: 5_000_000,
+		TotalFeeEarnedMloki:          1_500,
+		ForwardedAt:                  exportStart.Add(3 * time.Hour),
+	}).Error)
+
+	require.NoError(t, svc.DB.Create(&db.Swap{
+		SwapId:             "swap-out",
+		Type:               constants.SWAP_TYPE_OUT,
+		State:              constants.SWAP_STATE_SUCCESS,
+		SendAmount:         10_000,
+		ReceiveAmount:      9_000,
+		PaymentHash:        "swap",
+		ClaimTxId:          "claim",
+		DestinationAddress: "fc1qdestination",
+		UpdatedAt:          exportStart.Add(4 * time.Hour),
+	}).Error)
+
+	return app
+}
+
+func exportToString(t *testing.T, svc *tests.TestService, req *Request) string {
+	var out bytes.Buffer
+	require.NoError(t, Export(context.Background(), svc.DB, svc.LNClient, req, &out))
+	return out.String()
+}
+
+func TestExport_CSV(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app := createHistory(t, svc)
+	out := exportToString(t, svc, &Request{Format: FormatCSV, FiatCurrency: "USD"})
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+	assert.Equal(t, csvHeader, records[0])
+
+	assert.Equal(t, []string{
+		"2026-03-01T12:00:00Z", KindOnchain, DirectionIncoming, "50000.000", "0.000", "0.000",
+		"USD", "2", "0.001", "0", "", "", "deposit", "",
+	}, records[1])
+	assert.Equal(t, []string{
+		"2026-03-01T13:00:00Z", KindLightning, DirectionOutgoing, "100.000", "2.000", "0.500",
+		"USD", "2", "0.000002", "0.00000005", strconv.FormatUint(uint64(app.ID), 10), app.Name, "outgoing", "'=coffee",
+	}, records[2])
+	assert.Equal(t, KindLightning, records[3][1])
+	assert.Equal(t, DirectionIncoming, records[3][2])
+	assert.Equal(t, "salary", records[3][13])
+	assert.Equal(t, []string{
+		"2026-03-01T15:00:00Z", KindForward, DirectionIncoming, "1.500", "0.000", "0.000",
+		"USD", "2", "0.00000003", "0", "", "", "1x1x1>2x2x2", "Forwarded 5000.000 loki",
+	}, records[4])
+	// the swap's Lightning payment and claim transaction are part of the swap
+	assert.Equal(t, []string{
+		"2026-03-01T16:00:00Z", KindSwap, DirectionOutgoing, "9000.000", "1000.000", "0.000",
+		"USD", "2", "0.00018", "0.00002", "", "", "swap-out", "Swap out to fc1qdestination",
+	}, records[5])
+}
+
+func TestExport_DateRangeAndApp(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app := createHistory(t, svc)
+
+	out := exportToString(t, svc, &Request{
+		Format: FormatCSV,
+		From:   exportStart.Add(time.Hour),
+		Until:  exportStart.Add(3 * time.Hour),
+	})
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "outgoing", records[1][12])
+	assert.Equal(t, "incoming", records[2][12])
+	// no rate was recorded in the export currency
+	assert.Empty(t, records[1][6])
+	assert.Empty(t, records[1][8])
+
+	out = exportToString(t, svc, &Request{Format: FormatCSV, AppId: &app.ID})
+	records, err = csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "outgoing", records[1][12])
+}
+
+func TestExport_Beancount(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	createHistory(t, svc)
+	out := exportToString(t, svc, &Request{Format: FormatBeancount, FiatCurrency: "USD"})
+
+	assert.Contains(t, out, "option \"operating_currency\" \"USD\"\n")
+	assert.Contains(t, out, "1970-01-01 open Assets:Lokihub:Lightning LOKI\n")
+	assert.Contains(t, out, `2026-03-01 * "Lightning payment sent" "=coffee"
+  time: "13:00:00"
+  type: "lightning"
+  reference: "outgoing"
+  app_id: "1"
+  app_name: "`)
+	assert.Contains(t, out, `  fiat_amount: "0.000002 USD"
+  fiat_fee: "0.00000005 USD"
+  Expenses:Lokihub:Payments        100.000 LOKI
+  Expenses:Lokihub:Fees            2.000 LOKI
+  Expenses:Lokihub:FeeSkims        0.500 LOKI
+  Assets:Lokihub:Lightning         -102.500 LOKI
+`)
+	assert.Contains(t, out, `2026-03-01 * "Swap out" "Swap out to fc1qdestination"
+  time: "16:00:00"
+  type: "swap"
+  reference: "swap-out"
+  fiat_rate: "2 USD"
+  fiat_amount: "0.00018 USD"
+  fiat_fee: "0.00002 USD"
+  Assets:Lokihub:Onchain           9000.000 LOKI
+  Expenses:Lokihub:SwapFees        1000.000 LOKI
+  Assets:Lokihub:Lightning         -10000.000 LOKI
+`)
+}
+
+func TestExport_Hledger(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	createHistory(t, svc)
+	out := exportToString(t, svc, &Request{Format: FormatHledger, FiatCurrency: "USD"})
+
+	assert.Contains(t, out, "commodity 1000.000 LOKI\n")
+	assert.Contains(t, out, `2026-03-01 * Forwarding fee | Forwarded 5000.000 loki
+    ; time: 15:00:00
+    ; type: forward
+    ; reference: 1x1x1>2x2x2
+    ; fiat_rate: 2 USD
+    ; fiat_amount: 0.00000003 USD
+    assets:lokihub:lightning          1.500 LOKI
+    income:lokihub:forwarding         -1.500 LOKI
+`)
+}
+
+func TestExport_Batches(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	// rows settled at the same time must not be skipped or repeated
+	// across batch boundaries
+	for i := 0; i < batchSize+10; i++ {
+		createSettledTransaction(t, svc, db.Transaction{
+			Type:        constants.TRANSACTION_TYPE_INCOMING,
+			AmountMloki: 1000,
+			PaymentHash: strconv.Itoa(i),
+			CreatedAt:   exportStart,
+		})
+	}
+
+	out := exportToString(t, svc, &Request{Format: FormatCSV})
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, batchSize+11)
+
+	seen := map[string]bool{}
+	for _, record := range records[1:] {
+		assert.False(t, seen[record[12]])
+		seen[record[12]] = true
+	}
+}
+
+func TestExport_InvalidParams(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	var out bytes.Buffer
+	err = Export(context.Background(), svc.DB, svc.LNClient, &Request{Format: "xlsx"}, &out)
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+
+	err = Export(context.Background(), svc.DB, svc.LNClient, &Request{Format: FormatCSV, From: exportStart, Until: exportStart}, &out)
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,191 @@
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+)
+
+// rowSource yields rows in ascending time order and nil once exhausted.
+type rowSource interface {
+	next() (*Row, error)
+}
+
+type sliceSource struct {
+	rows []Row
+}
+
+func (source *sliceSource) next() (*Row, error) {
+	if len(source.rows) == 0 {
+		return nil, nil
+	}
+	row := &source.rows[0]
+	source.rows = source.rows[1:]
+	return row, nil
+}
+
+// batchedSource reads rows batchSize at a time, continuing after the time
+// and ID of the last row read so no row is skipped or read twice.
+type batchedSource struct {
+	rows     []Row
+	lastTime time.Time
+	lastId   uint
+	started  bool
+	done     bool
+	// load reads the batch following lastTime and lastId, returning the
+	// rows of the batch and the number of records read.
+	load func(lastTime time.Time, lastId uint, started bool) ([]Row, int, error)
+}
+
+func (source *batchedSource) next() (*Row, error) {
+	for len(source.rows) == 0 {
+		if source.done {
+			return nil, nil
+		}
+		rows, read, err := source.load(source.lastTime, source.lastId, source.started)
+		if err != nil {
+			return nil, err
+		}
+		source.started = true
+		source.rows = rows
+		if read < batchSize {
+			source.done = true
+		}
+	}
+	row := &source.rows[0]
+	source.rows = source.rows[1:]
+	return row, nil
+}
+
+// afterCursor restricts query to records after the given time and ID in
+// (column, id) order.
+func afterCursor(query *gorm.DB, column string, lastTime time.Time, lastId uint, started bool) *gorm.DB {
+	if !started {
+		return query
+	}
+	return query.Where(column+" > ? OR ("+column+" = ? AND id > ?)", lastTime, lastTime, lastId)
+}
+
+func newTransactionsSource(gormDB *gorm.DB, req *Request, skipHashes map[string]bool) *batchedSource {
+	source := &batchedSource{}
+	source.load = func(lastTime time.Time, lastId uint, started bool) ([]Row, int, error) {
+		query := gormDB.Preload("App").
+			Where("state = ?", constants.TRANSACTION_STATE_SETTLED).
+			Order("settled_at ASC, id ASC").
+			Limit(batchSize)
+		query = whereTimeRange(query, "settled_at", req)
+		if req.AppId != nil {
+			query = query.Where("app_id = ?", *req.AppId)
+		}
+		query = afterCursor(query, "settled_at", lastTime, lastId, started)
+
+		transactions := []db.Transaction{}
+		if err := query.Find(&transactions).Error; err != nil {
+			return nil, 0, err
+		}
+
+		rows := make([]Row, 0, len(transactions))
+		for _, transaction := range transactions {
+			source.lastTime = *transaction.SettledAt
+			source.lastId = transaction.ID
+			if skipHashes[transaction.PaymentHash] {
+				continue
+			}
+			row := Row{
+				Time:         *transaction.SettledAt,
+				Kind:         KindLightning,
+				Direction:    transaction.Type,
+				AmountMloki:  transaction.AmountMloki,
+				FeeSkimMloki: transaction.FeeSkimMloki,
+				AppId:        transaction.AppId,
+				Reference:    transaction.PaymentHash,
+				Description:  transaction.Description,
+			}
+			if transaction.Type == constants.TRANSACTION_TYPE_OUTGOING {
+				row.FeeMloki = transaction.FeeMloki
+			}
+			if transaction.App != nil {
+				row.AppName = transaction.App.Name
+			}
+			rows = append(rows, row)
+		}
+		return rows, len(transactions), nil
+	}
+	return source
+}
+
+func newForwardsSource(gormDB *gorm.DB, req *Request) *batchedSource {
+	source := &batchedSource{}
+	source.load = func(lastTime time.Time, lastId uint, started bool) ([]Row, int, error) {
+		query := gormDB.Order("forwarded_at ASC, id ASC").Limit(batchSize)
+		query = whereTimeRange(query, "forwarded_at", req)
+		query = afterCursor(query, "forwarded_at", lastTime, lastId, started)
+
+		forwards := []db.Forward{}
+		if err := query.Find(&forwards).Error; err != nil {
+			return nil, 0, err
+		}
+
+		rows := make([]Row, 0, len(forwards))
+		for _, forward := range forwards {
+			source.lastTime = forward.ForwardedAt
+			source.lastId = forward.ID
+			// the earned fee is the only change to the node's balance
+			rows = append(rows, Row{
+				Time:        forward.ForwardedAt,
+				Kind:        KindForward,
+				Direction:   DirectionIncoming,
+				AmountMloki: forward.TotalFeeEarnedMloki,
+				Reference:   fmt.Sprintf("%s>%s", forward.IncomingChannelId, forward.OutgoingChannelId),
+				Description: fmt.Sprintf("Forwarded %s loki", formatMloki(forward.OutboundAmountForwardedMloki)),
+			})
+		}
+		return rows, len(forwards), nil
+	}
+	return source
+}
+
+// mergedSource yields the rows of all its sources in ascending time order.
+type mergedSource struct {
+	sources []rowSource
+	heads   []*Row
+}
+
+func newMergedSource(sources []rowSource) (*mergedSource, error) {
+	merged := &mergedSource{
+		sources: sources,
+		heads:   make([]*Row, len(sources)),
+	}
+	for i, source := range sources {
+		head, err := source.next()
+		if err != nil {
+			return nil, err
+		}
+		merged.heads[i] = head
+	}
+	return merged, nil
+}
+
+func (merged *mergedSource) next() (*Row, error) {
+	earliest := -1
+	for i, head := range merged.heads {
+		if head != nil && (earliest == -1 || head.Time.Before(merged.heads[earliest].Time)) {
+			earliest = i
+		}
+	}
+	if earliest == -1 {
+		return nil, nil
+	}
+
+	row := merged.heads[earliest]
+	head, err := merged.sources[earliest].next()
+	if err != nil {
+		return nil, err
+	}
+	merged.heads[earliest] = head
+	return row, nil
+}
@@ -0,0 +1,300 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ledgerCommodity is the commodity of journal amounts, which are written in
+// loki with mloki precision.
+const ledgerCommodity = "LOKI"
+
+type rowWriter interface {
+	writeHeader(req *Request) error
+	writeRow(row *Row) error
+	flush() error
+}
+
+func newWriter(format string, w io.Writer) rowWriter {
+	switch format {
+	case FormatBeancount:
+		return &ledgerWriter{w: bufio.NewWriter(w), beancount: true}
+	case FormatHledger:
+		return &ledgerWriter{w: bufio.NewWriter(w)}
+	default:
+		return &csvWriter{w: csv.NewWriter(w)}
+	}
+}
+
+var csvHeader = []string{
+	"time", "type", "direction", "amount_loki", "fee_loki", "fee_skim_loki",
+	"fiat_currency", "fiat_rate", "fiat_amount", "fiat_fee",
+	"app_id", "app_name", "reference", "description",
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (writer *csvWriter) writeHeader(req *Request) error {
+	return writer.w.Write(csvHeader)
+}
+
+func (writer *csvWriter) writeRow(row *Row) error {
+	var fiatCurrency, fiatRate, fiatAmount, fiatFee string
+	if row.FiatRate > 0 {
+		fiatCurrency = row.FiatCurrency
+		fiatRate = formatFiat(row.FiatRate)
+		fiatAmount = formatFiat(row.FiatValue(row.AmountMloki))
+		fiatFee = formatFiat(row.FiatValue(row.FeeMloki + row.FeeSkimMloki))
+	}
+	var appId string
+	if row.AppId != nil {
+		appId = strconv.FormatUint(uint64(*row.AppId), 10)
+	}
+
+	return writer.w.Write([]string{
+		row.Time.UTC().Format(time.RFC3339),
+		row.Kind,
+		row.Direction,
+		formatMloki(row.AmountMloki),
+		formatMloki(row.FeeMloki),
+		formatMloki(row.FeeSkimMloki),
+		fiatCurrency,
+		fiatRate,
+		fiatAmount,
+		fiatFee,
+		appId,
+		csvText(row.AppName),
+		csvText(row.Reference),
+		csvText(row.Description),
+	})
+}
+
+func (writer *csvWriter) flush() error {
+	writer.w.Flush()
+	return writer.w.Error()
+}
+
+// csvText keeps spreadsheet applications from evaluating free text as a
+// formula.
+func csvText(text string) string {
+	if text != "" && strings.ContainsAny(text[:1], "=+-@\t\r") {
+		return "'" + text
+	}
+	return text
+}
+
+const (
+	accountLightning  = "Assets:Lokihub:Lightning"
+	accountOnchain    = "Assets:Lokihub:Onchain"
+	accountIncome     = "Income:Lokihub:Payments"
+	accountIncomeOn   = "Income:Lokihub:Onchain"
+	accountForwarding = "Income:Lokihub:Forwarding"
+	accountExpenses   = "Expenses:Lokihub:Payments"
+	accountExpensesOn = "Expenses:Lokihub:Onchain"
+	accountFees       = "Expenses:Lokihub:Fees"
+	accountFeeSkims   = "Expenses:Lokihub:FeeSkims"
+	accountSwapFees   = "Expenses:Lokihub:SwapFees"
+)
+
+var ledgerAccounts = []string{
+	accountLightning, accountOnchain,
+	accountIncome, accountIncomeOn, accountForwarding,
+	accountExpenses, accountExpensesOn, accountFees, accountFeeSkims, accountSwapFees,
+}
+
+type posting struct {
+	account string
+	mloki   int64
+}
+
+// ledgerWriter writes a Beancount or hledger journal. Both share the same
+// accounts and entries and differ only in syntax.
+type ledgerWriter struct {
+	w         *bufio.Writer
+	beancount bool
+}
+
+func (writer *ledgerWriter) writeHeader(req *Request) error {
+	if !writer.beancount {
+		fmt.Fprintf(writer.w, "; Lokihub export\n\ncommodity 1000.000 %s\n\n", ledgerCommodity)
+		return nil
+	}
+
+	openDate := "1970-01-01"
+	if !req.From.IsZero() {
+		openDate = req.From.UTC().Format(time.DateOnly)
+	}
+	fmt.Fprintf(writer.w, "option \"title\" \"Lokihub export\"\n")
+	if req.FiatCurrency != "" {
+		fmt.Fprintf(writer.w, "option \"operating_currency\" %s\n", beancountString(req.FiatCurrency))
+	}
+	fmt.Fprintf(writer.w, "\n%s commodity %s\n", openDate, ledgerCommodity)
+	for _, account := range ledgerAccounts {
+		fmt.Fprintf(writer.w, "%s open %s %s\n", openDate, account, ledgerCommodity)
+	}
+	fmt.Fprintln(writer.w)
+	return nil
+}
+
+func (writer *ledgerWriter) writeRow(row *Row) error {
+	postings := ledgerPostings(row)
+	if len(postings) == 0 {
+		return nil
+	}
+
+	date := row.Time.UTC().Format(time.DateOnly)
+	metadata := [][2]string{
+		{"time", row.Time.UTC().Format(time.TimeOnly)},
+		{"type", row.Kind},
+		{"reference", row.Reference},
+	}
+	if row.AppId != nil {
+		metadata = append(metadata,
+			[2]string{"app_id", strconv.FormatUint(uint64(*row.AppId), 10)},
+			[2]string{"app_name", row.AppName})
+	}
+	if row.FiatRate > 0 {
+		metadata = append(metadata,
+			[2]string{"fiat_rate", formatFiat(row.FiatRate) + " " + row.FiatCurrency},
+			[2]string{"fiat_amount", formatFiat(row.FiatValue(row.AmountMloki)) + " " + row.FiatCurrency})
+		if fee := row.FeeMloki + row.FeeSkimMloki; fee > 0 {
+			metadata = append(metadata,
+				[2]string{"fiat_fee", formatFiat(row.FiatValue(fee)) + " " + row.FiatCurrency})
+		}
+	}
+
+	if writer.beancount {
+		fmt.Fprintf(writer.w, "%s * %s %s\n", date, beancountString(rowTitle(row)), beancountString(row.Description))
+		for _, entry := range metadata {
+			if entry[1] != "" {
+				fmt.Fprintf(writer.w, "  %s: %s\n", entry[0], beancountString(entry[1]))
+			}
+		}
+		for _, posting := range postings {
+			fmt.Fprintf(writer.w, "  %-32s %s %s\n", posting.account, formatSignedMloki(posting.mloki), ledgerCommodity)
+		}
+	} else {
+		title := rowTitle(row)
+		if description := hledgerText(row.Description); description != "" {
+			title += " | " + description
+		}
+		fmt.Fprintf(writer.w, "%s * %s\n", date, title)
+		for _, entry := range metadata {
+			if entry[1] != "" {
+				fmt.Fprintf(writer.w, "    ; %s: %s\n", entry[0], hledgerText(entry[1]))
+			}
+		}
+		for _, posting := range postings {
+			fmt.Fprintf(writer.w, "    %-32s  %s %s\n", strings.ToLower(posting.account), formatSignedMloki(posting.mloki), ledgerCommodity)
+		}
+	}
+	_, err := fmt.Fprintln(writer.w)
+	return err
+}
+
+func (writer *ledgerWriter) flush() error {
+	return writer.w.Flush()
+}
+
+// ledgerPostings returns the balanced postings of a row, leaving out zero
+// amounts, or none if nothing changed hands.
+func ledgerPostings(row *Row) []posting {
+	amount := int64(row.AmountMloki)   //nolint:gosec // mloki amounts are far below int64 range
+	fee := int64(row.FeeMloki)         //nolint:gosec // mloki amounts are far below int64 range
+	feeSkim := int64(row.FeeSkimMloki) //nolint:gosec // mloki amounts are far below int64 range
+	incoming := row.Direction == DirectionIncoming
+
+	var postings []posting
+	switch row.Kind {
+	case KindLightning:
+		if incoming {
+			postings = []posting{{accountLightning, amount}, {accountIncome, -amount}}
+		} else {
+			postings = []posting{{accountExpenses, amount}, {accountFees, fee}, {accountFeeSkims, feeSkim}, {accountLightning, -(amount + fee + feeSkim)}}
+		}
+	case KindOnchain:
+		if incoming {
+			postings = []posting{{accountOnchain, amount}, {accountIncomeOn, -amount}}
+		} else {
+			postings = []posting{{accountExpensesOn, amount}, {accountOnchain, -amount}}
+		}
+	case KindSwap:
+		if incoming {
+			postings = []posting{{accountLightning, amount}, {accountSwapFees, fee}, {accountOnchain, -(amount + fee)}}
+		} else {
+			postings = []posting{{accountOnchain, amount}, {accountSwapFees, fee}, {accountLightning, -(amount + fee)}}
+		}
+	case KindForward:
+		postings = []posting{{accountLightning, amount}, {accountForwarding, -amount}}
+	}
+
+	nonZero := []posting{}
+	for _, posting := range postings {
+		if posting.mloki != 0 {
+			nonZero = append(nonZero, posting)
+		}
+	}
+	return nonZero
+}
+
+func rowTitle(row *Row) string {
+	incoming := row.Direction == DirectionIncoming
+	switch row.Kind {
+	case KindLightning:
+		if incoming {
+			return "Lightning payment received"
+		}
+		return "Lightning payment sent"
+	case KindOnchain:
+		if incoming {
+			return "On-chain deposit"
+		}
+		return "On-chain withdrawal"
+	case KindSwap:
+		if incoming {
+			return "Swap in"
+		}
+		return "Swap out"
+	case KindForward:
+		return "Forwarding fee"
+	}
+	return row.Kind
+}
+
+func beancountString(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+	return `"` + text + `"`
+}
+
+// hledgerText keeps free text on one line and out of comments.
+func hledgerText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, ";", ",")
+}
+
+// formatMloki formats an mloki amount in loki, keeping mloki precision.
+func formatMloki(mloki uint64) string {
+	return fmt.Sprintf("%d.%03d", mloki/1000, mloki%1000)
+}
+
+func formatSignedMloki(mloki int64) string {
+	if mloki < 0 {
+		return "-" + formatMloki(uint64(-mloki))
+	}
+	return formatMloki(uint64(mloki))
+}
+
+func formatFiat(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', 8, 64)
+	formatted = strings.TrimRight(formatted, "0")
+	return strings.TrimSuffix(formatted, ".")
+}
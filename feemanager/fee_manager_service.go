@@ -0,0 +1,317 @@
+// Package feemanager adjusts the forwarding fees of the node's public
+// channels. Each channel's fee rate follows its local balance share, so
+// channels running out of outbound liquidity get more expensive and full
+// ones get cheaper, and is nudged by the channel's recent outbound flow. Fees
+// stay within the configured bounds and every change is recorded.
+package feemanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+const (
+	defaultIntervalMinutes = 60
+	minIntervalMinutes     = 10
+	defaultFlowWindowHours = 24
+	// idleDiscount lowers the fee of channels that forwarded nothing
+	// recently, to attract some flow
+	idleDiscount = 0.9
+	// maxFlowPremium is the most the fee rises for a channel that forwarded
+	// its whole capacity out within the flow window
+	maxFlowPremium = 0.5
+	// minChangePercent keeps small adjustments from flooding the gossip
+	// network with channel updates
+	minChangePercent = 5
+)
+
+// Config is the fee manager configuration. Channels listed in
+// ExcludedChannelIds keep the fees set by hand.
+type Config struct {
+	Enabled            bool     `json:"enabled"`
+	MinFeePpm          uint32   `json:"minFeePpm"`
+	MaxFeePpm          uint32   `json:"maxFeePpm"`
+	BaseFeeMloki       uint32   `json:"baseFeeMloki"`
+	IntervalMinutes    uint32   `json:"intervalMinutes"`
+	FlowWindowHours    uint32   `json:"flowWindowHours"`
+	ExcludedChannelIds []string `json:"excludedChannelIds"`
+}
+
+// LoadConfig returns the saved fee manager config, or a disabled config if
+// none was saved
+func LoadConfig(cfg config.Config) (*Config, error) {
+	feeManagerConfig := &Config{
+		IntervalMinutes:    defaultIntervalMinutes,
+		FlowWindowHours:    defaultFlowWindowHours,
+		ExcludedChannelIds: []string{},
+	}
+	value, err := cfg.Get(config.ChannelFeeManagerConfigKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return feeManagerConfig, nil
+	}
+	if err := json.Unmarshal([]byte(value), feeManagerConfig); err != nil {
+		return nil, fmt.Errorf("invalid channel fee manager config: %w", err)
+	}
+	return feeManagerConfig, nil
+}
+
+// SaveConfig validates and saves the fee manager config
+func SaveConfig(cfg config.Config, feeManagerConfig *Config) error {
+	if feeManagerConfig.MaxFeePpm < feeManagerConfig.MinFeePpm {
+		return fmt.Errorf("%w: max fee rate must not be below min fee rate", constants.ErrInvalidParams)
+	}
+	if feeManagerConfig.Enabled && feeManagerConfig.MaxFeePpm == 0 {
+		return fmt.Errorf("%w: max fee rate must be set", constants.ErrInvalidParams)
+	}
+	if feeManagerConfig.IntervalMinutes < minIntervalMinutes {
+		return fmt.Errorf("%w: interval must be at least %d minutes", constants.ErrInvalidParams, minIntervalMinutes)
+	}
+	if feeManagerConfig.FlowWindowHours == 0 {
+		return fmt.Errorf("%w: flow window must be set", constants.ErrInvalidParams)
+	}
+
+	normalized := *feeManagerConfig
+	normalized.ExcludedChannelIds = []string{}
+	for _, channelId := range feeManagerConfig.ExcludedChannelIds {
+		if channelId != "" && !slices.Contains(normalized.ExcludedChannelIds, channelId) {
+			normalized.ExcludedChannelIds = append(normalized.ExcludedChannelIds, channelId)
+		}
+	}
+	value, err := json.Marshal(&normalized)
+	if err != nil {
+		return err
+	}
+	return cfg.SetUpdate(config.ChannelFeeManagerConfigKey, string(value), "")
+}
+
+type FeeManagerService interface {
+	// EnableFeeManager (re)starts adjusting fees with the saved config. It
+	// does nothing if the fee manager is disabled.
+	EnableFeeManager() error
+	StopFeeManager()
+	// ListFeeUpdates returns past fee changes, newest first, optionally of a
+	// single channel, and their total count
+	ListFeeUpdates(channelId string, limit, offset uint64) ([]db.ChannelFeeUpdate, uint64, error)
+}
+
+type feeManagerService struct {
+	ctx      context.Context
+	db       *gorm.DB
+	cfg      config.Config
+	lnClient lnclient.LNClient
+
+	mu       sync.Mutex
+	cancelFn context.CancelFunc
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func NewFeeManagerService(ctx context.Context, db *gorm.DB, cfg config.Config, lnClient lnclient.LNClient) FeeManagerService {
+	svc := &feeManagerService{
+		ctx:      ctx,
+		db:       db,
+		cfg:      cfg,
+		lnClient: lnClient,
+		now:      time.Now,
+	}
+	if err := svc.EnableFeeManager(); err != nil {
+		logger.Logger.Error().Err(err).Msg("Couldn't enable channel fee manager")
+	}
+	return svc
+}
+
+func (svc *feeManagerService) StopFeeManager() {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.stop()
+}
+
+func (svc *feeManagerService) stop() {
+	if svc.cancelFn != nil {
+		svc.cancelFn()
+		svc.cancelFn = nil
+		logger.Logger.Info().Msg("Channel fee manager stopped")
+	}
+}
+
+func (svc *feeManagerService) EnableFeeManager() error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.stop()
+
+	feeManagerConfig, err := LoadConfig(svc.cfg)
+	if err != nil {
+		return err
+	}
+	if !feeManagerConfig.Enabled {
+		logger.Logger.Info().Msg("Channel fee manager not enabled")
+		return nil
+	}
+
+	ctx, cancelFn := context.WithCancel(svc.ctx)
+	svc.cancelFn = cancelFn
+
+	logger.Logger.Info().Msg("Starting channel fee manager")
+
+	go func() {
+		for {
+			select {
+			case <-time.After(time.Duration(feeManagerConfig.IntervalMinutes) * time.Minute):
+				if err := svc.adjustFees(ctx, feeManagerConfig); err != nil {
+					logger.Logger.Error().Err(err).Msg("Failed to adjust channel fees")
+				}
+			case <-ctx.Done():
+				logger.Logger.Info().Msg("Stopping channel fee manager")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (svc *feeManagerService) ListFeeUpdates(channelId string, limit, offset uint64) ([]db.ChannelFeeUpdate, uint64, error) {
+	query := svc.db.Model(&db.ChannelFeeUpdate{})
+	if channelId != "" {
+		query = query.Where("channel_id = ?", channelId)
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(int(limit)).Offset(int(offset)) //nolint:gosec // page sizes are small
+	}
+	feeUpdates := []db.ChannelFeeUpdate{}
+	if err := query.Find(&feeUpdates).Error; err != nil {
+		return nil, 0, err
+	}
+	return feeUpdates, uint64(totalCount), nil //nolint:gosec // counts are never negative
+}
+
+// adjustFees updates the fees of every active public channel that is not
+// excluded and whose target fee moved far enough from the current one
+func (svc *feeManagerService) adjustFees(ctx context.Context, feeManagerConfig *Config) error {
+	channels, err := svc.lnClient.ListChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list channels: %w", err)
+	}
+	outboundFlows, err := svc.outboundFlowsSince(svc.now().Add(-time.Duration(feeManagerConfig.FlowWindowHours) * time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to sum recent forwards: %w", err)
+	}
+
+	for _, channel := range channels {
+		if !channel.Active || !channel.Public || slices.Contains(feeManagerConfig.ExcludedChannelIds, channel.Id) {
+			continue
+		}
+		capacity := channel.LocalBalance + channel.RemoteBalance
+		if capacity <= 0 {
+			continue
+		}
+
+		outboundFlow := outboundFlows[channel.Id]
+		feePpm := targetFeePpm(feeManagerConfig, channel.LocalBalance, channel.RemoteBalance, outboundFlow)
+		if channel.ForwardingFeeBaseMloki == feeManagerConfig.BaseFeeMloki && !feeChanged(channel.ForwardingFeeProportionalMillionths, feePpm) {
+			continue
+		}
+
+		err := svc.lnClient.UpdateChannel(ctx, &lnclient.UpdateChannelRequest{
+			ChannelId:                           channel.Id,
+			NodeId:                              channel.RemotePubkey,
+			ForwardingFeeBaseMloki:              feeManagerConfig.BaseFeeMloki,
+			ForwardingFeeProportionalMillionths: feePpm,
+		})
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("channel_id", channel.Id).Msg("Failed to update channel fees")
+			continue
+		}
+
+		feeUpdate := &db.ChannelFeeUpdate{
+			ChannelId:           channel.Id,
+			PeerPubkey:          channel.RemotePubkey,
+			OldBaseFeeMloki:     channel.ForwardingFeeBaseMloki,
+			NewBaseFeeMloki:     feeManagerConfig.BaseFeeMloki,
+			OldFeePpm:           channel.ForwardingFeeProportionalMillionths,
+			NewFeePpm:           feePpm,
+			LocalBalancePercent: uint32(max(channel.LocalBalance, 0) * 100 / capacity), //nolint:gosec // at most 100
+			OutboundFlowMloki:   outboundFlow,
+		}
+		if err := svc.db.Create(feeUpdate).Error; err != nil {
+			logger.Logger.Error().Err(err).Str("channel_id", channel.Id).Msg("Failed to save channel fee update")
+		}
+		logger.Logger.Info().
+			Str("channel_id", channel.Id).
+			Uint32("old_fee_ppm", feeUpdate.OldFeePpm).
+			Uint32("new_fee_ppm", feeUpdate.NewFeePpm).
+			Uint32("local_balance_percent", feeUpdate.LocalBalancePercent).
+			Msg("Updated channel fees")
+	}
+	return nil
+}
+
+// outboundFlowsSince sums the amounts forwarded out of each channel since the
+// given time, in mloki
+func (svc *feeManagerService) outboundFlowsSince(since time.Time) (map[string]uint64, error) {
+	var rows []struct {
+		OutgoingChannelId string
+		Total             uint64
+	}
+	err := svc.db.Model(&db.Forward{}).
+		Select("outgoing_channel_id, COALESCE(SUM(outbound_amount_forwarded_mloki), 0) AS total").
+		Where("forwarded_at >= ?", since).
+		Group("outgoing_channel_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		flows[row.OutgoingChannelId] = row.Total
+	}
+	return flows, nil
+}
+
+// targetFeePpm scales the fee rate from MaxFeePpm for an empty channel down
+// to MinFeePpm for a full one. Channels without recent outbound forwards get
+// a discount, and busy ones a premium of up to maxFlowPremium.
+func targetFeePpm(feeManagerConfig *Config, localBalance, remoteBalance int64, outboundFlowMloki uint64) uint32 {
+	capacity := float64(localBalance + remoteBalance)
+	localShare := min(max(float64(localBalance)/capacity, 0), 1)
+	minFee := float64(feeManagerConfig.MinFeePpm)
+	maxFee := float64(feeManagerConfig.MaxFeePpm)
+
+	fee := maxFee - (maxFee-minFee)*localShare
+	if outboundFlowMloki == 0 {
+		fee *= idleDiscount
+	} else {
+		fee *= 1 + maxFlowPremium*min(float64(outboundFlowMloki)/capacity, 1)
+	}
+	return uint32(min(max(fee, minFee), maxFee))
+}
+
+// feeChanged reports whether the target fee differs enough from the current
+// one to be worth a channel update
+func feeChanged(currentFeePpm, targetFeePpm uint32) bool {
+	diff := max(currentFeePpm, targetFeePpm) - min(currentFeePpm, targetFeePpm)
+	return diff > 0 && diff*100 >= currentFeePpm*minChangePercent
+}
@@ -0,0 +1,191 @@
+package feemanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+// channelsLn serves a fixed channel list and records channel updates
+type channelsLn struct {
+	*tests.MockLn
+	channels []lnclient.Channel
+	updates  []lnclient.UpdateChannelRequest
+}
+
+func (ln *channelsLn) ListChannels(ctx context.Context) ([]lnclient.Channel, error) {
+	return ln.channels, nil
+}
+
+func (ln *channelsLn) UpdateChannel(ctx context.Context, updateChannelRequest *lnclient.UpdateChannelRequest) error {
+	ln.updates = append(ln.updates, *updateChannelRequest)
+	return nil
+}
+
+func publicChannel(id string, localMloki, remoteMloki int64, feePpm uint32) lnclient.Channel {
+	return lnclient.Channel{
+		Id:                                  id,
+		RemotePubkey:                        "02peer" + id,
+		LocalBalance:                        localMloki,
+		RemoteBalance:                       remoteMloki,
+		Active:                              true,
+		Public:                              true,
+		ForwardingFeeBaseMloki:              1_000,
+		ForwardingFeeProportionalMillionths: feePpm,
+	}
+}
+
+func newTestFeeManagerService(t *testing.T, channels ...lnclient.Channel) (*feeManagerService, *channelsLn) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	t.Cleanup(svc.Remove)
+	ln := &channelsLn{MockLn: svc.LNClient.(*tests.MockLn), channels: channels}
+	return &feeManagerService{
+		ctx:      context.Background(),
+		db:       svc.DB,
+		cfg:      svc.Cfg,
+		lnClient: ln,
+		now:      time.Now,
+	}, ln
+}
+
+var testConfig = &Config{
+	Enabled:         true,
+	MinFeePpm:       100,
+	MaxFeePpm:       1_100,
+	BaseFeeMloki:    1_000,
+	IntervalMinutes: 60,
+	FlowWindowHours: 24,
+}
+
+func TestTargetFeePpm(t *testing.T) {
+	testCases := []struct {
+		name         string
+		local        int64
+		remote       int64
+		outboundFlow uint64
+		expected     uint32
+	}{
+		{"empty and idle", 0, 1_000_000, 0, 990},
+		{"empty and busy", 0, 1_000_000, 1_000_000, 1_100},
+		{"half full and idle", 500_000, 500_000, 0, 540},
+		{"half full with some flow", 500_000, 500_000, 200_000, 660},
+		{"full and idle", 1_000_000, 0, 0, 100},
+		{"full and busy", 1_000_000, 0, 2_000_000, 150},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, targetFeePpm(testConfig, tc.local, tc.remote, tc.outboundFlow))
+		})
+	}
+}
+
+func TestFeeChanged(t *testing.T) {
+	assert.False(t, feeChanged(500, 500))
+	assert.False(t, feeChanged(500, 524))
+	assert.True(t, feeChanged(500, 525))
+	assert.True(t, feeChanged(500, 475))
+	assert.True(t, feeChanged(0, 1))
+}
+
+func TestAdjustFees(t *testing.T) {
+	private := publicChannel("private", 0, 1_000_000, 0)
+	private.Public = false
+	inactive := publicChannel("inactive", 0, 1_000_000, 0)
+	inactive.Active = false
+	svc, ln := newTestFeeManagerService(t,
+		publicChannel("depleted", 0, 1_000_000, 500),
+		publicChannel("unchanged", 500_000, 500_000, 540),
+		publicChannel("excluded", 0, 1_000_000, 500),
+		private,
+		inactive,
+	)
+
+	forwardedAt := time.Now().Add(-time.Hour)
+	forwards := []db.Forward{
+		{OutgoingChannelId: "depleted", OutboundAmountForwardedMloki: 400_000, ForwardedAt: forwardedAt},
+		{OutgoingChannelId: "depleted", OutboundAmountForwardedMloki: 600_000, ForwardedAt: forwardedAt},
+		{OutgoingChannelId: "depleted", OutboundAmountForwardedMloki: 5_000_000, ForwardedAt: time.Now().Add(-48 * time.Hour)},
+	}
+	require.NoError(t, svc.db.Create(&forwards).Error)
+
+	feeManagerConfig := *testConfig
+	feeManagerConfig.ExcludedChannelIds = []string{"excluded"}
+	require.NoError(t, svc.adjustFees(context.Background(), &feeManagerConfig))
+
+	require.Len(t, ln.updates, 1)
+	assert.Equal(t, lnclient.UpdateChannelRequest{
+		ChannelId:                           "depleted",
+		NodeId:                              "02peerdepleted",
+		ForwardingFeeBaseMloki:              1_000,
+		ForwardingFeeProportionalMillionths: 1_100,
+	}, ln.updates[0])
+
+	feeUpdates, totalCount, err := svc.ListFeeUpdates("", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), totalCount)
+	require.Len(t, feeUpdates, 1)
+	assert.Equal(t, uint32(500), feeUpdates[0].OldFeePpm)
+	assert.Equal(t, uint32(1_100), feeUpdates[0].NewFeePpm)
+	assert.Equal(t, uint32(0), feeUpdates[0].LocalBalancePercent)
+	assert.Equal(t, uint64(1_000_000), feeUpdates[0].OutboundFlowMloki)
+
+	feeUpdates, totalCount, err = svc.ListFeeUpdates("unchanged", 10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, totalCount)
+	assert.Empty(t, feeUpdates)
+}
+
+func TestAdjustFees_BaseFeeChange(t *testing.T) {
+	svc, ln := newTestFeeManagerService(t, publicChannel("1", 500_000, 500_000, 540))
+
+	feeManagerConfig := *testConfig
+	feeManagerConfig.BaseFeeMloki = 0
+	require.NoError(t, svc.adjustFees(context.Background(), &feeManagerConfig))
+
+	require.Len(t, ln.updates, 1)
+	assert.Equal(t, uint32(0), ln.updates[0].ForwardingFeeBaseMloki)
+	assert.Equal(t, uint32(540), ln.updates[0].ForwardingFeeProportionalMillionths)
+}
+
+func TestSaveConfig(t *testing.T) {
+	svc, _ := newTestFeeManagerService(t)
+
+	feeManagerConfig, err := LoadConfig(svc.cfg)
+	require.NoError(t, err)
+	assert.False(t, feeManagerConfig.Enabled)
+	assert.Equal(t, uint32(defaultIntervalMinutes), feeManagerConfig.IntervalMinutes)
+
+	invalid := []Config{
+		{Enabled: true, MinFeePpm: 500, MaxFeePpm: 100, IntervalMinutes: 60, FlowWindowHours: 24},
+		{Enabled: true, IntervalMinutes: 60, FlowWindowHours: 24},
+		{Enabled: true, MaxFeePpm: 100, IntervalMinutes: 5, FlowWindowHours: 24},
+		{Enabled: true, MaxFeePpm: 100, IntervalMinutes: 60},
+	}
+	for _, c := range invalid {
+		assert.Error(t, SaveConfig(svc.cfg, &c))
+	}
+
+	feeManagerConfig = &Config{
+		Enabled:            true,
+		MinFeePpm:          10,
+		MaxFeePpm:          2_000,
+		IntervalMinutes:    30,
+		FlowWindowHours:    12,
+		ExcludedChannelIds: []string{"1", "", "1", "2"},
+	}
+	require.NoError(t, SaveConfig(svc.cfg, feeManagerConfig))
+
+	saved, err := LoadConfig(svc.cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, saved.ExcludedChannelIds)
+	assert.Equal(t, uint32(2_000), saved.MaxFeePpm)
+	assert.Equal(t, uint32(30), saved.IntervalMinutes)
+}
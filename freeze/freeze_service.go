@@ -0,0 +1,156 @@
+// Package freeze implements the emergency freeze of the hub. When a
+// connection secret leaks, freezing stops every NIP-47 connection from doing
+// anything but get_info at once, and can also pause outgoing payments from
+// the admin API. Single apps can be frozen as well.
+package freeze
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/approvals"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+// Sources of a freeze or unfreeze. Admin Nostr DMs use SourceNostrPrefix
+// followed by the sender's pubkey.
+const (
+	SourceApi         = "api"
+	SourceTray        = "tray"
+	SourceNostrPrefix = "nostr:"
+)
+
+var (
+	ErrOutgoingPaymentsPaused = errors.New("outgoing payments are paused by an emergency freeze")
+	ErrAppNotFound            = errors.New("app not found")
+)
+
+type FreezeService interface {
+	// GetState returns the freeze state, which is unfrozen if the hub has
+	// never been frozen.
+	GetState() (*db.HubFreeze, error)
+	// Freeze freezes the hub and rejects the payments held for approval.
+	// Freezing an already frozen hub can pause, but never resume, outgoing
+	// payments.
+	Freeze(pauseOutgoingPayments bool, source string) (*db.HubFreeze, error)
+	Unfreeze(source string) (*db.HubFreeze, error)
+	// CheckOutgoingPayment returns ErrOutgoingPaymentsPaused if admin API
+	// payments are paused.
+	CheckOutgoingPayment() error
+	SetAppFrozen(appId uint, frozen bool) error
+	// StartNostrListener freezes the hub on commands sent by admins in direct
+	// messages to the hub's Nostr pubkey, until ctx is done.
+	StartNostrListener(ctx context.Context, pool *nostr.SimplePool, relayUrls []string, hubPubkey string, hubSecretKey string)
+}
+
+type freezeService struct {
+	db             *gorm.DB
+	eventPublisher events.EventPublisher
+	approvalsSvc   approvals.ApprovalsService
+}
+
+func NewFreezeService(db *gorm.DB, eventPublisher events.EventPublisher) *freezeService {
+	return &freezeService{
+		db:             db,
+		eventPublisher: eventPublisher,
+		approvalsSvc:   approvals.NewApprovalsService(db, eventPublisher),
+	}
+}
+
+func (svc *freezeService) GetState() (*db.HubFreeze, error) {
+	var state db.HubFreeze
+	if err := svc.db.Limit(1).Find(&state).Error; err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (svc *freezeService) Freeze(pauseOutgoingPayments bool, source string) (*db.HubFreeze, error) {
+	state, err := svc.GetState()
+	if err != nil {
+		return nil, err
+	}
+	if !state.Frozen {
+		now := time.Now()
+		state.FrozenAt = &now
+	}
+	state.Frozen = true
+	state.PauseOutgoingPayments = state.PauseOutgoingPayments || pauseOutgoingPayments
+	state.Source = source
+	if err := svc.db.Save(state).Error; err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Warn().
+		Str("source", source).
+		Bool("pause_outgoing_payments", state.PauseOutgoingPayments).
+		Msg("Hub frozen")
+	svc.eventPublisher.Publish(&events.Event{
+		Event: "nwc_hub_frozen",
+		Properties: map[string]interface{}{
+			"source":                  source,
+			"pause_outgoing_payments": state.PauseOutgoingPayments,
+		},
+	})
+
+	// payments held for approval must not go out once the hub is frozen
+	if err := svc.approvalsSvc.RejectPendingApprovals(); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (svc *freezeService) Unfreeze(source string) (*db.HubFreeze, error) {
+	state, err := svc.GetState()
+	if err != nil {
+		return nil, err
+	}
+	if !state.Frozen {
+		return state, nil
+	}
+	state.Frozen = false
+	state.PauseOutgoingPayments = false
+	state.FrozenAt = nil
+	state.Source = source
+	if err := svc.db.Save(state).Error; err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Warn().Str("source", source).Msg("Hub unfrozen")
+	svc.eventPublisher.Publish(&events.Event{
+		Event: "nwc_hub_unfrozen",
+		Properties: map[string]interface{}{
+			"source": source,
+		},
+	})
+	return state, nil
+}
+
+func (svc *freezeService) CheckOutgoingPayment() error {
+	state, err := svc.GetState()
+	if err != nil {
+		return err
+	}
+	if state.Frozen && state.PauseOutgoingPayments {
+		return ErrOutgoingPaymentsPaused
+	}
+	return nil
+}
+
+func (svc *freezeService) SetAppFrozen(appId uint, frozen bool) error {
+	result := svc.db.Model(&db.App{}).Where("id = ?", appId).Update("frozen", frozen)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAppNotFound
+	}
+	logger.Logger.Warn().Uint("app_id", appId).Bool("frozen", frozen).Msg("Updated app freeze")
+	return nil
+}
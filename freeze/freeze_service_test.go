@@ -0,0 +1,164 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip04"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func TestFreeze(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	freezeSvc := NewFreezeService(svc.DB, svc.EventPublisher)
+
+	state, err := freezeSvc.GetState()
+	require.NoError(t, err)
+	assert.False(t, state.Frozen)
+	assert.NoError(t, freezeSvc.CheckOutgoingPayment())
+
+	state, err = freezeSvc.Freeze(false, SourceApi)
+	require.NoError(t, err)
+	assert.True(t, state.Frozen)
+	assert.False(t, state.PauseOutgoingPayments)
+	require.NotNil(t, state.FrozenAt)
+	frozenAt := *state.FrozenAt
+	assert.NoError(t, freezeSvc.CheckOutgoingPayment())
+
+	state, err = freezeSvc.Freeze(true, SourceTray)
+	require.NoError(t, err)
+	assert.True(t, state.PauseOutgoingPayments)
+	assert.Equal(t, SourceTray, state.Source)
+	assert.True(t, frozenAt.Equal(*state.FrozenAt))
+	assert.ErrorIs(t, freezeSvc.CheckOutgoingPayment(), ErrOutgoingPaymentsPaused)
+
+	// freezing again cannot resume outgoing payments
+	state, err = freezeSvc.Freeze(false, SourceApi)
+	require.NoError(t, err)
+	assert.True(t, state.PauseOutgoingPayments)
+
+	state, err = freezeSvc.Unfreeze(SourceApi)
+	require.NoError(t, err)
+	assert.False(t, state.Frozen)
+	assert.False(t, state.PauseOutgoingPayments)
+	assert.Nil(t, state.FrozenAt)
+	assert.NoError(t, freezeSvc.CheckOutgoingPayment())
+
+	var count int64
+	require.NoError(t, svc.DB.Model(&db.HubFreeze{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestFreeze_RejectsPendingApprovals(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	approval := db.PaymentApproval{
+		AppId:     app.ID,
+		State:     db.PaymentApprovalStatePending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, svc.DB.Create(&approval).Error)
+
+	_, err = NewFreezeService(svc.DB, svc.EventPublisher).Freeze(false, SourceApi)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DB.First(&approval, approval.ID).Error)
+	assert.Equal(t, db.PaymentApprovalStateRejected, approval.State)
+	assert.NotNil(t, approval.DecidedAt)
+}
+
+func TestSetAppFrozen(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	freezeSvc := NewFreezeService(svc.DB, svc.EventPublisher)
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	require.NoError(t, freezeSvc.SetAppFrozen(app.ID, true))
+	require.NoError(t, svc.DB.First(app, app.ID).Error)
+	assert.True(t, app.Frozen)
+
+	require.NoError(t, freezeSvc.SetAppFrozen(app.ID, false))
+	require.NoError(t, svc.DB.First(app, app.ID).Error)
+	assert.False(t, app.Frozen)
+
+	assert.ErrorIs(t, freezeSvc.SetAppFrozen(app.ID+1, true), ErrAppNotFound)
+}
+
+func TestHandleCommandEvent(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	freezeSvc := NewFreezeService(svc.DB, svc.EventPublisher)
+	hubPubkey := svc.Keys.GetNostrPublicKey()
+	hubSecretKey := svc.Keys.GetNostrSecretKey()
+
+	adminSecretKey := nostr.GeneratePrivateKey()
+	adminPubkey, err := nostr.GetPublicKey(adminSecretKey)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AdminPubkey{Pubkey: adminPubkey, Permission: "full"}).Error)
+	readonlySecretKey := nostr.GeneratePrivateKey()
+	readonlyPubkey, err := nostr.GetPublicKey(readonlySecretKey)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AdminPubkey{Pubkey: readonlyPubkey, Permission: "readonly"}).Error)
+
+	directMessage := func(secretKey string, content string, createdAt time.Time) *nostr.Event {
+		sharedSecret, err := nip04.ComputeSharedSecret(hubPubkey, secretKey)
+		require.NoError(t, err)
+		encrypted, err := nip04.Encrypt(content, sharedSecret)
+		require.NoError(t, err)
+		event := &nostr.Event{
+			Kind:      nostr.KindEncryptedDirectMessage,
+			CreatedAt: nostr.Timestamp(createdAt.Unix()),
+			Tags:      nostr.Tags{{"p", hubPubkey}},
+			Content:   encrypted,
+		}
+		require.NoError(t, event.Sign(secretKey))
+		return event
+	}
+
+	now := time.Now()
+	assert.ErrorIs(t, freezeSvc.HandleCommandEvent(directMessage(nostr.GeneratePrivateKey(), CommandFreeze, now), hubPubkey, hubSecretKey, now), ErrInvalidCommand)
+	assert.ErrorIs(t, freezeSvc.HandleCommandEvent(directMessage(readonlySecretKey, CommandFreeze, now), hubPubkey, hubSecretKey, now), ErrInvalidCommand)
+	assert.ErrorIs(t, freezeSvc.HandleCommandEvent(directMessage(adminSecretKey, "unfreeze", now), hubPubkey, hubSecretKey, now), ErrInvalidCommand)
+	assert.ErrorIs(t, freezeSvc.HandleCommandEvent(directMessage(adminSecretKey, CommandFreeze, now.Add(-time.Hour)), hubPubkey, hubSecretKey, now), ErrInvalidCommand)
+	state, err := freezeSvc.GetState()
+	require.NoError(t, err)
+	assert.False(t, state.Frozen)
+
+	freezeEvent := directMessage(adminSecretKey, " Freeze\n", now)
+	require.NoError(t, freezeSvc.HandleCommandEvent(freezeEvent, hubPubkey, hubSecretKey, now))
+	state, err = freezeSvc.GetState()
+	require.NoError(t, err)
+	assert.True(t, state.Frozen)
+	assert.False(t, state.PauseOutgoingPayments)
+	assert.Equal(t, SourceNostrPrefix+adminPubkey, state.Source)
+
+	// a relay delivering the command again must not undo the unfreeze
+	_, err = freezeSvc.Unfreeze(SourceApi)
+	require.NoError(t, err)
+	assert.ErrorIs(t, freezeSvc.HandleCommandEvent(freezeEvent, hubPubkey, hubSecretKey, now), ErrInvalidCommand)
+	state, err = freezeSvc.GetState()
+	require.NoError(t, err)
+	assert.False(t, state.Frozen)
+
+	later := now.Add(2 * time.Second)
+	require.NoError(t, freezeSvc.HandleCommandEvent(directMessage(adminSecretKey, CommandFreezeAll, later), hubPubkey, hubSecretKey, later))
+	assert.ErrorIs(t, freezeSvc.CheckOutgoingPayment(), ErrOutgoingPaymentsPaused)
+}
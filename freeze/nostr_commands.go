@@ -0,0 +1,121 @@
+package freeze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip04"
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+// Commands an admin can send to the hub in a NIP-04 direct message. There is
+// deliberately no command to unfreeze: a replayed or leaked DM can only ever
+// make the hub safer.
+const (
+	CommandFreeze    = "freeze"
+	CommandFreezeAll = "freeze all"
+)
+
+// maxCommandAge is how old a command DM may be when it is received. Relays
+// may deliver old events after a reconnect.
+const maxCommandAge = 10 * time.Minute
+
+// resubscribeDelay is the backoff between a relay channel close and the next
+// subscription.
+const resubscribeDelay = 5 * time.Second
+
+var ErrInvalidCommand = errors.New("invalid freeze command")
+
+// HandleCommandEvent freezes the hub if event is a direct message to the hub
+// with a freeze command, signed by an admin pubkey with full permission.
+func (svc *freezeService) HandleCommandEvent(event *nostr.Event, hubPubkey string, hubSecretKey string, now time.Time) error {
+	if event.Kind != nostr.KindEncryptedDirectMessage {
+		return fmt.Errorf("%w: kind must be %d", ErrInvalidCommand, nostr.KindEncryptedDirectMessage)
+	}
+	if tag := event.Tags.Find("p"); tag == nil || tag[1] != hubPubkey {
+		return fmt.Errorf("%w: not addressed to the hub", ErrInvalidCommand)
+	}
+	if ok, err := event.CheckSignature(); !ok || err != nil {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidCommand)
+	}
+	if createdAt := event.CreatedAt.Time(); createdAt.Before(now.Add(-maxCommandAge)) || createdAt.After(now.Add(maxCommandAge)) {
+		return fmt.Errorf("%w: created_at is outside the accepted window", ErrInvalidCommand)
+	}
+
+	var adminPubkey db.AdminPubkey
+	err := svc.db.Where("pubkey = ? AND permission = ?", event.PubKey, "full").First(&adminPubkey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: sender is not an admin with full permission", ErrInvalidCommand)
+	}
+	if err != nil {
+		return err
+	}
+
+	// relays deliver the same DM again after a reconnect, which must not
+	// undo a later unfreeze
+	state, err := svc.GetState()
+	if err != nil {
+		return err
+	}
+	if !event.CreatedAt.Time().After(state.UpdatedAt) {
+		return fmt.Errorf("%w: older than the last change of the freeze state", ErrInvalidCommand)
+	}
+
+	sharedSecret, err := nip04.ComputeSharedSecret(event.PubKey, hubSecretKey)
+	if err != nil {
+		return err
+	}
+	content, err := nip04.Decrypt(event.Content, sharedSecret)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decrypt", ErrInvalidCommand)
+	}
+
+	source := SourceNostrPrefix + event.PubKey
+	switch strings.ToLower(strings.TrimSpace(content)) {
+	case CommandFreeze:
+		_, err = svc.Freeze(false, source)
+	case CommandFreezeAll:
+		_, err = svc.Freeze(true, source)
+	default:
+		return fmt.Errorf("%w: unknown command", ErrInvalidCommand)
+	}
+	return err
+}
+
+func (svc *freezeService) StartNostrListener(ctx context.Context, pool *nostr.SimplePool, relayUrls []string, hubPubkey string, hubSecretKey string) {
+	filter := nostr.Filter{
+		Kinds: []int{nostr.KindEncryptedDirectMessage},
+		Tags:  nostr.TagMap{"p": []string{hubPubkey}},
+	}
+
+	go func() {
+		for {
+			since := nostr.Timestamp(time.Now().Add(-maxCommandAge).Unix())
+			filter.Since = &since
+			for relayEvent := range pool.SubscribeMany(ctx, relayUrls, filter) {
+				if relayEvent.Event == nil {
+					continue
+				}
+				err := svc.HandleCommandEvent(relayEvent.Event, hubPubkey, hubSecretKey, time.Now())
+				if err != nil && !errors.Is(err, ErrInvalidCommand) {
+					logger.Logger.Error().Err(err).Str("id", relayEvent.ID).Msg("Failed to handle freeze command")
+				} else if err != nil {
+					logger.Logger.Debug().Err(err).Str("id", relayEvent.ID).Msg("Ignoring direct message")
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(resubscribeDelay):
+			}
+		}
+	}()
+}
@@ -162,7 +162,8 @@ const NewAppInternal = ({ capabilities, appStoreApps }: NewAppInternalProps) =>
       requestMethodsSet.has("pay_invoice") ||
       requestMethodsSet.has("pay_keysend") ||
       requestMethodsSet.has("multi_pay_invoice") ||
-      requestMethodsSet.has("multi_pay_keysend")
+      requestMethodsSet.has("multi_pay_keysend") ||
+      requestMethodsSet.has("pay_offer")
     ) {
       scopes.push("pay_invoice");
     }
@@ -177,11 +178,15 @@ const NewAppInternal = ({ capabilities, appStoreApps }: NewAppInternalProps) =>
       requestMethodsSet.has("make_invoice") ||
       requestMethodsSet.has("make_hold_invoice") ||
       requestMethodsSet.has("settle_hold_invoice") ||
-      requestMethodsSet.has("cancel_hold_invoice")
+      requestMethodsSet.has("cancel_hold_invoice") ||
+      requestMethodsSet.has("make_offer")
     ) {
       scopes.push("make_invoice");
     }
-    if (requestMethodsSet.has("lookup_invoice")) {
+    if (
+      requestMethodsSet.has("lookup_invoice") ||
+      requestMethodsSet.has("lookup_offer")
+    ) {
       scopes.push("lookup_invoice");
     }
     if (requestMethodsSet.has("list_transactions")) {
@@ -30,7 +30,10 @@ export type Nip47RequestMethod =
   | "multi_pay_keysend"
   | "make_hold_invoice"
   | "settle_hold_invoice"
-  | "cancel_hold_invoice";
+  | "cancel_hold_invoice"
+  | "make_offer"
+  | "pay_offer"
+  | "lookup_offer";
 
 export type BudgetRenewalType =
   | "daily"
@@ -41,7 +44,7 @@ export type BudgetRenewalType =
   | "";
 
 export type Scope =
-  | "pay_invoice" // also used for pay_keysend, multi_pay_invoice, multi_pay_keysend
+  | "pay_invoice" // also used for pay_keysend, multi_pay_invoice, multi_pay_keysend, pay_offer
   | "get_balance"
   | "get_info"
   | "make_invoice"
@@ -134,6 +137,7 @@ export interface App {
   lastUsedAt?: string;
   expiresAt?: string;
   isolated: boolean;
+  frozen: boolean;
   kind?: string;
   balance: number;
 
@@ -168,8 +172,21 @@ export interface App {
   circleFeesPpm?: number;
   circlePerWalletMaxMloki?: number;
   circleMinBudgetRenewal?: BudgetRenewalType;
+  spendingPolicy?: SpendingPolicy;
 }
 
+// SpendingPolicy rules are checked on every outgoing payment on top of the
+// budget; 0 and empty lists disable the corresponding rule.
+export type SpendingPolicy = {
+  maxAmountPerPayment: number; // loki
+  maxPaymentsPerHour: number;
+  maxFeePpm: number;
+  allowedPubkeys: string[];
+  deniedPubkeys: string[];
+  // NIP-47 payments above this amount (loki) wait for approval; 0 disables
+  approvalThreshold: number;
+};
+
 export interface CircleIdentitySummary {
   id: number;
   name: string;
@@ -248,6 +265,7 @@ export interface InfoResponse {
   lsps: LSP[];
   enableSwap: boolean;
   enableMessageboardNwc: boolean;
+  enableHttpWebhooks: boolean;
   workDir: string;
   enablePolling?: boolean;
 }
@@ -290,6 +308,59 @@ export type AutoSwapConfig = {
   balanceThreshold: number;
   swapAmount: number;
   destination: string;
+  swapIn: AutoSwapInConfig;
+};
+
+export type AutoSwapInConfig = {
+  enabled: boolean;
+  spendableThreshold: number;
+  onchainThreshold: number;
+  swapAmount: number;
+  dailyLimit: number;
+  swappedToday: number;
+  pendingSwapId?: string;
+  unrefundedSwapIds: string[];
+};
+
+export type AutoLiquidityConfig = {
+  enabled: boolean;
+  receiveThreshold: number;
+  channelSize: number;
+  maxFee: number;
+  dailyLimit: number;
+  monthlyLimit: number;
+  spentToday: number;
+  spentThisMonth: number;
+};
+
+export type AutoRebalanceConfig = {
+  enabled: boolean;
+  sourceMinLocalPercent: number;
+  targetMaxLocalPercent: number;
+  amountLoki: number;
+  maxFeePpm: number;
+};
+
+export type RebalanceState = "PENDING" | "SUCCEEDED" | "FAILED";
+
+export type Rebalance = {
+  id: number;
+  outgoingChannelId: string;
+  incomingChannelId: string;
+  lastHopPubkey: string;
+  amountMloki: number;
+  feeMloki: number;
+  maxFeePpm: number;
+  paymentHash: string;
+  state: RebalanceState;
+  failureReason?: string;
+  auto: boolean;
+  createdAt: string;
+};
+
+export type ListRebalancesResponse = {
+  rebalances: Rebalance[];
+  totalCount: number;
 };
 
 export type SwapInfo = {
@@ -354,6 +425,7 @@ export interface CreateAppRequest {
   circleFeesPpm?: number;
   circlePerWalletMaxMloki?: number;
   circleMinBudgetRenewal?: BudgetRenewalType;
+  spendingPolicy?: SpendingPolicy;
   // circleIdentityId reuses an existing CircleIdentity — when set,
   // circleIdentityName/circlePolicy/providerPubkey below are ignored.
   circleIdentityId?: number;
@@ -416,6 +488,8 @@ export type UpdateAppRequest = {
   circleFeesPpm?: number;
   circlePerWalletMaxMloki?: number;
   circleMinBudgetRenewal?: BudgetRenewalType;
+  // replaces the spending policy; a policy without any rule removes it
+  spendingPolicy?: SpendingPolicy;
 };
 
 export type Channel = {
@@ -492,12 +566,31 @@ export type OpenChannelRequest = {
   pubkey: string;
   amountLoki: number;
   public: boolean;
+  feeRate?: number;
+  confTarget?: number;
+  pushAmountLoki?: number;
+  closeAddress?: string;
+  minHtlcMloki?: number;
+  zeroConf?: boolean;
+  scidAlias?: boolean;
+  outpoints?: string[];
 };
 
 export type OpenChannelResponse = {
   fundingTxId: string;
 };
 
+export type BatchOpenChannelRequest = {
+  channels: OpenChannelRequest[];
+  feeRate?: number;
+  confTarget?: number;
+};
+
+export type BatchOpenChannelResponse = {
+  fundingTxId: string;
+  fundingTxVouts: number[];
+};
+
 // eslint-disable-next-line @typescript-eslint/ban-types
 export type CloseChannelResponse = {};
 
@@ -579,6 +672,7 @@ export type SetupNodeInfo = Partial<{
   mempoolApi?: string;
   enableSwap?: boolean;
   enableMessageboardNwc?: boolean;
+  enableHttpWebhooks?: boolean;
   lsps?: LSP[];
 }>;
 
@@ -724,6 +818,62 @@ export type OnchainTransaction = {
   txId: string;
 };
 
+export type Utxo = {
+  outpoint: string;
+  address: string;
+  amountLoki: number;
+  confirmations: number;
+  frozen: boolean;
+  label: string;
+};
+
+export type UpdateUtxoRequest = {
+  outpoint: string;
+  label?: string;
+  frozen?: boolean;
+};
+
+export type PsbtOutput = {
+  address: string;
+  amountLoki: number;
+};
+
+export type CreatePsbtRequest = {
+  outputs: PsbtOutput[];
+  outpoints?: string[];
+  feeRate?: number;
+  confTarget?: number;
+};
+
+export type CreatePsbtResponse = {
+  psbt: string;
+  changeOutputIndex: number;
+  lockedUtxos: string[];
+};
+
+export type FinalizePsbtResponse = {
+  signedPsbt: string;
+  rawTx: string;
+  txId: string;
+};
+
+export type PublishTransactionResponse = {
+  txId: string;
+};
+
+export type BumpFeeRequest = {
+  txId?: string;
+  outpoint?: string;
+  feeRate: number;
+};
+
+export type BumpFeeResponse = {
+  method: "rbf" | "cpfp";
+  outpoint: string;
+  txId: string;
+  feeLoki: number;
+};
+
 export type LSPS1GetInfoResponse = LSPS1Option;
 
 export type LSPS1Option = {
@@ -830,10 +980,35 @@ export type AuthTokenResponse = {
 };
 
 
+export type Forward = {
+  id: number;
+  incomingChannelId: string;
+  outgoingChannelId: string;
+  incomingPeerPubkey: string;
+  outgoingPeerPubkey: string;
+  inboundAmountMloki: number;
+  outboundAmountMloki: number;
+  feeEarnedMloki: number;
+  forwardedAt: string;
+};
+
+export type ForwardsGroup = {
+  channelId?: string;
+  peerPubkey?: string;
+  numForwardsIn: number;
+  numForwardsOut: number;
+  inboundAmountMloki: number;
+  outboundAmountMloki: number;
+  feeEarnedMloki: number;
+};
+
 export type GetForwardsResponse = {
   outboundAmountForwardedMloki: number;
   totalFeeEarnedMloki: number;
   numForwards: number;
+  totalCount: number;
+  forwards: Forward[];
+  groups?: ForwardsGroup[];
 };
 
 export interface FAQ {
@@ -925,3 +1100,270 @@ export interface IdentityAuthority {
   created_at: number;
 }
 
+
+export type PaymentApprovalState =
+  | "pending"
+  | "approved"
+  | "rejected"
+  | "expired";
+
+// PaymentApproval is a NIP-47 payment above its app's approval threshold,
+// held until it is decided via POST /api/approvals/:id. New requests and
+// decisions are streamed from /api/approvals/events.
+export interface PaymentApproval {
+  id: number;
+  appId: number;
+  appName: string;
+  method: string;
+  amount: number; // mloki
+  destination: string;
+  invoice: string;
+  description: string;
+  state: PaymentApprovalState;
+  createdAt: string;
+  expiresAt: string;
+  decidedAt?: string;
+}
+
+// WebhookEndpoint receives hub events as JSON POSTs signed with its secret
+// in the X-Lokihub-Signature header. An empty events list means all events.
+export interface WebhookEndpoint {
+  id: number;
+  url: string;
+  events: string[];
+  enabled: boolean;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface CreateWebhookEndpointRequest {
+  url: string;
+  events: string[];
+  enabled: boolean;
+}
+
+// the secret is only returned when the endpoint is created
+export interface CreateWebhookEndpointResponse extends WebhookEndpoint {
+  secret: string;
+}
+
+export interface UpdateWebhookEndpointRequest {
+  url?: string;
+  events?: string[];
+  enabled?: boolean;
+}
+
+export type WebhookDeliveryState = "pending" | "delivered" | "failed";
+
+export interface WebhookDelivery {
+  id: number;
+  endpointId: number;
+  event: string;
+  payload: string;
+  state: WebhookDeliveryState;
+  attempts: number;
+  nextAttemptAt?: string;
+  lastStatusCode: number;
+  lastError: string;
+  createdAt: string;
+  deliveredAt?: string;
+}
+
+export interface ListWebhookDeliveriesResponse {
+  deliveries: WebhookDelivery[];
+  totalCount: number;
+}
+
+// ApiTokenScope groups admin API routes. A token restricted to an app can
+// only hold read, apps:write, payments:send and invoices:write.
+export type ApiTokenScope =
+  | "read"
+  | "apps:write"
+  | "payments:send"
+  | "invoices:write"
+  | "channels:manage"
+  | "swaps"
+  | "settings";
+
+export interface ApiToken {
+  id: number;
+  name: string;
+  scopes: ApiTokenScope[];
+  appId?: number;
+  appName?: string;
+  expiresAt?: string;
+  lastUsedAt?: string;
+  createdAt: string;
+}
+
+export interface CreateApiTokenRequest {
+  name: string;
+  scopes: ApiTokenScope[];
+  appId?: number;
+  expiresAt?: string;
+}
+
+// the token is only returned when it is created
+export interface CreateApiTokenResponse extends ApiToken {
+  token: string;
+}
+
+// AdminPubkey can log in to the admin API with a NIP-98 signed event
+// (POST /api/unlock/nostr) and is given a session with its permission.
+export interface AdminPubkey {
+  id: number;
+  pubkey: string;
+  name: string;
+  permission: "full" | "readonly";
+  createdAt: string;
+}
+
+export interface AddAdminPubkeyRequest {
+  pubkey: string;
+  name: string;
+  permission: "full" | "readonly";
+}
+
+export type AuditLogOutcome = "success" | "failure";
+
+// AuditLogEntry is one admin API request that changed the hub or revealed a
+// secret. The actor is "token:<name>", "session:<permission>" or
+// "unlock-password"; the payload is the request body with secrets redacted.
+export interface AuditLogEntry {
+  id: number;
+  action: string;
+  actor: string;
+  apiTokenId?: number;
+  remoteIp: string;
+  requestId: string;
+  method: string;
+  path: string;
+  payload: string;
+  outcome: AuditLogOutcome;
+  statusCode: number;
+  createdAt: string;
+}
+
+export interface ListAuditLogResponse {
+  entries: AuditLogEntry[];
+  totalCount: number;
+}
+
+// TotpStatus describes the TOTP second factor. Once enabled, a totpCode is
+// required for full access unlocks, the mnemonic, backups, password changes
+// and payments above paymentThresholdMloki (every payment if it is null).
+export interface TotpStatus {
+  enabled: boolean;
+  pending: boolean;
+  paymentThresholdMloki: number | null;
+  recoveryCodesLeft: number;
+}
+
+export interface BeginTotpEnrollmentResponse {
+  secret: string;
+  otpauthUrl: string;
+}
+
+// recovery codes are only returned when they are generated
+export interface TotpRecoveryCodesResponse {
+  recoveryCodes: string[];
+}
+
+// FreezeState is the emergency freeze of the hub. While frozen, every app
+// connection only answers get_info; pauseOutgoingPayments also refuses
+// payments from the admin API. Receiving payments keeps working.
+export interface FreezeState {
+  frozen: boolean;
+  pauseOutgoingPayments: boolean;
+  source: string;
+  frozenAt: string | null;
+}
+
+export interface FreezeRequest {
+  pauseOutgoingPayments: boolean;
+}
+
+export type TransactionExportFormat = "csv" | "beancount" | "hledger";
+
+export interface ChannelAcceptorPolicy {
+  minCapacity: number; // loki
+  maxCapacity: number; // loki
+  allowedPubkeys: string[] | null;
+  deniedPubkeys: string[] | null;
+  maxPendingPerPeer: number;
+  rejectPrivate: boolean;
+  onlyLsps: boolean;
+}
+
+export interface ChannelAcceptDecision {
+  id: number;
+  peerPubkey: string;
+  capacity: number; // loki
+  private: boolean;
+  accepted: boolean;
+  zeroConf: boolean;
+  reason: string;
+  createdAt: string;
+}
+
+export interface ListChannelAcceptDecisionsResponse {
+  decisions: ChannelAcceptDecision[];
+  totalCount: number;
+}
+
+export interface ChannelFeeManagerConfig {
+  enabled: boolean;
+  minFeePpm: number;
+  maxFeePpm: number;
+  baseFeeMloki: number;
+  intervalMinutes: number;
+  flowWindowHours: number;
+  excludedChannelIds: string[];
+}
+
+export interface ChannelFeeUpdate {
+  id: number;
+  channelId: string;
+  peerPubkey: string;
+  oldBaseFeeMloki: number;
+  newBaseFeeMloki: number;
+  oldFeePpm: number;
+  newFeePpm: number;
+  localBalancePercent: number;
+  outboundFlowMloki: number;
+  createdAt: string;
+}
+
+export interface ListChannelFeeUpdatesResponse {
+  feeUpdates: ChannelFeeUpdate[];
+  totalCount: number;
+}
+
+export interface LSPServiceConfig {
+  enabled: boolean;
+  lsps1: {
+    minChannelBalanceLoki: number;
+    maxChannelBalanceLoki: number;
+    maxChannelExpiryBlocks: number;
+    minRequiredChannelConfirmations: number;
+    minFundingConfirmsWithinBlocks: number;
+    feeBaseLoki: number;
+    feePpm: number;
+    paymentExpirySeconds: number;
+  };
+}
+
+// a paid LSPS1 order sold by this node whose channel was not opened
+export interface LSPServiceRefund {
+  order_id: string;
+  client_pubkey: string;
+  state: "FAILED" | "OPENING";
+  payment_state: "PAID";
+  lsp_balance_loki: number;
+  refund_onchain_address: string;
+  order_total: number;
+  payment_hash: string;
+  failure_reason: string;
+  created_at: string;
+  updated_at: string;
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/apitokens"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+)
+
+// apiTokenContextKey holds the *db.ApiToken of requests authenticated with
+// an API token rather than a JWT
+const apiTokenContextKey = "apiToken"
+
+const (
+	// the first path parameter of the route is the app's ID or pubkey
+	appFromPath = iota + 1
+	// the handler restricts the app named in the request with
+	// restrictToTokenApp
+	appFromRequest
+)
+
+// appTokenRoutes are the only routes a token restricted to a single app can
+// call, keyed by method and route path
+var appTokenRoutes = map[string]int{
+	"GET /api/apps/:pubkey":                                      appFromPath,
+	"GET /api/apps/:id":                                          appFromPath,
+	"PATCH /api/apps/:pubkey":                                    appFromPath,
+	"DELETE /api/apps/:pubkey":                                   appFromPath,
+	"GET /api/apps/:id/circle/allowlist":                         appFromPath,
+	"PUT /api/apps/:id/circle/allowlist":                         appFromPath,
+	"DELETE /api/apps/:id/circle/allowlist/:pubkey":              appFromPath,
+	"POST /api/apps/:id/circle/refresh/preview":                  appFromPath,
+	"POST /api/apps/:id/circle/refresh":                          appFromPath,
+	"GET /api/apps/:id/circle/children":                          appFromPath,
+	"DELETE /api/apps/:id/circle/children/:childId":              appFromPath,
+	"POST /api/apps/:id/circle/delete":                           appFromPath,
+	"GET /api/apps/:id/jit-wallets":                              appFromPath,
+	"POST /api/apps/:id/jit-wallets":                             appFromPath,
+	"DELETE /api/apps/:id/jit-wallets/:walletId":                 appFromPath,
+	"DELETE /api/apps/:id/jit-wallets/:walletId/claims/:claimId": appFromPath,
+	"GET /api/apps/:id/jit-connection":                           appFromPath,
+	"GET /api/apps/:id/jit-wallet-recipients":                    appFromPath,
+	"DELETE /api/lightning-addresses/:appId":                     appFromPath,
+	"POST /api/lightning-addresses":                              appFromRequest,
+	"GET /api/transactions":                                      appFromRequest,
+	"POST /api/transfers":                                        appFromRequest,
+	"POST /api/payments/:invoice":                                appFromRequest,
+	"POST /api/invoices":                                         appFromRequest,
+	"POST /api/offers":                                           appFromRequest,
+	"POST /api/offers/pay":                                       appFromRequest,
+}
+
+var errAppRestricted = errors.New("this API token is restricted to another app")
+
+// authenticate accepts API tokens and hands every other request to the JWT
+// middleware
+func (httpSvc *HttpService) authenticate(jwtMiddleware echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withJWT := jwtMiddleware(next)
+		return func(c echo.Context) error {
+			token, found := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			if !found {
+				token = c.QueryParam("token")
+			}
+			if !strings.HasPrefix(token, apitokens.TokenPrefix) {
+				return withJWT(c)
+			}
+
+			apiToken, err := httpSvc.apiTokensSvc.Authenticate(token)
+			if err != nil {
+				if !errors.Is(err, apitokens.ErrInvalidToken) {
+					httpSvc.logger.Error().Err(err).Msg("Failed to authenticate API token")
+				}
+				return c.JSON(http.StatusUnauthorized, ErrorResponse{
+					Message: apitokens.ErrInvalidToken.Error(),
+				})
+			}
+			c.Set(apiTokenContextKey, apiToken)
+			return next(c)
+		}
+	}
+}
+
+// requireScope only lets requests through that hold the given scope
+func (httpSvc *HttpService) requireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if apiToken, ok := c.Get(apiTokenContextKey).(*db.ApiToken); ok {
+				if !apitokens.HasScope(apiToken, scope) {
+					return c.JSON(http.StatusForbidden, ErrorResponse{
+						Message: fmt.Sprintf("This operation requires an API token with the %s scope", scope),
+					})
+				}
+				if apiToken.AppId != nil {
+					if err := checkAppTokenRoute(c, apiToken); err != nil {
+						return c.JSON(http.StatusForbidden, ErrorResponse{
+							Message: err.Error(),
+						})
+					}
+				}
+				return next(c)
+			}
+
+			claims := c.Get("user").(*jwt.Token).Claims.(*jwtCustomClaims)
+			// Allow if no permission specified (backward compatibility) or if full access
+			if claims.Permission == "" || claims.Permission == "full" || scope == apitokens.ScopeRead {
+				return next(c)
+			}
+
+			return c.JSON(http.StatusForbidden, ErrorResponse{
+				Message: "This operation requires full access permissions",
+			})
+		}
+	}
+}
+
+func checkAppTokenRoute(c echo.Context, apiToken *db.ApiToken) error {
+	switch appTokenRoutes[c.Request().Method+" "+c.Path()] {
+	case appFromRequest:
+		return nil
+	case appFromPath:
+		paramValues := c.ParamValues()
+		if len(paramValues) == 0 {
+			return errAppRestricted
+		}
+		if paramValues[0] == strconv.FormatUint(uint64(*apiToken.AppId), 10) ||
+			(apiToken.App != nil && paramValues[0] == apiToken.App.AppPubkey) {
+			return nil
+		}
+		return errAppRestricted
+	default:
+		return errors.New("this API token is restricted to an app and cannot call this route")
+	}
+}
+
+// restrictToTokenApp applies the app restriction of the request's API token
+// to the app a request acts on: a restricted token defaults to its app and
+// cannot name another one
+func restrictToTokenApp(c echo.Context, appId *uint) (*uint, error) {
+	apiToken, ok := c.Get(apiTokenContextKey).(*db.ApiToken)
+	if !ok || apiToken.AppId == nil {
+		return appId, nil
+	}
+	if appId != nil && *appId != *apiToken.AppId {
+		return nil, errAppRestricted
+	}
+	return apiToken.AppId, nil
+}
+
+func (httpSvc *HttpService) apiTokensListHandler(c echo.Context) error {
+	apiTokens, err := httpSvc.api.ListApiTokens()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to list API tokens: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, apiTokens)
+}
+
+func (httpSvc *HttpService) apiTokenCreateHandler(c echo.Context) error {
+	var createRequest api.CreateApiTokenRequest
+	if err := c.Bind(&createRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	apiToken, err := httpSvc.api.CreateApiToken(&createRequest)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, apiToken)
+}
+
+func (httpSvc *HttpService) apiTokenRevokeHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "invalid API token ID",
+		})
+	}
+
+	if err := httpSvc.api.RevokeApiToken(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, apitokens.ErrTokenNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/apitokens"
+	"github.com/flokiorg/lokihub/config"
+	lokidb "github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/tests/db"
+	"github.com/flokiorg/lokihub/tests/mocks"
+)
+
+func TestApiToken_Scopes(t *testing.T) {
+	e := echo.New()
+	logger.Init(strconv.Itoa(int(4)))
+	mockSvc := mocks.NewMockService(t)
+	gormDb, err := db.NewDB(t)
+	require.NoError(t, err)
+	defer db.CloseDB(gormDb)
+
+	mockConfig := mocks.NewMockConfig(t)
+	mockConfig.On("GetEnv").Return(&config.AppConfig{})
+	mockConfig.On("GetJWTSecret").Return("dummy secret", nil).Maybe()
+
+	mockSvc.On("GetDB").Return(gormDb)
+	mockSvc.On("GetConfig").Return(mockConfig)
+	mockKeys := mocks.NewMockKeys(t)
+	mockKeys.On("GetNostrPublicKey").Return("c3e1f5a4b1c2d3e4f5a6b7c8d9e0f1aa5f8b05a3b14d0e0d3d0dcf2e2db2c5c1").Maybe()
+
+	mockSvc.On("GetKeys").Return(mockKeys)
+	mockSvc.On("GetLokiSvc").Return(mocks.NewMockLokiService(t))
+	mockSvc.On("GetAppStoreSvc").Return(&mocks.MockAppStoreService{})
+
+	httpSvc := NewHttpService(mockSvc, events.NewEventPublisher())
+	httpSvc.RegisterSharedRoutes(e)
+
+	app := lokidb.App{Name: "Test app", AppPubkey: "a5f8b05a3b14d0e0d3d0dcf2e2db2c5c1a3e1f5a4b1c2d3e4f5a6b7c8d9e0f1a"}
+	require.NoError(t, gormDb.Create(&app).Error)
+	otherApp := lokidb.App{Name: "Other app", AppPubkey: "b5f8b05a3b14d0e0d3d0dcf2e2db2c5c1a3e1f5a4b1c2d3e4f5a6b7c8d9e0f1a"}
+	require.NoError(t, gormDb.Create(&otherApp).Error)
+
+	apiTokensSvc := apitokens.NewApiTokensService(gormDb)
+	_, readToken, err := apiTokensSvc.CreateToken("read", []string{apitokens.ScopeRead}, nil, nil)
+	require.NoError(t, err)
+	_, swapsToken, err := apiTokensSvc.CreateToken("swaps", []string{apitokens.ScopeSwaps}, nil, nil)
+	require.NoError(t, err)
+	_, appToken, err := apiTokensSvc.CreateToken("app", []string{apitokens.ScopeRead, apitokens.ScopeAppsWrite}, &app.ID, nil)
+	require.NoError(t, err)
+	revoked, revokedToken, err := apiTokensSvc.CreateToken("revoked", []string{apitokens.ScopeRead}, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, apiTokensSvc.RevokeToken(revoked.ID))
+
+	testCases := []struct {
+		name           string
+		token          string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"read token lists apps", readToken, http.MethodGet, "/api/apps", http.StatusOK},
+		{"read token cannot list API tokens", readToken, http.MethodGet, "/api/api-tokens", http.StatusForbidden},
+		{"read token cannot delete apps", readToken, http.MethodDelete, "/api/apps/" + app.AppPubkey, http.StatusForbidden},
+		{"read token cannot reveal the swap mnemonic", readToken, http.MethodGet, "/api/swaps/mnemonic", http.StatusForbidden},
+		{"swaps token cannot reveal the swap mnemonic", swapsToken, http.MethodGet, "/api/swaps/mnemonic", http.StatusForbidden},
+		{"swaps token cannot list apps", swapsToken, http.MethodGet, "/api/apps", http.StatusForbidden},
+		{"app token cannot list every app", appToken, http.MethodGet, "/api/apps", http.StatusForbidden},
+		{"app token cannot delete another app", appToken, http.MethodDelete, "/api/apps/" + otherApp.AppPubkey, http.StatusForbidden},
+		{"app token deletes its app", appToken, http.MethodDelete, "/api/apps/" + app.AppPubkey, http.StatusNoContent},
+		{"revoked token", revokedToken, http.MethodGet, "/api/apps", http.StatusUnauthorized},
+		{"unknown token", apitokens.TokenPrefix + "unknown", http.MethodGet, "/api/apps", http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequestWithContext(t.Context(), tc.method, tc.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, tc.expectedStatus, rec.Code, tc.name)
+	}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/approvals"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/events"
+)
+
+func (httpSvc *HttpService) approvalsListHandler(c echo.Context) error {
+	paymentApprovals, err := httpSvc.api.ListPaymentApprovals(c.QueryParam("state"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, paymentApprovals)
+}
+
+func (httpSvc *HttpService) approvalDecideHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "invalid approval ID",
+		})
+	}
+
+	var decideRequest api.DecidePaymentApprovalRequest
+	if err := c.Bind(&decideRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	paymentApproval, err := httpSvc.api.DecidePaymentApproval(totpContext(c, ""), uint(id), decideRequest.Approve)
+	if err != nil {
+		status := paymentErrorStatus(err)
+		switch {
+		case errors.Is(err, approvals.ErrApprovalNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, approvals.ErrApprovalNotPending):
+			status = http.StatusConflict
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, paymentApproval)
+}
+
+// approvalsEventsSSEHandler streams approval requests and decisions so the
+// owner can act on held payments while their NIP-47 request is still open.
+func (httpSvc *HttpService) approvalsEventsSSEHandler(c echo.Context) error {
+	return httpSvc.streamEvents(c, func(event *events.Event) bool {
+		return strings.HasPrefix(event.Event, "nwc_payment_approval_")
+	})
+}
@@ -0,0 +1,233 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/audit"
+	"github.com/flokiorg/lokihub/constants"
+	lokidb "github.com/flokiorg/lokihub/db"
+)
+
+// auditedRoutes are the admin API routes recorded in the audit log, keyed by
+// method and route path, with the action they are recorded as
+var auditedRoutes = map[string]string{
+	"POST /api/apps":                                "app.create",
+	"PATCH /api/apps/:pubkey":                       "app.update",
+	"DELETE /api/apps/:pubkey":                      "app.delete",
+	"POST /api/apps/:id/circle/delete":              "app.delete",
+	"DELETE /api/apps/:id/circle/children/:childId": "app.delete",
+	"POST /api/transfers":                           "transfer",
+	"POST /api/channels":                            "channel.open",
+	"POST /api/channels/batch":                      "channel.open",
+	"DELETE /api/peers/:peerId/channels/:channelId": "channel.close",
+	"PATCH /api/peers/:peerId/channels/:channelId":  "channel.update",
+	"POST /api/peers":                               "peer.connect",
+	"DELETE /api/peers/:peerId":                     "peer.disconnect",
+	"PATCH /api/settings":                           "settings.update",
+	"PATCH /api/unlock-password":                    "settings.unlock_password",
+	"PATCH /api/auto-unlock":                        "settings.auto_unlock",
+	"PUT /api/channels/acceptor":                    "settings.channel_acceptor",
+	"PUT /api/channels/fees/manager":                "settings.fee_manager",
+	"POST /api/lsp-service/refunds/:orderId":        "lsp_service.refund",
+	"POST /api/node/alias":                          "settings.node_alias",
+	"POST /api/webhooks":                            "settings.webhook_create",
+	"PATCH /api/webhooks/:id":                       "settings.webhook_update",
+	"DELETE /api/webhooks/:id":                      "settings.webhook_delete",
+	"POST /api/api-tokens":                          "api_token.create",
+	"DELETE /api/api-tokens/:id":                    "api_token.revoke",
+	"POST /api/admin-pubkeys":                       "admin_pubkey.add",
+	"DELETE /api/admin-pubkeys/:id":                 "admin_pubkey.remove",
+	"POST /api/totp/enroll":                         "totp.enroll",
+	"POST /api/totp/confirm":                        "totp.enable",
+	"POST /api/totp/disable":                        "totp.disable",
+	"POST /api/totp/recovery-codes":                 "totp.recovery_codes",
+	"PATCH /api/totp":                               "totp.update",
+	"POST /api/freeze":                              "freeze.enable",
+	"DELETE /api/freeze":                            "freeze.disable",
+	"PUT /api/apps/:id/freeze":                      "app.freeze",
+	"POST /api/swaps/out":                           "swap.out",
+	"POST /api/swaps/in":                            "swap.in",
+	"POST /api/swaps/refund":                        "swap.refund",
+	"POST /api/autoswap":                            "swap.auto_out_enable",
+	"DELETE /api/autoswap":                          "swap.auto_out_disable",
+	"POST /api/autoswap/in":                         "swap.auto_in_enable",
+	"DELETE /api/autoswap/in":                       "swap.auto_in_disable",
+	"POST /api/backup":                              "backup.create",
+	"POST /api/mnemonic":                            "mnemonic.reveal",
+	"GET /api/swaps/mnemonic":                       "mnemonic.reveal_swaps",
+	"PUT /api/apps/:id/circle/allowlist":            "circle_allowlist.replace",
+	"DELETE /api/apps/:id/circle/allowlist/:pubkey": "circle_allowlist.remove",
+	"POST /api/apps/:id/circle/refresh":             "circle_allowlist.refresh",
+	"POST /api/apps/:id/jit-wallets":                "jit_wallet.create",
+}
+
+// auditLog records requests to audited routes once they have been handled,
+// including those refused for lacking a scope. It must run after
+// authenticate so the actor is known.
+func (httpSvc *HttpService) auditLog(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		action, ok := auditedRoutes[c.Request().Method+" "+c.Path()]
+		if !ok {
+			return next(c)
+		}
+
+		var body []byte
+		if c.Request().Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request().Body)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, ErrorResponse{
+					Message: fmt.Sprintf("Bad request: %s", err.Error()),
+				})
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		handlerErr := next(c)
+
+		status := c.Response().Status
+		if handlerErr != nil {
+			status = http.StatusInternalServerError
+			var httpErr *echo.HTTPError
+			if errors.As(handlerErr, &httpErr) {
+				status = httpErr.Code
+			}
+		}
+		outcome := audit.OutcomeSuccess
+		if status >= http.StatusBadRequest {
+			outcome = audit.OutcomeFailure
+		}
+
+		entry := &lokidb.AuditLogEntry{
+			Action:     action,
+			RemoteIp:   c.RealIP(),
+			RequestId:  c.Response().Header().Get(echo.HeaderXRequestID),
+			Method:     c.Request().Method,
+			Path:       c.Request().URL.Path,
+			Payload:    audit.RedactPayload(body),
+			Outcome:    outcome,
+			StatusCode: status,
+		}
+		entry.Actor, entry.ApiTokenId = requestActor(c)
+		if err := httpSvc.auditSvc.Record(entry); err != nil {
+			httpSvc.logger.Error().Err(err).Str("action", action).Msg("Failed to record audit log entry")
+		}
+
+		return handlerErr
+	}
+}
+
+// requestActor describes who made a request: an API token, an unlocked
+// session or, on routes outside the /api group, whoever knew the unlock
+// password
+func requestActor(c echo.Context) (string, *uint) {
+	if apiToken, ok := c.Get(apiTokenContextKey).(*lokidb.ApiToken); ok {
+		apiTokenId := apiToken.ID
+		return "token:" + apiToken.Name, &apiTokenId
+	}
+	if token, ok := c.Get("user").(*jwt.Token); ok {
+		if claims, ok := token.Claims.(*jwtCustomClaims); ok && claims.Permission != "" {
+			return "session:" + claims.Permission, nil
+		}
+		return "session:full", nil
+	}
+	return "unlock-password", nil
+}
+
+func parseAuditLogFilter(c echo.Context) api.AuditLogFilter {
+	filter := api.AuditLogFilter{
+		Action:  c.QueryParam("action"),
+		Actor:   c.QueryParam("actor"),
+		Outcome: c.QueryParam("outcome"),
+	}
+
+	if apiTokenIdParam := c.QueryParam("apiTokenId"); apiTokenIdParam != "" {
+		if parsedApiTokenId, err := strconv.ParseUint(apiTokenIdParam, 10, 64); err == nil {
+			var unsignedApiTokenId = uint(parsedApiTokenId)
+			filter.ApiTokenId = &unsignedApiTokenId
+		}
+	}
+
+	if fromParam := c.QueryParam("from"); fromParam != "" {
+		if parsedFrom, err := strconv.ParseUint(fromParam, 10, 64); err == nil {
+			filter.From = parsedFrom
+		}
+	}
+
+	if untilParam := c.QueryParam("until"); untilParam != "" {
+		if parsedUntil, err := strconv.ParseUint(untilParam, 10, 64); err == nil {
+			filter.Until = parsedUntil
+		}
+	}
+
+	return filter
+}
+
+func (httpSvc *HttpService) auditLogListHandler(c echo.Context) error {
+	listRequest := &api.ListAuditLogRequest{
+		AuditLogFilter: parseAuditLogFilter(c),
+		Limit:          20,
+	}
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		if parsedLimit, err := strconv.ParseUint(limitParam, 10, 64); err == nil {
+			listRequest.Limit = parsedLimit
+		}
+	}
+
+	if offsetParam := c.QueryParam("offset"); offsetParam != "" {
+		if parsedOffset, err := strconv.ParseUint(offsetParam, 10, 64); err == nil {
+			listRequest.Offset = parsedOffset
+		}
+	}
+
+	entries, err := httpSvc.api.ListAuditLog(listRequest)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+// auditLogExportHandler streams the export as a file download, so it can only
+// report errors as JSON until the first row has been written.
+func (httpSvc *HttpService) auditLogExportHandler(c echo.Context) error {
+	filter := parseAuditLogFilter(c)
+
+	filename := fmt.Sprintf("lokihub-audit-%s.csv", time.Now().Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	err := httpSvc.api.ExportAuditLog(&filter, c.Response())
+	if err != nil {
+		if c.Response().Committed {
+			httpSvc.logger.Error().Err(err).Msg("Failed to finish audit log export")
+			return nil
+		}
+		c.Response().Header().Del(echo.HeaderContentDisposition)
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: fmt.Sprintf("Failed to export audit log: %s", err.Error()),
+		})
+	}
+	return nil
+}
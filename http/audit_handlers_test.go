@@ -0,0 +1,115 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/apitokens"
+	"github.com/flokiorg/lokihub/audit"
+	"github.com/flokiorg/lokihub/config"
+	lokidb "github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/tests/db"
+	"github.com/flokiorg/lokihub/tests/mocks"
+)
+
+func TestAuditLog(t *testing.T) {
+	e := echo.New()
+	logger.Init(strconv.Itoa(int(4)))
+	mockSvc := mocks.NewMockService(t)
+	gormDb, err := db.NewDB(t)
+	require.NoError(t, err)
+	defer db.CloseDB(gormDb)
+
+	mockConfig := mocks.NewMockConfig(t)
+	mockConfig.On("GetEnv").Return(&config.AppConfig{})
+	mockConfig.On("CheckUnlockPassword", "123").Return(true)
+	mockConfig.On("GetJWTSecret").Return("dummy secret", nil)
+
+	mockSvc.On("GetDB").Return(gormDb)
+	mockSvc.On("GetConfig").Return(mockConfig)
+	mockKeys := mocks.NewMockKeys(t)
+	mockKeys.On("GetNostrPublicKey").Return("c3e1f5a4b1c2d3e4f5a6b7c8d9e0f1aa5f8b05a3b14d0e0d3d0dcf2e2db2c5c1").Maybe()
+
+	mockSvc.On("GetKeys").Return(mockKeys)
+	mockSvc.On("GetLokiSvc").Return(mocks.NewMockLokiService(t))
+	mockSvc.On("GetAppStoreSvc").Return(&mocks.MockAppStoreService{})
+
+	httpSvc := NewHttpService(mockSvc, events.NewEventPublisher())
+	httpSvc.RegisterSharedRoutes(e)
+
+	app := lokidb.App{Name: "Test app", AppPubkey: "a5f8b05a3b14d0e0d3d0dcf2e2db2c5c1a3e1f5a4b1c2d3e4f5a6b7c8d9e0f1a"}
+	require.NoError(t, gormDb.Create(&app).Error)
+
+	apiTokensSvc := apitokens.NewApiTokensService(gormDb)
+	_, readToken, err := apiTokensSvc.CreateToken("read", []string{apitokens.ScopeRead}, nil, nil)
+	require.NoError(t, err)
+	appApiToken, appToken, err := apiTokensSvc.CreateToken("app", []string{apitokens.ScopeAppsWrite}, &app.ID, nil)
+	require.NoError(t, err)
+
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(t.Context(), method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// refused and successful requests are both recorded, reads are not
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodDelete, "/api/apps/"+app.AppPubkey, readToken).Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/apps", readToken).Code)
+	rec := serve(http.MethodDelete, "/api/apps/"+app.AppPubkey, appToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	unlockBody, _ := json.Marshal(api.UnlockRequest{UnlockPassword: "123", Permission: "full"})
+	unlockReq := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/unlock", bytes.NewBuffer(unlockBody))
+	unlockReq.Header.Set("Content-Type", "application/json")
+	unlockRec := httptest.NewRecorder()
+	e.ServeHTTP(unlockRec, unlockReq)
+	require.Equal(t, http.StatusOK, unlockRec.Code)
+	var unlockResponse struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(unlockRec.Body.Bytes(), &unlockResponse))
+
+	// API tokens cannot read the audit log
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/audit", readToken).Code)
+
+	listRec := serve(http.MethodGet, "/api/audit?action=app.delete", unlockResponse.Token)
+	require.Equal(t, http.StatusOK, listRec.Code)
+	var listResponse api.ListAuditLogResponse
+	require.NoError(t, json.Unmarshal(listRec.Body.Bytes(), &listResponse))
+	assert.Equal(t, int64(2), listResponse.TotalCount)
+	require.Len(t, listResponse.Entries, 2)
+
+	deleted := listResponse.Entries[0]
+	assert.Equal(t, "token:app", deleted.Actor)
+	require.NotNil(t, deleted.ApiTokenId)
+	assert.Equal(t, appApiToken.ID, *deleted.ApiTokenId)
+	assert.Equal(t, audit.OutcomeSuccess, deleted.Outcome)
+	assert.Equal(t, http.StatusNoContent, deleted.StatusCode)
+	assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), deleted.RequestId)
+	assert.NotEmpty(t, deleted.RequestId)
+	assert.Equal(t, "/api/apps/"+app.AppPubkey, deleted.Path)
+
+	refused := listResponse.Entries[1]
+	assert.Equal(t, "token:read", refused.Actor)
+	assert.Equal(t, audit.OutcomeFailure, refused.Outcome)
+	assert.Equal(t, http.StatusForbidden, refused.StatusCode)
+
+	exportRec := serve(http.MethodGet, "/api/audit/export?outcome=failure", unlockResponse.Token)
+	require.Equal(t, http.StatusOK, exportRec.Code)
+	assert.Contains(t, exportRec.Header().Get(echo.HeaderContentDisposition), "lokihub-audit-")
+	assert.Contains(t, exportRec.Body.String(), "token:read")
+	assert.NotContains(t, exportRec.Body.String(), "token:app")
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lsps/manager"
+)
+
+func (httpSvc *HttpService) channelAcceptorPolicyHandler(c echo.Context) error {
+	policy, err := httpSvc.api.GetChannelAcceptorPolicy()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to get channel acceptor policy: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, policy)
+}
+
+func (httpSvc *HttpService) updateChannelAcceptorPolicyHandler(c echo.Context) error {
+	var policy manager.ChannelAcceptorPolicy
+	if err := c.Bind(&policy); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	if err := httpSvc.api.UpdateChannelAcceptorPolicy(&policy); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: fmt.Sprintf("Failed to update channel acceptor policy: %s", err.Error()),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) channelAcceptDecisionsListHandler(c echo.Context) error {
+	listRequest := &api.ListChannelAcceptDecisionsRequest{
+		Limit: 20,
+	}
+
+	if acceptedParam := c.QueryParam("accepted"); acceptedParam != "" {
+		if accepted, err := strconv.ParseBool(acceptedParam); err == nil {
+			listRequest.Accepted = &accepted
+		}
+	}
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		if parsedLimit, err := strconv.ParseUint(limitParam, 10, 64); err == nil {
+			listRequest.Limit = parsedLimit
+		}
+	}
+
+	if offsetParam := c.QueryParam("offset"); offsetParam != "" {
+		if parsedOffset, err := strconv.ParseUint(offsetParam, 10, 64); err == nil {
+			listRequest.Offset = parsedOffset
+		}
+	}
+
+	decisions, err := httpSvc.api.ListChannelAcceptDecisions(listRequest)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to list channel acceptor decisions: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, decisions)
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/feemanager"
+)
+
+func (httpSvc *HttpService) channelFeeManagerConfigHandler(c echo.Context) error {
+	feeManagerConfig, err := httpSvc.api.GetChannelFeeManagerConfig()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to get channel fee manager config: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, feeManagerConfig)
+}
+
+func (httpSvc *HttpService) updateChannelFeeManagerConfigHandler(c echo.Context) error {
+	var feeManagerConfig feemanager.Config
+	if err := c.Bind(&feeManagerConfig); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	if err := httpSvc.api.UpdateChannelFeeManagerConfig(&feeManagerConfig); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: fmt.Sprintf("Failed to update channel fee manager config: %s", err.Error()),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) channelFeeUpdatesListHandler(c echo.Context) error {
+	listRequest := &api.ListChannelFeeUpdatesRequest{
+		ChannelId: c.QueryParam("channelId"),
+		Limit:     20,
+	}
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		if parsedLimit, err := strconv.ParseUint(limitParam, 10, 64); err == nil {
+			listRequest.Limit = parsedLimit
+		}
+	}
+
+	if offsetParam := c.QueryParam("offset"); offsetParam != "" {
+		if parsedOffset, err := strconv.ParseUint(offsetParam, 10, 64); err == nil {
+			listRequest.Offset = parsedOffset
+		}
+	}
+
+	feeUpdates, err := httpSvc.api.ListChannelFeeUpdates(listRequest)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to list channel fee updates: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, feeUpdates)
+}
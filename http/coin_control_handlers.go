@@ -0,0 +1,120 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+func (httpSvc *HttpService) listUtxosHandler(c echo.Context) error {
+	utxos, err := httpSvc.api.ListUtxos(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to list UTXOs: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, utxos)
+}
+
+func (httpSvc *HttpService) updateUtxoHandler(c echo.Context) error {
+	var updateUtxoRequest api.UpdateUtxoRequest
+	if err := c.Bind(&updateUtxoRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	if err := httpSvc.api.UpdateUtxo(c.Request().Context(), &updateUtxoRequest); err != nil {
+		return c.JSON(coinControlErrorStatus(err), ErrorResponse{
+			Message: fmt.Sprintf("Failed to update UTXO: %s", err.Error()),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) createPsbtHandler(c echo.Context) error {
+	var createPsbtRequest api.CreatePsbtRequest
+	if err := c.Bind(&createPsbtRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	createPsbtResponse, err := httpSvc.api.CreatePsbt(totpContext(c, ""), &createPsbtRequest)
+	if err != nil {
+		return c.JSON(coinControlErrorStatus(err), ErrorResponse{
+			Message: fmt.Sprintf("Failed to create PSBT: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, createPsbtResponse)
+}
+
+func (httpSvc *HttpService) finalizePsbtHandler(c echo.Context) error {
+	var finalizePsbtRequest api.FinalizePsbtRequest
+	if err := c.Bind(&finalizePsbtRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	finalizePsbtResponse, err := httpSvc.api.FinalizePsbt(totpContext(c, ""), &finalizePsbtRequest)
+	if err != nil {
+		return c.JSON(coinControlErrorStatus(err), ErrorResponse{
+			Message: fmt.Sprintf("Failed to finalize PSBT: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, finalizePsbtResponse)
+}
+
+func (httpSvc *HttpService) publishTransactionHandler(c echo.Context) error {
+	var publishTransactionRequest api.PublishTransactionRequest
+	if err := c.Bind(&publishTransactionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	publishTransactionResponse, err := httpSvc.api.PublishTransaction(totpContext(c, ""), &publishTransactionRequest)
+	if err != nil {
+		return c.JSON(coinControlErrorStatus(err), ErrorResponse{
+			Message: fmt.Sprintf("Failed to publish transaction: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, publishTransactionResponse)
+}
+
+func (httpSvc *HttpService) bumpFeeHandler(c echo.Context) error {
+	var bumpFeeRequest api.BumpFeeRequest
+	if err := c.Bind(&bumpFeeRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	bumpFeeResponse, err := httpSvc.api.BumpFee(c.Request().Context(), &bumpFeeRequest)
+	if err != nil {
+		return c.JSON(coinControlErrorStatus(err), ErrorResponse{
+			Message: fmt.Sprintf("Failed to bump fee: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, bumpFeeResponse)
+}
+
+func coinControlErrorStatus(err error) int {
+	if errors.Is(err, constants.ErrInvalidParams) || errors.Is(err, lnclient.ErrTransactionNotBumpable) {
+		return http.StatusBadRequest
+	}
+	return paymentErrorStatus(err)
+}
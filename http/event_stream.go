@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/events"
+)
+
+// streamEvents serves the events accepted by match as Server-Sent Events
+// until the client disconnects or the service shuts down.
+func (httpSvc *HttpService) streamEvents(c echo.Context, match func(event *events.Event) bool) error {
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+
+	// Create a channel for this client
+	eventChan := make(chan *events.Event, 16)
+
+	subscriber := &eventStreamSubscriber{
+		handler: func(event *events.Event) {
+			if match(event) {
+				select {
+				case eventChan <- event:
+				default:
+					// Channel full, skip event
+				}
+			}
+		},
+	}
+	httpSvc.eventPublisher.RegisterSubscriber(subscriber)
+	defer httpSvc.eventPublisher.RemoveSubscriber(subscriber)
+
+	// Send keepalive and events
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-httpSvc.shutdownCh:
+			return nil
+		case <-ticker.C:
+			// Send keepalive comment
+			if _, err := c.Response().Write([]byte(": keepalive\n\n")); err != nil {
+				return nil
+			}
+			c.Response().Flush()
+		case event := <-eventChan:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event.Event, string(data)); err != nil {
+				return nil
+			}
+			c.Response().Flush()
+		}
+	}
+}
+
+type eventStreamSubscriber struct {
+	handler func(event *events.Event)
+}
+
+func (s *eventStreamSubscriber) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
+	s.handler(event)
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/freeze"
+)
+
+// paymentErrorStatus is the status of a failed admin API payment. Payments
+// refused by an emergency freeze are reported as 423 Locked, those refused by
+// a TOTP check as 403 or 429.
+func paymentErrorStatus(err error) int {
+	if errors.Is(err, freeze.ErrOutgoingPaymentsPaused) {
+		return http.StatusLocked
+	}
+	if status, ok := totpErrorStatus(err); ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func (httpSvc *HttpService) freezeStateHandler(c echo.Context) error {
+	state, err := httpSvc.api.GetFreezeState()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to get freeze state: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, state)
+}
+
+func (httpSvc *HttpService) freezeHandler(c echo.Context) error {
+	var freezeRequest api.FreezeRequest
+	if err := c.Bind(&freezeRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	state, err := httpSvc.api.Freeze(&freezeRequest, freeze.SourceApi)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to freeze: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, state)
+}
+
+func (httpSvc *HttpService) unfreezeHandler(c echo.Context) error {
+	state, err := httpSvc.api.Unfreeze(freeze.SourceApi)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to unfreeze: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, state)
+}
+
+func (httpSvc *HttpService) appFreezeHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "invalid app ID",
+		})
+	}
+
+	var freezeAppRequest api.FreezeAppRequest
+	if err := c.Bind(&freezeAppRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	if err := httpSvc.api.SetAppFrozen(uint(id), &freezeAppRequest); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, freeze.ErrAppNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,11 +28,17 @@ import (
 	"github.com/flokiorg/lokihub/constants"
 	lokidb "github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/export"
 	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/metrics"
+	"github.com/flokiorg/lokihub/nostrauth"
 	"github.com/flokiorg/lokihub/service"
+	"github.com/flokiorg/lokihub/totp"
 	"github.com/flokiorg/lokihub/transactions"
 
 	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/apitokens"
+	"github.com/flokiorg/lokihub/audit"
 	"github.com/flokiorg/lokihub/frontend"
 )
 
@@ -55,6 +62,11 @@ type HttpService struct {
 	db             *gorm.DB
 	appsSvc        apps.AppsService
 	appStoreSvc    appstore.Service
+	apiTokensSvc   apitokens.ApiTokensService
+	auditSvc       audit.AuditService
+	nostrAuthSvc   nostrauth.NostrAuthService
+	totpSvc        totp.TotpService
+	metricsSvc     metrics.MetricsService
 	logger         zerolog.Logger
 
 	shutdownOnce sync.Once
@@ -62,7 +74,7 @@ type HttpService struct {
 }
 
 func NewHttpService(svc service.Service, eventPublisher events.EventPublisher) *HttpService {
-	return &HttpService{
+	httpSvc := &HttpService{
 		api:            api.NewAPI(svc, svc.GetDB(), svc.GetConfig(), svc.GetKeys(), svc.GetLokiSvc(), eventPublisher),
 		lokiHttpSvc:    NewLokiHttpService(svc, svc.GetLokiSvc(), svc.GetConfig().GetEnv()),
 		cfg:            svc.GetConfig(),
@@ -70,9 +82,22 @@ func NewHttpService(svc service.Service, eventPublisher events.EventPublisher) *
 		db:             svc.GetDB(),
 		appsSvc:        apps.NewAppsService(svc.GetDB(), eventPublisher, svc.GetKeys(), svc.GetConfig()),
 		appStoreSvc:    svc.GetAppStoreSvc(),
+		apiTokensSvc:   apitokens.NewApiTokensService(svc.GetDB()),
+		auditSvc:       audit.NewAuditService(svc.GetDB()),
+		nostrAuthSvc:   nostrauth.NewNostrAuthService(svc.GetDB()),
+		totpSvc:        totp.NewTotpService(svc.GetDB(), svc.GetConfig()),
 		logger:         logger.Logger.With().Str("component", "http").Logger(),
 		shutdownCh:     make(chan struct{}),
 	}
+
+	// metrics are only collected when there is a token to scrape them with
+	if svc.GetConfig().GetEnv().MetricsToken != "" {
+		metricsSvc := metrics.NewMetricsService(svc)
+		eventPublisher.RegisterSubscriber(metricsSvc)
+		httpSvc.metricsSvc = metricsSvc
+	}
+
+	return httpSvc
 }
 
 // Shutdown signals long-lived handlers (e.g. SSE streams) to stop.
@@ -144,7 +169,9 @@ func (httpSvc *HttpService) RegisterSharedRoutes(e *echo.Echo) {
 	unlockRateLimiter := middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(1))
 	e.POST("/api/start", httpSvc.startHandler, unlockRateLimiter)
 	e.POST("/api/unlock", httpSvc.unlockHandler, unlockRateLimiter)
-	e.POST("/api/backup", httpSvc.createBackupHandler, unlockRateLimiter)
+	// NIP-98: admin pubkeys log in with a signed event instead of the password
+	e.POST("/api/unlock/nostr", httpSvc.nostrUnlockHandler, unlockRateLimiter)
+	e.POST("/api/backup", httpSvc.createBackupHandler, unlockRateLimiter, httpSvc.auditLog)
 	e.GET("/logout", httpSvc.logoutHandler, unlockRateLimiter)
 
 	// Redirect /wallet/swap to /settings if swap is disabled
@@ -177,9 +204,20 @@ func (httpSvc *HttpService) RegisterSharedRoutes(e *echo.Echo) {
 		},
 		TokenLookup: "header:Authorization:Bearer ,query:token",
 	}
-	// Read-only API group - accessible to both full and readonly tokens
-	readOnlyApiGroup := e.Group("/api")
-	readOnlyApiGroup.Use(echojwt.WithConfig(jwtConfig))
+	// Every /api route below accepts either an unlock JWT or an API token and
+	// requires the scope of its group. Full access JWTs hold every scope,
+	// read-only JWTs only the read scope, API tokens the scopes they were
+	// created with. Changes to the hub and secret reveals are recorded in the
+	// audit log (see auditedRoutes).
+	apiGroup := e.Group("/api", httpSvc.authenticate(echojwt.WithConfig(jwtConfig)), httpSvc.auditLog)
+	readOnlyApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopeRead))
+	appsApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopeAppsWrite))
+	paymentsApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopePaymentsSend))
+	invoicesApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopeInvoicesWrite))
+	channelsApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopeChannelsManage))
+	swapsApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopeSwaps))
+	settingsApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopeSettings))
+	adminApiGroup := apiGroup.Group("", httpSvc.requireScope(apitokens.ScopeAdmin))
 
 	// Avatar proxy — see avatar_proxy.go. Requires login: unlike the app
 	// store logos route, this one fetches an arbitrary caller-supplied URL
@@ -194,6 +232,10 @@ func (httpSvc *HttpService) RegisterSharedRoutes(e *echo.Echo) {
 	readOnlyApiGroup.GET("/apps/:pubkey", httpSvc.appsShowByPubkeyHandler)
 	readOnlyApiGroup.GET("/apps/:id", httpSvc.appsShowHandler)
 	readOnlyApiGroup.GET("/channels", httpSvc.channelsListHandler)
+	readOnlyApiGroup.GET("/channels/acceptor", httpSvc.channelAcceptorPolicyHandler)
+	readOnlyApiGroup.GET("/channels/acceptor/decisions", httpSvc.channelAcceptDecisionsListHandler)
+	readOnlyApiGroup.GET("/channels/fees/manager", httpSvc.channelFeeManagerConfigHandler)
+	readOnlyApiGroup.GET("/channels/fees/history", httpSvc.channelFeeUpdatesListHandler)
 	readOnlyApiGroup.POST("/invoices/estimate-fee", httpSvc.estimateInvoiceFeeHandler)
 
 	readOnlyApiGroup.GET("/node/connection-info", httpSvc.nodeConnectionInfoHandler)
@@ -203,8 +245,12 @@ func (httpSvc *HttpService) RegisterSharedRoutes(e *echo.Echo) {
 	readOnlyApiGroup.GET("/peers", httpSvc.listPeers)
 	readOnlyApiGroup.GET("/wallet/address", httpSvc.onchainAddressHandler)
 	readOnlyApiGroup.GET("/wallet/capabilities", httpSvc.capabilitiesHandler)
+	readOnlyApiGroup.GET("/wallet/utxos", httpSvc.listUtxosHandler)
 	readOnlyApiGroup.GET("/transactions", httpSvc.listTransactionsHandler)
+	readOnlyApiGroup.GET("/transactions/export", httpSvc.exportTransactionsHandler)
 	readOnlyApiGroup.GET("/transactions/:paymentHash", httpSvc.lookupTransactionHandler)
+	readOnlyApiGroup.GET("/offers/:offer", httpSvc.lookupOfferHandler)
+	readOnlyApiGroup.GET("/lightning-addresses", httpSvc.lightningAddressesListHandler)
 	readOnlyApiGroup.GET("/balances", httpSvc.balancesHandler)
 	readOnlyApiGroup.GET("/mempool", httpSvc.mempoolApiHandler)
 	readOnlyApiGroup.GET("/log/:type", httpSvc.getLogOutputHandler)
@@ -214,98 +260,162 @@ func (httpSvc *HttpService) RegisterSharedRoutes(e *echo.Echo) {
 	readOnlyApiGroup.GET("/swaps/:swapId", httpSvc.lookupSwapHandler)
 	readOnlyApiGroup.GET("/swaps/out/info", httpSvc.getSwapOutInfoHandler)
 	readOnlyApiGroup.GET("/swaps/in/info", httpSvc.getSwapInInfoHandler)
-	readOnlyApiGroup.GET("/swaps/mnemonic", httpSvc.swapMnemonicHandler)
 	readOnlyApiGroup.GET("/autoswap", httpSvc.getAutoSwapConfigHandler)
+	readOnlyApiGroup.GET("/autoliquidity", httpSvc.getAutoLiquidityConfigHandler)
+	readOnlyApiGroup.GET("/autorebalance", httpSvc.getAutoRebalanceConfigHandler)
+	readOnlyApiGroup.GET("/rebalances", httpSvc.rebalancesListHandler)
 	readOnlyApiGroup.GET("/forwards", httpSvc.forwardsHandler)
+	readOnlyApiGroup.GET("/approvals", httpSvc.approvalsListHandler)
+	readOnlyApiGroup.GET("/webhooks", httpSvc.webhookEndpointsListHandler)
+	readOnlyApiGroup.GET("/webhooks/deliveries", httpSvc.webhookDeliveriesListHandler)
 	readOnlyApiGroup.GET("/appstore/apps", httpSvc.getAppStoreAppsHandler)
 	readOnlyApiGroup.GET("/lsps2/info", httpSvc.getLSPS2InfoHandler)
 
-	// Full access API group - requires a token with full permissions
-	fullAccessApiGroup := e.Group("/api")
-	fullAccessApiGroup.Use(echojwt.WithConfig(jwtConfig))
-	fullAccessApiGroup.Use(httpSvc.requireFullAccess)
-
-	fullAccessApiGroup.POST("/api/event", httpSvc.eventHandler)
-	fullAccessApiGroup.PATCH("/unlock-password", httpSvc.changeUnlockPasswordHandler)
-	fullAccessApiGroup.PATCH("/auto-unlock", httpSvc.autoUnlockHandler)
-	fullAccessApiGroup.PATCH("/settings", httpSvc.updateSettingsHandler)
-	fullAccessApiGroup.PATCH("/apps/:pubkey", httpSvc.appsUpdateHandler)
-	fullAccessApiGroup.DELETE("/apps/:pubkey", httpSvc.appsDeleteHandler)
-	fullAccessApiGroup.POST("/transfers", httpSvc.transfersHandler)
-	fullAccessApiGroup.POST("/apps", httpSvc.appsCreateHandler)
-	fullAccessApiGroup.GET("/apps/:id/circle/allowlist", httpSvc.circleAllowlistListHandler)
-	fullAccessApiGroup.PUT("/apps/:id/circle/allowlist", httpSvc.circleAllowlistReplaceHandler)
-	fullAccessApiGroup.DELETE("/apps/:id/circle/allowlist/:pubkey", httpSvc.circleAllowlistRemoveHandler)
-	fullAccessApiGroup.POST("/apps/:id/circle/refresh/preview", httpSvc.circleAllowlistRefreshPreviewHandler)
-	fullAccessApiGroup.POST("/apps/:id/circle/refresh", httpSvc.circleAllowlistRefreshHandler)
-	fullAccessApiGroup.GET("/apps/:id/circle/children", httpSvc.circleChildrenListHandler)
-	fullAccessApiGroup.DELETE("/apps/:id/circle/children/:childId", httpSvc.circleChildDeleteHandler)
-	fullAccessApiGroup.POST("/apps/:id/circle/delete", httpSvc.circleHubDeleteHandler)
-	fullAccessApiGroup.GET("/circle-identities", httpSvc.circleIdentitiesListHandler)
-	fullAccessApiGroup.GET("/circle-identities/:id", httpSvc.circleIdentityGetHandler)
-	fullAccessApiGroup.DELETE("/circle-identities/:id", httpSvc.circleIdentityDeleteHandler)
-	fullAccessApiGroup.GET("/apps/:id/jit-wallets", httpSvc.jitWalletClaimsListHandler)
-	fullAccessApiGroup.POST("/apps/:id/jit-wallets", httpSvc.jitWalletsCreateHandler)
-	fullAccessApiGroup.DELETE("/apps/:id/jit-wallets/:walletId", httpSvc.jitWalletDeleteHandler)
-	fullAccessApiGroup.DELETE("/apps/:id/jit-wallets/:walletId/claims/:claimId", httpSvc.jitWalletClaimDeleteHandler)
-	fullAccessApiGroup.GET("/apps/:id/jit-connection", httpSvc.jitWalletConnectionHandler)
-	fullAccessApiGroup.GET("/apps/:id/jit-wallet-recipients", httpSvc.jitWalletRecipientsHandler)
-	fullAccessApiGroup.GET("/identity-authorities", httpSvc.identityAuthoritiesListHandler)
-	fullAccessApiGroup.POST("/identity-authorities", httpSvc.identityAuthoritiesCreateHandler)
-	fullAccessApiGroup.DELETE("/identity-authorities/:pubkey", httpSvc.identityAuthoritiesDeleteHandler)
-
-	fullAccessApiGroup.POST("/mnemonic", httpSvc.mnemonicHandler)
-	fullAccessApiGroup.PATCH("/backup-reminder", httpSvc.backupReminderHandler)
-	fullAccessApiGroup.POST("/channels", httpSvc.openChannelHandler)
-
-	fullAccessApiGroup.POST("/node/migrate-storage", httpSvc.migrateNodeStorageHandler)
-	fullAccessApiGroup.POST("/peers", httpSvc.connectPeerHandler)
-	fullAccessApiGroup.DELETE("/peers/:peerId", httpSvc.disconnectPeerHandler)
-	fullAccessApiGroup.DELETE("/peers/:peerId/channels/:channelId", httpSvc.closeChannelHandler)
-	fullAccessApiGroup.PATCH("/peers/:peerId/channels/:channelId", httpSvc.updateChannelHandler)
-	fullAccessApiGroup.POST("/wallet/new-address", httpSvc.newOnchainAddressHandler)
-	fullAccessApiGroup.POST("/wallet/redeem-onchain-funds", httpSvc.redeemOnchainFundsHandler)
-	fullAccessApiGroup.POST("/wallet/sign-message", httpSvc.signMessageHandler)
-	fullAccessApiGroup.POST("/wallet/sync", httpSvc.walletSyncHandler)
-	fullAccessApiGroup.POST("/payments/:invoice", httpSvc.sendPaymentHandler)
-	fullAccessApiGroup.POST("/invoices", httpSvc.makeInvoiceHandler)
-
-	fullAccessApiGroup.POST("/reset-router", httpSvc.resetRouterHandler)
-	fullAccessApiGroup.POST("/stop", httpSvc.stopHandler)
-	fullAccessApiGroup.POST("/send-payment-probes", httpSvc.sendPaymentProbesHandler)
-	fullAccessApiGroup.POST("/send-spontaneous-payment-probes", httpSvc.sendSpontaneousPaymentProbesHandler)
-	fullAccessApiGroup.POST("/command", httpSvc.execCustomNodeCommandHandler)
-	fullAccessApiGroup.POST("/swaps/out", httpSvc.initiateSwapOutHandler)
-	fullAccessApiGroup.POST("/swaps/in", httpSvc.initiateSwapInHandler)
-	fullAccessApiGroup.POST("/swaps/refund", httpSvc.refundSwapHandler)
-	fullAccessApiGroup.POST("/autoswap", httpSvc.enableAutoSwapOutHandler)
-	fullAccessApiGroup.DELETE("/autoswap", httpSvc.disableAutoSwapOutHandler)
-	fullAccessApiGroup.POST("/node/alias", httpSvc.setNodeAliasHandler)
-	fullAccessApiGroup.POST("/lsps2/buy", httpSvc.buyLSPS2LiquidityHandler)
-
-	fullAccessApiGroup.GET("/lsps", httpSvc.listLSPsHandler)
-	fullAccessApiGroup.POST("/lsps", httpSvc.addLSPHandler)
-	fullAccessApiGroup.PUT("/lsps/:pubkey", httpSvc.updateLSPHandler)
-	fullAccessApiGroup.DELETE("/lsps/:pubkey", httpSvc.deleteLSPHandler)
+	settingsApiGroup.POST("/api/event", httpSvc.eventHandler)
+	adminApiGroup.PATCH("/unlock-password", httpSvc.changeUnlockPasswordHandler)
+	adminApiGroup.PATCH("/auto-unlock", httpSvc.autoUnlockHandler)
+	adminApiGroup.GET("/api-tokens", httpSvc.apiTokensListHandler)
+	adminApiGroup.POST("/api-tokens", httpSvc.apiTokenCreateHandler)
+	adminApiGroup.DELETE("/api-tokens/:id", httpSvc.apiTokenRevokeHandler)
+	adminApiGroup.GET("/admin-pubkeys", httpSvc.adminPubkeysListHandler)
+	adminApiGroup.POST("/admin-pubkeys", httpSvc.adminPubkeyAddHandler)
+	adminApiGroup.DELETE("/admin-pubkeys/:id", httpSvc.adminPubkeyRemoveHandler)
+	readOnlyApiGroup.GET("/freeze", httpSvc.freezeStateHandler)
+	// anyone allowed to change settings can freeze, but only full access
+	// can lift it
+	settingsApiGroup.POST("/freeze", httpSvc.freezeHandler)
+	adminApiGroup.DELETE("/freeze", httpSvc.unfreezeHandler)
+	adminApiGroup.GET("/totp", httpSvc.totpStatusHandler)
+	adminApiGroup.PATCH("/totp", httpSvc.totpUpdateHandler)
+	adminApiGroup.POST("/totp/enroll", httpSvc.totpEnrollHandler)
+	adminApiGroup.POST("/totp/confirm", httpSvc.totpConfirmHandler)
+	adminApiGroup.POST("/totp/disable", httpSvc.totpDisableHandler)
+	adminApiGroup.POST("/totp/recovery-codes", httpSvc.totpRecoveryCodesHandler)
+	adminApiGroup.GET("/audit", httpSvc.auditLogListHandler)
+	adminApiGroup.GET("/audit/export", httpSvc.auditLogExportHandler)
+	settingsApiGroup.PATCH("/settings", httpSvc.updateSettingsHandler)
+	appsApiGroup.PATCH("/apps/:pubkey", httpSvc.appsUpdateHandler)
+	appsApiGroup.DELETE("/apps/:pubkey", httpSvc.appsDeleteHandler)
+	paymentsApiGroup.POST("/transfers", httpSvc.transfersHandler)
+	appsApiGroup.POST("/apps", httpSvc.appsCreateHandler)
+	appsApiGroup.GET("/apps/:id/circle/allowlist", httpSvc.circleAllowlistListHandler)
+	appsApiGroup.PUT("/apps/:id/circle/allowlist", httpSvc.circleAllowlistReplaceHandler)
+	appsApiGroup.DELETE("/apps/:id/circle/allowlist/:pubkey", httpSvc.circleAllowlistRemoveHandler)
+	appsApiGroup.POST("/apps/:id/circle/refresh/preview", httpSvc.circleAllowlistRefreshPreviewHandler)
+	appsApiGroup.POST("/apps/:id/circle/refresh", httpSvc.circleAllowlistRefreshHandler)
+	appsApiGroup.GET("/apps/:id/circle/children", httpSvc.circleChildrenListHandler)
+	appsApiGroup.DELETE("/apps/:id/circle/children/:childId", httpSvc.circleChildDeleteHandler)
+	appsApiGroup.POST("/apps/:id/circle/delete", httpSvc.circleHubDeleteHandler)
+	appsApiGroup.PUT("/apps/:id/freeze", httpSvc.appFreezeHandler)
+	appsApiGroup.GET("/circle-identities", httpSvc.circleIdentitiesListHandler)
+	appsApiGroup.GET("/circle-identities/:id", httpSvc.circleIdentityGetHandler)
+	appsApiGroup.DELETE("/circle-identities/:id", httpSvc.circleIdentityDeleteHandler)
+	appsApiGroup.GET("/apps/:id/jit-wallets", httpSvc.jitWalletClaimsListHandler)
+	appsApiGroup.POST("/apps/:id/jit-wallets", httpSvc.jitWalletsCreateHandler)
+	appsApiGroup.DELETE("/apps/:id/jit-wallets/:walletId", httpSvc.jitWalletDeleteHandler)
+	appsApiGroup.DELETE("/apps/:id/jit-wallets/:walletId/claims/:claimId", httpSvc.jitWalletClaimDeleteHandler)
+	appsApiGroup.GET("/apps/:id/jit-connection", httpSvc.jitWalletConnectionHandler)
+	appsApiGroup.GET("/apps/:id/jit-wallet-recipients", httpSvc.jitWalletRecipientsHandler)
+	appsApiGroup.GET("/identity-authorities", httpSvc.identityAuthoritiesListHandler)
+	appsApiGroup.POST("/identity-authorities", httpSvc.identityAuthoritiesCreateHandler)
+	appsApiGroup.DELETE("/identity-authorities/:pubkey", httpSvc.identityAuthoritiesDeleteHandler)
+
+	adminApiGroup.POST("/mnemonic", httpSvc.mnemonicHandler)
+	// the swap mnemonic can recover swap funds, so it needs full access like
+	// the wallet mnemonic
+	adminApiGroup.GET("/swaps/mnemonic", httpSvc.swapMnemonicHandler)
+	settingsApiGroup.PATCH("/backup-reminder", httpSvc.backupReminderHandler)
+	channelsApiGroup.POST("/channels", httpSvc.openChannelHandler)
+	channelsApiGroup.POST("/channels/batch", httpSvc.batchOpenChannelHandler)
+	channelsApiGroup.PUT("/channels/acceptor", httpSvc.updateChannelAcceptorPolicyHandler)
+	channelsApiGroup.PUT("/channels/fees/manager", httpSvc.updateChannelFeeManagerConfigHandler)
+
+	adminApiGroup.POST("/node/migrate-storage", httpSvc.migrateNodeStorageHandler)
+	channelsApiGroup.POST("/peers", httpSvc.connectPeerHandler)
+	channelsApiGroup.DELETE("/peers/:peerId", httpSvc.disconnectPeerHandler)
+	channelsApiGroup.DELETE("/peers/:peerId/channels/:channelId", httpSvc.closeChannelHandler)
+	channelsApiGroup.PATCH("/peers/:peerId/channels/:channelId", httpSvc.updateChannelHandler)
+	invoicesApiGroup.POST("/wallet/new-address", httpSvc.newOnchainAddressHandler)
+	paymentsApiGroup.POST("/wallet/redeem-onchain-funds", httpSvc.redeemOnchainFundsHandler)
+	settingsApiGroup.POST("/wallet/sign-message", httpSvc.signMessageHandler)
+	channelsApiGroup.POST("/wallet/sync", httpSvc.walletSyncHandler)
+	paymentsApiGroup.PUT("/wallet/utxos", httpSvc.updateUtxoHandler)
+	paymentsApiGroup.POST("/wallet/psbt", httpSvc.createPsbtHandler)
+	paymentsApiGroup.POST("/wallet/psbt/finalize", httpSvc.finalizePsbtHandler)
+	paymentsApiGroup.POST("/wallet/publish", httpSvc.publishTransactionHandler)
+	paymentsApiGroup.POST("/wallet/bump-fee", httpSvc.bumpFeeHandler)
+	paymentsApiGroup.POST("/payments/:invoice", httpSvc.sendPaymentHandler)
+	invoicesApiGroup.POST("/invoices", httpSvc.makeInvoiceHandler)
+	invoicesApiGroup.POST("/offers", httpSvc.makeOfferHandler)
+	paymentsApiGroup.POST("/offers/pay", httpSvc.payOfferHandler)
+	appsApiGroup.POST("/lightning-addresses", httpSvc.lightningAddressesCreateHandler)
+	appsApiGroup.DELETE("/lightning-addresses/:appId", httpSvc.lightningAddressesDeleteHandler)
+	paymentsApiGroup.POST("/approvals/:id", httpSvc.approvalDecideHandler)
+	settingsApiGroup.POST("/webhooks", httpSvc.webhookEndpointCreateHandler)
+	settingsApiGroup.PATCH("/webhooks/:id", httpSvc.webhookEndpointUpdateHandler)
+	settingsApiGroup.DELETE("/webhooks/:id", httpSvc.webhookEndpointDeleteHandler)
+	settingsApiGroup.POST("/webhooks/deliveries/:id/redeliver", httpSvc.webhookDeliveryRedeliverHandler)
+
+	settingsApiGroup.POST("/reset-router", httpSvc.resetRouterHandler)
+	adminApiGroup.POST("/stop", httpSvc.stopHandler)
+	paymentsApiGroup.POST("/send-payment-probes", httpSvc.sendPaymentProbesHandler)
+	paymentsApiGroup.POST("/send-spontaneous-payment-probes", httpSvc.sendSpontaneousPaymentProbesHandler)
+	adminApiGroup.POST("/command", httpSvc.execCustomNodeCommandHandler)
+	swapsApiGroup.POST("/swaps/out", httpSvc.initiateSwapOutHandler)
+	swapsApiGroup.POST("/swaps/in", httpSvc.initiateSwapInHandler)
+	swapsApiGroup.POST("/swaps/refund", httpSvc.refundSwapHandler)
+	swapsApiGroup.POST("/autoswap", httpSvc.enableAutoSwapOutHandler)
+	swapsApiGroup.DELETE("/autoswap", httpSvc.disableAutoSwapOutHandler)
+	swapsApiGroup.POST("/autoswap/in", httpSvc.enableAutoSwapInHandler)
+	swapsApiGroup.DELETE("/autoswap/in", httpSvc.disableAutoSwapInHandler)
+	channelsApiGroup.POST("/autoliquidity", httpSvc.enableAutoLiquidityHandler)
+	channelsApiGroup.DELETE("/autoliquidity", httpSvc.disableAutoLiquidityHandler)
+	channelsApiGroup.POST("/autorebalance", httpSvc.enableAutoRebalanceHandler)
+	channelsApiGroup.DELETE("/autorebalance", httpSvc.disableAutoRebalanceHandler)
+	channelsApiGroup.POST("/rebalance", httpSvc.rebalanceHandler)
+	settingsApiGroup.POST("/node/alias", httpSvc.setNodeAliasHandler)
+	channelsApiGroup.POST("/lsps2/buy", httpSvc.buyLSPS2LiquidityHandler)
+
+	channelsApiGroup.GET("/lsps", httpSvc.listLSPsHandler)
+	channelsApiGroup.POST("/lsps", httpSvc.addLSPHandler)
+	channelsApiGroup.PUT("/lsps/:pubkey", httpSvc.updateLSPHandler)
+	channelsApiGroup.DELETE("/lsps/:pubkey", httpSvc.deleteLSPHandler)
+	channelsApiGroup.GET("/lsp-service", httpSvc.lspServiceConfigHandler)
+	channelsApiGroup.PUT("/lsp-service", httpSvc.updateLSPServiceConfigHandler)
+	channelsApiGroup.GET("/lsp-service/refunds", httpSvc.lspServiceRefundsHandler)
+	channelsApiGroup.POST("/lsp-service/refunds/:orderId", httpSvc.lspServiceOrderRefundedHandler)
 
 	// LSPS0/1/5
-	fullAccessApiGroup.GET("/lsps0/protocols", httpSvc.lsps0ListProtocolsHandler)
-	fullAccessApiGroup.GET("/lsps1/info", httpSvc.lsps1GetInfoHandler)
-	fullAccessApiGroup.POST("/lsps1/order", httpSvc.lsps1CreateOrderHandler)
-	fullAccessApiGroup.GET("/lsps1/order", httpSvc.lsps1GetOrderHandler)
-	fullAccessApiGroup.GET("/lsps1/orders", httpSvc.lsps1ListOrdersHandler)
-	fullAccessApiGroup.GET("/lsps5/webhooks", httpSvc.lsps5ListWebhooksHandler)
-	fullAccessApiGroup.POST("/lsps5/webhook", httpSvc.lsps5SetWebhookHandler)
-	fullAccessApiGroup.DELETE("/lsps5/webhook", httpSvc.lsps5RemoveWebhookHandler)
+	channelsApiGroup.GET("/lsps0/protocols", httpSvc.lsps0ListProtocolsHandler)
+	channelsApiGroup.GET("/lsps1/info", httpSvc.lsps1GetInfoHandler)
+	channelsApiGroup.POST("/lsps1/order", httpSvc.lsps1CreateOrderHandler)
+	channelsApiGroup.GET("/lsps1/order", httpSvc.lsps1GetOrderHandler)
+	channelsApiGroup.GET("/lsps1/orders", httpSvc.lsps1ListOrdersHandler)
+	channelsApiGroup.GET("/lsps5/webhooks", httpSvc.lsps5ListWebhooksHandler)
+	channelsApiGroup.POST("/lsps5/webhook", httpSvc.lsps5SetWebhookHandler)
+	channelsApiGroup.DELETE("/lsps5/webhook", httpSvc.lsps5RemoveWebhookHandler)
+
+	// Prometheus metrics - authenticated with METRICS_TOKEN instead of a JWT
+	// so scrapers never hold admin credentials
+	if httpSvc.metricsSvc != nil {
+		e.GET("/metrics", echo.WrapHandler(httpSvc.metricsSvc.Handler()), httpSvc.requireMetricsToken)
+	}
+
+	// LNURL-pay / lightning address endpoints - public, payers are not logged in
+	e.GET("/.well-known/lnurlp/:username", httpSvc.lnurlpHandler)
+	e.GET("/lnurlp/:username/callback", httpSvc.lnurlpCallbackHandler)
 
 	// LSPS5 webhook callback - public endpoint for LSPs to send notifications
 	// This must be accessible without auth as external LSPs will call it
 	e.POST("/api/lsps5/webhook-callback", httpSvc.lsps5WebhookCallbackHandler)
 
 	// SSE endpoint for LSPS events - requires auth to subscribe
-	fullAccessApiGroup.GET("/lsps5/events", httpSvc.lsps5EventsSSEHandler)
+	channelsApiGroup.GET("/lsps5/events", httpSvc.lsps5EventsSSEHandler)
+
+	// SSE endpoint for payments waiting for approval
+	paymentsApiGroup.GET("/approvals/events", httpSvc.approvalsEventsSSEHandler)
 
-	httpSvc.lokiHttpSvc.RegisterSharedRoutes(readOnlyApiGroup, fullAccessApiGroup, e)
+	httpSvc.lokiHttpSvc.RegisterSharedRoutes(readOnlyApiGroup, adminApiGroup, e)
 }
 
 func (httpSvc *HttpService) infoHandler(c echo.Context) error {
@@ -366,9 +476,12 @@ func (httpSvc *HttpService) mnemonicHandler(c echo.Context) error {
 		})
 	}
 
-	responseBody, err := httpSvc.api.GetMnemonic(mnemonicRequest.UnlockPassword)
+	responseBody, err := httpSvc.api.GetMnemonic(mnemonicRequest.UnlockPassword, mnemonicRequest.TotpCode)
 
 	if err != nil {
+		if _, ok := totpErrorStatus(err); ok {
+			return httpSvc.totpErrorResponse(c, err, http.StatusForbidden)
+		}
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Message: err.Error(),
 		})
@@ -455,6 +568,13 @@ func (httpSvc *HttpService) unlockHandler(c echo.Context) error {
 		})
 	}
 
+	// read-only sessions cannot reach anything the second factor protects
+	if unlockRequest.Permission == "full" {
+		if err := httpSvc.totpSvc.Verify(unlockRequest.TotpCode, unlockRequest.UnlockPassword); err != nil {
+			return httpSvc.totpErrorResponse(c, err, http.StatusUnauthorized)
+		}
+	}
+
 	token, err := httpSvc.createJWT(unlockRequest.TokenExpiryDays, unlockRequest.Permission)
 
 	if err != nil {
@@ -472,19 +592,16 @@ func (httpSvc *HttpService) unlockHandler(c echo.Context) error {
 	})
 }
 
-func (httpSvc *HttpService) requireFullAccess(next echo.HandlerFunc) echo.HandlerFunc {
+func (httpSvc *HttpService) requireMetricsToken(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		token := c.Get("user").(*jwt.Token)
-		claims := token.Claims.(*jwtCustomClaims)
-
-		// Allow if no permission specified (backward compatibility) or if full access
-		if claims.Permission == "" || claims.Permission == "full" {
-			return next(c)
+		token, found := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+		metricsToken := httpSvc.cfg.GetEnv().MetricsToken
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(metricsToken)) != 1 {
+			return c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Message: "Invalid metrics token",
+			})
 		}
-
-		return c.JSON(http.StatusForbidden, ErrorResponse{
-			Message: "This operation requires full access permissions",
-		})
+		return next(c)
 	}
 }
 
@@ -496,6 +613,16 @@ func (httpSvc *HttpService) changeUnlockPasswordHandler(c echo.Context) error {
 		})
 	}
 
+	if !httpSvc.cfg.CheckUnlockPassword(changeUnlockPasswordRequest.CurrentUnlockPassword) {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: "Invalid password",
+		})
+	}
+
+	if err := httpSvc.totpSvc.Verify(changeUnlockPasswordRequest.TotpCode, changeUnlockPasswordRequest.CurrentUnlockPassword); err != nil {
+		return httpSvc.totpErrorResponse(c, err, http.StatusForbidden)
+	}
+
 	err := httpSvc.api.ChangeUnlockPassword(&changeUnlockPasswordRequest)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -720,10 +847,19 @@ func (httpSvc *HttpService) sendPaymentHandler(c echo.Context) error {
 		})
 	}
 
+	appId, err := restrictToTokenApp(c, payInvoiceRequest.AppId)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+	payInvoiceRequest.AppId = appId
+
+	ctx = totpContext(c, payInvoiceRequest.TotpCode)
 	paymentResponse, err := httpSvc.api.SendPayment(ctx, c.Param("invoice"), payInvoiceRequest.Amount, payInvoiceRequest.AppId, payInvoiceRequest.Metadata)
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		return c.JSON(paymentErrorStatus(err), ErrorResponse{
 			Message: err.Error(),
 		})
 	}
@@ -739,6 +875,14 @@ func (httpSvc *HttpService) makeInvoiceHandler(c echo.Context) error {
 		})
 	}
 
+	appId, err := restrictToTokenApp(c, makeInvoiceRequest.AppId)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+	makeInvoiceRequest.AppId = appId
+
 	invoice, err := httpSvc.api.CreateInvoice(c.Request().Context(), &makeInvoiceRequest)
 
 	if err != nil {
@@ -750,6 +894,72 @@ func (httpSvc *HttpService) makeInvoiceHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, invoice)
 }
 
+func (httpSvc *HttpService) makeOfferHandler(c echo.Context) error {
+	var makeOfferRequest api.MakeOfferRequest
+	if err := c.Bind(&makeOfferRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	appId, err := restrictToTokenApp(c, makeOfferRequest.AppId)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+	makeOfferRequest.AppId = appId
+
+	offer, err := httpSvc.api.CreateOffer(c.Request().Context(), &makeOfferRequest)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, offer)
+}
+
+func (httpSvc *HttpService) payOfferHandler(c echo.Context) error {
+	var payOfferRequest api.PayOfferRequest
+	if err := c.Bind(&payOfferRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	appId, err := restrictToTokenApp(c, payOfferRequest.AppId)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+	payOfferRequest.AppId = appId
+
+	paymentResponse, err := httpSvc.api.PayOffer(totpContext(c, payOfferRequest.TotpCode), &payOfferRequest)
+
+	if err != nil {
+		return c.JSON(paymentErrorStatus(err), ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, paymentResponse)
+}
+
+func (httpSvc *HttpService) lookupOfferHandler(c echo.Context) error {
+	offer, err := httpSvc.api.LookupOffer(c.Request().Context(), c.Param("offer"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, offer)
+}
+
 func (httpSvc *HttpService) lookupTransactionHandler(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -790,6 +1000,13 @@ func (httpSvc *HttpService) listTransactionsHandler(c echo.Context) error {
 		}
 	}
 
+	appId, err := restrictToTokenApp(c, appId)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
 	transactions, err := httpSvc.api.ListTransactions(ctx, appId, limit, offset)
 
 	if err != nil {
@@ -801,6 +1018,62 @@ func (httpSvc *HttpService) listTransactionsHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, transactions)
 }
 
+// exportTransactionsHandler streams the export as a file download, so it
+// can only report errors as JSON until the first row has been written.
+func (httpSvc *HttpService) exportTransactionsHandler(c echo.Context) error {
+	exportRequest := &api.ExportTransactionsRequest{
+		Format: c.QueryParam("format"),
+	}
+	if exportRequest.Format == "" {
+		exportRequest.Format = export.FormatCSV
+	}
+	if err := export.ValidateFormat(exportRequest.Format); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	if fromParam := c.QueryParam("from"); fromParam != "" {
+		if parsedFrom, err := strconv.ParseUint(fromParam, 10, 64); err == nil {
+			exportRequest.From = parsedFrom
+		}
+	}
+
+	if untilParam := c.QueryParam("until"); untilParam != "" {
+		if parsedUntil, err := strconv.ParseUint(untilParam, 10, 64); err == nil {
+			exportRequest.Until = parsedUntil
+		}
+	}
+
+	if appIdParam := c.QueryParam("appId"); appIdParam != "" {
+		if parsedAppId, err := strconv.ParseUint(appIdParam, 10, 64); err == nil {
+			var unsignedAppId = uint(parsedAppId)
+			exportRequest.AppId = &unsignedAppId
+		}
+	}
+
+	filename := fmt.Sprintf("lokihub-transactions-%s.%s", time.Now().Format("20060102"), export.FileExtension(exportRequest.Format))
+	c.Response().Header().Set(echo.HeaderContentType, export.ContentType(exportRequest.Format))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	err := httpSvc.api.ExportTransactions(c.Request().Context(), exportRequest, c.Response())
+	if err != nil {
+		if c.Response().Committed {
+			logger.Logger.Error().Err(err).Msg("Failed to finish transaction export")
+			return nil
+		}
+		c.Response().Header().Del(echo.HeaderContentDisposition)
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: fmt.Sprintf("Failed to export transactions: %s", err.Error()),
+		})
+	}
+	return nil
+}
+
 func (httpSvc *HttpService) listOnchainTransactionsHandler(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -916,7 +1189,7 @@ func (httpSvc *HttpService) connectPeerHandler(c echo.Context) error {
 }
 
 func (httpSvc *HttpService) openChannelHandler(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx := totpContext(c, "")
 
 	var openChannelRequest api.OpenChannelRequest
 	if err := c.Bind(&openChannelRequest); err != nil {
@@ -928,7 +1201,11 @@ func (httpSvc *HttpService) openChannelHandler(c echo.Context) error {
 	openChannelResponse, err := httpSvc.api.OpenChannel(ctx, &openChannelRequest)
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		status := paymentErrorStatus(err)
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
 			Message: fmt.Sprintf("Failed to open channel: %s", err.Error()),
 		})
 	}
@@ -936,6 +1213,31 @@ func (httpSvc *HttpService) openChannelHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, openChannelResponse)
 }
 
+func (httpSvc *HttpService) batchOpenChannelHandler(c echo.Context) error {
+	ctx := totpContext(c, "")
+
+	var batchOpenChannelRequest api.BatchOpenChannelRequest
+	if err := c.Bind(&batchOpenChannelRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	batchOpenChannelResponse, err := httpSvc.api.BatchOpenChannel(ctx, &batchOpenChannelRequest)
+
+	if err != nil {
+		status := paymentErrorStatus(err)
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: fmt.Sprintf("Failed to batch open channels: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, batchOpenChannelResponse)
+}
+
 func (httpSvc *HttpService) disconnectPeerHandler(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -1026,10 +1328,15 @@ func (httpSvc *HttpService) redeemOnchainFundsHandler(c echo.Context) error {
 		})
 	}
 
-	redeemOnchainFundsResponse, err := httpSvc.api.RedeemOnchainFunds(ctx, redeemOnchainFundsRequest.ToAddress, redeemOnchainFundsRequest.Amount, redeemOnchainFundsRequest.FeeRate, redeemOnchainFundsRequest.SendAll)
+	ctx = totpContext(c, redeemOnchainFundsRequest.TotpCode)
+	redeemOnchainFundsResponse, err := httpSvc.api.RedeemOnchainFunds(ctx, redeemOnchainFundsRequest.ToAddress, redeemOnchainFundsRequest.Amount, redeemOnchainFundsRequest.FeeRate, redeemOnchainFundsRequest.SendAll, redeemOnchainFundsRequest.Outpoints)
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		status := paymentErrorStatus(err)
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
 			Message: fmt.Sprintf("Failed to redeem onchain funds: %s", err.Error()),
 		})
 	}
@@ -1182,11 +1489,18 @@ func (httpSvc *HttpService) transfersHandler(c echo.Context) error {
 		})
 	}
 
-	err := httpSvc.api.Transfer(c.Request().Context(), requestData.FromAppId, requestData.ToAppId, requestData.AmountLoki*1000)
+	fromAppId, err := restrictToTokenApp(c, requestData.FromAppId)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	err = httpSvc.api.Transfer(totpContext(c, ""), fromAppId, requestData.ToAppId, requestData.AmountLoki*1000)
 
 	if err != nil {
 		httpSvc.logger.Error().Err(err).Msg("Failed to transfer funds")
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		return c.JSON(paymentErrorStatus(err), ErrorResponse{
 			Message: fmt.Sprintf("Failed to transfer funds: %v", err),
 		})
 	}
@@ -1391,8 +1705,11 @@ func (httpSvc *HttpService) createBackupHandler(c echo.Context) error {
 	}
 
 	var buffer bytes.Buffer
-	err := httpSvc.api.CreateBackup(backupRequest.UnlockPassword, &buffer)
+	err := httpSvc.api.CreateBackup(backupRequest.UnlockPassword, backupRequest.TotpCode, &buffer)
 	if err != nil {
+		if _, ok := totpErrorStatus(err); ok {
+			return httpSvc.totpErrorResponse(c, err, http.StatusUnauthorized)
+		}
 		return c.String(500, fmt.Sprintf("Failed to create backup: %v", err))
 	}
 
@@ -1505,9 +1822,9 @@ func (httpSvc *HttpService) initiateSwapOutHandler(c echo.Context) error {
 		})
 	}
 
-	swapOutResponse, err := httpSvc.api.InitiateSwapOut(c.Request().Context(), &initiateSwapOutRequest)
+	swapOutResponse, err := httpSvc.api.InitiateSwapOut(totpContext(c, ""), &initiateSwapOutRequest)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		return c.JSON(paymentErrorStatus(err), ErrorResponse{
 			Message: fmt.Sprintf("Failed to initiate swap out: %v", err),
 		})
 	}
@@ -1597,6 +1914,40 @@ func (httpSvc *HttpService) disableAutoSwapOutHandler(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+func (httpSvc *HttpService) enableAutoSwapInHandler(c echo.Context) error {
+	var enableAutoSwapInRequest api.EnableAutoSwapInRequest
+	if err := c.Bind(&enableAutoSwapInRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	err := httpSvc.api.EnableAutoSwapIn(&enableAutoSwapInRequest)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: fmt.Sprintf("Failed to save swap in settings: %v", err),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) disableAutoSwapInHandler(c echo.Context) error {
+	err := httpSvc.api.DisableAutoSwapIn()
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (httpSvc *HttpService) setNodeAliasHandler(c echo.Context) error {
 	var setNodeAliasRequest api.SetNodeAliasRequest
 	if err := c.Bind(&setNodeAliasRequest); err != nil {
@@ -1616,8 +1967,42 @@ func (httpSvc *HttpService) setNodeAliasHandler(c echo.Context) error {
 }
 
 func (httpSvc *HttpService) forwardsHandler(c echo.Context) error {
-	forwards, err := httpSvc.api.GetForwards()
+	forwardsRequest := &api.GetForwardsRequest{
+		Limit:   20,
+		GroupBy: c.QueryParam("groupBy"),
+	}
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		if parsedLimit, err := strconv.ParseUint(limitParam, 10, 64); err == nil {
+			forwardsRequest.Limit = parsedLimit
+		}
+	}
+
+	if offsetParam := c.QueryParam("offset"); offsetParam != "" {
+		if parsedOffset, err := strconv.ParseUint(offsetParam, 10, 64); err == nil {
+			forwardsRequest.Offset = parsedOffset
+		}
+	}
+
+	if fromParam := c.QueryParam("from"); fromParam != "" {
+		if parsedFrom, err := strconv.ParseUint(fromParam, 10, 64); err == nil {
+			forwardsRequest.From = parsedFrom
+		}
+	}
+
+	if untilParam := c.QueryParam("until"); untilParam != "" {
+		if parsedUntil, err := strconv.ParseUint(untilParam, 10, 64); err == nil {
+			forwardsRequest.Until = parsedUntil
+		}
+	}
+
+	forwards, err := httpSvc.api.GetForwards(forwardsRequest)
 	if err != nil {
+		if errors.Is(err, constants.ErrInvalidParams) {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: err.Error(),
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Message: fmt.Sprintf("Failed to get forwards: %s", err.Error()),
 		})
@@ -1723,7 +2108,7 @@ func (httpSvc *HttpService) buyLSPS2LiquidityHandler(c echo.Context) error {
 		})
 	}
 
-	response, err := httpSvc.api.LSPS2Buy(c.Request().Context(), &req)
+	response, err := httpSvc.api.LSPS2Buy(totpContext(c, ""), &req)
 	if err != nil {
 		return handleErrorWithTimeout(c, err)
 	}
@@ -1736,7 +2121,7 @@ func handleErrorWithTimeout(c echo.Context, err error) error {
 	if strings.Contains(strings.ToLower(err.Error()), "time out") || errors.Is(err, context.DeadlineExceeded) {
 		return c.JSON(http.StatusGatewayTimeout, ErrorResponse{Message: "Request timed out, please retry"})
 	}
-	return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	return c.JSON(paymentErrorStatus(err), ErrorResponse{Message: err.Error()})
 }
 
 func (httpSvc *HttpService) lsps0ListProtocolsHandler(c echo.Context) error {
@@ -1772,7 +2157,7 @@ func (httpSvc *HttpService) lsps1CreateOrderHandler(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
 	}
-	resp, err := httpSvc.api.LSPS1CreateOrder(c.Request().Context(), &req)
+	resp, err := httpSvc.api.LSPS1CreateOrder(totpContext(c, ""), &req)
 	if err != nil {
 		return handleErrorWithTimeout(c, err)
 	}
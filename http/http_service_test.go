@@ -376,3 +376,46 @@ func TestCreateApp_ReadonlyPermission(t *testing.T) {
 
 	assert.Equal(t, http.StatusForbidden, rec2.Code)
 }
+
+func TestMetrics_Token(t *testing.T) {
+	e := echo.New()
+	logger.Init(strconv.Itoa(int(4)))
+	mockSvc := mocks.NewMockService(t)
+	gormDb, err := db.NewDB(t)
+	require.NoError(t, err)
+	defer db.CloseDB(gormDb)
+
+	mockEventPublisher := events.NewEventPublisher()
+
+	mockConfig := mocks.NewMockConfig(t)
+	mockConfig.On("GetEnv").Return(&config.AppConfig{MetricsToken: "scrape-secret"})
+
+	mockSvc.On("GetDB").Return(gormDb)
+	mockSvc.On("GetConfig").Return(mockConfig)
+	mockSvc.On("GetKeys").Return(mocks.NewMockKeys(t))
+	mockSvc.On("GetLokiSvc").Return(mocks.NewMockLokiService(t))
+	mockSvc.On("GetAppStoreSvc").Return(&mocks.MockAppStoreService{})
+	mockSvc.On("GetLNClient").Return(nil).Maybe()
+	mockSvc.On("GetRelayStatuses").Return(nil).Maybe()
+
+	httpSvc := NewHttpService(mockSvc, mockEventPublisher)
+	httpSvc.RegisterSharedRoutes(e)
+
+	for token, expectedStatus := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"wrong-secret":  http.StatusUnauthorized,
+		"scrape-secret": http.StatusOK,
+	} {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, expectedStatus, rec.Code, token)
+		if expectedStatus == http.StatusOK {
+			assert.Contains(t, rec.Body.String(), "lokihub_db_rows")
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/lnurl"
+)
+
+// lnurlErrorResponse is the LUD-06 error format. LNURL wallets only look at
+// the body, not the HTTP status code.
+type lnurlErrorResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// lnurlBaseUrl returns the URL LNURL callbacks and metadata point to: BASE_URL
+// if configured, since the Host header is set by the client, or else the URL
+// the request reached the hub on.
+func (httpSvc *HttpService) lnurlBaseUrl(c echo.Context) string {
+	if baseUrl := strings.TrimSuffix(httpSvc.cfg.GetEnv().BaseUrl, "/"); baseUrl != "" {
+		return baseUrl
+	}
+	return fmt.Sprintf("%s://%s", c.Scheme(), c.Request().Host)
+}
+
+func lnurlError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, lnurl.ErrUnknownUsername):
+		status = http.StatusNotFound
+	case errors.Is(err, lnurl.ErrAmountOutOfRange),
+		errors.Is(err, lnurl.ErrCommentTooLong),
+		errors.Is(err, lnurl.ErrInvalidPayerData),
+		errors.Is(err, lnurl.ErrInvalidZapRequest):
+		status = http.StatusBadRequest
+	case errors.Is(err, lnurl.ErrNodeNotRunning):
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, lnurlErrorResponse{
+		Status: "ERROR",
+		Reason: err.Error(),
+	})
+}
+
+// lnurlpHandler serves the first step of LNURL-pay for a lightning address
+// (GET /.well-known/lnurlp/:username). Public — payers are not logged in.
+func (httpSvc *HttpService) lnurlpHandler(c echo.Context) error {
+	// LNURL wallets running in a browser fetch this cross-origin
+	c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+
+	payParams, err := httpSvc.api.GetLNURLPayParams(c.Param("username"), httpSvc.lnurlBaseUrl(c))
+	if err != nil {
+		return lnurlError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, payParams)
+}
+
+// lnurlpCallbackHandler serves the second step of LNURL-pay, returning an
+// invoice credited to the app that owns the username.
+func (httpSvc *HttpService) lnurlpCallbackHandler(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+
+	amount, err := strconv.ParseUint(c.QueryParam("amount"), 10, 64)
+	if err != nil {
+		return lnurlError(c, lnurl.ErrAmountOutOfRange)
+	}
+
+	invoice, err := httpSvc.api.MakeLNURLPayInvoice(c.Request().Context(), c.Param("username"), httpSvc.lnurlBaseUrl(c), &lnurl.InvoiceRequest{
+		AmountMloki: amount,
+		Comment:     c.QueryParam("comment"),
+		PayerData:   c.QueryParam("payerdata"),
+		ZapRequest:  c.QueryParam("nostr"),
+	})
+	if err != nil {
+		return lnurlError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, invoice)
+}
+
+func (httpSvc *HttpService) lightningAddressesListHandler(c echo.Context) error {
+	lightningAddresses, err := httpSvc.api.ListLightningAddresses()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, lightningAddresses)
+}
+
+func (httpSvc *HttpService) lightningAddressesCreateHandler(c echo.Context) error {
+	var createLightningAddressRequest api.CreateLightningAddressRequest
+	if err := c.Bind(&createLightningAddressRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	if _, err := restrictToTokenApp(c, &createLightningAddressRequest.AppId); err != nil {
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	lightningAddress, err := httpSvc.api.CreateLightningAddress(&createLightningAddressRequest)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, lnurl.ErrInvalidUsername) || errors.Is(err, lnurl.ErrUsernameTaken) || errors.Is(err, lnurl.ErrAppHasAddress) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, lightningAddress)
+}
+
+func (httpSvc *HttpService) lightningAddressesDeleteHandler(c echo.Context) error {
+	dbApp, err := httpSvc.getAppByIDParam(c, "appId")
+	if err != nil {
+		return err
+	}
+
+	if err := httpSvc.api.DeleteLightningAddress(dbApp.ID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, lnurl.ErrAppHasNoAddress) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
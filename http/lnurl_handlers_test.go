@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/config"
+	lokidb "github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/lnurl"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/tests/db"
+	"github.com/flokiorg/lokihub/tests/mocks"
+)
+
+func newLNURLTestServer(t *testing.T, baseUrl string) (*echo.Echo, *mocks.MockKeys, func()) {
+	e := echo.New()
+	logger.Init(strconv.Itoa(int(4)))
+	mockSvc := mocks.NewMockService(t)
+	gormDb, err := db.NewDB(t)
+	require.NoError(t, err)
+
+	mockConfig := mocks.NewMockConfig(t)
+	mockConfig.On("GetEnv").Return(&config.AppConfig{BaseUrl: baseUrl})
+	mockKeys := mocks.NewMockKeys(t)
+
+	mockSvc.On("GetDB").Return(gormDb)
+	mockSvc.On("GetConfig").Return(mockConfig)
+	mockSvc.On("GetKeys").Return(mockKeys)
+	mockSvc.On("GetLokiSvc").Return(mocks.NewMockLokiService(t))
+	mockSvc.On("GetAppStoreSvc").Return(&mocks.MockAppStoreService{})
+	mockSvc.On("GetLNURLService").Return(lnurl.NewLNURLService(gormDb, mockKeys, nil, func() lnclient.LNClient { return nil })).Maybe()
+
+	app := lokidb.App{Name: "test"}
+	require.NoError(t, gormDb.Create(&app).Error)
+	require.NoError(t, gormDb.Create(&lokidb.LightningAddress{AppId: app.ID, Username: "alice"}).Error)
+
+	httpSvc := NewHttpService(mockSvc, events.NewEventPublisher())
+	httpSvc.RegisterSharedRoutes(e)
+
+	return e, mockKeys, func() { db.CloseDB(gormDb) }
+}
+
+func TestLNURLPay_UnknownUsername(t *testing.T) {
+	e, _, cleanup := newLNURLTestServer(t, "")
+	defer cleanup()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/.well-known/lnurlp/bob", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var response lnurlErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "ERROR", response.Status)
+	assert.Equal(t, lnurl.ErrUnknownUsername.Error(), response.Reason)
+}
+
+func TestLNURLPay_PayParams(t *testing.T) {
+	e, mockKeys, cleanup := newLNURLTestServer(t, "")
+	defer cleanup()
+	mockKeys.On("GetNostrPublicKey").Return("")
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/.well-known/lnurlp/alice", nil)
+	req.Host = "hub.example.com"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	var payParams lnurl.PayParams
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &payParams))
+	assert.Equal(t, "payRequest", payParams.Tag)
+	assert.Equal(t, "http://hub.example.com/lnurlp/alice/callback", payParams.Callback)
+	assert.False(t, payParams.AllowsNostr)
+}
+
+func TestLNURLPay_PayParams_BaseUrl(t *testing.T) {
+	e, mockKeys, cleanup := newLNURLTestServer(t, "https://hub.example.com/")
+	defer cleanup()
+	mockKeys.On("GetNostrPublicKey").Return("")
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/.well-known/lnurlp/alice", nil)
+	req.Host = "attacker.example.com"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var payParams lnurl.PayParams
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &payParams))
+	assert.Equal(t, "https://hub.example.com/lnurlp/alice/callback", payParams.Callback)
+	assert.Contains(t, payParams.Metadata, "alice@hub.example.com")
+	assert.NotContains(t, payParams.Metadata, "attacker.example.com")
+}
+
+func TestLNURLPay_CallbackInvalidAmount(t *testing.T) {
+	e, _, cleanup := newLNURLTestServer(t, "")
+	defer cleanup()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/lnurlp/alice/callback?amount=abc", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var response lnurlErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "ERROR", response.Status)
+}
@@ -27,7 +27,7 @@ func NewLokiHttpService(svc service.Service, lokiSvc loki.LokiService, appConfig
 	}
 }
 
-func (lokiHttpSvc *LokiHttpService) RegisterSharedRoutes(readOnlyApiGroup *echo.Group, fullAccessApiGroup *echo.Group, e *echo.Echo) {
+func (lokiHttpSvc *LokiHttpService) RegisterSharedRoutes(readOnlyApiGroup *echo.Group, adminApiGroup *echo.Group, e *echo.Echo) {
 	e.GET("/api/loki/info", lokiHttpSvc.lokiInfoHandler)
 	e.GET("/api/loki/rates", lokiHttpSvc.lokiFlokicoinRateHandler)
 	e.GET("/api/currencies", lokiHttpSvc.lokiCurrenciesHandler)
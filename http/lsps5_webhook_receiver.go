@@ -358,63 +358,9 @@ type LSPS5WebhookEvent struct {
 
 // lsps5EventsSSEHandler provides Server-Sent Events for LSPS5 notifications
 func (httpSvc *HttpService) lsps5EventsSSEHandler(c echo.Context) error {
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-
-	// Create a channel for this client
-	eventChan := make(chan *events.Event, 16)
-
-	// Subscribe to LSPS5 events
-	subscriber := &lsps5EventSubscriber{
-		handler: func(event *events.Event) {
-			if strings.HasPrefix(event.Event, "lsps5.") || strings.HasPrefix(event.Event, "lsps1.") {
-				select {
-				case eventChan <- event:
-				default:
-					// Channel full, skip event
-				}
-			}
-		},
-	}
-	httpSvc.eventPublisher.RegisterSubscriber(subscriber)
-	defer httpSvc.eventPublisher.RemoveSubscriber(subscriber)
-
-	// Send keepalive and events
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-c.Request().Context().Done():
-			return nil
-		case <-httpSvc.shutdownCh:
-			return nil
-		case <-ticker.C:
-			// Send keepalive comment
-			if _, err := c.Response().Write([]byte(": keepalive\n\n")); err != nil {
-				return nil
-			}
-			c.Response().Flush()
-		case event := <-eventChan:
-			data, err := json.Marshal(event)
-			if err != nil {
-				continue
-			}
-			if _, err := fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event.Event, string(data)); err != nil {
-				return nil
-			}
-			c.Response().Flush()
-		}
-	}
-}
-
-type lsps5EventSubscriber struct {
-	handler func(event *events.Event)
-}
-
-func (s *lsps5EventSubscriber) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
-	s.handler(event)
+	return httpSvc.streamEvents(c, func(event *events.Event) bool {
+		return strings.HasPrefix(event.Event, "lsps5.") || strings.HasPrefix(event.Event, "lsps1.")
+	})
 }
 
 // verifyLSPS5Signature verifies an LSPS5 webhook signature
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lsps/manager"
 	"github.com/labstack/echo/v4"
 )
 
@@ -52,3 +55,76 @@ func (httpSvc *HttpService) deleteLSPHandler(c echo.Context) error {
 	}
 	return c.NoContent(http.StatusOK)
 }
+
+func (httpSvc *HttpService) lspServiceConfigHandler(c echo.Context) error {
+	serviceConfig, err := httpSvc.api.GetLSPServiceConfig()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, serviceConfig)
+}
+
+func (httpSvc *HttpService) updateLSPServiceConfigHandler(c echo.Context) error {
+	var serviceConfig manager.LSPServiceConfig
+	if err := c.Bind(&serviceConfig); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	if err := httpSvc.api.UpdateLSPServiceConfig(&serviceConfig); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) lspServiceRefundsHandler(c echo.Context) error {
+	orders, err := httpSvc.api.ListLSPServiceRefunds()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, orders)
+}
+
+func (httpSvc *HttpService) lspServiceOrderRefundedHandler(c echo.Context) error {
+	order, err := httpSvc.api.MarkLSPServiceOrderRefunded(c.Param("orderId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, order)
+}
+
+func (httpSvc *HttpService) getAutoLiquidityConfigHandler(c echo.Context) error {
+	autoLiquidityConfig, err := httpSvc.api.GetAutoLiquidityConfig()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, autoLiquidityConfig)
+}
+
+func (httpSvc *HttpService) enableAutoLiquidityHandler(c echo.Context) error {
+	var enableAutoLiquidityRequest api.EnableAutoLiquidityRequest
+	if err := c.Bind(&enableAutoLiquidityRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	if err := httpSvc.api.EnableAutoLiquidity(&enableAutoLiquidityRequest); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) disableAutoLiquidityHandler(c echo.Context) error {
+	if err := httpSvc.api.DisableAutoLiquidity(); err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
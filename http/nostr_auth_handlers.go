@@ -0,0 +1,147 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/nostrauth"
+)
+
+// nostrAuthUrl returns the absolute URL a NIP-98 event must be signed for.
+// Behind a reverse proxy the hub cannot see the URL the client used, so
+// BASE_URL takes precedence when set.
+func (httpSvc *HttpService) nostrAuthUrl(c echo.Context) string {
+	baseUrl := strings.TrimSuffix(httpSvc.cfg.GetEnv().BaseUrl, "/")
+	if baseUrl == "" {
+		baseUrl = fmt.Sprintf("%s://%s", c.Scheme(), c.Request().Host)
+	}
+	return baseUrl + c.Request().URL.RequestURI()
+}
+
+// nostrUnlockHandler logs an admin pubkey in with a NIP-98 signed event and
+// issues the same session token as unlocking with the password
+func (httpSvc *HttpService) nostrUnlockHandler(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	adminPubkey, err := httpSvc.nostrAuthSvc.Verify(
+		c.Request().Header.Get("Authorization"),
+		httpSvc.nostrAuthUrl(c),
+		c.Request().Method,
+		body,
+	)
+	if err != nil {
+		if errors.Is(err, nostrauth.ErrInvalidAuthEvent) ||
+			errors.Is(err, nostrauth.ErrUnauthorizedPubkey) ||
+			errors.Is(err, nostrauth.ErrAuthEventReplayed) {
+			return c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Message: err.Error(),
+			})
+		}
+		httpSvc.logger.Error().Err(err).Msg("Failed to verify NIP-98 auth event")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "Failed to verify auth event",
+		})
+	}
+
+	var unlockRequest api.NostrUnlockRequest
+	if len(bytes.TrimSpace(body)) > 0 {
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		if err := c.Bind(&unlockRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: fmt.Sprintf("Bad request: %s", err.Error()),
+			})
+		}
+	}
+
+	// read-only sessions cannot reach anything the second factor protects
+	if adminPubkey.Permission == "full" {
+		if err := httpSvc.totpSvc.Verify(unlockRequest.TotpCode, ""); err != nil {
+			return httpSvc.totpErrorResponse(c, err, http.StatusUnauthorized)
+		}
+	}
+
+	token, err := httpSvc.createJWT(unlockRequest.TokenExpiryDays, adminPubkey.Permission)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to save session: %s", err.Error()),
+		})
+	}
+
+	httpSvc.logger.Info().Str("pubkey", adminPubkey.Pubkey).Str("permission", adminPubkey.Permission).Msg("Admin logged in with Nostr")
+	httpSvc.eventPublisher.Publish(&events.Event{
+		Event: "nwc_unlocked",
+	})
+
+	return c.JSON(http.StatusOK, &authTokenResponse{
+		Token: token,
+	})
+}
+
+func (httpSvc *HttpService) adminPubkeysListHandler(c echo.Context) error {
+	adminPubkeys, err := httpSvc.api.ListAdminPubkeys()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to list admin pubkeys: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, adminPubkeys)
+}
+
+func (httpSvc *HttpService) adminPubkeyAddHandler(c echo.Context) error {
+	var addRequest api.AddAdminPubkeyRequest
+	if err := c.Bind(&addRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	adminPubkey, err := httpSvc.api.AddAdminPubkey(&addRequest)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, adminPubkey)
+}
+
+func (httpSvc *HttpService) adminPubkeyRemoveHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "invalid admin pubkey ID",
+		})
+	}
+
+	if err := httpSvc.api.RemoveAdminPubkey(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, nostrauth.ErrAdminPubkeyNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/config"
+	lokidb "github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/nostrauth"
+	"github.com/flokiorg/lokihub/tests/db"
+	"github.com/flokiorg/lokihub/tests/mocks"
+	"github.com/flokiorg/lokihub/totp"
+)
+
+func TestNostrUnlock(t *testing.T) {
+	e := echo.New()
+	logger.Init(strconv.Itoa(int(4)))
+	mockSvc := mocks.NewMockService(t)
+	gormDb, err := db.NewDB(t)
+	require.NoError(t, err)
+	defer db.CloseDB(gormDb)
+
+	mockConfig := mocks.NewMockConfig(t)
+	mockConfig.On("GetEnv").Return(&config.AppConfig{BaseUrl: "https://hub.example.com/"})
+	mockConfig.On("GetJWTSecret").Return("dummy secret", nil)
+
+	mockSvc.On("GetDB").Return(gormDb)
+	mockSvc.On("GetConfig").Return(mockConfig)
+	mockSvc.On("GetKeys").Return(mocks.NewMockKeys(t))
+	mockSvc.On("GetLokiSvc").Return(mocks.NewMockLokiService(t))
+	mockSvc.On("GetAppStoreSvc").Return(&mocks.MockAppStoreService{})
+
+	httpSvc := NewHttpService(mockSvc, events.NewEventPublisher())
+	httpSvc.RegisterSharedRoutes(e)
+
+	secretKey := nostr.GeneratePrivateKey()
+	pubkey, err := nostr.GetPublicKey(secretKey)
+	require.NoError(t, err)
+	_, err = nostrauth.NewNostrAuthService(gormDb).AddAdminPubkey(pubkey, "extension", "readonly")
+	require.NoError(t, err)
+
+	body := []byte(`{"tokenExpiryDays":1}`)
+	bodyHash := sha256.Sum256(body)
+	event := nostr.Event{
+		Kind:      nostr.KindHTTPAuth,
+		CreatedAt: nostr.Timestamp(time.Now().Unix()),
+		Tags: nostr.Tags{
+			{"u", "https://hub.example.com/api/unlock/nostr"},
+			{"method", "POST"},
+			{"payload", hex.EncodeToString(bodyHash[:])},
+		},
+	}
+	require.NoError(t, event.Sign(secretKey))
+	encodedEvent, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	unlock := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/unlock/nostr", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Nostr "+base64.StdEncoding.EncodeToString(encodedEvent))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := unlock()
+	require.Equal(t, http.StatusOK, rec.Code)
+	var unlockResponse authTokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &unlockResponse))
+	assert.NotEmpty(t, unlockResponse.Token)
+
+	// the session has the pubkey's permission
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/api/apps", nil)
+	req.Header.Set("Authorization", "Bearer "+unlockResponse.Token)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/apps", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+unlockResponse.Token)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// wait out the rate limiter, then replay the event
+	time.Sleep(time.Second)
+	assert.Equal(t, http.StatusUnauthorized, unlock().Code)
+}
+
+func TestNostrUnlock_Totp(t *testing.T) {
+	e := echo.New()
+	logger.Init(strconv.Itoa(int(4)))
+	mockSvc := mocks.NewMockService(t)
+	gormDb, err := db.NewDB(t)
+	require.NoError(t, err)
+	defer db.CloseDB(gormDb)
+
+	// RFC 6238 test secret
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	mockConfig := mocks.NewMockConfig(t)
+	mockConfig.On("GetEnv").Return(&config.AppConfig{BaseUrl: "https://hub.example.com/"})
+	mockConfig.On("GetJWTSecret").Return("dummy secret", nil)
+	mockConfig.On("GetTOTPSecret").Return(secret)
+
+	mockSvc.On("GetDB").Return(gormDb)
+	mockSvc.On("GetConfig").Return(mockConfig)
+	mockSvc.On("GetKeys").Return(mocks.NewMockKeys(t))
+	mockSvc.On("GetLokiSvc").Return(mocks.NewMockLokiService(t))
+	mockSvc.On("GetAppStoreSvc").Return(&mocks.MockAppStoreService{})
+
+	httpSvc := NewHttpService(mockSvc, events.NewEventPublisher())
+	httpSvc.RegisterSharedRoutes(e)
+
+	confirmedAt := time.Now()
+	require.NoError(t, gormDb.Create(&lokidb.TotpFactor{ConfirmedAt: &confirmedAt}).Error)
+
+	secretKey := nostr.GeneratePrivateKey()
+	pubkey, err := nostr.GetPublicKey(secretKey)
+	require.NoError(t, err)
+	_, err = nostrauth.NewNostrAuthService(gormDb).AddAdminPubkey(pubkey, "extension", "full")
+	require.NoError(t, err)
+
+	remoteIp := 0
+	unlock := func(unlockRequest api.NostrUnlockRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(unlockRequest)
+		require.NoError(t, err)
+		bodyHash := sha256.Sum256(body)
+		event := nostr.Event{
+			Kind:      nostr.KindHTTPAuth,
+			CreatedAt: nostr.Timestamp(time.Now().Unix()),
+			Tags: nostr.Tags{
+				{"u", "https://hub.example.com/api/unlock/nostr"},
+				{"method", "POST"},
+				{"payload", hex.EncodeToString(bodyHash[:])},
+			},
+		}
+		require.NoError(t, event.Sign(secretKey))
+		encodedEvent, err := json.Marshal(event)
+		require.NoError(t, err)
+
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/unlock/nostr", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Nostr "+base64.StdEncoding.EncodeToString(encodedEvent))
+		// every request comes from another address to stay clear of the
+		// unlock rate limiter
+		remoteIp++
+		req.RemoteAddr = "192.0.2." + strconv.Itoa(remoteIp) + ":1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// a full access pubkey needs a code once TOTP is enabled
+	assert.Equal(t, http.StatusUnauthorized, unlock(api.NostrUnlockRequest{}).Code)
+	assert.Equal(t, http.StatusUnauthorized, unlock(api.NostrUnlockRequest{TotpCode: "000000"}).Code)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, unlock(api.NostrUnlockRequest{TotpCode: code}).Code)
+}
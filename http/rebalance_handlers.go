@@ -0,0 +1,92 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+)
+
+func (httpSvc *HttpService) rebalanceHandler(c echo.Context) error {
+	var rebalanceRequest api.RebalanceRequest
+	if err := c.Bind(&rebalanceRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	rebalance, err := httpSvc.api.Rebalance(totpContext(c, ""), &rebalanceRequest)
+	if err != nil {
+		status := paymentErrorStatus(err)
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{
+			Message: fmt.Sprintf("Failed to rebalance channels: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, rebalance)
+}
+
+func (httpSvc *HttpService) rebalancesListHandler(c echo.Context) error {
+	listRequest := &api.ListRebalancesRequest{
+		Limit: 20,
+	}
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		if parsedLimit, err := strconv.ParseUint(limitParam, 10, 64); err == nil {
+			listRequest.Limit = parsedLimit
+		}
+	}
+
+	if offsetParam := c.QueryParam("offset"); offsetParam != "" {
+		if parsedOffset, err := strconv.ParseUint(offsetParam, 10, 64); err == nil {
+			listRequest.Offset = parsedOffset
+		}
+	}
+
+	rebalances, err := httpSvc.api.ListRebalances(listRequest)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to list rebalances: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, rebalances)
+}
+
+func (httpSvc *HttpService) getAutoRebalanceConfigHandler(c echo.Context) error {
+	autoRebalanceConfig, err := httpSvc.api.GetAutoRebalanceConfig()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, autoRebalanceConfig)
+}
+
+func (httpSvc *HttpService) enableAutoRebalanceHandler(c echo.Context) error {
+	var enableAutoRebalanceRequest api.EnableAutoRebalanceRequest
+	if err := c.Bind(&enableAutoRebalanceRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	if err := httpSvc.api.EnableAutoRebalance(&enableAutoRebalanceRequest); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, constants.ErrInvalidParams) {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) disableAutoRebalanceHandler(c echo.Context) error {
+	if err := httpSvc.api.DisableAutoRebalance(); err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/totp"
+)
+
+// totpCodeHeader carries the TOTP code for routes whose request body has no
+// totpCode field
+const totpCodeHeader = "X-Totp-Code"
+
+// totpContext returns the request context with the TOTP code to check for
+// actions that move funds: the one sent in the body, or else the header.
+func totpContext(c echo.Context, bodyCode string) context.Context {
+	code := bodyCode
+	if code == "" {
+		code = c.Request().Header.Get(totpCodeHeader)
+	}
+	return api.WithTotpCode(c.Request().Context(), code)
+}
+
+// totpErrorStatus returns the status for an action refused by a TOTP check.
+// ok is false for any other error.
+func totpErrorStatus(err error) (status int, ok bool) {
+	switch {
+	case errors.Is(err, totp.ErrTotpLocked):
+		return http.StatusTooManyRequests, true
+	case errors.Is(err, totp.ErrTotpRequired), errors.Is(err, totp.ErrInvalidTotpCode):
+		return http.StatusForbidden, true
+	}
+	return 0, false
+}
+
+// totpErrorResponse responds to a failed TOTP check. Missing and invalid codes
+// are refused with status; routes behind a session use 403 so the frontend
+// asks for a code instead of logging the user out.
+func (httpSvc *HttpService) totpErrorResponse(c echo.Context, err error, status int) error {
+	switch {
+	case errors.Is(err, totp.ErrTotpLocked):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, totp.ErrTotpRequired),
+		errors.Is(err, totp.ErrInvalidTotpCode),
+		errors.Is(err, totp.ErrInvalidPassword):
+	case errors.Is(err, totp.ErrTotpNotEnabled),
+		errors.Is(err, totp.ErrTotpAlreadyEnabled),
+		errors.Is(err, constants.ErrInvalidParams):
+		status = http.StatusBadRequest
+	default:
+		httpSvc.logger.Error().Err(err).Msg("Failed to check TOTP code")
+		status = http.StatusInternalServerError
+	}
+	return c.JSON(status, ErrorResponse{
+		Message: err.Error(),
+	})
+}
+
+func (httpSvc *HttpService) totpStatusHandler(c echo.Context) error {
+	status, err := httpSvc.api.GetTotpStatus()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to get TOTP status: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+func (httpSvc *HttpService) totpEnrollHandler(c echo.Context) error {
+	var enrollRequest api.BeginTotpEnrollmentRequest
+	if err := c.Bind(&enrollRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	enrollResponse, err := httpSvc.api.BeginTotpEnrollment(&enrollRequest)
+	if err != nil {
+		return httpSvc.totpErrorResponse(c, err, http.StatusForbidden)
+	}
+
+	return c.JSON(http.StatusOK, enrollResponse)
+}
+
+func (httpSvc *HttpService) totpConfirmHandler(c echo.Context) error {
+	var confirmRequest api.TotpCodeRequest
+	if err := c.Bind(&confirmRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	recoveryCodes, err := httpSvc.api.ConfirmTotpEnrollment(&confirmRequest)
+	if err != nil {
+		return httpSvc.totpErrorResponse(c, err, http.StatusForbidden)
+	}
+
+	return c.JSON(http.StatusOK, recoveryCodes)
+}
+
+func (httpSvc *HttpService) totpDisableHandler(c echo.Context) error {
+	var disableRequest api.DisableTotpRequest
+	if err := c.Bind(&disableRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	if err := httpSvc.api.DisableTotp(&disableRequest); err != nil {
+		return httpSvc.totpErrorResponse(c, err, http.StatusForbidden)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) totpRecoveryCodesHandler(c echo.Context) error {
+	var regenerateRequest api.TotpCodeRequest
+	if err := c.Bind(&regenerateRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	recoveryCodes, err := httpSvc.api.RegenerateTotpRecoveryCodes(&regenerateRequest)
+	if err != nil {
+		return httpSvc.totpErrorResponse(c, err, http.StatusForbidden)
+	}
+
+	return c.JSON(http.StatusOK, recoveryCodes)
+}
+
+func (httpSvc *HttpService) totpUpdateHandler(c echo.Context) error {
+	var updateRequest api.UpdateTotpRequest
+	if err := c.Bind(&updateRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	if err := httpSvc.api.UpdateTotp(&updateRequest); err != nil {
+		return httpSvc.totpErrorResponse(c, err, http.StatusForbidden)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/config"
+	lokidb "github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/tests/db"
+	"github.com/flokiorg/lokihub/tests/mocks"
+	"github.com/flokiorg/lokihub/totp"
+)
+
+func TestTotp_Required(t *testing.T) {
+	e := echo.New()
+	logger.Init(strconv.Itoa(int(4)))
+	mockSvc := mocks.NewMockService(t)
+	gormDb, err := db.NewDB(t)
+	require.NoError(t, err)
+	defer db.CloseDB(gormDb)
+
+	// RFC 6238 test secret
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	mockConfig := mocks.NewMockConfig(t)
+	mockConfig.On("GetEnv").Return(&config.AppConfig{})
+	mockConfig.On("CheckUnlockPassword", "123").Return(true)
+	mockConfig.On("GetJWTSecret").Return("dummy secret", nil)
+	mockConfig.On("GetTOTPSecret").Return(secret)
+
+	mockSvc.On("GetDB").Return(gormDb)
+	mockSvc.On("GetConfig").Return(mockConfig)
+	mockSvc.On("GetKeys").Return(mocks.NewMockKeys(t))
+	mockSvc.On("GetLokiSvc").Return(mocks.NewMockLokiService(t))
+	mockSvc.On("GetAppStoreSvc").Return(&mocks.MockAppStoreService{})
+	mockSvc.On("GetLNClient").Return(mocks.NewMockLNClient(t))
+
+	httpSvc := NewHttpService(mockSvc, events.NewEventPublisher())
+	httpSvc.RegisterSharedRoutes(e)
+
+	confirmedAt := time.Now()
+	require.NoError(t, gormDb.Create(&lokidb.TotpFactor{ConfirmedAt: &confirmedAt}).Error)
+
+	remoteIp := 0
+	serve := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		encoded, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequestWithContext(t.Context(), method, path, bytes.NewBuffer(encoded))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		// every request comes from another address to stay clear of the
+		// unlock rate limiter
+		remoteIp++
+		req.RemoteAddr = "192.0.2." + strconv.Itoa(remoteIp) + ":1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// read-only sessions do not need a code
+	rec := serve(http.MethodPost, "/api/unlock", "", api.UnlockRequest{UnlockPassword: "123", Permission: "readonly"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(http.MethodPost, "/api/unlock", "", api.UnlockRequest{UnlockPassword: "123", Permission: "full"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = serve(http.MethodPost, "/api/unlock", "", api.UnlockRequest{UnlockPassword: "123", Permission: "full", TotpCode: "000000"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	rec = serve(http.MethodPost, "/api/unlock", "", api.UnlockRequest{UnlockPassword: "123", Permission: "full", TotpCode: code})
+	require.Equal(t, http.StatusOK, rec.Code)
+	var unlockResponse authTokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &unlockResponse))
+
+	// a code cannot be used twice
+	rec = serve(http.MethodPost, "/api/unlock", "", api.UnlockRequest{UnlockPassword: "123", Permission: "full", TotpCode: code})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// sensitive actions of a full access session still need a code, refused
+	// with 403 so the session is kept
+	rec = serve(http.MethodPost, "/api/mnemonic", unlockResponse.Token, api.MnemonicRequest{UnlockPassword: "123"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(http.MethodPatch, "/api/unlock-password", unlockResponse.Token, api.ChangeUnlockPasswordRequest{CurrentUnlockPassword: "123", NewUnlockPassword: "456"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(http.MethodPost, "/api/backup", "", api.BasicBackupRequest{UnlockPassword: "123"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// without a threshold every payment needs a code
+	amount := uint64(1000)
+	rec = serve(http.MethodPost, "/api/payments/lnfc1invoice", unlockResponse.Token, api.PayInvoiceRequest{Amount: &amount})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(http.MethodPost, "/api/wallet/redeem-onchain-funds", unlockResponse.Token, api.RedeemOnchainFundsRequest{ToAddress: "address", SendAll: true})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(http.MethodPost, "/api/transfers", unlockResponse.Token, api.TransferRequest{AmountLoki: 1})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(http.MethodGet, "/api/totp", unlockResponse.Token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status api.TotpStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.True(t, status.Enabled)
+	assert.Nil(t, status.PaymentThresholdMloki)
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/flokiorg/lokihub/api"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/webhooks"
+)
+
+func (httpSvc *HttpService) webhookEndpointsListHandler(c echo.Context) error {
+	endpoints, err := httpSvc.api.ListWebhookEndpoints()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: fmt.Sprintf("Failed to list webhook endpoints: %s", err.Error()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, endpoints)
+}
+
+func (httpSvc *HttpService) webhookEndpointCreateHandler(c echo.Context) error {
+	var createRequest api.CreateWebhookEndpointRequest
+	if err := c.Bind(&createRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	endpoint, err := httpSvc.api.CreateWebhookEndpoint(&createRequest)
+	if err != nil {
+		return c.JSON(webhookErrorStatus(err), ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, endpoint)
+}
+
+func (httpSvc *HttpService) webhookEndpointUpdateHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "invalid webhook endpoint ID",
+		})
+	}
+
+	var updateRequest api.UpdateWebhookEndpointRequest
+	if err := c.Bind(&updateRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: fmt.Sprintf("Bad request: %s", err.Error()),
+		})
+	}
+
+	endpoint, err := httpSvc.api.UpdateWebhookEndpoint(uint(id), &updateRequest)
+	if err != nil {
+		return c.JSON(webhookErrorStatus(err), ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, endpoint)
+}
+
+func (httpSvc *HttpService) webhookEndpointDeleteHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "invalid webhook endpoint ID",
+		})
+	}
+
+	if err := httpSvc.api.DeleteWebhookEndpoint(uint(id)); err != nil {
+		return c.JSON(webhookErrorStatus(err), ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (httpSvc *HttpService) webhookDeliveriesListHandler(c echo.Context) error {
+	listRequest := &api.ListWebhookDeliveriesRequest{
+		Limit: 20,
+		State: c.QueryParam("state"),
+	}
+
+	if endpointParam := c.QueryParam("endpointId"); endpointParam != "" {
+		if parsedEndpointId, err := strconv.ParseUint(endpointParam, 10, 64); err == nil {
+			listRequest.EndpointId = uint(parsedEndpointId)
+		}
+	}
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		if parsedLimit, err := strconv.ParseUint(limitParam, 10, 64); err == nil {
+			listRequest.Limit = parsedLimit
+		}
+	}
+
+	if offsetParam := c.QueryParam("offset"); offsetParam != "" {
+		if parsedOffset, err := strconv.ParseUint(offsetParam, 10, 64); err == nil {
+			listRequest.Offset = parsedOffset
+		}
+	}
+
+	deliveries, err := httpSvc.api.ListWebhookDeliveries(listRequest)
+	if err != nil {
+		return c.JSON(webhookErrorStatus(err), ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+func (httpSvc *HttpService) webhookDeliveryRedeliverHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "invalid webhook delivery ID",
+		})
+	}
+
+	delivery, err := httpSvc.api.RedeliverWebhookDelivery(uint(id))
+	if err != nil {
+		return c.JSON(webhookErrorStatus(err), ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, constants.ErrInvalidParams):
+		return http.StatusBadRequest
+	case errors.Is(err, webhooks.ErrEndpointNotFound), errors.Is(err, webhooks.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
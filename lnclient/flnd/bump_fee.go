@@ -0,0 +1,168 @@
+package flnd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+const (
+	// the sweeper broadcasts asynchronously, so the new transaction is
+	// awaited for a short while
+	bumpFeePollAttempts = 10
+	bumpFeePollInterval = time.Second
+)
+
+// BumpFee hands an input to the flnd sweeper at the new fee rate. Inputs the
+// sweeper is already spending get their sweep replaced (RBF); any other
+// unconfirmed output of ours is spent by a child transaction (CPFP). flnd
+// cannot replace regular wallet sends, so a send without an output of ours
+// (e.g. a send-all) is rejected with lnclient.ErrTransactionNotBumpable.
+func (svc *FLNDService) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	pendingSweepsResp, err := svc.client.PendingSweeps(ctx, &walletrpc.PendingSweepsRequest{})
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list pending sweeps")
+		return nil, err
+	}
+	pendingSweeps := make(map[string]bool, len(pendingSweepsResp.PendingSweeps))
+	for _, pendingSweep := range pendingSweepsResp.PendingSweeps {
+		pendingSweeps[formatOutpoint(pendingSweep.Outpoint)] = true
+	}
+
+	var outpoint, method string
+	// the fee already paid by the parent of a CPFP child
+	var parentFeeLoki int64
+	switch {
+	case bumpFeeRequest.Outpoint != "" && pendingSweeps[bumpFeeRequest.Outpoint]:
+		outpoint, method = bumpFeeRequest.Outpoint, lnclient.BUMP_FEE_METHOD_RBF
+	case bumpFeeRequest.Outpoint != "":
+		txId, _, _ := strings.Cut(bumpFeeRequest.Outpoint, ":")
+		tx, err := svc.client.GetTransaction(ctx, &walletrpc.GetTransactionRequest{Txid: txId})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up transaction %s: %w", txId, err)
+		}
+		if tx.NumConfirmations > 0 {
+			return nil, fmt.Errorf("%w: it is already confirmed", lnclient.ErrTransactionNotBumpable)
+		}
+		outpoint, method = bumpFeeRequest.Outpoint, lnclient.BUMP_FEE_METHOD_CPFP
+		parentFeeLoki = tx.TotalFees
+	default:
+		tx, err := svc.client.GetTransaction(ctx, &walletrpc.GetTransactionRequest{Txid: bumpFeeRequest.TxId})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up transaction %s: %w", bumpFeeRequest.TxId, err)
+		}
+		outpoint, method, err = selectBumpOutpoint(tx, pendingSweeps)
+		if err != nil {
+			return nil, err
+		}
+		if method == lnclient.BUMP_FEE_METHOD_CPFP {
+			parentFeeLoki = tx.TotalFees
+		}
+	}
+
+	parsedOutpoint, err := parseOutpoint(outpoint)
+	if err != nil {
+		return nil, err
+	}
+	previousSweeps, err := svc.unconfirmedSweeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.client.BumpFee(ctx, &walletrpc.BumpFeeRequest{
+		Outpoint:    parsedOutpoint,
+		SatPerVbyte: bumpFeeRequest.FeeRate,
+		Immediate:   true,
+	})
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("outpoint", outpoint).Msg("Failed to bump fee")
+		return nil, err
+	}
+	logger.Logger.Info().
+		Str("outpoint", outpoint).
+		Str("method", method).
+		Uint64("fee_rate", bumpFeeRequest.FeeRate).
+		Msg("Bumped transaction fee")
+
+	bumpFeeResponse := &lnclient.BumpFeeResponse{
+		Method:   method,
+		Outpoint: outpoint,
+	}
+	for range bumpFeePollAttempts {
+		select {
+		case <-ctx.Done():
+			return bumpFeeResponse, nil
+		case <-time.After(bumpFeePollInterval):
+		}
+		sweeps, err := svc.unconfirmedSweeps(ctx)
+		if err != nil {
+			return bumpFeeResponse, nil
+		}
+		if sweep := findNewSweep(sweeps, previousSweeps, outpoint); sweep != nil {
+			bumpFeeResponse.TxId = sweep.TxHash
+			bumpFeeResponse.FeeLoki = parentFeeLoki + sweep.TotalFees
+			break
+		}
+	}
+	return bumpFeeResponse, nil
+}
+
+func (svc *FLNDService) unconfirmedSweeps(ctx context.Context) ([]*lnrpc.Transaction, error) {
+	// a start height of -1 only returns sweeps that are not confirmed yet
+	resp, err := svc.client.ListSweeps(ctx, &walletrpc.ListSweepsRequest{Verbose: true, StartHeight: -1})
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list sweeps")
+		return nil, err
+	}
+	return resp.GetTransactionDetails().GetTransactions(), nil
+}
+
+// selectBumpOutpoint picks the input to hand to the sweeper for an
+// unconfirmed transaction: one of its inputs if the transaction is a sweep,
+// otherwise its largest output of ours. Transactions with neither cannot be
+// bumped.
+func selectBumpOutpoint(tx *lnrpc.Transaction, pendingSweeps map[string]bool) (outpoint string, method string, err error) {
+	if tx.NumConfirmations > 0 {
+		return "", "", fmt.Errorf("%w: it is already confirmed", lnclient.ErrTransactionNotBumpable)
+	}
+	for _, input := range tx.PreviousOutpoints {
+		if pendingSweeps[input.Outpoint] {
+			return input.Outpoint, lnclient.BUMP_FEE_METHOD_RBF, nil
+		}
+	}
+
+	var largest *lnrpc.OutputDetail
+	for _, output := range tx.OutputDetails {
+		if output.IsOurAddress && (largest == nil || output.Amount > largest.Amount) {
+			largest = output
+		}
+	}
+	if largest == nil {
+		return "", "", fmt.Errorf("%w: it has no output of ours to spend with a child transaction and only sweeps can be replaced", lnclient.ErrTransactionNotBumpable)
+	}
+	return fmt.Sprintf("%s:%d", tx.TxHash, largest.OutputIndex), lnclient.BUMP_FEE_METHOD_CPFP, nil
+}
+
+// findNewSweep returns the sweep spending the outpoint that was not in the
+// previous list of sweeps
+func findNewSweep(sweeps []*lnrpc.Transaction, previousSweeps []*lnrpc.Transaction, outpoint string) *lnrpc.Transaction {
+	for _, sweep := range sweeps {
+		isPrevious := slices.ContainsFunc(previousSweeps, func(previous *lnrpc.Transaction) bool {
+			return previous.TxHash == sweep.TxHash
+		})
+		spendsOutpoint := slices.ContainsFunc(sweep.PreviousOutpoints, func(input *lnrpc.PreviousOutPoint) bool {
+			return input.Outpoint == outpoint
+		})
+		if spendsOutpoint && !isPrevious {
+			return sweep
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package flnd
+
+import (
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+const (
+	bumpTxid      = "1111111111111111111111111111111111111111111111111111111111111111"
+	bumpInputTxid = "2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func unconfirmedTx() *lnrpc.Transaction {
+	return &lnrpc.Transaction{
+		TxHash:            bumpTxid,
+		PreviousOutpoints: []*lnrpc.PreviousOutPoint{{Outpoint: bumpInputTxid + ":0", IsOurOutput: true}},
+		OutputDetails: []*lnrpc.OutputDetail{
+			{OutputIndex: 0, Amount: 50_000},
+			{OutputIndex: 1, Amount: 10_000, IsOurAddress: true},
+			{OutputIndex: 2, Amount: 30_000, IsOurAddress: true},
+		},
+	}
+}
+
+func TestSelectBumpOutpoint_Rbf(t *testing.T) {
+	outpoint, method, err := selectBumpOutpoint(unconfirmedTx(), map[string]bool{bumpInputTxid + ":0": true})
+	require.NoError(t, err)
+	assert.Equal(t, bumpInputTxid+":0", outpoint)
+	assert.Equal(t, lnclient.BUMP_FEE_METHOD_RBF, method)
+}
+
+func TestSelectBumpOutpoint_Cpfp(t *testing.T) {
+	outpoint, method, err := selectBumpOutpoint(unconfirmedTx(), map[string]bool{})
+	require.NoError(t, err)
+	assert.Equal(t, bumpTxid+":2", outpoint)
+	assert.Equal(t, lnclient.BUMP_FEE_METHOD_CPFP, method)
+}
+
+func TestSelectBumpOutpoint_Invalid(t *testing.T) {
+	confirmed := unconfirmedTx()
+	confirmed.NumConfirmations = 1
+	_, _, err := selectBumpOutpoint(confirmed, map[string]bool{})
+	assert.ErrorIs(t, err, lnclient.ErrTransactionNotBumpable)
+
+	// a send-all has no change output to spend with a child
+	sendAll := unconfirmedTx()
+	sendAll.OutputDetails = sendAll.OutputDetails[:1]
+	_, _, err = selectBumpOutpoint(sendAll, map[string]bool{})
+	assert.ErrorIs(t, err, lnclient.ErrTransactionNotBumpable)
+}
+
+func TestFindNewSweep(t *testing.T) {
+	outpoint := bumpInputTxid + ":0"
+	original := &lnrpc.Transaction{TxHash: "original", PreviousOutpoints: []*lnrpc.PreviousOutPoint{{Outpoint: outpoint}}}
+	unrelated := &lnrpc.Transaction{TxHash: "unrelated", PreviousOutpoints: []*lnrpc.PreviousOutPoint{{Outpoint: bumpTxid + ":0"}}}
+	replacement := &lnrpc.Transaction{TxHash: "replacement", TotalFees: 2_000, PreviousOutpoints: []*lnrpc.PreviousOutPoint{{Outpoint: outpoint}}}
+
+	assert.Nil(t, findNewSweep([]*lnrpc.Transaction{original, unrelated}, []*lnrpc.Transaction{original}, outpoint))
+	assert.Equal(t, replacement, findNewSweep([]*lnrpc.Transaction{original, unrelated, replacement}, []*lnrpc.Transaction{original}, outpoint))
+}
@@ -0,0 +1,299 @@
+package flnd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"github.com/flokiorg/go-flokicoin/chaincfg"
+	"github.com/flokiorg/go-flokicoin/txscript"
+	"github.com/flokiorg/go-flokicoin/wire"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+// frozen outputs are leased under our own lock id, so they are told apart
+// from the short leases flnd takes while funding transactions
+var frozenUtxoLockId = sha256.Sum256([]byte("lokihub-frozen-utxo"))
+
+// frozenUtxoLeaseSeconds is long enough for a freeze to only end on unfreeze
+const frozenUtxoLeaseSeconds = 10 * 365 * 24 * 60 * 60
+
+func (svc *FLNDService) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (txId string, err error) {
+	sendCoinsRequest := &lnrpc.SendCoinsRequest{
+		Addr:    toAddress,
+		SendAll: sendAll,
+		Amount:  int64(amount), //nolint:gosec // sat amounts are always far below int64 range
+	}
+
+	if feeRate != nil {
+		sendCoinsRequest.SatPerVbyte = *feeRate
+	} else {
+		sendCoinsRequest.TargetConf = 1
+	}
+
+	for _, outpoint := range outpoints {
+		parsed, err := parseOutpoint(outpoint)
+		if err != nil {
+			return "", err
+		}
+		sendCoinsRequest.Outpoints = append(sendCoinsRequest.Outpoints, parsed)
+	}
+
+	resp, err := svc.client.SendCoins(ctx, sendCoinsRequest)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to send onchain funds")
+		return "", err
+	}
+	return resp.Txid, nil
+}
+
+// ListUtxos returns the spendable outputs of the wallet followed by the
+// frozen ones, which flnd leaves out of its unspent list
+func (svc *FLNDService) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	unspent, err := svc.client.ListUnspent(ctx, &lnrpc.ListUnspentRequest{MinConfs: 0, MaxConfs: math.MaxInt32})
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list unspent outputs")
+		return nil, err
+	}
+	leases, err := svc.client.ListLeases(ctx, &walletrpc.ListLeasesRequest{})
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list leased outputs")
+		return nil, err
+	}
+
+	utxos := make([]lnclient.Utxo, 0, len(unspent.Utxos)+len(leases.LockedUtxos))
+	for _, utxo := range unspent.Utxos {
+		utxos = append(utxos, lnclient.Utxo{
+			Outpoint:      formatOutpoint(utxo.Outpoint),
+			Address:       utxo.Address,
+			AmountLoki:    utxo.AmountSat,
+			Confirmations: utxo.Confirmations,
+		})
+	}
+
+	frozen := frozenLeases(leases.LockedUtxos)
+	if len(frozen) == 0 {
+		return utxos, nil
+	}
+
+	confirmations := map[string]uint32{}
+	transactions, err := svc.ListOnchainTransactions(ctx, 0, 0, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range transactions {
+		confirmations[transaction.TxId] = transaction.NumConfirmations
+	}
+
+	params := chainParams(svc.nodeInfo.Network)
+	for _, lease := range frozen {
+		utxo := lnclient.Utxo{
+			Outpoint:      formatOutpoint(lease.Outpoint),
+			AmountLoki:    int64(lease.Value), //nolint:gosec // sat amounts are always far below int64 range
+			Confirmations: int64(confirmations[lease.Outpoint.TxidStr]),
+			Frozen:        true,
+		}
+		if params != nil {
+			_, addresses, _, err := txscript.ExtractPkScriptAddrs(lease.PkScript, params)
+			if err == nil && len(addresses) == 1 {
+				utxo.Address = addresses[0].EncodeAddress()
+			}
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+func (svc *FLNDService) FreezeUtxo(ctx context.Context, outpoint string) error {
+	parsed, err := parseOutpoint(outpoint)
+	if err != nil {
+		return err
+	}
+	_, err = svc.client.LeaseOutput(ctx, &walletrpc.LeaseOutputRequest{
+		Id:                frozenUtxoLockId[:],
+		Outpoint:          parsed,
+		ExpirationSeconds: frozenUtxoLeaseSeconds,
+	})
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("outpoint", outpoint).Msg("Failed to freeze output")
+		return err
+	}
+	return nil
+}
+
+func (svc *FLNDService) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	parsed, err := parseOutpoint(outpoint)
+	if err != nil {
+		return err
+	}
+	_, err = svc.client.ReleaseOutput(ctx, &walletrpc.ReleaseOutputRequest{
+		Id:       frozenUtxoLockId[:],
+		Outpoint: parsed,
+	})
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("outpoint", outpoint).Msg("Failed to unfreeze output")
+		return err
+	}
+	return nil
+}
+
+func (svc *FLNDService) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	fundRequest, err := buildFundPsbtRequest(createPsbtRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.client.FundPsbt(ctx, fundRequest)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to fund PSBT")
+		return nil, err
+	}
+
+	lockedUtxos := make([]string, 0, len(resp.LockedUtxos))
+	for _, lease := range resp.LockedUtxos {
+		lockedUtxos = append(lockedUtxos, formatOutpoint(lease.Outpoint))
+	}
+	return &lnclient.CreatePsbtResponse{
+		Psbt:              base64.StdEncoding.EncodeToString(resp.FundedPsbt),
+		ChangeOutputIndex: resp.ChangeOutputIndex,
+		LockedUtxos:       lockedUtxos,
+	}, nil
+}
+
+func (svc *FLNDService) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	psbtBytes, err := base64.StdEncoding.DecodeString(psbt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 PSBT: %w", err)
+	}
+
+	resp, err := svc.client.FinalizePsbt(ctx, &walletrpc.FinalizePsbtRequest{FundedPsbt: psbtBytes})
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to finalize PSBT")
+		return nil, err
+	}
+
+	txId, err := rawTxId(resp.RawFinalTx)
+	if err != nil {
+		return nil, err
+	}
+	return &lnclient.FinalizePsbtResponse{
+		SignedPsbt: base64.StdEncoding.EncodeToString(resp.SignedPsbt),
+		RawTx:      hex.EncodeToString(resp.RawFinalTx),
+		TxId:       txId,
+	}, nil
+}
+
+func (svc *FLNDService) PublishTransaction(ctx context.Context, rawTx string, label string) (txId string, err error) {
+	rawTxBytes, err := hex.DecodeString(rawTx)
+	if err != nil {
+		return "", fmt.Errorf("invalid hex transaction: %w", err)
+	}
+	txId, err = rawTxId(rawTxBytes)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := svc.client.PublishTransaction(ctx, &walletrpc.Transaction{TxHex: rawTxBytes, Label: label})
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("txid", txId).Msg("Failed to publish transaction")
+		return "", err
+	}
+	if resp.PublishError != "" {
+		logger.Logger.Error().Str("txid", txId).Str("error", resp.PublishError).Msg("Transaction rejected")
+		return "", errors.New(resp.PublishError)
+	}
+	return txId, nil
+}
+
+// buildFundPsbtRequest turns the outputs into a transaction template. Given
+// outpoints become the only inputs; otherwise flnd selects them.
+func buildFundPsbtRequest(createPsbtRequest *lnclient.CreatePsbtRequest) (*walletrpc.FundPsbtRequest, error) {
+	if len(createPsbtRequest.Outputs) == 0 {
+		return nil, errors.New("no outputs")
+	}
+
+	template := &walletrpc.TxTemplate{
+		Outputs: make(map[string]uint64, len(createPsbtRequest.Outputs)),
+	}
+	for _, output := range createPsbtRequest.Outputs {
+		if output.Address == "" || output.AmountLoki == 0 {
+			return nil, errors.New("outputs need an address and an amount")
+		}
+		if _, ok := template.Outputs[output.Address]; ok {
+			return nil, fmt.Errorf("duplicate output address %s", output.Address)
+		}
+		template.Outputs[output.Address] = output.AmountLoki
+	}
+	for _, outpoint := range createPsbtRequest.Outpoints {
+		parsed, err := parseOutpoint(outpoint)
+		if err != nil {
+			return nil, err
+		}
+		template.Inputs = append(template.Inputs, parsed)
+	}
+
+	req := &walletrpc.FundPsbtRequest{
+		Template: &walletrpc.FundPsbtRequest_Raw{Raw: template},
+	}
+	switch {
+	case createPsbtRequest.FeeRate != nil:
+		req.Fees = &walletrpc.FundPsbtRequest_SatPerVbyte{SatPerVbyte: *createPsbtRequest.FeeRate}
+	case createPsbtRequest.ConfTarget != nil:
+		req.Fees = &walletrpc.FundPsbtRequest_TargetConf{TargetConf: *createPsbtRequest.ConfTarget}
+	default:
+		req.Fees = &walletrpc.FundPsbtRequest_TargetConf{TargetConf: 1}
+	}
+	return req, nil
+}
+
+func frozenLeases(leases []*walletrpc.UtxoLease) []*walletrpc.UtxoLease {
+	frozen := []*walletrpc.UtxoLease{}
+	for _, lease := range leases {
+		if bytes.Equal(lease.Id, frozenUtxoLockId[:]) && lease.Outpoint != nil {
+			frozen = append(frozen, lease)
+		}
+	}
+	return frozen
+}
+
+func formatOutpoint(outpoint *lnrpc.OutPoint) string {
+	if outpoint == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", outpoint.TxidStr, outpoint.OutputIndex)
+}
+
+func rawTxId(rawTx []byte) (string, error) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return "", fmt.Errorf("invalid transaction: %w", err)
+	}
+	return tx.TxHash().String(), nil
+}
+
+// chainParams maps the network reported by flnd to its chain parameters
+func chainParams(network string) *chaincfg.Params {
+	switch network {
+	case "bitcoin", "mainnet":
+		return &chaincfg.MainNetParams
+	case "testnet":
+		return &chaincfg.TestNet3Params
+	case "testnet4":
+		return &chaincfg.TestNet4Params
+	case "regtest":
+		return &chaincfg.RegressionNetParams
+	case "signet":
+		return &chaincfg.SigNetParams
+	case "simnet":
+		return &chaincfg.SimNetParams
+	}
+	return nil
+}
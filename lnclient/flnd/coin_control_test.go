@@ -0,0 +1,93 @@
+package flnd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"github.com/flokiorg/go-flokicoin/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+func TestBuildFundPsbtRequest(t *testing.T) {
+	feeRate := uint64(7)
+	req, err := buildFundPsbtRequest(&lnclient.CreatePsbtRequest{
+		Outputs: []lnclient.PsbtOutput{
+			{Address: "fc1qfirst", AmountLoki: 10_000},
+			{Address: "fc1qsecond", AmountLoki: 20_000},
+		},
+		Outpoints: []string{openChannelTxid + ":2"},
+		FeeRate:   &feeRate,
+	})
+	require.NoError(t, err)
+
+	template := req.GetRaw()
+	require.NotNil(t, template)
+	assert.Equal(t, map[string]uint64{"fc1qfirst": 10_000, "fc1qsecond": 20_000}, template.Outputs)
+	require.Len(t, template.Inputs, 1)
+	assert.Equal(t, openChannelTxid, template.Inputs[0].TxidStr)
+	assert.Equal(t, uint32(2), template.Inputs[0].OutputIndex)
+	assert.Equal(t, uint64(7), req.GetSatPerVbyte())
+}
+
+func TestBuildFundPsbtRequest_Fees(t *testing.T) {
+	outputs := []lnclient.PsbtOutput{{Address: "fc1qfirst", AmountLoki: 10_000}}
+
+	req, err := buildFundPsbtRequest(&lnclient.CreatePsbtRequest{Outputs: outputs})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), req.GetTargetConf())
+	assert.Empty(t, req.GetRaw().Inputs)
+
+	confTarget := uint32(12)
+	req, err = buildFundPsbtRequest(&lnclient.CreatePsbtRequest{Outputs: outputs, ConfTarget: &confTarget})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(12), req.GetTargetConf())
+}
+
+func TestBuildFundPsbtRequest_Invalid(t *testing.T) {
+	invalid := []*lnclient.CreatePsbtRequest{
+		{},
+		{Outputs: []lnclient.PsbtOutput{{Address: "fc1qfirst"}}},
+		{Outputs: []lnclient.PsbtOutput{{AmountLoki: 10_000}}},
+		{Outputs: []lnclient.PsbtOutput{{Address: "fc1qfirst", AmountLoki: 1}, {Address: "fc1qfirst", AmountLoki: 2}}},
+		{Outputs: []lnclient.PsbtOutput{{Address: "fc1qfirst", AmountLoki: 1}}, Outpoints: []string{"txid"}},
+	}
+	for _, req := range invalid {
+		_, err := buildFundPsbtRequest(req)
+		assert.Error(t, err)
+	}
+}
+
+func TestFrozenLeases(t *testing.T) {
+	frozen := &walletrpc.UtxoLease{Id: frozenUtxoLockId[:], Outpoint: &lnrpc.OutPoint{TxidStr: openChannelTxid, OutputIndex: 1}, Value: 5_000}
+	funding := &walletrpc.UtxoLease{Id: []byte("funding lock"), Outpoint: &lnrpc.OutPoint{TxidStr: openChannelTxid, OutputIndex: 2}}
+
+	leases := frozenLeases([]*walletrpc.UtxoLease{frozen, funding})
+	require.Len(t, leases, 1)
+	assert.Equal(t, openChannelTxid+":1", formatOutpoint(leases[0].Outpoint))
+}
+
+func TestRawTxId(t *testing.T) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1_000, []byte{0x51}))
+	var buf bytes.Buffer
+	require.NoError(t, tx.Serialize(&buf))
+
+	txId, err := rawTxId(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, tx.TxHash().String(), txId)
+
+	_, err = rawTxId([]byte{0x01, 0x02})
+	assert.Error(t, err)
+}
+
+func TestChainParams(t *testing.T) {
+	assert.Equal(t, "main", chainParams("bitcoin").Name)
+	assert.Equal(t, "regtest", chainParams("regtest").Name)
+	assert.Nil(t, chainParams("unknown"))
+}
@@ -30,7 +30,6 @@ import (
 	"github.com/flokiorg/lokihub/logger"
 	"github.com/flokiorg/lokihub/nip47/models"
 	"github.com/flokiorg/lokihub/nip47/notifications"
-	"github.com/flokiorg/lokihub/transactions"
 	"github.com/rs/zerolog"
 	// "gorm.io/gorm"
 )
@@ -124,48 +123,12 @@ func NewFLNDService(ctx context.Context, eventPublisher events.EventPublisher, f
 	go flndService.subscribeChannelEvents(flndCtx)
 	go flndService.subscribeOpenHoldInvoices(flndCtx)
 	go flndService.subscribeTransactions(flndCtx)
-	go flndService.trackForwardedPayments(flndCtx)
 
 	logger.Logger.Info().Str("alias", nodeInfo.Alias).Msg("Connected to FLND")
 
 	return flndService, nil
 }
 
-func (svc *FLNDService) trackForwardedPayments(ctx context.Context) {
-	// NOTE: this only tracks payments when hub is online and attached
-	lastTime := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-time.After(1 * time.Minute):
-			nextTime := time.Now()
-			forwardedPayments, err := svc.client.ForwardingHistory(ctx, &lnrpc.ForwardingHistoryRequest{
-				StartTime: uint64(lastTime.Unix()), //nolint:gosec // time.Now().Unix() is always positive post-1970
-				EndTime:   uint64(nextTime.Unix()), //nolint:gosec // time.Now().Unix() is always positive post-1970
-			})
-			if err != nil {
-				if ctx.Err() != nil {
-					// context was cancelled while waiting for the timer or during the RPC call, exiting
-					return
-				}
-				logger.Logger.Error().Err(err).Msg("failed to read forwarding history")
-				continue
-			}
-			for _, forwardingEvent := range forwardedPayments.ForwardingEvents {
-				svc.eventPublisher.Publish(&events.Event{
-					Event: "nwc_payment_forwarded",
-					Properties: &lnclient.PaymentForwardedEventProperties{
-						TotalFeeEarnedMloki:          forwardingEvent.FeeMsat,
-						OutboundAmountForwardedMloki: forwardingEvent.AmtOutMsat,
-					},
-				})
-			}
-			lastTime = nextTime
-		}
-	}
-}
-
 func (svc *FLNDService) subscribePayments(ctx context.Context) {
 	for {
 		select {
@@ -473,25 +436,20 @@ func (svc *FLNDService) Shutdown() error {
 	return nil
 }
 
-func (svc *FLNDService) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
+func (svc *FLNDService) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
 	const MAX_PARTIAL_PAYMENTS = 16
 
-	paymentRequest, err := decodepay.Decode(payReq)
-	if err != nil {
+	if _, err := decodepay.Decode(payReq); err != nil {
 		logger.Logger.Error().Err(err).
 			Str("bolt11", payReq).
 			Msg("Failed to decode bolt11 invoice")
 		return nil, err
 	}
 
-	paymentAmountMloki := uint64(paymentRequest.MSat) //nolint:gosec // msat amounts are always far below int64/uint64 range
-	if amount != nil {
-		paymentAmountMloki = *amount
-	}
 	sendRequest := &routerrpc.SendPaymentRequest{
 		PaymentRequest: payReq,
 		MaxParts:       MAX_PARTIAL_PAYMENTS,
-		FeeLimitMsat:   int64(transactions.CalculateFeeReserveMloki(paymentAmountMloki)), //nolint:gosec // msat amounts are always far below int64 range
+		FeeLimitMsat:   int64(feeLimitMloki), //nolint:gosec // msat amounts are always far below int64 range
 	}
 
 	if amount != nil {
@@ -530,7 +488,16 @@ func (svc *FLNDService) SendPaymentSync(payReq string, amount *uint64) (*lnclien
 	}, nil
 }
 
-func (svc *FLNDService) SendKeysend(amount uint64, destination string, custom_records []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
+// flnd has no BOLT12 support yet, so offers can be neither created nor paid.
+func (svc *FLNDService) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	return nil, lnclient.ErrOffersNotSupported
+}
+
+func (svc *FLNDService) MakeOffer(ctx context.Context, description string) (string, error) {
+	return "", lnclient.ErrOffersNotSupported
+}
+
+func (svc *FLNDService) SendKeysend(amount uint64, destination string, custom_records []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
 	destBytes, err := hex.DecodeString(destination)
 	if err != nil {
 		logger.Logger.Error().Err(err).
@@ -577,7 +544,7 @@ func (svc *FLNDService) SendKeysend(amount uint64, destination string, custom_re
 		DestCustomRecords: destCustomRecords,
 		MaxParts:          MAX_PARTIAL_PAYMENTS,
 		TimeoutSeconds:    SEND_PAYMENT_TIMEOUT,
-		FeeLimitMsat:      int64(transactions.CalculateFeeReserveMloki(amount)), //nolint:gosec // msat amounts are always far below int64 range
+		FeeLimitMsat:      int64(feeLimitMloki), //nolint:gosec // msat amounts are always far below int64 range
 	}
 
 	payStream, err := svc.client.SendPayment(svc.ctx, sendPaymentRequest)
@@ -1309,13 +1276,12 @@ func (svc *FLNDService) OpenChannel(ctx context.Context, openChannelRequest *lnc
 		return nil, errors.New("failed to decode pubkey")
 	}
 
-	channel, err := svc.client.OpenChannelSync(ctx, &lnrpc.OpenChannelRequest{
-		NodePubkey:         nodePub,
-		Private:            !openChannelRequest.Public,
-		LocalFundingAmount: openChannelRequest.AmountLoki,
-		// set a super-high forwarding fee of 100K loki by default to disable unwanted routing
-		BaseFee: 100_000_000,
-	})
+	openRequest, err := buildOpenChannelRequest(nodePub, openChannelRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := svc.client.OpenChannelSync(ctx, openRequest)
 	if err != nil {
 		logger.Logger.Error().Err(err).Msg("Failed to open channel")
 		return nil, fmt.Errorf("failed to open channel with %s: %s", foundPeer.NodeId, err)
@@ -1499,24 +1465,7 @@ func (svc *FLNDService) GetOnchainBalance(ctx context.Context) (*lnclient.Onchai
 }
 
 func (svc *FLNDService) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (txId string, err error) {
-	sendCoinsRequest := &lnrpc.SendCoinsRequest{
-		Addr:    toAddress,
-		SendAll: sendAll,
-		Amount:  int64(amount), //nolint:gosec // sat amounts are always far below int64 range
-	}
-
-	if feeRate != nil {
-		sendCoinsRequest.SatPerVbyte = *feeRate
-	} else {
-		sendCoinsRequest.TargetConf = 1
-	}
-
-	resp, err := svc.client.SendCoins(ctx, sendCoinsRequest)
-	if err != nil {
-		logger.Logger.Error().Err(err).Msg("Failed to send onchain funds")
-		return "", err
-	}
-	return resp.Txid, nil
+	return svc.SendOnchainFundsFromUtxos(ctx, toAddress, amount, feeRate, sendAll, nil)
 }
 
 func (svc *FLNDService) ResetRouter(key string) error {
@@ -1533,14 +1482,6 @@ func (svc *FLNDService) SignMessage(ctx context.Context, message string) (string
 	return resp.Signature, nil
 }
 
-func (svc *FLNDService) SendPaymentProbes(ctx context.Context, invoice string) error {
-	return nil
-}
-
-func (svc *FLNDService) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
-	return nil
-}
-
 func (svc *FLNDService) ListPeers(ctx context.Context) ([]lnclient.PeerDetails, error) {
 	resp, err := svc.client.ListPeers(ctx, &lnrpc.ListPeersRequest{})
 	if err != nil {
@@ -1734,7 +1675,16 @@ func (svc *FLNDService) GetSupportedNIP47Methods() []string {
 }
 
 func (svc *FLNDService) GetSupportedNIP47NotificationTypes() []string {
-	return []string{notifications.PAYMENT_RECEIVED_NOTIFICATION, notifications.PAYMENT_SENT_NOTIFICATION, notifications.HOLD_INVOICE_ACCEPTED_NOTIFICATION}
+	return []string{
+		notifications.PAYMENT_RECEIVED_NOTIFICATION,
+		notifications.PAYMENT_SENT_NOTIFICATION,
+		notifications.HOLD_INVOICE_ACCEPTED_NOTIFICATION,
+		notifications.BUDGET_WARNING_NOTIFICATION,
+		notifications.BUDGET_RENEWED_NOTIFICATION,
+		notifications.CONNECTION_EXPIRING_NOTIFICATION,
+		notifications.PAYMENT_FAILED_NOTIFICATION,
+		notifications.BALANCE_CHANGED_NOTIFICATION,
+	}
 }
 
 func (svc *FLNDService) GetPubkey() string {
@@ -1855,14 +1805,6 @@ func flndInvoiceToTransaction(invoice *lnrpc.Invoice) *lnclient.Transaction {
 	}
 }
 
-func (svc *FLNDService) GetCustomNodeCommandDefinitions() []lnclient.CustomNodeCommandDef {
-	return nil
-}
-
-func (svc *FLNDService) ExecuteCustomNodeCommand(ctx context.Context, command *lnclient.CustomNodeCommandRequest) (*lnclient.CustomNodeCommandResponse, error) {
-	return nil, nil
-}
-
 func (svc *FLNDService) subscribeTransactions(ctx context.Context) {
 	stream, err := svc.client.SubscribeTransactions(ctx, &lnrpc.GetTransactionsRequest{})
 	if err != nil {
@@ -2026,7 +1968,7 @@ func (svc *FLNDService) ListOnchainTransactions(ctx context.Context, from, until
 	return result, nil
 }
 
-func (svc *FLNDService) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (svc *FLNDService) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	stream, err := svc.client.ChannelAcceptor(ctx)
 	if err != nil {
 		svc.logger.Error().Err(err).Msg("Failed to subscribe to channel acceptor")
@@ -2058,11 +2000,13 @@ func (svc *FLNDService) SubscribeChannelAcceptor(ctx context.Context) (<-chan ln
 				ID:         id,
 				NodePubkey: nodePubkey,
 				Capacity:   req.FundingAmt,
+				// bit 0 of the channel flags asks for the channel to be announced
+				Private: req.ChannelFlags&1 == 0,
 			}
 		}
 	}()
 
-	respond := func(id string, accept bool, zeroConf bool) error {
+	respond := func(id string, accept bool, zeroConf bool, rejectReason string) error {
 		chanIdBytes, err := hex.DecodeString(id)
 		if err != nil {
 			return err
@@ -2073,6 +2017,10 @@ func (svc *FLNDService) SubscribeChannelAcceptor(ctx context.Context) (<-chan ln
 			PendingChanId: chanIdBytes,
 		}
 
+		if !accept {
+			response.Error = rejectReason
+		}
+
 		if accept {
 			if zeroConf {
 				// For ZeroConf JIT channels:
@@ -2093,6 +2041,7 @@ func (svc *FLNDService) SubscribeChannelAcceptor(ctx context.Context) (<-chan ln
 			Bool("accept", accept).
 			Uint32("minAcceptDepth", response.MinAcceptDepth).
 			Bool("zeroConf", response.ZeroConf).
+			Str("error", response.Error).
 			Msg("Sending ChannelAcceptResponse")
 
 		return stream.Send(response)
@@ -0,0 +1,78 @@
+package flnd
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+func (svc *FLNDService) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	// start from the epoch so the index offset is an absolute position in
+	// the forwarding log rather than relative to a time window
+	resp, err := svc.client.ForwardingHistory(ctx, &lnrpc.ForwardingHistoryRequest{
+		StartTime:    0,
+		EndTime:      uint64(time.Now().Unix()), //nolint:gosec // time.Now().Unix() is always positive post-1970
+		IndexOffset:  clampUint64ToUint32(afterIndex),
+		NumMaxEvents: clampUint64ToUint32(limit),
+	})
+	if err != nil {
+		logger.Logger.Error().Err(err).Uint64("after_index", afterIndex).Msg("Failed to read forwarding history")
+		return nil, err
+	}
+	if len(resp.ForwardingEvents) == 0 {
+		return []lnclient.ForwardingEvent{}, nil
+	}
+
+	channelPeers, err := svc.getChannelPeers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toForwardingEvents(resp.ForwardingEvents, afterIndex, channelPeers), nil
+}
+
+// getChannelPeers maps the ids of open and closed channels to the pubkey of
+// the peer on the other side.
+func (svc *FLNDService) getChannelPeers(ctx context.Context) (map[uint64]string, error) {
+	openChannels, err := svc.client.ListChannels(ctx, &lnrpc.ListChannelsRequest{})
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list channels")
+		return nil, err
+	}
+	closedChannels, err := svc.client.ClosedChannels(ctx, &lnrpc.ClosedChannelsRequest{})
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list closed channels")
+		return nil, err
+	}
+
+	channelPeers := make(map[uint64]string, len(openChannels.Channels)+len(closedChannels.Channels))
+	for _, channel := range closedChannels.Channels {
+		channelPeers[channel.ChanId] = channel.RemotePubkey
+	}
+	for _, channel := range openChannels.Channels {
+		channelPeers[channel.ChanId] = channel.RemotePubkey
+	}
+	return channelPeers, nil
+}
+
+func toForwardingEvents(flndEvents []*lnrpc.ForwardingEvent, afterIndex uint64, channelPeers map[uint64]string) []lnclient.ForwardingEvent {
+	forwardingEvents := make([]lnclient.ForwardingEvent, 0, len(flndEvents))
+	for i, flndEvent := range flndEvents {
+		forwardingEvents = append(forwardingEvents, lnclient.ForwardingEvent{
+			Index:              afterIndex + uint64(i) + 1, //nolint:gosec // i is a non-negative slice index
+			IncomingChannelId:  strconv.FormatUint(flndEvent.ChanIdIn, 10),
+			OutgoingChannelId:  strconv.FormatUint(flndEvent.ChanIdOut, 10),
+			IncomingPeerPubkey: channelPeers[flndEvent.ChanIdIn],
+			OutgoingPeerPubkey: channelPeers[flndEvent.ChanIdOut],
+			AmountInMloki:      flndEvent.AmtInMsat,
+			AmountOutMloki:     flndEvent.AmtOutMsat,
+			FeeMloki:           flndEvent.FeeMsat,
+			Timestamp:          time.Unix(0, int64(flndEvent.TimestampNs)), //nolint:gosec // nanosecond timestamps fit in int64 until 2262
+		})
+	}
+	return forwardingEvents
+}
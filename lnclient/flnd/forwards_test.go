@@ -0,0 +1,53 @@
+package flnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToForwardingEvents(t *testing.T) {
+	timestamp := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	flndEvents := []*lnrpc.ForwardingEvent{
+		{
+			ChanIdIn:    111,
+			ChanIdOut:   222,
+			AmtInMsat:   101_000,
+			AmtOutMsat:  100_000,
+			FeeMsat:     1_000,
+			TimestampNs: uint64(timestamp.UnixNano()),
+		},
+		{
+			ChanIdIn:    222,
+			ChanIdOut:   333,
+			AmtInMsat:   50_500,
+			AmtOutMsat:  50_000,
+			FeeMsat:     500,
+			TimestampNs: uint64(timestamp.Add(time.Minute).UnixNano()),
+		},
+	}
+	channelPeers := map[uint64]string{
+		111: "02aaaa",
+		222: "02bbbb",
+	}
+
+	forwardingEvents := toForwardingEvents(flndEvents, 40, channelPeers)
+
+	require.Len(t, forwardingEvents, 2)
+	assert.Equal(t, uint64(41), forwardingEvents[0].Index)
+	assert.Equal(t, "111", forwardingEvents[0].IncomingChannelId)
+	assert.Equal(t, "222", forwardingEvents[0].OutgoingChannelId)
+	assert.Equal(t, "02aaaa", forwardingEvents[0].IncomingPeerPubkey)
+	assert.Equal(t, "02bbbb", forwardingEvents[0].OutgoingPeerPubkey)
+	assert.Equal(t, uint64(101_000), forwardingEvents[0].AmountInMloki)
+	assert.Equal(t, uint64(100_000), forwardingEvents[0].AmountOutMloki)
+	assert.Equal(t, uint64(1_000), forwardingEvents[0].FeeMloki)
+	assert.True(t, timestamp.Equal(forwardingEvents[0].Timestamp))
+
+	assert.Equal(t, uint64(42), forwardingEvents[1].Index)
+	// channel 333 is unknown to the node, e.g. closed and forgotten
+	assert.Empty(t, forwardingEvents[1].OutgoingPeerPubkey)
+}
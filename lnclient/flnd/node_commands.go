@@ -0,0 +1,374 @@
+package flnd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+// nodeCommandClient is the subset of the flnd wrapper used by the custom node
+// commands, so they can be run against a fake node in tests.
+type nodeCommandClient interface {
+	GetInfo(ctx context.Context, req *lnrpc.GetInfoRequest, options ...grpc.CallOption) (*lnrpc.GetInfoResponse, error)
+	PendingChannels(ctx context.Context, req *lnrpc.PendingChannelsRequest, options ...grpc.CallOption) (*lnrpc.PendingChannelsResponse, error)
+	ForwardingHistory(ctx context.Context, req *lnrpc.ForwardingHistoryRequest, options ...grpc.CallOption) (*lnrpc.ForwardingHistoryResponse, error)
+	GetNodeInfo(ctx context.Context, req *lnrpc.NodeInfoRequest, options ...grpc.CallOption) (*lnrpc.NodeInfo, error)
+	QueryRoutes(ctx context.Context, req *lnrpc.QueryRoutesRequest, options ...grpc.CallOption) (*lnrpc.QueryRoutesResponse, error)
+	WalletBalance(ctx context.Context, req *lnrpc.WalletBalanceRequest, options ...grpc.CallOption) (*lnrpc.WalletBalanceResponse, error)
+	ListUnspent(ctx context.Context, req *lnrpc.ListUnspentRequest, options ...grpc.CallOption) (*lnrpc.ListUnspentResponse, error)
+	BumpFee(ctx context.Context, req *walletrpc.BumpFeeRequest, options ...grpc.CallOption) (*walletrpc.BumpFeeResponse, error)
+	UpdateChannel(ctx context.Context, req *lnrpc.PolicyUpdateRequest, options ...grpc.CallOption) (*lnrpc.PolicyUpdateResponse, error)
+	ClosedChannels(ctx context.Context, req *lnrpc.ClosedChannelsRequest, options ...grpc.CallOption) (*lnrpc.ClosedChannelsResponse, error)
+}
+
+// node command responses use the same JSON shape as flncli.
+var nodeCommandMarshalOptions = protojson.MarshalOptions{
+	EmitUnpopulated: true,
+	UseProtoNames:   true,
+	UseHexForBytes:  true,
+}
+
+var nodeCommandDefinitions = []lnclient.CustomNodeCommandDef{
+	{
+		Name:        "getinfo",
+		Description: "Returns basic information about the node.",
+	},
+	{
+		Name:        "pendingchannels",
+		Description: "Lists channels that are being opened or closed.",
+	},
+	{
+		Name:        "fwdinghistory",
+		Description: "Lists payments forwarded by the node.",
+		Args: []lnclient.CustomNodeCommandArgDef{
+			{Name: "start_time", Description: "Unix timestamp (seconds) to start from", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "end_time", Description: "Unix timestamp (seconds) to end at, defaults to now", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "index_offset", Description: "Number of forwarding events to skip", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "max_events", Description: "Maximum number of events to return, defaults to 100", Type: lnclient.CustomNodeCommandArgTypeInteger},
+		},
+	},
+	{
+		Name:        "describegraph",
+		Description: "Describes a node in the network graph along with its channels.",
+		Args: []lnclient.CustomNodeCommandArgDef{
+			{Name: "node", Description: "Pubkey of the node to describe", Type: lnclient.CustomNodeCommandArgTypeString, Required: true},
+		},
+	},
+	{
+		Name:        "queryroutes",
+		Description: "Finds a route to a node for the given amount.",
+		Args: []lnclient.CustomNodeCommandArgDef{
+			{Name: "dest", Description: "Pubkey of the destination node", Type: lnclient.CustomNodeCommandArgTypeString, Required: true},
+			{Name: "amt", Description: "Amount to route in loki", Type: lnclient.CustomNodeCommandArgTypeInteger, Required: true},
+			{Name: "final_cltv_delta", Description: "CLTV delta used for the final hop", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "fee_limit", Description: "Maximum routing fee in loki", Type: lnclient.CustomNodeCommandArgTypeInteger},
+		},
+	},
+	{
+		Name:        "walletbalance",
+		Description: "Returns the on-chain wallet balance.",
+	},
+	{
+		Name:        "listunspent",
+		Description: "Lists unspent on-chain outputs of the wallet.",
+		Args: []lnclient.CustomNodeCommandArgDef{
+			{Name: "min_confs", Description: "Minimum number of confirmations, defaults to 1", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "max_confs", Description: "Maximum number of confirmations", Type: lnclient.CustomNodeCommandArgTypeInteger},
+		},
+	},
+	{
+		Name:        "bumpfee",
+		Description: "Bumps the fee of an unconfirmed transaction spending one of the wallet's outputs.",
+		Args: []lnclient.CustomNodeCommandArgDef{
+			{Name: "outpoint", Description: "Outpoint to bump, as txid:index", Type: lnclient.CustomNodeCommandArgTypeOutpoint, Required: true},
+			{Name: "conf_target", Description: "Number of blocks to confirm within", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "sat_per_vbyte", Description: "Fee rate in loki per vbyte", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "budget", Description: "Maximum fee in loki to spend on the bump", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "immediate", Description: "Broadcast immediately instead of waiting for the next block", Type: lnclient.CustomNodeCommandArgTypeBoolean},
+		},
+	},
+	{
+		Name:        "updatechanpolicy",
+		Description: "Updates the forwarding policy of one channel, or of all channels if no chan_point is given.",
+		Args: []lnclient.CustomNodeCommandArgDef{
+			{Name: "base_fee_msat", Description: "Base fee in mloki", Type: lnclient.CustomNodeCommandArgTypeInteger, Required: true},
+			{Name: "fee_rate_ppm", Description: "Proportional fee in parts per million", Type: lnclient.CustomNodeCommandArgTypeInteger, Required: true},
+			{Name: "time_lock_delta", Description: "CLTV delta for forwarded HTLCs", Type: lnclient.CustomNodeCommandArgTypeInteger, Required: true},
+			{Name: "min_htlc_msat", Description: "Minimum HTLC size in mloki", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "max_htlc_msat", Description: "Maximum HTLC size in mloki", Type: lnclient.CustomNodeCommandArgTypeInteger},
+			{Name: "chan_point", Description: "Channel point as txid:index", Type: lnclient.CustomNodeCommandArgTypeOutpoint},
+		},
+	},
+	{
+		Name:        "closedchannels",
+		Description: "Lists channels that have been closed.",
+	},
+}
+
+func (svc *FLNDService) GetCustomNodeCommandDefinitions() []lnclient.CustomNodeCommandDef {
+	return nodeCommandDefinitions
+}
+
+func (svc *FLNDService) ExecuteCustomNodeCommand(ctx context.Context, command *lnclient.CustomNodeCommandRequest) (*lnclient.CustomNodeCommandResponse, error) {
+	return executeNodeCommand(ctx, svc.client, command)
+}
+
+func executeNodeCommand(ctx context.Context, client nodeCommandClient, command *lnclient.CustomNodeCommandRequest) (*lnclient.CustomNodeCommandResponse, error) {
+	args := newNodeCommandArgs(command.Args)
+
+	var resp proto.Message
+	var err error
+	switch command.Name {
+	case "getinfo":
+		resp, err = nodeCommandResult(client.GetInfo(ctx, &lnrpc.GetInfoRequest{}))
+	case "pendingchannels":
+		resp, err = nodeCommandResult(client.PendingChannels(ctx, &lnrpc.PendingChannelsRequest{}))
+	case "fwdinghistory":
+		req := &lnrpc.ForwardingHistoryRequest{NumMaxEvents: 100, PeerAliasLookup: true}
+		if err = errors.Join(
+			args.parseUint64("start_time", &req.StartTime),
+			args.parseUint64("end_time", &req.EndTime),
+			args.parseUint32("index_offset", &req.IndexOffset),
+			args.parseUint32("max_events", &req.NumMaxEvents),
+		); err != nil {
+			return nil, err
+		}
+		resp, err = nodeCommandResult(client.ForwardingHistory(ctx, req))
+	case "describegraph":
+		req := &lnrpc.NodeInfoRequest{IncludeChannels: true}
+		if err = args.requireString("node", &req.PubKey); err != nil {
+			return nil, err
+		}
+		resp, err = nodeCommandResult(client.GetNodeInfo(ctx, req))
+	case "queryroutes":
+		var feeLimit uint64
+		req := &lnrpc.QueryRoutesRequest{UseMissionControl: true}
+		if err = errors.Join(
+			args.requireString("dest", &req.PubKey),
+			args.requireInt64("amt", &req.Amt),
+			args.parseInt32("final_cltv_delta", &req.FinalCltvDelta),
+			args.parseUint64("fee_limit", &feeLimit),
+		); err != nil {
+			return nil, err
+		}
+		if args.has("fee_limit") {
+			req.FeeLimit = &lnrpc.FeeLimit{Limit: &lnrpc.FeeLimit_Fixed{Fixed: int64(feeLimit)}} //nolint:gosec // loki amounts are always far below int64 range
+		}
+		resp, err = nodeCommandResult(client.QueryRoutes(ctx, req))
+	case "walletbalance":
+		resp, err = nodeCommandResult(client.WalletBalance(ctx, &lnrpc.WalletBalanceRequest{}))
+	case "listunspent":
+		req := &lnrpc.ListUnspentRequest{MinConfs: 1, MaxConfs: math.MaxInt32}
+		if err = errors.Join(
+			args.parseInt32("min_confs", &req.MinConfs),
+			args.parseInt32("max_confs", &req.MaxConfs),
+		); err != nil {
+			return nil, err
+		}
+		resp, err = nodeCommandResult(client.ListUnspent(ctx, req))
+	case "bumpfee":
+		req := &walletrpc.BumpFeeRequest{}
+		if err = errors.Join(
+			args.requireOutpoint("outpoint", &req.Outpoint),
+			args.parseUint32("conf_target", &req.TargetConf),
+			args.parseUint64("sat_per_vbyte", &req.SatPerVbyte),
+			args.parseUint64("budget", &req.Budget),
+			args.parseBool("immediate", &req.Immediate),
+		); err != nil {
+			return nil, err
+		}
+		resp, err = nodeCommandResult(client.BumpFee(ctx, req))
+	case "updatechanpolicy":
+		var chanPoint *lnrpc.OutPoint
+		req := &lnrpc.PolicyUpdateRequest{}
+		if err = errors.Join(
+			args.requireInt64("base_fee_msat", &req.BaseFeeMsat),
+			args.requireUint32("fee_rate_ppm", &req.FeeRatePpm),
+			args.requireUint32("time_lock_delta", &req.TimeLockDelta),
+			args.parseUint64("min_htlc_msat", &req.MinHtlcMsat),
+			args.parseUint64("max_htlc_msat", &req.MaxHtlcMsat),
+			args.parseOutpoint("chan_point", &chanPoint),
+		); err != nil {
+			return nil, err
+		}
+		req.MinHtlcMsatSpecified = args.has("min_htlc_msat")
+		if chanPoint == nil {
+			req.Scope = &lnrpc.PolicyUpdateRequest_Global{Global: true}
+		} else {
+			req.Scope = &lnrpc.PolicyUpdateRequest_ChanPoint{
+				ChanPoint: &lnrpc.ChannelPoint{
+					FundingTxid: &lnrpc.ChannelPoint_FundingTxidStr{FundingTxidStr: chanPoint.TxidStr},
+					OutputIndex: chanPoint.OutputIndex,
+				},
+			}
+		}
+		resp, err = nodeCommandResult(client.UpdateChannel(ctx, req))
+	case "closedchannels":
+		resp, err = nodeCommandResult(client.ClosedChannels(ctx, &lnrpc.ClosedChannelsRequest{}))
+	default:
+		return nil, lnclient.ErrUnknownCustomNodeCommand
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	respJson, err := nodeCommandMarshalOptions.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnclient.CustomNodeCommandResponse{
+		Response: json.RawMessage(respJson),
+	}, nil
+}
+
+// nodeCommandResult avoids storing a typed nil response in the proto.Message
+// interface when the call failed.
+func nodeCommandResult[T proto.Message](resp T, err error) (proto.Message, error) {
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// nodeCommandArgs parses the string values received from the API into the
+// types the flnd RPCs expect. Unset optional args leave the target untouched.
+type nodeCommandArgs map[string]string
+
+func newNodeCommandArgs(args []lnclient.CustomNodeCommandArg) nodeCommandArgs {
+	values := make(nodeCommandArgs, len(args))
+	for _, arg := range args {
+		values[arg.Name] = arg.Value
+	}
+	return values
+}
+
+func (args nodeCommandArgs) has(name string) bool {
+	_, ok := args[name]
+	return ok
+}
+
+func (args nodeCommandArgs) require(name string) error {
+	if !args.has(name) {
+		return fmt.Errorf("missing required argument --%s", name)
+	}
+	return nil
+}
+
+func (args nodeCommandArgs) parseString(name string, target *string) error {
+	if value, ok := args[name]; ok {
+		*target = value
+	}
+	return nil
+}
+
+func (args nodeCommandArgs) requireString(name string, target *string) error {
+	if args[name] == "" {
+		return fmt.Errorf("missing required argument --%s", name)
+	}
+	return args.parseString(name, target)
+}
+
+func (args nodeCommandArgs) parseOutpoint(name string, target **lnrpc.OutPoint) error {
+	value, ok := args[name]
+	if !ok {
+		return nil
+	}
+	txid, outputIndexStr, found := strings.Cut(value, ":")
+	outputIndex, err := strconv.ParseUint(outputIndexStr, 10, 32)
+	if !found || txid == "" || err != nil {
+		return fmt.Errorf("invalid value for --%s: expected txid:index", name)
+	}
+	*target = &lnrpc.OutPoint{TxidStr: txid, OutputIndex: uint32(outputIndex)}
+	return nil
+}
+
+func (args nodeCommandArgs) requireOutpoint(name string, target **lnrpc.OutPoint) error {
+	if err := args.require(name); err != nil {
+		return err
+	}
+	return args.parseOutpoint(name, target)
+}
+
+func (args nodeCommandArgs) parseBool(name string, target *bool) error {
+	value, ok := args[name]
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for --%s: %w", name, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func (args nodeCommandArgs) parseInt32(name string, target *int32) error {
+	value, ok := args[name]
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid value for --%s: %w", name, err)
+	}
+	*target = int32(parsed)
+	return nil
+}
+
+func (args nodeCommandArgs) requireInt64(name string, target *int64) error {
+	if err := args.require(name); err != nil {
+		return err
+	}
+	parsed, err := strconv.ParseInt(args[name], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value for --%s: %w", name, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func (args nodeCommandArgs) parseUint32(name string, target *uint32) error {
+	value, ok := args[name]
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid value for --%s: %w", name, err)
+	}
+	*target = uint32(parsed)
+	return nil
+}
+
+func (args nodeCommandArgs) requireUint32(name string, target *uint32) error {
+	if err := args.require(name); err != nil {
+		return err
+	}
+	return args.parseUint32(name, target)
+}
+
+func (args nodeCommandArgs) parseUint64(name string, target *uint64) error {
+	value, ok := args[name]
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value for --%s: %w", name, err)
+	}
+	*target = parsed
+	return nil
+}
@@ -0,0 +1,253 @@
+package flnd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+// fakeNodeCommandClient records the last request it received and returns
+// empty responses, or err if set.
+type fakeNodeCommandClient struct {
+	lastRequest proto.Message
+	getInfo     *lnrpc.GetInfoResponse
+	err         error
+}
+
+func fakeNodeCommandCall[Req proto.Message, Resp proto.Message](fake *fakeNodeCommandClient, req Req, resp Resp) (Resp, error) {
+	fake.lastRequest = req
+	if fake.err != nil {
+		var empty Resp
+		return empty, fake.err
+	}
+	return resp, nil
+}
+
+func (fake *fakeNodeCommandClient) GetInfo(ctx context.Context, req *lnrpc.GetInfoRequest, options ...grpc.CallOption) (*lnrpc.GetInfoResponse, error) {
+	resp := fake.getInfo
+	if resp == nil {
+		resp = &lnrpc.GetInfoResponse{}
+	}
+	return fakeNodeCommandCall(fake, req, resp)
+}
+
+func (fake *fakeNodeCommandClient) PendingChannels(ctx context.Context, req *lnrpc.PendingChannelsRequest, options ...grpc.CallOption) (*lnrpc.PendingChannelsResponse, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.PendingChannelsResponse{})
+}
+
+func (fake *fakeNodeCommandClient) ForwardingHistory(ctx context.Context, req *lnrpc.ForwardingHistoryRequest, options ...grpc.CallOption) (*lnrpc.ForwardingHistoryResponse, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.ForwardingHistoryResponse{})
+}
+
+func (fake *fakeNodeCommandClient) GetNodeInfo(ctx context.Context, req *lnrpc.NodeInfoRequest, options ...grpc.CallOption) (*lnrpc.NodeInfo, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.NodeInfo{})
+}
+
+func (fake *fakeNodeCommandClient) QueryRoutes(ctx context.Context, req *lnrpc.QueryRoutesRequest, options ...grpc.CallOption) (*lnrpc.QueryRoutesResponse, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.QueryRoutesResponse{})
+}
+
+func (fake *fakeNodeCommandClient) WalletBalance(ctx context.Context, req *lnrpc.WalletBalanceRequest, options ...grpc.CallOption) (*lnrpc.WalletBalanceResponse, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.WalletBalanceResponse{})
+}
+
+func (fake *fakeNodeCommandClient) ListUnspent(ctx context.Context, req *lnrpc.ListUnspentRequest, options ...grpc.CallOption) (*lnrpc.ListUnspentResponse, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.ListUnspentResponse{})
+}
+
+func (fake *fakeNodeCommandClient) BumpFee(ctx context.Context, req *walletrpc.BumpFeeRequest, options ...grpc.CallOption) (*walletrpc.BumpFeeResponse, error) {
+	return fakeNodeCommandCall(fake, req, &walletrpc.BumpFeeResponse{})
+}
+
+func (fake *fakeNodeCommandClient) UpdateChannel(ctx context.Context, req *lnrpc.PolicyUpdateRequest, options ...grpc.CallOption) (*lnrpc.PolicyUpdateResponse, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.PolicyUpdateResponse{})
+}
+
+func (fake *fakeNodeCommandClient) ClosedChannels(ctx context.Context, req *lnrpc.ClosedChannelsRequest, options ...grpc.CallOption) (*lnrpc.ClosedChannelsResponse, error) {
+	return fakeNodeCommandCall(fake, req, &lnrpc.ClosedChannelsResponse{})
+}
+
+func nodeCommand(name string, args map[string]string) *lnclient.CustomNodeCommandRequest {
+	command := &lnclient.CustomNodeCommandRequest{Name: name}
+	for argName, value := range args {
+		command.Args = append(command.Args, lnclient.CustomNodeCommandArg{Name: argName, Value: value})
+	}
+	return command
+}
+
+func TestNodeCommandDefinitions(t *testing.T) {
+	names := make([]string, 0, len(nodeCommandDefinitions))
+	for _, def := range nodeCommandDefinitions {
+		names = append(names, def.Name)
+		assert.NotEmpty(t, def.Description)
+	}
+	assert.Equal(t, []string{
+		"getinfo", "pendingchannels", "fwdinghistory", "describegraph", "queryroutes",
+		"walletbalance", "listunspent", "bumpfee", "updatechanpolicy", "closedchannels",
+	}, names)
+
+	// every command must be executable, and fail without its required args
+	for _, def := range nodeCommandDefinitions {
+		_, err := executeNodeCommand(context.Background(), &fakeNodeCommandClient{}, nodeCommand(def.Name, nil))
+		assert.NotErrorIs(t, err, lnclient.ErrUnknownCustomNodeCommand, def.Name)
+
+		hasRequiredArg := false
+		for _, arg := range def.Args {
+			assert.NotEmpty(t, arg.Type, "%s --%s", def.Name, arg.Name)
+			hasRequiredArg = hasRequiredArg || arg.Required
+		}
+		if hasRequiredArg {
+			assert.ErrorContains(t, err, "missing required argument", def.Name)
+		}
+	}
+}
+
+func TestExecuteNodeCommand_GetInfo(t *testing.T) {
+	fake := &fakeNodeCommandClient{getInfo: &lnrpc.GetInfoResponse{
+		IdentityPubkey:    "02abc",
+		Alias:             "loki",
+		NumActiveChannels: 3,
+	}}
+
+	resp, err := executeNodeCommand(context.Background(), fake, nodeCommand("getinfo", nil))
+	require.NoError(t, err)
+
+	raw, ok := resp.Response.(json.RawMessage)
+	require.True(t, ok)
+	var info map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &info))
+	assert.Equal(t, "02abc", info["identity_pubkey"])
+	assert.Equal(t, "loki", info["alias"])
+	assert.Equal(t, float64(3), info["num_active_channels"])
+	// unpopulated fields are included
+	assert.Contains(t, info, "num_pending_channels")
+}
+
+func TestExecuteNodeCommand_FwdingHistory(t *testing.T) {
+	fake := &fakeNodeCommandClient{}
+
+	_, err := executeNodeCommand(context.Background(), fake, nodeCommand("fwdinghistory", map[string]string{
+		"start_time":   "1700000000",
+		"index_offset": "10",
+	}))
+	require.NoError(t, err)
+
+	req := fake.lastRequest.(*lnrpc.ForwardingHistoryRequest)
+	assert.Equal(t, uint64(1700000000), req.StartTime)
+	assert.Zero(t, req.EndTime)
+	assert.Equal(t, uint32(10), req.IndexOffset)
+	assert.Equal(t, uint32(100), req.NumMaxEvents)
+
+	_, err = executeNodeCommand(context.Background(), fake, nodeCommand("fwdinghistory", map[string]string{
+		"max_events": "-1",
+	}))
+	assert.ErrorContains(t, err, "invalid value for --max_events")
+}
+
+func TestExecuteNodeCommand_DescribeGraph(t *testing.T) {
+	fake := &fakeNodeCommandClient{}
+
+	_, err := executeNodeCommand(context.Background(), fake, nodeCommand("describegraph", nil))
+	assert.EqualError(t, err, "missing required argument --node")
+
+	_, err = executeNodeCommand(context.Background(), fake, nodeCommand("describegraph", map[string]string{"node": "02abc"}))
+	require.NoError(t, err)
+	req := fake.lastRequest.(*lnrpc.NodeInfoRequest)
+	assert.Equal(t, "02abc", req.PubKey)
+	assert.True(t, req.IncludeChannels)
+}
+
+func TestExecuteNodeCommand_QueryRoutes(t *testing.T) {
+	fake := &fakeNodeCommandClient{}
+
+	_, err := executeNodeCommand(context.Background(), fake, nodeCommand("queryroutes", map[string]string{"amt": "abc"}))
+	assert.ErrorContains(t, err, "missing required argument --dest")
+	assert.ErrorContains(t, err, "invalid value for --amt")
+
+	_, err = executeNodeCommand(context.Background(), fake, nodeCommand("queryroutes", map[string]string{
+		"dest":      "02abc",
+		"amt":       "1000",
+		"fee_limit": "5",
+	}))
+	require.NoError(t, err)
+	req := fake.lastRequest.(*lnrpc.QueryRoutesRequest)
+	assert.Equal(t, "02abc", req.PubKey)
+	assert.Equal(t, int64(1000), req.Amt)
+	assert.Equal(t, int64(5), req.FeeLimit.GetFixed())
+	assert.True(t, req.UseMissionControl)
+}
+
+func TestExecuteNodeCommand_BumpFee(t *testing.T) {
+	fake := &fakeNodeCommandClient{}
+
+	_, err := executeNodeCommand(context.Background(), fake, nodeCommand("bumpfee", map[string]string{"outpoint": "abc"}))
+	assert.EqualError(t, err, "invalid value for --outpoint: expected txid:index")
+
+	_, err = executeNodeCommand(context.Background(), fake, nodeCommand("bumpfee", map[string]string{
+		"outpoint":      "abc:1",
+		"sat_per_vbyte": "20",
+		"immediate":     "true",
+	}))
+	require.NoError(t, err)
+	req := fake.lastRequest.(*walletrpc.BumpFeeRequest)
+	assert.Equal(t, "abc", req.Outpoint.TxidStr)
+	assert.Equal(t, uint32(1), req.Outpoint.OutputIndex)
+	assert.Equal(t, uint64(20), req.SatPerVbyte)
+	assert.True(t, req.Immediate)
+}
+
+func TestExecuteNodeCommand_UpdateChanPolicy(t *testing.T) {
+	fake := &fakeNodeCommandClient{}
+	policyArgs := map[string]string{
+		"base_fee_msat":   "1000",
+		"fee_rate_ppm":    "500",
+		"time_lock_delta": "80",
+	}
+
+	_, err := executeNodeCommand(context.Background(), fake, nodeCommand("updatechanpolicy", policyArgs))
+	require.NoError(t, err)
+	req := fake.lastRequest.(*lnrpc.PolicyUpdateRequest)
+	assert.True(t, req.GetGlobal())
+	assert.Equal(t, int64(1000), req.BaseFeeMsat)
+	assert.Equal(t, uint32(500), req.FeeRatePpm)
+	assert.Equal(t, uint32(80), req.TimeLockDelta)
+	assert.False(t, req.MinHtlcMsatSpecified)
+
+	policyArgs["chan_point"] = "abc:2"
+	policyArgs["min_htlc_msat"] = "1"
+	_, err = executeNodeCommand(context.Background(), fake, nodeCommand("updatechanpolicy", policyArgs))
+	require.NoError(t, err)
+	req = fake.lastRequest.(*lnrpc.PolicyUpdateRequest)
+	assert.Equal(t, "abc", req.GetChanPoint().GetFundingTxidStr())
+	assert.Equal(t, uint32(2), req.GetChanPoint().OutputIndex)
+	assert.Equal(t, uint64(1), req.MinHtlcMsat)
+	assert.True(t, req.MinHtlcMsatSpecified)
+
+	_, err = executeNodeCommand(context.Background(), fake, nodeCommand("updatechanpolicy", map[string]string{"fee_rate_ppm": "1"}))
+	assert.ErrorContains(t, err, "missing required argument --base_fee_msat")
+	assert.ErrorContains(t, err, "missing required argument --time_lock_delta")
+}
+
+func TestExecuteNodeCommand_NodeError(t *testing.T) {
+	fake := &fakeNodeCommandClient{err: errors.New("node offline")}
+
+	resp, err := executeNodeCommand(context.Background(), fake, nodeCommand("walletbalance", nil))
+	assert.EqualError(t, err, "node offline")
+	assert.Nil(t, resp)
+}
+
+func TestExecuteNodeCommand_Unknown(t *testing.T) {
+	resp, err := executeNodeCommand(context.Background(), &fakeNodeCommandClient{}, nodeCommand("stop", nil))
+	assert.ErrorIs(t, err, lnclient.ErrUnknownCustomNodeCommand)
+	assert.Nil(t, resp)
+}
@@ -0,0 +1,163 @@
+package flnd
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/go-flokicoin/chaincfg/chainhash"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+// set a super-high forwarding fee of 100K loki by default to disable unwanted routing
+const defaultOpenChannelBaseFeeMloki = 100_000_000
+
+func (svc *FLNDService) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	peers, err := svc.ListPeers(ctx)
+	if err != nil {
+		return nil, errors.New("failed to list peers")
+	}
+	for _, channel := range batchOpenChannelRequest.Channels {
+		if !slices.ContainsFunc(peers, func(peer lnclient.PeerDetails) bool { return peer.NodeId == channel.Pubkey }) {
+			return nil, fmt.Errorf("node %s is not peered yet", channel.Pubkey)
+		}
+	}
+
+	batchRequest, err := buildBatchOpenChannelRequest(batchOpenChannelRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info().Int("channels", len(batchRequest.Channels)).Msg("Opening channels in a batch")
+
+	resp, err := svc.client.BatchOpenChannel(ctx, batchRequest)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to batch open channels")
+		return nil, fmt.Errorf("failed to batch open channels: %w", err)
+	}
+	if len(resp.PendingChannels) == 0 {
+		return nil, errors.New("no pending channels returned")
+	}
+
+	// all channels share the funding transaction, whose id comes in reverse
+	fundingTxid, err := chainhash.NewHash(resp.PendingChannels[0].Txid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding txid: %w", err)
+	}
+	fundingTxVouts := make([]uint32, 0, len(resp.PendingChannels))
+	for _, pendingChannel := range resp.PendingChannels {
+		fundingTxVouts = append(fundingTxVouts, pendingChannel.OutputIndex)
+	}
+
+	return &lnclient.BatchOpenChannelResponse{
+		FundingTxId:    fundingTxid.String(),
+		FundingTxVouts: fundingTxVouts,
+	}, nil
+}
+
+// buildOpenChannelRequest maps the open channel options onto the flnd
+// request. Zero-conf and SCID alias channels need an explicit anchors
+// channel type.
+func buildOpenChannelRequest(nodePubkey []byte, openChannelRequest *lnclient.OpenChannelRequest) (*lnrpc.OpenChannelRequest, error) {
+	outpoints := make([]*lnrpc.OutPoint, 0, len(openChannelRequest.Outpoints))
+	for _, outpoint := range openChannelRequest.Outpoints {
+		parsed, err := parseOutpoint(outpoint)
+		if err != nil {
+			return nil, err
+		}
+		outpoints = append(outpoints, parsed)
+	}
+
+	req := &lnrpc.OpenChannelRequest{
+		NodePubkey:         nodePubkey,
+		Private:            !openChannelRequest.Public,
+		LocalFundingAmount: openChannelRequest.AmountLoki,
+		PushSat:            int64(openChannelRequest.PushAmountLoki), //nolint:gosec // push amounts are below the channel amount
+		MinHtlcMsat:        int64(openChannelRequest.MinHtlcMloki),   //nolint:gosec // msat amounts are always far below int64 range
+		CloseAddress:       openChannelRequest.CloseAddress,
+		ZeroConf:           openChannelRequest.ZeroConf,
+		ScidAlias:          openChannelRequest.ScidAlias,
+		Outpoints:          outpoints,
+		BaseFee:            defaultOpenChannelBaseFeeMloki,
+	}
+	if openChannelRequest.FeeRate != nil {
+		req.SatPerVbyte = *openChannelRequest.FeeRate
+	} else if openChannelRequest.ConfTarget != nil {
+		req.TargetConf = int32(*openChannelRequest.ConfTarget) //nolint:gosec // confirmation targets are small
+	}
+	if openChannelRequest.ZeroConf || openChannelRequest.ScidAlias {
+		req.CommitmentType = lnrpc.CommitmentType_ANCHORS
+	}
+	return req, nil
+}
+
+// buildBatchOpenChannelRequest maps a batch open onto the flnd request. flnd
+// funds batches from the whole wallet, so per-channel outpoints and fee
+// options are rejected rather than silently ignored.
+func buildBatchOpenChannelRequest(batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnrpc.BatchOpenChannelRequest, error) {
+	if len(batchOpenChannelRequest.Channels) == 0 {
+		return nil, errors.New("no channels to open")
+	}
+
+	channels := make([]*lnrpc.BatchOpenChannel, 0, len(batchOpenChannelRequest.Channels))
+	for _, channel := range batchOpenChannelRequest.Channels {
+		if len(channel.Outpoints) > 0 || channel.FeeRate != nil || channel.ConfTarget != nil {
+			return nil, errors.New("outpoints and fee options cannot be set per channel in a batch")
+		}
+		nodePubkey, err := hex.DecodeString(channel.Pubkey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode pubkey %q", channel.Pubkey)
+		}
+
+		batchChannel := &lnrpc.BatchOpenChannel{
+			NodePubkey:         nodePubkey,
+			Private:            !channel.Public,
+			LocalFundingAmount: channel.AmountLoki,
+			PushSat:            int64(channel.PushAmountLoki), //nolint:gosec // push amounts are below the channel amount
+			MinHtlcMsat:        int64(channel.MinHtlcMloki),   //nolint:gosec // msat amounts are always far below int64 range
+			CloseAddress:       channel.CloseAddress,
+			ZeroConf:           channel.ZeroConf,
+			ScidAlias:          channel.ScidAlias,
+			BaseFee:            defaultOpenChannelBaseFeeMloki,
+		}
+		if channel.ZeroConf || channel.ScidAlias {
+			batchChannel.CommitmentType = lnrpc.CommitmentType_ANCHORS
+		}
+		channels = append(channels, batchChannel)
+	}
+
+	req := &lnrpc.BatchOpenChannelRequest{
+		Channels: channels,
+	}
+	if batchOpenChannelRequest.FeeRate != nil {
+		req.SatPerVbyte = int64(*batchOpenChannelRequest.FeeRate) //nolint:gosec // fee rates are small
+	} else if batchOpenChannelRequest.ConfTarget != nil {
+		req.TargetConf = int32(*batchOpenChannelRequest.ConfTarget) //nolint:gosec // confirmation targets are small
+	}
+	return req, nil
+}
+
+// parseOutpoint parses a "txid:vout" UTXO reference
+func parseOutpoint(outpoint string) (*lnrpc.OutPoint, error) {
+	txid, vout, found := strings.Cut(outpoint, ":")
+	if !found {
+		return nil, fmt.Errorf("invalid outpoint %q", outpoint)
+	}
+	if _, err := chainhash.NewHashFromStr(txid); err != nil || len(txid) != chainhash.MaxHashStringSize {
+		return nil, fmt.Errorf("invalid outpoint txid %q", txid)
+	}
+	outputIndex, err := strconv.ParseUint(vout, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outpoint index %q", vout)
+	}
+	return &lnrpc.OutPoint{
+		TxidStr:     txid,
+		OutputIndex: uint32(outputIndex),
+	}, nil
+}
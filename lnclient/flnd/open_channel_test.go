@@ -0,0 +1,101 @@
+package flnd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+const openChannelTxid = "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"
+
+func TestBuildOpenChannelRequest(t *testing.T) {
+	nodePubkey, err := hex.DecodeString(probeHopPubkey)
+	require.NoError(t, err)
+	feeRate := uint64(12)
+
+	req, err := buildOpenChannelRequest(nodePubkey, &lnclient.OpenChannelRequest{
+		Pubkey:         probeHopPubkey,
+		AmountLoki:     1_000_000,
+		FeeRate:        &feeRate,
+		PushAmountLoki: 10_000,
+		CloseAddress:   "fc1qcloseaddress",
+		MinHtlcMloki:   5_000,
+		ZeroConf:       true,
+		Outpoints:      []string{openChannelTxid + ":1"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, nodePubkey, req.NodePubkey)
+	assert.True(t, req.Private)
+	assert.Equal(t, int64(1_000_000), req.LocalFundingAmount)
+	assert.Equal(t, uint64(12), req.SatPerVbyte)
+	assert.Zero(t, req.TargetConf)
+	assert.Equal(t, int64(10_000), req.PushSat)
+	assert.Equal(t, "fc1qcloseaddress", req.CloseAddress)
+	assert.Equal(t, int64(5_000), req.MinHtlcMsat)
+	assert.True(t, req.ZeroConf)
+	assert.Equal(t, lnrpc.CommitmentType_ANCHORS, req.CommitmentType)
+	require.Len(t, req.Outpoints, 1)
+	assert.Equal(t, openChannelTxid, req.Outpoints[0].TxidStr)
+	assert.Equal(t, uint32(1), req.Outpoints[0].OutputIndex)
+}
+
+func TestBuildOpenChannelRequest_Defaults(t *testing.T) {
+	confTarget := uint32(6)
+	req, err := buildOpenChannelRequest(nil, &lnclient.OpenChannelRequest{AmountLoki: 1_000_000, Public: true, ConfTarget: &confTarget})
+	require.NoError(t, err)
+
+	assert.False(t, req.Private)
+	assert.Equal(t, int32(6), req.TargetConf)
+	assert.Equal(t, lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE, req.CommitmentType)
+	assert.Equal(t, uint64(defaultOpenChannelBaseFeeMloki), req.BaseFee)
+	assert.Empty(t, req.Outpoints)
+}
+
+func TestBuildOpenChannelRequest_InvalidOutpoint(t *testing.T) {
+	for _, outpoint := range []string{openChannelTxid, "abcd:0", openChannelTxid + ":x"} {
+		_, err := buildOpenChannelRequest(nil, &lnclient.OpenChannelRequest{AmountLoki: 1_000_000, Outpoints: []string{outpoint}})
+		assert.Error(t, err, outpoint)
+	}
+}
+
+func TestBuildBatchOpenChannelRequest(t *testing.T) {
+	confTarget := uint32(3)
+	req, err := buildBatchOpenChannelRequest(&lnclient.BatchOpenChannelRequest{
+		Channels: []lnclient.OpenChannelRequest{
+			{Pubkey: probeHopPubkey, AmountLoki: 1_000_000, Public: true},
+			{Pubkey: probeDestPubkey, AmountLoki: 2_000_000, ScidAlias: true, PushAmountLoki: 1_000},
+		},
+		ConfTarget: &confTarget,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(3), req.TargetConf)
+	require.Len(t, req.Channels, 2)
+	assert.Equal(t, probeHopPubkey, hex.EncodeToString(req.Channels[0].NodePubkey))
+	assert.False(t, req.Channels[0].Private)
+	assert.Equal(t, int64(2_000_000), req.Channels[1].LocalFundingAmount)
+	assert.True(t, req.Channels[1].Private)
+	assert.True(t, req.Channels[1].ScidAlias)
+	assert.Equal(t, int64(1_000), req.Channels[1].PushSat)
+	assert.Equal(t, lnrpc.CommitmentType_ANCHORS, req.Channels[1].CommitmentType)
+}
+
+func TestBuildBatchOpenChannelRequest_Invalid(t *testing.T) {
+	feeRate := uint64(5)
+	invalid := []*lnclient.BatchOpenChannelRequest{
+		{},
+		{Channels: []lnclient.OpenChannelRequest{{Pubkey: "not hex", AmountLoki: 1_000_000}}},
+		{Channels: []lnclient.OpenChannelRequest{{Pubkey: probeHopPubkey, AmountLoki: 1_000_000, FeeRate: &feeRate}}},
+		{Channels: []lnclient.OpenChannelRequest{{Pubkey: probeHopPubkey, AmountLoki: 1_000_000, Outpoints: []string{openChannelTxid + ":0"}}}},
+	}
+	for _, req := range invalid {
+		_, err := buildBatchOpenChannelRequest(req)
+		assert.Error(t, err)
+	}
+}
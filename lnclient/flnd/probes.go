@@ -0,0 +1,189 @@
+package flnd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/routerrpc"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+func (svc *FLNDService) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
+	payReq, err := svc.client.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("bolt11", invoice).Msg("Failed to decode bolt11 invoice")
+		return nil, err
+	}
+	if payReq.NumMsat <= 0 {
+		return nil, errors.New("cannot probe an invoice without an amount")
+	}
+
+	destBytes, err := hex.DecodeString(payReq.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	destFeatures := make([]lnrpc.FeatureBit, 0, len(payReq.Features))
+	for bit := range payReq.Features {
+		destFeatures = append(destFeatures, lnrpc.FeatureBit(bit)) //nolint:gosec // feature bits are small
+	}
+
+	return svc.sendProbe(ctx, &routerrpc.SendPaymentRequest{
+		Dest:           destBytes,
+		AmtMsat:        payReq.NumMsat,
+		FinalCltvDelta: int32(payReq.CltvExpiry), //nolint:gosec // cltv deltas are always far below int32 range
+		RouteHints:     payReq.RouteHints,
+		PaymentAddr:    payReq.PaymentAddr,
+		DestFeatures:   destFeatures,
+	})
+}
+
+func (svc *FLNDService) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
+	if amountMloki == 0 {
+		return nil, errors.New("cannot probe without an amount")
+	}
+
+	destBytes, err := hex.DecodeString(nodeId)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("node_id", nodeId).Msg("Failed to decode node id")
+		return nil, err
+	}
+
+	return svc.sendProbe(ctx, &routerrpc.SendPaymentRequest{
+		Dest:         destBytes,
+		AmtMsat:      int64(amountMloki), //nolint:gosec // msat amounts are always far below int64 range
+		DestFeatures: []lnrpc.FeatureBit{lnrpc.FeatureBit_TLV_ONION_REQ},
+	})
+}
+
+// sendProbe sends the payment with a random payment hash, which the
+// destination cannot know the preimage of, so the HTLCs can never settle.
+func (svc *FLNDService) sendProbe(ctx context.Context, sendRequest *routerrpc.SendPaymentRequest) (*lnclient.ProbeResult, error) {
+	// single-path, so every failed attempt points at exactly one channel
+	const MAX_PARTS = 1
+	const SEND_PROBE_TIMEOUT = 60
+
+	paymentHash := make([]byte, 32)
+	if _, err := rand.Read(paymentHash); err != nil {
+		return nil, err
+	}
+
+	sendRequest.PaymentHash = paymentHash
+	sendRequest.MaxParts = MAX_PARTS
+	sendRequest.TimeoutSeconds = SEND_PROBE_TIMEOUT
+	sendRequest.FeeLimitMsat = int64(transactions.CalculateFeeReserveMloki(uint64(sendRequest.AmtMsat))) //nolint:gosec // msat amounts are always far below int64/uint64 range
+
+	payStream, err := svc.client.SendPayment(ctx, sendRequest)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("dest", hex.EncodeToString(sendRequest.Dest)).Msg("Failed to send probe")
+		return nil, err
+	}
+
+	payment, err := svc.getPaymentResult(payStream)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("dest", hex.EncodeToString(sendRequest.Dest)).Msg("Couldn't get response from probe paystream")
+		return nil, err
+	}
+
+	result := buildProbeResult(payment, svc.GetPubkey())
+
+	logger.Logger.Info().
+		Str("dest", hex.EncodeToString(sendRequest.Dest)).
+		Int64("amount_mloki", sendRequest.AmtMsat).
+		Bool("success", result.Success).
+		Str("failure_reason", result.FailureReason).
+		Int("attempts", result.Attempts).
+		Msg("Probe finished")
+
+	return result, nil
+}
+
+// buildProbeResult turns the HTLC attempts of a probe into a route report.
+// A probe succeeds when the destination rejects it for an unknown payment
+// hash; any other failure location tells us which channel stopped it.
+func buildProbeResult(payment *lnrpc.Payment, ourPubkey string) *lnclient.ProbeResult {
+	result := &lnclient.ProbeResult{
+		Hops:     []lnclient.ProbeHop{},
+		Attempts: len(payment.Htlcs),
+	}
+
+	var reportedRoute *lnrpc.Route
+	bottlenecks := []*lnclient.ProbeBottleneck{}
+	for _, htlc := range payment.Htlcs {
+		if htlc.Route == nil || len(htlc.Route.Hops) == 0 {
+			continue
+		}
+		reportedRoute = htlc.Route
+
+		if htlc.Failure == nil {
+			continue
+		}
+		failureSourceIndex := int(htlc.Failure.FailureSourceIndex)
+		if htlc.Failure.Code == lnrpc.Failure_INCORRECT_OR_UNKNOWN_PAYMENT_DETAILS && failureSourceIndex == len(htlc.Route.Hops) {
+			result.Success = true
+			break
+		}
+		if htlc.Failure.Code != lnrpc.Failure_TEMPORARY_CHANNEL_FAILURE || failureSourceIndex >= len(htlc.Route.Hops) {
+			continue
+		}
+
+		// the node at failureSourceIndex couldn't forward over the channel to
+		// the next hop; index 0 is our own node.
+		hop := htlc.Route.Hops[failureSourceIndex]
+		fromNodeId := ourPubkey
+		if failureSourceIndex > 0 {
+			fromNodeId = htlc.Route.Hops[failureSourceIndex-1].PubKey
+		}
+		channelId := strconv.FormatUint(hop.ChanId, 10)
+
+		found := false
+		for _, bottleneck := range bottlenecks {
+			if bottleneck.ChannelId == channelId {
+				bottleneck.Failures++
+				found = true
+				break
+			}
+		}
+		if !found {
+			bottlenecks = append(bottlenecks, &lnclient.ProbeBottleneck{
+				ChannelId:   channelId,
+				FromNodeId:  fromNodeId,
+				ToNodeId:    hop.PubKey,
+				AmountMloki: uint64(hop.AmtToForwardMsat + hop.FeeMsat), //nolint:gosec // msat amounts are always far below int64/uint64 range
+				FailureCode: htlc.Failure.Code.String(),
+				Failures:    1,
+			})
+		}
+	}
+
+	if reportedRoute != nil {
+		result.FeeMloki = uint64(reportedRoute.TotalFeesMsat) //nolint:gosec // msat amounts are always far below int64/uint64 range
+		for _, hop := range reportedRoute.Hops {
+			result.Hops = append(result.Hops, lnclient.ProbeHop{
+				ChannelId:            strconv.FormatUint(hop.ChanId, 10),
+				NodeId:               hop.PubKey,
+				AmountToForwardMloki: uint64(hop.AmtToForwardMsat), //nolint:gosec // msat amounts are always far below int64/uint64 range
+				FeeMloki:             uint64(hop.FeeMsat),          //nolint:gosec // msat amounts are always far below int64/uint64 range
+				Expiry:               hop.Expiry,
+			})
+		}
+	}
+
+	if result.Success {
+		return result
+	}
+
+	result.FailureReason = payment.FailureReason.String()
+	for _, bottleneck := range bottlenecks {
+		if result.Bottleneck == nil || bottleneck.Failures > result.Bottleneck.Failures {
+			result.Bottleneck = bottleneck
+		}
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package flnd
+
+import (
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	probeOurPubkey  = "02aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	probeHopPubkey  = "02bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	probeDestPubkey = "02cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+)
+
+func probeRoute(firstChanId, secondChanId uint64) *lnrpc.Route {
+	return &lnrpc.Route{
+		TotalFeesMsat: 1_100,
+		TotalAmtMsat:  101_100,
+		Hops: []*lnrpc.Hop{
+			{ChanId: firstChanId, PubKey: probeHopPubkey, AmtToForwardMsat: 100_000, FeeMsat: 1_100, Expiry: 840},
+			{ChanId: secondChanId, PubKey: probeDestPubkey, AmtToForwardMsat: 100_000, FeeMsat: 0, Expiry: 800},
+		},
+	}
+}
+
+func TestBuildProbeResult_Success(t *testing.T) {
+	payment := &lnrpc.Payment{
+		Status:        lnrpc.Payment_FAILED,
+		FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_INCORRECT_PAYMENT_DETAILS,
+		Htlcs: []*lnrpc.HTLCAttempt{
+			{
+				Status:  lnrpc.HTLCAttempt_FAILED,
+				Route:   probeRoute(1, 2),
+				Failure: &lnrpc.Failure{Code: lnrpc.Failure_INCORRECT_OR_UNKNOWN_PAYMENT_DETAILS, FailureSourceIndex: 2},
+			},
+		},
+	}
+
+	result := buildProbeResult(payment, probeOurPubkey)
+
+	assert.True(t, result.Success)
+	assert.Empty(t, result.FailureReason)
+	assert.Nil(t, result.Bottleneck)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Equal(t, uint64(1_100), result.FeeMloki)
+	require.Len(t, result.Hops, 2)
+	assert.Equal(t, "1", result.Hops[0].ChannelId)
+	assert.Equal(t, probeHopPubkey, result.Hops[0].NodeId)
+	assert.Equal(t, uint64(100_000), result.Hops[0].AmountToForwardMloki)
+	assert.Equal(t, uint64(1_100), result.Hops[0].FeeMloki)
+	assert.Equal(t, uint32(840), result.Hops[0].Expiry)
+	assert.Equal(t, "2", result.Hops[1].ChannelId)
+	assert.Equal(t, probeDestPubkey, result.Hops[1].NodeId)
+}
+
+func TestBuildProbeResult_Bottleneck(t *testing.T) {
+	temporaryChannelFailure := func(sourceIndex uint32) *lnrpc.Failure {
+		return &lnrpc.Failure{Code: lnrpc.Failure_TEMPORARY_CHANNEL_FAILURE, FailureSourceIndex: sourceIndex}
+	}
+	payment := &lnrpc.Payment{
+		Status:        lnrpc.Payment_FAILED,
+		FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+		Htlcs: []*lnrpc.HTLCAttempt{
+			{Status: lnrpc.HTLCAttempt_FAILED, Route: probeRoute(1, 2), Failure: temporaryChannelFailure(1)},
+			{Status: lnrpc.HTLCAttempt_FAILED, Route: probeRoute(3, 2), Failure: temporaryChannelFailure(1)},
+			{Status: lnrpc.HTLCAttempt_FAILED, Route: probeRoute(4, 5), Failure: temporaryChannelFailure(0)},
+		},
+	}
+
+	result := buildProbeResult(payment, probeOurPubkey)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, "FAILURE_REASON_NO_ROUTE", result.FailureReason)
+	assert.Equal(t, 3, result.Attempts)
+	require.Len(t, result.Hops, 2)
+	assert.Equal(t, "4", result.Hops[0].ChannelId)
+
+	require.NotNil(t, result.Bottleneck)
+	assert.Equal(t, "2", result.Bottleneck.ChannelId)
+	assert.Equal(t, probeHopPubkey, result.Bottleneck.FromNodeId)
+	assert.Equal(t, probeDestPubkey, result.Bottleneck.ToNodeId)
+	assert.Equal(t, uint64(100_000), result.Bottleneck.AmountMloki)
+	assert.Equal(t, "TEMPORARY_CHANNEL_FAILURE", result.Bottleneck.FailureCode)
+	assert.Equal(t, 2, result.Bottleneck.Failures)
+}
+
+func TestBuildProbeResult_LocalBottleneck(t *testing.T) {
+	payment := &lnrpc.Payment{
+		Status:        lnrpc.Payment_FAILED,
+		FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+		Htlcs: []*lnrpc.HTLCAttempt{
+			{
+				Status:  lnrpc.HTLCAttempt_FAILED,
+				Route:   probeRoute(1, 2),
+				Failure: &lnrpc.Failure{Code: lnrpc.Failure_TEMPORARY_CHANNEL_FAILURE, FailureSourceIndex: 0},
+			},
+		},
+	}
+
+	result := buildProbeResult(payment, probeOurPubkey)
+
+	require.NotNil(t, result.Bottleneck)
+	assert.Equal(t, "1", result.Bottleneck.ChannelId)
+	assert.Equal(t, probeOurPubkey, result.Bottleneck.FromNodeId)
+	assert.Equal(t, probeHopPubkey, result.Bottleneck.ToNodeId)
+	assert.Equal(t, uint64(101_100), result.Bottleneck.AmountMloki)
+}
+
+func TestBuildProbeResult_NoRoute(t *testing.T) {
+	payment := &lnrpc.Payment{
+		Status:        lnrpc.Payment_FAILED,
+		FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+	}
+
+	result := buildProbeResult(payment, probeOurPubkey)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, "FAILURE_REASON_NO_ROUTE", result.FailureReason)
+	assert.Empty(t, result.Hops)
+	assert.Zero(t, result.Attempts)
+	assert.Nil(t, result.Bottleneck)
+}
@@ -0,0 +1,75 @@
+package flnd
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/routerrpc"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+func (svc *FLNDService) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	sendRequest, err := buildSendThroughChannelsRequest(payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+	if err != nil {
+		return nil, err
+	}
+
+	payStream, err := svc.client.SendPayment(ctx, sendRequest)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("bolt11", payReq).Msg("SendPayment failed")
+		return nil, err
+	}
+
+	resp, err := svc.getPaymentResult(payStream)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("bolt11", payReq).Msg("Couldn't get response from paystream")
+		return nil, err
+	}
+
+	if resp.Status != lnrpc.Payment_SUCCEEDED {
+		failureReasonMessage := resp.FailureReason.String()
+		logger.Logger.Error().
+			Str("bolt11", payReq).
+			Str("outgoing_channel_id", outgoingChannelId).
+			Str("last_hop_pubkey", lastHopPubkey).
+			Str("reason", failureReasonMessage).
+			Msg("Payment through channels not successful")
+		return nil, errors.New(failureReasonMessage)
+	}
+
+	return &lnclient.PayInvoiceResponse{
+		Preimage: resp.PaymentPreimage,
+		Fee:      uint64(resp.FeeMsat), //nolint:gosec // msat amounts are always far below int64/uint64 range
+	}, nil
+}
+
+// buildSendThroughChannelsRequest pins the first and last hop of the route.
+// Self payments must be allowed explicitly, and a single part keeps the whole
+// amount on the chosen channels.
+func buildSendThroughChannelsRequest(payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*routerrpc.SendPaymentRequest, error) {
+	const SEND_THROUGH_CHANNELS_TIMEOUT = 60
+
+	chanId, err := strconv.ParseUint(outgoingChannelId, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outgoing channel id %q: %w", outgoingChannelId, err)
+	}
+	lastHop, err := hex.DecodeString(lastHopPubkey)
+	if err != nil || len(lastHop) != 33 {
+		return nil, fmt.Errorf("invalid last hop pubkey %q", lastHopPubkey)
+	}
+
+	return &routerrpc.SendPaymentRequest{
+		PaymentRequest:   payReq,
+		OutgoingChanIds:  []uint64{chanId},
+		LastHopPubkey:    lastHop,
+		AllowSelfPayment: true,
+		MaxParts:         1,
+		TimeoutSeconds:   SEND_THROUGH_CHANNELS_TIMEOUT,
+		FeeLimitMsat:     int64(feeLimitMloki), //nolint:gosec // msat amounts are always far below int64 range
+	}, nil
+}
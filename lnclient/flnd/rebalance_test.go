@@ -0,0 +1,33 @@
+package flnd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSendThroughChannelsRequest(t *testing.T) {
+	req, err := buildSendThroughChannelsRequest("lnfc1invoice", "123456789", probeHopPubkey, 5_000)
+	require.NoError(t, err)
+
+	assert.Equal(t, "lnfc1invoice", req.PaymentRequest)
+	assert.Equal(t, []uint64{123456789}, req.OutgoingChanIds)
+	assert.Equal(t, probeHopPubkey, hex.EncodeToString(req.LastHopPubkey))
+	assert.True(t, req.AllowSelfPayment)
+	assert.Equal(t, uint32(1), req.MaxParts)
+	assert.Equal(t, int64(5_000), req.FeeLimitMsat)
+	assert.NotZero(t, req.TimeoutSeconds)
+}
+
+func TestBuildSendThroughChannelsRequest_Invalid(t *testing.T) {
+	_, err := buildSendThroughChannelsRequest("lnfc1invoice", "1x2x3", probeHopPubkey, 0)
+	assert.Error(t, err)
+
+	_, err = buildSendThroughChannelsRequest("lnfc1invoice", "1", "02abcd", 0)
+	assert.Error(t, err)
+
+	_, err = buildSendThroughChannelsRequest("lnfc1invoice", "1", "not hex", 0)
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/flokiorg/flnd/lnrpc/invoicesrpc"
 	"github.com/flokiorg/flnd/lnrpc/peersrpc"
 	"github.com/flokiorg/flnd/lnrpc/routerrpc"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
 	"github.com/flokiorg/flnd/macaroons"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -37,6 +38,7 @@ type FLNDWrapper struct {
 	stateClient    lnrpc.StateClient
 	invoicesClient invoicesrpc.InvoicesClient
 	peersClient    peersrpc.PeersClient
+	walletClient   walletrpc.WalletKitClient
 	IdentityPubkey string
 	conn           *grpc.ClientConn
 }
@@ -107,6 +109,7 @@ func NewFLNDclient(flndOptions FLNDoptions) (result *FLNDWrapper, err error) {
 		stateClient:    lnrpc.NewStateClient(conn),
 		invoicesClient: invoicesrpc.NewInvoicesClient(conn),
 		peersClient:    peersrpc.NewPeersClient(conn),
+		walletClient:   walletrpc.NewWalletKitClient(conn),
 		conn:           conn,
 	}, nil
 }
@@ -131,6 +134,10 @@ func (wrapper *FLNDWrapper) SendPayment(ctx context.Context, req *routerrpc.Send
 	return wrapper.routerClient.SendPaymentV2(ctx, req, options...)
 }
 
+func (wrapper *FLNDWrapper) DecodePayReq(ctx context.Context, req *lnrpc.PayReqString, options ...grpc.CallOption) (*lnrpc.PayReq, error) {
+	return wrapper.client.DecodePayReq(ctx, req, options...)
+}
+
 func (wrapper *FLNDWrapper) ChannelBalance(ctx context.Context, req *lnrpc.ChannelBalanceRequest, options ...grpc.CallOption) (*lnrpc.ChannelBalanceResponse, error) {
 	return wrapper.client.ChannelBalance(ctx, req, options...)
 }
@@ -227,6 +234,10 @@ func (wrapper *FLNDWrapper) OpenChannelSync(ctx context.Context, req *lnrpc.Open
 	return wrapper.client.OpenChannelSync(ctx, req, options...)
 }
 
+func (wrapper *FLNDWrapper) BatchOpenChannel(ctx context.Context, req *lnrpc.BatchOpenChannelRequest, options ...grpc.CallOption) (*lnrpc.BatchOpenChannelResponse, error) {
+	return wrapper.client.BatchOpenChannel(ctx, req, options...)
+}
+
 func (wrapper *FLNDWrapper) CloseChannel(ctx context.Context, req *lnrpc.CloseChannelRequest, options ...grpc.CallOption) (lnrpc.Lightning_CloseChannelClient, error) {
 	return wrapper.client.CloseChannel(ctx, req, options...)
 }
@@ -274,3 +285,55 @@ func (wrapper *FLNDWrapper) ChannelAcceptor(ctx context.Context, options ...grpc
 func (wrapper *FLNDWrapper) UpdateNodeAnnouncement(ctx context.Context, req *peersrpc.NodeAnnouncementUpdateRequest, options ...grpc.CallOption) (*peersrpc.NodeAnnouncementUpdateResponse, error) {
 	return wrapper.peersClient.UpdateNodeAnnouncement(ctx, req, options...)
 }
+
+func (wrapper *FLNDWrapper) QueryRoutes(ctx context.Context, req *lnrpc.QueryRoutesRequest, options ...grpc.CallOption) (*lnrpc.QueryRoutesResponse, error) {
+	return wrapper.client.QueryRoutes(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) ListUnspent(ctx context.Context, req *lnrpc.ListUnspentRequest, options ...grpc.CallOption) (*lnrpc.ListUnspentResponse, error) {
+	return wrapper.client.ListUnspent(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) ClosedChannels(ctx context.Context, req *lnrpc.ClosedChannelsRequest, options ...grpc.CallOption) (*lnrpc.ClosedChannelsResponse, error) {
+	return wrapper.client.ClosedChannels(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) BumpFee(ctx context.Context, req *walletrpc.BumpFeeRequest, options ...grpc.CallOption) (*walletrpc.BumpFeeResponse, error) {
+	return wrapper.walletClient.BumpFee(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) ListLeases(ctx context.Context, req *walletrpc.ListLeasesRequest, options ...grpc.CallOption) (*walletrpc.ListLeasesResponse, error) {
+	return wrapper.walletClient.ListLeases(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) LeaseOutput(ctx context.Context, req *walletrpc.LeaseOutputRequest, options ...grpc.CallOption) (*walletrpc.LeaseOutputResponse, error) {
+	return wrapper.walletClient.LeaseOutput(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) ReleaseOutput(ctx context.Context, req *walletrpc.ReleaseOutputRequest, options ...grpc.CallOption) (*walletrpc.ReleaseOutputResponse, error) {
+	return wrapper.walletClient.ReleaseOutput(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) FundPsbt(ctx context.Context, req *walletrpc.FundPsbtRequest, options ...grpc.CallOption) (*walletrpc.FundPsbtResponse, error) {
+	return wrapper.walletClient.FundPsbt(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) FinalizePsbt(ctx context.Context, req *walletrpc.FinalizePsbtRequest, options ...grpc.CallOption) (*walletrpc.FinalizePsbtResponse, error) {
+	return wrapper.walletClient.FinalizePsbt(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) PublishTransaction(ctx context.Context, req *walletrpc.Transaction, options ...grpc.CallOption) (*walletrpc.PublishResponse, error) {
+	return wrapper.walletClient.PublishTransaction(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) GetTransaction(ctx context.Context, req *walletrpc.GetTransactionRequest, options ...grpc.CallOption) (*lnrpc.Transaction, error) {
+	return wrapper.walletClient.GetTransaction(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) PendingSweeps(ctx context.Context, req *walletrpc.PendingSweepsRequest, options ...grpc.CallOption) (*walletrpc.PendingSweepsResponse, error) {
+	return wrapper.walletClient.PendingSweeps(ctx, req, options...)
+}
+
+func (wrapper *FLNDWrapper) ListSweeps(ctx context.Context, req *walletrpc.ListSweepsRequest, options ...grpc.CallOption) (*walletrpc.ListSweepsResponse, error) {
+	return wrapper.walletClient.ListSweeps(ctx, req, options...)
+}
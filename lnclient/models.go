@@ -3,6 +3,7 @@ package lnclient
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 // TODO: remove JSON tags from these models (LNClient models should not be exposed directly)
@@ -64,8 +65,19 @@ type CustomMessage struct {
 }
 
 type LNClient interface {
-	SendPaymentSync(payReq string, amount *uint64) (*PayInvoiceResponse, error)
-	SendKeysend(amount uint64, destination string, customRecords []TLVRecord, preimage string) (*PayKeysendResponse, error)
+	// SendPaymentSync and SendKeysend spend at most feeLimitMloki on routing
+	// fees; callers pass the fee reserve held for the payment.
+	SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*PayInvoiceResponse, error)
+	SendKeysend(amount uint64, destination string, customRecords []TLVRecord, preimage string, feeLimitMloki uint64) (*PayKeysendResponse, error)
+	// SendPaymentThroughChannels pays the invoice in a single part leaving
+	// through outgoingChannelId and reaching the destination from
+	// lastHopPubkey. Paying one of our own invoices this way moves liquidity
+	// between two of our channels.
+	SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*PayInvoiceResponse, error)
+	// PayOfferSync fetches an invoice for a BOLT12 offer and pays it. amount is
+	// in mloki and is required for offers that don't fix their own amount.
+	PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*PayOfferResponse, error)
+	MakeOffer(ctx context.Context, description string) (string, error)
 	GetPubkey() string
 	GetInfo(ctx context.Context) (info *NodeInfo, err error)
 	MakeInvoice(ctx context.Context, amount int64, description string, descriptionHash string, expiry int64, throughNodePubkey *string, lspJitChannelSCID *string, lspCltvExpiryDelta *uint16, lspFeeBaseMloki *uint64, lspFeeProportionalMillionths *uint32) (transaction *Transaction, err error)
@@ -75,12 +87,16 @@ type LNClient interface {
 	LookupInvoice(ctx context.Context, paymentHash string) (transaction *Transaction, err error)
 	ListTransactions(ctx context.Context, from, until, limit, offset uint64, unpaid bool, invoiceType string) (transactions []Transaction, err error)
 	ListOnchainTransactions(ctx context.Context, from, until, limit, offset uint64) ([]OnchainTransaction, error)
+	// ListForwards returns up to limit forwarding events after the given
+	// index in the node's forwarding log, oldest first.
+	ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]ForwardingEvent, error)
 	Shutdown() error
 	ListChannels(ctx context.Context) (channels []Channel, err error)
 	GetNodeConnectionInfo(ctx context.Context) (*NodeConnectionInfo, error)
 	GetNodeStatus(ctx context.Context) (nodeStatus *NodeStatus, err error)
 	ConnectPeer(ctx context.Context, connectPeerRequest *ConnectPeerRequest) error
 	OpenChannel(ctx context.Context, openChannelRequest *OpenChannelRequest) (*OpenChannelResponse, error)
+	BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error)
 	CloseChannel(ctx context.Context, closeChannelRequest *CloseChannelRequest) (*CloseChannelResponse, error)
 	UpdateChannel(ctx context.Context, updateChannelRequest *UpdateChannelRequest) error
 	DisconnectPeer(ctx context.Context, peerId string) error
@@ -90,8 +106,23 @@ type LNClient interface {
 	GetOnchainBalance(ctx context.Context) (*OnchainBalanceResponse, error)
 	GetBalances(ctx context.Context, includeInactiveChannels bool) (*BalancesResponse, error)
 	RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (txId string, err error)
-	SendPaymentProbes(ctx context.Context, invoice string) error
-	SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error
+	// SendOnchainFundsFromUtxos is RedeemOnchainFunds spending only the
+	// given "txid:vout" outpoints
+	SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (txId string, err error)
+	ListUtxos(ctx context.Context) ([]Utxo, error)
+	// FreezeUtxo keeps an output from being spent until UnfreezeUtxo is called
+	FreezeUtxo(ctx context.Context, outpoint string) error
+	UnfreezeUtxo(ctx context.Context, outpoint string) error
+	CreatePsbt(ctx context.Context, createPsbtRequest *CreatePsbtRequest) (*CreatePsbtResponse, error)
+	FinalizePsbt(ctx context.Context, psbt string) (*FinalizePsbtResponse, error)
+	PublishTransaction(ctx context.Context, rawTx string, label string) (txId string, err error)
+	// BumpFee speeds up an unconfirmed transaction, replacing it if it is a
+	// sweep and spending one of its outputs in a child transaction otherwise
+	BumpFee(ctx context.Context, bumpFeeRequest *BumpFeeRequest) (*BumpFeeResponse, error)
+	// SendPaymentProbes and SendSpontaneousPaymentProbes send HTLCs that can
+	// never settle and report how far they got along the route.
+	SendPaymentProbes(ctx context.Context, invoice string) (*ProbeResult, error)
+	SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*ProbeResult, error)
 	ListPeers(ctx context.Context) ([]PeerDetails, error)
 	GetLogOutput(ctx context.Context, maxLen int) ([]byte, error)
 	SignMessage(ctx context.Context, message string) (string, error)
@@ -106,8 +137,9 @@ type LNClient interface {
 	SendCustomMessage(ctx context.Context, peerPubkey string, msgType uint32, data []byte) error
 	SubscribeCustomMessages(ctx context.Context) (<-chan CustomMessage, <-chan error, error)
 
-	// Channel Acceptor
-	SubscribeChannelAcceptor(ctx context.Context) (<-chan ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error)
+	// Channel Acceptor. A rejectReason given when rejecting a channel is
+	// sent to the peer.
+	SubscribeChannelAcceptor(ctx context.Context) (<-chan ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error)
 
 	SetNodeAlias(ctx context.Context, alias string) error
 }
@@ -116,6 +148,8 @@ type ChannelAcceptRequest struct {
 	ID         string
 	NodePubkey string
 	Capacity   uint64
+	// Private is set when the opener does not want the channel announced.
+	Private bool
 }
 
 type Channel struct {
@@ -154,12 +188,39 @@ type OpenChannelRequest struct {
 	Pubkey     string `json:"pubkey"`
 	AmountLoki int64  `json:"amountLoki"`
 	Public     bool   `json:"public"`
+	// FeeRate is the funding transaction fee rate in loki/vbyte. Only one of
+	// FeeRate and ConfTarget may be set; without either the node picks the fee.
+	FeeRate        *uint64 `json:"feeRate,omitempty"`
+	ConfTarget     *uint32 `json:"confTarget,omitempty"`
+	PushAmountLoki uint64  `json:"pushAmountLoki,omitempty"`
+	// CloseAddress is where our balance goes on a cooperative close
+	CloseAddress string `json:"closeAddress,omitempty"`
+	MinHtlcMloki uint64 `json:"minHtlcMloki,omitempty"`
+	ZeroConf     bool   `json:"zeroConf,omitempty"`
+	ScidAlias    bool   `json:"scidAlias,omitempty"`
+	// Outpoints restricts the funding inputs to the given "txid:vout" UTXOs
+	Outpoints []string `json:"outpoints,omitempty"`
 }
 
 type OpenChannelResponse struct {
 	FundingTxId string `json:"fundingTxId"`
 }
 
+// BatchOpenChannelRequest opens several channels in a single funding
+// transaction. The fee options apply to the whole transaction, so they must
+// not be set on the individual channels, and neither may outpoints.
+type BatchOpenChannelRequest struct {
+	Channels   []OpenChannelRequest `json:"channels"`
+	FeeRate    *uint64              `json:"feeRate,omitempty"`
+	ConfTarget *uint32              `json:"confTarget,omitempty"`
+}
+
+type BatchOpenChannelResponse struct {
+	FundingTxId string `json:"fundingTxId"`
+	// FundingTxVouts are the funding outputs of the channels, in request order
+	FundingTxVouts []uint32 `json:"fundingTxVouts"`
+}
+
 type CloseChannelRequest struct {
 	ChannelId string `json:"channelId"`
 	NodeId    string `json:"nodeId"`
@@ -195,6 +256,71 @@ type OnchainBalanceResponse struct {
 	InternalBalances                   interface{}             `json:"internalBalances"`
 }
 
+type Utxo struct {
+	Outpoint      string `json:"outpoint"`
+	Address       string `json:"address"`
+	AmountLoki    int64  `json:"amountLoki"`
+	Confirmations int64  `json:"confirmations"`
+	Frozen        bool   `json:"frozen"`
+}
+
+type PsbtOutput struct {
+	Address    string `json:"address"`
+	AmountLoki uint64 `json:"amountLoki"`
+}
+
+// CreatePsbtRequest describes an unsigned transaction for the wallet to
+// fund. Without outpoints the wallet selects the inputs itself. Only one of
+// FeeRate (loki/vbyte) and ConfTarget may be set.
+type CreatePsbtRequest struct {
+	Outputs    []PsbtOutput `json:"outputs"`
+	Outpoints  []string     `json:"outpoints,omitempty"`
+	FeeRate    *uint64      `json:"feeRate,omitempty"`
+	ConfTarget *uint32      `json:"confTarget,omitempty"`
+}
+
+type CreatePsbtResponse struct {
+	// Psbt is base64 encoded
+	Psbt string `json:"psbt"`
+	// ChangeOutputIndex is -1 if the transaction has no change output
+	ChangeOutputIndex int32 `json:"changeOutputIndex"`
+	// LockedUtxos are the inputs reserved for this transaction
+	LockedUtxos []string `json:"lockedUtxos"`
+}
+
+type FinalizePsbtResponse struct {
+	// SignedPsbt is base64 encoded
+	SignedPsbt string `json:"signedPsbt"`
+	// RawTx is the hex encoded transaction, ready to publish
+	RawTx string `json:"rawTx"`
+	TxId  string `json:"txId"`
+}
+
+const (
+	BUMP_FEE_METHOD_RBF  = "rbf"
+	BUMP_FEE_METHOD_CPFP = "cpfp"
+)
+
+// BumpFeeRequest identifies the transaction to bump either by TxId or by
+// one of its "txid:vout" outpoints. FeeRate is in loki/vbyte.
+type BumpFeeRequest struct {
+	TxId     string `json:"txId,omitempty"`
+	Outpoint string `json:"outpoint,omitempty"`
+	FeeRate  uint64 `json:"feeRate"`
+}
+
+type BumpFeeResponse struct {
+	// Method is BUMP_FEE_METHOD_RBF or BUMP_FEE_METHOD_CPFP
+	Method string `json:"method"`
+	// Outpoint is the input handed to the sweeper
+	Outpoint string `json:"outpoint"`
+	// TxId is the replacement or child transaction. FeeLoki is the fee of the
+	// replacement, or for CPFP the package fee of the parent and the child.
+	// Both are empty if it was not broadcast yet when the call returned.
+	TxId    string `json:"txId"`
+	FeeLoki int64  `json:"feeLoki"`
+}
+
 type PeerDetails struct {
 	NodeId      string `json:"nodeId"`
 	Address     string `json:"address"`
@@ -225,6 +351,36 @@ type PayKeysendResponse struct {
 	Fee uint64 `json:"fee"`
 }
 
+type ProbeHop struct {
+	ChannelId            string `json:"channelId"`
+	NodeId               string `json:"nodeId"`
+	AmountToForwardMloki uint64 `json:"amountToForwardMloki"`
+	FeeMloki             uint64 `json:"feeMloki"`
+	Expiry               uint32 `json:"expiry"`
+}
+
+// ProbeBottleneck is the channel that failed the most probe attempts, i.e.
+// the most likely place a real payment of the same amount would get stuck.
+type ProbeBottleneck struct {
+	ChannelId   string `json:"channelId"`
+	FromNodeId  string `json:"fromNodeId"`
+	ToNodeId    string `json:"toNodeId"`
+	AmountMloki uint64 `json:"amountMloki"`
+	FailureCode string `json:"failureCode"`
+	Failures    int    `json:"failures"`
+}
+
+type ProbeResult struct {
+	// Success means a probe reached the destination, which rejected it only
+	// because it didn't know the (random) payment hash.
+	Success       bool             `json:"success"`
+	FailureReason string           `json:"failureReason,omitempty"`
+	FeeMloki      uint64           `json:"feeMloki"`
+	Hops          []ProbeHop       `json:"hops"`
+	Attempts      int              `json:"attempts"`
+	Bottleneck    *ProbeBottleneck `json:"bottleneck,omitempty"`
+}
+
 type BalancesResponse struct {
 	Onchain   OnchainBalanceResponse   `json:"onchain"`
 	Lightning LightningBalanceResponse `json:"lightning"`
@@ -240,11 +396,41 @@ type PaymentFailedEventProperties struct {
 type PaymentForwardedEventProperties struct {
 	TotalFeeEarnedMloki          uint64
 	OutboundAmountForwardedMloki uint64
-}
+	InboundAmountMloki           uint64
+	IncomingChannelId            string
+	OutgoingChannelId            string
+}
+
+// ForwardingEvent is a single HTLC forwarded by the node. Index is the
+// event's position in the node's forwarding log, starting at 1.
+type ForwardingEvent struct {
+	Index              uint64
+	IncomingChannelId  string
+	OutgoingChannelId  string
+	IncomingPeerPubkey string
+	OutgoingPeerPubkey string
+	AmountInMloki      uint64
+	AmountOutMloki     uint64
+	FeeMloki           uint64
+	Timestamp          time.Time
+}
+
+// Types of custom node command arguments. Values are always passed to the
+// node as strings; the type tells clients what to send and lets the hub
+// reject malformed values before the node is called.
+const (
+	CustomNodeCommandArgTypeString   = "string"
+	CustomNodeCommandArgTypeInteger  = "integer"
+	CustomNodeCommandArgTypeBoolean  = "boolean"
+	CustomNodeCommandArgTypeOutpoint = "outpoint"
+)
 
 type CustomNodeCommandArgDef struct {
 	Name        string
 	Description string
+	// Type is one of the CustomNodeCommandArgType constants, string if empty
+	Type     string
+	Required bool
 }
 
 type CustomNodeCommandDef struct {
@@ -275,6 +461,14 @@ func NewCustomNodeCommandResponseEmpty() *CustomNodeCommandResponse {
 
 var ErrUnknownCustomNodeCommand = errors.New("unknown custom node command")
 
+// ErrOffersNotSupported is returned by LNClient implementations whose
+// underlying node has no BOLT12 support.
+var ErrOffersNotSupported = errors.New("BOLT12 offers are not supported by this node")
+
+// ErrTransactionNotBumpable is returned by BumpFee for transactions whose fee
+// can be raised neither by replacement nor by a child transaction.
+var ErrTransactionNotBumpable = errors.New("transaction cannot be bumped")
+
 // default invoice expiry in seconds (1 day)
 const DEFAULT_INVOICE_EXPIRY = 86400
 
@@ -0,0 +1,360 @@
+// Package lnurl serves LNURL-pay (LUD-06) endpoints for lightning addresses
+// (LUD-16) owned by individual apps. Invoices are created through the
+// transactions service under the owning app's ID, so payments to an isolated
+// app's address are credited to that app's balance. Comments (LUD-12), payer
+// data (LUD-18) and NIP-57 zap requests are supported; zap receipts are
+// published by ZapReceiptPublisher once the invoice is paid.
+//
+// This package knows nothing about HTTP — the http package maps its
+// responses and errors onto the LNURL wire format.
+package lnurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nbd-wtf/go-nostr"
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/keys"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+const (
+	// MinSendableMloki and MaxSendableMloki bound the amounts advertised in
+	// the pay request. 1 loki is the smallest amount an invoice can carry.
+	MinSendableMloki = 1_000
+	MaxSendableMloki = 100_000_000_000
+
+	// CommentAllowed is the maximum LUD-12 comment length.
+	CommentAllowed = 255
+
+	zapRequestKind = 9734
+)
+
+var (
+	ErrUnknownUsername   = errors.New("unknown lightning address")
+	ErrNodeNotRunning    = errors.New("node is not running")
+	ErrInvalidUsername   = errors.New("username may only contain a-z, 0-9, '-', '_' and '.'")
+	ErrUsernameTaken     = errors.New("username is already taken")
+	ErrAmountOutOfRange  = fmt.Errorf("amount must be between %d and %d mloki", MinSendableMloki, MaxSendableMloki)
+	ErrCommentTooLong    = fmt.Errorf("comment must be at most %d characters", CommentAllowed)
+	ErrInvalidPayerData  = errors.New("invalid payer data")
+	ErrInvalidZapRequest = errors.New("invalid zap request")
+	ErrAppHasNoAddress   = errors.New("app has no lightning address")
+	ErrAppHasAddress     = errors.New("app already has a lightning address")
+)
+
+// usernameRegex is the character set LUD-16 allows for the local part of an
+// internet identifier.
+var usernameRegex = regexp.MustCompile(`^[a-z0-9\-_.]+$`)
+
+// PayParams is the LUD-06 first-step response, with the LUD-12, LUD-18 and
+// NIP-57 extensions.
+type PayParams struct {
+	Tag            string     `json:"tag"`
+	Callback       string     `json:"callback"`
+	MinSendable    uint64     `json:"minSendable"`
+	MaxSendable    uint64     `json:"maxSendable"`
+	Metadata       string     `json:"metadata"`
+	CommentAllowed int        `json:"commentAllowed"`
+	PayerData      *PayerData `json:"payerData,omitempty"`
+	AllowsNostr    bool       `json:"allowsNostr,omitempty"`
+	NostrPubkey    string     `json:"nostrPubkey,omitempty"`
+}
+
+// PayerData advertises which LUD-18 payer fields are accepted.
+type PayerData struct {
+	Name       *PayerDataField `json:"name,omitempty"`
+	Pubkey     *PayerDataField `json:"pubkey,omitempty"`
+	Identifier *PayerDataField `json:"identifier,omitempty"`
+	Email      *PayerDataField `json:"email,omitempty"`
+}
+
+type PayerDataField struct {
+	Mandatory bool `json:"mandatory"`
+}
+
+// InvoiceRequest holds the query parameters of the LUD-06 callback.
+type InvoiceRequest struct {
+	AmountMloki uint64
+	Comment     string
+	// PayerData is the raw LUD-18 JSON object, kept verbatim because it is
+	// part of the description hash preimage.
+	PayerData string
+	// ZapRequest is the raw NIP-57 kind 9734 event JSON, kept verbatim for
+	// the same reason.
+	ZapRequest string
+}
+
+// InvoiceResponse is the LUD-06 second-step response.
+type InvoiceResponse struct {
+	Pr     string        `json:"pr"`
+	Routes []interface{} `json:"routes"`
+}
+
+type LNURLService interface {
+	CreateLightningAddress(appId uint, username string) (*db.LightningAddress, error)
+	DeleteLightningAddress(appId uint) error
+	ListLightningAddresses() ([]db.LightningAddress, error)
+	GetPayParams(username string, baseUrl string) (*PayParams, error)
+	MakeInvoice(ctx context.Context, username string, baseUrl string, request *InvoiceRequest) (*InvoiceResponse, error)
+}
+
+type lnurlService struct {
+	db                  *gorm.DB
+	keys                keys.Keys
+	transactionsService transactions.TransactionsService
+	getLNClient         func() lnclient.LNClient
+}
+
+func NewLNURLService(gormDB *gorm.DB, keys keys.Keys, transactionsService transactions.TransactionsService, getLNClient func() lnclient.LNClient) *lnurlService {
+	return &lnurlService{
+		db:                  gormDB,
+		keys:                keys,
+		transactionsService: transactionsService,
+		getLNClient:         getLNClient,
+	}
+}
+
+func (svc *lnurlService) CreateLightningAddress(appId uint, username string) (*db.LightningAddress, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if !usernameRegex.MatchString(username) {
+		return nil, ErrInvalidUsername
+	}
+
+	var app db.App
+	if err := svc.db.First(&app, appId).Error; err != nil {
+		return nil, err
+	}
+
+	lightningAddress := db.LightningAddress{
+		AppId:    app.ID,
+		Username: username,
+	}
+	err := svc.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&db.LightningAddress{}).Where("username = ?", username).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrUsernameTaken
+		}
+		if err := tx.Model(&db.LightningAddress{}).Where("app_id = ?", app.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAppHasAddress
+		}
+		return tx.Create(&lightningAddress).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info().
+		Uint("app_id", app.ID).
+		Str("username", username).
+		Msg("Created lightning address")
+	return &lightningAddress, nil
+}
+
+func (svc *lnurlService) DeleteLightningAddress(appId uint) error {
+	result := svc.db.Where("app_id = ?", appId).Delete(&db.LightningAddress{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAppHasNoAddress
+	}
+	return nil
+}
+
+func (svc *lnurlService) ListLightningAddresses() ([]db.LightningAddress, error) {
+	lightningAddresses := []db.LightningAddress{}
+	if err := svc.db.Order("username").Find(&lightningAddresses).Error; err != nil {
+		return nil, err
+	}
+	return lightningAddresses, nil
+}
+
+func (svc *lnurlService) GetPayParams(username string, baseUrl string) (*PayParams, error) {
+	lightningAddress, err := svc.findLightningAddress(username)
+	if err != nil {
+		return nil, err
+	}
+
+	payParams := &PayParams{
+		Tag:            "payRequest",
+		Callback:       callbackUrl(baseUrl, lightningAddress.Username),
+		MinSendable:    MinSendableMloki,
+		MaxSendable:    MaxSendableMloki,
+		Metadata:       encodeMetadata(lightningAddress.Username, baseUrl),
+		CommentAllowed: CommentAllowed,
+		PayerData: &PayerData{
+			Name:       &PayerDataField{},
+			Pubkey:     &PayerDataField{},
+			Identifier: &PayerDataField{},
+			Email:      &PayerDataField{},
+		},
+	}
+
+	// zap receipts are signed with the hub's nostr key, which is only
+	// available once the hub is unlocked
+	if nostrPubkey := svc.keys.GetNostrPublicKey(); nostrPubkey != "" {
+		payParams.AllowsNostr = true
+		payParams.NostrPubkey = nostrPubkey
+	}
+
+	return payParams, nil
+}
+
+func (svc *lnurlService) MakeInvoice(ctx context.Context, username string, baseUrl string, request *InvoiceRequest) (*InvoiceResponse, error) {
+	lightningAddress, err := svc.findLightningAddress(username)
+	if err != nil {
+		return nil, err
+	}
+
+	lnClient := svc.getLNClient()
+	if lnClient == nil {
+		return nil, ErrNodeNotRunning
+	}
+
+	if request.AmountMloki < MinSendableMloki || request.AmountMloki > MaxSendableMloki {
+		return nil, ErrAmountOutOfRange
+	}
+	if len([]rune(request.Comment)) > CommentAllowed {
+		return nil, ErrCommentTooLong
+	}
+
+	metadata := map[string]interface{}{}
+	if request.Comment != "" {
+		metadata["comment"] = request.Comment
+	}
+
+	// LUD-06: the description hash commits to the metadata string. LUD-18
+	// appends the payer data, and NIP-57 replaces both with the zap request.
+	descriptionHashPreimage := encodeMetadata(lightningAddress.Username, baseUrl)
+	if request.PayerData != "" {
+		var payerData map[string]interface{}
+		if err := json.Unmarshal([]byte(request.PayerData), &payerData); err != nil {
+			return nil, ErrInvalidPayerData
+		}
+		metadata["payer_data"] = payerData
+		descriptionHashPreimage += request.PayerData
+	}
+
+	var zapRequest *nostr.Event
+	if request.ZapRequest != "" {
+		zapRequest, err = parseZapRequest(request.ZapRequest, request.AmountMloki)
+		if err != nil {
+			return nil, err
+		}
+		metadata["nostr"] = zapRequest
+		descriptionHashPreimage = request.ZapRequest
+	}
+
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+
+	descriptionHash := sha256.Sum256([]byte(descriptionHashPreimage))
+	transaction, err := svc.transactionsService.MakeInvoice(ctx, request.AmountMloki, "", hex.EncodeToString(descriptionHash[:]), 0, metadata, lnClient, &lightningAddress.AppId, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		logger.Logger.Error().Err(err).
+			Str("username", lightningAddress.Username).
+			Uint64("amount", request.AmountMloki).
+			Msg("Failed to make LNURL-pay invoice")
+		return nil, err
+	}
+
+	if zapRequest != nil {
+		err = svc.db.Create(&db.ZapRequest{
+			TransactionId: transaction.ID,
+			Request:       request.ZapRequest,
+		}).Error
+		if err != nil {
+			logger.Logger.Error().Err(err).
+				Uint("transaction_id", transaction.ID).
+				Msg("Failed to save zap request")
+			return nil, err
+		}
+	}
+
+	return &InvoiceResponse{
+		Pr:     transaction.PaymentRequest,
+		Routes: []interface{}{},
+	}, nil
+}
+
+func (svc *lnurlService) findLightningAddress(username string) (*db.LightningAddress, error) {
+	var lightningAddress db.LightningAddress
+	result := svc.db.Limit(1).Find(&lightningAddress, &db.LightningAddress{
+		Username: strings.ToLower(username),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrUnknownUsername
+	}
+	return &lightningAddress, nil
+}
+
+// parseZapRequest validates a zap request as described in NIP-57 appendix D.
+func parseZapRequest(rawZapRequest string, amountMloki uint64) (*nostr.Event, error) {
+	var zapRequest nostr.Event
+	if err := json.Unmarshal([]byte(rawZapRequest), &zapRequest); err != nil {
+		return nil, ErrInvalidZapRequest
+	}
+	if zapRequest.Kind != zapRequestKind {
+		return nil, fmt.Errorf("%w: wrong kind", ErrInvalidZapRequest)
+	}
+	if ok, err := zapRequest.CheckSignature(); err != nil || !ok {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidZapRequest)
+	}
+	if len(zapRequest.Tags.GetAll([]string{"p"})) != 1 {
+		return nil, fmt.Errorf("%w: must have exactly one p tag", ErrInvalidZapRequest)
+	}
+	if len(zapRequest.Tags.GetAll([]string{"e"})) > 1 {
+		return nil, fmt.Errorf("%w: must have at most one e tag", ErrInvalidZapRequest)
+	}
+	if amountTag := zapRequest.Tags.GetFirst([]string{"amount"}); amountTag != nil && amountTag.Value() != fmt.Sprintf("%d", amountMloki) {
+		return nil, fmt.Errorf("%w: amount does not match", ErrInvalidZapRequest)
+	}
+	return &zapRequest, nil
+}
+
+func callbackUrl(baseUrl string, username string) string {
+	return fmt.Sprintf("%s/lnurlp/%s/callback", strings.TrimSuffix(baseUrl, "/"), username)
+}
+
+// encodeMetadata returns the LUD-06 metadata string. It must be identical in
+// both steps of the protocol because its hash is committed to by the invoice.
+func encodeMetadata(username string, baseUrl string) string {
+	identifier := Address(username, baseUrl)
+	metadata, _ := json.Marshal([][]string{
+		{"text/plain", fmt.Sprintf("Payment to %s", identifier)},
+		{"text/identifier", identifier},
+	})
+	return string(metadata)
+}
+
+// Address returns the lightning address for username served from baseUrl.
+func Address(username string, baseUrl string) string {
+	host := baseUrl
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	host = strings.TrimSuffix(host, "/")
+	return fmt.Sprintf("%s@%s", username, host)
+}
@@ -0,0 +1,242 @@
+package lnurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+const testBaseUrl = "https://hub.example.com"
+
+func newTestLNURLService(svc *tests.TestService) *lnurlService {
+	transactionsService := transactions.NewTransactionsService(svc.DB, svc.EventPublisher)
+	return NewLNURLService(svc.DB, svc.Keys, transactionsService, func() lnclient.LNClient { return svc.LNClient })
+}
+
+func createZapRequest(t *testing.T, amountMloki string) string {
+	senderKey := nostr.GeneratePrivateKey()
+	senderPubkey, err := nostr.GetPublicKey(senderKey)
+	require.NoError(t, err)
+
+	zapRequest := nostr.Event{
+		PubKey:    senderPubkey,
+		CreatedAt: nostr.Now(),
+		Kind:      zapRequestKind,
+		Tags: nostr.Tags{
+			{"p", "04c915daefee38317fa734444acee390a8269fe5810b2241e5e6dd343dfbecc9"},
+			{"e", "9ae37aa68f48645127299e9453eb5d908a0cbb6058ff340d528ed4d37c8994fb"},
+			{"relays", "wss://relay.example.com"},
+			{"amount", amountMloki},
+		},
+		Content: "great post",
+	}
+	require.NoError(t, zapRequest.Sign(senderKey))
+
+	zapRequestJson, err := json.Marshal(zapRequest)
+	require.NoError(t, err)
+	return string(zapRequestJson)
+}
+
+func TestCreateLightningAddress(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	otherApp, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	lnurlSvc := newTestLNURLService(svc)
+
+	lightningAddress, err := lnurlSvc.CreateLightningAddress(app.ID, " Alice ")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", lightningAddress.Username)
+	assert.Equal(t, app.ID, lightningAddress.AppId)
+
+	_, err = lnurlSvc.CreateLightningAddress(otherApp.ID, "alice")
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+
+	_, err = lnurlSvc.CreateLightningAddress(app.ID, "bob")
+	assert.ErrorIs(t, err, ErrAppHasAddress)
+
+	_, err = lnurlSvc.CreateLightningAddress(otherApp.ID, "bob smith")
+	assert.ErrorIs(t, err, ErrInvalidUsername)
+
+	lightningAddresses, err := lnurlSvc.ListLightningAddresses()
+	require.NoError(t, err)
+	require.Len(t, lightningAddresses, 1)
+
+	require.NoError(t, lnurlSvc.DeleteLightningAddress(app.ID))
+	assert.ErrorIs(t, lnurlSvc.DeleteLightningAddress(app.ID), ErrAppHasNoAddress)
+}
+
+func TestGetPayParams(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	lnurlSvc := newTestLNURLService(svc)
+	_, err = lnurlSvc.CreateLightningAddress(app.ID, "alice")
+	require.NoError(t, err)
+
+	payParams, err := lnurlSvc.GetPayParams("ALICE", testBaseUrl)
+	require.NoError(t, err)
+	assert.Equal(t, "payRequest", payParams.Tag)
+	assert.Equal(t, "https://hub.example.com/lnurlp/alice/callback", payParams.Callback)
+	assert.Equal(t, `[["text/plain","Payment to alice@hub.example.com"],["text/identifier","alice@hub.example.com"]]`, payParams.Metadata)
+	assert.Equal(t, CommentAllowed, payParams.CommentAllowed)
+	assert.NotNil(t, payParams.PayerData)
+	assert.True(t, payParams.AllowsNostr)
+	assert.Equal(t, svc.Keys.GetNostrPublicKey(), payParams.NostrPubkey)
+
+	_, err = lnurlSvc.GetPayParams("bob", testBaseUrl)
+	assert.ErrorIs(t, err, ErrUnknownUsername)
+}
+
+func TestMakeInvoice_CreditsApp(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	app.Kind = db.AppKindIsolated
+	require.NoError(t, svc.DB.Save(app).Error)
+
+	lnurlSvc := newTestLNURLService(svc)
+	_, err = lnurlSvc.CreateLightningAddress(app.ID, "alice")
+	require.NoError(t, err)
+
+	payerData := `{"name":"Bob"}`
+	invoice, err := lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{
+		AmountMloki: 123000,
+		Comment:     "thanks!",
+		PayerData:   payerData,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, tests.MockLNClientTransaction.Invoice, invoice.Pr)
+	assert.Empty(t, invoice.Routes)
+
+	var transaction db.Transaction
+	require.NoError(t, svc.DB.First(&transaction, &db.Transaction{PaymentRequest: invoice.Pr}).Error)
+	assert.Equal(t, app.ID, *transaction.AppId)
+	assert.Equal(t, constants.TRANSACTION_TYPE_INCOMING, transaction.Type)
+
+	expectedHash := sha256.Sum256([]byte(encodeMetadata("alice", testBaseUrl) + payerData))
+	assert.Equal(t, hex.EncodeToString(expectedHash[:]), transaction.DescriptionHash)
+
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(transaction.Metadata, &metadata))
+	assert.Equal(t, "thanks!", metadata["comment"])
+	assert.Equal(t, "Bob", metadata["payer_data"].(map[string]interface{})["name"])
+}
+
+func TestMakeInvoice_Validation(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	lnurlSvc := newTestLNURLService(svc)
+	_, err = lnurlSvc.CreateLightningAddress(app.ID, "alice")
+	require.NoError(t, err)
+
+	_, err = lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{AmountMloki: 999})
+	assert.ErrorIs(t, err, ErrAmountOutOfRange)
+
+	_, err = lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{
+		AmountMloki: 1000,
+		Comment:     strings.Repeat("a", CommentAllowed+1),
+	})
+	assert.ErrorIs(t, err, ErrCommentTooLong)
+
+	_, err = lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{
+		AmountMloki: 1000,
+		PayerData:   "not json",
+	})
+	assert.ErrorIs(t, err, ErrInvalidPayerData)
+
+	_, err = lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{
+		AmountMloki: 1000,
+		ZapRequest:  createZapRequest(t, "2000"),
+	})
+	assert.ErrorIs(t, err, ErrInvalidZapRequest)
+
+	_, err = lnurlSvc.MakeInvoice(context.TODO(), "bob", testBaseUrl, &InvoiceRequest{AmountMloki: 1000})
+	assert.ErrorIs(t, err, ErrUnknownUsername)
+
+	var count int64
+	svc.DB.Model(&db.Transaction{}).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestMakeInvoice_NodeNotRunning(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	transactionsService := transactions.NewTransactionsService(svc.DB, svc.EventPublisher)
+	lnurlSvc := NewLNURLService(svc.DB, svc.Keys, transactionsService, func() lnclient.LNClient { return nil })
+	_, err = lnurlSvc.CreateLightningAddress(app.ID, "alice")
+	require.NoError(t, err)
+
+	_, err = lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{AmountMloki: 1000})
+	assert.ErrorIs(t, err, ErrNodeNotRunning)
+}
+
+func TestMakeInvoice_ZapRequest(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	lnurlSvc := newTestLNURLService(svc)
+	_, err = lnurlSvc.CreateLightningAddress(app.ID, "alice")
+	require.NoError(t, err)
+
+	zapRequest := createZapRequest(t, "21000")
+	invoice, err := lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{
+		AmountMloki: 21000,
+		ZapRequest:  zapRequest,
+	})
+	require.NoError(t, err)
+
+	var transaction db.Transaction
+	require.NoError(t, svc.DB.First(&transaction, &db.Transaction{PaymentRequest: invoice.Pr}).Error)
+	expectedHash := sha256.Sum256([]byte(zapRequest))
+	assert.Equal(t, hex.EncodeToString(expectedHash[:]), transaction.DescriptionHash)
+
+	var savedZapRequest db.ZapRequest
+	require.NoError(t, svc.DB.First(&savedZapRequest, &db.ZapRequest{TransactionId: transaction.ID}).Error)
+	assert.Equal(t, zapRequest, savedZapRequest.Request)
+	assert.Empty(t, savedZapRequest.ReceiptEventId)
+}
+
+func TestAddress(t *testing.T) {
+	assert.Equal(t, "alice@hub.example.com", Address("alice", "https://hub.example.com/"))
+	assert.Equal(t, "alice@localhost:1610", Address("alice", "http://localhost:1610"))
+}
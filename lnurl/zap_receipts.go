@@ -0,0 +1,134 @@
+package lnurl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/keys"
+	"github.com/flokiorg/lokihub/logger"
+	nostrmodels "github.com/flokiorg/lokihub/nostr/models"
+)
+
+const zapReceiptKind = 9735
+
+// ZapReceiptPublisher publishes a NIP-57 zap receipt when an invoice created
+// for a zap request is paid. Receipts only go to the general relays: the
+// relays listed in a zap request are chosen by the payer, and connecting to
+// them would let anyone make the hub open connections to arbitrary hosts.
+type ZapReceiptPublisher struct {
+	events.EventSubscriber
+	db   *gorm.DB
+	cfg  config.Config
+	keys keys.Keys
+	pool nostrmodels.SimplePool
+}
+
+func NewZapReceiptPublisher(gormDB *gorm.DB, cfg config.Config, keys keys.Keys, pool nostrmodels.SimplePool) *ZapReceiptPublisher {
+	return &ZapReceiptPublisher{
+		db:   gormDB,
+		cfg:  cfg,
+		keys: keys,
+		pool: pool,
+	}
+}
+
+func (publisher *ZapReceiptPublisher) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
+	if event.Event != "nwc_payment_received" {
+		return
+	}
+
+	transaction, ok := event.Properties.(*db.Transaction)
+	if !ok {
+		logger.Logger.Error().Interface("event", event).Msg("Failed to cast event.Properties to transaction")
+		return
+	}
+
+	var zapRequest db.ZapRequest
+	result := publisher.db.Limit(1).Find(&zapRequest, &db.ZapRequest{TransactionId: transaction.ID})
+	if result.Error != nil {
+		logger.Logger.Error().Err(result.Error).Uint("transaction_id", transaction.ID).Msg("Failed to lookup zap request")
+		return
+	}
+	if result.RowsAffected == 0 || zapRequest.ReceiptEventId != "" {
+		return
+	}
+
+	if err := publisher.publishReceipt(ctx, transaction, &zapRequest); err != nil {
+		logger.Logger.Error().Err(err).Uint("transaction_id", transaction.ID).Msg("Failed to publish zap receipt")
+	}
+}
+
+func (publisher *ZapReceiptPublisher) publishReceipt(ctx context.Context, transaction *db.Transaction, zapRequest *db.ZapRequest) error {
+	var zapRequestEvent nostr.Event
+	if err := json.Unmarshal([]byte(zapRequest.Request), &zapRequestEvent); err != nil {
+		return err
+	}
+
+	receipt, err := publisher.buildReceipt(transaction, zapRequest.Request, &zapRequestEvent)
+	if err != nil {
+		return err
+	}
+
+	publishSuccessful := false
+	for result := range publisher.pool.PublishMany(ctx, publisher.cfg.GetGeneralRelayUrls(), *receipt) {
+		if result.Error == nil {
+			publishSuccessful = true
+		} else {
+			logger.Logger.Warn().Err(result.Error).
+				Str("relay", result.RelayURL).
+				Str("event_id", receipt.ID).
+				Msg("Failed to publish zap receipt to relay")
+		}
+	}
+	if !publishSuccessful {
+		return errors.New("zap receipt was not accepted by any relay")
+	}
+
+	logger.Logger.Info().
+		Uint("transaction_id", transaction.ID).
+		Str("event_id", receipt.ID).
+		Msg("Published zap receipt")
+
+	return publisher.db.Model(zapRequest).Update("receipt_event_id", receipt.ID).Error
+}
+
+func (publisher *ZapReceiptPublisher) buildReceipt(transaction *db.Transaction, rawZapRequest string, zapRequestEvent *nostr.Event) (*nostr.Event, error) {
+	tags := nostr.Tags{}
+	for _, tagName := range []string{"p", "e", "a"} {
+		if tag := zapRequestEvent.Tags.GetFirst([]string{tagName}); tag != nil {
+			tags = append(tags, nostr.Tag{tagName, tag.Value()})
+		}
+	}
+	tags = append(tags,
+		nostr.Tag{"P", zapRequestEvent.PubKey},
+		nostr.Tag{"bolt11", transaction.PaymentRequest},
+		nostr.Tag{"description", rawZapRequest},
+	)
+	if transaction.Preimage != nil {
+		tags = append(tags, nostr.Tag{"preimage", *transaction.Preimage})
+	}
+
+	createdAt := time.Now()
+	if transaction.SettledAt != nil {
+		createdAt = *transaction.SettledAt
+	}
+
+	receipt := &nostr.Event{
+		PubKey:    publisher.keys.GetNostrPublicKey(),
+		CreatedAt: nostr.Timestamp(createdAt.Unix()),
+		Kind:      zapReceiptKind,
+		Tags:      tags,
+	}
+	if err := receipt.Sign(publisher.keys.GetNostrSecretKey()); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
@@ -0,0 +1,101 @@
+package lnurl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func TestZapReceiptPublisher_PublishesReceipt(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	lnurlSvc := newTestLNURLService(svc)
+	_, err = lnurlSvc.CreateLightningAddress(app.ID, "alice")
+	require.NoError(t, err)
+
+	zapRequest := createZapRequest(t, "21000")
+	invoice, err := lnurlSvc.MakeInvoice(context.TODO(), "alice", testBaseUrl, &InvoiceRequest{
+		AmountMloki: 21000,
+		ZapRequest:  zapRequest,
+	})
+	require.NoError(t, err)
+
+	var transaction db.Transaction
+	require.NoError(t, svc.DB.First(&transaction, &db.Transaction{PaymentRequest: invoice.Pr}).Error)
+	preimage := "c8aeb44ae8eb269c8dbfb7ec5c263f0bfa3d755bc0ca641b8ee118673afda657"
+	settledAt := time.Unix(1700000000, 0)
+	transaction.State = constants.TRANSACTION_STATE_SETTLED
+	transaction.Preimage = &preimage
+	transaction.SettledAt = &settledAt
+	require.NoError(t, svc.DB.Save(&transaction).Error)
+
+	pool := tests.NewMockSimplePool()
+	publisher := NewZapReceiptPublisher(svc.DB, svc.Cfg, svc.Keys, pool)
+	publisher.ConsumeEvent(context.TODO(), &events.Event{
+		Event:      "nwc_payment_received",
+		Properties: &transaction,
+	}, map[string]interface{}{})
+
+	require.Len(t, pool.PublishedEvents, 1)
+	// the relays tag of the zap request is chosen by the payer and ignored
+	assert.Equal(t, svc.Cfg.GetGeneralRelayUrls(), pool.PublishedRelayUrls[0])
+	receipt := pool.PublishedEvents[0]
+	assert.Equal(t, zapReceiptKind, receipt.Kind)
+	assert.Equal(t, svc.Keys.GetNostrPublicKey(), receipt.PubKey)
+	assert.Equal(t, nostr.Timestamp(settledAt.Unix()), receipt.CreatedAt)
+	ok, err := receipt.CheckSignature()
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, "04c915daefee38317fa734444acee390a8269fe5810b2241e5e6dd343dfbecc9", receipt.Tags.GetFirst([]string{"p"}).Value())
+	assert.Equal(t, "9ae37aa68f48645127299e9453eb5d908a0cbb6058ff340d528ed4d37c8994fb", receipt.Tags.GetFirst([]string{"e"}).Value())
+	assert.Equal(t, invoice.Pr, receipt.Tags.GetFirst([]string{"bolt11"}).Value())
+	assert.Equal(t, zapRequest, receipt.Tags.GetFirst([]string{"description"}).Value())
+	assert.Equal(t, preimage, receipt.Tags.GetFirst([]string{"preimage"}).Value())
+
+	var savedZapRequest db.ZapRequest
+	require.NoError(t, svc.DB.First(&savedZapRequest, &db.ZapRequest{TransactionId: transaction.ID}).Error)
+	assert.Equal(t, receipt.ID, savedZapRequest.ReceiptEventId)
+
+	// a receipt is only ever published once
+	publisher.ConsumeEvent(context.TODO(), &events.Event{
+		Event:      "nwc_payment_received",
+		Properties: &transaction,
+	}, map[string]interface{}{})
+	assert.Len(t, pool.PublishedEvents, 1)
+}
+
+func TestZapReceiptPublisher_IgnoresPaymentsWithoutZapRequest(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	transaction := db.Transaction{
+		Type:  constants.TRANSACTION_TYPE_INCOMING,
+		State: constants.TRANSACTION_STATE_SETTLED,
+	}
+	require.NoError(t, svc.DB.Create(&transaction).Error)
+
+	pool := tests.NewMockSimplePool()
+	publisher := NewZapReceiptPublisher(svc.DB, svc.Cfg, svc.Keys, pool)
+	publisher.ConsumeEvent(context.TODO(), &events.Event{
+		Event:      "nwc_payment_received",
+		Properties: &transaction,
+	}, map[string]interface{}{})
+
+	assert.Empty(t, pool.PublishedEvents)
+}
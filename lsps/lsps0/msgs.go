@@ -15,6 +15,14 @@ const (
 	MethodGetInfo       = "lsps0.get_info"
 )
 
+// JSON-RPC 2.0 error codes shared by all protocols
+const (
+	ErrorCodeParseError     = -32700
+	ErrorCodeMethodNotFound = -32601
+	ErrorCodeInvalidParams  = -32602
+	ErrorCodeInternalError  = -32603
+)
+
 // JsonRpcRequest represents a JSON-RPC 2.0 request
 type JsonRpcRequest struct {
 	Jsonrpc string      `json:"jsonrpc"`
@@ -82,3 +90,15 @@ func DecodeJsonRpcResponse(data []byte) (*JsonRpcResponse, error) {
 	}
 	return &resp, nil
 }
+
+// DecodeParams decodes the params of a JSON-RPC request into v
+func DecodeParams(req *JsonRpcRequest, v interface{}) error {
+	if req.Params == nil {
+		return nil
+	}
+	data, err := json.Marshal(req.Params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -5,17 +5,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/flokiorg/lokihub/lsps/events"
 	"github.com/flokiorg/lokihub/lsps/transport"
 )
 
-// ServiceHandler handles LSPS0 service-side operations (LSP perspective)
+// ProtocolHandler answers the requests of one LSPS protocol on behalf of the
+// ServiceHandler. It returns the result to send back, or the JSON-RPC error.
+type ProtocolHandler interface {
+	HandleRequest(ctx context.Context, peerPubkey string, req *JsonRpcRequest) (interface{}, *JsonRpcError)
+}
+
+// ServiceHandler handles LSPS0 service-side operations (LSP perspective) and
+// routes requests of other protocols to their registered handlers
 type ServiceHandler struct {
 	transport          transport.Transport
 	eventQueue         *events.EventQueue
 	supportedProtocols []int
+	protocolHandlers   map[int]ProtocolHandler
 	mu                 sync.RWMutex
 }
 
@@ -28,7 +37,7 @@ type ServiceConfig struct {
 func NewServiceHandler(transport transport.Transport, eventQueue *events.EventQueue, config *ServiceConfig) *ServiceHandler {
 	if config == nil {
 		config = &ServiceConfig{
-			SupportedProtocols: []int{0, 1, 2}, // Default: LSPS0, LSPS1, LSPS2
+			SupportedProtocols: []int{0}, // Other protocols are added as their handlers are registered
 		}
 	}
 
@@ -36,9 +45,25 @@ func NewServiceHandler(transport transport.Transport, eventQueue *events.EventQu
 		transport:          transport,
 		eventQueue:         eventQueue,
 		supportedProtocols: config.SupportedProtocols,
+		protocolHandlers:   make(map[int]ProtocolHandler),
 	}
 }
 
+// RegisterProtocolHandler routes requests for methods of the given protocol
+// (e.g. "lsps1.*" for protocol 1) to handler and advertises the protocol
+func (h *ServiceHandler) RegisterProtocolHandler(protocol int, handler ProtocolHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.protocolHandlers[protocol] = handler
+	for _, supported := range h.supportedProtocols {
+		if supported == protocol {
+			return
+		}
+	}
+	h.supportedProtocols = append(h.supportedProtocols, protocol)
+}
+
 // SetSupportedProtocols updates the list of supported protocols
 func (h *ServiceHandler) SetSupportedProtocols(protocols []int) {
 	h.mu.Lock()
@@ -50,15 +75,44 @@ func (h *ServiceHandler) SetSupportedProtocols(protocols []int) {
 func (h *ServiceHandler) HandleMessage(ctx context.Context, peerPubkey string, data []byte) error {
 	req, err := DecodeJsonRpcRequest(data)
 	if err != nil {
-		return h.sendError(ctx, peerPubkey, "", -32700, "Parse error", err)
+		return h.sendError(ctx, peerPubkey, "", ErrorCodeParseError, "Parse error", err)
+	}
+	if req.Method == "" {
+		// responses to our own requests are handled by the client handlers
+		return fmt.Errorf("not a request: %s", req.ID)
 	}
 
-	switch req.Method {
-	case MethodListProtocols:
+	if req.Method == MethodListProtocols {
 		return h.handleListProtocols(ctx, peerPubkey, req)
-	default:
-		return h.sendError(ctx, peerPubkey, req.ID, -32601, "Method not found", nil)
 	}
+
+	if handler := h.protocolHandler(req.Method); handler != nil {
+		result, rpcError := handler.HandleRequest(ctx, peerPubkey, req)
+		if rpcError != nil {
+			return h.sendError(ctx, peerPubkey, req.ID, rpcError.Code, rpcError.Message, rpcError.Data)
+		}
+		return h.sendResponse(ctx, peerPubkey, &JsonRpcResponse{
+			Jsonrpc: "2.0",
+			Result:  result,
+			ID:      req.ID,
+		})
+	}
+
+	return h.sendError(ctx, peerPubkey, req.ID, ErrorCodeMethodNotFound, "Method not found", nil)
+}
+
+// protocolHandler returns the handler registered for the protocol of method,
+// or nil if there is none
+func (h *ServiceHandler) protocolHandler(method string) ProtocolHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for protocol, handler := range h.protocolHandlers {
+		if strings.HasPrefix(method, fmt.Sprintf("lsps%d.", protocol)) {
+			return handler
+		}
+	}
+	return nil
 }
 
 // handleListProtocols handles the list_protocols request
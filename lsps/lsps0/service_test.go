@@ -0,0 +1,120 @@
+package lsps0
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/flokiorg/lokihub/lsps/events"
+	"github.com/flokiorg/lokihub/lsps/transport"
+)
+
+type echoProtocolHandler struct{}
+
+func (h *echoProtocolHandler) HandleRequest(ctx context.Context, peerPubkey string, req *JsonRpcRequest) (interface{}, *JsonRpcError) {
+	if req.Method != "lsps1.echo" {
+		return nil, &JsonRpcError{Code: ErrorCodeMethodNotFound, Message: "Method not found"}
+	}
+	var params map[string]string
+	if err := DecodeParams(req, &params); err != nil {
+		return nil, &JsonRpcError{Code: ErrorCodeInvalidParams, Message: "Invalid params"}
+	}
+	return map[string]string{"peer": peerPubkey, "value": params["value"]}, nil
+}
+
+// startServiceHandler answers requests arriving on serviceTransport until the
+// test ends
+func startServiceHandler(t *testing.T, serviceTransport transport.Transport, service *ServiceHandler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	msgs, _, err := serviceTransport.SubscribeCustomMessages(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeCustomMessages failed: %v", err)
+	}
+	go func() {
+		for {
+			select {
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				_ = service.HandleMessage(ctx, msg.PeerPubkey, msg.Data)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func nextResponse(t *testing.T, msgs <-chan transport.CustomMessage) *JsonRpcResponse {
+	select {
+	case msg := <-msgs:
+		resp, err := DecodeJsonRpcResponse(msg.Data)
+		if err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for response")
+		return nil
+	}
+}
+
+func TestServiceHandler_RoutesProtocols(t *testing.T) {
+	clientTransport, serviceTransport := transport.NewMemoryTransportPair("client", "lsp")
+	service := NewServiceHandler(serviceTransport, events.NewEventQueue(10), nil)
+	service.RegisterProtocolHandler(1, &echoProtocolHandler{})
+	startServiceHandler(t, serviceTransport, service)
+
+	ctx := context.Background()
+	clientMsgs, _, _ := clientTransport.SubscribeCustomMessages(ctx)
+	send := func(method string, params interface{}) *JsonRpcResponse {
+		data, _ := json.Marshal(&JsonRpcRequest{Jsonrpc: "2.0", Method: method, Params: params, ID: method})
+		if err := clientTransport.SendCustomMessage(ctx, "lsp", LSPS_MESSAGE_TYPE_ID, data); err != nil {
+			t.Fatalf("SendCustomMessage failed: %v", err)
+		}
+		return nextResponse(t, clientMsgs)
+	}
+
+	// only protocols with a handler are advertised
+	resp := send(MethodListProtocols, &ListProtocolsRequest{})
+	result, _ := json.Marshal(resp.Result)
+	if string(result) != `{"protocols":[0,1]}` {
+		t.Errorf("Unexpected protocols: %s", result)
+	}
+
+	resp = send("lsps1.echo", map[string]string{"value": "hello"})
+	result, _ = json.Marshal(resp.Result)
+	if resp.ID != "lsps1.echo" || string(result) != `{"peer":"client","value":"hello"}` {
+		t.Errorf("Unexpected response: %s %s", resp.ID, result)
+	}
+
+	resp = send("lsps1.unknown", nil)
+	if resp.Error == nil || resp.Error.Code != ErrorCodeMethodNotFound {
+		t.Errorf("Expected method not found, got %+v", resp.Error)
+	}
+
+	resp = send("lsps2.get_info", nil)
+	if resp.Error == nil || resp.Error.Code != ErrorCodeMethodNotFound {
+		t.Errorf("Expected method not found for unregistered protocol, got %+v", resp.Error)
+	}
+}
+
+func TestServiceHandler_IgnoresResponses(t *testing.T) {
+	clientTransport, serviceTransport := transport.NewMemoryTransportPair("client", "lsp")
+	service := NewServiceHandler(serviceTransport, events.NewEventQueue(10), nil)
+
+	data, _ := json.Marshal(&JsonRpcResponse{Jsonrpc: "2.0", Result: map[string]string{}, ID: "1"})
+	if err := service.HandleMessage(context.Background(), "client", data); err == nil {
+		t.Error("Expected responses not to be handled")
+	}
+
+	msgs, _, _ := clientTransport.SubscribeCustomMessages(context.Background())
+	select {
+	case msg := <-msgs:
+		t.Errorf("Unexpected reply to a response: %s", msg.Data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
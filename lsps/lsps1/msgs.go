@@ -10,6 +10,29 @@ const (
 	MethodGetOrder    = "lsps1.get_order"
 )
 
+// LSPS1 error codes
+const (
+	ErrorCodeOptionMismatch = 100
+	ErrorCodeOrderNotFound  = 101
+)
+
+// Order states
+const (
+	OrderStateCreated   = "CREATED"
+	OrderStateCompleted = "COMPLETED"
+	OrderStateFailed    = "FAILED"
+	// OrderStateOpening is only persisted by the service while the channel
+	// of a paid order is being opened. Clients see it as CREATED.
+	OrderStateOpening = "OPENING"
+)
+
+// Payment states
+const (
+	PaymentStateExpectPayment = "EXPECT_PAYMENT"
+	PaymentStatePaid          = "PAID"
+	PaymentStateRefunded      = "REFUNDED"
+)
+
 // GetInfoRequest requests supported options from LSP
 type GetInfoRequest struct{}
 
@@ -0,0 +1,377 @@
+package lsps1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/lsps/lsps0"
+	"github.com/flokiorg/lokihub/lsps/persist"
+)
+
+// blockInterval is Flokicoin's target block interval, used to turn the
+// channel expiry in blocks into a time
+const blockInterval = time.Minute
+
+// ServiceNode is the part of the Lightning node the LSPS1 service uses to
+// take payment for orders and open the channels
+type ServiceNode interface {
+	MakeInvoice(ctx context.Context, amount int64, description string, descriptionHash string, expiry int64, throughNodePubkey *string, lspJitChannelSCID *string, lspCltvExpiryDelta *uint16, lspFeeBaseMloki *uint64, lspFeeProportionalMillionths *uint32) (*lnclient.Transaction, error)
+	LookupInvoice(ctx context.Context, paymentHash string) (*lnclient.Transaction, error)
+	OpenChannel(ctx context.Context, openChannelRequest *lnclient.OpenChannelRequest) (*lnclient.OpenChannelResponse, error)
+	ListChannels(ctx context.Context) ([]lnclient.Channel, error)
+}
+
+// OrderStore persists the orders placed with the LSPS1 service
+type OrderStore interface {
+	CreateLSPS1Order(order *persist.LSPS1ServiceOrder) error
+	GetLSPS1Order(clientPubkey, orderID string) (*persist.LSPS1ServiceOrder, error)
+	UpdateLSPS1Order(order *persist.LSPS1ServiceOrder) error
+	ListLSPS1OrdersByState(state string) ([]persist.LSPS1ServiceOrder, error)
+}
+
+// ServiceConfig is the channel and fee policy of the LSPS1 service. Balances
+// and fees are in loki. Clients cannot ask for a client balance, since
+// pushing funds on channel open is not supported.
+type ServiceConfig struct {
+	MinChannelBalanceLoki           uint64 `json:"minChannelBalanceLoki"`
+	MaxChannelBalanceLoki           uint64 `json:"maxChannelBalanceLoki"`
+	MaxChannelExpiryBlocks          uint32 `json:"maxChannelExpiryBlocks"`
+	MinRequiredChannelConfirmations uint16 `json:"minRequiredChannelConfirmations"`
+	MinFundingConfirmsWithinBlocks  uint16 `json:"minFundingConfirmsWithinBlocks"`
+	FeeBaseLoki                     uint64 `json:"feeBaseLoki"`
+	FeePPM                          uint32 `json:"feePpm"` // proportional to the channel balance
+	PaymentExpirySeconds            uint32 `json:"paymentExpirySeconds"`
+}
+
+// DefaultServiceConfig returns the policy used until one is configured
+func DefaultServiceConfig() ServiceConfig {
+	return ServiceConfig{
+		MinChannelBalanceLoki:           100_000,
+		MaxChannelBalanceLoki:           16_777_215,
+		MaxChannelExpiryBlocks:          129_600, // 90 days
+		MinRequiredChannelConfirmations: 3,
+		MinFundingConfirmsWithinBlocks:  6,
+		FeeBaseLoki:                     1_000,
+		FeePPM:                          10_000,
+		PaymentExpirySeconds:            3600,
+	}
+}
+
+// ServiceHandler answers LSPS1 requests, selling channels to other nodes. An
+// order is paid with a Lightning invoice and the channel is opened once the
+// invoice is settled, either when the client checks the order or when
+// ProcessPendingOrders runs.
+type ServiceHandler struct {
+	node   ServiceNode
+	store  OrderStore
+	config ServiceConfig
+	// mu serializes order processing so a paid order opens one channel
+	mu sync.Mutex
+}
+
+// NewServiceHandler creates a new LSPS1 service handler
+func NewServiceHandler(node ServiceNode, store OrderStore, config ServiceConfig) *ServiceHandler {
+	return &ServiceHandler{
+		node:   node,
+		store:  store,
+		config: config,
+	}
+}
+
+// HandleRequest implements lsps0.ProtocolHandler
+func (h *ServiceHandler) HandleRequest(ctx context.Context, peerPubkey string, req *lsps0.JsonRpcRequest) (interface{}, *lsps0.JsonRpcError) {
+	switch req.Method {
+	case MethodGetInfo:
+		return &GetInfoResponse{Options: h.options()}, nil
+	case MethodCreateOrder:
+		var params CreateOrderRequest
+		if err := lsps0.DecodeParams(req, &params); err != nil {
+			return nil, &lsps0.JsonRpcError{Code: lsps0.ErrorCodeInvalidParams, Message: "Invalid params", Data: err.Error()}
+		}
+		return h.createOrder(ctx, peerPubkey, &params)
+	case MethodGetOrder:
+		var params GetOrderRequest
+		if err := lsps0.DecodeParams(req, &params); err != nil {
+			return nil, &lsps0.JsonRpcError{Code: lsps0.ErrorCodeInvalidParams, Message: "Invalid params", Data: err.Error()}
+		}
+		return h.getOrder(ctx, peerPubkey, &params)
+	default:
+		return nil, &lsps0.JsonRpcError{Code: lsps0.ErrorCodeMethodNotFound, Message: "Method not found"}
+	}
+}
+
+func (h *ServiceHandler) options() Options {
+	return Options{
+		MinRequiredChannelConfirmations: h.config.MinRequiredChannelConfirmations,
+		MinFundingConfirmsWithinBlocks:  h.config.MinFundingConfirmsWithinBlocks,
+		SupportsZeroChannelReserve:      false,
+		MaxChannelExpiryBlocks:          h.config.MaxChannelExpiryBlocks,
+		MinInitialClientBalanceLoki:     0,
+		MaxInitialClientBalanceLoki:     0,
+		MinInitialLspBalanceLoki:        h.config.MinChannelBalanceLoki,
+		MaxInitialLspBalanceLoki:        h.config.MaxChannelBalanceLoki,
+		MinChannelBalanceLoki:           h.config.MinChannelBalanceLoki,
+		MaxChannelBalanceLoki:           h.config.MaxChannelBalanceLoki,
+	}
+}
+
+// feeLoki returns the fee for a channel with the given LSP balance
+func (h *ServiceHandler) feeLoki(lspBalanceLoki uint64) uint64 {
+	proportional := (lspBalanceLoki*uint64(h.config.FeePPM) + 999_999) / 1_000_000
+	return h.config.FeeBaseLoki + proportional
+}
+
+// validateOrder checks the order against the service options and returns the
+// mismatching property, if any
+func (h *ServiceHandler) validateOrder(order *OrderParams) *lsps0.JsonRpcError {
+	mismatch := func(property string) *lsps0.JsonRpcError {
+		return &lsps0.JsonRpcError{
+			Code:    ErrorCodeOptionMismatch,
+			Message: "Option mismatch",
+			Data:    map[string]interface{}{"property": property},
+		}
+	}
+
+	if order.ClientBalanceLoki > 0 {
+		return mismatch("client_balance_loki")
+	}
+	if order.LspBalanceLoki < h.config.MinChannelBalanceLoki || order.LspBalanceLoki > h.config.MaxChannelBalanceLoki {
+		return mismatch("lsp_balance_loki")
+	}
+	if order.RequiredChannelConfirmations < h.config.MinRequiredChannelConfirmations {
+		return mismatch("required_channel_confirmations")
+	}
+	if order.FundingConfirmsWithinBlocks < h.config.MinFundingConfirmsWithinBlocks {
+		return mismatch("funding_confirms_within_blocks")
+	}
+	if order.ChannelExpiryBlocks > h.config.MaxChannelExpiryBlocks {
+		return mismatch("channel_expiry_blocks")
+	}
+	return nil
+}
+
+func (h *ServiceHandler) createOrder(ctx context.Context, peerPubkey string, req *CreateOrderRequest) (interface{}, *lsps0.JsonRpcError) {
+	if rpcError := h.validateOrder(&req.OrderParams); rpcError != nil {
+		return nil, rpcError
+	}
+
+	orderID := generateRequestID()
+	feeTotal := h.feeLoki(req.LspBalanceLoki)
+	invoice, err := h.node.MakeInvoice(ctx, int64(feeTotal*1000), fmt.Sprintf("LSPS1 channel order %s", orderID), "", int64(h.config.PaymentExpirySeconds), nil, nil, nil, nil, nil) //nolint:gosec // fees are far below int64 range
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("order_id", orderID).Msg("Failed to create LSPS1 order invoice")
+		return nil, &lsps0.JsonRpcError{Code: lsps0.ErrorCodeInternalError, Message: "Internal error"}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	order := &persist.LSPS1ServiceOrder{
+		OrderID:                      orderID,
+		ClientPubkey:                 peerPubkey,
+		State:                        OrderStateCreated,
+		PaymentState:                 PaymentStateExpectPayment,
+		LSPBalance:                   req.LspBalanceLoki,
+		RequiredChannelConfirmations: req.RequiredChannelConfirmations,
+		FundingConfirmsWithinBlocks:  req.FundingConfirmsWithinBlocks,
+		ChannelExpiryBlocks:          req.ChannelExpiryBlocks,
+		AnnounceChannel:              req.AnnounceChannel,
+		FeeTotal:                     feeTotal,
+		OrderTotal:                   feeTotal,
+		PaymentInvoice:               invoice.Invoice,
+		PaymentHash:                  invoice.PaymentHash,
+		PaymentExpiresAt:             now.Add(time.Duration(h.config.PaymentExpirySeconds) * time.Second),
+		CreatedAt:                    now,
+		UpdatedAt:                    now,
+	}
+	if req.Token != nil {
+		order.Token = *req.Token
+	}
+	if req.RefundOnchainAddress != nil {
+		order.RefundOnchainAddress = *req.RefundOnchainAddress
+	}
+
+	if err := h.store.CreateLSPS1Order(order); err != nil {
+		logger.Logger.Error().Err(err).Str("order_id", orderID).Msg("Failed to save LSPS1 order")
+		return nil, &lsps0.JsonRpcError{Code: lsps0.ErrorCodeInternalError, Message: "Internal error"}
+	}
+
+	logger.Logger.Info().
+		Str("order_id", orderID).
+		Str("client", peerPubkey).
+		Uint64("lsp_balance", order.LSPBalance).
+		Uint64("fee_total", feeTotal).
+		Msg("Created LSPS1 order")
+
+	return orderResponse(order), nil
+}
+
+func (h *ServiceHandler) getOrder(ctx context.Context, peerPubkey string, req *GetOrderRequest) (interface{}, *lsps0.JsonRpcError) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	order, err := h.store.GetLSPS1Order(peerPubkey, req.OrderID)
+	if errors.Is(err, persist.ErrServiceRecordNotFound) {
+		return nil, &lsps0.JsonRpcError{
+			Code:    ErrorCodeOrderNotFound,
+			Message: "Order not found",
+			Data:    map[string]interface{}{"order_id": req.OrderID},
+		}
+	}
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("order_id", req.OrderID).Msg("Failed to get LSPS1 order")
+		return nil, &lsps0.JsonRpcError{Code: lsps0.ErrorCodeInternalError, Message: "Internal error"}
+	}
+
+	if err := h.processOrder(ctx, order); err != nil {
+		logger.Logger.Error().Err(err).Str("order_id", order.OrderID).Msg("Failed to process LSPS1 order")
+	}
+	return orderResponse(order), nil
+}
+
+// ProcessPendingOrders opens the channels of paid orders and fails orders
+// whose payment expired
+func (h *ServiceHandler) ProcessPendingOrders(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	orders, err := h.store.ListLSPS1OrdersByState(OrderStateCreated)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list pending LSPS1 orders")
+		return
+	}
+	for i := range orders {
+		if err := h.processOrder(ctx, &orders[i]); err != nil {
+			logger.Logger.Error().Err(err).Str("order_id", orders[i].OrderID).Msg("Failed to process LSPS1 order")
+		}
+	}
+}
+
+// processOrder moves a created order forward: once its invoice is settled the
+// channel is opened. Paid orders whose channel could not be opened stay PAID
+// until they are refunded. Must be called with h.mu held.
+func (h *ServiceHandler) processOrder(ctx context.Context, order *persist.LSPS1ServiceOrder) error {
+	if order.State != OrderStateCreated {
+		return nil
+	}
+
+	if order.PaymentState == PaymentStateExpectPayment {
+		invoice, err := h.node.LookupInvoice(ctx, order.PaymentHash)
+		if err != nil {
+			return fmt.Errorf("failed to look up order invoice: %w", err)
+		}
+		if invoice.SettledAt == nil {
+			if time.Now().After(order.PaymentExpiresAt) {
+				order.State = OrderStateFailed
+				order.FailureReason = "payment expired"
+				return h.store.UpdateLSPS1Order(order)
+			}
+			return nil
+		}
+		order.PaymentState = PaymentStatePaid
+	}
+
+	// the order leaves CREATED before the channel is opened, so it is never
+	// opened twice even if the outcome cannot be saved. Orders left OPENING
+	// are listed for the operator to check.
+	order.State = OrderStateOpening
+	if err := h.store.UpdateLSPS1Order(order); err != nil {
+		return err
+	}
+
+	openResponse, err := h.node.OpenChannel(ctx, &lnclient.OpenChannelRequest{
+		Pubkey:     order.ClientPubkey,
+		AmountLoki: int64(order.LSPBalance), //nolint:gosec // channel balances are far below int64 range
+		Public:     order.AnnounceChannel,
+	})
+	if err != nil {
+		// the payment was taken, so the order is listed to be refunded
+		logger.Logger.Error().Err(err).
+			Str("order_id", order.OrderID).
+			Str("client", order.ClientPubkey).
+			Uint64("order_total", order.OrderTotal).
+			Msg("Failed to open channel for paid LSPS1 order, it needs a refund")
+		order.State = OrderStateFailed
+		order.FailureReason = err.Error()
+		return h.store.UpdateLSPS1Order(order)
+	}
+
+	fundedAt := time.Now().UTC().Truncate(time.Second)
+	channelExpiresAt := fundedAt.Add(time.Duration(order.ChannelExpiryBlocks) * blockInterval)
+	order.State = OrderStateCompleted
+	order.FundingOutpoint = h.fundingOutpoint(ctx, order.ClientPubkey, openResponse.FundingTxId)
+	order.FundedAt = &fundedAt
+	order.ChannelExpiresAt = &channelExpiresAt
+
+	logger.Logger.Info().
+		Str("order_id", order.OrderID).
+		Str("client", order.ClientPubkey).
+		Str("funding_outpoint", order.FundingOutpoint).
+		Msg("Opened channel for LSPS1 order")
+
+	return h.store.UpdateLSPS1Order(order)
+}
+
+// fundingOutpoint returns the outpoint of the channel funded by fundingTxId,
+// or just the txid if the channel is not listed yet
+func (h *ServiceHandler) fundingOutpoint(ctx context.Context, clientPubkey, fundingTxId string) string {
+	channels, err := h.node.ListChannels(ctx)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Failed to list channels to find the funding outpoint")
+		return fundingTxId
+	}
+	for _, channel := range channels {
+		if channel.FundingTxId == fundingTxId && channel.RemotePubkey == clientPubkey {
+			return fmt.Sprintf("%s:%d", fundingTxId, channel.FundingTxVout)
+		}
+	}
+	return fundingTxId
+}
+
+// clientOrderState maps the persisted order state to an LSPS1 order state
+func clientOrderState(state string) string {
+	if state == OrderStateOpening {
+		return OrderStateCreated
+	}
+	return state
+}
+
+func orderResponse(order *persist.LSPS1ServiceOrder) *CreateOrderResponse {
+	response := &CreateOrderResponse{
+		OrderID: order.OrderID,
+		OrderParams: OrderParams{
+			LspBalanceLoki:               order.LSPBalance,
+			ClientBalanceLoki:            order.ClientBalance,
+			RequiredChannelConfirmations: order.RequiredChannelConfirmations,
+			FundingConfirmsWithinBlocks:  order.FundingConfirmsWithinBlocks,
+			ChannelExpiryBlocks:          order.ChannelExpiryBlocks,
+			AnnounceChannel:              order.AnnounceChannel,
+		},
+		CreatedAt:  order.CreatedAt,
+		OrderState: clientOrderState(order.State),
+		Payment: PaymentInfo{
+			Bolt11: &Bolt11PaymentInfo{
+				State:          order.PaymentState,
+				ExpiresAt:      order.PaymentExpiresAt,
+				FeeTotalLoki:   order.FeeTotal,
+				OrderTotalLoki: order.OrderTotal,
+				Invoice:        order.PaymentInvoice,
+			},
+		},
+	}
+	if order.Token != "" {
+		token := order.Token
+		response.Token = &token
+	}
+	if order.FundedAt != nil {
+		response.Channel = &ChannelInfo{
+			FundedAt:        *order.FundedAt,
+			FundingOutpoint: order.FundingOutpoint,
+			ExpiresAt:       *order.ChannelExpiresAt,
+		}
+	}
+	return response
+}
@@ -0,0 +1,407 @@
+package lsps1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/lsps/events"
+	"github.com/flokiorg/lokihub/lsps/lsps0"
+	"github.com/flokiorg/lokihub/lsps/persist"
+	"github.com/flokiorg/lokihub/lsps/transport"
+)
+
+const (
+	serviceTestClientPubkey = "03client"
+	serviceTestLSPPubkey    = "02lsp"
+)
+
+type fakeServiceNode struct {
+	invoices     map[string]*lnclient.Transaction
+	openRequests []*lnclient.OpenChannelRequest
+	openErr      error
+	mu           sync.Mutex
+}
+
+func (n *fakeServiceNode) MakeInvoice(ctx context.Context, amount int64, description string, descriptionHash string, expiry int64, throughNodePubkey *string, lspJitChannelSCID *string, lspCltvExpiryDelta *uint16, lspFeeBaseMloki *uint64, lspFeeProportionalMillionths *uint32) (*lnclient.Transaction, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	paymentHash := fmt.Sprintf("hash%d", len(n.invoices))
+	invoice := &lnclient.Transaction{
+		Type:        "incoming",
+		Invoice:     "lnfc" + paymentHash,
+		Description: description,
+		PaymentHash: paymentHash,
+		Amount:      amount,
+	}
+	n.invoices[paymentHash] = invoice
+	return invoice, nil
+}
+
+func (n *fakeServiceNode) LookupInvoice(ctx context.Context, paymentHash string) (*lnclient.Transaction, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	invoice, ok := n.invoices[paymentHash]
+	if !ok {
+		return nil, errors.New("invoice not found")
+	}
+	copied := *invoice
+	return &copied, nil
+}
+
+func (n *fakeServiceNode) OpenChannel(ctx context.Context, openChannelRequest *lnclient.OpenChannelRequest) (*lnclient.OpenChannelResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.openErr != nil {
+		return nil, n.openErr
+	}
+	n.openRequests = append(n.openRequests, openChannelRequest)
+	return &lnclient.OpenChannelResponse{FundingTxId: "fundingtx"}, nil
+}
+
+func (n *fakeServiceNode) ListChannels(ctx context.Context) ([]lnclient.Channel, error) {
+	return []lnclient.Channel{{RemotePubkey: serviceTestClientPubkey, FundingTxId: "fundingtx", FundingTxVout: 1}}, nil
+}
+
+func (n *fakeServiceNode) settle(paymentHash string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	settledAt := time.Now().Unix()
+	n.invoices[paymentHash].SettledAt = &settledAt
+}
+
+func (n *fakeServiceNode) openCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.openRequests)
+}
+
+type serviceTestSetup struct {
+	client  *ClientHandler
+	service *ServiceHandler
+	node    *fakeServiceNode
+	store   *persist.GormServiceStore
+	events  *events.EventQueue
+}
+
+// pumpMessages passes the messages arriving on t to handle until the test ends
+func pumpMessages(t *testing.T, tr transport.Transport, handle func(peerPubkey string, data []byte)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	msgs, _, err := tr.SubscribeCustomMessages(ctx)
+	require.NoError(t, err)
+	go func() {
+		for {
+			select {
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				handle(msg.PeerPubkey, msg.Data)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func setupService(t *testing.T, config ServiceConfig) *serviceTestSetup {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&persist.LSPS1ServiceOrder{}))
+
+	clientTransport, serviceTransport := transport.NewMemoryTransportPair(serviceTestClientPubkey, serviceTestLSPPubkey)
+
+	node := &fakeServiceNode{invoices: map[string]*lnclient.Transaction{}}
+	store := persist.NewGormServiceStore(db)
+	service := NewServiceHandler(node, store, config)
+	lsps0Service := lsps0.NewServiceHandler(serviceTransport, events.NewEventQueue(10), nil)
+	lsps0Service.RegisterProtocolHandler(1, service)
+	pumpMessages(t, serviceTransport, func(peerPubkey string, data []byte) {
+		_ = lsps0Service.HandleMessage(context.Background(), peerPubkey, data)
+	})
+
+	eq := events.NewEventQueue(10)
+	client := NewClientHandler(clientTransport, eq)
+	pumpMessages(t, clientTransport, func(peerPubkey string, data []byte) {
+		_ = client.HandleMessage(peerPubkey, data)
+	})
+
+	return &serviceTestSetup{client: client, service: service, node: node, store: store, events: eq}
+}
+
+func (s *serviceTestSetup) nextEvent(t *testing.T) events.Event {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	event, err := s.events.NextEvent(ctx)
+	require.NoError(t, err)
+	return event
+}
+
+func validOrder() OrderParams {
+	return OrderParams{
+		LspBalanceLoki:               1_000_000,
+		RequiredChannelConfirmations: 3,
+		FundingConfirmsWithinBlocks:  6,
+		ChannelExpiryBlocks:          1440,
+		AnnounceChannel:              true,
+	}
+}
+
+func TestServiceHandler_GetInfo(t *testing.T) {
+	s := setupService(t, DefaultServiceConfig())
+
+	_, err := s.client.RequestSupportedOptions(context.Background(), serviceTestLSPPubkey)
+	require.NoError(t, err)
+
+	event, ok := s.nextEvent(t).(*SupportedOptionsReadyEvent)
+	require.True(t, ok)
+	assert.Equal(t, uint64(100_000), event.SupportedOptions.MinInitialLspBalanceLoki)
+	assert.Equal(t, uint64(16_777_215), event.SupportedOptions.MaxInitialLspBalanceLoki)
+	assert.Equal(t, uint64(0), event.SupportedOptions.MaxInitialClientBalanceLoki)
+	assert.Equal(t, uint16(3), event.SupportedOptions.MinRequiredChannelConfirmations)
+}
+
+func TestServiceHandler_OrderLifecycle(t *testing.T) {
+	s := setupService(t, DefaultServiceConfig())
+	ctx := context.Background()
+
+	refundAddress := "fc1qrefund"
+	_, err := s.client.CreateOrder(ctx, serviceTestLSPPubkey, validOrder(), &refundAddress)
+	require.NoError(t, err)
+
+	created, ok := s.nextEvent(t).(*OrderCreatedEvent)
+	require.True(t, ok)
+	assert.Equal(t, OrderStateCreated, created.OrderState)
+	require.NotNil(t, created.Payment.Bolt11)
+	assert.Equal(t, PaymentStateExpectPayment, created.Payment.Bolt11.State)
+	// 1000 base + 1% of 1M
+	assert.Equal(t, uint64(11_000), created.Payment.Bolt11.FeeTotalLoki)
+	assert.Equal(t, uint64(11_000), created.Payment.Bolt11.OrderTotalLoki)
+	assert.Equal(t, "lnfchash0", created.Payment.Bolt11.Invoice)
+	assert.Equal(t, int64(11_000_000), s.node.invoices["hash0"].Amount)
+	assert.Nil(t, created.Channel)
+
+	order, err := s.store.GetLSPS1Order(serviceTestClientPubkey, created.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, refundAddress, order.RefundOnchainAddress)
+
+	// unpaid orders stay open
+	_, err = s.client.CheckOrderStatus(ctx, serviceTestLSPPubkey, created.OrderID)
+	require.NoError(t, err)
+	status, ok := s.nextEvent(t).(*OrderStatusEvent)
+	require.True(t, ok)
+	assert.Equal(t, OrderStateCreated, status.OrderState)
+	assert.Equal(t, 0, s.node.openCount())
+
+	s.node.settle("hash0")
+	_, err = s.client.CheckOrderStatus(ctx, serviceTestLSPPubkey, created.OrderID)
+	require.NoError(t, err)
+	status, ok = s.nextEvent(t).(*OrderStatusEvent)
+	require.True(t, ok)
+	assert.Equal(t, OrderStateCompleted, status.OrderState)
+	assert.Equal(t, PaymentStatePaid, status.Payment.Bolt11.State)
+	require.NotNil(t, status.Channel)
+	assert.Equal(t, "fundingtx:1", status.Channel.FundingOutpoint)
+	assert.Equal(t, 1440*time.Minute, status.Channel.ExpiresAt.Sub(status.Channel.FundedAt))
+
+	require.Equal(t, 1, s.node.openCount())
+	assert.Equal(t, &lnclient.OpenChannelRequest{Pubkey: serviceTestClientPubkey, AmountLoki: 1_000_000, Public: true}, s.node.openRequests[0])
+
+	// the channel is only opened once
+	s.service.ProcessPendingOrders(ctx)
+	_, err = s.client.CheckOrderStatus(ctx, serviceTestLSPPubkey, created.OrderID)
+	require.NoError(t, err)
+	status, ok = s.nextEvent(t).(*OrderStatusEvent)
+	require.True(t, ok)
+	assert.Equal(t, OrderStateCompleted, status.OrderState)
+	assert.Equal(t, 1, s.node.openCount())
+}
+
+func TestServiceHandler_OptionMismatch(t *testing.T) {
+	s := setupService(t, DefaultServiceConfig())
+
+	tests := []struct {
+		property string
+		modify   func(order *OrderParams)
+	}{
+		{"lsp_balance_loki", func(order *OrderParams) { order.LspBalanceLoki = 99_999 }},
+		{"lsp_balance_loki", func(order *OrderParams) { order.LspBalanceLoki = 16_777_216 }},
+		{"client_balance_loki", func(order *OrderParams) { order.ClientBalanceLoki = 1 }},
+		{"required_channel_confirmations", func(order *OrderParams) { order.RequiredChannelConfirmations = 2 }},
+		{"funding_confirms_within_blocks", func(order *OrderParams) { order.FundingConfirmsWithinBlocks = 5 }},
+		{"channel_expiry_blocks", func(order *OrderParams) { order.ChannelExpiryBlocks = 129_601 }},
+	}
+	for _, tt := range tests {
+		order := validOrder()
+		tt.modify(&order)
+		_, err := s.client.CreateOrder(context.Background(), serviceTestLSPPubkey, order, nil)
+		require.NoError(t, err)
+
+		failed, ok := s.nextEvent(t).(*OrderRequestFailedEvent)
+		require.True(t, ok)
+		assert.Equal(t, ErrorCodeOptionMismatch, failed.ErrorCode)
+		assert.Equal(t, tt.property, failed.ErrorData["property"])
+	}
+
+	orders, err := s.store.ListLSPS1OrdersByState(OrderStateCreated)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+}
+
+func TestServiceHandler_OrderNotFound(t *testing.T) {
+	s := setupService(t, DefaultServiceConfig())
+
+	// orders placed by other nodes are not visible
+	require.NoError(t, s.store.CreateLSPS1Order(&persist.LSPS1ServiceOrder{
+		OrderID:      "other",
+		ClientPubkey: "03other",
+		State:        OrderStateCreated,
+	}))
+
+	_, err := s.client.CheckOrderStatus(context.Background(), serviceTestLSPPubkey, "other")
+	require.NoError(t, err)
+	failed, ok := s.nextEvent(t).(*OrderRequestFailedEvent)
+	require.True(t, ok)
+	assert.Equal(t, ErrorCodeOrderNotFound, failed.ErrorCode)
+}
+
+func TestServiceHandler_ProcessPendingOrders(t *testing.T) {
+	config := DefaultServiceConfig()
+	s := setupService(t, config)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := s.client.CreateOrder(ctx, serviceTestLSPPubkey, validOrder(), nil)
+		require.NoError(t, err)
+		_, ok := s.nextEvent(t).(*OrderCreatedEvent)
+		require.True(t, ok)
+	}
+	orders, err := s.store.ListLSPS1OrdersByState(OrderStateCreated)
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+
+	// one paid, one expired, one still waiting for payment
+	paid := orders[0]
+	s.node.settle(paid.PaymentHash)
+	expired := orders[1]
+	expired.PaymentExpiresAt = time.Now().Add(-time.Second)
+	require.NoError(t, s.store.UpdateLSPS1Order(&expired))
+
+	s.service.ProcessPendingOrders(ctx)
+
+	order, err := s.store.GetLSPS1Order(serviceTestClientPubkey, paid.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStateCompleted, order.State)
+	order, err = s.store.GetLSPS1Order(serviceTestClientPubkey, expired.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStateFailed, order.State)
+	assert.Equal(t, PaymentStateExpectPayment, order.PaymentState)
+	order, err = s.store.GetLSPS1Order(serviceTestClientPubkey, orders[2].OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStateCreated, order.State)
+	assert.Equal(t, 1, s.node.openCount())
+}
+
+func TestServiceHandler_OpenChannelFails(t *testing.T) {
+	s := setupService(t, DefaultServiceConfig())
+	ctx := context.Background()
+
+	_, err := s.client.CreateOrder(ctx, serviceTestLSPPubkey, validOrder(), nil)
+	require.NoError(t, err)
+	created, ok := s.nextEvent(t).(*OrderCreatedEvent)
+	require.True(t, ok)
+
+	s.node.openErr = errors.New("not enough funds")
+	s.node.settle("hash0")
+	s.service.ProcessPendingOrders(ctx)
+
+	order, err := s.store.GetLSPS1Order(serviceTestClientPubkey, created.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStateFailed, order.State)
+	assert.Equal(t, PaymentStatePaid, order.PaymentState)
+	assert.Equal(t, "not enough funds", order.FailureReason)
+}
+
+// completionFailingStore fails to save orders whose channel was opened
+type completionFailingStore struct {
+	*persist.GormServiceStore
+}
+
+func (s *completionFailingStore) UpdateLSPS1Order(order *persist.LSPS1ServiceOrder) error {
+	if order.State == OrderStateCompleted {
+		return errors.New("database is locked")
+	}
+	return s.GormServiceStore.UpdateLSPS1Order(order)
+}
+
+func TestServiceHandler_OpenedOrderNotSaved(t *testing.T) {
+	s := setupService(t, DefaultServiceConfig())
+	ctx := context.Background()
+
+	_, err := s.client.CreateOrder(ctx, serviceTestLSPPubkey, validOrder(), nil)
+	require.NoError(t, err)
+	created, ok := s.nextEvent(t).(*OrderCreatedEvent)
+	require.True(t, ok)
+
+	s.node.settle("hash0")
+	service := NewServiceHandler(s.node, &completionFailingStore{s.store}, DefaultServiceConfig())
+	service.ProcessPendingOrders(ctx)
+	service.ProcessPendingOrders(ctx)
+	assert.Equal(t, 1, s.node.openCount())
+
+	order, err := s.store.GetLSPS1Order(serviceTestClientPubkey, created.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStateOpening, order.State)
+	assert.Equal(t, OrderStateCreated, orderResponse(order).OrderState)
+
+	// the operator is asked to check it
+	orders, err := s.store.ListLSPS1OrdersToRefund()
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, created.OrderID, orders[0].OrderID)
+}
+
+func TestServiceHandler_RefundFailedOrder(t *testing.T) {
+	s := setupService(t, DefaultServiceConfig())
+	ctx := context.Background()
+
+	_, err := s.client.CreateOrder(ctx, serviceTestLSPPubkey, validOrder(), nil)
+	require.NoError(t, err)
+	created, ok := s.nextEvent(t).(*OrderCreatedEvent)
+	require.True(t, ok)
+	_, err = s.client.CreateOrder(ctx, serviceTestLSPPubkey, validOrder(), nil)
+	require.NoError(t, err)
+	_, ok = s.nextEvent(t).(*OrderCreatedEvent)
+	require.True(t, ok)
+
+	// only the paid order needs a refund
+	s.node.openErr = errors.New("not enough funds")
+	s.node.settle("hash0")
+	s.service.ProcessPendingOrders(ctx)
+
+	orders, err := s.store.ListLSPS1OrdersToRefund()
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, created.OrderID, orders[0].OrderID)
+
+	refunded, err := s.store.MarkLSPS1OrderRefunded(created.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStateFailed, refunded.State)
+	assert.Equal(t, PaymentStateRefunded, refunded.PaymentState)
+
+	orders, err = s.store.ListLSPS1OrdersToRefund()
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+	_, err = s.store.MarkLSPS1OrderRefunded(created.OrderID)
+	assert.ErrorIs(t, err, persist.ErrServiceRecordNotFound)
+}
@@ -42,12 +42,18 @@ func (m *mockLNClient) SubscribeCustomMessages(ctx context.Context) (<-chan lncl
 }
 
 // No-ops for other methods
-func (m *mockLNClient) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
+func (m *mockLNClient) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
 	return nil, nil
 }
-func (m *mockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
+func (m *mockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
 	return nil, nil
 }
+func (m *mockLNClient) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) MakeOffer(ctx context.Context, description string) (string, error) {
+	return "", nil
+}
 func (m *mockLNClient) GetPubkey() string                                       { return "" }
 func (m *mockLNClient) GetInfo(ctx context.Context) (*lnclient.NodeInfo, error) { return nil, nil }
 func (m *mockLNClient) MakeInvoice(ctx context.Context, amount int64, description string, descriptionHash string, expiry int64, throughNodePubkey *string, lspJitChannelSCID *string, lspCltvExpiryDelta *uint16, lspFeeBaseMloki *uint64, lspFeeProportionalMillionths *uint32) (*lnclient.Transaction, error) {
@@ -67,6 +73,39 @@ func (m *mockLNClient) ListTransactions(ctx context.Context, from, until, limit,
 func (m *mockLNClient) ListOnchainTransactions(ctx context.Context, from, until, limit, offset uint64) ([]lnclient.OnchainTransaction, error) {
 	return nil, nil
 }
+func (m *mockLNClient) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) PublishTransaction(ctx context.Context, rawTx string, label string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClient) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClient) FreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClient) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClient) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	return nil, nil
+}
 func (m *mockLNClient) Shutdown() error                                              { return nil }
 func (m *mockLNClient) ListChannels(ctx context.Context) ([]lnclient.Channel, error) { return nil, nil }
 func (m *mockLNClient) GetNodeConnectionInfo(ctx context.Context) (*lnclient.NodeConnectionInfo, error) {
@@ -99,9 +138,11 @@ func (m *mockLNClient) GetBalances(ctx context.Context, includeInactiveChannels
 func (m *mockLNClient) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (string, error) {
 	return "", nil
 }
-func (m *mockLNClient) SendPaymentProbes(ctx context.Context, invoice string) error { return nil }
-func (m *mockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
-	return nil
+func (m *mockLNClient) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
+	return nil, nil
 }
 func (m *mockLNClient) ListPeers(ctx context.Context) ([]lnclient.PeerDetails, error) {
 	return nil, nil
@@ -122,7 +163,7 @@ func (m *mockLNClient) ExecuteCustomNodeCommand(ctx context.Context, command *ln
 	return nil, nil
 }
 
-func (m *mockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (m *mockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	return nil, nil, nil
 }
 
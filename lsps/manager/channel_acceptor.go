@@ -0,0 +1,182 @@
+package manager
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/lsps/persist"
+)
+
+// ChannelAcceptorPolicy decides which inbound channels are accepted. Every
+// rule is off at its zero value, so the zero policy accepts every channel.
+// Channels from active LSPs are only subject to the deny list, since they are
+// liquidity the user asked for and are often private or small.
+type ChannelAcceptorPolicy struct {
+	MinCapacity       uint64   `json:"minCapacity"` // loki
+	MaxCapacity       uint64   `json:"maxCapacity"` // loki
+	AllowedPubkeys    []string `json:"allowedPubkeys"`
+	DeniedPubkeys     []string `json:"deniedPubkeys"`
+	MaxPendingPerPeer int      `json:"maxPendingPerPeer"`
+	RejectPrivate     bool     `json:"rejectPrivate"`
+	OnlyLSPs          bool     `json:"onlyLsps"`
+}
+
+// LoadChannelAcceptorPolicy returns the saved channel acceptor policy, or the
+// zero policy if none was saved.
+func LoadChannelAcceptorPolicy(cfg config.Config) (*ChannelAcceptorPolicy, error) {
+	policy := &ChannelAcceptorPolicy{}
+	value, err := cfg.Get(config.ChannelAcceptorPolicyKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("invalid channel acceptor policy: %w", err)
+	}
+	return policy, nil
+}
+
+// SaveChannelAcceptorPolicy validates and saves the channel acceptor policy.
+// Pubkeys are stored lowercased and without duplicates.
+func SaveChannelAcceptorPolicy(cfg config.Config, policy *ChannelAcceptorPolicy) error {
+	if policy.MaxPendingPerPeer < 0 {
+		return fmt.Errorf("%w: max pending channels per peer must not be negative", constants.ErrInvalidParams)
+	}
+	if policy.MaxCapacity > 0 && policy.MaxCapacity < policy.MinCapacity {
+		return fmt.Errorf("%w: max capacity must not be below min capacity", constants.ErrInvalidParams)
+	}
+	allowedPubkeys, err := normalizeNodePubkeys(policy.AllowedPubkeys)
+	if err != nil {
+		return err
+	}
+	deniedPubkeys, err := normalizeNodePubkeys(policy.DeniedPubkeys)
+	if err != nil {
+		return err
+	}
+
+	normalized := *policy
+	normalized.AllowedPubkeys = allowedPubkeys
+	normalized.DeniedPubkeys = deniedPubkeys
+	value, err := json.Marshal(&normalized)
+	if err != nil {
+		return err
+	}
+	return cfg.SetUpdate(config.ChannelAcceptorPolicyKey, string(value), "")
+}
+
+// normalizeNodePubkeys lowercases and dedupes a list of node pubkeys,
+// rejecting anything that is not a 33-byte compressed public key.
+func normalizeNodePubkeys(pubkeys []string) ([]string, error) {
+	normalized := make([]string, 0, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+		decoded, err := hex.DecodeString(pubkey)
+		if err != nil || len(decoded) != 33 {
+			return nil, fmt.Errorf("%w: invalid node pubkey %q", constants.ErrInvalidParams, pubkey)
+		}
+		if !slices.Contains(normalized, pubkey) {
+			normalized = append(normalized, pubkey)
+		}
+	}
+	return normalized, nil
+}
+
+// getChannelAcceptorPolicy returns the saved policy, or nil to accept every
+// channel if there is none or it cannot be read.
+func (m *LiquidityManager) getChannelAcceptorPolicy() *ChannelAcceptorPolicy {
+	if m.cfg.AppConfig == nil {
+		return nil
+	}
+	policy, err := LoadChannelAcceptorPolicy(m.cfg.AppConfig)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to load channel acceptor policy, accepting channels without it")
+		return nil
+	}
+	return policy
+}
+
+// evaluateChannelAcceptRequest applies policy to req and returns whether to
+// accept the channel, or the reason to send to the peer otherwise.
+func (m *LiquidityManager) evaluateChannelAcceptRequest(ctx context.Context, req lnclient.ChannelAcceptRequest, policy *ChannelAcceptorPolicy, fromLSP bool) (bool, string) {
+	if policy == nil {
+		return true, ""
+	}
+	peerPubkey := strings.ToLower(req.NodePubkey)
+
+	if slices.Contains(policy.DeniedPubkeys, peerPubkey) {
+		return false, "channels from this node are not accepted"
+	}
+	if fromLSP {
+		return true, ""
+	}
+	if policy.OnlyLSPs {
+		return false, "only channels from this node's LSPs are accepted"
+	}
+	if len(policy.AllowedPubkeys) > 0 && !slices.Contains(policy.AllowedPubkeys, peerPubkey) {
+		return false, "channels from this node are not accepted"
+	}
+	if policy.RejectPrivate && req.Private {
+		return false, "private channels are not accepted"
+	}
+	if policy.MinCapacity > 0 && req.Capacity < policy.MinCapacity {
+		return false, fmt.Sprintf("channel capacity is below the minimum of %d loki", policy.MinCapacity)
+	}
+	if policy.MaxCapacity > 0 && req.Capacity > policy.MaxCapacity {
+		return false, fmt.Sprintf("channel capacity is above the maximum of %d loki", policy.MaxCapacity)
+	}
+	if policy.MaxPendingPerPeer > 0 {
+		channels, err := m.cfg.LNClient.ListChannels(ctx)
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("pubkey", req.NodePubkey).
+				Msg("Failed to list channels, not enforcing max pending channels per peer")
+			return true, ""
+		}
+		pending := 0
+		for _, channel := range channels {
+			if strings.ToLower(channel.RemotePubkey) == peerPubkey && isOpeningChannel(&channel) {
+				pending++
+			}
+		}
+		if pending >= policy.MaxPendingPerPeer {
+			return false, fmt.Sprintf("too many pending channels with this node (maximum %d)", policy.MaxPendingPerPeer)
+		}
+	}
+	return true, ""
+}
+
+// isOpeningChannel reports whether a channel is still waiting for its
+// funding transaction to confirm.
+func isOpeningChannel(channel *lnclient.Channel) bool {
+	if channel.Active {
+		return false
+	}
+	return channel.Confirmations == nil || channel.ConfirmationsRequired == nil ||
+		*channel.Confirmations < *channel.ConfirmationsRequired
+}
+
+func (m *LiquidityManager) recordChannelAcceptDecision(req lnclient.ChannelAcceptRequest, accept, zeroConf bool, reason string) {
+	if m.cfg.LSPManager == nil {
+		return
+	}
+	err := m.cfg.LSPManager.RecordChannelAcceptDecision(&persist.ChannelAcceptDecision{
+		PeerPubkey: strings.ToLower(req.NodePubkey),
+		Capacity:   req.Capacity,
+		Private:    req.Private,
+		Accepted:   accept,
+		ZeroConf:   zeroConf,
+		Reason:     reason,
+	})
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("pubkey", req.NodePubkey).Msg("Failed to record channel acceptor decision")
+	}
+}
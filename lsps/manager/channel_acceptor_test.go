@@ -0,0 +1,263 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+const (
+	acceptorLSPPubkey  = "02aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	acceptorPeerPubkey = "03bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	acceptorDenyPubkey = "03cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+)
+
+// acceptorTestConfig stores config values in memory. Only Get and SetUpdate
+// are used by the channel acceptor.
+type acceptorTestConfig struct {
+	config.Config
+	values map[string]string
+}
+
+func (c *acceptorTestConfig) Get(key string, encryptionKey string) (string, error) {
+	return c.values[key], nil
+}
+
+func (c *acceptorTestConfig) SetUpdate(key string, value string, encryptionKey string) error {
+	c.values[key] = value
+	return nil
+}
+
+type acceptorResponse struct {
+	accept       bool
+	zeroConf     bool
+	rejectReason string
+}
+
+func setupChannelAcceptor(t *testing.T, policy *ChannelAcceptorPolicy) (*LiquidityManager, *mockLNClient) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+
+	lspManager := NewLSPManager(db)
+	_, err = lspManager.AddLSP("LSP", acceptorLSPPubkey, "127.0.0.1:9735", true, false)
+	require.NoError(t, err)
+
+	appConfig := &acceptorTestConfig{values: map[string]string{}}
+	if policy != nil {
+		require.NoError(t, SaveChannelAcceptorPolicy(appConfig, policy))
+	}
+
+	lnClient := &mockLNClient{}
+	m := &LiquidityManager{
+		cfg: &ManagerConfig{
+			LNClient:   lnClient,
+			LSPManager: lspManager,
+			AppConfig:  appConfig,
+		},
+		nostrPubkeys: make(map[string]string),
+	}
+	return m, lnClient
+}
+
+func handleChannelAcceptTestRequest(m *LiquidityManager, req lnclient.ChannelAcceptRequest) acceptorResponse {
+	var response acceptorResponse
+	m.handleChannelAcceptRequest(context.Background(), req, func(_ string, accept bool, zeroConf bool, rejectReason string) error {
+		response = acceptorResponse{accept: accept, zeroConf: zeroConf, rejectReason: rejectReason}
+		return nil
+	})
+	return response
+}
+
+func TestChannelAcceptor_NoPolicy(t *testing.T) {
+	m, _ := setupChannelAcceptor(t, nil)
+
+	response := handleChannelAcceptTestRequest(m, lnclient.ChannelAcceptRequest{ID: "1", NodePubkey: acceptorPeerPubkey, Capacity: 1, Private: true})
+	assert.Equal(t, acceptorResponse{accept: true}, response)
+
+	response = handleChannelAcceptTestRequest(m, lnclient.ChannelAcceptRequest{ID: "2", NodePubkey: acceptorLSPPubkey, Capacity: 1})
+	assert.Equal(t, acceptorResponse{accept: true, zeroConf: true}, response)
+}
+
+func TestChannelAcceptor_Rules(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   ChannelAcceptorPolicy
+		req      lnclient.ChannelAcceptRequest
+		accept   bool
+		zeroConf bool
+		reason   string
+	}{
+		{
+			name:   "below min capacity",
+			policy: ChannelAcceptorPolicy{MinCapacity: 100_000},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorPeerPubkey, Capacity: 99_999},
+			reason: "channel capacity is below the minimum of 100000 loki",
+		},
+		{
+			name:   "above max capacity",
+			policy: ChannelAcceptorPolicy{MaxCapacity: 1_000_000},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorPeerPubkey, Capacity: 1_000_001},
+			reason: "channel capacity is above the maximum of 1000000 loki",
+		},
+		{
+			name:   "within capacity bounds",
+			policy: ChannelAcceptorPolicy{MinCapacity: 100_000, MaxCapacity: 1_000_000},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorPeerPubkey, Capacity: 100_000},
+			accept: true,
+		},
+		{
+			name:   "private channel",
+			policy: ChannelAcceptorPolicy{RejectPrivate: true},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorPeerPubkey, Capacity: 100_000, Private: true},
+			reason: "private channels are not accepted",
+		},
+		{
+			name:   "denied peer",
+			policy: ChannelAcceptorPolicy{DeniedPubkeys: []string{acceptorPeerPubkey}},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: strings.ToUpper(acceptorPeerPubkey), Capacity: 100_000},
+			reason: "channels from this node are not accepted",
+		},
+		{
+			name:   "peer not on allow list",
+			policy: ChannelAcceptorPolicy{AllowedPubkeys: []string{acceptorDenyPubkey}},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorPeerPubkey, Capacity: 100_000},
+			reason: "channels from this node are not accepted",
+		},
+		{
+			name:   "peer on allow list",
+			policy: ChannelAcceptorPolicy{AllowedPubkeys: []string{acceptorPeerPubkey}, RejectPrivate: true},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorPeerPubkey, Capacity: 100_000},
+			accept: true,
+		},
+		{
+			name:   "only LSPs",
+			policy: ChannelAcceptorPolicy{OnlyLSPs: true},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorPeerPubkey, Capacity: 100_000},
+			reason: "only channels from this node's LSPs are accepted",
+		},
+		{
+			name:     "LSP exempt from other rules",
+			policy:   ChannelAcceptorPolicy{OnlyLSPs: true, MinCapacity: 100_000, RejectPrivate: true, AllowedPubkeys: []string{acceptorPeerPubkey}},
+			req:      lnclient.ChannelAcceptRequest{NodePubkey: acceptorLSPPubkey, Capacity: 1, Private: true},
+			accept:   true,
+			zeroConf: true,
+		},
+		{
+			name:   "denied LSP",
+			policy: ChannelAcceptorPolicy{DeniedPubkeys: []string{acceptorLSPPubkey}},
+			req:    lnclient.ChannelAcceptRequest{NodePubkey: acceptorLSPPubkey, Capacity: 100_000},
+			reason: "channels from this node are not accepted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.policy
+			m, _ := setupChannelAcceptor(t, &policy)
+			tt.req.ID = "req"
+
+			response := handleChannelAcceptTestRequest(m, tt.req)
+			assert.Equal(t, acceptorResponse{accept: tt.accept, zeroConf: tt.zeroConf, rejectReason: tt.reason}, response)
+
+			decisions, totalCount, err := m.cfg.LSPManager.ListChannelAcceptDecisions(nil, 0, 0)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), totalCount)
+			assert.Equal(t, strings.ToLower(tt.req.NodePubkey), decisions[0].PeerPubkey)
+			assert.Equal(t, tt.req.Capacity, decisions[0].Capacity)
+			assert.Equal(t, tt.req.Private, decisions[0].Private)
+			assert.Equal(t, tt.accept, decisions[0].Accepted)
+			assert.Equal(t, tt.zeroConf, decisions[0].ZeroConf)
+			assert.Equal(t, tt.reason, decisions[0].Reason)
+		})
+	}
+}
+
+func TestChannelAcceptor_MaxPendingPerPeer(t *testing.T) {
+	m, lnClient := setupChannelAcceptor(t, &ChannelAcceptorPolicy{MaxPendingPerPeer: 2})
+
+	confirmations := uint32(0)
+	confirmationsRequired := uint32(3)
+	pendingChannel := lnclient.Channel{RemotePubkey: acceptorPeerPubkey, Confirmations: &confirmations, ConfirmationsRequired: &confirmationsRequired}
+	lnClient.channels = []lnclient.Channel{
+		pendingChannel,
+		{RemotePubkey: acceptorPeerPubkey, Active: true},
+		{RemotePubkey: acceptorDenyPubkey},
+	}
+
+	req := lnclient.ChannelAcceptRequest{ID: "1", NodePubkey: acceptorPeerPubkey, Capacity: 100_000}
+	assert.Equal(t, acceptorResponse{accept: true}, handleChannelAcceptTestRequest(m, req))
+
+	lnClient.channels = append(lnClient.channels, pendingChannel)
+	assert.Equal(t, acceptorResponse{rejectReason: "too many pending channels with this node (maximum 2)"}, handleChannelAcceptTestRequest(m, req))
+}
+
+func TestListChannelAcceptDecisions(t *testing.T) {
+	m, _ := setupChannelAcceptor(t, &ChannelAcceptorPolicy{RejectPrivate: true})
+
+	for i := 0; i < 3; i++ {
+		handleChannelAcceptTestRequest(m, lnclient.ChannelAcceptRequest{ID: "public", NodePubkey: acceptorPeerPubkey, Capacity: uint64(i)})
+	}
+	handleChannelAcceptTestRequest(m, lnclient.ChannelAcceptRequest{ID: "private", NodePubkey: acceptorPeerPubkey, Private: true})
+
+	accepted := true
+	decisions, totalCount, err := m.cfg.LSPManager.ListChannelAcceptDecisions(&accepted, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), totalCount)
+	require.Len(t, decisions, 2)
+	// newest first
+	assert.Equal(t, uint64(2), decisions[0].Capacity)
+	assert.Equal(t, uint64(1), decisions[1].Capacity)
+
+	decisions, _, err = m.cfg.LSPManager.ListChannelAcceptDecisions(&accepted, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, uint64(0), decisions[0].Capacity)
+
+	rejected := false
+	decisions, totalCount, err = m.cfg.LSPManager.ListChannelAcceptDecisions(&rejected, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), totalCount)
+	assert.True(t, decisions[0].Private)
+}
+
+func TestSaveChannelAcceptorPolicy(t *testing.T) {
+	appConfig := &acceptorTestConfig{values: map[string]string{}}
+
+	policy, err := LoadChannelAcceptorPolicy(appConfig)
+	require.NoError(t, err)
+	assert.Equal(t, &ChannelAcceptorPolicy{}, policy)
+
+	err = SaveChannelAcceptorPolicy(appConfig, &ChannelAcceptorPolicy{
+		MinCapacity:    100_000,
+		AllowedPubkeys: []string{strings.ToUpper(acceptorPeerPubkey), " " + acceptorPeerPubkey + " "},
+	})
+	require.NoError(t, err)
+	policy, err = LoadChannelAcceptorPolicy(appConfig)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100_000), policy.MinCapacity)
+	assert.Equal(t, []string{acceptorPeerPubkey}, policy.AllowedPubkeys)
+
+	invalidPolicies := []*ChannelAcceptorPolicy{
+		{MaxPendingPerPeer: -1},
+		{MinCapacity: 200_000, MaxCapacity: 100_000},
+		{DeniedPubkeys: []string{"not-a-pubkey"}},
+		{AllowedPubkeys: []string{acceptorPeerPubkey[:64]}},
+	}
+	for _, invalidPolicy := range invalidPolicies {
+		assert.ErrorIs(t, SaveChannelAcceptorPolicy(appConfig, invalidPolicy), constants.ErrInvalidParams)
+	}
+	// the saved policy is unchanged
+	policy, err = LoadChannelAcceptorPolicy(appConfig)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100_000), policy.MinCapacity)
+}
@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/lsps/lsps0"
+	"github.com/flokiorg/lokihub/lsps/lsps1"
+	"github.com/flokiorg/lokihub/lsps/persist"
+)
+
+// LSPServiceConfig configures selling channels to other nodes over LSPS1.
+// Changes take effect when the node is restarted.
+type LSPServiceConfig struct {
+	Enabled bool                `json:"enabled"`
+	LSPS1   lsps1.ServiceConfig `json:"lsps1"`
+}
+
+// LoadLSPServiceConfig returns the saved LSP service config, or the disabled
+// default config if none was saved.
+func LoadLSPServiceConfig(cfg config.Config) (*LSPServiceConfig, error) {
+	serviceConfig := &LSPServiceConfig{
+		LSPS1: lsps1.DefaultServiceConfig(),
+	}
+	value, err := cfg.Get(config.LSPServiceConfigKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return serviceConfig, nil
+	}
+	if err := json.Unmarshal([]byte(value), serviceConfig); err != nil {
+		return nil, fmt.Errorf("invalid LSP service config: %w", err)
+	}
+	return serviceConfig, nil
+}
+
+// SaveLSPServiceConfig validates and saves the LSP service config.
+func SaveLSPServiceConfig(cfg config.Config, serviceConfig *LSPServiceConfig) error {
+	if serviceConfig.LSPS1.MinChannelBalanceLoki == 0 ||
+		serviceConfig.LSPS1.MaxChannelBalanceLoki < serviceConfig.LSPS1.MinChannelBalanceLoki {
+		return fmt.Errorf("%w: channel balance range is invalid", constants.ErrInvalidParams)
+	}
+	if serviceConfig.LSPS1.PaymentExpirySeconds == 0 {
+		return fmt.Errorf("%w: payment expiry must be set", constants.ErrInvalidParams)
+	}
+
+	value, err := json.Marshal(serviceConfig)
+	if err != nil {
+		return err
+	}
+	return cfg.SetUpdate(config.LSPServiceConfigKey, string(value), "")
+}
+
+// startLSPService answers LSPS1 requests from other nodes if the LSP service
+// is enabled. LSPS2 is not offered: selling JIT channels needs HTLC
+// interception to open the channel when the client's payment arrives, which
+// the node does not provide.
+func (m *LiquidityManager) startLSPService() error {
+	if m.cfg.AppConfig == nil || m.cfg.LSPManager == nil {
+		return nil
+	}
+	serviceConfig, err := LoadLSPServiceConfig(m.cfg.AppConfig)
+	if err != nil {
+		return err
+	}
+	if !serviceConfig.Enabled {
+		return nil
+	}
+
+	store := persist.NewGormServiceStore(m.cfg.LSPManager.db)
+	m.lsps0Service = lsps0.NewServiceHandler(m.transport, m.eventQueue, nil)
+	m.lsps1Service = lsps1.NewServiceHandler(m.cfg.LNClient, store, serviceConfig.LSPS1)
+	m.lsps0Service.RegisterProtocolHandler(1, m.lsps1Service)
+
+	logger.Logger.Info().Msg("LSP service enabled, answering LSPS1 requests")
+	return nil
+}
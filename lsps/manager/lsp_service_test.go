@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/lsps/lsps0"
+	"github.com/flokiorg/lokihub/lsps/lsps1"
+)
+
+// lspServiceTestLNClient records the custom messages sent by the manager
+type lspServiceTestLNClient struct {
+	mockLNClient
+	sent chan lnclient.CustomMessage
+}
+
+func (m *lspServiceTestLNClient) SendCustomMessage(ctx context.Context, peerPubkey string, msgType uint32, data []byte) error {
+	m.sent <- lnclient.CustomMessage{PeerPubkey: peerPubkey, Type: msgType, Data: data}
+	return nil
+}
+
+func TestSaveLSPServiceConfig(t *testing.T) {
+	appConfig := &acceptorTestConfig{values: map[string]string{}}
+
+	serviceConfig, err := LoadLSPServiceConfig(appConfig)
+	require.NoError(t, err)
+	assert.False(t, serviceConfig.Enabled)
+	assert.Equal(t, lsps1.DefaultServiceConfig(), serviceConfig.LSPS1)
+
+	serviceConfig.Enabled = true
+	serviceConfig.LSPS1.FeePPM = 5_000
+	require.NoError(t, SaveLSPServiceConfig(appConfig, serviceConfig))
+	saved, err := LoadLSPServiceConfig(appConfig)
+	require.NoError(t, err)
+	assert.Equal(t, serviceConfig, saved)
+
+	invalidConfigs := []func(serviceConfig *LSPServiceConfig){
+		func(serviceConfig *LSPServiceConfig) { serviceConfig.LSPS1.MinChannelBalanceLoki = 0 },
+		func(serviceConfig *LSPServiceConfig) { serviceConfig.LSPS1.MaxChannelBalanceLoki = 1 },
+		func(serviceConfig *LSPServiceConfig) { serviceConfig.LSPS1.PaymentExpirySeconds = 0 },
+	}
+	for _, modify := range invalidConfigs {
+		invalid := *saved
+		modify(&invalid)
+		assert.ErrorIs(t, SaveLSPServiceConfig(appConfig, &invalid), constants.ErrInvalidParams)
+	}
+}
+
+func TestLSPService_AnswersRequests(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+
+	appConfig := &acceptorTestConfig{values: map[string]string{}}
+	lnClient := &lspServiceTestLNClient{sent: make(chan lnclient.CustomMessage, 10)}
+	newManager := func() *LiquidityManager {
+		m, err := NewLiquidityManager(&ManagerConfig{
+			LNClient:   lnClient,
+			LSPManager: NewLSPManager(db),
+			AppConfig:  appConfig,
+		})
+		require.NoError(t, err)
+		return m
+	}
+
+	// disabled by default
+	m := newManager()
+	assert.Nil(t, m.lsps0Service)
+
+	serviceConfig, err := LoadLSPServiceConfig(appConfig)
+	require.NoError(t, err)
+	serviceConfig.Enabled = true
+	require.NoError(t, SaveLSPServiceConfig(appConfig, serviceConfig))
+
+	m = newManager()
+	require.NotNil(t, m.lsps0Service)
+
+	request, err := json.Marshal(&lsps0.JsonRpcRequest{Jsonrpc: "2.0", Method: lsps0.MethodListProtocols, ID: "1"})
+	require.NoError(t, err)
+	m.dispatchMessage(context.Background(), lnclient.CustomMessage{PeerPubkey: "03client", Type: lsps0.LSPS_MESSAGE_TYPE_ID, Data: request})
+
+	sent := <-lnClient.sent
+	assert.Equal(t, "03client", sent.PeerPubkey)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":{"protocols":[0,1]},"id":"1"}`, string(sent.Data))
+
+	// JIT channels are not sold
+	request, err = json.Marshal(&lsps0.JsonRpcRequest{Jsonrpc: "2.0", Method: "lsps2.get_info", Params: json.RawMessage(`{}`), ID: "2"})
+	require.NoError(t, err)
+	m.dispatchMessage(context.Background(), lnclient.CustomMessage{PeerPubkey: "03client", Type: lsps0.LSPS_MESSAGE_TYPE_ID, Data: request})
+
+	sent = <-lnClient.sent
+	assert.Contains(t, string(sent.Data), fmt.Sprintf(`"code":%d`, lsps0.ErrorCodeMethodNotFound))
+}
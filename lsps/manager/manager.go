@@ -35,8 +35,9 @@ type LiquidityManager struct {
 
 	connectionManager *ConnectionManager
 
-	// Future service handlers
-	// lsps2Service *lsps2.ServiceHandler
+	// Service handlers, set when acting as LSP for other nodes
+	lsps0Service *lsps0.ServiceHandler
+	lsps1Service *lsps1.ServiceHandler
 
 	mu sync.RWMutex
 
@@ -101,6 +102,10 @@ func NewLiquidityManager(cfg *ManagerConfig) (*LiquidityManager, error) {
 
 	m.connectionManager = NewConnectionManager(cfg)
 
+	if err := m.startLSPService(); err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to start LSP service")
+	}
+
 	return m, nil
 }
 
@@ -132,6 +137,10 @@ func (m *LiquidityManager) pollOrders(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			if m.lsps1Service != nil {
+				m.lsps1Service.ProcessPendingOrders(ctx)
+			}
+
 			// Fetch pending orders from DB
 			orders, err := m.cfg.LSPManager.ListPendingOrders()
 			if err != nil {
@@ -308,7 +317,7 @@ func (m *LiquidityManager) consumeMessageStream(
 				if err != nil {
 					logger.Logger.Error().Err(err).Msg("Custom messages stream error")
 				}
-				m.drainMessages(msgs)
+				m.drainMessages(ctx, msgs)
 				return true
 			}
 		case msg, ok := <-msgs:
@@ -316,28 +325,28 @@ func (m *LiquidityManager) consumeMessageStream(
 				logger.Logger.Info().Msg("Custom messages stream closed")
 				return true
 			}
-			m.dispatchMessage(msg)
+			m.dispatchMessage(ctx, msg)
 		}
 	}
 }
 
 // drainMessages dispatches any messages already buffered in a closing stream so
 // that in-flight LSPS request-response pairs are not silently dropped.
-func (m *LiquidityManager) drainMessages(msgs <-chan lnclient.CustomMessage) {
+func (m *LiquidityManager) drainMessages(ctx context.Context, msgs <-chan lnclient.CustomMessage) {
 	for {
 		select {
 		case msg, ok := <-msgs:
 			if !ok {
 				return
 			}
-			m.dispatchMessage(msg)
+			m.dispatchMessage(ctx, msg)
 		default:
 			return
 		}
 	}
 }
 
-func (m *LiquidityManager) dispatchMessage(msg lnclient.CustomMessage) {
+func (m *LiquidityManager) dispatchMessage(ctx context.Context, msg lnclient.CustomMessage) {
 	// Dispatch based on message logic or try all handlers?
 	// LSPS1, LSPS2, LSPS5 share the same message type (37913 - LSPS0)
 	// They distinguish by JSON-RPC method inside the payload.
@@ -346,9 +355,8 @@ func (m *LiquidityManager) dispatchMessage(msg lnclient.CustomMessage) {
 		return
 	}
 
-	// We try to let each client handle it.
-	// Since we are strictly clients for now, we are mostly expecting RESPONSES
-	// which match by ID.
+	// We try to let each client handle it first: responses to our requests
+	// match by ID. Anything left is a request to us when acting as LSP.
 
 	// LSPS0
 	if err := m.lsps0Client.HandleMessage(msg.PeerPubkey, msg.Data); err == nil {
@@ -370,6 +378,13 @@ func (m *LiquidityManager) dispatchMessage(msg lnclient.CustomMessage) {
 		return
 	}
 
+	// Requests from our own clients
+	if m.lsps0Service != nil {
+		if err := m.lsps0Service.HandleMessage(ctx, msg.PeerPubkey, msg.Data); err == nil {
+			return
+		}
+	}
+
 	// If none claimed it, it might be a notification (request from LSP)
 	// For notifications (like lsps5.payment_incoming), they have a "method".
 	// We probably need a unified dispatcher if this gets complex.
@@ -684,6 +699,24 @@ func (m *LiquidityManager) ListLSPS1Orders() ([]persist.LSPS1Order, error) {
 	return m.cfg.LSPManager.ListAllOrders()
 }
 
+// MarkOrderAutoLiquidity flags a tracked order as bought by the
+// auto-liquidity service
+func (m *LiquidityManager) MarkOrderAutoLiquidity(orderID string) error {
+	return m.cfg.LSPManager.MarkOrderAutoLiquidity(orderID)
+}
+
+// HasPendingAutoLiquidityOrder reports whether an auto-liquidity order is
+// still waiting for its channel
+func (m *LiquidityManager) HasPendingAutoLiquidityOrder() (bool, error) {
+	return m.cfg.LSPManager.HasPendingAutoLiquidityOrder()
+}
+
+// AutoLiquidityOrderTotalSince returns the loki spent on auto-liquidity orders
+// created since the given time
+func (m *LiquidityManager) AutoLiquidityOrderTotalSince(since time.Time) (uint64, error) {
+	return m.cfg.LSPManager.AutoLiquidityOrderTotalSince(since)
+}
+
 func (m *LiquidityManager) EnsureInboundLiquidity(ctx context.Context, amountMloki uint64) (*JitChannelHints, error) {
 
 	// 1. Check current liquidity
@@ -946,6 +979,28 @@ func (m *LiquidityManager) GetLSPS1InfoList(ctx context.Context, pubkey string)
 }
 
 func (m *LiquidityManager) CreateLSPS1Order(ctx context.Context, pubkey string, orderParams lsps1.OrderParams, refundAddr *string) (*lsps1.OrderCreatedEvent, error) {
+	e, err := m.QuoteLSPS1Order(ctx, pubkey, orderParams, refundAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := ""
+	fee := uint64(0)
+	total := uint64(0)
+	if e.Payment.Bolt11 != nil {
+		invoice = e.Payment.Bolt11.Invoice
+		fee = e.Payment.Bolt11.FeeTotalLoki
+		total = e.Payment.Bolt11.OrderTotalLoki
+	}
+	// Start monitoring with persistence
+	m.MonitorOrder(pubkey, e.OrderID, invoice, fee, total, orderParams.LspBalanceLoki, orderParams.ClientBalanceLoki)
+	return e, nil
+}
+
+// QuoteLSPS1Order creates an order with the LSP without tracking it, so its
+// price can be compared with other LSPs. Orders that are never paid expire at
+// the LSP; call MonitorOrder for the one that gets paid.
+func (m *LiquidityManager) QuoteLSPS1Order(ctx context.Context, pubkey string, orderParams lsps1.OrderParams, refundAddr *string) (*lsps1.OrderCreatedEvent, error) {
 	reqID, err := m.lsps1Client.CreateOrder(ctx, pubkey, orderParams, refundAddr)
 	if err != nil {
 		return nil, err
@@ -958,16 +1013,6 @@ func (m *LiquidityManager) CreateLSPS1Order(ctx context.Context, pubkey string,
 
 	switch e := event.(type) {
 	case *lsps1.OrderCreatedEvent:
-		invoice := ""
-		fee := uint64(0)
-		total := uint64(0)
-		if e.Payment.Bolt11 != nil {
-			invoice = e.Payment.Bolt11.Invoice
-			fee = e.Payment.Bolt11.FeeTotalLoki
-			total = e.Payment.Bolt11.OrderTotalLoki
-		}
-		// Start monitoring with persistence
-		m.MonitorOrder(pubkey, e.OrderID, invoice, fee, total, orderParams.LspBalanceLoki, orderParams.ClientBalanceLoki)
 		return e, nil
 	case *lsps1.OrderRequestFailedEvent:
 		return nil, fmt.Errorf("LSP returned error: %s", e.Error)
@@ -1031,7 +1076,7 @@ func (m *LiquidityManager) StartInterceptor(ctx context.Context) {
 func (m *LiquidityManager) runChannelAcceptor(
 	ctx context.Context,
 	reqChan <-chan lnclient.ChannelAcceptRequest,
-	respond func(id string, accept bool, zeroConf bool) error,
+	respond func(id string, accept bool, zeroConf bool, rejectReason string) error,
 ) bool {
 	for {
 		select {
@@ -1042,16 +1087,18 @@ func (m *LiquidityManager) runChannelAcceptor(
 				logger.Logger.Info().Msg("Channel acceptor stream closed, resubscribing")
 				return true
 			}
-			m.handleChannelAcceptRequest(req, respond)
+			m.handleChannelAcceptRequest(ctx, req, respond)
 		}
 	}
 }
 
-// handleChannelAcceptRequest enforces the LSP zero-conf whitelist and responds
-// to an incoming channel-open request.
+// handleChannelAcceptRequest applies the channel acceptor policy and the LSP
+// zero-conf whitelist to an incoming channel-open request, responds to it and
+// records the decision.
 func (m *LiquidityManager) handleChannelAcceptRequest(
+	ctx context.Context,
 	req lnclient.ChannelAcceptRequest,
-	respond func(id string, accept bool, zeroConf bool) error,
+	respond func(id string, accept bool, zeroConf bool, rejectReason string) error,
 ) {
 	m.mu.RLock()
 	activeLSPs, err := m.getLSPsFromDB()
@@ -1072,17 +1119,29 @@ func (m *LiquidityManager) handleChannelAcceptRequest(
 		}
 	}
 
-	if whitelisted {
+	accept, rejectReason := m.evaluateChannelAcceptRequest(ctx, req, m.getChannelAcceptorPolicy(), whitelisted)
+	zeroConf := accept && whitelisted
+
+	if !accept {
+		logger.Logger.Info().Str("pubkey", req.NodePubkey).Uint64("capacity", req.Capacity).
+			Str("reason", rejectReason).Msg("Rejecting channel by channel acceptor policy")
+		if err := respond(req.ID, false, false, rejectReason); err != nil {
+			logger.Logger.Error().Err(err).Str("pubkey", req.NodePubkey).
+				Msg("Failed to send channel reject response")
+		}
+	} else if zeroConf {
 		logger.Logger.Info().Str("pubkey", req.NodePubkey).Msg("Accepting ZeroConf channel from trusted LSP")
-		if err := respond(req.ID, true, true); err != nil {
+		if err := respond(req.ID, true, true, ""); err != nil {
 			logger.Logger.Error().Err(err).Str("pubkey", req.NodePubkey).
 				Msg("Failed to send ZeroConf channel accept response")
 		}
 	} else {
 		logger.Logger.Info().Str("pubkey", req.NodePubkey).Msg("Standard accept for channel from untrusted peer")
-		if err := respond(req.ID, true, false); err != nil {
+		if err := respond(req.ID, true, false, ""); err != nil {
 			logger.Logger.Error().Err(err).Str("pubkey", req.NodePubkey).
 				Msg("Failed to send standard channel accept response")
 		}
 	}
+
+	m.recordChannelAcceptDecision(req, accept, zeroConf, rejectReason)
 }
@@ -18,6 +18,8 @@ func NewLSPManager(db *gorm.DB) *LSPManager {
 	m := &LSPManager{db: db}
 	_ = db.AutoMigrate(&persist.LSP{})
 	_ = db.AutoMigrate(&persist.LSPS1Order{})
+	_ = db.AutoMigrate(&persist.ChannelAcceptDecision{})
+	_ = db.AutoMigrate(&persist.LSPS1ServiceOrder{})
 	_ = m.CleanupInvalidLSPs()
 	return m
 }
@@ -175,6 +177,47 @@ func (m *LSPManager) SyncSystemLSPs(inputs []CommunityLSPInput) error {
 	return nil
 }
 
+// Channel Acceptor Decisions
+
+// channelAcceptDecisionRetention is how long channel acceptor decisions are
+// kept, so a peer flooding the node with requests cannot grow the log forever.
+const channelAcceptDecisionRetention = 30 * 24 * time.Hour
+
+// RecordChannelAcceptDecision saves a channel acceptor decision and prunes
+// decisions past their retention.
+func (m *LSPManager) RecordChannelAcceptDecision(decision *persist.ChannelAcceptDecision) error {
+	if err := m.db.Create(decision).Error; err != nil {
+		return err
+	}
+	return m.db.Where("created_at < ?", time.Now().Add(-channelAcceptDecisionRetention)).
+		Delete(&persist.ChannelAcceptDecision{}).Error
+}
+
+// ListChannelAcceptDecisions returns channel acceptor decisions, newest
+// first, optionally only accepted or rejected ones, and their total count.
+// A limit of 0 returns all of them.
+func (m *LSPManager) ListChannelAcceptDecisions(accepted *bool, limit, offset int) ([]persist.ChannelAcceptDecision, int64, error) {
+	query := m.db.Model(&persist.ChannelAcceptDecision{})
+	if accepted != nil {
+		query = query.Where("accepted = ?", *accepted)
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_at DESC, id DESC").Offset(offset)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	decisions := []persist.ChannelAcceptDecision{}
+	if err := query.Find(&decisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return decisions, totalCount, nil
+}
+
 // LSPS1 Order Persistence
 
 // CreateOrder saves a new LSPS1 order
@@ -222,20 +265,45 @@ func (m *LSPManager) ListAllOrders() ([]persist.LSPS1Order, error) {
 	return orders, err
 }
 
+// terminalOrderStates are the order states that don't need tracking anymore
+var terminalOrderStates = []string{"COMPLETED", "FAILED", "CANCELLED", "CLOSED", "EXPIRED"}
+
 // ListPendingOrders returns orders that are not in a terminal state
 func (m *LSPManager) ListPendingOrders() ([]persist.LSPS1Order, error) {
 	var orders []persist.LSPS1Order
-	// Define terminal states that don't need tracking.
-	// We might want to keep checking "CREATED" logic.
-	// Typically, terminal states are: "COMPLETED", "FAILED", "CANCELLED", "CLOSED" (from previous logic)
-	// We filter where state NOT IN (...)
-	terminalStates := []string{"COMPLETED", "FAILED", "CANCELLED", "CLOSED", "EXPIRED"}
-	if err := m.db.Where("state NOT IN ?", terminalStates).Find(&orders).Error; err != nil {
+	if err := m.db.Where("state NOT IN ?", terminalOrderStates).Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
+// MarkOrderAutoLiquidity flags an order as bought by the auto-liquidity service
+func (m *LSPManager) MarkOrderAutoLiquidity(orderID string) error {
+	return m.db.Model(&persist.LSPS1Order{}).Where("order_id = ?", orderID).Update("auto_liquidity", true).Error
+}
+
+// HasPendingAutoLiquidityOrder reports whether an auto-liquidity order is
+// still waiting for its channel
+func (m *LSPManager) HasPendingAutoLiquidityOrder() (bool, error) {
+	var count int64
+	err := m.db.Model(&persist.LSPS1Order{}).
+		Where("auto_liquidity = ? AND state NOT IN ?", true, terminalOrderStates).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// AutoLiquidityOrderTotalSince sums the totals in loki of the auto-liquidity
+// orders created since the given time. Failed orders were either never paid
+// or get refunded by the LSP, so they are left out.
+func (m *LSPManager) AutoLiquidityOrderTotalSince(since time.Time) (uint64, error) {
+	var total uint64
+	err := m.db.Model(&persist.LSPS1Order{}).
+		Select("COALESCE(SUM(order_total), 0)").
+		Where("auto_liquidity = ? AND state != ? AND created_at >= ?", true, "FAILED", since).
+		Scan(&total).Error
+	return total, err
+}
+
 // DeleteOrder removes an order (used if we want to clean up old ones or purely temporary)
 func (m *LSPManager) DeleteOrder(orderID string) error {
 	return m.db.Delete(&persist.LSPS1Order{}, "order_id = ?", orderID).Error
@@ -86,3 +86,45 @@ func TestLSPS1OrderPersistence(t *testing.T) {
 	_, err = manager.GetOrder("order_123")
 	assert.Error(t, err) // Should not find
 }
+
+func TestAutoLiquidityOrders(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	assert.NoError(t, err)
+	manager := NewLSPManager(db)
+
+	now := time.Now()
+	orders := []*persist.LSPS1Order{
+		{OrderID: "manual", State: "COMPLETED", OrderTotal: 1_000, CreatedAt: now},
+		{OrderID: "auto_old", State: "COMPLETED", OrderTotal: 2_000, CreatedAt: now.Add(-48 * time.Hour)},
+		{OrderID: "auto_failed", State: "FAILED", OrderTotal: 4_000, CreatedAt: now},
+		{OrderID: "auto_pending", State: "CREATED", OrderTotal: 8_000, CreatedAt: now},
+	}
+	for _, order := range orders {
+		assert.NoError(t, manager.CreateOrder(order))
+	}
+
+	pending, err := manager.HasPendingAutoLiquidityOrder()
+	assert.NoError(t, err)
+	assert.False(t, pending)
+
+	for _, orderID := range []string{"auto_old", "auto_failed", "auto_pending"} {
+		assert.NoError(t, manager.MarkOrderAutoLiquidity(orderID))
+	}
+
+	pending, err = manager.HasPendingAutoLiquidityOrder()
+	assert.NoError(t, err)
+	assert.True(t, pending)
+
+	total, err := manager.AutoLiquidityOrderTotalSince(now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8_000), total)
+
+	total, err = manager.AutoLiquidityOrderTotalSince(now.Add(-72 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10_000), total)
+
+	assert.NoError(t, manager.UpdateOrderState("auto_pending", "COMPLETED"))
+	pending, err = manager.HasPendingAutoLiquidityOrder()
+	assert.NoError(t, err)
+	assert.False(t, pending)
+}
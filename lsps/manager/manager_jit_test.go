@@ -59,15 +59,21 @@ func (m *mockLNClientJIT) GetBalances(ctx context.Context, includeInactiveChanne
 }
 
 // Stubs
-func (m *mockLNClientJIT) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (m *mockLNClientJIT) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	return nil, nil, nil
 }
-func (m *mockLNClientJIT) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
+func (m *mockLNClientJIT) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
 	return nil, nil
 }
-func (m *mockLNClientJIT) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
+func (m *mockLNClientJIT) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
 	return nil, nil
 }
+func (m *mockLNClientJIT) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) MakeOffer(ctx context.Context, description string) (string, error) {
+	return "", nil
+}
 func (m *mockLNClientJIT) GetPubkey() string                                       { return "" }
 func (m *mockLNClientJIT) GetInfo(ctx context.Context) (*lnclient.NodeInfo, error) { return nil, nil }
 func (m *mockLNClientJIT) MakeInvoice(ctx context.Context, amount int64, description string, descriptionHash string, expiry int64, throughNodePubkey *string, lspJitChannelSCID *string, lspCltvExpiryDelta *uint16, lspFeeBaseMloki *uint64, lspFeeProportionalMillionths *uint32) (*lnclient.Transaction, error) {
@@ -89,6 +95,39 @@ func (m *mockLNClientJIT) ListTransactions(ctx context.Context, from, until, lim
 func (m *mockLNClientJIT) ListOnchainTransactions(ctx context.Context, from, until, limit, offset uint64) ([]lnclient.OnchainTransaction, error) {
 	return nil, nil
 }
+func (m *mockLNClientJIT) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) PublishTransaction(ctx context.Context, rawTx string, label string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClientJIT) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClientJIT) FreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClientJIT) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClientJIT) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	return nil, nil
+}
 func (m *mockLNClientJIT) Shutdown() error { return nil }
 func (m *mockLNClientJIT) ListChannels(ctx context.Context) ([]lnclient.Channel, error) {
 	return nil, nil
@@ -120,9 +159,11 @@ func (m *mockLNClientJIT) GetOnchainBalance(ctx context.Context) (*lnclient.Onch
 func (m *mockLNClientJIT) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (string, error) {
 	return "", nil
 }
-func (m *mockLNClientJIT) SendPaymentProbes(ctx context.Context, invoice string) error { return nil }
-func (m *mockLNClientJIT) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
-	return nil
+func (m *mockLNClientJIT) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
+	return nil, nil
+}
+func (m *mockLNClientJIT) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
+	return nil, nil
 }
 func (m *mockLNClientJIT) ListPeers(ctx context.Context) ([]lnclient.PeerDetails, error) {
 	return nil, nil
@@ -91,7 +91,7 @@ func TestDrainMessages_EmptiesBuffer(t *testing.T) {
 	msgs <- lnclient.CustomMessage{Type: 0}
 	close(msgs)
 
-	m.drainMessages(msgs)
+	m.drainMessages(context.Background(), msgs)
 
 	assert.Equal(t, 0, len(msgs))
 }
@@ -105,7 +105,7 @@ func TestDrainMessages_StopsAtDefault(t *testing.T) {
 
 	done := make(chan struct{})
 	go func() {
-		m.drainMessages(msgs)
+		m.drainMessages(context.Background(), msgs)
 		close(done)
 	}()
 
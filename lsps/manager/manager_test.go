@@ -21,13 +21,14 @@ import (
 // Mock LNClient for Manager tests
 type mockLNClient struct {
 	acceptorChan      chan lnclient.ChannelAcceptRequest
-	respondFunc       func(id string, accept bool, zeroConf bool) error
+	respondFunc       func(id string, accept bool, zeroConf bool, rejectReason string) error
 	subscribeErr      error
 	subscribeCalled   bool
 	subscribeCallback func() // optional hook to check calls // Fixed typo 'checking calls'
+	channels          []lnclient.Channel
 }
 
-func (m *mockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (m *mockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	m.subscribeCalled = true
 	if m.subscribeCallback != nil {
 		m.subscribeCallback()
@@ -47,12 +48,18 @@ func (m *mockLNClient) SubscribeCustomMessages(ctx context.Context) (<-chan lncl
 }
 
 // Stub out required methods to satisfy interface
-func (m *mockLNClient) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
+func (m *mockLNClient) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
 	return nil, nil
 }
-func (m *mockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
+func (m *mockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
 	return nil, nil
 }
+func (m *mockLNClient) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) MakeOffer(ctx context.Context, description string) (string, error) {
+	return "", nil
+}
 func (m *mockLNClient) GetPubkey() string                                       { return "" }
 func (m *mockLNClient) GetInfo(ctx context.Context) (*lnclient.NodeInfo, error) { return nil, nil }
 func (m *mockLNClient) MakeInvoice(ctx context.Context, amount int64, description string, descriptionHash string, expiry int64, throughNodePubkey *string, lspJitChannelSCID *string, lspCltvExpiryDelta *uint16, lspFeeBaseMloki *uint64, lspFeeProportionalMillionths *uint32) (*lnclient.Transaction, error) {
@@ -72,8 +79,43 @@ func (m *mockLNClient) ListTransactions(ctx context.Context, from, until, limit,
 func (m *mockLNClient) ListOnchainTransactions(ctx context.Context, from, until, limit, offset uint64) ([]lnclient.OnchainTransaction, error) {
 	return nil, nil
 }
-func (m *mockLNClient) Shutdown() error                                              { return nil }
-func (m *mockLNClient) ListChannels(ctx context.Context) ([]lnclient.Channel, error) { return nil, nil }
+func (m *mockLNClient) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) PublishTransaction(ctx context.Context, rawTx string, label string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClient) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClient) FreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClient) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClient) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	return nil, nil
+}
+func (m *mockLNClient) Shutdown() error { return nil }
+func (m *mockLNClient) ListChannels(ctx context.Context) ([]lnclient.Channel, error) {
+	return m.channels, nil
+}
 func (m *mockLNClient) GetNodeConnectionInfo(ctx context.Context) (*lnclient.NodeConnectionInfo, error) {
 	return nil, nil
 }
@@ -104,9 +146,11 @@ func (m *mockLNClient) GetBalances(ctx context.Context, includeInactiveChannels
 func (m *mockLNClient) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (string, error) {
 	return "", nil
 }
-func (m *mockLNClient) SendPaymentProbes(ctx context.Context, invoice string) error { return nil }
-func (m *mockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
-	return nil
+func (m *mockLNClient) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
+	return nil, nil
 }
 func (m *mockLNClient) ListPeers(ctx context.Context) ([]lnclient.PeerDetails, error) {
 	return nil, nil
@@ -142,7 +186,7 @@ func TestLiquidityManager_StartInterceptor(t *testing.T) {
 		accept   bool
 		zeroConf bool
 	})
-	mockLN.respondFunc = func(id string, accept bool, zeroConf bool) error {
+	mockLN.respondFunc = func(id string, accept bool, zeroConf bool, rejectReason string) error {
 		responsesMu.Lock()
 		receivedResponses[id] = struct {
 			accept   bool
@@ -307,10 +351,10 @@ func TestLiquidityManager_StartInterceptor(t *testing.T) {
 type mockInterceptorClient struct {
 	mockLNClient
 	calls    atomic.Int32
-	builders []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error)
+	builders []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error)
 }
 
-func (m *mockInterceptorClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (m *mockInterceptorClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	idx := int(m.calls.Add(1)) - 1
 	if idx < len(m.builders) {
 		return m.builders[idx]()
@@ -318,7 +362,7 @@ func (m *mockInterceptorClient) SubscribeChannelAcceptor(ctx context.Context) (<
 	// Default: block until context cancelled.
 	ch := make(chan lnclient.ChannelAcceptRequest)
 	go func() { <-ctx.Done(); close(ch) }()
-	return ch, func(string, bool, bool) error { return nil }, nil
+	return ch, func(string, bool, bool, string) error { return nil }, nil
 }
 
 func newInterceptorManager(t *testing.T, client lnclient.LNClient) *LiquidityManager {
@@ -342,14 +386,14 @@ func newInterceptorManager(t *testing.T, client lnclient.LNClient) *LiquidityMan
 
 func TestStartInterceptor_RetriesOnInitialFailure(t *testing.T) {
 	openCh := make(chan lnclient.ChannelAcceptRequest)
-	respond := func(string, bool, bool) error { return nil }
+	respond := func(string, bool, bool, string) error { return nil }
 
 	client := &mockInterceptorClient{
-		builders: []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error){
-			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error) {
+		builders: []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error){
+			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error) {
 				return nil, nil, fmt.Errorf("node not ready yet")
 			},
-			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error) {
+			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error) {
 				return openCh, respond, nil
 			},
 		},
@@ -373,14 +417,14 @@ func TestStartInterceptor_ReconnectsAfterStreamClose(t *testing.T) {
 	close(closedCh)
 
 	openCh := make(chan lnclient.ChannelAcceptRequest)
-	respond := func(string, bool, bool) error { return nil }
+	respond := func(string, bool, bool, string) error { return nil }
 
 	client := &mockInterceptorClient{
-		builders: []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error){
-			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error) {
+		builders: []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error){
+			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error) {
 				return closedCh, respond, nil
 			},
-			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error) {
+			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error) {
 				return openCh, respond, nil
 			},
 		},
@@ -401,9 +445,9 @@ func TestStartInterceptor_StopsOnContextCancel(t *testing.T) {
 	blockCh := make(chan lnclient.ChannelAcceptRequest)
 
 	client := &mockInterceptorClient{
-		builders: []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error){
-			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error) {
-				return blockCh, func(string, bool, bool) error { return nil }, nil
+		builders: []func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error){
+			func() (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error) {
+				return blockCh, func(string, bool, bool, string) error { return nil }, nil
 			},
 		},
 	}
@@ -440,7 +484,7 @@ func TestHandleChannelAcceptRequest_LogsRespondError(t *testing.T) {
 		nostrPubkeys: make(map[string]string),
 	}
 
-	respond := func(_ string, _ bool, _ bool) error {
+	respond := func(_ string, _ bool, _ bool, _ string) error {
 		return fmt.Errorf("grpc: connection closed")
 	}
 
@@ -448,7 +492,7 @@ func TestHandleChannelAcceptRequest_LogsRespondError(t *testing.T) {
 
 	// Must not panic even when respond() returns an error.
 	assert.NotPanics(t, func() {
-		m.handleChannelAcceptRequest(req, respond)
+		m.handleChannelAcceptRequest(context.Background(), req, respond)
 	})
 }
 
@@ -476,7 +520,7 @@ func TestHandleChannelAcceptRequest_DBErrorAcceptsWithoutZeroConf(t *testing.T)
 	}
 
 	var respondedAccept, respondedZeroConf bool
-	respond := func(_ string, accept bool, zeroConf bool) error {
+	respond := func(_ string, accept bool, zeroConf bool, _ string) error {
 		respondedAccept = accept
 		respondedZeroConf = zeroConf
 		return nil
@@ -487,7 +531,7 @@ func TestHandleChannelAcceptRequest_DBErrorAcceptsWithoutZeroConf(t *testing.T)
 		NodePubkey: "03aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
 	}
 
-	m.handleChannelAcceptRequest(req, respond)
+	m.handleChannelAcceptRequest(context.Background(), req, respond)
 
 	assert.True(t, respondedAccept, "channel must be accepted even when DB errors (fail-safe)")
 	assert.False(t, respondedZeroConf, "ZeroConf must be denied when whitelist cannot be consulted due to DB error")
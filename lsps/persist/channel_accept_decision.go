@@ -0,0 +1,23 @@
+package persist
+
+import (
+	"time"
+)
+
+// ChannelAcceptDecision records how the channel acceptor answered an inbound
+// channel request. Reason is the reason sent to the peer on rejection.
+type ChannelAcceptDecision struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	PeerPubkey string    `gorm:"index" json:"peerPubkey"`
+	Capacity   uint64    `json:"capacity"` // loki
+	Private    bool      `json:"private"`
+	Accepted   bool      `json:"accepted"`
+	ZeroConf   bool      `json:"zeroConf"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `gorm:"index" json:"createdAt"`
+}
+
+// TableName overrides the table name to 'channel_accept_decisions'
+func (ChannelAcceptDecision) TableName() string {
+	return "channel_accept_decisions"
+}
@@ -16,7 +16,8 @@ type LSPS1Order struct {
 	ClientBalance  uint64    `json:"client_balance_loki"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
-	ExpiresAt      time.Time `json:"expires_at"` // Optional connection to channel expiry or payment expiry
+	ExpiresAt      time.Time `json:"expires_at"`                  // Optional connection to channel expiry or payment expiry
+	AutoLiquidity  bool      `gorm:"index" json:"auto_liquidity"` // bought by the auto-liquidity service
 }
 
 // TableName overrides the table name to 'lsps1_orders'
@@ -0,0 +1,123 @@
+package persist
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrServiceRecordNotFound is returned when a service order does not exist
+// for the requesting client
+var ErrServiceRecordNotFound = errors.New("record not found")
+
+// LSPS1ServiceOrder is a channel order placed with this node acting as LSP.
+// Balances and fees are in loki.
+type LSPS1ServiceOrder struct {
+	OrderID                      string     `gorm:"primaryKey" json:"order_id"`
+	ClientPubkey                 string     `gorm:"index" json:"client_pubkey"`
+	State                        string     `gorm:"index" json:"state"`  // CREATED, OPENING, COMPLETED, FAILED
+	PaymentState                 string     `json:"payment_state"`       // EXPECT_PAYMENT, PAID, REFUNDED
+	LSPBalance                   uint64     `json:"lsp_balance_loki"`    // channel capacity funded by us
+	ClientBalance                uint64     `json:"client_balance_loki"` // always 0, pushing funds is not supported
+	RequiredChannelConfirmations uint16     `json:"required_channel_confirmations"`
+	FundingConfirmsWithinBlocks  uint16     `json:"funding_confirms_within_blocks"`
+	ChannelExpiryBlocks          uint32     `json:"channel_expiry_blocks"`
+	AnnounceChannel              bool       `json:"announce_channel"`
+	Token                        string     `json:"token"`
+	RefundOnchainAddress         string     `json:"refund_onchain_address"`
+	FeeTotal                     uint64     `json:"fee_total"`
+	OrderTotal                   uint64     `json:"order_total"`
+	PaymentInvoice               string     `json:"payment_invoice"`
+	PaymentHash                  string     `gorm:"index" json:"payment_hash"`
+	PaymentExpiresAt             time.Time  `json:"payment_expires_at"`
+	FundingOutpoint              string     `json:"funding_outpoint"`
+	FundedAt                     *time.Time `json:"funded_at"`
+	ChannelExpiresAt             *time.Time `json:"channel_expires_at"`
+	FailureReason                string     `json:"failure_reason"`
+	CreatedAt                    time.Time  `json:"created_at"`
+	UpdatedAt                    time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name to 'lsps1_service_orders'
+func (LSPS1ServiceOrder) TableName() string {
+	return "lsps1_service_orders"
+}
+
+// GormServiceStore persists the state of the LSPS1 service
+type GormServiceStore struct {
+	db *gorm.DB
+}
+
+func NewGormServiceStore(db *gorm.DB) *GormServiceStore {
+	return &GormServiceStore{db: db}
+}
+
+func (s *GormServiceStore) CreateLSPS1Order(order *LSPS1ServiceOrder) error {
+	if err := s.db.Create(order).Error; err != nil {
+		return fmt.Errorf("failed to create order %s: %w", order.OrderID, err)
+	}
+	return nil
+}
+
+// GetLSPS1Order returns the order with the given ID placed by clientPubkey
+func (s *GormServiceStore) GetLSPS1Order(clientPubkey, orderID string) (*LSPS1ServiceOrder, error) {
+	var order LSPS1ServiceOrder
+	result := s.db.Where("order_id = ? AND client_pubkey = ?", orderID, clientPubkey).Limit(1).Find(&order)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get order %s: %w", orderID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrServiceRecordNotFound
+	}
+	return &order, nil
+}
+
+func (s *GormServiceStore) UpdateLSPS1Order(order *LSPS1ServiceOrder) error {
+	if err := s.db.Save(order).Error; err != nil {
+		return fmt.Errorf("failed to update order %s: %w", order.OrderID, err)
+	}
+	return nil
+}
+
+// ListLSPS1OrdersByState returns the orders in the given state, oldest first
+func (s *GormServiceStore) ListLSPS1OrdersByState(state string) ([]LSPS1ServiceOrder, error) {
+	var orders []LSPS1ServiceOrder
+	if err := s.db.Where("state = ?", state).Order("created_at ASC").Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return orders, nil
+}
+
+// ListLSPS1OrdersToRefund returns the paid orders whose channel failed to
+// open, or may not have been opened, oldest first
+func (s *GormServiceStore) ListLSPS1OrdersToRefund() ([]LSPS1ServiceOrder, error) {
+	orders := []LSPS1ServiceOrder{}
+	if err := s.db.Where("payment_state = ? AND state IN ?", "PAID", []string{"FAILED", "OPENING"}).Order("created_at ASC").Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to list orders to refund: %w", err)
+	}
+	return orders, nil
+}
+
+// MarkLSPS1OrderRefunded records that the payment of an order listed by
+// ListLSPS1OrdersToRefund was returned to the client
+func (s *GormServiceStore) MarkLSPS1OrderRefunded(orderID string) (*LSPS1ServiceOrder, error) {
+	var order LSPS1ServiceOrder
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("order_id = ? AND payment_state = ? AND state IN ?", orderID, "PAID", []string{"FAILED", "OPENING"}).Limit(1).Find(&order)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrServiceRecordNotFound
+		}
+		order.State = "FAILED"
+		order.PaymentState = "REFUNDED"
+		return tx.Save(&order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
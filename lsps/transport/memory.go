@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemoryTransport implements Transport between two in-process peers. It lets
+// client and service handlers talk to each other without a Lightning node.
+type MemoryTransport struct {
+	pubkey   string
+	peer     *MemoryTransport
+	messages chan CustomMessage
+	errs     chan error
+	closed   bool
+	mu       sync.RWMutex
+}
+
+// NewMemoryTransportPair creates two connected transports for the nodes with
+// the given pubkeys. Messages sent by one are received by the other.
+func NewMemoryTransportPair(pubkeyA, pubkeyB string) (*MemoryTransport, *MemoryTransport) {
+	a := &MemoryTransport{
+		pubkey:   pubkeyA,
+		messages: make(chan CustomMessage, 100),
+		errs:     make(chan error),
+	}
+	b := &MemoryTransport{
+		pubkey:   pubkeyB,
+		messages: make(chan CustomMessage, 100),
+		errs:     make(chan error),
+	}
+	a.peer = b
+	b.peer = a
+	return a, b
+}
+
+// SendCustomMessage delivers a message to the paired transport
+func (t *MemoryTransport) SendCustomMessage(ctx context.Context, peerPubkey string, msgType uint32, data []byte) error {
+	if peerPubkey != t.peer.pubkey {
+		return fmt.Errorf("peer not connected: %s", peerPubkey)
+	}
+	if len(data) > 65535 {
+		return fmt.Errorf("message too large: %d bytes (max 65535)", len(data))
+	}
+
+	t.peer.mu.RLock()
+	defer t.peer.mu.RUnlock()
+	if t.peer.closed {
+		return fmt.Errorf("peer disconnected: %s", peerPubkey)
+	}
+
+	msg := CustomMessage{
+		PeerPubkey: t.pubkey,
+		Type:       msgType,
+		Data:       append([]byte(nil), data...),
+	}
+	select {
+	case t.peer.messages <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SubscribeCustomMessages returns the messages sent by the paired transport
+func (t *MemoryTransport) SubscribeCustomMessages(ctx context.Context) (<-chan CustomMessage, <-chan error, error) {
+	return t.messages, t.errs, nil
+}
+
+// Close stops delivering messages to this transport and closes its stream
+func (t *MemoryTransport) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.closed {
+		t.closed = true
+		close(t.messages)
+	}
+}
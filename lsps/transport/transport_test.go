@@ -47,12 +47,18 @@ func (m *mockLNClient) SubscribeCustomMessages(ctx context.Context) (<-chan lncl
 }
 
 // Implement other LNClient methods as no-ops
-func (m *mockLNClient) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
+func (m *mockLNClient) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
 	return nil, nil
 }
-func (m *mockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
+func (m *mockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
 	return nil, nil
 }
+func (m *mockLNClient) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) MakeOffer(ctx context.Context, description string) (string, error) {
+	return "", nil
+}
 func (m *mockLNClient) GetPubkey() string { return "" }
 func (m *mockLNClient) GetInfo(ctx context.Context) (*lnclient.NodeInfo, error) {
 	return nil, nil
@@ -76,6 +82,39 @@ func (m *mockLNClient) ListTransactions(ctx context.Context, from, until, limit,
 func (m *mockLNClient) ListOnchainTransactions(ctx context.Context, from, until, limit, offset uint64) ([]lnclient.OnchainTransaction, error) {
 	return nil, nil
 }
+func (m *mockLNClient) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) PublishTransaction(ctx context.Context, rawTx string, label string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClient) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClient) FreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (m *mockLNClient) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error) {
+	return "", nil
+}
+func (m *mockLNClient) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	return nil, nil
+}
+func (m *mockLNClient) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	return nil, nil
+}
 func (m *mockLNClient) Shutdown() error { return nil }
 func (m *mockLNClient) ListChannels(ctx context.Context) ([]lnclient.Channel, error) {
 	return nil, nil
@@ -112,9 +151,11 @@ func (m *mockLNClient) GetBalances(ctx context.Context, includeInactiveChannels
 func (m *mockLNClient) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (string, error) {
 	return "", nil
 }
-func (m *mockLNClient) SendPaymentProbes(ctx context.Context, invoice string) error { return nil }
-func (m *mockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
-	return nil
+func (m *mockLNClient) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
+	return nil, nil
+}
+func (m *mockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
+	return nil, nil
 }
 func (m *mockLNClient) ListPeers(ctx context.Context) ([]lnclient.PeerDetails, error) {
 	return nil, nil
@@ -139,7 +180,7 @@ func (m *mockLNClient) ExecuteCustomNodeCommand(ctx context.Context, command *ln
 	return nil, nil
 }
 
-func (m *mockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (m *mockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	return nil, nil, nil
 }
 
@@ -216,3 +257,44 @@ func TestFLNDTransport_SubscribeCustomMessages(t *testing.T) {
 
 	cancel()
 }
+
+func TestMemoryTransportPair(t *testing.T) {
+	a, b := NewMemoryTransportPair("03aaaa", "03bbbb")
+	ctx := context.Background()
+
+	msgChan, _, err := b.SubscribeCustomMessages(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeCustomMessages failed: %v", err)
+	}
+
+	if err := a.SendCustomMessage(ctx, "03bbbb", 37913, []byte("hello")); err != nil {
+		t.Fatalf("SendCustomMessage failed: %v", err)
+	}
+
+	select {
+	case msg := <-msgChan:
+		if msg.PeerPubkey != "03aaaa" {
+			t.Errorf("Expected peerPubkey 03aaaa, got %s", msg.PeerPubkey)
+		}
+		if msg.Type != 37913 {
+			t.Errorf("Expected type 37913, got %d", msg.Type)
+		}
+		if string(msg.Data) != "hello" {
+			t.Errorf("Expected data hello, got %s", string(msg.Data))
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timeout waiting for message")
+	}
+
+	if err := a.SendCustomMessage(ctx, "03cccc", 37913, []byte("hello")); err == nil {
+		t.Error("Expected error sending to a peer that is not connected")
+	}
+
+	b.Close()
+	if err := a.SendCustomMessage(ctx, "03bbbb", 37913, []byte("hello")); err == nil {
+		t.Error("Expected error sending to a closed transport")
+	}
+	if _, ok := <-msgChan; ok {
+		t.Error("Expected message stream to be closed")
+	}
+}
@@ -0,0 +1,345 @@
+// Package metrics exposes the state of the hub and its node in the Prometheus
+// text format. Request, payment and forwarding counters are fed by hub events;
+// balances, channels, relays, swaps, LSPS orders and table sizes are read on
+// every scrape.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/lsps/persist"
+	"github.com/flokiorg/lokihub/nip47/models"
+	"github.com/flokiorg/lokihub/service"
+)
+
+const (
+	namespace = "lokihub"
+	// scrapeTimeout bounds the node and database reads of a single scrape
+	scrapeTimeout = 10 * time.Second
+)
+
+const (
+	CHANNEL_STATE_ACTIVE   = "active"
+	CHANNEL_STATE_INACTIVE = "inactive"
+	CHANNEL_STATE_PENDING  = "pending"
+)
+
+// unknownMethod is the method label of requests for a method the hub does not
+// know. Methods are chosen by the client, so they cannot be used as labels
+// as-is without letting any app create unbounded series.
+const unknownMethod = "unknown"
+
+var nip47Methods = map[string]struct{}{
+	models.PAY_INVOICE_METHOD:         {},
+	models.GET_BALANCE_METHOD:         {},
+	models.GET_BUDGET_METHOD:          {},
+	models.GET_INFO_METHOD:            {},
+	models.MAKE_INVOICE_METHOD:        {},
+	models.LOOKUP_INVOICE_METHOD:      {},
+	models.LIST_TRANSACTIONS_METHOD:   {},
+	models.PAY_KEYSEND_METHOD:         {},
+	models.MULTI_PAY_INVOICE_METHOD:   {},
+	models.MULTI_PAY_KEYSEND_METHOD:   {},
+	models.SIGN_MESSAGE_METHOD:        {},
+	models.CREATE_CONNECTION_METHOD:   {},
+	models.MAKE_HOLD_INVOICE_METHOD:   {},
+	models.CANCEL_HOLD_INVOICE_METHOD: {},
+	models.SETTLE_HOLD_INVOICE_METHOD: {},
+	models.MAKE_OFFER_METHOD:          {},
+	models.PAY_OFFER_METHOD:           {},
+	models.LOOKUP_OFFER_METHOD:        {},
+}
+
+func methodLabel(method string) string {
+	if _, ok := nip47Methods[method]; ok {
+		return method
+	}
+	return unknownMethod
+}
+
+type MetricsService interface {
+	events.EventSubscriber
+	Handler() http.Handler
+}
+
+type metricsService struct {
+	svc      service.Service
+	registry *prometheus.Registry
+
+	nip47Requests        *prometheus.CounterVec
+	nip47RequestDuration *prometheus.HistogramVec
+	payments             *prometheus.CounterVec
+	paymentFees          prometheus.Counter
+	forwards             prometheus.Counter
+	forwardingFees       prometheus.Counter
+}
+
+func NewMetricsService(svc service.Service) *metricsService {
+	metricsSvc := &metricsService{
+		svc:      svc,
+		registry: prometheus.NewRegistry(),
+		nip47Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "nip47_requests_total",
+			Help:      "NIP-47 responses sent, by request method and error code (empty on success).",
+		}, []string{"method", "code"}),
+		nip47RequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "nip47_request_duration_seconds",
+			Help:      "Time from receiving a NIP-47 request to sending its response.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"method", "code"}),
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payments_total",
+			Help:      "Outgoing payments, by result.",
+		}, []string{"result"}),
+		paymentFees: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payment_fees_mloki_total",
+			Help:      "Routing fees paid for successful outgoing payments.",
+		}),
+		forwards: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "forwards_total",
+			Help:      "Payments forwarded by the node.",
+		}),
+		forwardingFees: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "forwarding_fees_mloki_total",
+			Help:      "Fees earned from forwarded payments.",
+		}),
+	}
+
+	metricsSvc.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metricsSvc.nip47Requests,
+		metricsSvc.nip47RequestDuration,
+		metricsSvc.payments,
+		metricsSvc.paymentFees,
+		metricsSvc.forwards,
+		metricsSvc.forwardingFees,
+		&hubCollector{svc: svc},
+	)
+	return metricsSvc
+}
+
+func (metricsSvc *metricsService) Handler() http.Handler {
+	return promhttp.HandlerFor(metricsSvc.registry, promhttp.HandlerOpts{})
+}
+
+func (metricsSvc *metricsService) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
+	switch event.Event {
+	case "nwc_request_handled":
+		properties, ok := event.Properties.(*models.RequestHandledEventProperties)
+		if !ok {
+			logger.Logger.Error().Interface("event", event).Msg("Failed to cast event")
+			return
+		}
+		method := methodLabel(properties.Method)
+		metricsSvc.nip47Requests.WithLabelValues(method, properties.Code).Inc()
+		metricsSvc.nip47RequestDuration.WithLabelValues(method, properties.Code).
+			Observe(float64(properties.DurationMs) / 1000)
+	case "nwc_payment_sent":
+		transaction, ok := event.Properties.(*db.Transaction)
+		if !ok {
+			logger.Logger.Error().Interface("event", event).Msg("Failed to cast event")
+			return
+		}
+		metricsSvc.payments.WithLabelValues("succeeded").Inc()
+		metricsSvc.paymentFees.Add(float64(transaction.FeeMloki))
+	case "nwc_payment_failed":
+		metricsSvc.payments.WithLabelValues("failed").Inc()
+	case "nwc_payment_forwarded":
+		properties, ok := event.Properties.(*lnclient.PaymentForwardedEventProperties)
+		if !ok {
+			logger.Logger.Error().Interface("event", event).Msg("Failed to cast event")
+			return
+		}
+		metricsSvc.forwards.Inc()
+		metricsSvc.forwardingFees.Add(float64(properties.TotalFeeEarnedMloki))
+	}
+}
+
+var (
+	onchainBalanceDesc = prometheus.NewDesc(
+		namespace+"_onchain_balance_loki",
+		"On-chain wallet balance, by kind (spendable, total, reserved).",
+		[]string{"kind"}, nil)
+	lightningBalanceDesc = prometheus.NewDesc(
+		namespace+"_lightning_balance_mloki",
+		"Lightning balance across active channels, by kind (spendable, receivable).",
+		[]string{"kind"}, nil)
+	channelsDesc = prometheus.NewDesc(
+		namespace+"_channels",
+		"Channels of the node, by state (active, inactive, pending).",
+		[]string{"state"}, nil)
+	relayConnectedDesc = prometheus.NewDesc(
+		namespace+"_relay_connected",
+		"Whether the hub is connected to a Nostr relay (1) or not (0).",
+		[]string{"url"}, nil)
+	swapsDesc = prometheus.NewDesc(
+		namespace+"_swaps",
+		"Swaps, by type and state.",
+		[]string{"type", "state"}, nil)
+	lsps1OrdersDesc = prometheus.NewDesc(
+		namespace+"_lsps1_orders",
+		"LSPS1 channel orders, by role (client, service) and state.",
+		[]string{"role", "state"}, nil)
+	dbRowsDesc = prometheus.NewDesc(
+		namespace+"_db_rows",
+		"Number of rows in a database table.",
+		[]string{"table"}, nil)
+)
+
+// hubCollector reads the current state of the node and the database when
+// Prometheus scrapes. Sources that are unavailable, such as the node before
+// the hub is unlocked, are left out of the scrape.
+type hubCollector struct {
+	svc service.Service
+}
+
+func (collector *hubCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- onchainBalanceDesc
+	ch <- lightningBalanceDesc
+	ch <- channelsDesc
+	ch <- relayConnectedDesc
+	ch <- swapsDesc
+	ch <- lsps1OrdersDesc
+	ch <- dbRowsDesc
+}
+
+func (collector *hubCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	collector.collectNode(ctx, ch)
+
+	for _, relayStatus := range collector.svc.GetRelayStatuses() {
+		connected := 0.0
+		if relayStatus.Online {
+			connected = 1
+		}
+		ch <- prometheus.MustNewConstMetric(relayConnectedDesc, prometheus.GaugeValue, connected, relayStatus.Url)
+	}
+
+	collector.collectDB(ctx, ch)
+}
+
+func (collector *hubCollector) collectNode(ctx context.Context, ch chan<- prometheus.Metric) {
+	lnClient := collector.svc.GetLNClient()
+	if lnClient == nil {
+		return
+	}
+
+	balances, err := lnClient.GetBalances(ctx, false)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Failed to fetch balances for metrics")
+	} else {
+		ch <- prometheus.MustNewConstMetric(onchainBalanceDesc, prometheus.GaugeValue, float64(balances.Onchain.Spendable), "spendable")
+		ch <- prometheus.MustNewConstMetric(onchainBalanceDesc, prometheus.GaugeValue, float64(balances.Onchain.Total), "total")
+		ch <- prometheus.MustNewConstMetric(onchainBalanceDesc, prometheus.GaugeValue, float64(balances.Onchain.Reserved), "reserved")
+		ch <- prometheus.MustNewConstMetric(lightningBalanceDesc, prometheus.GaugeValue, float64(balances.Lightning.TotalSpendable), "spendable")
+		ch <- prometheus.MustNewConstMetric(lightningBalanceDesc, prometheus.GaugeValue, float64(balances.Lightning.TotalReceivable), "receivable")
+	}
+
+	channels, err := lnClient.ListChannels(ctx)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Failed to fetch channels for metrics")
+		return
+	}
+	channelStates := map[string]int{
+		CHANNEL_STATE_ACTIVE:   0,
+		CHANNEL_STATE_INACTIVE: 0,
+		CHANNEL_STATE_PENDING:  0,
+	}
+	for _, channel := range channels {
+		channelStates[channelState(&channel)]++
+	}
+	for state, count := range channelStates {
+		ch <- prometheus.MustNewConstMetric(channelsDesc, prometheus.GaugeValue, float64(count), state)
+	}
+}
+
+func (collector *hubCollector) collectDB(ctx context.Context, ch chan<- prometheus.Metric) {
+	gormDB := collector.svc.GetDB().WithContext(ctx)
+
+	var swapCounts []struct {
+		Type  string
+		State string
+		Count int64
+	}
+	if err := gormDB.Model(&db.Swap{}).Select("type, state, COUNT(*) AS count").Group("type, state").Scan(&swapCounts).Error; err != nil {
+		logger.Logger.Warn().Err(err).Msg("Failed to count swaps for metrics")
+	}
+	for _, swapCount := range swapCounts {
+		ch <- prometheus.MustNewConstMetric(swapsDesc, prometheus.GaugeValue, float64(swapCount.Count), swapCount.Type, swapCount.State)
+	}
+
+	// the LSPS tables are created by the liquidity manager, so they may not
+	// exist yet
+	orderModels := []struct {
+		role  string
+		model interface{}
+	}{
+		{"client", &persist.LSPS1Order{}},
+		{"service", &persist.LSPS1ServiceOrder{}},
+	}
+	for _, orderModel := range orderModels {
+		if !gormDB.Migrator().HasTable(orderModel.model) {
+			continue
+		}
+		var orderCounts []struct {
+			State string
+			Count int64
+		}
+		if err := gormDB.Model(orderModel.model).Select("state, COUNT(*) AS count").Group("state").Scan(&orderCounts).Error; err != nil {
+			logger.Logger.Warn().Err(err).Str("role", orderModel.role).Msg("Failed to count LSPS1 orders for metrics")
+			continue
+		}
+		for _, orderCount := range orderCounts {
+			ch <- prometheus.MustNewConstMetric(lsps1OrdersDesc, prometheus.GaugeValue, float64(orderCount.Count), orderModel.role, orderCount.State)
+		}
+	}
+
+	tableModels := []struct {
+		table string
+		model interface{}
+	}{
+		{"request_events", &db.RequestEvent{}},
+		{"transactions", &db.Transaction{}},
+	}
+	for _, tableModel := range tableModels {
+		var count int64
+		if err := gormDB.Model(tableModel.model).Count(&count).Error; err != nil {
+			logger.Logger.Warn().Err(err).Str("table", tableModel.table).Msg("Failed to count rows for metrics")
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(dbRowsDesc, prometheus.GaugeValue, float64(count), tableModel.table)
+	}
+}
+
+// channelState returns whether a channel is still being opened, or is open
+// and usable (active) or not (inactive)
+func channelState(channel *lnclient.Channel) string {
+	switch {
+	case channel.Active:
+		return CHANNEL_STATE_ACTIVE
+	case channel.ConfirmationsRequired != nil &&
+		(channel.Confirmations == nil || *channel.Confirmations < *channel.ConfirmationsRequired):
+		return CHANNEL_STATE_PENDING
+	default:
+		return CHANNEL_STATE_INACTIVE
+	}
+}
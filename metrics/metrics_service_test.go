@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/nip47/models"
+	"github.com/flokiorg/lokihub/service"
+	"github.com/flokiorg/lokihub/tests"
+	"github.com/flokiorg/lokihub/tests/mocks"
+)
+
+func newTestMetricsService(t *testing.T) (*metricsService, *tests.TestService) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	t.Cleanup(svc.Remove)
+
+	mockSvc := mocks.NewMockService(t)
+	mockSvc.On("GetLNClient").Return(svc.LNClient).Maybe()
+	mockSvc.On("GetDB").Return(svc.DB).Maybe()
+	mockSvc.On("GetRelayStatuses").Return([]service.RelayStatus{
+		{Url: "wss://relay.one", Online: true},
+		{Url: "wss://relay.two", Online: false},
+	}).Maybe()
+	return NewMetricsService(mockSvc), svc
+}
+
+func TestConsumeEvent(t *testing.T) {
+	metricsSvc, _ := newTestMetricsService(t)
+	ctx := context.Background()
+
+	metricsSvc.ConsumeEvent(ctx, &events.Event{
+		Event:      "nwc_request_handled",
+		Properties: &models.RequestHandledEventProperties{Method: models.PAY_INVOICE_METHOD, DurationMs: 1500},
+	}, nil)
+	metricsSvc.ConsumeEvent(ctx, &events.Event{
+		Event:      "nwc_request_handled",
+		Properties: &models.RequestHandledEventProperties{Method: models.PAY_INVOICE_METHOD, Code: constants.ERROR_QUOTA_EXCEEDED},
+	}, nil)
+	metricsSvc.ConsumeEvent(ctx, &events.Event{
+		Event:      "nwc_request_handled",
+		Properties: &models.RequestHandledEventProperties{Method: "made_up_method", Code: constants.ERROR_NOT_IMPLEMENTED},
+	}, nil)
+	metricsSvc.ConsumeEvent(ctx, &events.Event{Event: "nwc_payment_sent", Properties: &db.Transaction{FeeMloki: 3_000}}, nil)
+	metricsSvc.ConsumeEvent(ctx, &events.Event{Event: "nwc_payment_sent", Properties: &db.Transaction{FeeMloki: 2_000}}, nil)
+	metricsSvc.ConsumeEvent(ctx, &events.Event{Event: "nwc_payment_failed", Properties: &db.Transaction{}}, nil)
+	metricsSvc.ConsumeEvent(ctx, &events.Event{
+		Event:      "nwc_payment_forwarded",
+		Properties: &lnclient.PaymentForwardedEventProperties{TotalFeeEarnedMloki: 1_234},
+	}, nil)
+	// wrongly typed properties are ignored
+	metricsSvc.ConsumeEvent(ctx, &events.Event{Event: "nwc_payment_forwarded", Properties: map[string]interface{}{}}, nil)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricsSvc.nip47Requests.WithLabelValues(models.PAY_INVOICE_METHOD, "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricsSvc.nip47Requests.WithLabelValues(models.PAY_INVOICE_METHOD, constants.ERROR_QUOTA_EXCEEDED)))
+	// client-supplied methods the hub does not know share one label
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricsSvc.nip47Requests.WithLabelValues(unknownMethod, constants.ERROR_NOT_IMPLEMENTED)))
+	assert.Equal(t, 3, testutil.CollectAndCount(metricsSvc.nip47RequestDuration))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metricsSvc.payments.WithLabelValues("succeeded")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricsSvc.payments.WithLabelValues("failed")))
+	assert.Equal(t, 5_000.0, testutil.ToFloat64(metricsSvc.paymentFees))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricsSvc.forwards))
+	assert.Equal(t, 1_234.0, testutil.ToFloat64(metricsSvc.forwardingFees))
+}
+
+func TestHubCollector(t *testing.T) {
+	metricsSvc, svc := newTestMetricsService(t)
+
+	require.NoError(t, svc.DB.Create(&db.Swap{SwapId: "swap-1", Type: constants.SWAP_TYPE_OUT, State: constants.SWAP_STATE_SUCCESS}).Error)
+	require.NoError(t, svc.DB.Create(&db.Swap{SwapId: "swap-2", Type: constants.SWAP_TYPE_OUT, State: constants.SWAP_STATE_SUCCESS}).Error)
+	require.NoError(t, svc.DB.Create(&db.Swap{SwapId: "swap-3", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_PENDING}).Error)
+	require.NoError(t, svc.DB.Create(&db.Transaction{Type: constants.TRANSACTION_TYPE_INCOMING, State: constants.TRANSACTION_STATE_SETTLED}).Error)
+
+	expected := `
+# HELP lokihub_db_rows Number of rows in a database table.
+# TYPE lokihub_db_rows gauge
+lokihub_db_rows{table="request_events"} 0
+lokihub_db_rows{table="transactions"} 1
+# HELP lokihub_lightning_balance_mloki Lightning balance across active channels, by kind (spendable, receivable).
+# TYPE lokihub_lightning_balance_mloki gauge
+lokihub_lightning_balance_mloki{kind="receivable"} 0
+lokihub_lightning_balance_mloki{kind="spendable"} 21000
+# HELP lokihub_relay_connected Whether the hub is connected to a Nostr relay (1) or not (0).
+# TYPE lokihub_relay_connected gauge
+lokihub_relay_connected{url="wss://relay.one"} 1
+lokihub_relay_connected{url="wss://relay.two"} 0
+# HELP lokihub_swaps Swaps, by type and state.
+# TYPE lokihub_swaps gauge
+lokihub_swaps{state="PENDING",type="in"} 1
+lokihub_swaps{state="SUCCESS",type="out"} 2
+`
+	err := testutil.GatherAndCompare(metricsSvc.registry, strings.NewReader(expected),
+		"lokihub_db_rows", "lokihub_lightning_balance_mloki", "lokihub_relay_connected", "lokihub_swaps")
+	assert.NoError(t, err)
+}
+
+func TestChannelState(t *testing.T) {
+	confirmations := uint32(1)
+	confirmationsRequired := uint32(3)
+	enoughConfirmations := uint32(3)
+
+	assert.Equal(t, CHANNEL_STATE_ACTIVE, channelState(&lnclient.Channel{Active: true}))
+	assert.Equal(t, CHANNEL_STATE_INACTIVE, channelState(&lnclient.Channel{}))
+	assert.Equal(t, CHANNEL_STATE_INACTIVE, channelState(&lnclient.Channel{Confirmations: &enoughConfirmations, ConfirmationsRequired: &confirmationsRequired}))
+	assert.Equal(t, CHANNEL_STATE_PENDING, channelState(&lnclient.Channel{Confirmations: &confirmations, ConfirmationsRequired: &confirmationsRequired}))
+	assert.Equal(t, CHANNEL_STATE_PENDING, channelState(&lnclient.Channel{ConfirmationsRequired: &confirmationsRequired}))
+}
@@ -17,6 +17,25 @@ type getBudgetResponse struct {
 	TotalBudget   uint64  `json:"total_budget"`
 	RenewsAt      *uint64 `json:"renews_at,omitempty"`
 	RenewalPeriod string  `json:"renewal_period"`
+	// SpendingPolicy is only set when the app has a db.AppSpendingPolicy.
+	SpendingPolicy *getBudgetSpendingPolicy `json:"spending_policy,omitempty"`
+}
+
+// getBudgetSpendingPolicy reports the app's spending policy rules; rules
+// that are not set are omitted. Amounts are in millilokis.
+type getBudgetSpendingPolicy struct {
+	MaxAmountPerPayment uint64   `json:"max_amount_per_payment,omitempty"`
+	MaxPaymentsPerHour  int      `json:"max_payments_per_hour,omitempty"`
+	MaxFeePpm           int      `json:"max_fee_ppm,omitempty"`
+	AllowedPubkeys      []string `json:"allowed_pubkeys,omitempty"`
+	DeniedPubkeys       []string `json:"denied_pubkeys,omitempty"`
+	// ApprovalThreshold is the amount above which payments wait for the
+	// wallet owner's approval.
+	ApprovalThreshold uint64 `json:"approval_threshold,omitempty"`
+}
+
+type getBudgetPolicyOnlyResponse struct {
+	SpendingPolicy *getBudgetSpendingPolicy `json:"spending_policy"`
 }
 
 func (controller *nip47Controller) HandleGetBudgetEvent(ctx context.Context, nip47Request *models.Request, requestEventId uint, app *db.App, publishResponse publishFunc) {
@@ -34,21 +53,39 @@ func (controller *nip47Controller) HandleGetBudgetEvent(ctx context.Context, nip
 	appPermission := db.AppPermission{}
 	controller.db.Where("app_id = ? AND scope IN ?", app.ID, constants.PayCapableScopes).First(&appPermission)
 
+	var spendingPolicy *getBudgetSpendingPolicy
+	policy := db.AppSpendingPolicy{}
+	if result := controller.db.Where("app_id = ?", app.ID).Limit(1).Find(&policy); result.Error == nil && result.RowsAffected > 0 {
+		spendingPolicy = &getBudgetSpendingPolicy{
+			MaxAmountPerPayment: uint64(policy.MaxAmountPerPaymentLoki) * 1000, //nolint:gosec // validated non-negative on write
+			MaxPaymentsPerHour:  policy.MaxPaymentsPerHour,
+			MaxFeePpm:           policy.MaxFeePpm,
+			AllowedPubkeys:      policy.AllowedPubkeys,
+			DeniedPubkeys:       policy.DeniedPubkeys,
+			ApprovalThreshold:   uint64(policy.ApprovalThresholdLoki) * 1000, //nolint:gosec // validated non-negative on write
+		}
+	}
+
 	maxAmount := appPermission.MaxAmountLoki
 	if maxAmount == 0 {
+		var result interface{} = struct{}{}
+		if spendingPolicy != nil {
+			result = &getBudgetPolicyOnlyResponse{SpendingPolicy: spendingPolicy}
+		}
 		publishResponse(&models.Response{
 			ResultType: nip47Request.Method,
-			Result:     struct{}{},
+			Result:     result,
 		}, nostr.Tags{})
 		return
 	}
 
 	usedBudget := queries.GetBudgetUsageSat(controller.db, &appPermission)
 	responsePayload := &getBudgetResponse{
-		TotalBudget:   uint64(maxAmount * 1000), //nolint:gosec // app-internal budget value, always non-negative
-		UsedBudget:    usedBudget * 1000,
-		RenewalPeriod: appPermission.BudgetRenewal,
-		RenewsAt:      queries.GetBudgetRenewsAt(appPermission.BudgetRenewal),
+		TotalBudget:    uint64(maxAmount * 1000), //nolint:gosec // app-internal budget value, always non-negative
+		UsedBudget:     usedBudget * 1000,
+		RenewalPeriod:  appPermission.BudgetRenewal,
+		RenewsAt:       queries.GetBudgetRenewsAt(appPermission.BudgetRenewal),
+		SpendingPolicy: spendingPolicy,
 	}
 
 	publishResponse(&models.Response{
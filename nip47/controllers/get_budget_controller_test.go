@@ -276,3 +276,99 @@ func TestHandleGetBudgetEvent_CircleHub_NoLeak(t *testing.T) {
 	assert.Equal(t, struct{}{}, publishedResponse.Result, "a circle_hub's get_budget must reveal nothing about its real committed capacity")
 	assert.Nil(t, publishedResponse.Error)
 }
+
+func TestHandleGetBudgetEvent_SpendingPolicy(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47GetBudgetJson), nip47Request)
+	assert.NoError(t, err)
+
+	app, _, err := tests.CreateApp(svc)
+	assert.NoError(t, err)
+
+	appPermission := &db.AppPermission{
+		AppId:         app.ID,
+		App:           *app,
+		Scope:         constants.PAY_INVOICE_SCOPE,
+		MaxAmountLoki: 400,
+		BudgetRenewal: constants.BUDGET_RENEWAL_NEVER,
+	}
+	err = svc.DB.Create(appPermission).Error
+	assert.NoError(t, err)
+
+	err = svc.DB.Create(&db.AppSpendingPolicy{
+		AppID:                   app.ID,
+		MaxAmountPerPaymentLoki: 50,
+		MaxPaymentsPerHour:      3,
+		DeniedPubkeys:           []string{"02a3b1fbd2a6a3e0f0c45fdd8b0b8b5e0d5b2dd4a2b1c1ab1ad3c6f1e3e2d1c0b9"},
+	}).Error
+	require.NoError(t, err)
+
+	var publishedResponse *models.Response
+
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandleGetBudgetEvent(ctx, nip47Request, 0, app, publishResponse)
+
+	assert.Nil(t, publishedResponse.Error)
+	response := publishedResponse.Result.(*getBudgetResponse)
+	assert.Equal(t, uint64(400000), response.TotalBudget)
+	require.NotNil(t, response.SpendingPolicy)
+	assert.Equal(t, uint64(50000), response.SpendingPolicy.MaxAmountPerPayment)
+	assert.Equal(t, 3, response.SpendingPolicy.MaxPaymentsPerHour)
+	assert.Equal(t, 0, response.SpendingPolicy.MaxFeePpm)
+	assert.Equal(t, []string{"02a3b1fbd2a6a3e0f0c45fdd8b0b8b5e0d5b2dd4a2b1c1ab1ad3c6f1e3e2d1c0b9"}, response.SpendingPolicy.DeniedPubkeys)
+}
+
+func TestHandleGetBudgetEvent_SpendingPolicyWithoutBudget(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47GetBudgetJson), nip47Request)
+	assert.NoError(t, err)
+
+	app, _, err := tests.CreateApp(svc)
+	assert.NoError(t, err)
+
+	appPermission := &db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}
+	err = svc.DB.Create(appPermission).Error
+	assert.NoError(t, err)
+
+	err = svc.DB.Create(&db.AppSpendingPolicy{
+		AppID:     app.ID,
+		MaxFeePpm: 5000,
+	}).Error
+	require.NoError(t, err)
+
+	var publishedResponse *models.Response
+
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandleGetBudgetEvent(ctx, nip47Request, 0, app, publishResponse)
+
+	assert.Nil(t, publishedResponse.Error)
+	response := publishedResponse.Result.(*getBudgetPolicyOnlyResponse)
+	require.NotNil(t, response.SpendingPolicy)
+	assert.Equal(t, 5000, response.SpendingPolicy.MaxFeePpm)
+
+	payload, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"spending_policy":{"max_fee_ppm":5000}}`, string(payload))
+}
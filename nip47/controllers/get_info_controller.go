@@ -7,6 +7,7 @@ import (
 	"github.com/flokiorg/lokihub/constants"
 	"github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/db/queries"
+	"github.com/flokiorg/lokihub/lnurl"
 	"github.com/flokiorg/lokihub/logger"
 	"github.com/flokiorg/lokihub/nip47/models"
 	"github.com/nbd-wtf/go-nostr"
@@ -96,7 +97,14 @@ func (controller *nip47Controller) HandleGetInfoEvent(ctx context.Context, nip47
 			if metadata["name"] == nil {
 				metadata["name"] = app.Name
 			}
-			if !app.IsIsolated() {
+			// a lightning address served by this hub for the app itself takes
+			// precedence; it can only be reported once the public base URL is known
+			var appLightningAddress db.LightningAddress
+			baseUrl := controller.cfg.GetEnv().BaseUrl
+			if baseUrl != "" && controller.db.Limit(1).Find(&appLightningAddress, &db.LightningAddress{AppId: app.ID}).RowsAffected > 0 {
+				lightningAddress := lnurl.Address(appLightningAddress.Username, baseUrl)
+				responsePayload.LightningAddress = &lightningAddress
+			} else if !app.IsIsolated() {
 				lightningAddress, _ := controller.cfg.Get("LightningAddress", "")
 				responsePayload.LightningAddress = &lightningAddress
 			} else if metadata["app_store_app_id"] == constants.SUBWALLET_APPSTORE_APP_ID && metadata["lud16"] != nil {
@@ -518,3 +518,45 @@ func TestHandleGetInfoEvent_NonCircleAdmin_NoCircleWalletBlock(t *testing.T) {
 	nodeInfo := publishedResponse.Result.(*getInfoResponse)
 	assert.Nil(t, nodeInfo.CircleWallet, "non-circle_admin app must not have circle_wallet block")
 }
+
+func TestHandleGetInfoEvent_AppLightningAddress(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	require.NoError(t, svc.Cfg.SetUpdate("LightningAddress", "hello@flokicoin.org", ""))
+	svc.Cfg.GetEnv().BaseUrl = "https://hub.example.com"
+
+	app, _, err := svc.AppsService.CreateApp("test", "", 0, "monthly", nil, []string{constants.GET_INFO_SCOPE}, "", nil, "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DB.Create(&db.LightningAddress{AppId: app.ID, Username: "alice"}).Error)
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47GetInfoJson), nip47Request)
+	require.NoError(t, err)
+
+	dbRequestEvent := &db.RequestEvent{}
+	err = svc.DB.Create(&dbRequestEvent).Error
+	require.NoError(t, err)
+
+	err = svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		Scope: constants.GET_INFO_SCOPE,
+	}).Error
+	require.NoError(t, err)
+
+	var publishedResponse *models.Response
+
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandleGetInfoEvent(ctx, nip47Request, dbRequestEvent.ID, app, publishResponse)
+
+	assert.Nil(t, publishedResponse.Error)
+	nodeInfo := publishedResponse.Result.(*getInfoResponse)
+	assert.Equal(t, "alice@hub.example.com", *nodeInfo.LightningAddress)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/nip47/models"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+type lookupOfferParams struct {
+	Offer string `json:"offer"`
+}
+
+type lookupOfferResponse struct {
+	models.Offer
+}
+
+func (controller *nip47Controller) HandleLookupOfferEvent(ctx context.Context, nip47Request *models.Request, requestEventId uint, appId uint, publishResponse publishFunc) {
+	lookupOfferParams := &lookupOfferParams{}
+	resp := decodeRequest(nip47Request, lookupOfferParams)
+	if resp != nil {
+		publishResponse(resp, nostr.Tags{})
+		return
+	}
+
+	logger.Logger.Info().
+		Interface("offer", lookupOfferParams.Offer).
+		Interface("request_event_id", requestEventId).
+		Msg("Looking up offer")
+
+	offer, err := controller.transactionsService.LookupOffer(ctx, lookupOfferParams.Offer, &appId)
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("offer", lookupOfferParams.Offer).
+			Msg("Failed to lookup offer")
+
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, nostr.Tags{})
+		return
+	}
+
+	publishResponse(&models.Response{
+		ResultType: nip47Request.Method,
+		Result: &lookupOfferResponse{
+			Offer: *models.ToNip47Offer(offer),
+		},
+	}, nostr.Tags{})
+}
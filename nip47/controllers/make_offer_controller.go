@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/nip47/models"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+type makeOfferParams struct {
+	Description string `json:"description"`
+}
+
+type makeOfferResponse struct {
+	models.Offer
+}
+
+func (controller *nip47Controller) HandleMakeOfferEvent(ctx context.Context, nip47Request *models.Request, requestEventId uint, appId uint, publishResponse publishFunc) {
+	makeOfferParams := &makeOfferParams{}
+	resp := decodeRequest(nip47Request, makeOfferParams)
+	if resp != nil {
+		publishResponse(resp, nostr.Tags{})
+		return
+	}
+
+	logger.Logger.Debug().
+		Interface("app_id", appId).
+		Interface("request_event_id", requestEventId).
+		Interface("description", makeOfferParams.Description).
+		Msg("Handling make_offer request")
+
+	offer, err := controller.transactionsService.MakeOffer(ctx, makeOfferParams.Description, controller.lnClient, &appId)
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("description", makeOfferParams.Description).
+			Msg("Failed to make offer")
+
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, nostr.Tags{})
+		return
+	}
+
+	publishResponse(&models.Response{
+		ResultType: nip47Request.Method,
+		Result: &makeOfferResponse{
+			Offer: *models.ToNip47Offer(offer),
+		},
+	}, nostr.Tags{})
+}
@@ -3,7 +3,9 @@ package controllers
 import (
 	"errors"
 
+	"github.com/flokiorg/lokihub/approvals"
 	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/lnclient"
 	"github.com/flokiorg/lokihub/nip47/models"
 	"github.com/flokiorg/lokihub/transactions"
 )
@@ -19,9 +21,21 @@ func mapNip47Error(err error) *models.Error {
 	if errors.Is(err, transactions.NewQuotaExceededError()) {
 		code = constants.ERROR_QUOTA_EXCEEDED
 	}
+	if errors.Is(err, transactions.NewSpendingPolicyError("")) {
+		code = constants.ERROR_POLICY_VIOLATION
+	}
+	if errors.Is(err, approvals.ErrPaymentRejected) {
+		code = constants.ERROR_PAYMENT_REJECTED
+	}
+	if errors.Is(err, approvals.ErrApprovalTimeout) {
+		code = constants.ERROR_APPROVAL_TIMEOUT
+	}
 	if errors.Is(err, transactions.NewJITPartialSpendError()) {
 		code = constants.ERROR_RESTRICTED
 	}
+	if errors.Is(err, lnclient.ErrOffersNotSupported) {
+		code = constants.ERROR_NOT_SUPPORTED
+	}
 
 	return &models.Error{
 		Code:    code,
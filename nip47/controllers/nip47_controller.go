@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/flokiorg/lokihub/approvals"
 	"github.com/flokiorg/lokihub/apps"
 	"github.com/flokiorg/lokihub/config"
 	"github.com/flokiorg/lokihub/db"
@@ -30,6 +31,7 @@ type nip47Controller struct {
 	permissionsService  permissions.PermissionsService
 	transactionsService transactions.TransactionsService
 	appsService         apps.AppsService
+	approvalsService    approvals.ApprovalsService
 	keys                keys.Keys
 	socialCache         NostrSocialCache
 	jitRateLimiter      RateLimiter
@@ -66,6 +68,7 @@ func NewNip47Controller(
 		permissionsService:  permissionsService,
 		transactionsService: transactionsService,
 		appsService:         appsService,
+		approvalsService:    approvals.NewApprovalsService(db, eventPublisher),
 		keys:                keys,
 		socialCache:         socialCache,
 		jitRateLimiter:      jitRateLimiter,
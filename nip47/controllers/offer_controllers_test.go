@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/approvals"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/nip47/models"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+const nip47MakeOfferJson = `
+{
+	"method": "make_offer",
+	"params": {
+		"description": "coffee"
+	}
+}
+`
+
+const nip47LookupOfferJson = `
+{
+	"method": "lookup_offer",
+	"params": {
+		"offer": "` + tests.MockOffer + `"
+	}
+}
+`
+
+const nip47PayOfferJson = `
+{
+	"method": "pay_offer",
+	"params": {
+		"offer": "` + tests.MockOffer + `",
+		"amount": 123000,
+		"payer_note": "thanks"
+	}
+}
+`
+
+func TestHandleMakeOfferEvent(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47MakeOfferJson), nip47Request)
+	require.NoError(t, err)
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	dbRequestEvent := &db.RequestEvent{AppId: &app.ID}
+	require.NoError(t, svc.DB.Create(&dbRequestEvent).Error)
+
+	var publishedResponse *models.Response
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandleMakeOfferEvent(ctx, nip47Request, dbRequestEvent.ID, app.ID, publishResponse)
+
+	require.Nil(t, publishedResponse.Error)
+	offer := publishedResponse.Result.(*makeOfferResponse)
+	assert.Equal(t, tests.MockOffer, offer.Offer.Offer)
+	assert.Equal(t, "coffee", offer.Description)
+
+	// the offer can be looked up again by the app that made it
+	nip47Request = &models.Request{}
+	err = json.Unmarshal([]byte(nip47LookupOfferJson), nip47Request)
+	require.NoError(t, err)
+
+	publishedResponse = nil
+	NewTestNip47Controller(svc).
+		HandleLookupOfferEvent(ctx, nip47Request, dbRequestEvent.ID, app.ID, publishResponse)
+
+	require.Nil(t, publishedResponse.Error)
+	lookedUp := publishedResponse.Result.(*lookupOfferResponse)
+	assert.Equal(t, tests.MockOffer, lookedUp.Offer.Offer)
+	assert.Equal(t, "coffee", lookedUp.Description)
+}
+
+func TestHandleLookupOfferEvent_NotFound(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47LookupOfferJson), nip47Request)
+	require.NoError(t, err)
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	dbRequestEvent := &db.RequestEvent{AppId: &app.ID}
+	require.NoError(t, svc.DB.Create(&dbRequestEvent).Error)
+
+	var publishedResponse *models.Response
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandleLookupOfferEvent(ctx, nip47Request, dbRequestEvent.ID, app.ID, publishResponse)
+
+	require.NotNil(t, publishedResponse.Error)
+	assert.Equal(t, constants.ERROR_NOT_FOUND, publishedResponse.Error.Code)
+	assert.Nil(t, publishedResponse.Result)
+}
+
+func TestHandlePayOfferEvent(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47PayOfferJson), nip47Request)
+	require.NoError(t, err)
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error)
+
+	dbRequestEvent := &db.RequestEvent{AppId: &app.ID}
+	require.NoError(t, svc.DB.Create(&dbRequestEvent).Error)
+
+	var publishedResponse *models.Response
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandlePayOfferEvent(ctx, nip47Request, dbRequestEvent.ID, app, publishResponse, nostr.Tags{})
+
+	require.Nil(t, publishedResponse.Error)
+	result := publishedResponse.Result.(payOfferResponse)
+	assert.Equal(t, "123preimage", result.Preimage)
+	assert.Equal(t, uint64(1), result.FeesPaid)
+	assert.Equal(t, tests.MockOfferPaymentHash, result.PaymentHash)
+
+	var transaction db.Transaction
+	require.NoError(t, svc.DB.First(&transaction, &db.Transaction{PaymentHash: tests.MockOfferPaymentHash}).Error)
+	assert.Equal(t, app.ID, *transaction.AppId)
+	assert.Equal(t, dbRequestEvent.ID, *transaction.RequestEventId)
+	assert.Equal(t, uint64(123000), transaction.AmountMloki)
+	assert.Equal(t, "thanks", transaction.Description)
+}
+
+func TestHandlePayOfferEvent_IsolatedApp_InsufficientBalance(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47PayOfferJson), nip47Request)
+	require.NoError(t, err)
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	app.Kind = db.AppKindIsolated
+	svc.DB.Save(&app)
+
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error)
+
+	dbRequestEvent := &db.RequestEvent{AppId: &app.ID}
+	require.NoError(t, svc.DB.Create(&dbRequestEvent).Error)
+
+	var publishedResponse *models.Response
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandlePayOfferEvent(ctx, nip47Request, dbRequestEvent.ID, app, publishResponse, nostr.Tags{})
+
+	require.NotNil(t, publishedResponse.Error)
+	assert.Equal(t, constants.ERROR_INSUFFICIENT_BALANCE, publishedResponse.Error.Code)
+	assert.Nil(t, publishedResponse.Result)
+}
+
+func TestHandlePayOfferEvent_NotSupported(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	svc.LNClient.(*tests.MockLn).PayOfferError = lnclient.ErrOffersNotSupported
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47PayOfferJson), nip47Request)
+	require.NoError(t, err)
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error)
+
+	dbRequestEvent := &db.RequestEvent{AppId: &app.ID}
+	require.NoError(t, svc.DB.Create(&dbRequestEvent).Error)
+
+	var publishedResponse *models.Response
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		publishedResponse = response
+	}
+
+	NewTestNip47Controller(svc).
+		HandlePayOfferEvent(ctx, nip47Request, dbRequestEvent.ID, app, publishResponse, nostr.Tags{})
+
+	require.NotNil(t, publishedResponse.Error)
+	assert.Equal(t, constants.ERROR_NOT_SUPPORTED, publishedResponse.Error.Code)
+}
+
+func TestHandlePayOfferEvent_AboveApprovalThreshold(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error)
+	// the offer is paid for 123 loki
+	require.NoError(t, svc.DB.Create(&db.AppSpendingPolicy{AppID: app.ID, ApprovalThresholdLoki: 100}).Error)
+
+	nip47Request := &models.Request{}
+	require.NoError(t, json.Unmarshal([]byte(nip47PayOfferJson), nip47Request))
+
+	dbRequestEvent := &db.RequestEvent{}
+	require.NoError(t, svc.DB.Create(&dbRequestEvent).Error)
+
+	responses := make(chan *models.Response, 1)
+	publishResponse := func(response *models.Response, tags nostr.Tags) {
+		responses <- response
+	}
+
+	go NewTestNip47Controller(svc).
+		HandlePayOfferEvent(ctx, nip47Request, dbRequestEvent.ID, app, publishResponse, nostr.Tags{})
+
+	var approval db.PaymentApproval
+	require.Eventually(t, func() bool {
+		return svc.DB.Where("app_id = ?", app.ID).First(&approval).Error == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(123000), approval.AmountMloki)
+	assert.Equal(t, tests.MockOffer, approval.PaymentRequest)
+
+	_, err = approvals.NewApprovalsService(svc.DB, svc.EventPublisher).DecideApproval(approval.ID, false)
+	require.NoError(t, err)
+
+	var publishedResponse *models.Response
+	select {
+	case publishedResponse = <-responses:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response was published after the approval was decided")
+	}
+	require.NotNil(t, publishedResponse.Error)
+	assert.Equal(t, constants.ERROR_PAYMENT_REJECTED, publishedResponse.Error.Code)
+
+	var count int64
+	svc.DB.Model(&db.Transaction{}).Where("app_id = ?", app.ID).Count(&count)
+	assert.Zero(t, count)
+}
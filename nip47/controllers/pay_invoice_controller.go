@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/flokiorg/lokihub/approvals"
 	"github.com/flokiorg/lokihub/constants"
 	"github.com/flokiorg/lokihub/db"
 	decodepay "github.com/flokiorg/lokihub/decodepay"
@@ -50,10 +51,10 @@ func (controller *nip47Controller) HandlePayInvoiceEvent(ctx context.Context, ni
 
 	// JIT full-drain enforcement is applied inside SendPaymentSync (transactions_service.go)
 	// so it covers all payment paths (NIP-47, HTTP API, keysend) uniformly.
-	controller.pay(bolt11, payParams.Amount, payParams.Metadata, paymentRequest, nip47Request, requestEventId, app, publishResponse, tags)
+	controller.pay(ctx, bolt11, payParams.Amount, payParams.Metadata, paymentRequest, nip47Request, requestEventId, app, publishResponse, tags)
 }
 
-func (controller *nip47Controller) pay(bolt11 string, amount *uint64, metadata map[string]interface{}, paymentRequest *decodepay.Bolt11, nip47Request *models.Request, requestEventId uint, app *db.App, publishResponse publishFunc, tags nostr.Tags) {
+func (controller *nip47Controller) pay(ctx context.Context, bolt11 string, amount *uint64, metadata map[string]interface{}, paymentRequest *decodepay.Bolt11, nip47Request *models.Request, requestEventId uint, app *db.App, publishResponse publishFunc, tags nostr.Tags) {
 	logger.Logger.Info().
 		Interface("request_event_id", requestEventId).
 		Interface("app_id", app.ID).
@@ -71,6 +72,47 @@ func (controller *nip47Controller) pay(bolt11 string, amount *uint64, metadata m
 		delete(metadata, "jit_claim_slice")
 	}
 
+	// the amount param only applies to amountless invoices (see SendPaymentSync)
+	paymentAmountMloki := uint64(paymentRequest.MSat) //nolint:gosec // msat amounts are always far below int64/uint64 range
+	if amount != nil && paymentRequest.MSat == 0 {
+		paymentAmountMloki = *amount
+	}
+
+	// payments that would be refused anyway are not held for approval
+	err := controller.transactionsService.CheckCanPay(paymentAmountMloki, paymentRequest.Payee, paymentRequest.PaymentHash, controller.lnClient, &app.ID)
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("app_id", app.ID).
+			Msg("Payment cannot be made")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, tags)
+		return
+	}
+
+	err = controller.approvalsService.AwaitApproval(ctx, approvals.Payment{
+		AppId:          app.ID,
+		RequestEventId: &requestEventId,
+		Method:         nip47Request.Method,
+		AmountMloki:    paymentAmountMloki,
+		Destination:    paymentRequest.Payee,
+		PaymentRequest: bolt11,
+		Description:    paymentRequest.Description,
+	})
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("app_id", app.ID).
+			Msg("Payment was not approved")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, tags)
+		return
+	}
+
 	transaction, err := controller.transactionsService.SendPaymentSync(bolt11, amount, metadata, controller.lnClient, &app.ID, &requestEventId)
 	if err != nil {
 		logger.Logger.Error().Err(err).
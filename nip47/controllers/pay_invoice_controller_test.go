@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/nbd-wtf/go-nostr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flokiorg/lokihub/approvals"
 	"github.com/flokiorg/lokihub/constants"
 	"github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/nip47/models"
@@ -443,3 +445,118 @@ func TestHandlePayInvoiceEvent_JitClaimSliceMetadataSpoofRejected(t *testing.T)
 	assert.Equal(t, constants.ERROR_INSUFFICIENT_BALANCE, publishedResponse.Error.Code,
 		"user-injected jit_claim_slice must not bypass the fee-reserve balance check")
 }
+
+func TestHandlePayInvoiceEvent_AboveApprovalThreshold(t *testing.T) {
+	for _, approve := range []bool{true, false} {
+		ctx := context.TODO()
+		svc, err := tests.CreateTestService(t)
+		require.NoError(t, err)
+
+		app, _, err := tests.CreateApp(svc)
+		assert.NoError(t, err)
+
+		appPermission := &db.AppPermission{
+			AppId: app.ID,
+			App:   *app,
+			Scope: constants.PAY_INVOICE_SCOPE,
+		}
+		err = svc.DB.Create(appPermission).Error
+		assert.NoError(t, err)
+
+		// the invoice is for 123 loki
+		err = svc.DB.Create(&db.AppSpendingPolicy{AppID: app.ID, ApprovalThresholdLoki: 100}).Error
+		require.NoError(t, err)
+
+		nip47Request := &models.Request{}
+		err = json.Unmarshal([]byte(nip47PayInvoiceJson), nip47Request)
+		assert.NoError(t, err)
+
+		dbRequestEvent := &db.RequestEvent{}
+		err = svc.DB.Create(&dbRequestEvent).Error
+		assert.NoError(t, err)
+
+		responses := make(chan *models.Response, 1)
+		publishResponse := func(response *models.Response, tags nostr.Tags) {
+			responses <- response
+		}
+
+		go NewTestNip47Controller(svc).
+			HandlePayInvoiceEvent(ctx, nip47Request, dbRequestEvent.ID, app, publishResponse, nostr.Tags{})
+
+		var approval db.PaymentApproval
+		require.Eventually(t, func() bool {
+			return svc.DB.Where("app_id = ?", app.ID).First(&approval).Error == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, uint64(123000), approval.AmountMloki)
+		assert.Equal(t, dbRequestEvent.ID, *approval.RequestEventId)
+
+		_, err = approvals.NewApprovalsService(svc.DB, svc.EventPublisher).DecideApproval(approval.ID, approve)
+		require.NoError(t, err)
+
+		var publishedResponse *models.Response
+		select {
+		case publishedResponse = <-responses:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no response was published after the approval was decided")
+		}
+
+		if approve {
+			assert.Nil(t, publishedResponse.Error)
+			assert.Equal(t, "123preimage", publishedResponse.Result.(payResponse).Preimage)
+		} else {
+			require.NotNil(t, publishedResponse.Error)
+			assert.Equal(t, constants.ERROR_PAYMENT_REJECTED, publishedResponse.Error.Code)
+
+			var count int64
+			svc.DB.Model(&db.Transaction{}).Where("app_id = ?", app.ID).Count(&count)
+			assert.Zero(t, count)
+		}
+
+		svc.Remove()
+	}
+}
+
+func TestHandlePayInvoiceEvent_AboveApprovalThreshold_OverBudget(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	assert.NoError(t, err)
+
+	appPermission := &db.AppPermission{
+		AppId:         app.ID,
+		App:           *app,
+		Scope:         constants.PAY_INVOICE_SCOPE,
+		MaxAmountLoki: 10,
+	}
+	err = svc.DB.Create(appPermission).Error
+	assert.NoError(t, err)
+
+	// the invoice is for 123 loki
+	err = svc.DB.Create(&db.AppSpendingPolicy{AppID: app.ID, ApprovalThresholdLoki: 100}).Error
+	require.NoError(t, err)
+
+	nip47Request := &models.Request{}
+	err = json.Unmarshal([]byte(nip47PayInvoiceJson), nip47Request)
+	assert.NoError(t, err)
+
+	dbRequestEvent := &db.RequestEvent{}
+	err = svc.DB.Create(&dbRequestEvent).Error
+	assert.NoError(t, err)
+
+	var publishedResponse *models.Response
+	NewTestNip47Controller(svc).
+		HandlePayInvoiceEvent(ctx, nip47Request, dbRequestEvent.ID, app, func(response *models.Response, tags nostr.Tags) {
+			publishedResponse = response
+		}, nostr.Tags{})
+
+	// the payment is refused straight away instead of being held
+	require.NotNil(t, publishedResponse.Error)
+	assert.Equal(t, constants.ERROR_QUOTA_EXCEEDED, publishedResponse.Error.Code)
+
+	var count int64
+	svc.DB.Model(&db.PaymentApproval{}).Where("app_id = ?", app.ID).Count(&count)
+	assert.Zero(t, count)
+}
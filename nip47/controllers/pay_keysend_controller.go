@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/flokiorg/lokihub/approvals"
 	"github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/lnclient"
 	"github.com/flokiorg/lokihub/logger"
@@ -35,6 +36,39 @@ func (controller *nip47Controller) payKeysend(ctx context.Context, payKeysendPar
 		Interface("senderPubkey", payKeysendParams.Pubkey).
 		Msg("Sending keysend payment")
 
+	// payments that would be refused anyway are not held for approval
+	err := controller.transactionsService.CheckCanPay(payKeysendParams.Amount, payKeysendParams.Pubkey, "", controller.lnClient, &app.ID)
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("appId", app.ID).
+			Msg("Keysend payment cannot be made")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, tags)
+		return
+	}
+
+	err = controller.approvalsService.AwaitApproval(ctx, approvals.Payment{
+		AppId:          app.ID,
+		RequestEventId: &requestEventId,
+		Method:         nip47Request.Method,
+		AmountMloki:    payKeysendParams.Amount,
+		Destination:    payKeysendParams.Pubkey,
+	})
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("appId", app.ID).
+			Msg("Keysend payment was not approved")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, tags)
+		return
+	}
+
 	transaction, err := controller.transactionsService.SendKeysend(payKeysendParams.Amount, payKeysendParams.Pubkey, payKeysendParams.TLVRecords, payKeysendParams.Preimage, controller.lnClient, &app.ID, &requestEventId)
 	if err != nil {
 		logger.Logger.Info().Err(err).
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/approvals"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/nip47/models"
+	"github.com/flokiorg/lokihub/transactions"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+type payOfferParams struct {
+	Offer     string                 `json:"offer"`
+	Amount    uint64                 `json:"amount"`
+	PayerNote string                 `json:"payer_note"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type payOfferResponse struct {
+	payResponse
+	PaymentHash string `json:"payment_hash"`
+}
+
+func (controller *nip47Controller) HandlePayOfferEvent(ctx context.Context, nip47Request *models.Request, requestEventId uint, app *db.App, publishResponse publishFunc, tags nostr.Tags) {
+	payParams := &payOfferParams{}
+	resp := decodeRequest(nip47Request, payParams)
+	if resp != nil {
+		publishResponse(resp, tags)
+		return
+	}
+
+	logger.Logger.Info().
+		Interface("request_event_id", requestEventId).
+		Interface("app_id", app.ID).
+		Interface("offer", payParams.Offer).
+		Interface("amount", payParams.Amount).
+		Msg("Paying offer")
+
+	// internal_transfer/jit_claim_slice may only be set by trusted call sites (see pay)
+	if payParams.Metadata != nil {
+		delete(payParams.Metadata, "internal_transfer")
+		delete(payParams.Metadata, "jit_claim_slice")
+	}
+
+	// offers without an amount param are paid for the amount they fix
+	paymentAmountMloki := payParams.Amount
+	if paymentAmountMloki == 0 {
+		offerAmountMloki, err := transactions.OfferAmountMloki(payParams.Offer)
+		if err != nil {
+			publishResponse(&models.Response{
+				ResultType: nip47Request.Method,
+				Error: &models.Error{
+					Code:    constants.ERROR_BAD_REQUEST,
+					Message: err.Error(),
+				},
+			}, tags)
+			return
+		}
+		paymentAmountMloki = offerAmountMloki
+	}
+	// payments that would be refused anyway are not held for approval
+	err := controller.transactionsService.CheckCanPay(paymentAmountMloki, "", "", controller.lnClient, &app.ID)
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("app_id", app.ID).
+			Msg("Offer payment cannot be made")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, tags)
+		return
+	}
+
+	err = controller.approvalsService.AwaitApproval(ctx, approvals.Payment{
+		AppId:          app.ID,
+		RequestEventId: &requestEventId,
+		Method:         nip47Request.Method,
+		AmountMloki:    paymentAmountMloki,
+		PaymentRequest: payParams.Offer,
+		Description:    payParams.PayerNote,
+	})
+	if err != nil {
+		logger.Logger.Info().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("app_id", app.ID).
+			Msg("Offer payment was not approved")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, tags)
+		return
+	}
+
+	transaction, err := controller.transactionsService.PayOffer(ctx, payParams.Offer, payParams.Amount, payParams.PayerNote, payParams.Metadata, controller.lnClient, &app.ID, &requestEventId)
+	if err != nil {
+		logger.Logger.Error().Err(err).
+			Interface("request_event_id", requestEventId).
+			Interface("app_id", app.ID).
+			Interface("offer", payParams.Offer).
+			Msg("Failed to pay offer")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error:      mapNip47Error(err),
+		}, tags)
+		return
+	}
+
+	if transaction == nil || transaction.Preimage == nil {
+		logger.Logger.Error().
+			Interface("request_event_id", requestEventId).
+			Interface("app_id", app.ID).
+			Msg("Offer payment succeeded but transaction or preimage is nil")
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error: &models.Error{
+				Code:    constants.ERROR_INTERNAL,
+				Message: "payment completed but preimage unavailable",
+			},
+		}, tags)
+		return
+	}
+
+	publishResponse(&models.Response{
+		ResultType: nip47Request.Method,
+		Result: payOfferResponse{
+			payResponse: payResponse{
+				Preimage: *transaction.Preimage,
+				FeesPaid: transaction.FeeMloki,
+			},
+			PaymentHash: transaction.PaymentHash,
+		},
+	}, tags)
+}
@@ -23,6 +23,7 @@ import (
 )
 
 func (svc *nip47Service) HandleEvent(ctx context.Context, pool nostrmodels.SimplePool, event *nostr.Event, lnClient lnclient.LNClient) {
+	handleStartTime := time.Now()
 	var nip47Response *models.Response
 	logger.Logger.Debug().
 		Str("requestEventNostrId", event.ID).
@@ -353,6 +354,19 @@ func (svc *nip47Service) HandleEvent(ctx context.Context, pool nostrmodels.Simpl
 				Str("appPubkey", event.PubKey).
 				Msg("Failed to save state to nostr event")
 		}
+
+		requestHandledEventProperties := &models.RequestHandledEventProperties{
+			AppId:      app.ID,
+			Method:     nip47Request.Method,
+			DurationMs: time.Since(handleStartTime).Milliseconds(),
+		}
+		if nip47Response.Error != nil {
+			requestHandledEventProperties.Code = nip47Response.Error.Code
+		}
+		svc.eventPublisher.Publish(&events.Event{
+			Event:      "nwc_request_handled",
+			Properties: requestHandledEventProperties,
+		})
 	}
 
 	logger.Logger.Debug().
@@ -363,6 +377,44 @@ func (svc *nip47Service) HandleEvent(ctx context.Context, pool nostrmodels.Simpl
 		Interface("params", nip47Request.Params).
 		Msg("Handling NIP-47 request")
 
+	// an emergency freeze leaves connections able to introspect, but nothing
+	// else, so NWC clients can still tell why they are being refused
+	if nip47Request.Method != models.GET_INFO_METHOD {
+		freezeState, err := svc.freezeService.GetState()
+		if err != nil {
+			logger.Logger.Error().Err(err).
+				Uint("app_id", app.ID).
+				Msg("Failed to get freeze state")
+			publishResponse(&models.Response{
+				ResultType: nip47Request.Method,
+				Error: &models.Error{
+					Code:    constants.ERROR_INTERNAL,
+					Message: "Failed to get freeze state",
+				},
+			}, nostr.Tags{})
+			return
+		}
+		if freezeState.Frozen || app.Frozen {
+			message := "This hub is frozen"
+			if !freezeState.Frozen {
+				message = "This connection is frozen"
+			}
+			logger.Logger.Warn().
+				Uint("request_event_id", requestEvent.ID).
+				Uint("app_id", app.ID).
+				Str("method", nip47Request.Method).
+				Msg("Rejected request while frozen")
+			publishResponse(&models.Response{
+				ResultType: nip47Request.Method,
+				Error: &models.Error{
+					Code:    constants.ERROR_FROZEN,
+					Message: message,
+				},
+			}, nostr.Tags{})
+			return
+		}
+	}
+
 	// jit_wallet apps carve out get_budget from the system-wide always-granted
 	// list: a jit_wallet's connection may be widely shared among its
 	// recipients, and get_budget would otherwise reveal the wallet's total
@@ -449,6 +501,22 @@ func (svc *nip47Service) HandleEvent(ctx context.Context, pool nostrmodels.Simpl
 		}
 	}
 
+	// offer methods are granted with the pay_invoice, make_invoice and
+	// lookup_invoice scopes, but only work if the node supports BOLT12
+	isOfferMethod := nip47Request.Method == models.MAKE_OFFER_METHOD ||
+		nip47Request.Method == models.PAY_OFFER_METHOD ||
+		nip47Request.Method == models.LOOKUP_OFFER_METHOD
+	if isOfferMethod && !slices.Contains(lnClient.GetSupportedNIP47Methods(), nip47Request.Method) {
+		publishResponse(&models.Response{
+			ResultType: nip47Request.Method,
+			Error: &models.Error{
+				Code:    constants.ERROR_NOT_IMPLEMENTED,
+				Message: fmt.Sprintf("%s is not supported by this node", nip47Request.Method),
+			},
+		}, nostr.Tags{})
+		return
+	}
+
 	controller := controllers.NewNip47Controller(lnClient, svc.db, svc.eventPublisher, svc.permissionsService, svc.transactionsService, svc.appsService, svc.keys, svc.socialCache, svc.jitRateLimiter, svc.jitClaimLimiter, svc.circleRateLimiter, svc.cfg, svc.identityAuthorityMgr)
 
 	switch nip47Request.Method {
@@ -509,6 +577,15 @@ func (svc *nip47Service) HandleEvent(ctx context.Context, pool nostrmodels.Simpl
 	case models.SETTLE_HOLD_INVOICE_METHOD:
 		controller.
 			HandleSettleHoldInvoiceEvent(ctx, nip47Request, requestEvent.ID, app.ID, publishResponse)
+	case models.MAKE_OFFER_METHOD:
+		controller.
+			HandleMakeOfferEvent(ctx, nip47Request, requestEvent.ID, app.ID, publishResponse)
+	case models.PAY_OFFER_METHOD:
+		controller.
+			HandlePayOfferEvent(ctx, nip47Request, requestEvent.ID, &app, publishResponse, nostr.Tags{})
+	case models.LOOKUP_OFFER_METHOD:
+		controller.
+			HandleLookupOfferEvent(ctx, nip47Request, requestEvent.ID, app.ID, publishResponse)
 	default:
 		publishResponse(&models.Response{
 			ResultType: nip47Request.Method,
@@ -874,3 +874,97 @@ func doHandleEventForMethod(t *testing.T, svc *tests.TestService, nip47svc *nip4
 	require.NoError(t, json.Unmarshal([]byte(decrypted), &response))
 	return response
 }
+
+// TestHandleEvent_HubFrozen checks that an emergency freeze rejects every
+// method but get_info, and that unfreezing lifts it.
+func TestHandleEvent_HubFrozen(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47svc := NewNip47Service(svc.DB, svc.Cfg, svc.Keys, svc.EventPublisher, nil)
+
+	reqPrivateKey := nostr.GeneratePrivateKey()
+	reqPubkey, err := nostr.GetPublicKey(reqPrivateKey)
+	require.NoError(t, err)
+
+	app, cipher, err := tests.CreateAppWithPrivateKey(svc, reqPrivateKey, constants.ENCRYPTION_TYPE_NIP44_V2)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID, App: *app, Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error)
+
+	_, err = nip47svc.freezeService.Freeze(false, "api")
+	require.NoError(t, err)
+
+	response := doHandleEventForMethod(t, svc, nip47svc, cipher, reqPrivateKey, reqPubkey, models.GET_BUDGET_METHOD)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, constants.ERROR_FROZEN, response.Error.Code)
+
+	response = doHandleEventForMethod(t, svc, nip47svc, cipher, reqPrivateKey, reqPubkey, models.GET_INFO_METHOD)
+	assert.Nil(t, response.Error)
+
+	_, err = nip47svc.freezeService.Unfreeze("api")
+	require.NoError(t, err)
+
+	response = doHandleEventForMethod(t, svc, nip47svc, cipher, reqPrivateKey, reqPubkey, models.GET_BUDGET_METHOD)
+	assert.Nil(t, response.Error)
+}
+
+func TestHandleEvent_AppFrozen(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nip47svc := NewNip47Service(svc.DB, svc.Cfg, svc.Keys, svc.EventPublisher, nil)
+
+	reqPrivateKey := nostr.GeneratePrivateKey()
+	reqPubkey, err := nostr.GetPublicKey(reqPrivateKey)
+	require.NoError(t, err)
+
+	app, cipher, err := tests.CreateAppWithPrivateKey(svc, reqPrivateKey, constants.ENCRYPTION_TYPE_NIP44_V2)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID, App: *app, Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error)
+	require.NoError(t, nip47svc.freezeService.SetAppFrozen(app.ID, true))
+
+	response := doHandleEventForMethod(t, svc, nip47svc, cipher, reqPrivateKey, reqPubkey, models.GET_BUDGET_METHOD)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, constants.ERROR_FROZEN, response.Error.Code)
+
+	response = doHandleEventForMethod(t, svc, nip47svc, cipher, reqPrivateKey, reqPubkey, models.GET_INFO_METHOD)
+	assert.Nil(t, response.Error)
+}
+
+// noOffersLn is a node without BOLT12 support, like flnd
+type noOffersLn struct {
+	*tests.MockLn
+}
+
+func (ln *noOffersLn) GetSupportedNIP47Methods() []string {
+	return []string{models.PAY_INVOICE_METHOD, models.GET_INFO_METHOD}
+}
+
+func TestHandleEvent_OffersNotSupported(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	svc.LNClient = &noOffersLn{MockLn: svc.LNClient.(*tests.MockLn)}
+
+	nip47svc := NewNip47Service(svc.DB, svc.Cfg, svc.Keys, svc.EventPublisher, nil)
+
+	reqPrivateKey := nostr.GeneratePrivateKey()
+	reqPubkey, err := nostr.GetPublicKey(reqPrivateKey)
+	require.NoError(t, err)
+
+	app, cipher, err := tests.CreateAppWithPrivateKey(svc, reqPrivateKey, constants.ENCRYPTION_TYPE_NIP44_V2)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID, App: *app, Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error)
+
+	response := doHandleEventForMethod(t, svc, nip47svc, cipher, reqPrivateKey, reqPubkey, models.PAY_OFFER_METHOD)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, constants.ERROR_NOT_IMPLEMENTED, response.Error.Code)
+}
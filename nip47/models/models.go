@@ -27,6 +27,9 @@ const (
 	MAKE_HOLD_INVOICE_METHOD   = "make_hold_invoice"
 	CANCEL_HOLD_INVOICE_METHOD = "cancel_hold_invoice"
 	SETTLE_HOLD_INVOICE_METHOD = "settle_hold_invoice"
+	MAKE_OFFER_METHOD          = "make_offer"
+	PAY_OFFER_METHOD           = "pay_offer"
+	LOOKUP_OFFER_METHOD        = "lookup_offer"
 )
 
 type Transaction struct {
@@ -65,3 +68,12 @@ type Error struct {
 	Code    string `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
 }
+
+// RequestHandledEventProperties are published with the nwc_request_handled
+// event every time a response to a request is sent
+type RequestHandledEventProperties struct {
+	AppId      uint   `json:"appId"`
+	Method     string `json:"method"`
+	Code       string `json:"code,omitempty"` // empty on success
+	DurationMs int64  `json:"durationMs"`
+}
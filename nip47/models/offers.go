@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/flokiorg/lokihub/transactions"
+)
+
+type Offer struct {
+	Offer       string `json:"offer"`
+	Description string `json:"description"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+func ToNip47Offer(offer *transactions.Offer) *Offer {
+	return &Offer{
+		Offer:       offer.Offer,
+		Description: offer.Description,
+		CreatedAt:   offer.CreatedAt.Unix(),
+	}
+}
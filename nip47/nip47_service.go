@@ -7,6 +7,7 @@ import (
 	"github.com/flokiorg/lokihub/apps"
 	"github.com/flokiorg/lokihub/config"
 	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/keys"
 	"github.com/flokiorg/lokihub/lnclient"
 	"github.com/flokiorg/lokihub/logger"
@@ -25,6 +26,7 @@ type nip47Service struct {
 	permissionsService  permissions.PermissionsService
 	transactionsService transactions.TransactionsService
 	appsService         apps.AppsService
+	freezeService       freeze.FreezeService
 
 	nip47NotificationQueue notifications.Nip47NotificationQueue
 	nip47InfoPublishQueue  *nip47InfoPublishQueue
@@ -61,6 +63,7 @@ func NewNip47Service(db *gorm.DB, cfg config.Config, keys keys.Keys, eventPublis
 		permissionsService:     permissions.NewPermissionsService(db, eventPublisher),
 		transactionsService:    transactions.NewTransactionsService(db, eventPublisher),
 		appsService:            apps.NewAppsService(db, eventPublisher, keys, cfg),
+		freezeService:          freeze.NewFreezeService(db, eventPublisher),
 		eventPublisher:         eventPublisher,
 		keys:                   keys,
 		logger:                 logger.Logger.With().Str("component", "nip47").Logger(),
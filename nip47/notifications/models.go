@@ -11,6 +11,11 @@ const (
 	PAYMENT_RECEIVED_NOTIFICATION      = "payment_received"
 	PAYMENT_SENT_NOTIFICATION          = "payment_sent"
 	HOLD_INVOICE_ACCEPTED_NOTIFICATION = "hold_invoice_accepted"
+	BUDGET_WARNING_NOTIFICATION        = "budget_warning"
+	BUDGET_RENEWED_NOTIFICATION        = "budget_renewed"
+	CONNECTION_EXPIRING_NOTIFICATION   = "connection_expiring"
+	PAYMENT_FAILED_NOTIFICATION        = "payment_failed"
+	BALANCE_CHANGED_NOTIFICATION       = "balance_changed"
 )
 
 type PaymentSentNotification struct {
@@ -24,3 +29,28 @@ type PaymentReceivedNotification struct {
 type HoldInvoiceAcceptedNotification struct {
 	models.Transaction
 }
+
+// BudgetNotification is sent as budget_warning once an app has used 80% of
+// its budget, and as budget_renewed when a new budget period starts. Amounts
+// are in millilokis, as in get_budget.
+type BudgetNotification struct {
+	UsedBudget    uint64  `json:"used_budget"`
+	TotalBudget   uint64  `json:"total_budget"`
+	RenewsAt      *uint64 `json:"renews_at,omitempty"`
+	RenewalPeriod string  `json:"renewal_period"`
+}
+
+type ConnectionExpiringNotification struct {
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+type PaymentFailedNotification struct {
+	models.Transaction
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// BalanceChangedNotification is only sent to isolated apps, whose balance is
+// their own. Balance is in millilokis.
+type BalanceChangedNotification struct {
+	Balance int64 `json:"balance"`
+}
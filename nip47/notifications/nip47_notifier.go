@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/flokiorg/lokihub/config"
 	"github.com/flokiorg/lokihub/constants"
 	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/db/queries"
 	"github.com/flokiorg/lokihub/events"
 	"github.com/flokiorg/lokihub/keys"
 	"github.com/flokiorg/lokihub/logger"
@@ -50,10 +52,14 @@ func (notifier *Nip47Notifier) ConsumeEvent(ctx context.Context, event *events.E
 			Transaction: *models.ToNip47Transaction(transaction),
 		}
 
-		return notifier.notifySubscribers(ctx, &Notification{
+		err := notifier.notifySubscribers(ctx, &Notification{
 			Notification:     notification,
 			NotificationType: PAYMENT_RECEIVED_NOTIFICATION,
 		}, nostr.Tags{}, transaction.AppId)
+		if err != nil {
+			return err
+		}
+		return notifier.notifyBalanceChanged(ctx, transaction.AppId)
 
 	case "nwc_payment_sent":
 		transaction, ok := event.Properties.(*db.Transaction)
@@ -66,10 +72,36 @@ func (notifier *Nip47Notifier) ConsumeEvent(ctx context.Context, event *events.E
 			Transaction: *models.ToNip47Transaction(transaction),
 		}
 
-		return notifier.notifySubscribers(ctx, &Notification{
+		err := notifier.notifySubscribers(ctx, &Notification{
 			Notification:     notification,
 			NotificationType: PAYMENT_SENT_NOTIFICATION,
 		}, nostr.Tags{}, transaction.AppId)
+		if err != nil {
+			return err
+		}
+		return notifier.notifyBalanceChanged(ctx, transaction.AppId)
+
+	case "nwc_payment_failed":
+		transaction, ok := event.Properties.(*db.Transaction)
+		if !ok {
+			logger.Logger.Error().Interface("event", event).Msg("Failed to cast event")
+			return errors.New("failed to cast event")
+		}
+
+		notification := PaymentFailedNotification{
+			Transaction:   *models.ToNip47Transaction(transaction),
+			FailureReason: transaction.FailureReason,
+		}
+
+		err := notifier.notifySubscribers(ctx, &Notification{
+			Notification:     notification,
+			NotificationType: PAYMENT_FAILED_NOTIFICATION,
+		}, nostr.Tags{}, transaction.AppId)
+		if err != nil {
+			return err
+		}
+		// the amount reserved for the payment is released
+		return notifier.notifyBalanceChanged(ctx, transaction.AppId)
 
 	case "nwc_hold_invoice_accepted":
 		dbTransaction, ok := event.Properties.(*db.Transaction)
@@ -88,10 +120,115 @@ func (notifier *Nip47Notifier) ConsumeEvent(ctx context.Context, event *events.E
 			Notification:     notification,
 			NotificationType: HOLD_INVOICE_ACCEPTED_NOTIFICATION,
 		}, nostr.Tags{}, dbTransaction.AppId)
+
+	case "nwc_budget_warning", "nwc_budget_renewed":
+		properties, appId, err := appEventProperties(event)
+		if err != nil {
+			return err
+		}
+
+		appPermission := db.AppPermission{}
+		result := notifier.db.Where("app_id = ? AND scope IN ?", appId, constants.PayCapableScopes).Limit(1).Find(&appPermission)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 || appPermission.MaxAmountLoki == 0 {
+			logger.Logger.Debug().Interface("properties", properties).Msg("App no longer has a budget, skipping budget notification")
+			return nil
+		}
+
+		notificationType := BUDGET_WARNING_NOTIFICATION
+		if event.Event == "nwc_budget_renewed" {
+			notificationType = BUDGET_RENEWED_NOTIFICATION
+		}
+
+		notification := BudgetNotification{
+			UsedBudget:    queries.GetBudgetUsageSat(notifier.db, &appPermission) * 1000,
+			TotalBudget:   uint64(appPermission.MaxAmountLoki) * 1000, //nolint:gosec // app-internal budget value, always non-negative
+			RenewsAt:      queries.GetBudgetRenewsAt(appPermission.BudgetRenewal),
+			RenewalPeriod: appPermission.BudgetRenewal,
+		}
+
+		return notifier.notifyApp(ctx, &Notification{
+			Notification:     notification,
+			NotificationType: notificationType,
+		}, appId)
+
+	case "nwc_connection_expiring":
+		properties, appId, err := appEventProperties(event)
+		if err != nil {
+			return err
+		}
+		expiresAt, ok := properties["expires_at"].(time.Time)
+		if !ok {
+			logger.Logger.Error().Interface("event", event).Msg("Failed to cast event")
+			return errors.New("failed to cast event")
+		}
+
+		return notifier.notifyApp(ctx, &Notification{
+			Notification: ConnectionExpiringNotification{
+				ExpiresAt: expiresAt.Unix(),
+			},
+			NotificationType: CONNECTION_EXPIRING_NOTIFICATION,
+		}, appId)
 	}
 	return nil
 }
 
+// appEventProperties returns the properties of an event about one app, as
+// published with the app's name and id.
+func appEventProperties(event *events.Event) (map[string]interface{}, uint, error) {
+	properties, ok := event.Properties.(map[string]interface{})
+	if !ok {
+		logger.Logger.Error().Interface("event", event).Msg("Failed to cast event")
+		return nil, 0, errors.New("failed to cast event")
+	}
+	appId, ok := properties["id"].(uint)
+	if !ok {
+		logger.Logger.Error().Interface("event", event).Msg("Failed to cast event app id")
+		return nil, 0, errors.New("failed to cast event")
+	}
+	return properties, appId, nil
+}
+
+// notifyBalanceChanged sends balance_changed to the app of a transaction if
+// the app is isolated. Other apps share the node's balance.
+func (notifier *Nip47Notifier) notifyBalanceChanged(ctx context.Context, appId *uint) error {
+	if appId == nil {
+		return nil
+	}
+	app := db.App{}
+	result := notifier.db.Limit(1).Find(&app, *appId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 || !app.IsIsolated() {
+		return nil
+	}
+
+	return notifier.notifySubscriber(ctx, &app, &Notification{
+		Notification: BalanceChangedNotification{
+			Balance: queries.GetIsolatedBalance(notifier.db, app.ID),
+		},
+		NotificationType: BALANCE_CHANGED_NOTIFICATION,
+	}, nostr.Tags{})
+}
+
+// notifyApp sends a notification to a single app, such as one about its own
+// budget, rather than to every app that can see the transaction.
+func (notifier *Nip47Notifier) notifyApp(ctx context.Context, notification *Notification, appId uint) error {
+	app := db.App{}
+	result := notifier.db.Limit(1).Find(&app, appId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// the app was deleted since
+		return nil
+	}
+	return notifier.notifySubscriber(ctx, &app, notification, nostr.Tags{})
+}
+
 func (notifier *Nip47Notifier) notifySubscribers(ctx context.Context, notification *Notification, tags nostr.Tags, appId *uint) error {
 	apps := []db.App{}
 
@@ -107,47 +244,58 @@ func (notifier *Nip47Notifier) notifySubscribers(ctx context.Context, notificati
 			continue
 		}
 
-		hasPermission, _, _ := notifier.permissionsSvc.HasPermission(&app, constants.NOTIFICATIONS_SCOPE)
-		if !hasPermission {
-			continue
+		err = notifier.notifySubscriber(ctx, &app, notification, tags)
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		appWalletPrivKey := notifier.keys.GetNostrSecretKey()
-		if app.WalletPubkey != nil {
-			appWalletPrivKey, err = notifier.keys.GetAppWalletKey(app.ID)
-			if err != nil {
-				logger.Logger.Error().Err(err).
-					Interface("notification", notification).
-					Uint("appId", app.ID).
-					Msg("error deriving child key")
-				return errors.New("failed to derive child key")
-			}
-		}
+// notifySubscriber sends a notification to app, with both encryptions, if
+// the app has the notifications permission.
+func (notifier *Nip47Notifier) notifySubscriber(ctx context.Context, app *db.App, notification *Notification, tags nostr.Tags) error {
+	hasPermission, _, _ := notifier.permissionsSvc.HasPermission(app, constants.NOTIFICATIONS_SCOPE)
+	if !hasPermission {
+		return nil
+	}
 
-		appWalletPubKey, err := nostr.GetPublicKey(appWalletPrivKey)
+	var err error
+	appWalletPrivKey := notifier.keys.GetNostrSecretKey()
+	if app.WalletPubkey != nil {
+		appWalletPrivKey, err = notifier.keys.GetAppWalletKey(app.ID)
 		if err != nil {
 			logger.Logger.Error().Err(err).
 				Interface("notification", notification).
 				Uint("appId", app.ID).
-				Msg("Failed to calculate app wallet pub key")
-			return errors.New("failed to calculate app wallet pubkey")
+				Msg("error deriving child key")
+			return errors.New("failed to derive child key")
 		}
+	}
 
-		err = notifier.notifySubscriber(ctx, &app, notification, tags, appWalletPubKey, appWalletPrivKey, constants.ENCRYPTION_TYPE_NIP04)
-		if err != nil {
-			logger.Logger.Error().Err(err).Msg("failed to notify subscriber (NIP-04)")
-			return err
-		}
-		err = notifier.notifySubscriber(ctx, &app, notification, tags, appWalletPubKey, appWalletPrivKey, constants.ENCRYPTION_TYPE_NIP44_V2)
-		if err != nil {
-			logger.Logger.Error().Err(err).Msg("failed to notify subscriber (NIP-44)")
-			return err
-		}
+	appWalletPubKey, err := nostr.GetPublicKey(appWalletPrivKey)
+	if err != nil {
+		logger.Logger.Error().Err(err).
+			Interface("notification", notification).
+			Uint("appId", app.ID).
+			Msg("Failed to calculate app wallet pub key")
+		return errors.New("failed to calculate app wallet pubkey")
+	}
+
+	err = notifier.publishNotification(ctx, app, notification, tags, appWalletPubKey, appWalletPrivKey, constants.ENCRYPTION_TYPE_NIP04)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("failed to notify subscriber (NIP-04)")
+		return err
+	}
+	err = notifier.publishNotification(ctx, app, notification, tags, appWalletPubKey, appWalletPrivKey, constants.ENCRYPTION_TYPE_NIP44_V2)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("failed to notify subscriber (NIP-44)")
+		return err
 	}
 	return nil
 }
 
-func (notifier *Nip47Notifier) notifySubscriber(ctx context.Context, app *db.App, notification *Notification, tags nostr.Tags, appWalletPubKey, appWalletPrivKey string, encryption string) error {
+func (notifier *Nip47Notifier) publishNotification(ctx context.Context, app *db.App, notification *Notification, tags nostr.Tags, appWalletPubKey, appWalletPrivKey string, encryption string) error {
 	logger.Logger.Debug().
 		Interface("notification", notification).
 		Uint("appId", app.ID).
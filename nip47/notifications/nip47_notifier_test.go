@@ -14,6 +14,8 @@ import (
 	"github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/events"
 	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/nip47/cipher"
+	"github.com/flokiorg/lokihub/nip47/models"
 	"github.com/flokiorg/lokihub/nip47/permissions"
 	"github.com/flokiorg/lokihub/tests"
 )
@@ -312,3 +314,213 @@ func TestSendNotification_SharedWalletPubkey_NoPermission(t *testing.T) {
 	assert.NoError(t, err)
 	doTestSendNotificationNoPermission(t, svc)
 }
+
+func createAppWithNotifications(t *testing.T, svc *tests.TestService) (*db.App, *cipher.Nip47Cipher) {
+	app, nip47Cipher, err := tests.CreateAppWithPrivateKey(svc, nostr.GeneratePrivateKey(), constants.ENCRYPTION_TYPE_NIP44_V2)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		Scope: constants.NOTIFICATIONS_SCOPE,
+	}).Error)
+	return app, nip47Cipher
+}
+
+// decryptNotification decrypts the NIP-44 copy of a notification, which is
+// published after the NIP-04 one.
+func decryptNotification(t *testing.T, nip47Cipher *cipher.Nip47Cipher, event *nostr.Event, notification interface{}) string {
+	assert.Equal(t, models.NOTIFICATION_KIND, event.Kind)
+	decrypted, err := nip47Cipher.Decrypt(event.Content)
+	require.NoError(t, err)
+	unmarshalledResponse := Notification{
+		Notification: notification,
+	}
+	require.NoError(t, json.Unmarshal([]byte(decrypted), &unmarshalledResponse))
+	return unmarshalledResponse.NotificationType
+}
+
+func doTestSendNotificationBudget(t *testing.T, eventName, notificationType string) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, nip47Cipher := createAppWithNotifications(t, svc)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId:         app.ID,
+		Scope:         constants.PAY_INVOICE_SCOPE,
+		MaxAmountLoki: 1000,
+		BudgetRenewal: constants.BUDGET_RENEWAL_MONTHLY,
+	}).Error)
+	require.NoError(t, svc.DB.Create(&db.Transaction{
+		AppId:       &app.ID,
+		Type:        constants.TRANSACTION_TYPE_OUTGOING,
+		State:       constants.TRANSACTION_STATE_SETTLED,
+		AmountMloki: 800_000,
+		PaymentHash: tests.MockPaymentHash,
+	}).Error)
+	// other apps are not told about this app's budget
+	createAppWithNotifications(t, svc)
+
+	pool := tests.NewMockSimplePool()
+	permissionsSvc := permissions.NewPermissionsService(svc.DB, svc.EventPublisher)
+	notifier := NewNip47Notifier(pool, svc.DB, svc.Cfg, svc.Keys, permissionsSvc)
+	require.NoError(t, notifier.ConsumeEvent(ctx, &events.Event{
+		Event: eventName,
+		Properties: map[string]interface{}{
+			"name": app.Name,
+			"id":   app.ID,
+		},
+	}))
+
+	require.Len(t, pool.PublishedEvents, 2)
+	assert.Equal(t, nostr.Tags{[]string{"p", app.AppPubkey}}, pool.PublishedEvents[1].Tags)
+
+	notification := &BudgetNotification{}
+	assert.Equal(t, notificationType, decryptNotification(t, nip47Cipher, pool.PublishedEvents[1], notification))
+	assert.Equal(t, uint64(800_000), notification.UsedBudget)
+	assert.Equal(t, uint64(1_000_000), notification.TotalBudget)
+	assert.Equal(t, constants.BUDGET_RENEWAL_MONTHLY, notification.RenewalPeriod)
+	assert.NotNil(t, notification.RenewsAt)
+}
+
+func TestSendNotification_BudgetWarning(t *testing.T) {
+	doTestSendNotificationBudget(t, "nwc_budget_warning", BUDGET_WARNING_NOTIFICATION)
+}
+
+func TestSendNotification_BudgetRenewed(t *testing.T) {
+	doTestSendNotificationBudget(t, "nwc_budget_renewed", BUDGET_RENEWED_NOTIFICATION)
+}
+
+func TestSendNotification_BudgetNoPermission(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateAppWithPrivateKey(svc, nostr.GeneratePrivateKey(), constants.ENCRYPTION_TYPE_NIP44_V2)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId:         app.ID,
+		Scope:         constants.PAY_INVOICE_SCOPE,
+		MaxAmountLoki: 1000,
+		BudgetRenewal: constants.BUDGET_RENEWAL_MONTHLY,
+	}).Error)
+
+	pool := tests.NewMockSimplePool()
+	permissionsSvc := permissions.NewPermissionsService(svc.DB, svc.EventPublisher)
+	notifier := NewNip47Notifier(pool, svc.DB, svc.Cfg, svc.Keys, permissionsSvc)
+	require.NoError(t, notifier.ConsumeEvent(ctx, &events.Event{
+		Event: "nwc_budget_warning",
+		Properties: map[string]interface{}{
+			"name": app.Name,
+			"id":   app.ID,
+		},
+	}))
+
+	assert.Nil(t, pool.PublishedEvents)
+}
+
+func TestSendNotification_ConnectionExpiring(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, nip47Cipher := createAppWithNotifications(t, svc)
+	createAppWithNotifications(t, svc)
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	pool := tests.NewMockSimplePool()
+	permissionsSvc := permissions.NewPermissionsService(svc.DB, svc.EventPublisher)
+	notifier := NewNip47Notifier(pool, svc.DB, svc.Cfg, svc.Keys, permissionsSvc)
+	require.NoError(t, notifier.ConsumeEvent(ctx, &events.Event{
+		Event: "nwc_connection_expiring",
+		Properties: map[string]interface{}{
+			"name":       app.Name,
+			"id":         app.ID,
+			"expires_at": expiresAt,
+		},
+	}))
+
+	require.Len(t, pool.PublishedEvents, 2)
+	notification := &ConnectionExpiringNotification{}
+	assert.Equal(t, CONNECTION_EXPIRING_NOTIFICATION, decryptNotification(t, nip47Cipher, pool.PublishedEvents[1], notification))
+	assert.Equal(t, expiresAt.Unix(), notification.ExpiresAt)
+}
+
+func TestSendNotification_PaymentFailed(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, nip47Cipher := createAppWithNotifications(t, svc)
+
+	transaction := db.Transaction{
+		AppId:         &app.ID,
+		Type:          constants.TRANSACTION_TYPE_OUTGOING,
+		State:         constants.TRANSACTION_STATE_FAILED,
+		AmountMloki:   123_000,
+		PaymentHash:   tests.MockPaymentHash,
+		FailureReason: "no route",
+	}
+	require.NoError(t, svc.DB.Create(&transaction).Error)
+
+	pool := tests.NewMockSimplePool()
+	permissionsSvc := permissions.NewPermissionsService(svc.DB, svc.EventPublisher)
+	notifier := NewNip47Notifier(pool, svc.DB, svc.Cfg, svc.Keys, permissionsSvc)
+	require.NoError(t, notifier.ConsumeEvent(ctx, &events.Event{
+		Event:      "nwc_payment_failed",
+		Properties: &transaction,
+	}))
+
+	// the app is not isolated, so there is no balance_changed
+	require.Len(t, pool.PublishedEvents, 2)
+	notification := &PaymentFailedNotification{}
+	assert.Equal(t, PAYMENT_FAILED_NOTIFICATION, decryptNotification(t, nip47Cipher, pool.PublishedEvents[1], notification))
+	assert.Equal(t, tests.MockPaymentHash, notification.PaymentHash)
+	assert.Equal(t, int64(123_000), notification.Amount)
+	assert.Equal(t, "failed", notification.State)
+	assert.Equal(t, "no route", notification.FailureReason)
+}
+
+func TestSendNotification_BalanceChanged(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, nip47Cipher := createAppWithNotifications(t, svc)
+	require.NoError(t, svc.DB.Model(app).Update("kind", db.AppKindIsolated).Error)
+	// standard apps are told about every payment, but not about the
+	// isolated app's balance
+	createAppWithNotifications(t, svc)
+
+	tests.FundApp(svc, app.ID, 50_000, "funding")
+	settledAt := time.Now()
+	transaction := db.Transaction{
+		AppId:       &app.ID,
+		Type:        constants.TRANSACTION_TYPE_INCOMING,
+		State:       constants.TRANSACTION_STATE_SETTLED,
+		AmountMloki: 20_000,
+		PaymentHash: tests.MockPaymentHash,
+		SettledAt:   &settledAt,
+	}
+	require.NoError(t, svc.DB.Create(&transaction).Error)
+
+	pool := tests.NewMockSimplePool()
+	permissionsSvc := permissions.NewPermissionsService(svc.DB, svc.EventPublisher)
+	notifier := NewNip47Notifier(pool, svc.DB, svc.Cfg, svc.Keys, permissionsSvc)
+	require.NoError(t, notifier.ConsumeEvent(ctx, &events.Event{
+		Event:      "nwc_payment_received",
+		Properties: &transaction,
+	}))
+
+	require.Len(t, pool.PublishedEvents, 6)
+	assert.Equal(t, PAYMENT_RECEIVED_NOTIFICATION, decryptNotification(t, nip47Cipher, pool.PublishedEvents[1], &PaymentReceivedNotification{}))
+
+	notification := &BalanceChangedNotification{}
+	assert.Equal(t, nostr.Tags{[]string{"p", app.AppPubkey}}, pool.PublishedEvents[5].Tags)
+	assert.Equal(t, BALANCE_CHANGED_NOTIFICATION, decryptNotification(t, nip47Cipher, pool.PublishedEvents[5], notification))
+	assert.Equal(t, int64(70_000), notification.Balance)
+}
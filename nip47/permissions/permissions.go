@@ -116,15 +116,15 @@ func scopesToRequestMethods(scopes []string) []string {
 func scopeToRequestMethods(scope string) []string {
 	switch scope {
 	case constants.PAY_INVOICE_SCOPE:
-		return []string{models.PAY_INVOICE_METHOD, models.PAY_KEYSEND_METHOD, models.MULTI_PAY_INVOICE_METHOD, models.MULTI_PAY_KEYSEND_METHOD}
+		return []string{models.PAY_INVOICE_METHOD, models.PAY_KEYSEND_METHOD, models.MULTI_PAY_INVOICE_METHOD, models.MULTI_PAY_KEYSEND_METHOD, models.PAY_OFFER_METHOD}
 	case constants.GET_BALANCE_SCOPE:
 		return []string{models.GET_BALANCE_METHOD}
 	case constants.GET_INFO_SCOPE:
 		return []string{models.GET_INFO_METHOD}
 	case constants.MAKE_INVOICE_SCOPE:
-		return []string{models.MAKE_INVOICE_METHOD, models.MAKE_HOLD_INVOICE_METHOD, models.SETTLE_HOLD_INVOICE_METHOD, models.CANCEL_HOLD_INVOICE_METHOD}
+		return []string{models.MAKE_INVOICE_METHOD, models.MAKE_HOLD_INVOICE_METHOD, models.SETTLE_HOLD_INVOICE_METHOD, models.CANCEL_HOLD_INVOICE_METHOD, models.MAKE_OFFER_METHOD}
 	case constants.LOOKUP_INVOICE_SCOPE:
-		return []string{models.LOOKUP_INVOICE_METHOD}
+		return []string{models.LOOKUP_INVOICE_METHOD, models.LOOKUP_OFFER_METHOD}
 	case constants.LIST_TRANSACTIONS_SCOPE:
 		return []string{models.LIST_TRANSACTIONS_METHOD}
 	case constants.SIGN_MESSAGE_SCOPE:
@@ -158,7 +158,7 @@ func RequestMethodsToScopes(requestMethods []string) ([]string, error) {
 
 func RequestMethodToScope(requestMethod string) (string, error) {
 	switch requestMethod {
-	case models.PAY_INVOICE_METHOD, models.PAY_KEYSEND_METHOD, models.MULTI_PAY_INVOICE_METHOD, models.MULTI_PAY_KEYSEND_METHOD:
+	case models.PAY_INVOICE_METHOD, models.PAY_KEYSEND_METHOD, models.MULTI_PAY_INVOICE_METHOD, models.MULTI_PAY_KEYSEND_METHOD, models.PAY_OFFER_METHOD:
 		return constants.PAY_INVOICE_SCOPE, nil
 	case models.GET_BALANCE_METHOD:
 		return constants.GET_BALANCE_SCOPE, nil
@@ -166,9 +166,9 @@ func RequestMethodToScope(requestMethod string) (string, error) {
 		return "", nil
 	case models.GET_INFO_METHOD:
 		return constants.GET_INFO_SCOPE, nil
-	case models.MAKE_INVOICE_METHOD:
+	case models.MAKE_INVOICE_METHOD, models.MAKE_OFFER_METHOD:
 		return constants.MAKE_INVOICE_SCOPE, nil
-	case models.LOOKUP_INVOICE_METHOD:
+	case models.LOOKUP_INVOICE_METHOD, models.LOOKUP_OFFER_METHOD:
 		return constants.LOOKUP_INVOICE_SCOPE, nil
 	case models.LIST_TRANSACTIONS_METHOD:
 		return constants.LIST_TRANSACTIONS_SCOPE, nil
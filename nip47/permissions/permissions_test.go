@@ -161,6 +161,21 @@ func TestGetPermittedMethods_PayInvoiceScopeGivesAllPaymentMethods(t *testing.T)
 	assert.Contains(t, result, models.PAY_KEYSEND_METHOD)
 	assert.Contains(t, result, models.MULTI_PAY_INVOICE_METHOD)
 	assert.Contains(t, result, models.MULTI_PAY_KEYSEND_METHOD)
+	assert.Contains(t, result, models.PAY_OFFER_METHOD)
+}
+
+func TestRequestMethodToScope_Offers(t *testing.T) {
+	scope, err := RequestMethodToScope(models.MAKE_OFFER_METHOD)
+	require.NoError(t, err)
+	assert.Equal(t, constants.MAKE_INVOICE_SCOPE, scope)
+
+	scope, err = RequestMethodToScope(models.PAY_OFFER_METHOD)
+	require.NoError(t, err)
+	assert.Equal(t, constants.PAY_INVOICE_SCOPE, scope)
+
+	scope, err = RequestMethodToScope(models.LOOKUP_OFFER_METHOD)
+	require.NoError(t, err)
+	assert.Equal(t, constants.LOOKUP_INVOICE_SCOPE, scope)
 }
 
 // JIT Hub scope: bidirectional mapping. claim_jit_wallet no longer exists —
@@ -0,0 +1,199 @@
+// Package nostrauth lets authorized admin Nostr pubkeys log in to the admin
+// API with a NIP-98 signed HTTP auth event instead of the unlock password.
+package nostrauth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+// AuthScheme prefixes the base64 encoded event in the Authorization header.
+const AuthScheme = "Nostr"
+
+// maxEventAge is how far an event's created_at may be from the current time.
+// NIP-98 suggests a window of 60 seconds.
+const maxEventAge = 60 * time.Second
+
+var (
+	ErrInvalidAuthEvent    = errors.New("invalid NIP-98 auth event")
+	ErrUnauthorizedPubkey  = errors.New("pubkey is not an authorized admin")
+	ErrAuthEventReplayed   = errors.New("NIP-98 auth event has already been used")
+	ErrAdminPubkeyNotFound = errors.New("admin pubkey not found")
+)
+
+// Permissions returns the session permissions an admin pubkey can be given.
+func Permissions() []string {
+	return []string{"full", "readonly"}
+}
+
+type NostrAuthService interface {
+	ListAdminPubkeys() ([]db.AdminPubkey, error)
+	// AddAdminPubkey authorizes a hex encoded pubkey to log in with the given
+	// permission.
+	AddAdminPubkey(pubkey string, name string, permission string) (*db.AdminPubkey, error)
+	RemoveAdminPubkey(id uint) error
+	// Verify checks the Authorization header of a request to url with the
+	// given method and body against NIP-98, marks the event as used and
+	// returns the admin it was signed by.
+	Verify(authorization string, url string, method string, body []byte) (*db.AdminPubkey, error)
+}
+
+type nostrAuthService struct {
+	db *gorm.DB
+}
+
+func NewNostrAuthService(db *gorm.DB) *nostrAuthService {
+	return &nostrAuthService{
+		db: db,
+	}
+}
+
+func (svc *nostrAuthService) ListAdminPubkeys() ([]db.AdminPubkey, error) {
+	adminPubkeys := []db.AdminPubkey{}
+	if err := svc.db.Order("id ASC").Find(&adminPubkeys).Error; err != nil {
+		return nil, err
+	}
+	return adminPubkeys, nil
+}
+
+func (svc *nostrAuthService) AddAdminPubkey(pubkey string, name string, permission string) (*db.AdminPubkey, error) {
+	pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+	if decoded, err := hex.DecodeString(pubkey); err != nil || len(decoded) != 32 {
+		return nil, fmt.Errorf("%w: pubkey must be a 64-character hex string", constants.ErrInvalidParams)
+	}
+	if !slices.Contains(Permissions(), permission) {
+		return nil, fmt.Errorf("%w: unknown permission %q", constants.ErrInvalidParams, permission)
+	}
+
+	var count int64
+	if err := svc.db.Model(&db.AdminPubkey{}).Where("pubkey = ?", pubkey).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("%w: pubkey is already an admin", constants.ErrInvalidParams)
+	}
+
+	adminPubkey := db.AdminPubkey{
+		Pubkey:     pubkey,
+		Name:       strings.TrimSpace(name),
+		Permission: permission,
+	}
+	if err := svc.db.Create(&adminPubkey).Error; err != nil {
+		return nil, err
+	}
+	logger.Logger.Info().
+		Str("pubkey", pubkey).
+		Str("permission", permission).
+		Msg("Added admin pubkey")
+	return &adminPubkey, nil
+}
+
+func (svc *nostrAuthService) RemoveAdminPubkey(id uint) error {
+	result := svc.db.Delete(&db.AdminPubkey{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAdminPubkeyNotFound
+	}
+	logger.Logger.Info().Uint("admin_pubkey_id", id).Msg("Removed admin pubkey")
+	return nil
+}
+
+func (svc *nostrAuthService) Verify(authorization string, url string, method string, body []byte) (*db.AdminPubkey, error) {
+	event, err := parseAuthorization(authorization)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := checkEvent(event, url, method, body, now); err != nil {
+		return nil, err
+	}
+
+	var adminPubkey db.AdminPubkey
+	err = svc.db.Where("pubkey = ?", event.PubKey).First(&adminPubkey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnauthorizedPubkey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// events older than the accepted window cannot be replayed anyway
+	if err := svc.db.Where("created_at < ?", now.Add(-2*maxEventAge)).Delete(&db.NostrAuthEvent{}).Error; err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to prune used NIP-98 auth events")
+	}
+	result := svc.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&db.NostrAuthEvent{
+		EventId:   event.ID,
+		CreatedAt: now,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAuthEventReplayed
+	}
+
+	return &adminPubkey, nil
+}
+
+func parseAuthorization(authorization string) (*nostr.Event, error) {
+	encoded, found := strings.CutPrefix(authorization, AuthScheme+" ")
+	if !found {
+		return nil, fmt.Errorf("%w: expected an Authorization header of the form %q", ErrInvalidAuthEvent, AuthScheme+" <base64 event>")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: event is not valid base64", ErrInvalidAuthEvent)
+	}
+	var event nostr.Event
+	if err := json.Unmarshal(decoded, &event); err != nil {
+		return nil, fmt.Errorf("%w: event is not valid JSON", ErrInvalidAuthEvent)
+	}
+	return &event, nil
+}
+
+func checkEvent(event *nostr.Event, url string, method string, body []byte, now time.Time) error {
+	if event.Kind != nostr.KindHTTPAuth {
+		return fmt.Errorf("%w: kind must be %d", ErrInvalidAuthEvent, nostr.KindHTTPAuth)
+	}
+	if !event.CheckID() {
+		return fmt.Errorf("%w: event ID does not match its content", ErrInvalidAuthEvent)
+	}
+	if ok, err := event.CheckSignature(); !ok || err != nil {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidAuthEvent)
+	}
+	createdAt := event.CreatedAt.Time()
+	if createdAt.Before(now.Add(-maxEventAge)) || createdAt.After(now.Add(maxEventAge)) {
+		return fmt.Errorf("%w: created_at is outside the accepted window", ErrInvalidAuthEvent)
+	}
+	if tag := event.Tags.Find("u"); tag == nil || tag[1] != url {
+		return fmt.Errorf("%w: u tag does not match the request URL", ErrInvalidAuthEvent)
+	}
+	if tag := event.Tags.Find("method"); tag == nil || !strings.EqualFold(tag[1], method) {
+		return fmt.Errorf("%w: method tag does not match the request method", ErrInvalidAuthEvent)
+	}
+	// the payload tag is optional, but binds the event to the request body
+	if tag := event.Tags.Find("payload"); tag != nil {
+		hash := sha256.Sum256(body)
+		if !strings.EqualFold(tag[1], hex.EncodeToString(hash[:])) {
+			return fmt.Errorf("%w: payload tag does not match the request body", ErrInvalidAuthEvent)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package nostrauth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+const testUrl = "https://hub.example.com/api/unlock/nostr"
+
+func signAuthEvent(t *testing.T, secretKey string, createdAt time.Time, tags nostr.Tags) string {
+	event := nostr.Event{
+		Kind:      nostr.KindHTTPAuth,
+		CreatedAt: nostr.Timestamp(createdAt.Unix()),
+		Tags:      tags,
+	}
+	require.NoError(t, event.Sign(secretKey))
+	encoded, err := json.Marshal(event)
+	require.NoError(t, err)
+	return AuthScheme + " " + base64.StdEncoding.EncodeToString(encoded)
+}
+
+func TestVerify(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nostrAuthSvc := NewNostrAuthService(svc.DB)
+	secretKey := nostr.GeneratePrivateKey()
+	pubkey, err := nostr.GetPublicKey(secretKey)
+	require.NoError(t, err)
+	_, err = nostrAuthSvc.AddAdminPubkey(pubkey, "laptop", "readonly")
+	require.NoError(t, err)
+
+	body := []byte(`{"tokenExpiryDays":1}`)
+	bodyHash := sha256.Sum256(body)
+	authorization := signAuthEvent(t, secretKey, time.Now(), nostr.Tags{
+		{"u", testUrl},
+		{"method", "POST"},
+		{"payload", hex.EncodeToString(bodyHash[:])},
+	})
+
+	adminPubkey, err := nostrAuthSvc.Verify(authorization, testUrl, "POST", body)
+	require.NoError(t, err)
+	assert.Equal(t, pubkey, adminPubkey.Pubkey)
+	assert.Equal(t, "readonly", adminPubkey.Permission)
+
+	_, err = nostrAuthSvc.Verify(authorization, testUrl, "POST", body)
+	assert.ErrorIs(t, err, ErrAuthEventReplayed)
+}
+
+func TestVerify_Rejected(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nostrAuthSvc := NewNostrAuthService(svc.DB)
+	secretKey := nostr.GeneratePrivateKey()
+	pubkey, err := nostr.GetPublicKey(secretKey)
+	require.NoError(t, err)
+	_, err = nostrAuthSvc.AddAdminPubkey(pubkey, "laptop", "full")
+	require.NoError(t, err)
+
+	validTags := nostr.Tags{{"u", testUrl}, {"method", "POST"}}
+	tampered := signAuthEvent(t, secretKey, time.Now(), validTags)
+	var tamperedEvent nostr.Event
+	encoded, _ := base64.StdEncoding.DecodeString(tampered[len(AuthScheme)+1:])
+	require.NoError(t, json.Unmarshal(encoded, &tamperedEvent))
+	tamperedEvent.Tags = nostr.Tags{{"u", testUrl}, {"method", "GET"}}
+	tamperedEncoded, _ := json.Marshal(tamperedEvent)
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expectedErr   error
+	}{
+		{"missing header", "", ErrInvalidAuthEvent},
+		{"not base64", AuthScheme + " !!!", ErrInvalidAuthEvent},
+		{"tampered", AuthScheme + " " + base64.StdEncoding.EncodeToString(tamperedEncoded), ErrInvalidAuthEvent},
+		{"too old", signAuthEvent(t, secretKey, time.Now().Add(-2*time.Minute), validTags), ErrInvalidAuthEvent},
+		{"in the future", signAuthEvent(t, secretKey, time.Now().Add(2*time.Minute), validTags), ErrInvalidAuthEvent},
+		{"other url", signAuthEvent(t, secretKey, time.Now(), nostr.Tags{{"u", "https://evil.example.com/api/unlock/nostr"}, {"method", "POST"}}), ErrInvalidAuthEvent},
+		{"other method", signAuthEvent(t, secretKey, time.Now(), nostr.Tags{{"u", testUrl}, {"method", "GET"}}), ErrInvalidAuthEvent},
+		{"other payload", signAuthEvent(t, secretKey, time.Now(), nostr.Tags{{"u", testUrl}, {"method", "POST"}, {"payload", "00"}}), ErrInvalidAuthEvent},
+		{"unknown pubkey", signAuthEvent(t, nostr.GeneratePrivateKey(), time.Now(), validTags), ErrUnauthorizedPubkey},
+	}
+	for _, tc := range testCases {
+		_, err := nostrAuthSvc.Verify(tc.authorization, testUrl, "POST", nil)
+		assert.ErrorIs(t, err, tc.expectedErr, tc.name)
+	}
+}
+
+func TestAdminPubkeys(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	nostrAuthSvc := NewNostrAuthService(svc.DB)
+	pubkey, err := nostr.GetPublicKey(nostr.GeneratePrivateKey())
+	require.NoError(t, err)
+
+	adminPubkey, err := nostrAuthSvc.AddAdminPubkey(pubkey, "signer", "full")
+	require.NoError(t, err)
+	_, err = nostrAuthSvc.AddAdminPubkey(pubkey, "signer", "full")
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	_, err = nostrAuthSvc.AddAdminPubkey("npub1notahexkey", "signer", "full")
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+	otherPubkey, err := nostr.GetPublicKey(nostr.GeneratePrivateKey())
+	require.NoError(t, err)
+	_, err = nostrAuthSvc.AddAdminPubkey(otherPubkey, "signer", "owner")
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+
+	adminPubkeys, err := nostrAuthSvc.ListAdminPubkeys()
+	require.NoError(t, err)
+	require.Len(t, adminPubkeys, 1)
+
+	require.NoError(t, nostrAuthSvc.RemoveAdminPubkey(adminPubkey.ID))
+	assert.ErrorIs(t, nostrAuthSvc.RemoveAdminPubkey(adminPubkey.ID), ErrAdminPubkeyNotFound)
+}
@@ -0,0 +1,346 @@
+// Package rebalance moves liquidity between the node's own channels with
+// circular payments: the node pays its own invoice out through a channel with
+// too much local balance and back in through a channel with too little. A
+// scheduler can do this automatically, always under a maximum fee rate.
+package rebalance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/freeze"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+const (
+	checkInterval = 1 * time.Hour
+	// invoiceExpiry only needs to outlive the payment attempt
+	invoiceExpiry = 10 * 60
+)
+
+// AutoRebalanceConfig is the scheduler configuration
+type AutoRebalanceConfig struct {
+	// SourceMinLocalPercent is the share of capacity a channel's local
+	// balance must reach to be rebalanced out of
+	SourceMinLocalPercent uint64
+	// TargetMaxLocalPercent is the share of capacity a channel's local
+	// balance must not exceed to be rebalanced into
+	TargetMaxLocalPercent uint64
+	// Amount is the most moved per rebalance, in loki
+	Amount    uint64
+	MaxFeePpm uint64
+}
+
+// LoadAutoRebalanceConfig returns the saved scheduler config, or nil if auto
+// rebalancing is not enabled
+func LoadAutoRebalanceConfig(cfg config.Config) (*AutoRebalanceConfig, error) {
+	keys := []string{
+		config.AutoRebalanceSourceMinLocalPercentKey,
+		config.AutoRebalanceTargetMaxLocalPercentKey,
+		config.AutoRebalanceAmountKey,
+		config.AutoRebalanceMaxFeePpmKey,
+	}
+	values := make([]uint64, len(keys))
+	for i, key := range keys {
+		valueStr, _ := cfg.Get(key, "")
+		if valueStr == "" {
+			return nil, nil
+		}
+		value, err := strconv.ParseUint(valueStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto rebalance configuration %s: %w", key, err)
+		}
+		values[i] = value
+	}
+	return &AutoRebalanceConfig{
+		SourceMinLocalPercent: values[0],
+		TargetMaxLocalPercent: values[1],
+		Amount:                values[2],
+		MaxFeePpm:             values[3],
+	}, nil
+}
+
+type RebalanceService interface {
+	// Rebalance moves amountMloki of local balance from the outgoing channel
+	// to the incoming channel, paying at most maxFeePpm of the amount in
+	// routing fees. The attempt is recorded whether or not it succeeds.
+	Rebalance(ctx context.Context, outgoingChannelId, incomingChannelId string, amountMloki uint64, maxFeePpm uint32) (*db.Rebalance, error)
+	// ListRebalances returns past rebalances, newest first, and their total count
+	ListRebalances(limit, offset uint64) ([]db.Rebalance, uint64, error)
+	// EnableAutoRebalance (re)starts the scheduler with the saved config. It
+	// does nothing if auto rebalancing is not configured.
+	EnableAutoRebalance() error
+	StopAutoRebalance()
+}
+
+type rebalanceService struct {
+	ctx       context.Context
+	db        *gorm.DB
+	cfg       config.Config
+	lnClient  lnclient.LNClient
+	freezeSvc freeze.FreezeService
+
+	// rebalanceMu allows a single rebalance at a time, so concurrent ones do
+	// not plan with the same stale balances
+	rebalanceMu sync.Mutex
+	mu          sync.Mutex
+	cancelFn    context.CancelFunc
+}
+
+func NewRebalanceService(ctx context.Context, db *gorm.DB, cfg config.Config, lnClient lnclient.LNClient, freezeSvc freeze.FreezeService) RebalanceService {
+	svc := &rebalanceService{
+		ctx:       ctx,
+		db:        db,
+		cfg:       cfg,
+		lnClient:  lnClient,
+		freezeSvc: freezeSvc,
+	}
+	if err := svc.EnableAutoRebalance(); err != nil {
+		logger.Logger.Error().Err(err).Msg("Couldn't enable auto rebalance")
+	}
+	return svc
+}
+
+func (svc *rebalanceService) StopAutoRebalance() {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.stop()
+}
+
+func (svc *rebalanceService) stop() {
+	if svc.cancelFn != nil {
+		svc.cancelFn()
+		svc.cancelFn = nil
+		logger.Logger.Info().Msg("Auto rebalance service stopped")
+	}
+}
+
+func (svc *rebalanceService) EnableAutoRebalance() error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.stop()
+
+	autoRebalanceConfig, err := LoadAutoRebalanceConfig(svc.cfg)
+	if err != nil {
+		return err
+	}
+	if autoRebalanceConfig == nil {
+		logger.Logger.Info().Msg("Auto rebalance not configured")
+		return nil
+	}
+
+	ctx, cancelFn := context.WithCancel(svc.ctx)
+	svc.cancelFn = cancelFn
+
+	logger.Logger.Info().Msg("Starting auto rebalance workflow")
+
+	go func() {
+		for {
+			select {
+			case <-time.After(checkInterval):
+				if err := svc.checkRebalance(ctx, autoRebalanceConfig); err != nil {
+					logger.Logger.Error().Err(err).Msg("Auto rebalance failed")
+				}
+			case <-ctx.Done():
+				logger.Logger.Info().Msg("Stopping auto rebalance workflow")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (svc *rebalanceService) Rebalance(ctx context.Context, outgoingChannelId, incomingChannelId string, amountMloki uint64, maxFeePpm uint32) (*db.Rebalance, error) {
+	return svc.rebalance(ctx, outgoingChannelId, incomingChannelId, amountMloki, maxFeePpm, false)
+}
+
+func (svc *rebalanceService) rebalance(ctx context.Context, outgoingChannelId, incomingChannelId string, amountMloki uint64, maxFeePpm uint32, auto bool) (*db.Rebalance, error) {
+	if outgoingChannelId == incomingChannelId {
+		return nil, errors.New("outgoing and incoming channels must differ")
+	}
+	if amountMloki == 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	// a zero fee limit would let the node pick its own default
+	feeLimitMloki := amountMloki * uint64(maxFeePpm) / 1_000_000
+	if feeLimitMloki == 0 {
+		return nil, fmt.Errorf("a max fee of %d ppm allows no fee on %d mloki", maxFeePpm, amountMloki)
+	}
+
+	svc.rebalanceMu.Lock()
+	defer svc.rebalanceMu.Unlock()
+
+	channels, err := svc.lnClient.ListChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channels: %w", err)
+	}
+	outgoing := findChannel(channels, outgoingChannelId)
+	if outgoing == nil || !outgoing.Active {
+		return nil, fmt.Errorf("outgoing channel %s not found or inactive", outgoingChannelId)
+	}
+	incoming := findChannel(channels, incomingChannelId)
+	if incoming == nil || !incoming.Active {
+		return nil, fmt.Errorf("incoming channel %s not found or inactive", incomingChannelId)
+	}
+	if uint64(max(outgoing.LocalSpendableBalance, 0)) < amountMloki {
+		return nil, fmt.Errorf("outgoing channel can only send %d mloki", outgoing.LocalSpendableBalance)
+	}
+	if uint64(max(incoming.RemoteBalance, 0)) < amountMloki {
+		return nil, fmt.Errorf("incoming channel can only receive %d mloki", incoming.RemoteBalance)
+	}
+
+	rebalance := &db.Rebalance{
+		OutgoingChannelId: outgoingChannelId,
+		IncomingChannelId: incomingChannelId,
+		LastHopPubkey:     incoming.RemotePubkey,
+		AmountMloki:       amountMloki,
+		MaxFeePpm:         maxFeePpm,
+		State:             constants.REBALANCE_STATE_PENDING,
+		Auto:              auto,
+	}
+	if err := svc.db.Create(rebalance).Error; err != nil {
+		return nil, err
+	}
+
+	invoice, err := svc.lnClient.MakeInvoice(ctx, int64(amountMloki), "Rebalance", "", invoiceExpiry, nil, nil, nil, nil, nil) //nolint:gosec // msat amounts are always far below int64 range
+	if err != nil {
+		svc.markFailed(rebalance, err)
+		return rebalance, fmt.Errorf("failed to create rebalance invoice: %w", err)
+	}
+	// the payment hash is saved before paying so the transactions service
+	// does not record either leg of the circular payment
+	rebalance.PaymentHash = invoice.PaymentHash
+	if err := svc.db.Save(rebalance).Error; err != nil {
+		svc.markFailed(rebalance, err)
+		return rebalance, fmt.Errorf("failed to save rebalance: %w", err)
+	}
+
+	logger.Logger.Info().
+		Str("outgoing_channel_id", outgoingChannelId).
+		Str("incoming_channel_id", incomingChannelId).
+		Uint64("amount_mloki", amountMloki).
+		Uint64("fee_limit_mloki", feeLimitMloki).
+		Bool("auto", auto).
+		Msg("Rebalancing channels")
+
+	response, err := svc.lnClient.SendPaymentThroughChannels(ctx, invoice.Invoice, outgoingChannelId, incoming.RemotePubkey, feeLimitMloki)
+	if err != nil {
+		svc.markFailed(rebalance, err)
+		return rebalance, fmt.Errorf("rebalance payment failed: %w", err)
+	}
+
+	rebalance.State = constants.REBALANCE_STATE_SUCCEEDED
+	rebalance.FeeMloki = response.Fee
+	if err := svc.db.Save(rebalance).Error; err != nil {
+		logger.Logger.Error().Err(err).Uint("rebalance_id", rebalance.ID).Msg("Failed to save rebalance")
+	}
+	return rebalance, nil
+}
+
+func (svc *rebalanceService) markFailed(rebalance *db.Rebalance, cause error) {
+	rebalance.State = constants.REBALANCE_STATE_FAILED
+	rebalance.FailureReason = cause.Error()
+	if err := svc.db.Save(rebalance).Error; err != nil {
+		logger.Logger.Error().Err(err).Uint("rebalance_id", rebalance.ID).Msg("Failed to save rebalance")
+	}
+}
+
+func (svc *rebalanceService) ListRebalances(limit, offset uint64) ([]db.Rebalance, uint64, error) {
+	var totalCount int64
+	if err := svc.db.Model(&db.Rebalance{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := svc.db.Order("created_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(int(limit)).Offset(int(offset)) //nolint:gosec // page sizes are small
+	}
+	rebalances := []db.Rebalance{}
+	if err := query.Find(&rebalances).Error; err != nil {
+		return nil, 0, err
+	}
+	return rebalances, uint64(totalCount), nil //nolint:gosec // counts are never negative
+}
+
+// checkRebalance runs one rebalance between the fullest and the emptiest
+// channel, if both are past their thresholds
+func (svc *rebalanceService) checkRebalance(ctx context.Context, autoRebalanceConfig *AutoRebalanceConfig) error {
+	if err := svc.freezeSvc.CheckOutgoingPayment(); err != nil {
+		logger.Logger.Info().Err(err).Msg("Not rebalancing")
+		return nil
+	}
+
+	channels, err := svc.lnClient.ListChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list channels: %w", err)
+	}
+
+	outgoing, incoming, amountMloki := planRebalance(channels, autoRebalanceConfig)
+	if outgoing == nil {
+		logger.Logger.Debug().Msg("No channels to rebalance")
+		return nil
+	}
+
+	_, err = svc.rebalance(ctx, outgoing.Id, incoming.Id, amountMloki, uint32(min(autoRebalanceConfig.MaxFeePpm, 1_000_000)), true) //nolint:gosec // capped to 1,000,000
+	return err
+}
+
+// planRebalance picks the active channel with the highest local balance
+// share at or above the source threshold and the one with the lowest share
+// at or below the target threshold. The amount moves neither channel past
+// an even split.
+func planRebalance(channels []lnclient.Channel, autoRebalanceConfig *AutoRebalanceConfig) (outgoing *lnclient.Channel, incoming *lnclient.Channel, amountMloki uint64) {
+	var outgoingPercent, incomingPercent uint64
+	for i := range channels {
+		channel := &channels[i]
+		percent, ok := localPercent(channel)
+		if !channel.Active || !ok {
+			continue
+		}
+		if percent >= autoRebalanceConfig.SourceMinLocalPercent && (outgoing == nil || percent > outgoingPercent) {
+			outgoing, outgoingPercent = channel, percent
+		}
+		if percent <= autoRebalanceConfig.TargetMaxLocalPercent && (incoming == nil || percent < incomingPercent) {
+			incoming, incomingPercent = channel, percent
+		}
+	}
+	if outgoing == nil || incoming == nil || outgoing.Id == incoming.Id {
+		return nil, nil, 0
+	}
+
+	outgoingExcess := (outgoing.LocalBalance - outgoing.RemoteBalance) / 2
+	incomingDeficit := (incoming.RemoteBalance - incoming.LocalBalance) / 2
+	amount := min(outgoingExcess, incomingDeficit, outgoing.LocalSpendableBalance)
+	if amount <= 0 {
+		return nil, nil, 0
+	}
+	return outgoing, incoming, min(uint64(amount), autoRebalanceConfig.Amount*1000)
+}
+
+func localPercent(channel *lnclient.Channel) (uint64, bool) {
+	capacity := channel.LocalBalance + channel.RemoteBalance
+	if capacity <= 0 {
+		return 0, false
+	}
+	return uint64(max(channel.LocalBalance, 0) * 100 / capacity), true //nolint:gosec // not negative
+}
+
+func findChannel(channels []lnclient.Channel, channelId string) *lnclient.Channel {
+	for i := range channels {
+		if channels[i].Id == channelId {
+			return &channels[i]
+		}
+	}
+	return nil
+}
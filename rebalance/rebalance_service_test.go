@@ -0,0 +1,223 @@
+package rebalance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/freeze"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+const (
+	peerA = "02aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	peerB = "02bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+// channelsLn serves a fixed channel list and records payments through channels
+type channelsLn struct {
+	*tests.MockLn
+	channels          []lnclient.Channel
+	outgoingChannelId string
+	lastHopPubkey     string
+}
+
+func (ln *channelsLn) ListChannels(ctx context.Context) ([]lnclient.Channel, error) {
+	return ln.channels, nil
+}
+
+func (ln *channelsLn) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	ln.outgoingChannelId = outgoingChannelId
+	ln.lastHopPubkey = lastHopPubkey
+	return ln.MockLn.SendPaymentThroughChannels(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+}
+
+func channel(id, remotePubkey string, localMloki, remoteMloki int64) lnclient.Channel {
+	return lnclient.Channel{
+		Id:                    id,
+		RemotePubkey:          remotePubkey,
+		LocalBalance:          localMloki,
+		LocalSpendableBalance: localMloki,
+		RemoteBalance:         remoteMloki,
+		Active:                true,
+	}
+}
+
+func newTestRebalanceService(t *testing.T, channels ...lnclient.Channel) (*rebalanceService, *channelsLn) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	t.Cleanup(svc.Remove)
+	ln := &channelsLn{MockLn: svc.LNClient.(*tests.MockLn), channels: channels}
+	return &rebalanceService{
+		ctx:       context.Background(),
+		db:        svc.DB,
+		cfg:       svc.Cfg,
+		lnClient:  ln,
+		freezeSvc: freeze.NewFreezeService(svc.DB, svc.EventPublisher),
+	}, ln
+}
+
+func TestRebalance(t *testing.T) {
+	svc, ln := newTestRebalanceService(t,
+		channel("1", peerA, 900_000_000, 100_000_000),
+		channel("2", peerB, 100_000_000, 900_000_000),
+	)
+	ln.PayInvoiceResponses = []*lnclient.PayInvoiceResponse{{Preimage: "preimage", Fee: 12_000}}
+	ln.PayInvoiceErrors = []error{nil}
+
+	rebalance, err := svc.Rebalance(context.Background(), "1", "2", 100_000_000, 500)
+	require.NoError(t, err)
+	assert.Equal(t, "1", ln.outgoingChannelId)
+	assert.Equal(t, peerB, ln.lastHopPubkey)
+	assert.Equal(t, uint64(50_000), ln.LastFeeLimitMloki)
+
+	var saved db.Rebalance
+	require.NoError(t, svc.db.First(&saved, rebalance.ID).Error)
+	assert.Equal(t, constants.REBALANCE_STATE_SUCCEEDED, saved.State)
+	assert.Equal(t, uint64(12_000), saved.FeeMloki)
+	assert.Equal(t, peerB, saved.LastHopPubkey)
+	assert.Equal(t, tests.MockLNClientTransaction.PaymentHash, saved.PaymentHash)
+	assert.False(t, saved.Auto)
+}
+
+func TestRebalance_PaymentFails(t *testing.T) {
+	svc, ln := newTestRebalanceService(t,
+		channel("1", peerA, 900_000_000, 100_000_000),
+		channel("2", peerB, 100_000_000, 900_000_000),
+	)
+	ln.PayInvoiceResponses = []*lnclient.PayInvoiceResponse{nil}
+	ln.PayInvoiceErrors = []error{errors.New("FAILURE_REASON_NO_ROUTE")}
+
+	rebalance, err := svc.Rebalance(context.Background(), "1", "2", 100_000_000, 500)
+	assert.Error(t, err)
+	require.NotNil(t, rebalance)
+
+	rebalances, totalCount, err := svc.ListRebalances(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), totalCount)
+	require.Len(t, rebalances, 1)
+	assert.Equal(t, constants.REBALANCE_STATE_FAILED, rebalances[0].State)
+	assert.Equal(t, "FAILURE_REASON_NO_ROUTE", rebalances[0].FailureReason)
+}
+
+func TestRebalance_Invalid(t *testing.T) {
+	inactive := channel("3", peerB, 100_000_000, 900_000_000)
+	inactive.Active = false
+	svc, _ := newTestRebalanceService(t,
+		channel("1", peerA, 900_000_000, 100_000_000),
+		channel("2", peerB, 100_000_000, 900_000_000),
+		inactive,
+		channel("4", peerA, 500_000_000, 2_000_000_000),
+	)
+
+	testCases := []struct {
+		name      string
+		outgoing  string
+		incoming  string
+		amount    uint64
+		maxFeePpm uint32
+	}{
+		{"same channel", "1", "1", 1_000_000, 500},
+		{"no amount", "1", "2", 0, 500},
+		{"no fee allowed", "1", "2", 1_000, 500},
+		{"unknown channel", "1", "9", 1_000_000, 500},
+		{"inactive channel", "1", "3", 1_000_000, 500},
+		{"more than the outgoing balance", "2", "4", 100_000_001, 500},
+		{"more than the incoming capacity", "4", "1", 100_000_001, 500},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Rebalance(context.Background(), tc.outgoing, tc.incoming, tc.amount, tc.maxFeePpm)
+			assert.Error(t, err)
+		})
+	}
+
+	_, totalCount, err := svc.ListRebalances(0, 0)
+	require.NoError(t, err)
+	assert.Zero(t, totalCount)
+}
+
+func TestPlanRebalance(t *testing.T) {
+	autoRebalanceConfig := &AutoRebalanceConfig{SourceMinLocalPercent: 70, TargetMaxLocalPercent: 30, Amount: 1_000_000, MaxFeePpm: 500}
+
+	full := channel("full", peerA, 800_000_000, 200_000_000)
+	fuller := channel("fuller", peerA, 900_000_000, 100_000_000)
+	empty := channel("empty", peerB, 200_000_000, 800_000_000)
+	balanced := channel("balanced", peerB, 500_000_000, 500_000_000)
+	inactive := channel("inactive", peerB, 0, 1_000_000_000)
+	inactive.Active = false
+
+	outgoing, incoming, amount := planRebalance([]lnclient.Channel{full, fuller, empty, balanced, inactive}, autoRebalanceConfig)
+	require.NotNil(t, outgoing)
+	assert.Equal(t, "fuller", outgoing.Id)
+	assert.Equal(t, "empty", incoming.Id)
+	// the empty channel is 300,000,000 mloki short of an even split
+	assert.Equal(t, uint64(300_000_000), amount)
+
+	autoRebalanceConfig.Amount = 100_000
+	_, _, amount = planRebalance([]lnclient.Channel{fuller, empty}, autoRebalanceConfig)
+	assert.Equal(t, uint64(100_000_000), amount)
+
+	outgoing, _, _ = planRebalance([]lnclient.Channel{full, balanced}, autoRebalanceConfig)
+	assert.Nil(t, outgoing)
+}
+
+func TestCheckRebalance(t *testing.T) {
+	svc, ln := newTestRebalanceService(t,
+		channel("1", peerA, 900_000_000, 100_000_000),
+		channel("2", peerB, 100_000_000, 900_000_000),
+	)
+
+	require.NoError(t, svc.checkRebalance(context.Background(), &AutoRebalanceConfig{SourceMinLocalPercent: 70, TargetMaxLocalPercent: 30, Amount: 50_000, MaxFeePpm: 1_000}))
+	assert.Equal(t, "1", ln.outgoingChannelId)
+
+	rebalances, _, err := svc.ListRebalances(10, 0)
+	require.NoError(t, err)
+	require.Len(t, rebalances, 1)
+	assert.True(t, rebalances[0].Auto)
+	assert.Equal(t, uint64(50_000_000), rebalances[0].AmountMloki)
+	assert.Equal(t, uint32(1_000), rebalances[0].MaxFeePpm)
+}
+
+func TestCheckRebalance_OutgoingPaymentsPaused(t *testing.T) {
+	svc, ln := newTestRebalanceService(t,
+		channel("1", peerA, 900_000_000, 100_000_000),
+		channel("2", peerB, 100_000_000, 900_000_000),
+	)
+	_, err := svc.freezeSvc.Freeze(true, freeze.SourceApi)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.checkRebalance(context.Background(), &AutoRebalanceConfig{SourceMinLocalPercent: 70, TargetMaxLocalPercent: 30, Amount: 50_000, MaxFeePpm: 1_000}))
+	assert.Empty(t, ln.outgoingChannelId)
+
+	_, totalCount, err := svc.ListRebalances(10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, totalCount)
+}
+
+func TestLoadAutoRebalanceConfig(t *testing.T) {
+	svc, _ := newTestRebalanceService(t)
+
+	autoRebalanceConfig, err := LoadAutoRebalanceConfig(svc.cfg)
+	require.NoError(t, err)
+	assert.Nil(t, autoRebalanceConfig)
+
+	for key, value := range map[string]string{
+		config.AutoRebalanceSourceMinLocalPercentKey: "70",
+		config.AutoRebalanceTargetMaxLocalPercentKey: "30",
+		config.AutoRebalanceAmountKey:                "100000",
+		config.AutoRebalanceMaxFeePpmKey:             "500",
+	} {
+		require.NoError(t, svc.cfg.SetUpdate(key, value, ""))
+	}
+	autoRebalanceConfig, err = LoadAutoRebalanceConfig(svc.cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &AutoRebalanceConfig{SourceMinLocalPercent: 70, TargetMaxLocalPercent: 30, Amount: 100_000, MaxFeePpm: 500}, autoRebalanceConfig)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/db/queries"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+const appLifecycleEventsInterval = 1 * time.Minute
+
+// connectionExpiringNotice is how long before a connection expires that
+// nwc_connection_expiring is published.
+const connectionExpiringNotice = 24 * time.Hour
+
+// StartAppLifecycleEvents publishes nwc_budget_renewed when an app's budget
+// period starts over and nwc_connection_expiring a day before an app's
+// permissions expire. Each check covers the time since the previous one, so
+// every event is published once; anything that happened while the hub was
+// offline is not published after the fact.
+func StartAppLifecycleEvents(ctx context.Context, gormDB *gorm.DB, eventPublisher events.EventPublisher) {
+	go func() {
+		ticker := time.NewTicker(appLifecycleEventsInterval)
+		defer ticker.Stop()
+		lastCheck := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				publishAppLifecycleEvents(gormDB, eventPublisher, lastCheck, now)
+				lastCheck = now
+			}
+		}
+	}()
+}
+
+// publishAppLifecycleEvents publishes the events due after lastCheck and up
+// to and including now.
+func publishAppLifecycleEvents(gormDB *gorm.DB, eventPublisher events.EventPublisher, lastCheck, now time.Time) {
+	budgetPermissions := []db.AppPermission{}
+	err := gormDB.Preload("App").
+		Where("scope IN ? AND max_amount_loki > 0 AND budget_renewal != ?", constants.PayCapableScopes, constants.BUDGET_RENEWAL_NEVER).
+		Find(&budgetPermissions).Error
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list app budgets")
+	}
+	for _, appPermission := range budgetPermissions {
+		budgetStart := queries.GetStartOfBudgetAt(appPermission.BudgetRenewal, now)
+		if budgetStart.IsZero() || !budgetStart.After(lastCheck) {
+			continue
+		}
+		eventPublisher.Publish(&events.Event{
+			Event: "nwc_budget_renewed",
+			Properties: map[string]interface{}{
+				"name": appPermission.App.Name,
+				"id":   appPermission.AppId,
+			},
+		})
+	}
+
+	expiringPermissions := []db.AppPermission{}
+	err = gormDB.Preload("App").
+		Where("expires_at > ? AND expires_at <= ?", lastCheck.Add(connectionExpiringNotice), now.Add(connectionExpiringNotice)).
+		Find(&expiringPermissions).Error
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to list expiring app permissions")
+	}
+	// an app's permissions usually share one expiry
+	notified := map[uint]bool{}
+	for _, appPermission := range expiringPermissions {
+		if notified[appPermission.AppId] {
+			continue
+		}
+		notified[appPermission.AppId] = true
+		eventPublisher.Publish(&events.Event{
+			Event: "nwc_connection_expiring",
+			Properties: map[string]interface{}{
+				"name":       appPermission.App.Name,
+				"id":         appPermission.AppId,
+				"expires_at": *appPermission.ExpiresAt,
+			},
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+type appLifecycleEventsCollector struct {
+	events chan *events.Event
+}
+
+func (c *appLifecycleEventsCollector) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
+	if event.Event == "nwc_budget_renewed" || event.Event == "nwc_connection_expiring" {
+		c.events <- event
+	}
+}
+
+func TestPublishAppLifecycleEvents(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	budgetApp, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	require.NoError(t, svc.DB.Create(&db.AppPermission{
+		AppId:         budgetApp.ID,
+		Scope:         constants.PAY_INVOICE_SCOPE,
+		MaxAmountLoki: 1000,
+		BudgetRenewal: constants.BUDGET_RENEWAL_DAILY,
+	}).Error)
+
+	now := time.Now()
+	expiresAt := now.Add(connectionExpiringNotice - time.Second)
+	expiringApp, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	for _, scope := range []string{constants.GET_BALANCE_SCOPE, constants.NOTIFICATIONS_SCOPE} {
+		require.NoError(t, svc.DB.Create(&db.AppPermission{
+			AppId:     expiringApp.ID,
+			Scope:     scope,
+			ExpiresAt: &expiresAt,
+		}).Error)
+	}
+
+	collector := &appLifecycleEventsCollector{events: make(chan *events.Event, 10)}
+	svc.EventPublisher.RegisterSubscriber(collector)
+
+	// the day started and the expiry came within notice since the last check
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	lastCheck := startOfDay.Add(-time.Minute)
+	publishAppLifecycleEvents(svc.DB, svc.EventPublisher, lastCheck, now)
+
+	received := map[string]*events.Event{}
+	for len(received) < 2 {
+		select {
+		case event := <-collector.events:
+			received[event.Event] = event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for app lifecycle events")
+		}
+	}
+
+	expiring := received["nwc_connection_expiring"].Properties.(map[string]interface{})
+	assert.Equal(t, expiringApp.ID, expiring["id"])
+	assert.True(t, expiresAt.Equal(expiring["expires_at"].(time.Time)))
+
+	renewed := received["nwc_budget_renewed"].Properties.(map[string]interface{})
+	assert.Equal(t, budgetApp.ID, renewed["id"])
+
+	// nothing is published twice
+	publishAppLifecycleEvents(svc.DB, svc.EventPublisher, now, now.Add(time.Second))
+	select {
+	case event := <-collector.events:
+		t.Fatalf("unexpected event %s", event.Event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
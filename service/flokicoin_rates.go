@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/loki"
+)
+
+const flokicoinRateRecordInterval = 1 * time.Hour
+
+// StartFlokicoinRateRecorder stores the fiat rate once an hour so exports
+// can value each transaction at the rate of the time it settled.
+func StartFlokicoinRateRecorder(ctx context.Context, gormDB *gorm.DB, cfg config.Config, lokiSvc loki.LokiService) {
+	go func() {
+		ticker := time.NewTicker(flokicoinRateRecordInterval)
+		defer ticker.Stop()
+		for {
+			if err := recordFlokicoinRate(ctx, gormDB, cfg, lokiSvc); err != nil {
+				logger.Logger.Warn().Err(err).Msg("Failed to record flokicoin rate")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func recordFlokicoinRate(ctx context.Context, gormDB *gorm.DB, cfg config.Config, lokiSvc loki.LokiService) error {
+	// the rate is always quoted in the configured currency
+	currency := cfg.GetCurrency()
+	rate, err := lokiSvc.GetFlokicoinRate(ctx)
+	if err != nil {
+		return err
+	}
+	return gormDB.Create(&db.FlokicoinRate{
+		Currency: currency,
+		Rate:     rate.RateFloat,
+	}).Error
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+const forwardsSyncInterval = 1 * time.Minute
+
+// forwardsSyncBatchSize bounds how many forwarding events are read from the
+// node per request while catching up.
+const forwardsSyncBatchSize = 1000
+
+// StartForwardsSyncService copies the node's forwarding log into the forwards
+// table, starting right away so anything forwarded while the hub was offline
+// is backfilled, then once a minute. The highest stored node index is the
+// cursor, so no event is stored twice and none is skipped across restarts.
+func StartForwardsSyncService(ctx context.Context, gormDB *gorm.DB, eventPublisher events.EventPublisher, getLNClient func() lnclient.LNClient) {
+	go func() {
+		ticker := time.NewTicker(forwardsSyncInterval)
+		defer ticker.Stop()
+		for {
+			if lnClient := getLNClient(); lnClient != nil {
+				if err := syncForwards(ctx, gormDB, eventPublisher, lnClient); err != nil {
+					logger.Logger.Error().Err(err).Msg("Failed to sync forwarding history")
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func syncForwards(ctx context.Context, gormDB *gorm.DB, eventPublisher events.EventPublisher, lnClient lnclient.LNClient) error {
+	var cursor uint64
+	err := gormDB.Model(&db.Forward{}).Select("COALESCE(MAX(node_index), 0)").Scan(&cursor).Error
+	if err != nil {
+		return err
+	}
+	// the first import copies the node's entire history, which is not news
+	initialImport := cursor == 0
+
+	for ctx.Err() == nil {
+		forwardingEvents, err := lnClient.ListForwards(ctx, cursor, forwardsSyncBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(forwardingEvents) == 0 {
+			return nil
+		}
+
+		forwards := make([]db.Forward, 0, len(forwardingEvents))
+		for _, forwardingEvent := range forwardingEvents {
+			nodeIndex := forwardingEvent.Index
+			forwards = append(forwards, db.Forward{
+				NodeIndex:                    &nodeIndex,
+				IncomingChannelId:            forwardingEvent.IncomingChannelId,
+				OutgoingChannelId:            forwardingEvent.OutgoingChannelId,
+				IncomingPeerPubkey:           forwardingEvent.IncomingPeerPubkey,
+				OutgoingPeerPubkey:           forwardingEvent.OutgoingPeerPubkey,
+				InboundAmountMloki:           forwardingEvent.AmountInMloki,
+				OutboundAmountForwardedMloki: forwardingEvent.AmountOutMloki,
+				TotalFeeEarnedMloki:          forwardingEvent.FeeMloki,
+				ForwardedAt:                  forwardingEvent.Timestamp,
+			})
+		}
+
+		err = gormDB.Clauses(clause.OnConflict{DoNothing: true}).Create(&forwards).Error
+		if err != nil {
+			return err
+		}
+
+		if !initialImport {
+			for _, forwardingEvent := range forwardingEvents {
+				eventPublisher.Publish(&events.Event{
+					Event: "nwc_payment_forwarded",
+					Properties: &lnclient.PaymentForwardedEventProperties{
+						TotalFeeEarnedMloki:          forwardingEvent.FeeMloki,
+						OutboundAmountForwardedMloki: forwardingEvent.AmountOutMloki,
+						InboundAmountMloki:           forwardingEvent.AmountInMloki,
+						IncomingChannelId:            forwardingEvent.IncomingChannelId,
+						OutgoingChannelId:            forwardingEvent.OutgoingChannelId,
+					},
+				})
+			}
+		}
+
+		cursor = forwardingEvents[len(forwardingEvents)-1].Index
+		if len(forwardingEvents) < forwardsSyncBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
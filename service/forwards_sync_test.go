@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+type forwardedEventsCollector struct {
+	forwarded chan *lnclient.PaymentForwardedEventProperties
+}
+
+func (c *forwardedEventsCollector) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
+	if event.Event == "nwc_payment_forwarded" {
+		c.forwarded <- event.Properties.(*lnclient.PaymentForwardedEventProperties)
+	}
+}
+
+func mockForwardingEvent(index uint64, forwardedAt time.Time) lnclient.ForwardingEvent {
+	return lnclient.ForwardingEvent{
+		Index:              index,
+		IncomingChannelId:  "111",
+		OutgoingChannelId:  "222",
+		IncomingPeerPubkey: "02aaaa",
+		OutgoingPeerPubkey: "02bbbb",
+		AmountInMloki:      101_000,
+		AmountOutMloki:     100_000,
+		FeeMloki:           1_000,
+		Timestamp:          forwardedAt,
+	}
+}
+
+func TestSyncForwards_Backfills(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	forwardedAt := time.Now().Add(-time.Hour).UTC()
+	mockLn := svc.LNClient.(*tests.MockLn)
+	mockLn.ForwardingEvents = []lnclient.ForwardingEvent{
+		mockForwardingEvent(1, forwardedAt),
+		mockForwardingEvent(2, forwardedAt.Add(time.Minute)),
+	}
+	collector := &forwardedEventsCollector{forwarded: make(chan *lnclient.PaymentForwardedEventProperties, 10)}
+	svc.EventPublisher.RegisterSubscriber(collector)
+
+	require.NoError(t, syncForwards(ctx, svc.DB, svc.EventPublisher, mockLn))
+
+	var forwards []db.Forward
+	require.NoError(t, svc.DB.Order("node_index").Find(&forwards).Error)
+	require.Len(t, forwards, 2)
+	assert.Equal(t, uint64(1), *forwards[0].NodeIndex)
+	assert.Equal(t, "111", forwards[0].IncomingChannelId)
+	assert.Equal(t, "222", forwards[0].OutgoingChannelId)
+	assert.Equal(t, "02aaaa", forwards[0].IncomingPeerPubkey)
+	assert.Equal(t, "02bbbb", forwards[0].OutgoingPeerPubkey)
+	assert.Equal(t, uint64(101_000), forwards[0].InboundAmountMloki)
+	assert.Equal(t, uint64(100_000), forwards[0].OutboundAmountForwardedMloki)
+	assert.Equal(t, uint64(1_000), forwards[0].TotalFeeEarnedMloki)
+	assert.True(t, forwardedAt.Equal(forwards[0].ForwardedAt))
+
+	// the initial import is history, not news
+	select {
+	case <-collector.forwarded:
+		t.Fatal("unexpected forwarded event for backfilled history")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSyncForwards_ResumesFromCursor(t *testing.T) {
+	ctx := context.TODO()
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	mockLn := svc.LNClient.(*tests.MockLn)
+	mockLn.ForwardingEvents = []lnclient.ForwardingEvent{mockForwardingEvent(1, time.Now())}
+	require.NoError(t, syncForwards(ctx, svc.DB, svc.EventPublisher, mockLn))
+
+	collector := &forwardedEventsCollector{forwarded: make(chan *lnclient.PaymentForwardedEventProperties, 10)}
+	svc.EventPublisher.RegisterSubscriber(collector)
+
+	// forwarded while the hub was offline
+	mockLn.ForwardingEvents = append(mockLn.ForwardingEvents, mockForwardingEvent(2, time.Now()))
+	require.NoError(t, syncForwards(ctx, svc.DB, svc.EventPublisher, mockLn))
+	// nothing new
+	require.NoError(t, syncForwards(ctx, svc.DB, svc.EventPublisher, mockLn))
+
+	var count int64
+	require.NoError(t, svc.DB.Model(&db.Forward{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	select {
+	case properties := <-collector.forwarded:
+		assert.Equal(t, uint64(1_000), properties.TotalFeeEarnedMloki)
+		assert.Equal(t, uint64(101_000), properties.InboundAmountMloki)
+		assert.Equal(t, "222", properties.OutgoingChannelId)
+	case <-time.After(time.Second):
+		t.Fatal("expected a forwarded event")
+	}
+	select {
+	case <-collector.forwarded:
+		t.Fatal("each forward must be published once")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
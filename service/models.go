@@ -7,12 +7,16 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/flokiorg/lokihub/appstore"
+	"github.com/flokiorg/lokihub/autoliquidity"
 	"github.com/flokiorg/lokihub/config"
 	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/feemanager"
 	"github.com/flokiorg/lokihub/keys"
 	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/lnurl"
 	"github.com/flokiorg/lokihub/loki"
 	"github.com/flokiorg/lokihub/lsps/manager"
+	"github.com/flokiorg/lokihub/rebalance"
 	"github.com/flokiorg/lokihub/swaps"
 	"github.com/flokiorg/lokihub/transactions"
 )
@@ -34,6 +38,7 @@ type Service interface {
 	GetLNClient() lnclient.LNClient
 	GetTransactionsService() transactions.TransactionsService
 	GetSwapsService() swaps.SwapsService
+	GetLNURLService() lnurl.LNURLService
 	InitSwapsService()
 	GetDB() *gorm.DB
 	GetConfig() config.Config
@@ -60,4 +65,7 @@ type Service interface {
 	PeekContactSyncedAt(ownerPubkey string) (syncedAt time.Time, ok bool)
 	GetAppStoreSvc() appstore.Service
 	GetLiquidityManager() *manager.LiquidityManager
+	GetAutoLiquidityService() autoliquidity.AutoLiquidityService
+	GetRebalanceService() rebalance.RebalanceService
+	GetFeeManagerService() feemanager.FeeManagerService
 }
@@ -16,11 +16,15 @@ import (
 	"github.com/kelseyhightower/envconfig"
 
 	"github.com/flokiorg/lokihub/appstore"
+	"github.com/flokiorg/lokihub/autoliquidity"
 	"github.com/flokiorg/lokihub/db/migrations"
 	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/feemanager"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/keys"
 	"github.com/flokiorg/lokihub/logger"
 	"github.com/flokiorg/lokihub/loki"
+	"github.com/flokiorg/lokihub/rebalance"
 	"github.com/flokiorg/lokihub/swaps"
 	"github.com/flokiorg/lokihub/transactions"
 	"github.com/flokiorg/lokihub/version"
@@ -28,6 +32,7 @@ import (
 	"github.com/flokiorg/lokihub/config"
 	"github.com/flokiorg/lokihub/db"
 	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/lnurl"
 	"github.com/flokiorg/lokihub/lsps/manager"
 	lspsnostr "github.com/flokiorg/lokihub/lsps/nostr"
 	"github.com/flokiorg/lokihub/nip47"
@@ -40,6 +45,7 @@ type service struct {
 	lnClient            lnclient.LNClient
 	transactionsService transactions.TransactionsService
 	swapsService        swaps.SwapsService
+	lnurlService        lnurl.LNURLService
 	lokiSvc             loki.LokiService
 	appStoreSvc         appstore.Service
 	eventPublisher      events.EventPublisher
@@ -50,6 +56,9 @@ type service struct {
 	socialCache         *nostrSocialCache
 	lsps5Listener       *lspsnostr.Listener
 	liquidityManager    *manager.LiquidityManager
+	autoLiquiditySvc    autoliquidity.AutoLiquidityService
+	rebalanceSvc        rebalance.RebalanceService
+	feeManagerSvc       feemanager.FeeManagerService
 	appCancelFn         context.CancelFunc
 	nostrCancelFn       context.CancelFunc
 	keys                keys.Keys
@@ -151,13 +160,11 @@ func NewService(ctx context.Context) (*service, error) {
 		keys:                keys,
 	}
 
+	svc.lnurlService = lnurl.NewLNURLService(gormDB, keys, transactionsSvc, svc.GetLNClient)
+
 	eventPublisher.RegisterSubscriber(svc.transactionsService)
 	eventPublisher.RegisterSubscriber(svc.nip47Service)
 
-	eventPublisher.RegisterSubscriber(&paymentForwardedConsumer{
-		db: gormDB,
-	})
-
 	svc.appStoreSvc = appstore.NewAppStoreService(cfg)
 	svc.appStoreSvc.Start()
 
@@ -308,6 +315,10 @@ func (svc *service) GetTransactionsService() transactions.TransactionsService {
 	return svc.transactionsService
 }
 
+func (svc *service) GetLNURLService() lnurl.LNURLService {
+	return svc.lnurlService
+}
+
 func (svc *service) GetSwapsService() swaps.SwapsService {
 	return svc.swapsService
 }
@@ -316,6 +327,18 @@ func (svc *service) GetLiquidityManager() *manager.LiquidityManager {
 	return svc.liquidityManager
 }
 
+func (svc *service) GetAutoLiquidityService() autoliquidity.AutoLiquidityService {
+	return svc.autoLiquiditySvc
+}
+
+func (svc *service) GetRebalanceService() rebalance.RebalanceService {
+	return svc.rebalanceSvc
+}
+
+func (svc *service) GetFeeManagerService() feemanager.FeeManagerService {
+	return svc.feeManagerSvc
+}
+
 func (svc *service) InitSwapsService() {
 	if svc.swapsService != nil {
 		return
@@ -324,7 +347,8 @@ func (svc *service) InitSwapsService() {
 		logger.Logger.Error().Msg("Cannot init swaps service: LNClient not started")
 		return
 	}
-	svc.swapsService = swaps.NewSwapsService(svc.ctx, svc.db, svc.cfg, svc.keys, svc.eventPublisher, svc.lnClient, svc.transactionsService)
+	svc.swapsService = swaps.NewSwapsService(svc.ctx, svc.db, svc.cfg, svc.keys, svc.eventPublisher, svc.lnClient, svc.transactionsService,
+		freeze.NewFreezeService(svc.db, svc.eventPublisher))
 }
 
 func (svc *service) GetKeys() keys.Keys {
@@ -7,11 +7,17 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/flokiorg/lokihub/approvals"
+	"github.com/flokiorg/lokihub/autoliquidity"
 	"github.com/flokiorg/lokihub/constants"
 	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/feemanager"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/nip47/models"
+	"github.com/flokiorg/lokihub/rebalance"
 	"github.com/flokiorg/lokihub/swaps"
 	"github.com/flokiorg/lokihub/version"
+	"github.com/flokiorg/lokihub/webhooks"
 	nostrlsps5 "github.com/flowgate-lsp/nostr-lsps5"
 
 	"github.com/nbd-wtf/go-nostr"
@@ -21,6 +27,7 @@ import (
 	"github.com/flokiorg/lokihub/config"
 	"github.com/flokiorg/lokihub/events"
 	"github.com/flokiorg/lokihub/lnclient/flnd"
+	"github.com/flokiorg/lokihub/lnurl"
 	"github.com/flokiorg/lokihub/logger"
 	"github.com/flokiorg/lokihub/lsps/manager"
 	lspsnostr "github.com/flokiorg/lokihub/lsps/nostr"
@@ -124,7 +131,9 @@ func (svc *service) startNostr(ctx context.Context) error {
 	svc.nip47Service.StartNotifier(ctx, pool)
 	svc.nip47Service.StartNip47InfoPublisher(ctx, pool, svc.lnClient)
 	StartJITCleanupService(ctx, svc.db, svc.transactionsService, svc.GetLNClient)
+	StartAppLifecycleEvents(ctx, svc.db, svc.eventPublisher)
 	StartNostrSocialCacheRefresher(ctx, svc.db, svc.socialCache, pool)
+	freeze.NewFreezeService(svc.db, svc.eventPublisher).StartNostrListener(ctx, pool, svc.cfg.GetRelayUrls(), svc.keys.GetNostrPublicKey(), svc.keys.GetNostrSecretKey())
 
 	// Start LSPS5 listener
 	svc.lsps5Listener = lspsnostr.NewListener(svc.keys, svc.cfg, svc.eventPublisher, func() []string {
@@ -155,6 +164,14 @@ func (svc *service) startNostr(ctx context.Context) error {
 	createAppEventListener := &createAppConsumer{svc: svc, pool: pool}
 	svc.eventPublisher.RegisterSubscriber(createAppEventListener)
 
+	// publish NIP-57 zap receipts for paid lightning address invoices
+	zapReceiptPublisher := lnurl.NewZapReceiptPublisher(svc.db, svc.cfg, svc.keys, pool)
+	svc.eventPublisher.RegisterSubscriber(zapReceiptPublisher)
+	go func() {
+		<-ctx.Done()
+		svc.eventPublisher.RemoveSubscriber(zapReceiptPublisher)
+	}()
+
 	// register a subscriber for events of "nwc_app_updated" which handles re-publishing of nip47 event info
 	updateAppEventListener := &updateAppConsumer{svc: svc}
 	svc.eventPublisher.RegisterSubscriber(updateAppEventListener)
@@ -381,7 +398,29 @@ func (svc *service) StartApp(encryptionKey string) error {
 		return err
 	}
 
-	svc.swapsService = swaps.NewSwapsService(ctx, svc.db, svc.cfg, svc.keys, svc.eventPublisher, svc.lnClient, svc.transactionsService)
+	// background payments stop while an emergency freeze pauses outgoing payments
+	freezeSvc := freeze.NewFreezeService(svc.db, svc.eventPublisher)
+	svc.swapsService = swaps.NewSwapsService(ctx, svc.db, svc.cfg, svc.keys, svc.eventPublisher, svc.lnClient, svc.transactionsService, freezeSvc)
+
+	svc.rebalanceSvc = rebalance.NewRebalanceService(ctx, svc.db, svc.cfg, svc.lnClient, freezeSvc)
+	svc.feeManagerSvc = feemanager.NewFeeManagerService(ctx, svc.db, svc.cfg, svc.lnClient)
+
+	StartForwardsSyncService(ctx, svc.db, svc.eventPublisher, svc.GetLNClient)
+	StartFlokicoinRateRecorder(ctx, svc.db, svc.cfg, svc.lokiSvc)
+
+	// NIP-47 requests waiting for approval did not survive the restart
+	if err := approvals.NewApprovalsService(svc.db, svc.eventPublisher).ExpirePendingApprovals(); err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to expire pending payment approvals")
+	}
+
+	// forward hub events to the owner's webhook endpoints
+	webhooksSvc := webhooks.NewWebhooksService(svc.db, svc.cfg)
+	svc.eventPublisher.RegisterSubscriber(webhooksSvc)
+	webhooksSvc.Start(ctx)
+	go func() {
+		<-ctx.Done()
+		svc.eventPublisher.RemoveSubscriber(webhooksSvc)
+	}()
 
 	// Initialize and start LiquidityManager (LSPS)
 	// Initialize and start LiquidityManager (LSPS)
@@ -421,6 +460,7 @@ func (svc *service) StartApp(encryptionKey string) error {
 			logger.Logger.Error().Err(err).Msg("Failed to start LiquidityManager")
 		} else {
 			logger.Logger.Info().Msg("LiquidityManager started")
+			svc.autoLiquiditySvc = autoliquidity.NewAutoLiquidityService(ctx, svc.cfg, svc.lnClient, lm, svc.transactionsService, freezeSvc)
 			// Sync system LSPs asynchronously
 			// Start background sync service
 			svc.shutdownGroup.Go(func() error {
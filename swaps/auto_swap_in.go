@@ -0,0 +1,282 @@
+package swaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+)
+
+// AutoSwapInConfig refills the Lightning balance from the node's on-chain
+// wallet. Amounts are in loki.
+type AutoSwapInConfig struct {
+	// SpendableThreshold triggers a swap in when the Lightning spendable
+	// balance drops below it
+	SpendableThreshold uint64
+	// OnchainThreshold is the confirmed on-chain balance required to swap in
+	OnchainThreshold uint64
+	SwapAmount       uint64
+	// DailyLimit caps the on-chain amount sent to swaps per calendar day (UTC)
+	DailyLimit uint64
+}
+
+// AutoSwapInStatus reports the automatic swap ins of the current day
+type AutoSwapInStatus struct {
+	// SwappedToday is the on-chain amount in loki sent to auto swap ins today
+	SwappedToday uint64
+	// PendingSwapId is the auto swap in waiting to complete, if any
+	PendingSwapId string
+	// UnrefundedSwapIds are failed auto swap ins whose lockup funds were not
+	// refunded yet. Refunds are retried on every check.
+	UnrefundedSwapIds []string
+}
+
+// LoadAutoSwapInConfig returns the saved auto swap in config, or nil if auto
+// swap in is not enabled
+func LoadAutoSwapInConfig(cfg config.Config) (*AutoSwapInConfig, error) {
+	keys := []string{
+		config.AutoSwapInSpendableThresholdKey,
+		config.AutoSwapInOnchainThresholdKey,
+		config.AutoSwapInAmountKey,
+		config.AutoSwapInDailyLimitKey,
+	}
+	values := make([]uint64, len(keys))
+	for i, key := range keys {
+		valueStr, _ := cfg.Get(key, "")
+		if valueStr == "" {
+			return nil, nil
+		}
+		value, err := strconv.ParseUint(valueStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto swap in configuration %s: %w", key, err)
+		}
+		values[i] = value
+	}
+	return &AutoSwapInConfig{
+		SpendableThreshold: values[0],
+		OnchainThreshold:   values[1],
+		SwapAmount:         values[2],
+		DailyLimit:         values[3],
+	}, nil
+}
+
+func (svc *swapsService) StopAutoSwapIn() {
+	if svc.autoSwapInCancelFn != nil {
+		svc.logger.Info().Msg("Stopping auto swap in service...")
+		svc.autoSwapInCancelFn()
+		svc.autoSwapInCancelFn = nil
+		svc.logger.Info().Msg("Auto swap in service stopped")
+	}
+}
+
+func (svc *swapsService) EnableAutoSwapIn() error {
+	svc.StopAutoSwapIn()
+
+	autoSwapInConfig, err := LoadAutoSwapInConfig(svc.cfg)
+	if err != nil {
+		return err
+	}
+	if autoSwapInConfig == nil {
+		svc.logger.Info().Msg("Auto swap in not configured")
+		return nil
+	}
+
+	ctx, cancelFn := context.WithCancel(svc.ctx)
+
+	svc.logger.Info().Msg("Starting auto swap in workflow")
+
+	go func() {
+		for {
+			select {
+			case <-time.After(1 * time.Hour):
+				svc.retryAutoSwapInRefunds()
+				if err := svc.checkAutoSwapIn(ctx, autoSwapInConfig); err != nil {
+					svc.logger.Error().Err(err).Msg("Auto swap in failed")
+				}
+			case <-ctx.Done():
+				svc.logger.Info().Msg("Stopping auto swap in workflow")
+				return
+			}
+		}
+	}()
+
+	svc.autoSwapInCancelFn = cancelFn
+
+	return nil
+}
+
+func (svc *swapsService) GetAutoSwapInStatus() (*AutoSwapInStatus, error) {
+	swappedToday, err := svc.autoSwapInTotalSince(startOfDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	status := &AutoSwapInStatus{SwappedToday: swappedToday, UnrefundedSwapIds: []string{}}
+
+	pendingSwap, err := svc.pendingAutoSwapIn()
+	if err != nil {
+		return nil, err
+	}
+	if pendingSwap != nil {
+		status.PendingSwapId = pendingSwap.SwapId
+	}
+
+	unrefunded, err := svc.unrefundedAutoSwapIns()
+	if err != nil {
+		return nil, err
+	}
+	for _, swap := range unrefunded {
+		status.UnrefundedSwapIds = append(status.UnrefundedSwapIds, swap.SwapId)
+	}
+	return status, nil
+}
+
+// checkAutoSwapIn starts and funds a swap in if the balances call for it and
+// the daily limit allows it
+func (svc *swapsService) checkAutoSwapIn(ctx context.Context, autoSwapInConfig *AutoSwapInConfig) error {
+	svc.logger.Debug().Msg("Checking to see if we can swap in")
+	if err := svc.freezeSvc.CheckOutgoingPayment(); err != nil {
+		svc.logger.Info().Err(err).Msg("Not swapping in")
+		return nil
+	}
+
+	pendingSwap, err := svc.pendingAutoSwapIn()
+	if err != nil {
+		return err
+	}
+	if pendingSwap != nil {
+		svc.logger.Debug().Str("swap_id", pendingSwap.SwapId).Msg("Waiting for the previous auto swap in")
+		return nil
+	}
+
+	balances, err := svc.lnClient.GetBalances(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get balances: %w", err)
+	}
+	swappedToday, err := svc.autoSwapInTotalSince(startOfDay(time.Now()))
+	if err != nil {
+		return err
+	}
+	if !autoSwapInNeeded(balances, autoSwapInConfig, swappedToday) {
+		svc.logger.Debug().Msg("Threshold requirements not met for swap in, ignoring")
+		return nil
+	}
+
+	svc.logger.Info().Uint64("amount", autoSwapInConfig.SwapAmount).Msg("Initiating auto swap in")
+	swapResponse, err := svc.SwapIn(autoSwapInConfig.SwapAmount, true)
+	if err != nil {
+		return fmt.Errorf("failed to initiate swap in: %w", err)
+	}
+
+	return svc.fundSwapIn(ctx, swapResponse.SwapId)
+}
+
+// autoSwapInNeeded reports whether the Lightning balance is low, the
+// on-chain balance is high enough to pay for the swap, and the swap fits in
+// what is left of the daily limit
+func autoSwapInNeeded(balances *lnclient.BalancesResponse, autoSwapInConfig *AutoSwapInConfig, swappedToday uint64) bool {
+	spendable := uint64(max(balances.Lightning.TotalSpendable, 0))
+	if spendable >= autoSwapInConfig.SpendableThreshold*1000 {
+		return false
+	}
+	onchain := uint64(max(balances.Onchain.Spendable, 0))
+	if onchain < autoSwapInConfig.OnchainThreshold || onchain <= autoSwapInConfig.SwapAmount {
+		return false
+	}
+	return swappedToday+autoSwapInConfig.SwapAmount <= autoSwapInConfig.DailyLimit
+}
+
+// fundSwapIn sends the expected amount from the node's on-chain wallet to the
+// lockup address of the swap. The swap in listener claims the invoice and
+// refunds the lockup if the swap fails later on.
+func (svc *swapsService) fundSwapIn(ctx context.Context, swapId string) error {
+	var swap db.Swap
+	if err := svc.db.Limit(1).Find(&swap, &db.Swap{SwapId: swapId}).Error; err != nil {
+		return err
+	}
+	if swap.LockupAddress == "" || swap.SendAmount == 0 {
+		svc.markSwapState(&swap, constants.SWAP_STATE_FAILED)
+		return errors.New("swap has no lockup address")
+	}
+
+	txId, err := svc.lnClient.RedeemOnchainFunds(ctx, swap.LockupAddress, swap.SendAmount, nil, false)
+	if err != nil {
+		// nothing was sent, so there is nothing to refund
+		svc.markSwapState(&swap, constants.SWAP_STATE_FAILED)
+		return fmt.Errorf("failed to fund swap in: %w", err)
+	}
+
+	if err := svc.db.Model(&swap).Updates(&db.Swap{LockupTxId: txId}).Error; err != nil {
+		svc.logger.Error().Err(err).Str("swap_id", swapId).Str("lockupTxId", txId).Msg("Failed to save lockup txid to swap")
+	}
+	svc.logger.Info().
+		Str("swap_id", swapId).
+		Str("lockupTxId", txId).
+		Uint64("amount", swap.SendAmount).
+		Msg("Funded auto swap in")
+	return nil
+}
+
+// retryAutoSwapInRefunds refunds failed auto swap ins whose refund did not go
+// through, e.g. because the swap had not timed out yet
+func (svc *swapsService) retryAutoSwapInRefunds() {
+	swaps, err := svc.unrefundedAutoSwapIns()
+	if err != nil {
+		svc.logger.Error().Err(err).Msg("Failed to list unrefunded auto swap ins")
+		return
+	}
+	for _, swap := range swaps {
+		if err := svc.RefundSwap(swap.SwapId, "", false); err != nil {
+			svc.logger.Warn().Err(err).Str("swap_id", swap.SwapId).Msg("Auto swap in refund failed, will retry")
+		}
+	}
+}
+
+func (svc *swapsService) pendingAutoSwapIn() (*db.Swap, error) {
+	var swap db.Swap
+	result := svc.db.Limit(1).
+		Where("type = ? AND auto_swap = ? AND state = ?", constants.SWAP_TYPE_IN, true, constants.SWAP_STATE_PENDING).
+		Find(&swap)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &swap, nil
+}
+
+// unrefundedAutoSwapIns returns failed auto swap ins that were funded but not
+// refunded
+func (svc *swapsService) unrefundedAutoSwapIns() ([]db.Swap, error) {
+	var swaps []db.Swap
+	err := svc.db.
+		Where("type = ? AND auto_swap = ? AND state = ? AND lockup_tx_id != '' AND claim_tx_id = ''",
+			constants.SWAP_TYPE_IN, true, constants.SWAP_STATE_FAILED).
+		Order("created_at").
+		Find(&swaps).Error
+	return swaps, err
+}
+
+// autoSwapInTotalSince sums the on-chain amounts sent to auto swap ins
+// created since the given time. Swaps that failed before being funded are
+// left out.
+func (svc *swapsService) autoSwapInTotalSince(since time.Time) (uint64, error) {
+	var total uint64
+	err := svc.db.Model(&db.Swap{}).
+		Select("COALESCE(SUM(send_amount), 0)").
+		Where("type = ? AND auto_swap = ? AND created_at >= ? AND (state != ? OR lockup_tx_id != '')",
+			constants.SWAP_TYPE_IN, true, since, constants.SWAP_STATE_FAILED).
+		Scan(&total).Error
+	return total, err
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,153 @@
+package swaps
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/freeze"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+// redeemRecordingLn records on-chain sends
+type redeemRecordingLn struct {
+	*tests.MockLn
+	toAddress string
+	amount    uint64
+	err       error
+}
+
+func (ln *redeemRecordingLn) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (string, error) {
+	ln.toAddress = toAddress
+	ln.amount = amount
+	if ln.err != nil {
+		return "", ln.err
+	}
+	return "lockup_txid", nil
+}
+
+func newTestAutoSwapInService(t *testing.T) (*swapsService, *redeemRecordingLn) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	t.Cleanup(svc.Remove)
+	ln := &redeemRecordingLn{MockLn: svc.LNClient.(*tests.MockLn)}
+	return &swapsService{
+		ctx:       context.Background(),
+		db:        svc.DB,
+		cfg:       svc.Cfg,
+		lnClient:  ln,
+		freezeSvc: freeze.NewFreezeService(svc.DB, svc.EventPublisher),
+		logger:    zerolog.Nop(),
+	}, ln
+}
+
+func TestAutoSwapInNeeded(t *testing.T) {
+	autoSwapInConfig := &AutoSwapInConfig{
+		SpendableThreshold: 10_000,
+		OnchainThreshold:   200_000,
+		SwapAmount:         100_000,
+		DailyLimit:         250_000,
+	}
+	balances := func(spendableMloki, onchain int64) *lnclient.BalancesResponse {
+		return &lnclient.BalancesResponse{
+			Lightning: lnclient.LightningBalanceResponse{TotalSpendable: spendableMloki},
+			Onchain:   lnclient.OnchainBalanceResponse{Spendable: onchain},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		balances     *lnclient.BalancesResponse
+		swappedToday uint64
+		expected     bool
+	}{
+		{"low spendable and enough on-chain", balances(9_999_999, 200_000), 0, true},
+		{"spendable at threshold", balances(10_000_000, 200_000), 0, false},
+		{"on-chain below threshold", balances(0, 199_999), 0, false},
+		{"daily limit reached", balances(0, 200_000), 150_001, false},
+		{"swap fills daily limit", balances(0, 200_000), 150_000, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, autoSwapInNeeded(tc.balances, autoSwapInConfig, tc.swappedToday))
+		})
+	}
+}
+
+func TestAutoSwapInTotals(t *testing.T) {
+	svc, _ := newTestAutoSwapInService(t)
+
+	yesterday := time.Now().Add(-48 * time.Hour)
+	swaps := []db.Swap{
+		{SwapId: "manual", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_SUCCESS, SendAmount: 1},
+		{SwapId: "out", Type: constants.SWAP_TYPE_OUT, State: constants.SWAP_STATE_SUCCESS, AutoSwap: true, SendAmount: 2},
+		{SwapId: "old", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_SUCCESS, AutoSwap: true, SendAmount: 4, CreatedAt: yesterday},
+		{SwapId: "success", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_SUCCESS, AutoSwap: true, SendAmount: 8},
+		{SwapId: "unfunded", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_FAILED, AutoSwap: true, SendAmount: 16},
+		{SwapId: "unrefunded", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_FAILED, AutoSwap: true, SendAmount: 32, LockupTxId: "tx1"},
+		{SwapId: "refunded", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_REFUNDED, AutoSwap: true, SendAmount: 64, LockupTxId: "tx2", ClaimTxId: "tx3"},
+		{SwapId: "pending", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_PENDING, AutoSwap: true, SendAmount: 128},
+	}
+	require.NoError(t, svc.db.Create(&swaps).Error)
+
+	status, err := svc.GetAutoSwapInStatus()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8+32+64+128), status.SwappedToday)
+	assert.Equal(t, "pending", status.PendingSwapId)
+	assert.Equal(t, []string{"unrefunded"}, status.UnrefundedSwapIds)
+
+	// the next swap waits for the pending one
+	require.NoError(t, svc.checkAutoSwapIn(context.Background(), &AutoSwapInConfig{SwapAmount: 1, DailyLimit: 1_000}))
+}
+
+func TestFundSwapIn(t *testing.T) {
+	svc, ln := newTestAutoSwapInService(t)
+
+	swap := db.Swap{SwapId: "funded", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_PENDING, AutoSwap: true, LockupAddress: "fc1lockup", SendAmount: 100_500}
+	require.NoError(t, svc.db.Create(&swap).Error)
+
+	require.NoError(t, svc.fundSwapIn(context.Background(), "funded"))
+	assert.Equal(t, "fc1lockup", ln.toAddress)
+	assert.Equal(t, uint64(100_500), ln.amount)
+	require.NoError(t, svc.db.First(&swap, swap.ID).Error)
+	assert.Equal(t, "lockup_txid", swap.LockupTxId)
+	assert.Equal(t, constants.SWAP_STATE_PENDING, swap.State)
+
+	swap = db.Swap{SwapId: "unfunded", Type: constants.SWAP_TYPE_IN, State: constants.SWAP_STATE_PENDING, AutoSwap: true, LockupAddress: "fc1lockup", SendAmount: 100_500}
+	require.NoError(t, svc.db.Create(&swap).Error)
+	ln.err = errors.New("insufficient funds")
+
+	assert.Error(t, svc.fundSwapIn(context.Background(), "unfunded"))
+	require.NoError(t, svc.db.First(&swap, swap.ID).Error)
+	assert.Equal(t, constants.SWAP_STATE_FAILED, swap.State)
+	assert.Empty(t, swap.LockupTxId)
+}
+
+func TestLoadAutoSwapInConfig(t *testing.T) {
+	svc, _ := newTestAutoSwapInService(t)
+
+	autoSwapInConfig, err := LoadAutoSwapInConfig(svc.cfg)
+	require.NoError(t, err)
+	assert.Nil(t, autoSwapInConfig)
+
+	for key, value := range map[string]string{
+		config.AutoSwapInSpendableThresholdKey: "1",
+		config.AutoSwapInOnchainThresholdKey:   "2",
+		config.AutoSwapInAmountKey:             "3",
+		config.AutoSwapInDailyLimitKey:         "4",
+	} {
+		require.NoError(t, svc.cfg.SetUpdate(key, value, ""))
+	}
+	autoSwapInConfig, err = LoadAutoSwapInConfig(svc.cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &AutoSwapInConfig{SpendableThreshold: 1, OnchainThreshold: 2, SwapAmount: 3, DailyLimit: 4}, autoSwapInConfig)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/flokiorg/lokihub/db"
 	decodepay "github.com/flokiorg/lokihub/decodepay"
 	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/keys"
 	"github.com/flokiorg/lokihub/lnclient"
 	"github.com/flokiorg/lokihub/logger"
@@ -57,6 +58,7 @@ func (w *wsWrapper) UpdatesChan() <-chan lightz.SwapUpdate { return w.Updates }
 
 type swapsService struct {
 	autoSwapOutCancelFn context.CancelFunc
+	autoSwapInCancelFn  context.CancelFunc
 	db                  *gorm.DB
 	ctx                 context.Context
 	lnClient            lnclient.LNClient
@@ -64,6 +66,7 @@ type swapsService struct {
 	keys                keys.Keys
 	eventPublisher      events.EventPublisher
 	transactionsService transactions.TransactionsService
+	freezeSvc           freeze.FreezeService
 	lightzApi           *lightz.Api
 	lightzWs            lightzWebsocket
 	swapListeners       map[string]chan lightz.SwapUpdate
@@ -74,6 +77,9 @@ type swapsService struct {
 type SwapsService interface {
 	StopAutoSwapOut()
 	EnableAutoSwapOut() error
+	StopAutoSwapIn()
+	EnableAutoSwapIn() error
+	GetAutoSwapInStatus() (*AutoSwapInStatus, error)
 	SwapOut(amount uint64, destination string, autoSwap, usedXpubDerivation bool) (*SwapResponse, error)
 	SwapIn(amount uint64, autoSwap bool) (*SwapResponse, error)
 	GetSwapOutInfo() (*SwapInfo, error)
@@ -210,7 +216,7 @@ func (svc *swapsService) Stop() {
 }
 
 func NewSwapsService(ctx context.Context, db *gorm.DB, cfg config.Config, keys keys.Keys, eventPublisher events.EventPublisher,
-	lnClient lnclient.LNClient, transactionsService transactions.TransactionsService) SwapsService {
+	lnClient lnclient.LNClient, transactionsService transactions.TransactionsService, freezeSvc freeze.FreezeService) SwapsService {
 	svc := &swapsService{
 		ctx:                 ctx,
 		cfg:                 cfg,
@@ -218,6 +224,7 @@ func NewSwapsService(ctx context.Context, db *gorm.DB, cfg config.Config, keys k
 		keys:                keys,
 		eventPublisher:      eventPublisher,
 		transactionsService: transactionsService,
+		freezeSvc:           freezeSvc,
 		lnClient:            lnClient,
 		swapListeners:       make(map[string]chan lightz.SwapUpdate),
 		logger:              logger.Logger.With().Str("component", "swaps").Logger(),
@@ -230,6 +237,11 @@ func NewSwapsService(ctx context.Context, db *gorm.DB, cfg config.Config, keys k
 		svc.logger.Error().Err(err).Msg("Couldn't enable auto swaps")
 	}
 
+	err = svc.EnableAutoSwapIn()
+	if err != nil {
+		svc.logger.Error().Err(err).Msg("Couldn't enable auto swap ins")
+	}
+
 	go svc.subscribePendingSwaps()
 
 	return svc
@@ -276,6 +288,10 @@ func (svc *swapsService) EnableAutoSwapOut() error {
 			select {
 			case <-time.After(1 * time.Hour):
 				svc.logger.Debug().Msg("Checking to see if we can swap")
+				if err := svc.freezeSvc.CheckOutgoingPayment(); err != nil {
+					svc.logger.Info().Err(err).Msg("Not swapping out")
+					continue
+				}
 				balance, err := svc.lnClient.GetBalances(ctx, false)
 				if err != nil {
 					svc.logger.Error().Err(err).Msg("Failed to get balance")
@@ -8,7 +8,8 @@ import (
 )
 
 type mockSimplePool struct {
-	PublishedEvents []*nostr.Event
+	PublishedEvents    []*nostr.Event
+	PublishedRelayUrls [][]string
 }
 
 func NewMockSimplePool() *mockSimplePool {
@@ -18,6 +19,7 @@ func NewMockSimplePool() *mockSimplePool {
 func (relay *mockSimplePool) PublishMany(ctx context.Context, relayUrls []string, event nostr.Event) chan nostr.PublishResult {
 	logger.Logger.Info().Interface("event", event).Msg("Mock Publishing event")
 	relay.PublishedEvents = append(relay.PublishedEvents, &event)
+	relay.PublishedRelayUrls = append(relay.PublishedRelayUrls, relayUrls)
 
 	channel := make(chan nostr.PublishResult)
 	go func() {
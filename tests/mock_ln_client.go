@@ -2,7 +2,6 @@ package tests
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/flokiorg/lokihub/lnclient"
@@ -18,6 +17,9 @@ const MockPaymentHash = "23277d5e13fce5534f9752c62fcf9337a2a6b0ebea9d21fa816a4c9
 const MockZeroAmountInvoice = "lntbs1pnkjfgudqjd3hkueeqv4u8q6tj0ynp4qws83mqzuqptu5kfvxeles7qmyhsj6u2s6zyuft26mcr4tdmcupuupp533y9nwnsaktr9zlvyxmv97ta23faerygh3t9xvsfwytsr28lgggssp5mku3023z3kdxlpx6vrwtfxvvrxpffrquy6veex4ndk7rxhdtslhq9qyysgqcqpcxqxfvltyqva6y7k89jwtcljx399jl6wsq4lkq29vnm3rj4jxmapc6vcs358sx8mtpgh93rdc6ccqpxwwfga59zrla5m55zwzck2y2rsrxumu852sqkvpcm7"
 const MockZeroAmountPaymentHash = "8c4859ba70ed96328bec21b6c2f97d5453dc8c88bc56533209711701a8ff4211"
 
+const MockOffer = "lno1qgsqvgnwgcg35z6ee2h3yczraddm72xrfua9uve2rlrm9deu7xyfzrcgqgn3qzsyvfkx26qkyypvr5hfx60h9w9k934lt8s2n6zc0wwtgqlulw7dythr83dqx8tzumg"
+const MockOfferPaymentHash = "0b7f8f2d1a3e8b44a2f55b0d6d2bb4b9b52f0d8b62a1f8e3f4d76d7aef3c9c11"
+
 var MockNodeInfo = lnclient.NodeInfo{
 	Alias:       "bob",
 	Color:       "#3399FF",
@@ -92,13 +94,22 @@ type MockLn struct {
 	MockLookupInvoiceError error
 	// SendKeysendError, when non-nil, is returned by SendKeysend instead of a success response.
 	SendKeysendError error
+	// PayOfferError, when non-nil, is returned by PayOfferSync instead of a success response.
+	PayOfferError error
+	// ForwardingEvents is the node's forwarding log returned by ListForwards.
+	ForwardingEvents []lnclient.ForwardingEvent
+	// OnchainTransactions is returned by ListOnchainTransactions.
+	OnchainTransactions []lnclient.OnchainTransaction
+	// LastFeeLimitMloki is the fee limit of the last SendPaymentSync or SendKeysend call.
+	LastFeeLimitMloki uint64
 }
 
 func NewMockLn() (*MockLn, error) {
 	return &MockLn{}, nil
 }
 
-func (mln *MockLn) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
+func (mln *MockLn) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	mln.LastFeeLimitMloki = feeLimitMloki
 	// Delay applies before consuming a queued response/error too, so a test can
 	// simulate a slow RPC call that ultimately errors (e.g. to race an async
 	// settle notification in ahead of the synchronous error return).
@@ -119,7 +130,8 @@ func (mln *MockLn) SendPaymentSync(payReq string, amount *uint64) (*lnclient.Pay
 	}, nil
 }
 
-func (mln *MockLn) SendKeysend(amount uint64, destination string, custom_records []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
+func (mln *MockLn) SendKeysend(amount uint64, destination string, custom_records []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
+	mln.LastFeeLimitMloki = feeLimitMloki
 	if mln.PaymentDelay != nil {
 		time.Sleep(*mln.PaymentDelay)
 	}
@@ -131,6 +143,20 @@ func (mln *MockLn) SendKeysend(amount uint64, destination string, custom_records
 	}, nil
 }
 
+func (mln *MockLn) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	if mln.PaymentDelay != nil {
+		time.Sleep(*mln.PaymentDelay)
+	}
+	if mln.PayOfferError != nil {
+		return nil, mln.PayOfferError
+	}
+	return &lnclient.PayOfferResponse{
+		Preimage:    "123preimage",
+		Fee:         1,
+		PaymentHash: MockOfferPaymentHash,
+	}, nil
+}
+
 func (mln *MockLn) GetInfo(ctx context.Context) (info *lnclient.NodeInfo, err error) {
 	return &MockNodeInfo, nil
 }
@@ -203,11 +229,11 @@ func (mln *MockLn) RedeemOnchainFunds(ctx context.Context, toAddress string, amo
 func (mln *MockLn) ResetRouter(key string) error {
 	return nil
 }
-func (mln *MockLn) SendPaymentProbes(ctx context.Context, invoice string) error {
-	return nil
+func (mln *MockLn) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
+	return &lnclient.ProbeResult{Success: true, Hops: []lnclient.ProbeHop{}}, nil
 }
-func (mln *MockLn) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
-	return nil
+func (mln *MockLn) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
+	return &lnclient.ProbeResult{Success: true, Hops: []lnclient.ProbeHop{}}, nil
 }
 func (mln *MockLn) ListPeers(ctx context.Context) ([]lnclient.PeerDetails, error) {
 	return nil, nil
@@ -241,7 +267,7 @@ func (mln *MockLn) UpdateChannel(ctx context.Context, updateChannelRequest *lncl
 }
 
 func (mln *MockLn) GetSupportedNIP47Methods() []string {
-	return []string{"pay_invoice", "pay_keysend", "get_balance", "get_budget", "get_info", "make_invoice", "lookup_invoice", "list_transactions", "multi_pay_invoice", "multi_pay_keysend", "sign_message"}
+	return []string{"pay_invoice", "pay_keysend", "get_balance", "get_budget", "get_info", "make_invoice", "lookup_invoice", "list_transactions", "multi_pay_invoice", "multi_pay_keysend", "sign_message", "make_offer", "pay_offer", "lookup_offer"}
 }
 func (mln *MockLn) GetSupportedNIP47NotificationTypes() []string {
 	if mln.SupportedNotificationTypes != nil {
@@ -267,11 +293,50 @@ func (mln *MockLn) ExecuteCustomNodeCommand(ctx context.Context, command *lnclie
 }
 
 func (mln *MockLn) MakeOffer(ctx context.Context, description string) (string, error) {
-	return "", errors.New("not supported")
+	return MockOffer, nil
 }
 
 func (mln *MockLn) ListOnchainTransactions(ctx context.Context, from, until, limit, offset uint64) ([]lnclient.OnchainTransaction, error) {
-	return nil, errors.ErrUnsupported
+	return mln.OnchainTransactions, nil
+}
+func (mln *MockLn) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	return nil, nil
+}
+func (mln *MockLn) PublishTransaction(ctx context.Context, rawTx string, label string) (string, error) {
+	return "", nil
+}
+func (mln *MockLn) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	return nil, nil
+}
+func (mln *MockLn) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	return nil, nil
+}
+func (mln *MockLn) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (mln *MockLn) FreezeUtxo(ctx context.Context, outpoint string) error {
+	return nil
+}
+func (mln *MockLn) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	return []lnclient.Utxo{}, nil
+}
+func (mln *MockLn) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error) {
+	return "", nil
+}
+func (mln *MockLn) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	return nil, nil
+}
+func (mln *MockLn) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	return mln.SendPaymentSync(payReq, nil, feeLimitMloki)
+}
+func (mln *MockLn) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	forwards := []lnclient.ForwardingEvent{}
+	for _, forward := range mln.ForwardingEvents {
+		if forward.Index > afterIndex && uint64(len(forwards)) < limit {
+			forwards = append(forwards, forward)
+		}
+	}
+	return forwards, nil
 }
 
 func (mln *MockLn) SendCustomMessage(ctx context.Context, peerPubkey string, msgType uint32, data []byte) error {
@@ -282,7 +347,7 @@ func (mln *MockLn) SubscribeCustomMessages(ctx context.Context) (<-chan lnclient
 	return make(chan lnclient.CustomMessage), make(chan error), nil
 }
 
-func (mln *MockLn) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (mln *MockLn) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	return nil, nil, nil
 }
 
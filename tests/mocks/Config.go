@@ -1387,6 +1387,88 @@ func (_c *MockConfig_SetEnableMessageboardNwc_Call) RunAndReturn(run func(bool)
 	return _c
 }
 
+// EnableHttpWebhooks provides a mock function for the type MockConfig
+func (_mock *MockConfig) EnableHttpWebhooks() bool {
+	ret := _mock.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockConfig_EnableHttpWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableHttpWebhooks'
+type MockConfig_EnableHttpWebhooks_Call struct {
+	*mock.Call
+}
+
+// EnableHttpWebhooks is a helper method to define mock.On call
+func (_e *MockConfig_Expecter) EnableHttpWebhooks() *MockConfig_EnableHttpWebhooks_Call {
+	return &MockConfig_EnableHttpWebhooks_Call{Call: _e.mock.On("EnableHttpWebhooks")}
+}
+
+func (_c *MockConfig_EnableHttpWebhooks_Call) Run(run func()) *MockConfig_EnableHttpWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfig_EnableHttpWebhooks_Call) Return(_a0 bool) *MockConfig_EnableHttpWebhooks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfig_EnableHttpWebhooks_Call) RunAndReturn(run func() bool) *MockConfig_EnableHttpWebhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetEnableHttpWebhooks provides a mock function for the type MockConfig
+func (_mock *MockConfig) SetEnableHttpWebhooks(enable bool) error {
+	ret := _mock.Called(enable)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(bool) error); ok {
+		r0 = rf(enable)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockConfig_SetEnableHttpWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEnableHttpWebhooks'
+type MockConfig_SetEnableHttpWebhooks_Call struct {
+	*mock.Call
+}
+
+// SetEnableHttpWebhooks is a helper method to define mock.On call
+func (_e *MockConfig_Expecter) SetEnableHttpWebhooks(enable interface{}) *MockConfig_SetEnableHttpWebhooks_Call {
+	return &MockConfig_SetEnableHttpWebhooks_Call{Call: _e.mock.On("SetEnableHttpWebhooks", enable)}
+}
+
+func (_c *MockConfig_SetEnableHttpWebhooks_Call) Run(run func(enable bool)) *MockConfig_SetEnableHttpWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(bool))
+	})
+	return _c
+}
+
+func (_c *MockConfig_SetEnableHttpWebhooks_Call) Return(_a0 error) *MockConfig_SetEnableHttpWebhooks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfig_SetEnableHttpWebhooks_Call) RunAndReturn(run func(bool) error) *MockConfig_SetEnableHttpWebhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetLokihubStoreURL provides a mock function for the type MockConfig
 func (_mock *MockConfig) GetLokihubStoreURL() string {
 	ret := _mock.Called()
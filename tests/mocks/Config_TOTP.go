@@ -0,0 +1,87 @@
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GetTOTPSecret provides a mock function for the type MockConfig
+func (_mock *MockConfig) GetTOTPSecret() string {
+	ret := _mock.Called()
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockConfig_GetTOTPSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTOTPSecret'
+type MockConfig_GetTOTPSecret_Call struct {
+	*mock.Call
+}
+
+// GetTOTPSecret is a helper method to define mock.On call
+func (_e *MockConfig_Expecter) GetTOTPSecret() *MockConfig_GetTOTPSecret_Call {
+	return &MockConfig_GetTOTPSecret_Call{Call: _e.mock.On("GetTOTPSecret")}
+}
+
+func (_c *MockConfig_GetTOTPSecret_Call) Run(run func()) *MockConfig_GetTOTPSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfig_GetTOTPSecret_Call) Return(s string) *MockConfig_GetTOTPSecret_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockConfig_GetTOTPSecret_Call) RunAndReturn(run func() string) *MockConfig_GetTOTPSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetTOTPSecret provides a mock function for the type MockConfig
+func (_mock *MockConfig) SetTOTPSecret(secret string, encryptionKey string) error {
+	ret := _mock.Called(secret, encryptionKey)
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(secret, encryptionKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockConfig_SetTOTPSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTOTPSecret'
+type MockConfig_SetTOTPSecret_Call struct {
+	*mock.Call
+}
+
+// SetTOTPSecret is a helper method to define mock.On call
+//   - secret
+//   - encryptionKey
+func (_e *MockConfig_Expecter) SetTOTPSecret(secret interface{}, encryptionKey interface{}) *MockConfig_SetTOTPSecret_Call {
+	return &MockConfig_SetTOTPSecret_Call{Call: _e.mock.On("SetTOTPSecret", secret, encryptionKey)}
+}
+
+func (_c *MockConfig_SetTOTPSecret_Call) Run(run func(secret string, encryptionKey string)) *MockConfig_SetTOTPSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockConfig_SetTOTPSecret_Call) Return(err error) *MockConfig_SetTOTPSecret_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockConfig_SetTOTPSecret_Call) RunAndReturn(run func(secret string, encryptionKey string) error) *MockConfig_SetTOTPSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -1608,8 +1608,8 @@ func (_c *MockLNClient_ResetRouter_Call) RunAndReturn(run func(key string) error
 }
 
 // SendKeysend provides a mock function for the type MockLNClient
-func (_mock *MockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
-	ret := _mock.Called(amount, destination, customRecords, preimage)
+func (_mock *MockLNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
+	ret := _mock.Called(amount, destination, customRecords, preimage, feeLimitMloki)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendKeysend")
@@ -1617,18 +1617,18 @@ func (_mock *MockLNClient) SendKeysend(amount uint64, destination string, custom
 
 	var r0 *lnclient.PayKeysendResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string) (*lnclient.PayKeysendResponse, error)); ok {
-		return returnFunc(amount, destination, customRecords, preimage)
+	if returnFunc, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string, uint64) (*lnclient.PayKeysendResponse, error)); ok {
+		return returnFunc(amount, destination, customRecords, preimage, feeLimitMloki)
 	}
-	if returnFunc, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string) *lnclient.PayKeysendResponse); ok {
-		r0 = returnFunc(amount, destination, customRecords, preimage)
+	if returnFunc, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string, uint64) *lnclient.PayKeysendResponse); ok {
+		r0 = returnFunc(amount, destination, customRecords, preimage, feeLimitMloki)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*lnclient.PayKeysendResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(uint64, string, []lnclient.TLVRecord, string) error); ok {
-		r1 = returnFunc(amount, destination, customRecords, preimage)
+	if returnFunc, ok := ret.Get(1).(func(uint64, string, []lnclient.TLVRecord, string, uint64) error); ok {
+		r1 = returnFunc(amount, destination, customRecords, preimage, feeLimitMloki)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1645,13 +1645,14 @@ type MockLNClient_SendKeysend_Call struct {
 //   - destination
 //   - customRecords
 //   - preimage
-func (_e *MockLNClient_Expecter) SendKeysend(amount interface{}, destination interface{}, customRecords interface{}, preimage interface{}) *MockLNClient_SendKeysend_Call {
-	return &MockLNClient_SendKeysend_Call{Call: _e.mock.On("SendKeysend", amount, destination, customRecords, preimage)}
+//   - feeLimitMloki
+func (_e *MockLNClient_Expecter) SendKeysend(amount interface{}, destination interface{}, customRecords interface{}, preimage interface{}, feeLimitMloki interface{}) *MockLNClient_SendKeysend_Call {
+	return &MockLNClient_SendKeysend_Call{Call: _e.mock.On("SendKeysend", amount, destination, customRecords, preimage, feeLimitMloki)}
 }
 
-func (_c *MockLNClient_SendKeysend_Call) Run(run func(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string)) *MockLNClient_SendKeysend_Call {
+func (_c *MockLNClient_SendKeysend_Call) Run(run func(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64)) *MockLNClient_SendKeysend_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint64), args[1].(string), args[2].([]lnclient.TLVRecord), args[3].(string))
+		run(args[0].(uint64), args[1].(string), args[2].([]lnclient.TLVRecord), args[3].(string), args[4].(uint64))
 	})
 	return _c
 }
@@ -1661,26 +1662,37 @@ func (_c *MockLNClient_SendKeysend_Call) Return(payKeysendResponse *lnclient.Pay
 	return _c
 }
 
-func (_c *MockLNClient_SendKeysend_Call) RunAndReturn(run func(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error)) *MockLNClient_SendKeysend_Call {
+func (_c *MockLNClient_SendKeysend_Call) RunAndReturn(run func(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error)) *MockLNClient_SendKeysend_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // SendPaymentProbes provides a mock function for the type MockLNClient
-func (_mock *MockLNClient) SendPaymentProbes(ctx context.Context, invoice string) error {
+func (_mock *MockLNClient) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
 	ret := _mock.Called(ctx, invoice)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendPaymentProbes")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+	var r0 *lnclient.ProbeResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*lnclient.ProbeResult, error)); ok {
+		return returnFunc(ctx, invoice)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *lnclient.ProbeResult); ok {
 		r0 = returnFunc(ctx, invoice)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.ProbeResult)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, invoice)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // MockLNClient_SendPaymentProbes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendPaymentProbes'
@@ -1702,19 +1714,19 @@ func (_c *MockLNClient_SendPaymentProbes_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockLNClient_SendPaymentProbes_Call) Return(err error) *MockLNClient_SendPaymentProbes_Call {
-	_c.Call.Return(err)
+func (_c *MockLNClient_SendPaymentProbes_Call) Return(probeResult *lnclient.ProbeResult, err error) *MockLNClient_SendPaymentProbes_Call {
+	_c.Call.Return(probeResult, err)
 	return _c
 }
 
-func (_c *MockLNClient_SendPaymentProbes_Call) RunAndReturn(run func(ctx context.Context, invoice string) error) *MockLNClient_SendPaymentProbes_Call {
+func (_c *MockLNClient_SendPaymentProbes_Call) RunAndReturn(run func(ctx context.Context, invoice string) (*lnclient.ProbeResult, error)) *MockLNClient_SendPaymentProbes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // SendPaymentSync provides a mock function for the type MockLNClient
-func (_mock *MockLNClient) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
-	ret := _mock.Called(payReq, amount)
+func (_mock *MockLNClient) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	ret := _mock.Called(payReq, amount, feeLimitMloki)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendPaymentSync")
@@ -1722,18 +1734,18 @@ func (_mock *MockLNClient) SendPaymentSync(payReq string, amount *uint64) (*lncl
 
 	var r0 *lnclient.PayInvoiceResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, *uint64) (*lnclient.PayInvoiceResponse, error)); ok {
-		return returnFunc(payReq, amount)
+	if returnFunc, ok := ret.Get(0).(func(string, *uint64, uint64) (*lnclient.PayInvoiceResponse, error)); ok {
+		return returnFunc(payReq, amount, feeLimitMloki)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, *uint64) *lnclient.PayInvoiceResponse); ok {
-		r0 = returnFunc(payReq, amount)
+	if returnFunc, ok := ret.Get(0).(func(string, *uint64, uint64) *lnclient.PayInvoiceResponse); ok {
+		r0 = returnFunc(payReq, amount, feeLimitMloki)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*lnclient.PayInvoiceResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string, *uint64) error); ok {
-		r1 = returnFunc(payReq, amount)
+	if returnFunc, ok := ret.Get(1).(func(string, *uint64, uint64) error); ok {
+		r1 = returnFunc(payReq, amount, feeLimitMloki)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1748,13 +1760,14 @@ type MockLNClient_SendPaymentSync_Call struct {
 // SendPaymentSync is a helper method to define mock.On call
 //   - payReq
 //   - amount
-func (_e *MockLNClient_Expecter) SendPaymentSync(payReq interface{}, amount interface{}) *MockLNClient_SendPaymentSync_Call {
-	return &MockLNClient_SendPaymentSync_Call{Call: _e.mock.On("SendPaymentSync", payReq, amount)}
+//   - feeLimitMloki
+func (_e *MockLNClient_Expecter) SendPaymentSync(payReq interface{}, amount interface{}, feeLimitMloki interface{}) *MockLNClient_SendPaymentSync_Call {
+	return &MockLNClient_SendPaymentSync_Call{Call: _e.mock.On("SendPaymentSync", payReq, amount, feeLimitMloki)}
 }
 
-func (_c *MockLNClient_SendPaymentSync_Call) Run(run func(payReq string, amount *uint64)) *MockLNClient_SendPaymentSync_Call {
+func (_c *MockLNClient_SendPaymentSync_Call) Run(run func(payReq string, amount *uint64, feeLimitMloki uint64)) *MockLNClient_SendPaymentSync_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(*uint64))
+		run(args[0].(string), args[1].(*uint64), args[2].(uint64))
 	})
 	return _c
 }
@@ -1764,26 +1777,37 @@ func (_c *MockLNClient_SendPaymentSync_Call) Return(payInvoiceResponse *lnclient
 	return _c
 }
 
-func (_c *MockLNClient_SendPaymentSync_Call) RunAndReturn(run func(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error)) *MockLNClient_SendPaymentSync_Call {
+func (_c *MockLNClient_SendPaymentSync_Call) RunAndReturn(run func(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error)) *MockLNClient_SendPaymentSync_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // SendSpontaneousPaymentProbes provides a mock function for the type MockLNClient
-func (_mock *MockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
+func (_mock *MockLNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
 	ret := _mock.Called(ctx, amountMloki, nodeId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendSpontaneousPaymentProbes")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+	var r0 *lnclient.ProbeResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string) (*lnclient.ProbeResult, error)); ok {
+		return returnFunc(ctx, amountMloki, nodeId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string) *lnclient.ProbeResult); ok {
 		r0 = returnFunc(ctx, amountMloki, nodeId)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.ProbeResult)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, string) error); ok {
+		r1 = returnFunc(ctx, amountMloki, nodeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // MockLNClient_SendSpontaneousPaymentProbes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendSpontaneousPaymentProbes'
@@ -1806,12 +1830,12 @@ func (_c *MockLNClient_SendSpontaneousPaymentProbes_Call) Run(run func(ctx conte
 	return _c
 }
 
-func (_c *MockLNClient_SendSpontaneousPaymentProbes_Call) Return(err error) *MockLNClient_SendSpontaneousPaymentProbes_Call {
-	_c.Call.Return(err)
+func (_c *MockLNClient_SendSpontaneousPaymentProbes_Call) Return(probeResult *lnclient.ProbeResult, err error) *MockLNClient_SendSpontaneousPaymentProbes_Call {
+	_c.Call.Return(probeResult, err)
 	return _c
 }
 
-func (_c *MockLNClient_SendSpontaneousPaymentProbes_Call) RunAndReturn(run func(ctx context.Context, amountMloki uint64, nodeId string) error) *MockLNClient_SendSpontaneousPaymentProbes_Call {
+func (_c *MockLNClient_SendSpontaneousPaymentProbes_Call) RunAndReturn(run func(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error)) *MockLNClient_SendSpontaneousPaymentProbes_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2041,7 +2065,7 @@ func (_c *MockLNClient_UpdateLastWalletSyncRequest_Call) RunAndReturn(run func()
 }
 
 // SubscribeChannelAcceptor provides a mock function for the type MockLNClient
-func (_mock *MockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool) error, error) {
+func (_mock *MockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(id string, accept bool, zeroConf bool, rejectReason string) error, error) {
 	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
@@ -2049,9 +2073,9 @@ func (_mock *MockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan
 	}
 
 	var r0 <-chan lnclient.ChannelAcceptRequest
-	var r1 func(id string, accept bool, zeroConf bool) error
+	var r1 func(id string, accept bool, zeroConf bool, rejectReason string) error
 	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error)); ok {
 		return returnFunc(ctx)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context) <-chan lnclient.ChannelAcceptRequest); ok {
@@ -2061,11 +2085,11 @@ func (_mock *MockLNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan
 			r0 = ret.Get(0).(<-chan lnclient.ChannelAcceptRequest)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) func(string, bool, bool) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context) func(string, bool, bool, string) error); ok {
 		r1 = returnFunc(ctx)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(func(string, bool, bool) error)
+			r1 = ret.Get(1).(func(string, bool, bool, string) error)
 		}
 	}
 	if returnFunc, ok := ret.Get(2).(func(context.Context) error); ok {
@@ -2094,12 +2118,12 @@ func (_c *MockLNClient_SubscribeChannelAcceptor_Call) Run(run func(ctx context.C
 	return _c
 }
 
-func (_c *MockLNClient_SubscribeChannelAcceptor_Call) Return(requests <-chan lnclient.ChannelAcceptRequest, acceptFn func(string, bool, bool) error, err error) *MockLNClient_SubscribeChannelAcceptor_Call {
+func (_c *MockLNClient_SubscribeChannelAcceptor_Call) Return(requests <-chan lnclient.ChannelAcceptRequest, acceptFn func(string, bool, bool, string) error, err error) *MockLNClient_SubscribeChannelAcceptor_Call {
 	_c.Call.Return(requests, acceptFn, err)
 	return _c
 }
 
-func (_c *MockLNClient_SubscribeChannelAcceptor_Call) RunAndReturn(run func(context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error)) *MockLNClient_SubscribeChannelAcceptor_Call {
+func (_c *MockLNClient_SubscribeChannelAcceptor_Call) RunAndReturn(run func(context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error)) *MockLNClient_SubscribeChannelAcceptor_Call {
 	_c.Run(func(ctx context.Context) { run(ctx) })
 	return _c
 }
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BatchOpenChannel provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	ret := _mock.Called(ctx, batchOpenChannelRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchOpenChannel")
+	}
+
+	var r0 *lnclient.BatchOpenChannelResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error)); ok {
+		return returnFunc(ctx, batchOpenChannelRequest)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *lnclient.BatchOpenChannelRequest) *lnclient.BatchOpenChannelResponse); ok {
+		r0 = returnFunc(ctx, batchOpenChannelRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.BatchOpenChannelResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *lnclient.BatchOpenChannelRequest) error); ok {
+		r1 = returnFunc(ctx, batchOpenChannelRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_BatchOpenChannel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchOpenChannel'
+type MockLNClient_BatchOpenChannel_Call struct {
+	*mock.Call
+}
+
+// BatchOpenChannel is a helper method to define mock.On call
+//   - ctx
+//   - batchOpenChannelRequest
+func (_e *MockLNClient_Expecter) BatchOpenChannel(ctx interface{}, batchOpenChannelRequest interface{}) *MockLNClient_BatchOpenChannel_Call {
+	return &MockLNClient_BatchOpenChannel_Call{Call: _e.mock.On("BatchOpenChannel", ctx, batchOpenChannelRequest)}
+}
+
+func (_c *MockLNClient_BatchOpenChannel_Call) Run(run func(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest)) *MockLNClient_BatchOpenChannel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*lnclient.BatchOpenChannelRequest))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_BatchOpenChannel_Call) Return(batchOpenChannelResponse *lnclient.BatchOpenChannelResponse, err error) *MockLNClient_BatchOpenChannel_Call {
+	_c.Call.Return(batchOpenChannelResponse, err)
+	return _c
+}
+
+func (_c *MockLNClient_BatchOpenChannel_Call) RunAndReturn(run func(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error)) *MockLNClient_BatchOpenChannel_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BumpFee provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	ret := _mock.Called(ctx, bumpFeeRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BumpFee")
+	}
+
+	var r0 *lnclient.BumpFeeResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error)); ok {
+		return returnFunc(ctx, bumpFeeRequest)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *lnclient.BumpFeeRequest) *lnclient.BumpFeeResponse); ok {
+		r0 = returnFunc(ctx, bumpFeeRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.BumpFeeResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *lnclient.BumpFeeRequest) error); ok {
+		r1 = returnFunc(ctx, bumpFeeRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_BumpFee_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BumpFee'
+type MockLNClient_BumpFee_Call struct {
+	*mock.Call
+}
+
+// BumpFee is a helper method to define mock.On call
+//   - ctx
+//   - bumpFeeRequest
+func (_e *MockLNClient_Expecter) BumpFee(ctx interface{}, bumpFeeRequest interface{}) *MockLNClient_BumpFee_Call {
+	return &MockLNClient_BumpFee_Call{Call: _e.mock.On("BumpFee", ctx, bumpFeeRequest)}
+}
+
+func (_c *MockLNClient_BumpFee_Call) Run(run func(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest)) *MockLNClient_BumpFee_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*lnclient.BumpFeeRequest))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_BumpFee_Call) Return(bumpFeeResponse *lnclient.BumpFeeResponse, err error) *MockLNClient_BumpFee_Call {
+	_c.Call.Return(bumpFeeResponse, err)
+	return _c
+}
+
+func (_c *MockLNClient_BumpFee_Call) RunAndReturn(run func(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error)) *MockLNClient_BumpFee_Call {
+	_c.Call.Return(run)
+	return _c
+}
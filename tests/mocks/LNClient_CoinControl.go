@@ -0,0 +1,385 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SendOnchainFundsFromUtxos provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error) {
+	ret := _mock.Called(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendOnchainFundsFromUtxos")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uint64, *uint64, bool, []string) (string, error)); ok {
+		return returnFunc(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uint64, *uint64, bool, []string) string); ok {
+		r0 = returnFunc(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uint64, *uint64, bool, []string) error); ok {
+		r1 = returnFunc(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_SendOnchainFundsFromUtxos_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendOnchainFundsFromUtxos'
+type MockLNClient_SendOnchainFundsFromUtxos_Call struct {
+	*mock.Call
+}
+
+// SendOnchainFundsFromUtxos is a helper method to define mock.On call
+//   - ctx
+//   - toAddress
+//   - amount
+//   - feeRate
+//   - sendAll
+//   - outpoints
+func (_e *MockLNClient_Expecter) SendOnchainFundsFromUtxos(ctx interface{}, toAddress interface{}, amount interface{}, feeRate interface{}, sendAll interface{}, outpoints interface{}) *MockLNClient_SendOnchainFundsFromUtxos_Call {
+	return &MockLNClient_SendOnchainFundsFromUtxos_Call{Call: _e.mock.On("SendOnchainFundsFromUtxos", ctx, toAddress, amount, feeRate, sendAll, outpoints)}
+}
+
+func (_c *MockLNClient_SendOnchainFundsFromUtxos_Call) Run(run func(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string)) *MockLNClient_SendOnchainFundsFromUtxos_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint64), args[3].(*uint64), args[4].(bool), args[5].([]string))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_SendOnchainFundsFromUtxos_Call) Return(string string, err error) *MockLNClient_SendOnchainFundsFromUtxos_Call {
+	_c.Call.Return(string, err)
+	return _c
+}
+
+func (_c *MockLNClient_SendOnchainFundsFromUtxos_Call) RunAndReturn(run func(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error)) *MockLNClient_SendOnchainFundsFromUtxos_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUtxos provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUtxos")
+	}
+
+	var r0 []lnclient.Utxo
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]lnclient.Utxo, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []lnclient.Utxo); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]lnclient.Utxo)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_ListUtxos_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUtxos'
+type MockLNClient_ListUtxos_Call struct {
+	*mock.Call
+}
+
+// ListUtxos is a helper method to define mock.On call
+//   - ctx
+func (_e *MockLNClient_Expecter) ListUtxos(ctx interface{}) *MockLNClient_ListUtxos_Call {
+	return &MockLNClient_ListUtxos_Call{Call: _e.mock.On("ListUtxos", ctx)}
+}
+
+func (_c *MockLNClient_ListUtxos_Call) Run(run func(ctx context.Context)) *MockLNClient_ListUtxos_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_ListUtxos_Call) Return(utxos []lnclient.Utxo, err error) *MockLNClient_ListUtxos_Call {
+	_c.Call.Return(utxos, err)
+	return _c
+}
+
+func (_c *MockLNClient_ListUtxos_Call) RunAndReturn(run func(ctx context.Context) ([]lnclient.Utxo, error)) *MockLNClient_ListUtxos_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FreezeUtxo provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) FreezeUtxo(ctx context.Context, outpoint string) error {
+	ret := _mock.Called(ctx, outpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FreezeUtxo")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, outpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLNClient_FreezeUtxo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FreezeUtxo'
+type MockLNClient_FreezeUtxo_Call struct {
+	*mock.Call
+}
+
+// FreezeUtxo is a helper method to define mock.On call
+//   - ctx
+//   - outpoint
+func (_e *MockLNClient_Expecter) FreezeUtxo(ctx interface{}, outpoint interface{}) *MockLNClient_FreezeUtxo_Call {
+	return &MockLNClient_FreezeUtxo_Call{Call: _e.mock.On("FreezeUtxo", ctx, outpoint)}
+}
+
+func (_c *MockLNClient_FreezeUtxo_Call) Run(run func(ctx context.Context, outpoint string)) *MockLNClient_FreezeUtxo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_FreezeUtxo_Call) Return(err error) *MockLNClient_FreezeUtxo_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLNClient_FreezeUtxo_Call) RunAndReturn(run func(ctx context.Context, outpoint string) error) *MockLNClient_FreezeUtxo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnfreezeUtxo provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	ret := _mock.Called(ctx, outpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnfreezeUtxo")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, outpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLNClient_UnfreezeUtxo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnfreezeUtxo'
+type MockLNClient_UnfreezeUtxo_Call struct {
+	*mock.Call
+}
+
+// UnfreezeUtxo is a helper method to define mock.On call
+//   - ctx
+//   - outpoint
+func (_e *MockLNClient_Expecter) UnfreezeUtxo(ctx interface{}, outpoint interface{}) *MockLNClient_UnfreezeUtxo_Call {
+	return &MockLNClient_UnfreezeUtxo_Call{Call: _e.mock.On("UnfreezeUtxo", ctx, outpoint)}
+}
+
+func (_c *MockLNClient_UnfreezeUtxo_Call) Run(run func(ctx context.Context, outpoint string)) *MockLNClient_UnfreezeUtxo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_UnfreezeUtxo_Call) Return(err error) *MockLNClient_UnfreezeUtxo_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLNClient_UnfreezeUtxo_Call) RunAndReturn(run func(ctx context.Context, outpoint string) error) *MockLNClient_UnfreezeUtxo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePsbt provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	ret := _mock.Called(ctx, createPsbtRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePsbt")
+	}
+
+	var r0 *lnclient.CreatePsbtResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error)); ok {
+		return returnFunc(ctx, createPsbtRequest)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *lnclient.CreatePsbtRequest) *lnclient.CreatePsbtResponse); ok {
+		r0 = returnFunc(ctx, createPsbtRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.CreatePsbtResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *lnclient.CreatePsbtRequest) error); ok {
+		r1 = returnFunc(ctx, createPsbtRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_CreatePsbt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePsbt'
+type MockLNClient_CreatePsbt_Call struct {
+	*mock.Call
+}
+
+// CreatePsbt is a helper method to define mock.On call
+//   - ctx
+//   - createPsbtRequest
+func (_e *MockLNClient_Expecter) CreatePsbt(ctx interface{}, createPsbtRequest interface{}) *MockLNClient_CreatePsbt_Call {
+	return &MockLNClient_CreatePsbt_Call{Call: _e.mock.On("CreatePsbt", ctx, createPsbtRequest)}
+}
+
+func (_c *MockLNClient_CreatePsbt_Call) Run(run func(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest)) *MockLNClient_CreatePsbt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*lnclient.CreatePsbtRequest))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_CreatePsbt_Call) Return(createPsbtResponse *lnclient.CreatePsbtResponse, err error) *MockLNClient_CreatePsbt_Call {
+	_c.Call.Return(createPsbtResponse, err)
+	return _c
+}
+
+func (_c *MockLNClient_CreatePsbt_Call) RunAndReturn(run func(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error)) *MockLNClient_CreatePsbt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FinalizePsbt provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	ret := _mock.Called(ctx, psbt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinalizePsbt")
+	}
+
+	var r0 *lnclient.FinalizePsbtResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*lnclient.FinalizePsbtResponse, error)); ok {
+		return returnFunc(ctx, psbt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *lnclient.FinalizePsbtResponse); ok {
+		r0 = returnFunc(ctx, psbt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.FinalizePsbtResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, psbt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_FinalizePsbt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinalizePsbt'
+type MockLNClient_FinalizePsbt_Call struct {
+	*mock.Call
+}
+
+// FinalizePsbt is a helper method to define mock.On call
+//   - ctx
+//   - psbt
+func (_e *MockLNClient_Expecter) FinalizePsbt(ctx interface{}, psbt interface{}) *MockLNClient_FinalizePsbt_Call {
+	return &MockLNClient_FinalizePsbt_Call{Call: _e.mock.On("FinalizePsbt", ctx, psbt)}
+}
+
+func (_c *MockLNClient_FinalizePsbt_Call) Run(run func(ctx context.Context, psbt string)) *MockLNClient_FinalizePsbt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_FinalizePsbt_Call) Return(finalizePsbtResponse *lnclient.FinalizePsbtResponse, err error) *MockLNClient_FinalizePsbt_Call {
+	_c.Call.Return(finalizePsbtResponse, err)
+	return _c
+}
+
+func (_c *MockLNClient_FinalizePsbt_Call) RunAndReturn(run func(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error)) *MockLNClient_FinalizePsbt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PublishTransaction provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) PublishTransaction(ctx context.Context, rawTx string, label string) (string, error) {
+	ret := _mock.Called(ctx, rawTx, label)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishTransaction")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return returnFunc(ctx, rawTx, label)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = returnFunc(ctx, rawTx, label)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, rawTx, label)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_PublishTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishTransaction'
+type MockLNClient_PublishTransaction_Call struct {
+	*mock.Call
+}
+
+// PublishTransaction is a helper method to define mock.On call
+//   - ctx
+//   - rawTx
+//   - label
+func (_e *MockLNClient_Expecter) PublishTransaction(ctx interface{}, rawTx interface{}, label interface{}) *MockLNClient_PublishTransaction_Call {
+	return &MockLNClient_PublishTransaction_Call{Call: _e.mock.On("PublishTransaction", ctx, rawTx, label)}
+}
+
+func (_c *MockLNClient_PublishTransaction_Call) Run(run func(ctx context.Context, rawTx string, label string)) *MockLNClient_PublishTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_PublishTransaction_Call) Return(string string, err error) *MockLNClient_PublishTransaction_Call {
+	_c.Call.Return(string, err)
+	return _c
+}
+
+func (_c *MockLNClient_PublishTransaction_Call) RunAndReturn(run func(ctx context.Context, rawTx string, label string) (string, error)) *MockLNClient_PublishTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
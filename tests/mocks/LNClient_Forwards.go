@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ListForwards provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	ret := _mock.Called(ctx, afterIndex, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListForwards")
+	}
+
+	var r0 []lnclient.ForwardingEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64) ([]lnclient.ForwardingEvent, error)); ok {
+		return returnFunc(ctx, afterIndex, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64) []lnclient.ForwardingEvent); ok {
+		r0 = returnFunc(ctx, afterIndex, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]lnclient.ForwardingEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = returnFunc(ctx, afterIndex, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_ListForwards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListForwards'
+type MockLNClient_ListForwards_Call struct {
+	*mock.Call
+}
+
+// ListForwards is a helper method to define mock.On call
+//   - ctx
+//   - afterIndex
+//   - limit
+func (_e *MockLNClient_Expecter) ListForwards(ctx interface{}, afterIndex interface{}, limit interface{}) *MockLNClient_ListForwards_Call {
+	return &MockLNClient_ListForwards_Call{Call: _e.mock.On("ListForwards", ctx, afterIndex, limit)}
+}
+
+func (_c *MockLNClient_ListForwards_Call) Run(run func(ctx context.Context, afterIndex uint64, limit uint64)) *MockLNClient_ListForwards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_ListForwards_Call) Return(forwardingEvents []lnclient.ForwardingEvent, err error) *MockLNClient_ListForwards_Call {
+	_c.Call.Return(forwardingEvents, err)
+	return _c
+}
+
+func (_c *MockLNClient_ListForwards_Call) RunAndReturn(run func(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error)) *MockLNClient_ListForwards_Call {
+	_c.Call.Return(run)
+	return _c
+}
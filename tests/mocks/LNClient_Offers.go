@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayOfferSync provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	ret := _mock.Called(ctx, offer, amount, payerNote)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PayOfferSync")
+	}
+
+	var r0 *lnclient.PayOfferResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uint64, string) (*lnclient.PayOfferResponse, error)); ok {
+		return returnFunc(ctx, offer, amount, payerNote)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uint64, string) *lnclient.PayOfferResponse); ok {
+		r0 = returnFunc(ctx, offer, amount, payerNote)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.PayOfferResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uint64, string) error); ok {
+		r1 = returnFunc(ctx, offer, amount, payerNote)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_PayOfferSync_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PayOfferSync'
+type MockLNClient_PayOfferSync_Call struct {
+	*mock.Call
+}
+
+// PayOfferSync is a helper method to define mock.On call
+//   - ctx
+//   - offer
+//   - amount
+//   - payerNote
+func (_e *MockLNClient_Expecter) PayOfferSync(ctx interface{}, offer interface{}, amount interface{}, payerNote interface{}) *MockLNClient_PayOfferSync_Call {
+	return &MockLNClient_PayOfferSync_Call{Call: _e.mock.On("PayOfferSync", ctx, offer, amount, payerNote)}
+}
+
+func (_c *MockLNClient_PayOfferSync_Call) Run(run func(ctx context.Context, offer string, amount uint64, payerNote string)) *MockLNClient_PayOfferSync_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint64), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_PayOfferSync_Call) Return(payOfferResponse *lnclient.PayOfferResponse, err error) *MockLNClient_PayOfferSync_Call {
+	_c.Call.Return(payOfferResponse, err)
+	return _c
+}
+
+func (_c *MockLNClient_PayOfferSync_Call) RunAndReturn(run func(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error)) *MockLNClient_PayOfferSync_Call {
+	_c.Call.Return(run)
+	return _c
+}
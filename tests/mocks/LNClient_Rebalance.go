@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/flokiorg/lokihub/lnclient"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SendPaymentThroughChannels provides a mock function for the type MockLNClient
+func (_mock *MockLNClient) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	ret := _mock.Called(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendPaymentThroughChannels")
+	}
+
+	var r0 *lnclient.PayInvoiceResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, uint64) (*lnclient.PayInvoiceResponse, error)); ok {
+		return returnFunc(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, uint64) *lnclient.PayInvoiceResponse); ok {
+		r0 = returnFunc(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.PayInvoiceResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, uint64) error); ok {
+		r1 = returnFunc(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLNClient_SendPaymentThroughChannels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendPaymentThroughChannels'
+type MockLNClient_SendPaymentThroughChannels_Call struct {
+	*mock.Call
+}
+
+// SendPaymentThroughChannels is a helper method to define mock.On call
+//   - ctx
+//   - payReq
+//   - outgoingChannelId
+//   - lastHopPubkey
+//   - feeLimitMloki
+func (_e *MockLNClient_Expecter) SendPaymentThroughChannels(ctx interface{}, payReq interface{}, outgoingChannelId interface{}, lastHopPubkey interface{}, feeLimitMloki interface{}) *MockLNClient_SendPaymentThroughChannels_Call {
+	return &MockLNClient_SendPaymentThroughChannels_Call{Call: _e.mock.On("SendPaymentThroughChannels", ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)}
+}
+
+func (_c *MockLNClient_SendPaymentThroughChannels_Call) Run(run func(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64)) *MockLNClient_SendPaymentThroughChannels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(uint64))
+	})
+	return _c
+}
+
+func (_c *MockLNClient_SendPaymentThroughChannels_Call) Return(payInvoiceResponse *lnclient.PayInvoiceResponse, err error) *MockLNClient_SendPaymentThroughChannels_Call {
+	_c.Call.Return(payInvoiceResponse, err)
+	return _c
+}
+
+func (_c *MockLNClient_SendPaymentThroughChannels_Call) RunAndReturn(run func(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error)) *MockLNClient_SendPaymentThroughChannels_Call {
+	_c.Call.Return(run)
+	return _c
+}
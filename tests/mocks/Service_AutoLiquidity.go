@@ -0,0 +1,10 @@
+package mocks
+
+import (
+	"github.com/flokiorg/lokihub/autoliquidity"
+)
+
+func (_mock *MockService) GetAutoLiquidityService() autoliquidity.AutoLiquidityService {
+	args := _mock.Called()
+	return args.Get(0).(autoliquidity.AutoLiquidityService)
+}
@@ -0,0 +1,10 @@
+package mocks
+
+import (
+	"github.com/flokiorg/lokihub/feemanager"
+)
+
+func (_mock *MockService) GetFeeManagerService() feemanager.FeeManagerService {
+	args := _mock.Called()
+	return args.Get(0).(feemanager.FeeManagerService)
+}
@@ -0,0 +1,10 @@
+package mocks
+
+import (
+	"github.com/flokiorg/lokihub/lnurl"
+)
+
+func (_mock *MockService) GetLNURLService() lnurl.LNURLService {
+	args := _mock.Called()
+	return args.Get(0).(lnurl.LNURLService)
+}
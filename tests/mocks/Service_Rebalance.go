@@ -0,0 +1,10 @@
+package mocks
+
+import (
+	"github.com/flokiorg/lokihub/rebalance"
+)
+
+func (_mock *MockService) GetRebalanceService() rebalance.RebalanceService {
+	args := _mock.Called()
+	return args.Get(0).(rebalance.RebalanceService)
+}
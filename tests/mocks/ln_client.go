@@ -532,6 +532,308 @@ func (_m *LNClient) ListOnchainTransactions(ctx context.Context, from uint64, un
 	return r0, r1
 }
 
+// BumpFee provides a mock function with given fields: ctx, bumpFeeRequest
+func (_m *LNClient) BumpFee(ctx context.Context, bumpFeeRequest *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error) {
+	ret := _m.Called(ctx, bumpFeeRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BumpFee")
+	}
+
+	var r0 *lnclient.BumpFeeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *lnclient.BumpFeeRequest) (*lnclient.BumpFeeResponse, error)); ok {
+		return rf(ctx, bumpFeeRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *lnclient.BumpFeeRequest) *lnclient.BumpFeeResponse); ok {
+		r0 = rf(ctx, bumpFeeRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.BumpFeeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *lnclient.BumpFeeRequest) error); ok {
+		r1 = rf(ctx, bumpFeeRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishTransaction provides a mock function with given fields: ctx, rawTx, label
+func (_m *LNClient) PublishTransaction(ctx context.Context, rawTx string, label string) (string, error) {
+	ret := _m.Called(ctx, rawTx, label)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishTransaction")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, rawTx, label)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, rawTx, label)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, rawTx, label)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinalizePsbt provides a mock function with given fields: ctx, psbt
+func (_m *LNClient) FinalizePsbt(ctx context.Context, psbt string) (*lnclient.FinalizePsbtResponse, error) {
+	ret := _m.Called(ctx, psbt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinalizePsbt")
+	}
+
+	var r0 *lnclient.FinalizePsbtResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*lnclient.FinalizePsbtResponse, error)); ok {
+		return rf(ctx, psbt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *lnclient.FinalizePsbtResponse); ok {
+		r0 = rf(ctx, psbt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.FinalizePsbtResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, psbt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePsbt provides a mock function with given fields: ctx, createPsbtRequest
+func (_m *LNClient) CreatePsbt(ctx context.Context, createPsbtRequest *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error) {
+	ret := _m.Called(ctx, createPsbtRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePsbt")
+	}
+
+	var r0 *lnclient.CreatePsbtResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *lnclient.CreatePsbtRequest) (*lnclient.CreatePsbtResponse, error)); ok {
+		return rf(ctx, createPsbtRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *lnclient.CreatePsbtRequest) *lnclient.CreatePsbtResponse); ok {
+		r0 = rf(ctx, createPsbtRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.CreatePsbtResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *lnclient.CreatePsbtRequest) error); ok {
+		r1 = rf(ctx, createPsbtRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnfreezeUtxo provides a mock function with given fields: ctx, outpoint
+func (_m *LNClient) UnfreezeUtxo(ctx context.Context, outpoint string) error {
+	ret := _m.Called(ctx, outpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnfreezeUtxo")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, outpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FreezeUtxo provides a mock function with given fields: ctx, outpoint
+func (_m *LNClient) FreezeUtxo(ctx context.Context, outpoint string) error {
+	ret := _m.Called(ctx, outpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FreezeUtxo")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, outpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListUtxos provides a mock function with given fields: ctx
+func (_m *LNClient) ListUtxos(ctx context.Context) ([]lnclient.Utxo, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUtxos")
+	}
+
+	var r0 []lnclient.Utxo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]lnclient.Utxo, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []lnclient.Utxo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]lnclient.Utxo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendOnchainFundsFromUtxos provides a mock function with given fields: ctx, toAddress, amount, feeRate, sendAll, outpoints
+func (_m *LNClient) SendOnchainFundsFromUtxos(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool, outpoints []string) (string, error) {
+	ret := _m.Called(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendOnchainFundsFromUtxos")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, *uint64, bool, []string) (string, error)); ok {
+		return rf(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, *uint64, bool, []string) string); ok {
+		r0 = rf(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, *uint64, bool, []string) error); ok {
+		r1 = rf(ctx, toAddress, amount, feeRate, sendAll, outpoints)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchOpenChannel provides a mock function with given fields: ctx, batchOpenChannelRequest
+func (_m *LNClient) BatchOpenChannel(ctx context.Context, batchOpenChannelRequest *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error) {
+	ret := _m.Called(ctx, batchOpenChannelRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchOpenChannel")
+	}
+
+	var r0 *lnclient.BatchOpenChannelResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *lnclient.BatchOpenChannelRequest) (*lnclient.BatchOpenChannelResponse, error)); ok {
+		return rf(ctx, batchOpenChannelRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *lnclient.BatchOpenChannelRequest) *lnclient.BatchOpenChannelResponse); ok {
+		r0 = rf(ctx, batchOpenChannelRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.BatchOpenChannelResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *lnclient.BatchOpenChannelRequest) error); ok {
+		r1 = rf(ctx, batchOpenChannelRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendPaymentThroughChannels provides a mock function with given fields: ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki
+func (_m *LNClient) SendPaymentThroughChannels(ctx context.Context, payReq string, outgoingChannelId string, lastHopPubkey string, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	ret := _m.Called(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendPaymentThroughChannels")
+	}
+
+	var r0 *lnclient.PayInvoiceResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, uint64) (*lnclient.PayInvoiceResponse, error)); ok {
+		return rf(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, uint64) *lnclient.PayInvoiceResponse); ok {
+		r0 = rf(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.PayInvoiceResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, uint64) error); ok {
+		r1 = rf(ctx, payReq, outgoingChannelId, lastHopPubkey, feeLimitMloki)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListForwards provides a mock function with given fields: ctx, afterIndex, limit
+func (_m *LNClient) ListForwards(ctx context.Context, afterIndex uint64, limit uint64) ([]lnclient.ForwardingEvent, error) {
+	ret := _m.Called(ctx, afterIndex, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListForwards")
+	}
+
+	var r0 []lnclient.ForwardingEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) ([]lnclient.ForwardingEvent, error)); ok {
+		return rf(ctx, afterIndex, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) []lnclient.ForwardingEvent); ok {
+		r0 = rf(ctx, afterIndex, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]lnclient.ForwardingEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, afterIndex, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListPeers provides a mock function with given fields: ctx
 func (_m *LNClient) ListPeers(ctx context.Context) ([]lnclient.PeerDetails, error) {
 	ret := _m.Called(ctx)
@@ -682,6 +984,34 @@ func (_m *LNClient) MakeInvoice(ctx context.Context, amount int64, description s
 	return r0, r1
 }
 
+// MakeOffer provides a mock function with given fields: ctx, description
+func (_m *LNClient) MakeOffer(ctx context.Context, description string) (string, error) {
+	ret := _m.Called(ctx, description)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeOffer")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, description)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, description)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, description)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // OpenChannel provides a mock function with given fields: ctx, openChannelRequest
 func (_m *LNClient) OpenChannel(ctx context.Context, openChannelRequest *lnclient.OpenChannelRequest) (*lnclient.OpenChannelResponse, error) {
 	ret := _m.Called(ctx, openChannelRequest)
@@ -712,6 +1042,36 @@ func (_m *LNClient) OpenChannel(ctx context.Context, openChannelRequest *lnclien
 	return r0, r1
 }
 
+// PayOfferSync provides a mock function with given fields: ctx, offer, amount, payerNote
+func (_m *LNClient) PayOfferSync(ctx context.Context, offer string, amount uint64, payerNote string) (*lnclient.PayOfferResponse, error) {
+	ret := _m.Called(ctx, offer, amount, payerNote)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PayOfferSync")
+	}
+
+	var r0 *lnclient.PayOfferResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, string) (*lnclient.PayOfferResponse, error)); ok {
+		return rf(ctx, offer, amount, payerNote)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, string) *lnclient.PayOfferResponse); ok {
+		r0 = rf(ctx, offer, amount, payerNote)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.PayOfferResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, string) error); ok {
+		r1 = rf(ctx, offer, amount, payerNote)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RedeemOnchainFunds provides a mock function with given fields: ctx, toAddress, amount, feeRate, sendAll
 func (_m *LNClient) RedeemOnchainFunds(ctx context.Context, toAddress string, amount uint64, feeRate *uint64, sendAll bool) (string, error) {
 	ret := _m.Called(ctx, toAddress, amount, feeRate, sendAll)
@@ -776,9 +1136,9 @@ func (_m *LNClient) SendCustomMessage(ctx context.Context, peerPubkey string, ms
 	return r0
 }
 
-// SendKeysend provides a mock function with given fields: amount, destination, customRecords, preimage
-func (_m *LNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string) (*lnclient.PayKeysendResponse, error) {
-	ret := _m.Called(amount, destination, customRecords, preimage)
+// SendKeysend provides a mock function with given fields: amount, destination, customRecords, preimage, feeLimitMloki
+func (_m *LNClient) SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, feeLimitMloki uint64) (*lnclient.PayKeysendResponse, error) {
+	ret := _m.Called(amount, destination, customRecords, preimage, feeLimitMloki)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendKeysend")
@@ -786,19 +1146,19 @@ func (_m *LNClient) SendKeysend(amount uint64, destination string, customRecords
 
 	var r0 *lnclient.PayKeysendResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string) (*lnclient.PayKeysendResponse, error)); ok {
-		return rf(amount, destination, customRecords, preimage)
+	if rf, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string, uint64) (*lnclient.PayKeysendResponse, error)); ok {
+		return rf(amount, destination, customRecords, preimage, feeLimitMloki)
 	}
-	if rf, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string) *lnclient.PayKeysendResponse); ok {
-		r0 = rf(amount, destination, customRecords, preimage)
+	if rf, ok := ret.Get(0).(func(uint64, string, []lnclient.TLVRecord, string, uint64) *lnclient.PayKeysendResponse); ok {
+		r0 = rf(amount, destination, customRecords, preimage, feeLimitMloki)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*lnclient.PayKeysendResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64, string, []lnclient.TLVRecord, string) error); ok {
-		r1 = rf(amount, destination, customRecords, preimage)
+	if rf, ok := ret.Get(1).(func(uint64, string, []lnclient.TLVRecord, string, uint64) error); ok {
+		r1 = rf(amount, destination, customRecords, preimage, feeLimitMloki)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -807,26 +1167,38 @@ func (_m *LNClient) SendKeysend(amount uint64, destination string, customRecords
 }
 
 // SendPaymentProbes provides a mock function with given fields: ctx, invoice
-func (_m *LNClient) SendPaymentProbes(ctx context.Context, invoice string) error {
+func (_m *LNClient) SendPaymentProbes(ctx context.Context, invoice string) (*lnclient.ProbeResult, error) {
 	ret := _m.Called(ctx, invoice)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendPaymentProbes")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+	var r0 *lnclient.ProbeResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*lnclient.ProbeResult, error)); ok {
+		return rf(ctx, invoice)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *lnclient.ProbeResult); ok {
 		r0 = rf(ctx, invoice)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.ProbeResult)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, invoice)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// SendPaymentSync provides a mock function with given fields: payReq, amount
-func (_m *LNClient) SendPaymentSync(payReq string, amount *uint64) (*lnclient.PayInvoiceResponse, error) {
-	ret := _m.Called(payReq, amount)
+// SendPaymentSync provides a mock function with given fields: payReq, amount, feeLimitMloki
+func (_m *LNClient) SendPaymentSync(payReq string, amount *uint64, feeLimitMloki uint64) (*lnclient.PayInvoiceResponse, error) {
+	ret := _m.Called(payReq, amount, feeLimitMloki)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendPaymentSync")
@@ -834,19 +1206,19 @@ func (_m *LNClient) SendPaymentSync(payReq string, amount *uint64) (*lnclient.Pa
 
 	var r0 *lnclient.PayInvoiceResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, *uint64) (*lnclient.PayInvoiceResponse, error)); ok {
-		return rf(payReq, amount)
+	if rf, ok := ret.Get(0).(func(string, *uint64, uint64) (*lnclient.PayInvoiceResponse, error)); ok {
+		return rf(payReq, amount, feeLimitMloki)
 	}
-	if rf, ok := ret.Get(0).(func(string, *uint64) *lnclient.PayInvoiceResponse); ok {
-		r0 = rf(payReq, amount)
+	if rf, ok := ret.Get(0).(func(string, *uint64, uint64) *lnclient.PayInvoiceResponse); ok {
+		r0 = rf(payReq, amount, feeLimitMloki)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*lnclient.PayInvoiceResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, *uint64) error); ok {
-		r1 = rf(payReq, amount)
+	if rf, ok := ret.Get(1).(func(string, *uint64, uint64) error); ok {
+		r1 = rf(payReq, amount, feeLimitMloki)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -855,21 +1227,33 @@ func (_m *LNClient) SendPaymentSync(payReq string, amount *uint64) (*lnclient.Pa
 }
 
 // SendSpontaneousPaymentProbes provides a mock function with given fields: ctx, amountMloki, nodeId
-func (_m *LNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) error {
+func (_m *LNClient) SendSpontaneousPaymentProbes(ctx context.Context, amountMloki uint64, nodeId string) (*lnclient.ProbeResult, error) {
 	ret := _m.Called(ctx, amountMloki, nodeId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendSpontaneousPaymentProbes")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+	var r0 *lnclient.ProbeResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) (*lnclient.ProbeResult, error)); ok {
+		return rf(ctx, amountMloki, nodeId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) *lnclient.ProbeResult); ok {
 		r0 = rf(ctx, amountMloki, nodeId)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lnclient.ProbeResult)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string) error); ok {
+		r1 = rf(ctx, amountMloki, nodeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // SetNodeAlias provides a mock function with given fields: ctx, alias
@@ -955,7 +1339,7 @@ func (_m *LNClient) SignMessage(ctx context.Context, message string) (string, er
 }
 
 // SubscribeChannelAcceptor provides a mock function with given fields: ctx
-func (_m *LNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error) {
+func (_m *LNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
@@ -963,9 +1347,9 @@ func (_m *LNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclie
 	}
 
 	var r0 <-chan lnclient.ChannelAcceptRequest
-	var r1 func(string, bool, bool) error
+	var r1 func(string, bool, bool, string) error
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool) error, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan lnclient.ChannelAcceptRequest, func(string, bool, bool, string) error, error)); ok {
 		return rf(ctx)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context) <-chan lnclient.ChannelAcceptRequest); ok {
@@ -976,11 +1360,11 @@ func (_m *LNClient) SubscribeChannelAcceptor(ctx context.Context) (<-chan lnclie
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) func(string, bool, bool) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context) func(string, bool, bool, string) error); ok {
 		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(func(string, bool, bool) error)
+			r1 = ret.Get(1).(func(string, bool, bool, string) error)
 		}
 	}
 
@@ -0,0 +1,492 @@
+// Package totp implements an optional TOTP (RFC 6238) second factor for the
+// admin API. Once enrolled, a code from an authenticator app, or one of the
+// single-use recovery codes, is required on top of the unlock password for
+// full access logins and for actions that expose or move funds.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/logger"
+)
+
+const (
+	issuer = "Lokihub"
+	digits = 6
+	period = 30
+	// skew is how many periods before and after the current one are accepted,
+	// to allow for clock drift between the hub and the authenticator
+	skew = 1
+
+	recoveryCodeCount = 10
+
+	// maxFailedAttempts wrong codes in a row lock the factor for
+	// lockoutDuration, doubling with every further wrong code up to
+	// maxLockoutDuration
+	maxFailedAttempts  = 5
+	lockoutDuration    = 30 * time.Second
+	maxLockoutDuration = time.Hour
+)
+
+var (
+	ErrTotpRequired       = errors.New("a TOTP code is required")
+	ErrInvalidTotpCode    = errors.New("invalid TOTP code")
+	ErrTotpNotEnabled     = errors.New("TOTP is not enabled")
+	ErrTotpAlreadyEnabled = errors.New("TOTP is already enabled")
+	ErrInvalidPassword    = errors.New("invalid unlock password")
+	ErrTotpLocked         = errors.New("too many invalid TOTP codes")
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type Status struct {
+	Enabled bool
+	// Pending is set between BeginEnrollment and ConfirmEnrollment
+	Pending               bool
+	PaymentThresholdMloki *uint64
+	RecoveryCodesLeft     int
+}
+
+type TotpService interface {
+	GetStatus() (*Status, error)
+	// BeginEnrollment generates a new secret, stores it encrypted with the
+	// unlock password and returns it with an otpauth:// URL for authenticator
+	// apps. TOTP is not required until the enrollment is confirmed.
+	BeginEnrollment(unlockPassword string) (secret string, otpauthUrl string, err error)
+	// ConfirmEnrollment enables TOTP once a code for the pending secret has
+	// been verified and returns the recovery codes, which are not
+	// retrievable afterwards.
+	ConfirmEnrollment(code string) ([]string, error)
+	Disable(unlockPassword string, code string) error
+	// RegenerateRecoveryCodes replaces all recovery codes with new ones.
+	RegenerateRecoveryCodes(code string) ([]string, error)
+	// SetPaymentThreshold sets the amount above which payments require a
+	// code. nil requires a code for every payment.
+	SetPaymentThreshold(thresholdMloki *uint64, code string) error
+	// Verify checks a TOTP or recovery code, if TOTP is enabled. Codes cannot
+	// be used twice, and too many wrong codes in a row lock the factor for a
+	// while. The unlock password is only needed if the secret has not been
+	// loaded yet, and may be empty otherwise.
+	Verify(code string, unlockPassword string) error
+	// RequiresCodeForPayment returns whether a payment of the given amount
+	// must be confirmed with a code.
+	RequiresCodeForPayment(amountMloki uint64) (bool, error)
+}
+
+type totpService struct {
+	db  *gorm.DB
+	cfg config.Config
+}
+
+func NewTotpService(db *gorm.DB, cfg config.Config) *totpService {
+	return &totpService{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func (svc *totpService) getFactor() (*db.TotpFactor, error) {
+	var factor db.TotpFactor
+	err := svc.db.Limit(1).Find(&factor).Error
+	if err != nil {
+		return nil, err
+	}
+	if factor.ID == 0 {
+		return nil, nil
+	}
+	return &factor, nil
+}
+
+func (svc *totpService) getEnabledFactor() (*db.TotpFactor, error) {
+	factor, err := svc.getFactor()
+	if err != nil {
+		return nil, err
+	}
+	if factor == nil || factor.ConfirmedAt == nil {
+		return nil, ErrTotpNotEnabled
+	}
+	return factor, nil
+}
+
+func (svc *totpService) GetStatus() (*Status, error) {
+	factor, err := svc.getFactor()
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{}
+	if factor == nil {
+		return status, nil
+	}
+	status.Enabled = factor.ConfirmedAt != nil
+	status.Pending = factor.ConfirmedAt == nil
+	status.PaymentThresholdMloki = factor.PaymentThresholdMloki
+
+	var recoveryCodesLeft int64
+	if err := svc.db.Model(&db.TotpRecoveryCode{}).Where("used_at IS NULL").Count(&recoveryCodesLeft).Error; err != nil {
+		return nil, err
+	}
+	status.RecoveryCodesLeft = int(recoveryCodesLeft)
+	return status, nil
+}
+
+func (svc *totpService) BeginEnrollment(unlockPassword string) (string, string, error) {
+	if !svc.cfg.CheckUnlockPassword(unlockPassword) {
+		return "", "", ErrInvalidPassword
+	}
+	factor, err := svc.getFactor()
+	if err != nil {
+		return "", "", err
+	}
+	if factor != nil && factor.ConfirmedAt != nil {
+		return "", "", ErrTotpAlreadyEnabled
+	}
+
+	secretBytes := make([]byte, 20)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+	secret := base32NoPadding.EncodeToString(secretBytes)
+
+	if err := svc.cfg.SetTOTPSecret(secret, unlockPassword); err != nil {
+		return "", "", err
+	}
+	if factor == nil {
+		if err := svc.db.Create(&db.TotpFactor{}).Error; err != nil {
+			return "", "", err
+		}
+	}
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(period))
+	otpauthUrl := fmt.Sprintf("otpauth://totp/%s?%s", url.PathEscape(issuer), query.Encode())
+
+	logger.Logger.Info().Msg("Started TOTP enrollment")
+	return secret, otpauthUrl, nil
+}
+
+func (svc *totpService) ConfirmEnrollment(code string) ([]string, error) {
+	factor, err := svc.getFactor()
+	if err != nil {
+		return nil, err
+	}
+	if factor == nil {
+		return nil, fmt.Errorf("%w: no TOTP enrollment has been started", constants.ErrInvalidParams)
+	}
+	if factor.ConfirmedAt != nil {
+		return nil, ErrTotpAlreadyEnabled
+	}
+
+	if err := checkLockout(factor); err != nil {
+		return nil, err
+	}
+	step, ok := matchCode(svc.cfg.GetTOTPSecret(), normalizeCode(code), time.Now())
+	if !ok {
+		return nil, svc.recordFailure(factor)
+	}
+
+	var recoveryCodes []string
+	err = svc.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(factor).Updates(map[string]interface{}{
+			"confirmed_at":    now,
+			"last_used_step":  step,
+			"failed_attempts": 0,
+			"locked_until":    nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+		recoveryCodes, err = replaceRecoveryCodes(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info().Msg("Enabled TOTP")
+	return recoveryCodes, nil
+}
+
+func (svc *totpService) Disable(unlockPassword string, code string) error {
+	if !svc.cfg.CheckUnlockPassword(unlockPassword) {
+		return ErrInvalidPassword
+	}
+	if _, err := svc.getEnabledFactor(); err != nil {
+		return err
+	}
+	if err := svc.Verify(code, unlockPassword); err != nil {
+		return err
+	}
+
+	err := svc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&db.TotpRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("1 = 1").Delete(&db.TotpFactor{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	if err := svc.cfg.SetTOTPSecret("", unlockPassword); err != nil {
+		return err
+	}
+
+	logger.Logger.Info().Msg("Disabled TOTP")
+	return nil
+}
+
+func (svc *totpService) RegenerateRecoveryCodes(code string) ([]string, error) {
+	if _, err := svc.getEnabledFactor(); err != nil {
+		return nil, err
+	}
+	if err := svc.Verify(code, ""); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	err := svc.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		recoveryCodes, err = replaceRecoveryCodes(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info().Msg("Regenerated TOTP recovery codes")
+	return recoveryCodes, nil
+}
+
+func (svc *totpService) SetPaymentThreshold(thresholdMloki *uint64, code string) error {
+	factor, err := svc.getEnabledFactor()
+	if err != nil {
+		return err
+	}
+	if err := svc.Verify(code, ""); err != nil {
+		return err
+	}
+	return svc.db.Model(factor).Update("payment_threshold_mloki", thresholdMloki).Error
+}
+
+func (svc *totpService) Verify(code string, unlockPassword string) error {
+	factor, err := svc.getFactor()
+	if err != nil {
+		return err
+	}
+	if factor == nil || factor.ConfirmedAt == nil {
+		return nil
+	}
+
+	code = normalizeCode(code)
+	if code == "" {
+		return ErrTotpRequired
+	}
+	if err := checkLockout(factor); err != nil {
+		return err
+	}
+
+	if len(code) != digits {
+		// anything that is not a TOTP code is tried as a recovery code
+		hash := hashRecoveryCode(code)
+		result := svc.db.Model(&db.TotpRecoveryCode{}).
+			Where("code_hash = ? AND used_at IS NULL", hash).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return svc.recordFailure(factor)
+		}
+		logger.Logger.Warn().Msg("Used a TOTP recovery code")
+		return svc.resetFailures(factor)
+	}
+
+	secret := svc.cfg.GetTOTPSecret()
+	if secret == "" && unlockPassword != "" {
+		secret, err = svc.cfg.Get(config.TOTPSecretKey, unlockPassword)
+		if err != nil {
+			return err
+		}
+	}
+	if secret == "" {
+		return errors.New("TOTP secret is not available until the hub is unlocked")
+	}
+
+	step, ok := matchCode(secret, code, time.Now())
+	if !ok {
+		return svc.recordFailure(factor)
+	}
+	// a code is only accepted once, even within its period
+	result := svc.db.Model(&db.TotpFactor{}).
+		Where("id = ? AND last_used_step < ?", factor.ID, step).
+		Updates(map[string]interface{}{
+			"last_used_step":  step,
+			"failed_attempts": 0,
+			"locked_until":    nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return svc.recordFailure(factor)
+	}
+	return nil
+}
+
+// checkLockout refuses any code while the factor is locked
+func checkLockout(factor *db.TotpFactor) error {
+	if factor.LockedUntil != nil && time.Now().Before(*factor.LockedUntil) {
+		return fmt.Errorf("%w, try again after %s", ErrTotpLocked, factor.LockedUntil.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// recordFailure counts a wrong code and locks the factor once there were too
+// many in a row. It returns the error to report for the wrong code.
+func (svc *totpService) recordFailure(factor *db.TotpFactor) error {
+	err := svc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&db.TotpFactor{}).Where("id = ?", factor.ID).
+			Update("failed_attempts", gorm.Expr("failed_attempts + 1")).Error; err != nil {
+			return err
+		}
+		if err := tx.First(factor, factor.ID).Error; err != nil {
+			return err
+		}
+		if factor.FailedAttempts < maxFailedAttempts {
+			return nil
+		}
+		lockedUntil := time.Now().Add(lockoutBackoff(factor.FailedAttempts))
+		factor.LockedUntil = &lockedUntil
+		return tx.Model(factor).Update("locked_until", lockedUntil).Error
+	})
+	if err != nil {
+		return err
+	}
+	if factor.FailedAttempts >= maxFailedAttempts {
+		logger.Logger.Warn().
+			Uint("failed_attempts", factor.FailedAttempts).
+			Time("locked_until", *factor.LockedUntil).
+			Msg("Locked TOTP after too many invalid codes")
+	}
+	return ErrInvalidTotpCode
+}
+
+func (svc *totpService) resetFailures(factor *db.TotpFactor) error {
+	if factor.FailedAttempts == 0 && factor.LockedUntil == nil {
+		return nil
+	}
+	return svc.db.Model(&db.TotpFactor{}).Where("id = ?", factor.ID).Updates(map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_until":    nil,
+	}).Error
+}
+
+// lockoutBackoff returns how long the factor is locked after the given number
+// of wrong codes in a row
+func lockoutBackoff(failedAttempts uint) time.Duration {
+	backoff := lockoutDuration
+	for i := uint(maxFailedAttempts); i < failedAttempts && backoff < maxLockoutDuration; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxLockoutDuration)
+}
+
+func (svc *totpService) RequiresCodeForPayment(amountMloki uint64) (bool, error) {
+	factor, err := svc.getFactor()
+	if err != nil {
+		return false, err
+	}
+	if factor == nil || factor.ConfirmedAt == nil {
+		return false, nil
+	}
+	return factor.PaymentThresholdMloki == nil || amountMloki > *factor.PaymentThresholdMloki, nil
+}
+
+// GenerateCode returns the code for the given secret at time t.
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+	return generateCode(key, uint64(t.Unix())/period), nil
+}
+
+func generateCode(key []byte, step uint64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], step)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, value%1_000_000)
+}
+
+// matchCode returns the time step the code is valid for, if any
+func matchCode(secret string, code string, now time.Time) (uint64, bool) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(key) == 0 || len(code) != digits {
+		return 0, false
+	}
+	current := uint64(now.Unix()) / period
+	for i := -skew; i <= skew; i++ {
+		step := uint64(int64(current) + int64(i))
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func normalizeCode(code string) string {
+	code = strings.ReplaceAll(code, " ", "")
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+func hashRecoveryCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+func replaceRecoveryCodes(tx *gorm.DB) ([]string, error) {
+	if err := tx.Where("1 = 1").Delete(&db.TotpRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		randomBytes := make([]byte, 5)
+		if _, err := rand.Read(randomBytes); err != nil {
+			return nil, err
+		}
+		encoded := strings.ToLower(base32NoPadding.EncodeToString(randomBytes))
+		if err := tx.Create(&db.TotpRecoveryCode{CodeHash: hashRecoveryCode(encoded)}).Error; err != nil {
+			return nil, err
+		}
+		recoveryCodes = append(recoveryCodes, encoded[:4]+"-"+encoded[4:])
+	}
+	return recoveryCodes, nil
+}
@@ -0,0 +1,182 @@
+package totp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+const unlockPassword = "123"
+
+func TestGenerateCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+	testCases := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range testCases {
+		code, err := GenerateCode(secret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, code, tc.unix)
+	}
+}
+
+func TestEnrollment(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	require.NoError(t, svc.Cfg.SaveUnlockPasswordCheck(unlockPassword))
+
+	totpSvc := NewTotpService(svc.DB, svc.Cfg)
+
+	// nothing is required before enrollment is confirmed
+	assert.NoError(t, totpSvc.Verify("", ""))
+	_, _, err = totpSvc.BeginEnrollment("wrong")
+	assert.ErrorIs(t, err, ErrInvalidPassword)
+	secret, otpauthUrl, err := totpSvc.BeginEnrollment(unlockPassword)
+	require.NoError(t, err)
+	assert.Contains(t, otpauthUrl, "secret="+secret)
+	assert.NoError(t, totpSvc.Verify("", ""))
+
+	// the secret is stored encrypted with the unlock password
+	storedSecret, err := svc.Cfg.Get(config.TOTPSecretKey, unlockPassword)
+	require.NoError(t, err)
+	assert.Equal(t, secret, storedSecret)
+
+	_, err = totpSvc.ConfirmEnrollment("000000")
+	assert.ErrorIs(t, err, ErrInvalidTotpCode)
+	code, err := GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	recoveryCodes, err := totpSvc.ConfirmEnrollment(code)
+	require.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodeCount)
+
+	status, err := totpSvc.GetStatus()
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, recoveryCodeCount, status.RecoveryCodesLeft)
+
+	assert.ErrorIs(t, totpSvc.Verify("", ""), ErrTotpRequired)
+	// the code used to confirm cannot be used again
+	assert.ErrorIs(t, totpSvc.Verify(code, ""), ErrInvalidTotpCode)
+	nextCode, err := GenerateCode(secret, time.Now().Add(period*time.Second))
+	require.NoError(t, err)
+	assert.NoError(t, totpSvc.Verify(nextCode, ""))
+
+	// recovery codes are single-use
+	assert.NoError(t, totpSvc.Verify(recoveryCodes[0], ""))
+	assert.ErrorIs(t, totpSvc.Verify(recoveryCodes[0], ""), ErrInvalidTotpCode)
+	status, err = totpSvc.GetStatus()
+	require.NoError(t, err)
+	assert.Equal(t, recoveryCodeCount-1, status.RecoveryCodesLeft)
+
+	_, _, err = totpSvc.BeginEnrollment(unlockPassword)
+	assert.ErrorIs(t, err, ErrTotpAlreadyEnabled)
+
+	assert.ErrorIs(t, totpSvc.Disable("wrong", recoveryCodes[1]), ErrInvalidPassword)
+	require.NoError(t, totpSvc.Disable(unlockPassword, recoveryCodes[1]))
+	assert.NoError(t, totpSvc.Verify("", ""))
+	assert.Empty(t, svc.Cfg.GetTOTPSecret())
+}
+
+func TestRequiresCodeForPayment(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	require.NoError(t, svc.Cfg.SaveUnlockPasswordCheck(unlockPassword))
+
+	totpSvc := NewTotpService(svc.DB, svc.Cfg)
+
+	required, err := totpSvc.RequiresCodeForPayment(1_000_000)
+	require.NoError(t, err)
+	assert.False(t, required)
+
+	_, _, err = totpSvc.BeginEnrollment(unlockPassword)
+	require.NoError(t, err)
+	recoveryCodes, err := totpSvc.RegenerateRecoveryCodes("")
+	assert.ErrorIs(t, err, ErrTotpNotEnabled)
+	assert.Nil(t, recoveryCodes)
+	code, err := GenerateCode(svc.Cfg.GetTOTPSecret(), time.Now())
+	require.NoError(t, err)
+	recoveryCodes, err = totpSvc.ConfirmEnrollment(code)
+	require.NoError(t, err)
+
+	// without a threshold every payment requires a code
+	required, err = totpSvc.RequiresCodeForPayment(1)
+	require.NoError(t, err)
+	assert.True(t, required)
+
+	threshold := uint64(100_000)
+	assert.ErrorIs(t, totpSvc.SetPaymentThreshold(&threshold, ""), ErrTotpRequired)
+	require.NoError(t, totpSvc.SetPaymentThreshold(&threshold, recoveryCodes[0]))
+
+	required, err = totpSvc.RequiresCodeForPayment(threshold)
+	require.NoError(t, err)
+	assert.False(t, required)
+	required, err = totpSvc.RequiresCodeForPayment(threshold + 1)
+	require.NoError(t, err)
+	assert.True(t, required)
+}
+
+func TestVerify_Lockout(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+	require.NoError(t, svc.Cfg.SaveUnlockPasswordCheck(unlockPassword))
+
+	totpSvc := NewTotpService(svc.DB, svc.Cfg)
+	secret, _, err := totpSvc.BeginEnrollment(unlockPassword)
+	require.NoError(t, err)
+	code, err := GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	recoveryCodes, err := totpSvc.ConfirmEnrollment(code)
+	require.NoError(t, err)
+
+	// a valid code resets the count of wrong ones
+	for range maxFailedAttempts - 1 {
+		assert.ErrorIs(t, totpSvc.Verify("wrong-code", ""), ErrInvalidTotpCode)
+	}
+	require.NoError(t, totpSvc.Verify(recoveryCodes[0], ""))
+	for range maxFailedAttempts {
+		assert.ErrorIs(t, totpSvc.Verify("wrong-code", ""), ErrInvalidTotpCode)
+	}
+
+	// even valid codes are refused while locked
+	assert.ErrorIs(t, totpSvc.Verify(recoveryCodes[1], ""), ErrTotpLocked)
+	factor, err := totpSvc.getFactor()
+	require.NoError(t, err)
+	assert.Equal(t, uint(maxFailedAttempts), factor.FailedAttempts)
+	require.NotNil(t, factor.LockedUntil)
+	assert.WithinDuration(t, time.Now().Add(lockoutDuration), *factor.LockedUntil, 5*time.Second)
+
+	// once the lock expires the next wrong code locks it for longer
+	require.NoError(t, svc.DB.Model(factor).Update("locked_until", time.Now().Add(-time.Second)).Error)
+	assert.ErrorIs(t, totpSvc.Verify("wrong-code", ""), ErrInvalidTotpCode)
+	factor, err = totpSvc.getFactor()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*lockoutDuration), *factor.LockedUntil, 5*time.Second)
+
+	require.NoError(t, svc.DB.Model(factor).Update("locked_until", time.Now().Add(-time.Second)).Error)
+	require.NoError(t, totpSvc.Verify(recoveryCodes[1], ""))
+	factor, err = totpSvc.getFactor()
+	require.NoError(t, err)
+	assert.Zero(t, factor.FailedAttempts)
+	assert.Nil(t, factor.LockedUntil)
+}
+
+func TestLockoutBackoff(t *testing.T) {
+	assert.Equal(t, lockoutDuration, lockoutBackoff(maxFailedAttempts))
+	assert.Equal(t, 4*lockoutDuration, lockoutBackoff(maxFailedAttempts+2))
+	assert.Equal(t, maxLockoutDuration, lockoutBackoff(maxFailedAttempts+100))
+}
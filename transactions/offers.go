@@ -0,0 +1,333 @@
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/lnclient"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// BOLT12 offers are bech32-encoded with the "lno" human-readable part.
+const offerPrefix = "lno1"
+
+// bech32Charset is the bech32 alphabet. BOLT12 strings use it without a
+// checksum.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// TLV types of the offer fields read by OfferAmountMloki
+const (
+	offerCurrencyType = 6
+	offerAmountType   = 8
+)
+
+type Offer = db.Offer
+
+func validateOffer(offer string) (string, error) {
+	offer = strings.ToLower(strings.TrimSpace(offer))
+	if !strings.HasPrefix(offer, offerPrefix) {
+		return "", errors.New("invalid BOLT12 offer")
+	}
+	return offer, nil
+}
+
+// OfferAmountMloki returns the amount a BOLT12 offer asks for, or 0 if the
+// payer chooses the amount.
+func OfferAmountMloki(offer string) (uint64, error) {
+	offer, err := validateOffer(offer)
+	if err != nil {
+		return 0, err
+	}
+	tlvStream, err := decodeBolt12(offer[len(offerPrefix):])
+	if err != nil {
+		return 0, err
+	}
+
+	var amountMloki uint64
+	for len(tlvStream) > 0 {
+		tlvType, n := readBigSize(tlvStream)
+		if n == 0 {
+			return 0, errors.New("invalid BOLT12 offer")
+		}
+		tlvStream = tlvStream[n:]
+		length, n := readBigSize(tlvStream)
+		if n == 0 || uint64(len(tlvStream)-n) < length {
+			return 0, errors.New("invalid BOLT12 offer")
+		}
+		value := tlvStream[n : n+int(length)] //nolint:gosec // bounded by len(tlvStream) above
+		tlvStream = tlvStream[n+int(length):] //nolint:gosec // bounded by len(tlvStream) above
+
+		switch tlvType {
+		case offerCurrencyType:
+			return 0, errors.New("offers with a fiat currency amount are not supported")
+		case offerAmountType:
+			if len(value) > 8 {
+				return 0, errors.New("invalid BOLT12 offer amount")
+			}
+			for _, b := range value {
+				amountMloki = amountMloki<<8 | uint64(b)
+			}
+		}
+	}
+	return amountMloki, nil
+}
+
+// decodeBolt12 decodes the data part of a BOLT12 string. Long strings may be
+// split with "+" and whitespace.
+func decodeBolt12(data string) ([]byte, error) {
+	var acc, bits uint
+	decoded := make([]byte, 0, len(data)*5/8)
+	for _, c := range data {
+		if c == '+' || c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			continue
+		}
+		value := strings.IndexRune(bech32Charset, c)
+		if value < 0 {
+			return nil, errors.New("invalid BOLT12 offer")
+		}
+		acc = acc<<5 | uint(value)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			decoded = append(decoded, byte(acc>>bits))
+			acc &= 1<<bits - 1
+		}
+	}
+	return decoded, nil
+}
+
+// readBigSize reads a BOLT1 BigSize integer and returns it with the number of
+// bytes read, which is 0 if data is too short.
+func readBigSize(data []byte) (uint64, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	size := 1
+	switch data[0] {
+	case 0xfd:
+		size = 3
+	case 0xfe:
+		size = 5
+	case 0xff:
+		size = 9
+	default:
+		return uint64(data[0]), 1
+	}
+	if len(data) < size {
+		return 0, 0
+	}
+	var value uint64
+	for _, b := range data[1:size] {
+		value = value<<8 | uint64(b)
+	}
+	return value, size
+}
+
+func (svc *transactionsService) MakeOffer(ctx context.Context, description string, lnClient lnclient.LNClient, appId *uint) (*Offer, error) {
+	offerString, err := lnClient.MakeOffer(ctx, description)
+	if err != nil {
+		svc.logger.Error().Err(err).Str("description", description).Msg("Failed to make offer")
+		return nil, err
+	}
+
+	offer := db.Offer{
+		AppId:       appId,
+		Offer:       strings.ToLower(offerString),
+		Description: description,
+	}
+	if err := svc.db.Create(&offer).Error; err != nil {
+		svc.logger.Error().Err(err).Str("offer", offerString).Msg("Failed to create DB offer")
+		return nil, err
+	}
+
+	return &offer, nil
+}
+
+// LookupOffer finds an offer previously created with MakeOffer. Like
+// LookupTransaction, isolated apps can only see their own offers.
+func (svc *transactionsService) LookupOffer(ctx context.Context, offerString string, appId *uint) (*Offer, error) {
+	offerString, err := validateOffer(offerString)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := svc.db
+	if appId != nil {
+		var appKind string
+		err := svc.db.
+			Model(&db.App{}).
+			Where("id", *appId).
+			Pluck("kind", &appKind).
+			Error
+		if err != nil {
+			return nil, err
+		}
+		if db.IsIsolatedKind(appKind) {
+			tx = tx.Where("app_id = ?", *appId)
+		}
+	}
+
+	var offer db.Offer
+	result := tx.Limit(1).Find(&offer, &db.Offer{Offer: offerString})
+	if result.Error != nil {
+		svc.logger.Error().Err(result.Error).Msg("Failed to lookup offer")
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, NewNotFoundError()
+	}
+
+	return &offer, nil
+}
+
+// PayOffer pays a BOLT12 offer. The payment hash is only known once the node
+// has fetched an invoice from the offer, so the pending transaction is created
+// without one and it is filled in when the payment settles. Otherwise this
+// follows SendPaymentSync: the amount is reserved against the app's balance
+// and budget before the payment is attempted. An amount of 0 pays the amount
+// fixed by the offer.
+func (svc *transactionsService) PayOffer(ctx context.Context, offerString string, amountMloki uint64, payerNote string, metadata map[string]interface{}, lnClient lnclient.LNClient, appId *uint, requestEventId *uint) (*Transaction, error) {
+	offerString, err := validateOffer(offerString)
+	if err != nil {
+		return nil, err
+	}
+	offerAmountMloki, err := OfferAmountMloki(offerString)
+	if err != nil {
+		return nil, err
+	}
+	if amountMloki == 0 {
+		if offerAmountMloki == 0 {
+			return nil, errors.New("an amount is required to pay an offer without a fixed amount")
+		}
+		amountMloki = offerAmountMloki
+	}
+	if amountMloki < offerAmountMloki {
+		return nil, fmt.Errorf("the offer asks for at least %d mloki", offerAmountMloki)
+	}
+
+	var metadataBytes []byte
+	if metadata != nil {
+		metadataBytes, err = json.Marshal(metadata)
+		if err != nil {
+			svc.logger.Error().Err(err).Msg("Failed to serialize metadata")
+			return nil, err
+		}
+		if len(metadataBytes) > constants.INVOICE_METADATA_MAX_LENGTH {
+			return nil, fmt.Errorf("encoded payment metadata provided is too large. Limit: %d Received: %d", constants.INVOICE_METADATA_MAX_LENGTH, len(metadataBytes))
+		}
+	}
+
+	var dbTransaction db.Transaction
+	err = svc.db.Transaction(func(tx *gorm.DB) error {
+		if tx.Name() == "postgres" && appId != nil {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock($1)", int64(*appId)).Error; err != nil { //nolint:gosec // app IDs are small auto-increment DB primary keys
+				return fmt.Errorf("acquire payment lock: %w", err)
+			}
+		}
+
+		balance, parentKind, feeSkimMloki, feeReserveMloki, err := svc.validateCanPay(tx, appId, amountMloki, payerNote, "", false, validateCanPayExemptions{})
+		if err != nil {
+			return err
+		}
+		if err := enforceJITFullDrain(parentKind, balance, amountMloki, false, false); err != nil {
+			return err
+		}
+
+		dbTransaction = db.Transaction{
+			AppId:           appId,
+			RequestEventId:  requestEventId,
+			Type:            constants.TRANSACTION_TYPE_OUTGOING,
+			State:           constants.TRANSACTION_STATE_PENDING,
+			FeeReserveMloki: feeReserveMloki,
+			FeeSkimMloki:    feeSkimMloki,
+			AmountMloki:     amountMloki,
+			PaymentRequest:  offerString,
+			Description:     payerNote,
+			Metadata:        datatypes.JSON(metadataBytes),
+		}
+		return tx.Create(&dbTransaction).Error
+	})
+	if err != nil {
+		svc.logger.Error().Err(err).
+			Str("offer", offerString).
+			Msg("Failed to create DB transaction")
+		return nil, err
+	}
+
+	svc.logger.Debug().
+		Interface("app_id", appId).
+		Interface("request_event_id", requestEventId).
+		Uint64("amount", amountMloki).
+		Str("payer_note", payerNote).
+		Interface("metadata", metadata).
+		Msg("Initiating offer payment")
+
+	response, err := lnClient.PayOfferSync(ctx, offerString, amountMloki, payerNote)
+	if err != nil {
+		rpcErr := err
+		svc.logger.Error().Err(rpcErr).
+			Str("offer", offerString).
+			Msg("Failed to pay offer")
+
+		var failedTransaction *db.Transaction
+		dbErr := svc.db.Transaction(func(tx *gorm.DB) error {
+			var markErr error
+			failedTransaction, markErr = svc.markPaymentFailed(tx, &dbTransaction, rpcErr.Error())
+			return markErr
+		})
+		if dbErr != nil {
+			return nil, dbErr
+		}
+		if failedTransaction != nil {
+			return nil, rpcErr
+		}
+
+		// markPaymentFailed no-opped: the payment may already have been
+		// settled (see SendPaymentSync). The payment hash is only known once
+		// the offer is paid, so re-fetch the row itself.
+		var existing db.Transaction
+		if lookupErr := svc.db.Where(&db.Transaction{
+			ID:    dbTransaction.ID,
+			State: constants.TRANSACTION_STATE_SETTLED,
+		}).First(&existing).Error; lookupErr != nil {
+			return nil, rpcErr
+		}
+		return &existing, nil
+	}
+
+	var settledTransaction *db.Transaction
+	err = svc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&dbTransaction).Update("payment_hash", response.PaymentHash).Error; err != nil {
+			return err
+		}
+		dbTransaction.PaymentHash = response.PaymentHash
+		settledTransaction, err = svc.markTransactionSettled(tx, &dbTransaction, response.Preimage, response.Fee, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if settledTransaction != nil {
+		svc.publishSettleEvent(settledTransaction)
+	} else {
+		// another outgoing row was already settled with this payment hash
+		// (see markTransactionSettled); return that one.
+		var existing db.Transaction
+		if err := svc.db.Where(&db.Transaction{
+			Type:        constants.TRANSACTION_TYPE_OUTGOING,
+			PaymentHash: response.PaymentHash,
+			State:       constants.TRANSACTION_STATE_SETTLED,
+		}).First(&existing).Error; err != nil {
+			return nil, fmt.Errorf("payment settled but transaction not found: %w", err)
+		}
+		settledTransaction = &existing
+	}
+
+	return settledTransaction, nil
+}
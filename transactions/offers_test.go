@@ -0,0 +1,300 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func TestMakeOffer(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	offer, err := transactionsService.MakeOffer(context.TODO(), "coffee", svc.LNClient, &app.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tests.MockOffer, offer.Offer)
+	assert.Equal(t, "coffee", offer.Description)
+	assert.Equal(t, app.ID, *offer.AppId)
+
+	found, err := transactionsService.LookupOffer(context.TODO(), tests.MockOffer, &app.ID)
+	require.NoError(t, err)
+	assert.Equal(t, offer.ID, found.ID)
+}
+
+func TestLookupOffer_IsolatedAppCannotSeeOtherAppsOffers(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	otherApp, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	otherApp.Kind = db.AppKindIsolated
+	svc.DB.Save(&otherApp)
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	_, err = transactionsService.MakeOffer(context.TODO(), "coffee", svc.LNClient, &app.ID)
+	require.NoError(t, err)
+
+	offer, err := transactionsService.LookupOffer(context.TODO(), tests.MockOffer, &otherApp.ID)
+	assert.ErrorIs(t, err, NewNotFoundError())
+	assert.Nil(t, offer)
+}
+
+func TestLookupOffer_InvalidOffer(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	offer, err := transactionsService.LookupOffer(context.TODO(), tests.MockInvoice, nil)
+	assert.EqualError(t, err, "invalid BOLT12 offer")
+	assert.Nil(t, offer)
+}
+
+func TestPayOffer_NoApp(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.PayOffer(context.TODO(), tests.MockOffer, 123000, "thanks", nil, svc.LNClient, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(123000), transaction.AmountMloki)
+	assert.Equal(t, constants.TRANSACTION_TYPE_OUTGOING, transaction.Type)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, transaction.State)
+	assert.Equal(t, tests.MockOffer, transaction.PaymentRequest)
+	assert.Equal(t, tests.MockOfferPaymentHash, transaction.PaymentHash)
+	assert.Equal(t, "thanks", transaction.Description)
+	assert.Equal(t, uint64(1), transaction.FeeMloki)
+	assert.Zero(t, transaction.FeeReserveMloki)
+	assert.Equal(t, "123preimage", *transaction.Preimage)
+}
+
+// mockOfferWithoutAmount is tests.MockOffer without its offer_amount
+const mockOfferWithoutAmount = "lno1qgsqvgnwgcg35z6ee2h3yczraddm72xrfua9uve2rlrm9deu7xyfzrc2q33xcetgzcss9swjaymf7u4ckckxhav7p20gtpaeedqrlname53wuv795qcavtnd"
+
+func TestOfferAmountMloki(t *testing.T) {
+	amount, err := OfferAmountMloki(tests.MockOffer)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10_000), amount)
+
+	// long offers may be split with "+"
+	amount, err = OfferAmountMloki(tests.MockOffer[:40] + "+\n" + tests.MockOffer[40:])
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10_000), amount)
+
+	amount, err = OfferAmountMloki(mockOfferWithoutAmount)
+	require.NoError(t, err)
+	assert.Zero(t, amount)
+
+	_, err = OfferAmountMloki("lno1bad")
+	assert.Error(t, err)
+	_, err = OfferAmountMloki("lnbc1")
+	assert.Error(t, err)
+}
+
+func TestPayOffer_ZeroAmount(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.PayOffer(context.TODO(), mockOfferWithoutAmount, 0, "", nil, svc.LNClient, nil, nil)
+
+	assert.EqualError(t, err, "an amount is required to pay an offer without a fixed amount")
+	assert.Nil(t, transaction)
+
+	// the amount of a fixed-amount offer cannot be undercut
+	transaction, err = transactionsService.PayOffer(context.TODO(), tests.MockOffer, 9_999, "", nil, svc.LNClient, nil, nil)
+	assert.EqualError(t, err, "the offer asks for at least 10000 mloki")
+	assert.Nil(t, transaction)
+
+	// offers with a fixed amount are paid without one
+	transaction, err = transactionsService.PayOffer(context.TODO(), tests.MockOffer, 0, "", nil, svc.LNClient, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10_000), transaction.AmountMloki)
+}
+
+func TestPayOffer_IsolatedApp_BalanceInsufficient(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	app.Kind = db.AppKindIsolated
+	svc.DB.Save(&app)
+
+	err = svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error
+	require.NoError(t, err)
+
+	svc.DB.Create(&db.Transaction{
+		AppId:       &app.ID,
+		State:       constants.TRANSACTION_STATE_SETTLED,
+		Type:        constants.TRANSACTION_TYPE_INCOMING,
+		AmountMloki: 132000, // payment is 123000 mloki, but we also calculate fee reserves max of(10 loki or 1%)
+	})
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.PayOffer(context.TODO(), tests.MockOffer, 123000, "", nil, svc.LNClient, &app.ID, nil)
+
+	assert.ErrorIs(t, err, NewInsufficientBalanceError())
+	assert.Nil(t, transaction)
+
+	var count int64
+	svc.DB.Model(&db.Transaction{}).Where("type = ?", constants.TRANSACTION_TYPE_OUTGOING).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestPayOffer_IsolatedApp_BalanceSufficient(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+	app.Kind = db.AppKindIsolated
+	svc.DB.Save(&app)
+
+	err = svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error
+	require.NoError(t, err)
+
+	svc.DB.Create(&db.Transaction{
+		AppId:       &app.ID,
+		State:       constants.TRANSACTION_STATE_SETTLED,
+		Type:        constants.TRANSACTION_TYPE_INCOMING,
+		AmountMloki: 133000,
+	})
+
+	dbRequestEvent := &db.RequestEvent{}
+	err = svc.DB.Create(&dbRequestEvent).Error
+	require.NoError(t, err)
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.PayOffer(context.TODO(), tests.MockOffer, 123000, "", nil, svc.LNClient, &app.ID, &dbRequestEvent.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, transaction.State)
+	assert.Equal(t, app.ID, *transaction.AppId)
+	assert.Equal(t, dbRequestEvent.ID, *transaction.RequestEventId)
+}
+
+func TestPayOffer_BudgetExceeded(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	err = svc.DB.Create(&db.AppPermission{
+		AppId:         app.ID,
+		App:           *app,
+		Scope:         constants.PAY_INVOICE_SCOPE,
+		MaxAmountLoki: 133, // payment is 123 loki, but we also calculate fee reserves max of(10 loki or 1%)
+	}).Error
+	require.NoError(t, err)
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.PayOffer(context.TODO(), tests.MockOffer, 124000, "", nil, svc.LNClient, &app.ID, nil)
+
+	assert.ErrorIs(t, err, NewQuotaExceededError())
+	assert.Nil(t, transaction)
+}
+
+func TestPayOffer_Failed(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	svc.LNClient.(*tests.MockLn).PayOfferError = errors.New("no route")
+
+	mockEventConsumer := tests.NewMockEventConsumer()
+	svc.EventPublisher.RegisterSubscriber(mockEventConsumer)
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.PayOffer(context.TODO(), tests.MockOffer, 123000, "", nil, svc.LNClient, nil, nil)
+
+	assert.EqualError(t, err, "no route")
+	assert.Nil(t, transaction)
+
+	var failed db.Transaction
+	require.NoError(t, svc.DB.First(&failed, &db.Transaction{PaymentRequest: tests.MockOffer}).Error)
+	assert.Equal(t, constants.TRANSACTION_STATE_FAILED, failed.State)
+	assert.Equal(t, "no route", failed.FailureReason)
+	assert.Zero(t, failed.FeeReserveMloki)
+
+	require.Equal(t, 1, len(mockEventConsumer.GetConsumedEvents()))
+	assert.Equal(t, "nwc_payment_failed", mockEventConsumer.GetConsumedEvents()[0].Event)
+}
+
+func TestPayOffer_SettleRacesFailure_ReturnsSettled(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	delay := 100 * time.Millisecond
+	mockLn := svc.LNClient.(*tests.MockLn)
+	mockLn.PaymentDelay = &delay
+	mockLn.PayOfferError = errors.New("timeout talking to node")
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+
+	var wg sync.WaitGroup
+	var result *Transaction
+	var payErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result, payErr = transactionsService.PayOffer(context.TODO(), tests.MockOffer, 123000, "", nil, svc.LNClient, nil, nil)
+	}()
+
+	var pending db.Transaction
+	require.Eventually(t, func() bool {
+		return svc.DB.Where(&db.Transaction{
+			Type:           constants.TRANSACTION_TYPE_OUTGOING,
+			State:          constants.TRANSACTION_STATE_PENDING,
+			PaymentRequest: tests.MockOffer,
+		}).First(&pending).Error == nil
+	}, 2*time.Second, 2*time.Millisecond, "PENDING transaction row was never created")
+
+	// settled elsewhere while the RPC call is still in flight
+	preimage := "123preimage"
+	require.NoError(t, svc.DB.Model(&pending).Updates(&db.Transaction{
+		State:       constants.TRANSACTION_STATE_SETTLED,
+		Preimage:    &preimage,
+		PaymentHash: tests.MockOfferPaymentHash,
+	}).Error)
+
+	wg.Wait()
+
+	assert.NoError(t, payErr)
+	require.NotNil(t, result)
+	assert.Equal(t, pending.ID, result.ID)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, result.State)
+}
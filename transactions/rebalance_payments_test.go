@@ -0,0 +1,51 @@
+package transactions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/lnclient"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func TestConsumeEvent_IgnoresRebalancePayments(t *testing.T) {
+	ctx := context.TODO()
+
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+
+	paymentHash := "ae4277b7be3ca1420cafd24c143866190f52b996856b0e4164763f936e61ea1b"
+	require.NoError(t, svc.DB.Create(&db.Rebalance{
+		OutgoingChannelId: "1",
+		IncomingChannelId: "2",
+		AmountMloki:       1000,
+		PaymentHash:       paymentHash,
+		State:             constants.REBALANCE_STATE_PENDING,
+	}).Error)
+
+	tx := lnclient.Transaction{
+		Type:        "incoming",
+		Description: "Rebalance",
+		Preimage:    "9f59b18f80a77c2930deb8be5ff1143eacdd1891c63c23d61bc9f99c64e57325",
+		PaymentHash: paymentHash,
+		Amount:      1000,
+		SettledAt:   &tests.MockTimeUnix,
+	}
+	transactionsService.ConsumeEvent(ctx, &events.Event{
+		Event:      "nwc_lnclient_payment_received",
+		Properties: &tx,
+	}, map[string]interface{}{})
+
+	var count int64
+	require.NoError(t, svc.DB.Model(&db.Transaction{}).Where("payment_hash = ?", paymentHash).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,94 @@
+package transactions
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+)
+
+type spendingPolicyError struct {
+	reason string
+}
+
+// NewSpendingPolicyError returns the error for a payment rejected by the
+// paying app's db.AppSpendingPolicy. All spending policy errors match each
+// other with errors.Is, whatever their reason.
+func NewSpendingPolicyError(reason string) error {
+	return &spendingPolicyError{reason: reason}
+}
+
+func (err *spendingPolicyError) Error() string {
+	if err.reason == "" {
+		return "This payment is not allowed by your app's spending policy."
+	}
+	return "This payment is not allowed by your app's spending policy: " + err.reason
+}
+
+func (err *spendingPolicyError) Is(target error) bool {
+	_, ok := target.(*spendingPolicyError)
+	return ok
+}
+
+// spendingPolicyRules are the db.AppSpendingPolicy columns validateCanPay
+// loads alongside the app.
+type spendingPolicyRules struct {
+	MaxAmountPerPaymentLoki int
+	MaxPaymentsPerHour      int
+	MaxFeePpm               int
+	AllowedPubkeys          []string
+	DeniedPubkeys           []string
+}
+
+// checkSpendingPolicy enforces the per-payment rules of an app's spending
+// policy. destination is the paid node's pubkey, or empty when it cannot be
+// known (e.g. a BOLT12 offer behind a blinded path) — such payments are
+// refused when the app may only pay allowed pubkeys.
+func checkSpendingPolicy(tx *gorm.DB, appId uint, rules spendingPolicyRules, amountMloki uint64, destination string) error {
+	if rules.MaxAmountPerPaymentLoki > 0 && amountMloki > uint64(rules.MaxAmountPerPaymentLoki)*1000 { //nolint:gosec // MaxAmountPerPaymentLoki > 0 is checked above
+		return NewSpendingPolicyError(fmt.Sprintf("amount exceeds the maximum of %d loki per payment", rules.MaxAmountPerPaymentLoki))
+	}
+
+	if destination != "" && slices.Contains(rules.DeniedPubkeys, destination) {
+		return NewSpendingPolicyError("destination is denied")
+	}
+	if len(rules.AllowedPubkeys) > 0 {
+		if destination == "" {
+			return NewSpendingPolicyError("destination cannot be verified against the allowed destinations")
+		}
+		if !slices.Contains(rules.AllowedPubkeys, destination) {
+			return NewSpendingPolicyError("destination is not allowed")
+		}
+	}
+
+	if rules.MaxPaymentsPerHour > 0 {
+		var recentPayments int64
+		err := tx.Model(&db.Transaction{}).
+			Where("app_id = ? AND type = ? AND state IN ? AND created_at > ?",
+				appId, constants.TRANSACTION_TYPE_OUTGOING,
+				[]string{constants.TRANSACTION_STATE_PENDING, constants.TRANSACTION_STATE_SETTLED},
+				time.Now().Add(-time.Hour)).
+			Count(&recentPayments).Error
+		if err != nil {
+			return err
+		}
+		if recentPayments >= int64(rules.MaxPaymentsPerHour) {
+			return NewSpendingPolicyError(fmt.Sprintf("no more than %d payments per hour are allowed", rules.MaxPaymentsPerHour))
+		}
+	}
+
+	return nil
+}
+
+// capFeeReserveMloki lowers the fee reserve of a payment, which is also the
+// most the node may spend on routing it, to the policy's max fee rate.
+func capFeeReserveMloki(feeReserveMloki uint64, amountMloki uint64, maxFeePpm int) uint64 {
+	if maxFeePpm <= 0 {
+		return feeReserveMloki
+	}
+	return min(feeReserveMloki, amountMloki*uint64(maxFeePpm)/constants.PPM_DIVISOR)
+}
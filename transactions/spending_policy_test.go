@@ -0,0 +1,186 @@
+package transactions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+// payee of tests.MockInvoice
+const mockInvoicePayee = "03cbd788f5b22bd56e2714bff756372d2293504c064e03250ed16a4dd80ad70e2c"
+
+const otherNodePubkey = "02a3b1fbd2a6a3e0f0c45fdd8b0b8b5e0d5b2dd4a2b1c1ab1ad3c6f1e3e2d1c0b9"
+
+func createAppWithSpendingPolicy(t *testing.T, svc *tests.TestService, policy db.AppSpendingPolicy) *db.App {
+	app, _, err := tests.CreateApp(svc)
+	require.NoError(t, err)
+
+	err = svc.DB.Create(&db.AppPermission{
+		AppId: app.ID,
+		App:   *app,
+		Scope: constants.PAY_INVOICE_SCOPE,
+	}).Error
+	require.NoError(t, err)
+
+	policy.AppID = app.ID
+	err = svc.DB.Create(&policy).Error
+	require.NoError(t, err)
+
+	return app
+}
+
+func TestSendPaymentSync_SpendingPolicy_MaxAmountPerPayment(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	// the mock invoice is for 123 loki
+	app := createAppWithSpendingPolicy(t, svc, db.AppSpendingPolicy{MaxAmountPerPaymentLoki: 100})
+
+	mockEventConsumer := tests.NewMockEventConsumer()
+	svc.EventPublisher.RegisterSubscriber(mockEventConsumer)
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.SendPaymentSync(tests.MockInvoice, nil, nil, svc.LNClient, &app.ID, nil)
+
+	assert.ErrorIs(t, err, NewSpendingPolicyError(""))
+	assert.Contains(t, err.Error(), "maximum of 100 loki per payment")
+	assert.Nil(t, transaction)
+
+	require.Equal(t, 1, len(mockEventConsumer.GetConsumedEvents()))
+	assert.Equal(t, "nwc_permission_denied", mockEventConsumer.GetConsumedEvents()[0].Event)
+	assert.Equal(t, constants.ERROR_POLICY_VIOLATION, mockEventConsumer.GetConsumedEvents()[0].Properties.(map[string]interface{})["code"])
+
+	err = svc.DB.Model(&db.AppSpendingPolicy{}).Where("app_id = ?", app.ID).Update("max_amount_per_payment_loki", 123).Error
+	require.NoError(t, err)
+
+	transaction, err = transactionsService.SendPaymentSync(tests.MockInvoice, nil, nil, svc.LNClient, &app.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, transaction.State)
+}
+
+func TestSendPaymentSync_SpendingPolicy_DeniedPubkey(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app := createAppWithSpendingPolicy(t, svc, db.AppSpendingPolicy{DeniedPubkeys: []string{mockInvoicePayee}})
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.SendPaymentSync(tests.MockInvoice, nil, nil, svc.LNClient, &app.ID, nil)
+
+	assert.ErrorIs(t, err, NewSpendingPolicyError(""))
+	assert.Contains(t, err.Error(), "destination is denied")
+	assert.Nil(t, transaction)
+}
+
+func TestSendPaymentSync_SpendingPolicy_AllowedPubkeys(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app := createAppWithSpendingPolicy(t, svc, db.AppSpendingPolicy{AllowedPubkeys: []string{otherNodePubkey}})
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.SendPaymentSync(tests.MockInvoice, nil, nil, svc.LNClient, &app.ID, nil)
+
+	assert.ErrorIs(t, err, NewSpendingPolicyError(""))
+	assert.Contains(t, err.Error(), "destination is not allowed")
+	assert.Nil(t, transaction)
+
+	err = svc.DB.Model(&db.AppSpendingPolicy{}).Where("app_id = ?", app.ID).
+		Update("allowed_pubkeys", `["`+otherNodePubkey+`","`+mockInvoicePayee+`"]`).Error
+	require.NoError(t, err)
+
+	transaction, err = transactionsService.SendPaymentSync(tests.MockInvoice, nil, nil, svc.LNClient, &app.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, transaction.State)
+}
+
+func TestSendKeysend_SpendingPolicy_AllowedPubkeys(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app := createAppWithSpendingPolicy(t, svc, db.AppSpendingPolicy{AllowedPubkeys: []string{otherNodePubkey}})
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.SendKeysend(1000, mockInvoicePayee, nil, "", svc.LNClient, &app.ID, nil)
+	assert.ErrorIs(t, err, NewSpendingPolicyError(""))
+	assert.Nil(t, transaction)
+
+	transaction, err = transactionsService.SendKeysend(1000, otherNodePubkey, nil, "", svc.LNClient, &app.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, transaction.State)
+}
+
+func TestSendPaymentSync_SpendingPolicy_MaxPaymentsPerHour(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app := createAppWithSpendingPolicy(t, svc, db.AppSpendingPolicy{MaxPaymentsPerHour: 2})
+
+	// failed payments and payments older than an hour don't count
+	for _, transaction := range []db.Transaction{
+		{State: constants.TRANSACTION_STATE_SETTLED, CreatedAt: time.Now().Add(-2 * time.Hour)},
+		{State: constants.TRANSACTION_STATE_FAILED},
+		{State: constants.TRANSACTION_STATE_SETTLED},
+	} {
+		transaction.AppId = &app.ID
+		transaction.Type = constants.TRANSACTION_TYPE_OUTGOING
+		transaction.AmountMloki = 1000
+		require.NoError(t, svc.DB.Create(&transaction).Error)
+	}
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.SendKeysend(1000, otherNodePubkey, nil, "", svc.LNClient, &app.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, transaction.State)
+
+	transaction, err = transactionsService.SendKeysend(1000, otherNodePubkey, nil, "", svc.LNClient, &app.ID, nil)
+	assert.ErrorIs(t, err, NewSpendingPolicyError(""))
+	assert.Contains(t, err.Error(), "no more than 2 payments per hour")
+	assert.Nil(t, transaction)
+}
+
+func TestSendPaymentSync_SpendingPolicy_MaxFeePpm(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	app := createAppWithSpendingPolicy(t, svc, db.AppSpendingPolicy{MaxFeePpm: 10_000})
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	transaction, err := transactionsService.SendPaymentSync(tests.MockInvoice, nil, nil, svc.LNClient, &app.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, constants.TRANSACTION_STATE_SETTLED, transaction.State)
+
+	// 1% of 123000 mloki instead of the default 10000 mloki reserve
+	assert.Equal(t, uint64(1230), svc.LNClient.(*tests.MockLn).LastFeeLimitMloki)
+}
+
+func TestSendPaymentSync_NoSpendingPolicy_DefaultFeeLimit(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	transactionsService := NewTransactionsService(svc.DB, svc.EventPublisher)
+	_, err = transactionsService.SendPaymentSync(tests.MockInvoice, nil, nil, svc.LNClient, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, CalculateFeeReserveMloki(123000), svc.LNClient.(*tests.MockLn).LastFeeLimitMloki)
+}
+
+func TestCapFeeReserveMloki(t *testing.T) {
+	assert.Equal(t, uint64(10_000), capFeeReserveMloki(10_000, 123_000, 0))
+	assert.Equal(t, uint64(1_230), capFeeReserveMloki(10_000, 123_000, 10_000))
+	assert.Equal(t, uint64(10_000), capFeeReserveMloki(10_000, 123_000, constants.PPM_DIVISOR))
+	assert.Equal(t, uint64(0), capFeeReserveMloki(10_000, 10, 1))
+}
@@ -56,6 +56,13 @@ type TransactionsService interface {
 	ListTransactions(ctx context.Context, from, until, limit, offset uint64, unpaidOutgoing bool, unpaidIncoming bool, transactionType *string, lnClient lnclient.LNClient, appId *uint, forceFilterByAppId bool) (transactions []Transaction, totalCount uint64, err error)
 	SendPaymentSync(payReq string, amountMloki *uint64, metadata map[string]interface{}, lnClient lnclient.LNClient, appId *uint, requestEventId *uint) (*Transaction, error)
 	SendKeysend(amount uint64, destination string, customRecords []lnclient.TLVRecord, preimage string, lnClient lnclient.LNClient, appId *uint, requestEventId *uint) (*Transaction, error)
+	// CheckCanPay runs the balance, budget and spending policy checks a payment
+	// of amountMloki from the app would face, without making it. paymentHash is
+	// empty for keysend payments and destination is empty for offers.
+	CheckCanPay(amountMloki uint64, destination string, paymentHash string, lnClient lnclient.LNClient, appId *uint) error
+	MakeOffer(ctx context.Context, description string, lnClient lnclient.LNClient, appId *uint) (*Offer, error)
+	LookupOffer(ctx context.Context, offer string, appId *uint) (*Offer, error)
+	PayOffer(ctx context.Context, offer string, amountMloki uint64, payerNote string, metadata map[string]interface{}, lnClient lnclient.LNClient, appId *uint, requestEventId *uint) (*Transaction, error)
 	MakeHoldInvoice(ctx context.Context, amount uint64, description string, descriptionHash string, expiry uint64, paymentHash string, metadata map[string]interface{}, lnClient lnclient.LNClient, appId *uint, requestEventId *uint) (*Transaction, error)
 	SettleHoldInvoice(ctx context.Context, preimage string, lnClient lnclient.LNClient) (*Transaction, error)
 	CancelHoldInvoice(ctx context.Context, paymentHash string, lnClient lnclient.LNClient) error
@@ -404,17 +411,7 @@ func (svc *transactionsService) SendPaymentSync(payReq string, amountMloki *uint
 		return nil, errors.New("this invoice has expired")
 	}
 
-	selfPayment := false
-	if paymentRequest.Payee != "" && paymentRequest.Payee == lnClient.GetPubkey() {
-		var incomingTransaction db.Transaction
-		result := svc.db.Limit(1).Find(&incomingTransaction, &db.Transaction{
-			Type:        constants.TRANSACTION_TYPE_INCOMING,
-			PaymentHash: paymentRequest.PaymentHash,
-		})
-		if result.Error == nil && result.RowsAffected > 0 {
-			selfPayment = true
-		}
-	}
+	selfPayment := svc.isSelfPayment(paymentRequest.Payee, paymentRequest.PaymentHash, lnClient)
 
 	var dbTransaction db.Transaction
 
@@ -458,7 +455,7 @@ func (svc *transactionsService) SendPaymentSync(payReq string, amountMloki *uint
 		isInternalTransfer, _ := metadata["internal_transfer"].(bool)
 		isJITClaimSlice, _ := metadata["jit_claim_slice"].(bool)
 
-		balance, parentKind, feeSkimMloki, err := svc.validateCanPay(tx, appId, paymentAmount, paymentRequest.Description, selfPayment, validateCanPayExemptions{
+		balance, parentKind, feeSkimMloki, feeReserveMloki, err := svc.validateCanPay(tx, appId, paymentAmount, paymentRequest.Description, paymentRequest.Payee, selfPayment, validateCanPayExemptions{
 			SkipFeeReserve: isJITClaimSlice,
 			SkipBudgetCap:  isInternalTransfer,
 		})
@@ -481,7 +478,7 @@ func (svc *transactionsService) SendPaymentSync(payReq string, amountMloki *uint
 			RequestEventId:  requestEventId,
 			Type:            constants.TRANSACTION_TYPE_OUTGOING,
 			State:           constants.TRANSACTION_STATE_PENDING,
-			FeeReserveMloki: CalculateFeeReserveMloki(paymentAmount),
+			FeeReserveMloki: feeReserveMloki,
 			FeeSkimMloki:    feeSkimMloki,
 			AmountMloki:     paymentAmount,
 			PaymentRequest:  payReq,
@@ -517,7 +514,7 @@ func (svc *transactionsService) SendPaymentSync(payReq string, amountMloki *uint
 	if selfPayment {
 		response, err = svc.interceptSelfPayment(paymentRequest.PaymentHash, lnClient)
 	} else {
-		response, err = lnClient.SendPaymentSync(payReq, amountMloki)
+		response, err = lnClient.SendPaymentSync(payReq, amountMloki, dbTransaction.FeeReserveMloki)
 	}
 
 	if err != nil {
@@ -632,7 +629,7 @@ func (svc *transactionsService) SendKeysend(amount uint64, destination string, c
 			}
 		}
 
-		balance, parentKind, feeSkimMloki, err := svc.validateCanPay(tx, appId, amount, "", selfPayment, validateCanPayExemptions{})
+		balance, parentKind, feeSkimMloki, feeReserveMloki, err := svc.validateCanPay(tx, appId, amount, "", destination, selfPayment, validateCanPayExemptions{})
 		if err != nil {
 			return err
 		}
@@ -647,7 +644,7 @@ func (svc *transactionsService) SendKeysend(amount uint64, destination string, c
 			RequestEventId:  requestEventId,
 			Type:            constants.TRANSACTION_TYPE_OUTGOING,
 			State:           constants.TRANSACTION_STATE_PENDING,
-			FeeReserveMloki: CalculateFeeReserveMloki(uint64(amount)),
+			FeeReserveMloki: feeReserveMloki,
 			FeeSkimMloki:    feeSkimMloki,
 			AmountMloki:     amount,
 			Metadata:        datatypes.JSON(metadataBytes),
@@ -698,7 +695,7 @@ func (svc *transactionsService) SendKeysend(amount uint64, destination string, c
 			}
 		}
 	} else {
-		payKeysendResponse, err = lnClient.SendKeysend(amount, destination, customRecords, preimage)
+		payKeysendResponse, err = lnClient.SendKeysend(amount, destination, customRecords, preimage, dbTransaction.FeeReserveMloki)
 	}
 
 	if err != nil {
@@ -985,6 +982,10 @@ func (svc *transactionsService) ConsumeEvent(ctx context.Context, event *events.
 			svc.logger.Error().Interface("event", event).Msg("Failed to cast event")
 			return
 		}
+		if svc.isRebalancePayment(lnClientTransaction.PaymentHash) {
+			svc.logger.Debug().Str("payment_hash", lnClientTransaction.PaymentHash).Msg("Ignoring received rebalance payment")
+			return
+		}
 
 		var dbTransaction db.Transaction
 		var settledIncoming *db.Transaction
@@ -1088,6 +1089,10 @@ func (svc *transactionsService) ConsumeEvent(ctx context.Context, event *events.
 			svc.logger.Error().Interface("event", event).Msg("Failed to cast event")
 			return
 		}
+		if svc.isRebalancePayment(lnClientTransaction.PaymentHash) {
+			svc.logger.Debug().Str("payment_hash", lnClientTransaction.PaymentHash).Msg("Ignoring sent rebalance payment")
+			return
+		}
 
 		var dbTransaction db.Transaction
 		var settledOutgoing *db.Transaction
@@ -1179,6 +1184,21 @@ func (svc *transactionsService) ConsumeEvent(ctx context.Context, event *events.
 	}
 }
 
+// isRebalancePayment returns true for the circular payments of a channel
+// rebalance, which move the node's own funds and are tracked by the rebalance
+// service instead
+func (svc *transactionsService) isRebalancePayment(paymentHash string) bool {
+	if paymentHash == "" {
+		return false
+	}
+	var count int64
+	if err := svc.db.Model(&db.Rebalance{}).Where("payment_hash = ?", paymentHash).Count(&count).Error; err != nil {
+		svc.logger.Error().Err(err).Str("payment_hash", paymentHash).Msg("Failed to look up rebalance")
+		return false
+	}
+	return count > 0
+}
+
 func (svc *transactionsService) markHoldInvoiceAccepted(paymentHash string, settleDeadline uint32, selfPayment bool) {
 	svc.logger.Info().
 		Str("payment_hash", paymentHash).
@@ -1317,6 +1337,10 @@ func (svc *transactionsService) interceptSelfHoldPayment(paymentHash string, lnC
 // validateCanPay checks whether the given app is permitted to pay the given amount.
 // Returns the isolated balance (0 if not isolated) and the app's parent_kind so
 // the JIT full-drain check can be applied by the caller without an extra DB query.
+// Also returns the fee reserve to hold for the payment, which the caller passes
+// to the LNClient as the routing fee limit: the default reserve, lowered by the
+// app's spending policy max fee rate if it has one. destination is the pubkey
+// of the node being paid ("" if unknown), checked against the spending policy.
 //
 // skipFeeReserve additionally exempts claim_funds' per-slice payout from the
 // fee-reserve headroom below, for the same reason it's exempt from
@@ -1354,11 +1378,12 @@ type validateCanPayExemptions struct {
 	SkipBudgetCap  bool
 }
 
-func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount uint64, description string, selfPayment bool, exemptions validateCanPayExemptions) (isolatedBalance int64, parentKind string, feeSkimMloki uint64, err error) {
+func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount uint64, description string, destination string, selfPayment bool, exemptions validateCanPayExemptions) (isolatedBalance int64, parentKind string, feeSkimMloki uint64, feeReserveMloki uint64, err error) {
 	skipFeeReserve := exemptions.SkipFeeReserve
 	skipBudgetCap := exemptions.SkipBudgetCap
+	feeReserveMloki = CalculateFeeReserveMloki(amount)
 	if appId == nil {
-		return 0, "", 0, nil
+		return 0, "", 0, feeReserveMloki, nil
 	}
 
 	// Fetch app and its pay-capable permission in a single JOIN so we only hit
@@ -1374,6 +1399,9 @@ func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount
 	// ever matches a circle_hub_configs.app_id row when this app is a
 	// circle_wallet child of a circle_hub, so chc.fees_ppm is harmlessly NULL
 	// (coalesced to 0) for every other app kind/lineage.
+	//
+	// The LEFT JOIN to app_spending_policies loads the app's optional
+	// spending policy the same way; every rule is zero/empty without one.
 	var row struct {
 		AppName       string
 		AppKind       string
@@ -1382,6 +1410,12 @@ func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount
 		BudgetRenewal string
 		PermAppId     uint
 		FeesPpm       int
+
+		MaxAmountPerPaymentLoki int
+		MaxPaymentsPerHour      int
+		MaxFeePpm               int
+		AllowedPubkeys          datatypes.JSONSlice[string]
+		DeniedPubkeys           datatypes.JSONSlice[string]
 	}
 	// LEFT JOIN (not INNER) so apps.kind/parent_kind still resolve even when
 	// no PayCapableScopes permission row exists — needed for skipBudgetCap's
@@ -1389,16 +1423,19 @@ func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount
 	// deliberately never NWC-granted pay_invoice — see
 	// create_circle_wallet_controller.go) has no such row by design.
 	result := tx.Table("apps").
-		Select("apps.name AS app_name, apps.kind AS app_kind, apps.parent_kind AS parent_kind, ap.max_amount_loki, ap.budget_renewal, ap.app_id AS perm_app_id, COALESCE(chc.fees_ppm, 0) AS fees_ppm").
+		Select("apps.name AS app_name, apps.kind AS app_kind, apps.parent_kind AS parent_kind, ap.max_amount_loki, ap.budget_renewal, ap.app_id AS perm_app_id, COALESCE(chc.fees_ppm, 0) AS fees_ppm, "+
+			"COALESCE(asp.max_amount_per_payment_loki, 0) AS max_amount_per_payment_loki, COALESCE(asp.max_payments_per_hour, 0) AS max_payments_per_hour, COALESCE(asp.max_fee_ppm, 0) AS max_fee_ppm, "+
+			"COALESCE(asp.allowed_pubkeys, 'null') AS allowed_pubkeys, COALESCE(asp.denied_pubkeys, 'null') AS denied_pubkeys").
 		Joins("LEFT JOIN app_permissions ap ON ap.app_id = apps.id AND ap.scope IN ?", constants.PayCapableScopes).
 		Joins("LEFT JOIN circle_hub_configs chc ON chc.app_id = apps.parent_app_id").
+		Joins("LEFT JOIN app_spending_policies asp ON asp.app_id = apps.id").
 		Where("apps.id = ?", *appId).
 		Scan(&row)
 	if result.Error != nil {
-		return 0, "", 0, result.Error
+		return 0, "", 0, 0, result.Error
 	}
 	if row.AppKind == "" {
-		return 0, "", 0, NewNotFoundError()
+		return 0, "", 0, 0, NewNotFoundError()
 	}
 	if row.PermAppId == 0 {
 		// skipBudgetCap (internal_transfer) is only ever set by trusted
@@ -1413,8 +1450,34 @@ func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount
 		// without opening any real payment capability to that hub's own,
 		// often-shared/public NWC connection.
 		if !skipBudgetCap {
-			return 0, "", 0, errors.New("app does not have pay_invoice scope")
+			return 0, "", 0, 0, errors.New("app does not have pay_invoice scope")
+		}
+	}
+
+	// Hub-internal transfers (skipBudgetCap, see above) are not payments the
+	// app chose to make, so its spending policy does not apply to them.
+	if !skipBudgetCap {
+		rules := spendingPolicyRules{
+			MaxAmountPerPaymentLoki: row.MaxAmountPerPaymentLoki,
+			MaxPaymentsPerHour:      row.MaxPaymentsPerHour,
+			MaxFeePpm:               row.MaxFeePpm,
+			AllowedPubkeys:          row.AllowedPubkeys,
+			DeniedPubkeys:           row.DeniedPubkeys,
+		}
+		if err := checkSpendingPolicy(tx, *appId, rules, amount, destination); err != nil {
+			if errors.Is(err, NewSpendingPolicyError("")) {
+				svc.eventPublisher.Publish(&events.Event{
+					Event: "nwc_permission_denied",
+					Properties: map[string]interface{}{
+						"app_name": row.AppName,
+						"code":     constants.ERROR_POLICY_VIOLATION,
+						"message":  err.Error(),
+					},
+				})
+			}
+			return 0, "", 0, 0, err
 		}
+		feeReserveMloki = capFeeReserveMloki(feeReserveMloki, amount, row.MaxFeePpm)
 	}
 
 	// A selfPayment (lnClient's own self-payment-interception shortcut, hit
@@ -1432,7 +1495,7 @@ func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount
 
 	amountWithFeeReserve := amount + feeSkimMloki
 	if !selfPayment && !skipFeeReserve {
-		amountWithFeeReserve += CalculateFeeReserveMloki(amount)
+		amountWithFeeReserve += feeReserveMloki
 	}
 
 	if db.IsIsolatedKind(row.AppKind) {
@@ -1461,7 +1524,7 @@ func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount
 					"message":  message,
 				},
 			})
-			return 0, "", 0, NewInsufficientBalanceError()
+			return 0, "", 0, 0, NewInsufficientBalanceError()
 		}
 	}
 
@@ -1490,11 +1553,37 @@ func (svc *transactionsService) validateCanPay(tx *gorm.DB, appId *uint, amount
 					"message":  message,
 				},
 			})
-			return 0, "", 0, NewQuotaExceededError()
+			return 0, "", 0, 0, NewQuotaExceededError()
 		}
 	}
 
-	return isolatedBalance, row.ParentKind, feeSkimMloki, nil
+	return isolatedBalance, row.ParentKind, feeSkimMloki, feeReserveMloki, nil
+}
+
+func (svc *transactionsService) CheckCanPay(amountMloki uint64, destination string, paymentHash string, lnClient lnclient.LNClient, appId *uint) error {
+	selfPayment := destination != "" && destination == lnClient.GetPubkey()
+	if paymentHash != "" {
+		selfPayment = svc.isSelfPayment(destination, paymentHash, lnClient)
+	}
+	balance, parentKind, _, _, err := svc.validateCanPay(svc.db, appId, amountMloki, "", destination, selfPayment, validateCanPayExemptions{})
+	if err != nil {
+		return err
+	}
+	return enforceJITFullDrain(parentKind, balance, amountMloki, false, false)
+}
+
+// isSelfPayment returns true if the invoice with paymentHash was made by
+// this node, so paying it never leaves the hub
+func (svc *transactionsService) isSelfPayment(payee string, paymentHash string, lnClient lnclient.LNClient) bool {
+	if payee == "" || payee != lnClient.GetPubkey() {
+		return false
+	}
+	var incomingTransaction db.Transaction
+	result := svc.db.Limit(1).Find(&incomingTransaction, &db.Transaction{
+		Type:        constants.TRANSACTION_TYPE_INCOMING,
+		PaymentHash: paymentHash,
+	})
+	return result.Error == nil && result.RowsAffected > 0
 }
 
 // max of 1% or 10000 milliloki (10 loki)
@@ -14,6 +14,7 @@ import "C"
 import "unsafe"
 
 var showCb func()
+var freezeCb func()
 var quitCb func()
 
 //export goShowCallback
@@ -23,6 +24,13 @@ func goShowCallback() {
 	}
 }
 
+//export goFreezeCallback
+func goFreezeCallback() {
+	if freezeCb != nil {
+		freezeCb()
+	}
+}
+
 //export goQuitCallback
 func goQuitCallback() {
 	if quitCb != nil {
@@ -33,8 +41,9 @@ func goQuitCallback() {
 func HideFromDock() { C.hideFromDock() }
 func ShowInDock()   { C.showInDock() }
 
-func Setup(title string, icon []byte, onShow func(), onQuit func()) {
+func Setup(title string, icon []byte, onShow func(), onFreeze func(), onQuit func()) {
 	showCb = onShow
+	freezeCb = onFreeze
 	quitCb = onQuit
 	cTitle := C.CString(title)
 	defer C.free(unsafe.Pointer(cTitle))
@@ -3,10 +3,12 @@
 
 @interface TrayHandler : NSObject
 - (void)onShow:(id)sender;
+- (void)onFreeze:(id)sender;
 - (void)onQuit:(id)sender;
 @end
 
 extern void goShowCallback();
+extern void goFreezeCallback();
 extern void goQuitCallback();
 
 // Called by macOS when the dock icon is clicked and no windows are visible.
@@ -40,6 +42,7 @@ static TrayHandler *handler;
 
 @implementation TrayHandler
 - (void)onShow:(id)sender { goShowCallback(); }
+- (void)onFreeze:(id)sender { goFreezeCallback(); }
 - (void)onQuit:(id)sender { goQuitCallback(); }
 @end
 
@@ -82,6 +85,13 @@ void createTray(const char* title, const void* iconData, int iconLen) {
         [menu addItem:showItem];
         [menu addItem:[NSMenuItem separatorItem]];
 
+        NSMenuItem *freezeItem = [[NSMenuItem alloc] initWithTitle:@"Freeze all connections"
+                                                            action:@selector(onFreeze:)
+                                                     keyEquivalent:@""];
+        [freezeItem setTarget:handler];
+        [menu addItem:freezeItem];
+        [menu addItem:[NSMenuItem separatorItem]];
+
         NSMenuItem *quitItem = [[NSMenuItem alloc] initWithTitle:@"Quit"
                                                           action:@selector(onQuit:)
                                                    keyEquivalent:@""];
@@ -78,10 +78,11 @@ type groupPropEntry struct {
 	Props map[string]dbus.Variant
 }
 
-// dbusMenu implements com.canonical.dbusmenu with a static 3-item menu.
+// dbusMenu implements com.canonical.dbusmenu with a static 5-item menu.
 type dbusMenu struct {
 	revision uint32
 	onShow   func()
+	onFreeze func()
 	onQuit   func()
 }
 
@@ -115,6 +116,8 @@ func (m *dbusMenu) GetLayout(parentID, recursionDepth int32, propertyNames []str
 		Children: []dbus.Variant{
 			itemNode(1, "Show Lokihub"),
 			sepNode(2),
+			itemNode(4, "Freeze all connections"),
+			sepNode(5),
 			itemNode(3, "Quit"),
 		},
 	}
@@ -136,6 +139,8 @@ func (m *dbusMenu) Event(id int32, eventID string, data dbus.Variant, timestamp
 			go m.onShow()
 		case 3:
 			go m.onQuit()
+		case 4:
+			go m.onFreeze()
 		}
 	}
 	return nil
@@ -183,7 +188,7 @@ func pngToPixmaps(data []byte) []pixmap {
 func HideFromDock() {}
 func ShowInDock()   {}
 
-func Setup(title string, icon []byte, onShow func(), onQuit func()) {
+func Setup(title string, icon []byte, onShow func(), onFreeze func(), onQuit func()) {
 	go func() {
 		conn, err := dbus.SessionBus()
 		if err != nil {
@@ -221,7 +226,7 @@ func Setup(title string, icon []byte, onShow func(), onQuit func()) {
 			return
 		}
 
-		menu := &dbusMenu{revision: 1, onShow: onShow, onQuit: onQuit}
+		menu := &dbusMenu{revision: 1, onShow: onShow, onFreeze: onFreeze, onQuit: onQuit}
 		if err := conn.Export(menu, menuPath, menuIface); err != nil {
 			return
 		}
@@ -2,6 +2,6 @@
 
 package tray
 
-func HideFromDock()                                {}
-func ShowInDock()                                  {}
-func Setup(_ string, _ []byte, _ func(), _ func()) {}
+func HideFromDock()                                          {}
+func ShowInDock()                                            {}
+func Setup(_ string, _ []byte, _ func(), _ func(), _ func()) {}
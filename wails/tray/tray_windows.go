@@ -11,7 +11,7 @@ import (
 func HideFromDock() {}
 func ShowInDock()   {}
 
-func Setup(title string, icon []byte, onShow func(), onQuit func()) {
+func Setup(title string, icon []byte, onShow func(), onFreeze func(), onQuit func()) {
 	go func() {
 		// Pin this goroutine to one OS thread for the lifetime of the message
 		// pump. Windows requires that CreateWindow, GetMessage, DispatchMessage,
@@ -29,6 +29,8 @@ func Setup(title string, icon []byte, onShow func(), onQuit func()) {
 
 			mShow := systray.AddMenuItem("Show Lokihub", "Show the Lokihub window")
 			systray.AddSeparator()
+			mFreeze := systray.AddMenuItem("Freeze all connections", "Stop every app connection until the hub is unfrozen")
+			systray.AddSeparator()
 			mQuit := systray.AddMenuItem("Quit", "Quit Lokihub")
 
 			go func() {
@@ -36,6 +38,8 @@ func Setup(title string, icon []byte, onShow func(), onQuit func()) {
 					select {
 					case <-mShow.ClickedCh:
 						onShow()
+					case <-mFreeze.ClickedCh:
+						onFreeze()
 					case <-mQuit.ClickedCh:
 						systray.Quit()
 						onQuit()
@@ -8,6 +8,7 @@ import (
 
 	"github.com/flokiorg/lokihub/api"
 	"github.com/flokiorg/lokihub/apps"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/logger"
 	"github.com/flokiorg/lokihub/service"
 	lokitray "github.com/flokiorg/lokihub/wails/tray"
@@ -49,6 +50,25 @@ func (app *WailsApp) startup(ctx context.Context, trayIcon []byte) {
 	lokitray.Setup("Lokihub", trayIcon, func() {
 		lokitray.ShowInDock()
 		runtime.WindowShow(ctx)
+	}, func() {
+		// Same main thread constraint as the quit callback below.
+		go func() {
+			response, err := runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+				Type:  runtime.QuestionDialog,
+				Title: "Freeze All Connections",
+				Message: "Freeze all app connections? Apps will be refused everything " +
+					"but get_info until the hub is unfrozen from the settings. " +
+					"Receiving payments keeps working.",
+				Buttons:       []string{"Freeze", "Cancel"},
+				DefaultButton: "Cancel",
+			})
+			if err != nil || response != "Freeze" {
+				return
+			}
+			if _, err := app.api.Freeze(&api.FreezeRequest{}, freeze.SourceTray); err != nil {
+				logger.Logger.Error().Err(err).Msg("Failed to freeze from the tray")
+			}
+		}()
 	}, func() {
 		// Run in a goroutine: the quit callback fires on the Cocoa main thread,
 		// and MessageDialog also needs the main thread — calling it directly deadlocks.
@@ -20,7 +20,11 @@ import (
 
 	"github.com/flokiorg/lokihub/api"
 	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/export"
+	"github.com/flokiorg/lokihub/feemanager"
+	"github.com/flokiorg/lokihub/freeze"
 	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/lsps/manager"
 )
 
 type authTokenResponse struct {
@@ -54,7 +58,10 @@ func (app *WailsApp) getAppOrErrorResponse(idStr string) (*db.App, *WailsRequest
 
 // TODO: make this match echo
 func (app *WailsApp) WailsRequestRouter(route string, method string, body string) WailsRequestRouterResponse {
-	ctx := app.ctx
+	// routes that move funds check the TOTP code sent in the request body
+	totpRequest := &api.TotpCodeRequest{}
+	_ = json.Unmarshal([]byte(body), totpRequest)
+	ctx := api.WithTotpCode(app.ctx, totpRequest.TotpCode)
 
 	// Anchored so it only matches the bare "/api/apps/:id" resource and not
 	// nested child routes like "/api/apps/:id/circle/allowlist" or
@@ -445,6 +452,159 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 		}
 	}
 
+	approvalRegex := regexp.MustCompile(
+		`^/api/approvals/([0-9]+)$`,
+	)
+	if m := approvalRegex.FindStringSubmatch(route); len(m) == 2 && method == "POST" {
+		id, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		req := &api.DecidePaymentApprovalRequest{}
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		paymentApproval, err := app.api.DecidePaymentApproval(ctx, uint(id), req.Approve)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: paymentApproval, Error: ""}
+	}
+
+	lspServiceRefundRegex := regexp.MustCompile(
+		`^/api/lsp-service/refunds/([^/]+)$`,
+	)
+	if m := lspServiceRefundRegex.FindStringSubmatch(route); len(m) == 2 && method == "POST" {
+		order, err := app.api.MarkLSPServiceOrderRefunded(m[1])
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: order, Error: ""}
+	}
+
+	webhookEndpointRegex := regexp.MustCompile(
+		`^/api/webhooks/([0-9]+)$`,
+	)
+	if m := webhookEndpointRegex.FindStringSubmatch(route); len(m) == 2 {
+		id, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		switch method {
+		case "PATCH":
+			req := &api.UpdateWebhookEndpointRequest{}
+			if err := json.Unmarshal([]byte(body), req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			endpoint, err := app.api.UpdateWebhookEndpoint(uint(id), req)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: endpoint, Error: ""}
+		case "DELETE":
+			if err := app.api.DeleteWebhookEndpoint(uint(id)); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	}
+
+	apiTokenRegex := regexp.MustCompile(
+		`^/api/api-tokens/([0-9]+)$`,
+	)
+	if m := apiTokenRegex.FindStringSubmatch(route); len(m) == 2 && method == "DELETE" {
+		id, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		if err := app.api.RevokeApiToken(uint(id)); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: nil, Error: ""}
+	}
+
+	adminPubkeyRegex := regexp.MustCompile(
+		`^/api/admin-pubkeys/([0-9]+)$`,
+	)
+	if m := adminPubkeyRegex.FindStringSubmatch(route); len(m) == 2 && method == "DELETE" {
+		id, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		if err := app.api.RemoveAdminPubkey(uint(id)); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: nil, Error: ""}
+	}
+
+	appFreezeRegex := regexp.MustCompile(
+		`^/api/apps/([0-9]+)/freeze$`,
+	)
+	if m := appFreezeRegex.FindStringSubmatch(route); len(m) == 2 && method == "PUT" {
+		id, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		req := &api.FreezeAppRequest{}
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		if err := app.api.SetAppFrozen(uint(id), req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: nil, Error: ""}
+	}
+
+	webhookRedeliverRegex := regexp.MustCompile(
+		`^/api/webhooks/deliveries/([0-9]+)/redeliver$`,
+	)
+	if m := webhookRedeliverRegex.FindStringSubmatch(route); len(m) == 2 && method == "POST" {
+		id, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		delivery, err := app.api.RedeliverWebhookDelivery(uint(id))
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: delivery, Error: ""}
+	}
+
+	lightningAddressRegex := regexp.MustCompile(
+		`^/api/lightning-addresses/([0-9]+)$`,
+	)
+	if m := lightningAddressRegex.FindStringSubmatch(route); len(m) == 2 && method == "DELETE" {
+		appId, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		if err := app.api.DeleteLightningAddress(uint(appId)); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: nil, Error: ""}
+	}
+
+	if route == "/api/lightning-addresses" {
+		switch method {
+		case "GET":
+			lightningAddresses, err := app.api.ListLightningAddresses()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: lightningAddresses, Error: ""}
+		case "POST":
+			req := &api.CreateLightningAddressRequest{}
+			if err := json.Unmarshal([]byte(body), req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			lightningAddress, err := app.api.CreateLightningAddress(req)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: lightningAddress, Error: ""}
+		}
+	}
+
 	appLogoRegex := regexp.MustCompile(
 		`/api/appstore/logos/([^/]+)`,
 	)
@@ -647,6 +807,38 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 		return WailsRequestRouterResponse{Body: node, Error: ""}
 	}
 
+	if strings.HasPrefix(route, "/api/transactions/export") {
+		exportRequest := &api.ExportTransactionsRequest{Format: export.FormatCSV}
+		paramRegex := regexp.MustCompile(`[?&](format|from|until|appId)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			switch match[1] {
+			case "format":
+				exportRequest.Format = match[2]
+			case "from":
+				if parsedFrom, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					exportRequest.From = parsedFrom
+				}
+			case "until":
+				if parsedUntil, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					exportRequest.Until = parsedUntil
+				}
+			case "appId":
+				if parsedAppId, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					var unsignedAppId = uint(parsedAppId)
+					exportRequest.AppId = &unsignedAppId
+				}
+			}
+		}
+
+		// the desktop app has no streaming responses, so the export is
+		// returned in one piece
+		var exported strings.Builder
+		if err := app.api.ExportTransactions(ctx, exportRequest, &exported); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: exported.String(), Error: ""}
+	}
+
 	transactionRegex := regexp.MustCompile(
 		`/api/transactions/([0-9a-fA-F]+)`,
 	)
@@ -663,6 +855,21 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 		return WailsRequestRouterResponse{Body: paymentInfo, Error: ""}
 	}
 
+	offerRegex := regexp.MustCompile(
+		`/api/offers/(lno1[0-9a-zA-Z]+)`,
+	)
+	offerMatch := offerRegex.FindStringSubmatch(route)
+
+	switch {
+	case len(offerMatch) > 1:
+		offer, err := app.api.LookupOffer(ctx, offerMatch[1])
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+
+		return WailsRequestRouterResponse{Body: offer, Error: ""}
+	}
+
 	listTransactionsRegex := regexp.MustCompile(
 		`/api/transactions`,
 	)
@@ -867,6 +1074,23 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 			return WailsRequestRouterResponse{Body: openChannelResponse, Error: ""}
 		}
 
+	case "/api/channels/batch":
+		batchOpenChannelRequest := &api.BatchOpenChannelRequest{}
+		err := json.Unmarshal([]byte(body), batchOpenChannelRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		batchOpenChannelResponse, err := app.api.BatchOpenChannel(ctx, batchOpenChannelRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: batchOpenChannelResponse, Error: ""}
+
 	case "/api/balances":
 		balancesResponse, err := app.api.GetBalances(ctx)
 		if err != nil {
@@ -891,6 +1115,38 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 		}
 		res := WailsRequestRouterResponse{Body: invoice, Error: ""}
 		return res
+	case "/api/offers":
+		makeOfferRequest := &api.MakeOfferRequest{}
+		err := json.Unmarshal([]byte(body), makeOfferRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		offer, err := app.api.CreateOffer(ctx, makeOfferRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: offer, Error: ""}
+	case "/api/offers/pay":
+		payOfferRequest := &api.PayOfferRequest{}
+		err := json.Unmarshal([]byte(body), payOfferRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		paymentResponse, err := app.api.PayOffer(ctx, payOfferRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: paymentResponse, Error: ""}
 	case "/api/invoices/estimate-fee":
 		type EstimateFeeRequest struct {
 			Invoice string `json:"invoice"`
@@ -934,11 +1190,100 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
 		}
 
-		redeemOnchainFundsResponse, err := app.api.RedeemOnchainFunds(ctx, redeemOnchainFundsRequest.ToAddress, redeemOnchainFundsRequest.Amount, redeemOnchainFundsRequest.FeeRate, redeemOnchainFundsRequest.SendAll)
+		redeemOnchainFundsResponse, err := app.api.RedeemOnchainFunds(ctx, redeemOnchainFundsRequest.ToAddress, redeemOnchainFundsRequest.Amount, redeemOnchainFundsRequest.FeeRate, redeemOnchainFundsRequest.SendAll, redeemOnchainFundsRequest.Outpoints)
 		if err != nil {
 			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
 		}
 		return WailsRequestRouterResponse{Body: *redeemOnchainFundsResponse, Error: ""}
+	case "/api/wallet/utxos":
+		switch method {
+		case "GET":
+			utxos, err := app.api.ListUtxos(ctx)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: utxos, Error: ""}
+		case "PUT":
+			updateUtxoRequest := &api.UpdateUtxoRequest{}
+			err := json.Unmarshal([]byte(body), updateUtxoRequest)
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to decode request to wails router")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			err = app.api.UpdateUtxo(ctx, updateUtxoRequest)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/wallet/psbt":
+		createPsbtRequest := &api.CreatePsbtRequest{}
+		err := json.Unmarshal([]byte(body), createPsbtRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		createPsbtResponse, err := app.api.CreatePsbt(ctx, createPsbtRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: createPsbtResponse, Error: ""}
+	case "/api/wallet/psbt/finalize":
+		finalizePsbtRequest := &api.FinalizePsbtRequest{}
+		err := json.Unmarshal([]byte(body), finalizePsbtRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		finalizePsbtResponse, err := app.api.FinalizePsbt(ctx, finalizePsbtRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: finalizePsbtResponse, Error: ""}
+	case "/api/wallet/publish":
+		publishTransactionRequest := &api.PublishTransactionRequest{}
+		err := json.Unmarshal([]byte(body), publishTransactionRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		publishTransactionResponse, err := app.api.PublishTransaction(ctx, publishTransactionRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: publishTransactionResponse, Error: ""}
+	case "/api/wallet/bump-fee":
+		bumpFeeRequest := &api.BumpFeeRequest{}
+		err := json.Unmarshal([]byte(body), bumpFeeRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		bumpFeeResponse, err := app.api.BumpFee(ctx, bumpFeeRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: bumpFeeResponse, Error: ""}
 	case "/api/wallet/sign-message":
 		signMessageRequest := &api.SignMessageRequest{}
 		err := json.Unmarshal([]byte(body), signMessageRequest)
@@ -994,6 +1339,30 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 		}
 
 	// LSPS Settings Routes (RESTful)
+	case "/api/lsp-service":
+		switch method {
+		case "GET":
+			serviceConfig, err := app.api.GetLSPServiceConfig()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: serviceConfig, Error: ""}
+		case "PUT":
+			serviceConfig := &manager.LSPServiceConfig{}
+			if err := json.Unmarshal([]byte(body), serviceConfig); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			if err := app.api.UpdateLSPServiceConfig(serviceConfig); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/lsp-service/refunds":
+		orders, err := app.api.ListLSPServiceRefunds()
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: orders, Error: ""}
 	case "/api/lsps":
 		switch method {
 		case "GET":
@@ -1391,7 +1760,7 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 			}).Err(err).Msg("Failed to parse mnemonic request")
 			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
 		}
-		mnemonicResponse, err := app.api.GetMnemonic(mnemonicRequest.UnlockPassword)
+		mnemonicResponse, err := app.api.GetMnemonic(mnemonicRequest.UnlockPassword, mnemonicRequest.TotpCode)
 		if err != nil {
 			logger.Logger.Error().Fields(map[string]interface{}{
 				"route":  route,
@@ -1615,7 +1984,7 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 			}
 		}()
 
-		err = app.api.CreateBackup(backupRequest.UnlockPassword, backupFile)
+		err = app.api.CreateBackup(backupRequest.UnlockPassword, backupRequest.TotpCode, backupFile)
 
 		if err != nil {
 			logger.Logger.Error().Fields(map[string]interface{}{
@@ -1765,20 +2134,188 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 			}
 			return WailsRequestRouterResponse{Body: nil, Error: ""}
 		}
-	case "/api/swaps/out/info":
-		swapOutInfo, err := app.api.GetSwapOutInfo()
-		if err != nil {
-			logger.Logger.Error().Fields(map[string]interface{}{
-				"route":  route,
-				"method": method,
-				"body":   body,
-			}).Err(err).Msg("Failed to get swap out info")
-			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
-		}
-		return WailsRequestRouterResponse{Body: swapOutInfo, Error: ""}
-	case "/api/swaps/in/info":
-		swapInInfo, err := app.api.GetSwapInInfo()
-		if err != nil {
+	case "/api/autoswap/in":
+		switch method {
+		case "POST":
+			enableAutoSwapInRequest := &api.EnableAutoSwapInRequest{}
+			err := json.Unmarshal([]byte(body), enableAutoSwapInRequest)
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to decode request to wails router")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			err = app.api.EnableAutoSwapIn(enableAutoSwapInRequest)
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to enable auto swap in")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		case "DELETE":
+			err := app.api.DisableAutoSwapIn()
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to disable auto swap in")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/autoliquidity":
+		switch method {
+		case "GET":
+			autoLiquidityConfig, err := app.api.GetAutoLiquidityConfig()
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to get auto liquidity configuration")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: autoLiquidityConfig, Error: ""}
+		case "POST":
+			enableAutoLiquidityRequest := &api.EnableAutoLiquidityRequest{}
+			err := json.Unmarshal([]byte(body), enableAutoLiquidityRequest)
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to decode request to wails router")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			err = app.api.EnableAutoLiquidity(enableAutoLiquidityRequest)
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to enable auto liquidity")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		case "DELETE":
+			err := app.api.DisableAutoLiquidity()
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to disable auto liquidity")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/autorebalance":
+		switch method {
+		case "GET":
+			autoRebalanceConfig, err := app.api.GetAutoRebalanceConfig()
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to get auto rebalance configuration")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: autoRebalanceConfig, Error: ""}
+		case "POST":
+			enableAutoRebalanceRequest := &api.EnableAutoRebalanceRequest{}
+			err := json.Unmarshal([]byte(body), enableAutoRebalanceRequest)
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to decode request to wails router")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			err = app.api.EnableAutoRebalance(enableAutoRebalanceRequest)
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to enable auto rebalance")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		case "DELETE":
+			err := app.api.DisableAutoRebalance()
+			if err != nil {
+				logger.Logger.Error().Fields(map[string]interface{}{
+					"route":  route,
+					"method": method,
+					"body":   body,
+				}).Err(err).Msg("Failed to disable auto rebalance")
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/rebalance":
+		rebalanceRequest := &api.RebalanceRequest{}
+		err := json.Unmarshal([]byte(body), rebalanceRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to decode request to wails router")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		rebalance, err := app.api.Rebalance(ctx, rebalanceRequest)
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to rebalance channels")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: rebalance, Error: ""}
+	case "/api/rebalances":
+		listRequest := &api.ListRebalancesRequest{Limit: 20}
+		paramRegex := regexp.MustCompile(`[?&](limit|offset)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			switch match[1] {
+			case "limit":
+				if parsedLimit, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Limit = parsedLimit
+				}
+			case "offset":
+				if parsedOffset, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Offset = parsedOffset
+				}
+			}
+		}
+		rebalances, err := app.api.ListRebalances(listRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: rebalances, Error: ""}
+	case "/api/swaps/out/info":
+		swapOutInfo, err := app.api.GetSwapOutInfo()
+		if err != nil {
+			logger.Logger.Error().Fields(map[string]interface{}{
+				"route":  route,
+				"method": method,
+				"body":   body,
+			}).Err(err).Msg("Failed to get swap out info")
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: swapOutInfo, Error: ""}
+	case "/api/swaps/in/info":
+		swapInInfo, err := app.api.GetSwapInInfo()
+		if err != nil {
 			logger.Logger.Error().Fields(map[string]interface{}{
 				"route":  route,
 				"method": method,
@@ -1895,11 +2432,325 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 		}
 
 	case "/api/forwards":
-		forwards, err := app.api.GetForwards()
+		forwardsRequest := &api.GetForwardsRequest{Limit: 20}
+		paramRegex := regexp.MustCompile(`[?&](limit|offset|from|until|groupBy)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			switch match[1] {
+			case "limit":
+				if parsedLimit, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					forwardsRequest.Limit = parsedLimit
+				}
+			case "offset":
+				if parsedOffset, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					forwardsRequest.Offset = parsedOffset
+				}
+			case "from":
+				if parsedFrom, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					forwardsRequest.From = parsedFrom
+				}
+			case "until":
+				if parsedUntil, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					forwardsRequest.Until = parsedUntil
+				}
+			case "groupBy":
+				forwardsRequest.GroupBy = match[2]
+			}
+		}
+		forwards, err := app.api.GetForwards(forwardsRequest)
 		if err != nil {
 			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
 		}
 		return WailsRequestRouterResponse{Body: forwards, Error: ""}
+	case "/api/approvals":
+		state := ""
+		paramRegex := regexp.MustCompile(`[?&](state)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			state = match[2]
+		}
+		paymentApprovals, err := app.api.ListPaymentApprovals(state)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: paymentApprovals, Error: ""}
+	case "/api/webhooks":
+		switch method {
+		case "GET":
+			endpoints, err := app.api.ListWebhookEndpoints()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: endpoints, Error: ""}
+		case "POST":
+			req := &api.CreateWebhookEndpointRequest{}
+			if err := json.Unmarshal([]byte(body), req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			endpoint, err := app.api.CreateWebhookEndpoint(req)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: endpoint, Error: ""}
+		}
+	case "/api/api-tokens":
+		switch method {
+		case "GET":
+			apiTokens, err := app.api.ListApiTokens()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: apiTokens, Error: ""}
+		case "POST":
+			req := &api.CreateApiTokenRequest{}
+			if err := json.Unmarshal([]byte(body), req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			apiToken, err := app.api.CreateApiToken(req)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: apiToken, Error: ""}
+		}
+	case "/api/admin-pubkeys":
+		switch method {
+		case "GET":
+			adminPubkeys, err := app.api.ListAdminPubkeys()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: adminPubkeys, Error: ""}
+		case "POST":
+			req := &api.AddAdminPubkeyRequest{}
+			if err := json.Unmarshal([]byte(body), req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			adminPubkey, err := app.api.AddAdminPubkey(req)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: adminPubkey, Error: ""}
+		}
+	case "/api/freeze":
+		switch method {
+		case "GET":
+			state, err := app.api.GetFreezeState()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: state, Error: ""}
+		case "POST":
+			req := &api.FreezeRequest{}
+			if err := json.Unmarshal([]byte(body), req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			state, err := app.api.Freeze(req, freeze.SourceApi)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: state, Error: ""}
+		case "DELETE":
+			state, err := app.api.Unfreeze(freeze.SourceApi)
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: state, Error: ""}
+		}
+	case "/api/totp":
+		switch method {
+		case "GET":
+			status, err := app.api.GetTotpStatus()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: status, Error: ""}
+		case "PATCH":
+			req := &api.UpdateTotpRequest{}
+			if err := json.Unmarshal([]byte(body), req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			if err := app.api.UpdateTotp(req); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/totp/enroll":
+		req := &api.BeginTotpEnrollmentRequest{}
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		enrollResponse, err := app.api.BeginTotpEnrollment(req)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: enrollResponse, Error: ""}
+	case "/api/totp/confirm":
+		req := &api.TotpCodeRequest{}
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		recoveryCodes, err := app.api.ConfirmTotpEnrollment(req)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: recoveryCodes, Error: ""}
+	case "/api/totp/disable":
+		req := &api.DisableTotpRequest{}
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		if err := app.api.DisableTotp(req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: nil, Error: ""}
+	case "/api/totp/recovery-codes":
+		req := &api.TotpCodeRequest{}
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		recoveryCodes, err := app.api.RegenerateTotpRecoveryCodes(req)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: recoveryCodes, Error: ""}
+	case "/api/audit":
+		listRequest := &api.ListAuditLogRequest{
+			AuditLogFilter: parseAuditLogFilter(route),
+			Limit:          20,
+		}
+		paramRegex := regexp.MustCompile(`[?&](limit|offset)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			switch match[1] {
+			case "limit":
+				if parsedLimit, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Limit = parsedLimit
+				}
+			case "offset":
+				if parsedOffset, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Offset = parsedOffset
+				}
+			}
+		}
+		entries, err := app.api.ListAuditLog(listRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: entries, Error: ""}
+	case "/api/audit/export":
+		filter := parseAuditLogFilter(route)
+		// the desktop app has no streaming responses, so the export is
+		// returned in one piece
+		var exported strings.Builder
+		if err := app.api.ExportAuditLog(&filter, &exported); err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: exported.String(), Error: ""}
+	case "/api/webhooks/deliveries":
+		listRequest := &api.ListWebhookDeliveriesRequest{Limit: 20}
+		paramRegex := regexp.MustCompile(`[?&](endpointId|state|limit|offset)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			switch match[1] {
+			case "endpointId":
+				if parsedEndpointId, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.EndpointId = uint(parsedEndpointId)
+				}
+			case "state":
+				listRequest.State = match[2]
+			case "limit":
+				if parsedLimit, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Limit = parsedLimit
+				}
+			case "offset":
+				if parsedOffset, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Offset = parsedOffset
+				}
+			}
+		}
+		deliveries, err := app.api.ListWebhookDeliveries(listRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: deliveries, Error: ""}
+	case "/api/channels/acceptor":
+		switch method {
+		case "GET":
+			policy, err := app.api.GetChannelAcceptorPolicy()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: policy, Error: ""}
+		case "PUT":
+			policy := &manager.ChannelAcceptorPolicy{}
+			if err := json.Unmarshal([]byte(body), policy); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			if err := app.api.UpdateChannelAcceptorPolicy(policy); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/channels/acceptor/decisions":
+		listRequest := &api.ListChannelAcceptDecisionsRequest{Limit: 20}
+		paramRegex := regexp.MustCompile(`[?&](accepted|limit|offset)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			switch match[1] {
+			case "accepted":
+				if parsedAccepted, err := strconv.ParseBool(match[2]); err == nil {
+					listRequest.Accepted = &parsedAccepted
+				}
+			case "limit":
+				if parsedLimit, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Limit = parsedLimit
+				}
+			case "offset":
+				if parsedOffset, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Offset = parsedOffset
+				}
+			}
+		}
+		decisions, err := app.api.ListChannelAcceptDecisions(listRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: decisions, Error: ""}
+	case "/api/channels/fees/manager":
+		switch method {
+		case "GET":
+			feeManagerConfig, err := app.api.GetChannelFeeManagerConfig()
+			if err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: feeManagerConfig, Error: ""}
+		case "PUT":
+			feeManagerConfig := &feemanager.Config{}
+			if err := json.Unmarshal([]byte(body), feeManagerConfig); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			if err := app.api.UpdateChannelFeeManagerConfig(feeManagerConfig); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+			return WailsRequestRouterResponse{Body: nil, Error: ""}
+		}
+	case "/api/channels/fees/history":
+		listRequest := &api.ListChannelFeeUpdatesRequest{Limit: 20}
+		paramRegex := regexp.MustCompile(`[?&](channelId|limit|offset)=([^&]+)`)
+		for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+			switch match[1] {
+			case "channelId":
+				listRequest.ChannelId = match[2]
+			case "limit":
+				if parsedLimit, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Limit = parsedLimit
+				}
+			case "offset":
+				if parsedOffset, err := strconv.ParseUint(match[2], 10, 64); err == nil {
+					listRequest.Offset = parsedOffset
+				}
+			}
+		}
+		feeUpdates, err := app.api.ListChannelFeeUpdates(listRequest)
+		if err != nil {
+			return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+		}
+		return WailsRequestRouterResponse{Body: feeUpdates, Error: ""}
 	case "/api/setup/status":
 		status, err := app.api.GetSetupStatus(ctx)
 		if err != nil {
@@ -1956,6 +2807,12 @@ func (app *WailsApp) WailsRequestRouter(route string, method string, body string
 		if !slices.Contains([]string{"full", "readonly"}, unlockRequest.Permission) {
 			return WailsRequestRouterResponse{Body: nil, Error: "Permission field is unknown"}
 		}
+		// read-only sessions cannot reach anything the second factor protects
+		if unlockRequest.Permission == "full" {
+			if err := app.api.VerifyTotp(unlockRequest.TotpCode, unlockRequest.UnlockPassword); err != nil {
+				return WailsRequestRouterResponse{Body: nil, Error: err.Error()}
+			}
+		}
 
 		token, err := app.createJWT(unlockRequest.TokenExpiryDays, unlockRequest.Permission)
 		if err != nil {
@@ -2081,3 +2938,36 @@ func (app *WailsApp) createJWT(tokenExpiryDays *uint64, permission string) (stri
 	}
 	return signed, nil
 }
+
+func parseAuditLogFilter(route string) api.AuditLogFilter {
+	filter := api.AuditLogFilter{}
+	paramRegex := regexp.MustCompile(`[?&](action|actor|outcome|apiTokenId|from|until)=([^&]+)`)
+	for _, match := range paramRegex.FindAllStringSubmatch(route, -1) {
+		value, err := url.QueryUnescape(match[2])
+		if err != nil {
+			continue
+		}
+		switch match[1] {
+		case "action":
+			filter.Action = value
+		case "actor":
+			filter.Actor = value
+		case "outcome":
+			filter.Outcome = value
+		case "apiTokenId":
+			if parsedApiTokenId, err := strconv.ParseUint(value, 10, 64); err == nil {
+				var unsignedApiTokenId = uint(parsedApiTokenId)
+				filter.ApiTokenId = &unsignedApiTokenId
+			}
+		case "from":
+			if parsedFrom, err := strconv.ParseUint(value, 10, 64); err == nil {
+				filter.From = parsedFrom
+			}
+		case "until":
+			if parsedUntil, err := strconv.ParseUint(value, 10, 64); err == nil {
+				filter.Until = parsedUntil
+			}
+		}
+	}
+	return filter
+}
@@ -0,0 +1,430 @@
+// Package webhooks forwards hub events to HTTP endpoints configured by the
+// wallet owner. Every matching event is queued as a db.WebhookDelivery so
+// deliveries survive restarts, and failed attempts are retried with
+// exponential backoff.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/flokiorg/lokihub/config"
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/logger"
+	"github.com/flokiorg/lokihub/utils"
+)
+
+const (
+	// SignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256>" where the
+	// HMAC is keyed with the endpoint secret over "<unix time>.<body>".
+	SignatureHeader = "X-Lokihub-Signature"
+	// EventHeader carries the name of the delivered event.
+	EventHeader = "X-Lokihub-Event"
+	// DeliveryHeader carries the delivery ID, which stays the same across
+	// retries so receivers can drop duplicates.
+	DeliveryHeader = "X-Lokihub-Delivery"
+)
+
+const (
+	// MaxAttempts is how often a delivery is tried before it fails for good.
+	MaxAttempts = 8
+	// firstRetryDelay doubles after every failed attempt, so the last retry
+	// happens about an hour after the first attempt.
+	firstRetryDelay = 30 * time.Second
+	deliveryTimeout = 10 * time.Second
+	pollInterval    = 5 * time.Second
+	// deliveryBatchSize bounds how many due deliveries are sent per poll.
+	deliveryBatchSize = 50
+	// deliveryRetention is how long delivered and failed deliveries are kept.
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+var (
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// Payload is the JSON body POSTed to webhook endpoints.
+type Payload struct {
+	Event      string      `json:"event"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Properties interface{} `json:"properties,omitempty"`
+}
+
+type WebhooksService interface {
+	events.EventSubscriber
+	// Start sends due deliveries until ctx is cancelled.
+	Start(ctx context.Context)
+	// CreateEndpoint adds an endpoint with a newly generated signing secret.
+	CreateEndpoint(url string, eventNames []string, enabled bool) (*db.WebhookEndpoint, error)
+	// UpdateEndpoint changes the given fields of an endpoint, leaving nil
+	// ones untouched.
+	UpdateEndpoint(id uint, url *string, eventNames *[]string, enabled *bool) (*db.WebhookEndpoint, error)
+	DeleteEndpoint(id uint) error
+	ListEndpoints() ([]db.WebhookEndpoint, error)
+	// ListDeliveries returns deliveries newest first, optionally restricted
+	// to one endpoint and state, together with the total number of matches.
+	ListDeliveries(endpointId uint, state string, limit uint64, offset uint64) ([]db.WebhookDelivery, int64, error)
+	// RedeliverDelivery queues a delivered or failed delivery to be sent
+	// again straight away.
+	RedeliverDelivery(id uint) (*db.WebhookDelivery, error)
+}
+
+type webhooksService struct {
+	db         *gorm.DB
+	cfg        config.Config
+	httpClient *http.Client
+}
+
+func NewWebhooksService(db *gorm.DB, cfg config.Config) *webhooksService {
+	return &webhooksService{
+		db:         db,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// ConsumeEvent queues the event for every enabled endpoint subscribed to it.
+func (svc *webhooksService) ConsumeEvent(ctx context.Context, event *events.Event, globalProperties map[string]interface{}) {
+	if !svc.cfg.EnableHttpWebhooks() {
+		return
+	}
+
+	endpoints := []db.WebhookEndpoint{}
+	if err := svc.db.Where("enabled = ?", true).Order("id ASC").Find(&endpoints).Error; err != nil {
+		logger.Logger.Error().Err(err).Str("event", event.Event).Msg("Failed to load webhook endpoints")
+		return
+	}
+
+	var payload []byte
+	now := time.Now()
+	for _, endpoint := range endpoints {
+		if len(endpoint.Events) > 0 && !slices.Contains(endpoint.Events, event.Event) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = json.Marshal(Payload{
+				Event:      event.Event,
+				CreatedAt:  now,
+				Properties: event.Properties,
+			})
+			if err != nil {
+				logger.Logger.Error().Err(err).Str("event", event.Event).Msg("Failed to serialize webhook payload")
+				return
+			}
+		}
+		err := svc.db.Create(&db.WebhookDelivery{
+			EndpointId:    endpoint.ID,
+			Event:         event.Event,
+			Payload:       string(payload),
+			State:         db.WebhookDeliveryStatePending,
+			NextAttemptAt: now,
+		}).Error
+		if err != nil {
+			logger.Logger.Error().Err(err).Uint("endpoint_id", endpoint.ID).Str("event", event.Event).Msg("Failed to queue webhook delivery")
+		}
+	}
+}
+
+func (svc *webhooksService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		lastPrune := time.Time{}
+		for {
+			if svc.cfg.EnableHttpWebhooks() {
+				if err := svc.deliverDue(ctx); err != nil {
+					logger.Logger.Error().Err(err).Msg("Failed to send webhook deliveries")
+				}
+			}
+			if time.Since(lastPrune) > time.Hour {
+				if err := svc.pruneDeliveries(); err != nil {
+					logger.Logger.Error().Err(err).Msg("Failed to prune webhook deliveries")
+				}
+				lastPrune = time.Now()
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// deliverDue sends every pending delivery whose next attempt is due, oldest
+// first so each endpoint receives its events in order.
+func (svc *webhooksService) deliverDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		deliveries := []db.WebhookDelivery{}
+		err := svc.db.Preload("Endpoint").
+			Where("state = ? AND next_attempt_at <= ?", db.WebhookDeliveryStatePending, time.Now()).
+			Order("id ASC").
+			Limit(deliveryBatchSize).
+			Find(&deliveries).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range deliveries {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := svc.attempt(ctx, &deliveries[i]); err != nil {
+				return err
+			}
+		}
+		if len(deliveries) < deliveryBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// attempt sends a delivery once and records the outcome. Only database
+// errors are returned; a failed request just schedules the next attempt.
+func (svc *webhooksService) attempt(ctx context.Context, delivery *db.WebhookDelivery) error {
+	if !delivery.Endpoint.Enabled {
+		return svc.db.Model(delivery).Updates(map[string]interface{}{
+			"state":      db.WebhookDeliveryStateFailed,
+			"last_error": "the endpoint was disabled",
+		}).Error
+	}
+
+	now := time.Now()
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts": attempts,
+	}
+
+	statusCode, err := svc.send(ctx, delivery)
+	updates["last_status_code"] = statusCode
+	if err == nil {
+		updates["state"] = db.WebhookDeliveryStateDelivered
+		updates["last_error"] = ""
+		updates["delivered_at"] = now
+		return svc.db.Model(delivery).Updates(updates).Error
+	}
+	if ctx.Err() != nil {
+		// shutting down: try again after the restart without counting this attempt
+		return nil
+	}
+
+	updates["last_error"] = err.Error()
+	if attempts >= MaxAttempts {
+		updates["state"] = db.WebhookDeliveryStateFailed
+	} else {
+		updates["next_attempt_at"] = now.Add(retryDelay(attempts))
+	}
+	logger.Logger.Warn().Err(err).
+		Uint("delivery_id", delivery.ID).
+		Uint("endpoint_id", delivery.EndpointId).
+		Int("attempts", attempts).
+		Msg("Webhook delivery failed")
+	return svc.db.Model(delivery).Updates(updates).Error
+}
+
+func (svc *webhooksService) send(ctx context.Context, delivery *db.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Endpoint.Secret, time.Now(), body))
+
+	resp, err := svc.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (svc *webhooksService) pruneDeliveries() error {
+	return svc.db.
+		Where("state <> ? AND updated_at < ?", db.WebhookDeliveryStatePending, time.Now().Add(-deliveryRetention)).
+		Delete(&db.WebhookDelivery{}).Error
+}
+
+func (svc *webhooksService) CreateEndpoint(url string, eventNames []string, enabled bool) (*db.WebhookEndpoint, error) {
+	if err := utils.ValidateHTTPURL(url); err != nil {
+		return nil, fmt.Errorf("%w: %s", constants.ErrInvalidParams, err.Error())
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := db.WebhookEndpoint{
+		Url:     url,
+		Secret:  secret,
+		Events:  normalizeEventNames(eventNames),
+		Enabled: enabled,
+	}
+	if err := svc.db.Create(&endpoint).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (svc *webhooksService) UpdateEndpoint(id uint, url *string, eventNames *[]string, enabled *bool) (*db.WebhookEndpoint, error) {
+	endpoint, err := svc.getEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if url != nil {
+		if err := utils.ValidateHTTPURL(*url); err != nil {
+			return nil, fmt.Errorf("%w: %s", constants.ErrInvalidParams, err.Error())
+		}
+		updates["url"] = *url
+	}
+	if eventNames != nil {
+		updates["events"] = normalizeEventNames(*eventNames)
+	}
+	if enabled != nil {
+		updates["enabled"] = *enabled
+	}
+	if len(updates) > 0 {
+		if err := svc.db.Model(endpoint).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return svc.getEndpoint(id)
+}
+
+func (svc *webhooksService) DeleteEndpoint(id uint) error {
+	result := svc.db.Delete(&db.WebhookEndpoint{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEndpointNotFound
+	}
+	return nil
+}
+
+func (svc *webhooksService) ListEndpoints() ([]db.WebhookEndpoint, error) {
+	endpoints := []db.WebhookEndpoint{}
+	if err := svc.db.Order("id ASC").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (svc *webhooksService) ListDeliveries(endpointId uint, state string, limit uint64, offset uint64) ([]db.WebhookDelivery, int64, error) {
+	query := svc.db.Model(&db.WebhookDelivery{})
+	if endpointId != 0 {
+		query = query.Where("endpoint_id = ?", endpointId)
+	}
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := []db.WebhookDelivery{}
+	query = query.Order("id DESC").Offset(int(offset)) //nolint:gosec // offset is bounded by the number of rows
+	if limit > 0 {
+		query = query.Limit(int(limit)) //nolint:gosec // limit is bounded by the caller
+	}
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (svc *webhooksService) RedeliverDelivery(id uint) (*db.WebhookDelivery, error) {
+	result := svc.db.Model(&db.WebhookDelivery{}).
+		Where("id = ? AND state <> ?", id, db.WebhookDeliveryStatePending).
+		Updates(map[string]interface{}{
+			"state":           db.WebhookDeliveryStatePending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	delivery := db.WebhookDelivery{}
+	if err := svc.db.First(&delivery, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (svc *webhooksService) getEndpoint(id uint) (*db.WebhookEndpoint, error) {
+	endpoint := db.WebhookEndpoint{}
+	if err := svc.db.First(&endpoint, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEndpointNotFound
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// Sign returns the SignatureHeader value for body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay is how long to wait after the given number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	return firstRetryDelay << (attempts - 1)
+}
+
+func normalizeEventNames(eventNames []string) []string {
+	normalized := []string{}
+	for _, eventName := range eventNames {
+		if eventName != "" && !slices.Contains(normalized, eventName) {
+			normalized = append(normalized, eventName)
+		}
+	}
+	return normalized
+}
+
+func generateSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
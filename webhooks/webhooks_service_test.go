@@ -0,0 +1,229 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flokiorg/lokihub/constants"
+	"github.com/flokiorg/lokihub/db"
+	"github.com/flokiorg/lokihub/events"
+	"github.com/flokiorg/lokihub/tests"
+)
+
+func createWebhooksService(t *testing.T, svc *tests.TestService) *webhooksService {
+	require.NoError(t, svc.Cfg.SetEnableHttpWebhooks(true))
+	return NewWebhooksService(svc.DB, svc.Cfg)
+}
+
+func TestConsumeEvent_Disabled(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	webhooksSvc := NewWebhooksService(svc.DB, svc.Cfg)
+	_, err = webhooksSvc.CreateEndpoint("https://example.com/hook", nil, true)
+	require.NoError(t, err)
+
+	webhooksSvc.ConsumeEvent(context.Background(), &events.Event{Event: "nwc_payment_received"}, nil)
+
+	var count int64
+	svc.DB.Model(&db.WebhookDelivery{}).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestConsumeEvent_EventFilters(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	webhooksSvc := createWebhooksService(t, svc)
+	received, err := webhooksSvc.CreateEndpoint("https://example.com/received", []string{"nwc_payment_received"}, true)
+	require.NoError(t, err)
+	all, err := webhooksSvc.CreateEndpoint("https://example.com/all", nil, true)
+	require.NoError(t, err)
+	_, err = webhooksSvc.CreateEndpoint("https://example.com/disabled", nil, false)
+	require.NoError(t, err)
+
+	webhooksSvc.ConsumeEvent(context.Background(), &events.Event{
+		Event:      "nwc_payment_sent",
+		Properties: map[string]interface{}{"amount": 1000},
+	}, nil)
+	webhooksSvc.ConsumeEvent(context.Background(), &events.Event{Event: "nwc_payment_received"}, nil)
+
+	deliveries, total, err := webhooksSvc.ListDeliveries(0, "", 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+
+	assert.Equal(t, all.ID, deliveries[0].EndpointId)
+	assert.Equal(t, "nwc_payment_received", deliveries[0].Event)
+	assert.Equal(t, received.ID, deliveries[1].EndpointId)
+	assert.Equal(t, "nwc_payment_received", deliveries[1].Event)
+	assert.Equal(t, all.ID, deliveries[2].EndpointId)
+	assert.Equal(t, "nwc_payment_sent", deliveries[2].Event)
+	assert.Equal(t, db.WebhookDeliveryStatePending, deliveries[2].State)
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal([]byte(deliveries[2].Payload), &payload))
+	assert.Equal(t, "nwc_payment_sent", payload.Event)
+	assert.Equal(t, map[string]interface{}{"amount": float64(1000)}, payload.Properties)
+
+	_, total, err = webhooksSvc.ListDeliveries(received.ID, "", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestDeliverDue_SignedDelivery(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	var request *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhooksSvc := createWebhooksService(t, svc)
+	endpoint, err := webhooksSvc.CreateEndpoint(server.URL, nil, true)
+	require.NoError(t, err)
+	assert.Len(t, endpoint.Secret, 64)
+
+	webhooksSvc.ConsumeEvent(context.Background(), &events.Event{Event: "nwc_channel_ready"}, nil)
+	require.NoError(t, webhooksSvc.deliverDue(context.Background()))
+
+	require.NotNil(t, request)
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "nwc_channel_ready", request.Header.Get(EventHeader))
+
+	deliveries, _, err := webhooksSvc.ListDeliveries(0, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	delivery := deliveries[0]
+	assert.Equal(t, strconv.FormatUint(uint64(delivery.ID), 10), request.Header.Get(DeliveryHeader))
+	assert.Equal(t, delivery.Payload, string(body))
+
+	// the receiver recomputes the signature from the timestamp and the body
+	signature := request.Header.Get(SignatureHeader)
+	timestamp, _, found := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	require.True(t, found)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign(endpoint.Secret, time.Unix(unix, 0), body), signature)
+	assert.NotEqual(t, Sign("wrong secret", time.Unix(unix, 0), body), signature)
+
+	assert.Equal(t, db.WebhookDeliveryStateDelivered, delivery.State)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestDeliverDue_RetriesWithBackoff(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhooksSvc := createWebhooksService(t, svc)
+	_, err = webhooksSvc.CreateEndpoint(server.URL, nil, true)
+	require.NoError(t, err)
+
+	webhooksSvc.ConsumeEvent(context.Background(), &events.Event{Event: "nwc_payment_received"}, nil)
+	require.NoError(t, webhooksSvc.deliverDue(context.Background()))
+
+	delivery := db.WebhookDelivery{}
+	require.NoError(t, svc.DB.First(&delivery).Error)
+	assert.Equal(t, db.WebhookDeliveryStatePending, delivery.State)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+	assert.Equal(t, "endpoint responded with status 503", delivery.LastError)
+	assert.WithinDuration(t, time.Now().Add(firstRetryDelay), delivery.NextAttemptAt, 5*time.Second)
+
+	// not due yet
+	require.NoError(t, webhooksSvc.deliverDue(context.Background()))
+	assert.Equal(t, int32(1), requests.Load())
+
+	for attempt := 2; attempt <= MaxAttempts; attempt++ {
+		require.NoError(t, svc.DB.Model(&delivery).Update("next_attempt_at", time.Now().Add(-time.Second)).Error)
+		require.NoError(t, webhooksSvc.deliverDue(context.Background()))
+		require.NoError(t, svc.DB.First(&delivery, delivery.ID).Error)
+		assert.Equal(t, attempt, delivery.Attempts)
+		if attempt < MaxAttempts {
+			assert.Equal(t, db.WebhookDeliveryStatePending, delivery.State)
+			assert.WithinDuration(t, time.Now().Add(retryDelay(attempt)), delivery.NextAttemptAt, 5*time.Second)
+		}
+	}
+	assert.Equal(t, db.WebhookDeliveryStateFailed, delivery.State)
+	assert.Equal(t, int32(MaxAttempts), requests.Load())
+
+	redelivered, err := webhooksSvc.RedeliverDelivery(delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, db.WebhookDeliveryStatePending, redelivered.State)
+	assert.Zero(t, redelivered.Attempts)
+
+	_, err = webhooksSvc.RedeliverDelivery(delivery.ID + 1)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1))
+	assert.Equal(t, time.Minute, retryDelay(2))
+	assert.Equal(t, 32*time.Minute, retryDelay(MaxAttempts-1))
+}
+
+func TestEndpoints(t *testing.T) {
+	svc, err := tests.CreateTestService(t)
+	require.NoError(t, err)
+	defer svc.Remove()
+
+	webhooksSvc := createWebhooksService(t, svc)
+
+	_, err = webhooksSvc.CreateEndpoint("ftp://example.com", nil, true)
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+
+	endpoint, err := webhooksSvc.CreateEndpoint("https://example.com/hook", []string{"nwc_app_created", "nwc_app_created", ""}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nwc_app_created"}, []string(endpoint.Events))
+
+	enabled := false
+	eventNames := []string{}
+	updated, err := webhooksSvc.UpdateEndpoint(endpoint.ID, nil, &eventNames, &enabled)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", updated.Url)
+	assert.Empty(t, updated.Events)
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, endpoint.Secret, updated.Secret)
+
+	badUrl := "not a url"
+	_, err = webhooksSvc.UpdateEndpoint(endpoint.ID, &badUrl, nil, nil)
+	assert.ErrorIs(t, err, constants.ErrInvalidParams)
+
+	require.NoError(t, webhooksSvc.DeleteEndpoint(endpoint.ID))
+	assert.ErrorIs(t, webhooksSvc.DeleteEndpoint(endpoint.ID), ErrEndpointNotFound)
+	_, err = webhooksSvc.UpdateEndpoint(endpoint.ID, nil, nil, &enabled)
+	assert.ErrorIs(t, err, ErrEndpointNotFound)
+
+	endpoints, err := webhooksSvc.ListEndpoints()
+	require.NoError(t, err)
+	assert.Empty(t, endpoints)
+}